@@ -0,0 +1,5 @@
+// Fetches CSRF token before any request, so that XSRF-TOKEN cookie exists.
+// axios sends the cookie back as X-XSRF-TOKEN header by default.
+export default async function({ $axios }) {
+  await $axios.$get('/api/csrf_token')
+}
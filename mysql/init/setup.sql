@@ -2,31 +2,155 @@ USE  nuxt-go-chat-app;
 
 /*
 Create users table. It has 'id' which has a unique identity, 
-'name' with the length of 30 characters, 'session' id with the 
-length of 36 characters, 'password' with the length of 64 characters,
-created time and updated time. Primary key is 'id'.
+'name' which is unique with the length of 30 characters, 
+'password' with the length of 64 characters, 'email' which is unique if set, 
+whether the email is verified, whether the user is disabled, 
+created time and updated time. 
+Primary key is 'id'.
 */
 CREATE TABLE IF NOT EXISTS users (
     id INT UNSIGNED NOT NULL AUTO_INCREMENT,
     name VARCHAR(30) NOT NULL,
-    session_id VARCHAR(36) NOT NULL,
     password VARCHAR(64) NOT NULL,
+    email VARCHAR(254) DEFAULT NULL,
+    email_verified TINYINT(1) NOT NULL DEFAULT 0,
+    disabled TINYINT(1) NOT NULL DEFAULT 0,
     created_at DATETIME DEFAULT NULL,
     updated_at DATETIME DEFAULT NULL,
-    PRIMARY KEY (id)
+    PRIMARY KEY (id),
+    UNIQUE KEY name (name),
+    UNIQUE KEY email (email)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4; 
 
 /*
-Create sessions table. It has 'id' with the length 
-of 36 characters, 'user id', created time, and 
-updated time. Primary key is 'id'.
+Create sessions table. It has 'id' which is SHA-256 of 
+the token in cookie with the length of 64 characters, 
+'user id', 'user agent' and 'ip address' of the client, 
+created time, and last seen time. 
+A user has sessions as many as devices.
+Primary key is 'id'.
 */
 CREATE TABLE IF NOT EXISTS sessions (
-    id VARCHAR(36) NOT NULL,
+    id VARCHAR(64) NOT NULL,
     user_id INT UNSIGNED NOT NULL,
+    user_agent VARCHAR(255) NOT NULL DEFAULT '',
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
     created_at DATETIME DEFAULT NULL,
-    updated_at DATETIME DEFAULT NULL,
-    PRIMARY KEY (id)
+    last_seen_at DATETIME DEFAULT NULL,
+    PRIMARY KEY (id),
+    KEY idx_sessions_user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+/*
+Create refresh_tokens table. It has 'id' which is SHA-256 of 
+the refresh token with the length of 64 characters, 
+'user id', created time and expiry time. 
+A refresh token is replaced with a new one every time it is used.
+Primary key is 'id'.
+*/
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id VARCHAR(64) NOT NULL,
+    user_id INT UNSIGNED NOT NULL,
+    created_at DATETIME DEFAULT NULL,
+    expires_at DATETIME NOT NULL,
+    PRIMARY KEY (id),
+    KEY idx_refresh_tokens_user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+/*
+Create api_keys table. It has 'id' which is SHA-256 of 
+the key with the length of 64 characters, 'prefix' which is 
+the head of the key to identify it, 'user id', 'name', 
+'scopes' separated with comma, created time, expiry time 
+which is NULL when it never expires, and last used time. 
+Primary key is 'id'.
+*/
+CREATE TABLE IF NOT EXISTS api_keys (
+    id VARCHAR(64) NOT NULL,
+    prefix VARCHAR(16) NOT NULL,
+    user_id INT UNSIGNED NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    scopes VARCHAR(255) NOT NULL,
+    created_at DATETIME DEFAULT NULL,
+    expires_at DATETIME DEFAULT NULL,
+    last_used_at DATETIME DEFAULT NULL,
+    PRIMARY KEY (id),
+    KEY idx_api_keys_user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+/*
+Create user_identities table. It has 'id' which has 
+a unique identity, 'user id', 'issuer' and 'subject' 
+which identify the account of OpenID Connect provider, 
+and created time. An account of a provider is linked 
+to only one user. Primary key is 'id'.
+*/
+CREATE TABLE IF NOT EXISTS user_identities (
+    id INT UNSIGNED NOT NULL AUTO_INCREMENT,
+    user_id INT UNSIGNED NOT NULL,
+    issuer VARCHAR(255) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    created_at DATETIME DEFAULT NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_user_identities_issuer_subject (issuer, subject),
+    KEY idx_user_identities_user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+/*
+Create user_totp table. It has 'user id', 'secret' 
+of TOTP in base32, 'enabled' which is set after 
+the first code is confirmed, 'last used step' which 
+rejects replay of a code, and created time. 
+Primary key is 'user id'.
+*/
+CREATE TABLE IF NOT EXISTS user_totp (
+    user_id INT UNSIGNED NOT NULL,
+    secret VARCHAR(64) NOT NULL,
+    enabled TINYINT(1) NOT NULL DEFAULT 0,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT NULL,
+    PRIMARY KEY (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+/*
+Create totp_recovery_codes table. It has 'id' which 
+is SHA-256 of a recovery code and 'user id'. 
+A code is deleted when it is used. 
+Primary key is 'user id' and 'id'.
+*/
+CREATE TABLE IF NOT EXISTS totp_recovery_codes (
+    id CHAR(64) NOT NULL,
+    user_id INT UNSIGNED NOT NULL,
+    PRIMARY KEY (user_id, id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+/*
+Create user_tokens table. It has 'id' which is SHA-256 
+of the token sent by email, 'user id', 'purpose' such as 
+verify_email or reset_password, 'email' which the token 
+was sent to, created time and expiry time. 
+A token is deleted when it is used. Primary key is 'id'.
+*/
+CREATE TABLE IF NOT EXISTS user_tokens (
+    id CHAR(64) NOT NULL,
+    user_id INT UNSIGNED NOT NULL,
+    purpose VARCHAR(32) NOT NULL,
+    email VARCHAR(254) NOT NULL,
+    created_at DATETIME DEFAULT NULL,
+    expires_at DATETIME NOT NULL,
+    PRIMARY KEY (id),
+    KEY user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+/*
+Create user_roles table. It has 'user id' and 'role' 
+such as admin or moderator. A user has no role unless 
+granted. Primary key is 'user id' and 'role'.
+*/
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id INT UNSIGNED NOT NULL,
+    role VARCHAR(32) NOT NULL,
+    PRIMARY KEY (user_id, role)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
 
 /*
@@ -34,30 +158,228 @@ Create threads table. It has 'id' which has
 a unique identity, 'time' with the length of 
 20 characters, user id and created time, 
 updated time. Primary key is 'id'.
+'public title' is the title of public threads and NULL for others,
+so that titles are unique only among public threads.
 */
 CREATE TABLE IF NOT EXISTS threads (
     id INT UNSIGNED NOT NULL AUTO_INCREMENT,
-    title VARCHAR(20) NOT NULL,
+    title VARCHAR(20) DEFAULT NULL,
+    visibility VARCHAR(16) NOT NULL DEFAULT 'public',
+    public_title VARCHAR(20) AS (IF(visibility = 'public', title, NULL)) STORED,
     user_id INT UNSIGNED NOT NULL,
     created_at DATETIME DEFAULT NULL,
     updated_at DATETIME DEFAULT NULL,
     PRIMARY KEY (id),
-    UNIQUE KEY (title)
+    UNIQUE KEY public_title (public_title)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
 
 /*
 Create comments. It has 'id' which has unique 
 character, thread id, user id, content with the 
-length of 200 characters, created time, and 
-updated time. Primary key is 'id'.
+length of 200 characters, created time, 
+updated time, and deleted time and user. 
+Deleted comments are kept as tombstones. 
+Replies have 'parent comment id' and 'depth', 
+which are 0 for comments posted to the thread. 
+Content is searched by the FULLTEXT index with 
+the ngram parser, which splits Japanese into words. 
+Primary key is 'id'.
 */
 CREATE TABLE IF NOT EXISTS comments (
     id INT UNSIGNED NOT NULL AUTO_INCREMENT,
     thread_id INT UNSIGNED NOT NULL,
     user_id INT UNSIGNED NOT NULL,
+    parent_comment_id INT UNSIGNED NOT NULL DEFAULT 0,
+    depth TINYINT UNSIGNED NOT NULL DEFAULT 0,
     content VARCHAR(200) NOT NULL,
     created_at DATETIME DEFAULT NULL,
     updated_at DATETIME DEFAULT NULL,
-    PRIMARY KEY (id)
+    deleted_at DATETIME DEFAULT NULL,
+    deleted_by INT UNSIGNED NOT NULL DEFAULT 0,
+    PRIMARY KEY (id),
+    KEY thread_id_id_user_id (thread_id, id, user_id),
+    KEY parent_comment_id_id (parent_comment_id, id),
+    KEY deleted_at (deleted_at),
+    FULLTEXT KEY content_fulltext (content) WITH PARSER ngram
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+/*
+Create thread_moderators table. It has 'thread id' and 
+'user id' of the user who moderates the thread. 
+The creator of a thread is its first moderator. 
+Primary key is 'thread id' and 'user id'.
+*/
+CREATE TABLE IF NOT EXISTS thread_moderators (
+    thread_id INT UNSIGNED NOT NULL,
+    user_id INT UNSIGNED NOT NULL,
+    PRIMARY KEY (thread_id, user_id),
+    KEY user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+/*
+Create thread_members table. It has 'thread id', 
+'user id' and 'role' which is owner or member. 
+Only members can read a private thread. 
+Primary key is 'thread id' and 'user id'.
+*/
+CREATE TABLE IF NOT EXISTS thread_members (
+    thread_id INT UNSIGNED NOT NULL,
+    user_id INT UNSIGNED NOT NULL,
+    role VARCHAR(16) NOT NULL,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (thread_id, user_id),
+    KEY user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+/*
+Create thread_invites table. It has 'id' which is 
+the hash of the token, 'thread id', 'user id' of the 
+inviter, the limit and count of uses, and expired time. 
+Primary key is 'id'.
+*/
+CREATE TABLE IF NOT EXISTS thread_invites (
+    id CHAR(64) NOT NULL,
+    thread_id INT UNSIGNED NOT NULL,
+    user_id INT UNSIGNED NOT NULL,
+    max_uses INT UNSIGNED NOT NULL DEFAULT 0,
+    uses INT UNSIGNED NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL,
+    expires_at DATETIME NOT NULL,
+    PRIMARY KEY (id),
+    KEY thread_id (thread_id),
+    KEY user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+/*
+Create direct_conversations table. It has the pair of 
+'user id' where 'user id1' is less than 'user id2', 
+'thread id' which stores messages as comments, and 
+the time of the last message. 
+Primary key is 'user id1' and 'user id2'.
+*/
+CREATE TABLE IF NOT EXISTS direct_conversations (
+    user_id1 INT UNSIGNED NOT NULL,
+    user_id2 INT UNSIGNED NOT NULL,
+    thread_id INT UNSIGNED NOT NULL,
+    last_message_at DATETIME NOT NULL,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (user_id1, user_id2),
+    UNIQUE KEY thread_id (thread_id),
+    KEY user_id2 (user_id2)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+/*
+Create user_blocks table. It has 'user id' and 
+'blocked user id'. Neither of them can send direct 
+messages to the other. 
+Primary key is 'user id' and 'blocked user id'.
+*/
+CREATE TABLE IF NOT EXISTS user_blocks (
+    user_id INT UNSIGNED NOT NULL,
+    blocked_user_id INT UNSIGNED NOT NULL,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (user_id, blocked_user_id),
+    KEY blocked_user_id (blocked_user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+/*
+Create thread_reads table. It has 'thread id', 'user id' 
+and 'last read comment id'. Comments whose ids are greater 
+are unread for the user. 
+Primary key is 'user id' and 'thread id'.
+*/
+CREATE TABLE IF NOT EXISTS thread_reads (
+    thread_id INT UNSIGNED NOT NULL,
+    user_id INT UNSIGNED NOT NULL,
+    last_read_comment_id INT UNSIGNED NOT NULL,
+    updated_at DATETIME NOT NULL,
+    PRIMARY KEY (user_id, thread_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+/*
+Create comment_reactions table. It has 'comment id', 
+'user id' and 'emoji'. Each user can react to each 
+comment with each emoji once. Emojis are compared 
+as binary so that similar emojis are not mixed up. 
+Primary key is 'comment id', 'user id' and 'emoji'.
+*/
+CREATE TABLE IF NOT EXISTS comment_reactions (
+    comment_id INT UNSIGNED NOT NULL,
+    user_id INT UNSIGNED NOT NULL,
+    emoji VARCHAR(32) NOT NULL,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (comment_id, user_id, emoji),
+    KEY user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
+
+/*
+Create comment_revisions table. It has 'id', 
+'comment id', 'editor id', and content of the 
+comment before the edit. 
+Primary key is 'id'.
+*/
+CREATE TABLE IF NOT EXISTS comment_revisions (
+    id INT UNSIGNED NOT NULL AUTO_INCREMENT,
+    comment_id INT UNSIGNED NOT NULL,
+    editor_id INT UNSIGNED NOT NULL,
+    content VARCHAR(200) NOT NULL,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (id),
+    KEY comment_id (comment_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+/*
+Create comment_mentions table. It has 'comment id' 
+and 'user id' mentioned with @name in the comment. 
+Primary key is 'comment id' and 'user id'.
+*/
+CREATE TABLE IF NOT EXISTS comment_mentions (
+    comment_id INT UNSIGNED NOT NULL,
+    user_id INT UNSIGNED NOT NULL,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (comment_id, user_id),
+    KEY user_id (user_id)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
 
+/*
+Create notifications table. It has 'id', 'user id' 
+who receives it, 'kind', 'thread id', 'comment id', 
+'actor id' who caused it, and when it is read. 
+It is unread while 'read at' is NULL. 
+Primary key is 'id'.
+*/
+CREATE TABLE IF NOT EXISTS notifications (
+    id INT UNSIGNED NOT NULL AUTO_INCREMENT,
+    user_id INT UNSIGNED NOT NULL,
+    kind VARCHAR(16) NOT NULL,
+    thread_id INT UNSIGNED NOT NULL,
+    comment_id INT UNSIGNED NOT NULL,
+    actor_id INT UNSIGNED NOT NULL,
+    created_at DATETIME NOT NULL,
+    read_at DATETIME DEFAULT NULL,
+    PRIMARY KEY (id),
+    KEY user_id_id (user_id, id),
+    KEY user_id_read_at (user_id, read_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+/*
+Create attachments table. It has 'id', 'comment id', 
+'user id' who uploaded it, 'file name', 'content type' 
+sniffed from the content, 'size', and 'sha256' of the 
+content which is the key of the blob shared by files 
+with the same content. 
+Primary key is 'id'.
+*/
+CREATE TABLE IF NOT EXISTS attachments (
+    id INT UNSIGNED NOT NULL AUTO_INCREMENT,
+    comment_id INT UNSIGNED NOT NULL,
+    user_id INT UNSIGNED NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(64) NOT NULL,
+    size BIGINT UNSIGNED NOT NULL,
+    sha256 CHAR(64) NOT NULL,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (id),
+    KEY comment_id (comment_id),
+    KEY sha256 (sha256)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
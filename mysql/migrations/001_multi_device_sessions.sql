@@ -0,0 +1,17 @@
+USE  nuxt-go-chat-app;
+
+/*
+Make sessions one-to-many from users.
+Sessions have the client and the last seen time, 
+and 'session_id' of users which kept only one session is dropped.
+Fresh databases are created by init/setup.sql and do not need this.
+*/
+ALTER TABLE sessions
+    ADD COLUMN user_agent VARCHAR(255) NOT NULL DEFAULT '' AFTER user_id,
+    ADD COLUMN ip_address VARCHAR(45) NOT NULL DEFAULT '' AFTER user_agent,
+    CHANGE COLUMN updated_at last_seen_at DATETIME DEFAULT NULL,
+    ADD KEY idx_sessions_user_id (user_id);
+
+UPDATE sessions SET last_seen_at = created_at WHERE last_seen_at IS NULL;
+
+ALTER TABLE users DROP COLUMN session_id;
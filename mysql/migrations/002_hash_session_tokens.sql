@@ -0,0 +1,11 @@
+USE  nuxt-go-chat-app;
+
+/*
+Store sessions by SHA-256 of the token instead of the token itself.
+The UUID in cookies issued before is treated as the token, 
+so that existing sessions keep working after the ids are hashed.
+Fresh databases are created by init/setup.sql and do not need this.
+*/
+ALTER TABLE sessions MODIFY COLUMN id VARCHAR(64) NOT NULL;
+
+UPDATE sessions SET id = SHA2(id, 256) WHERE CHAR_LENGTH(id) = 36;
@@ -0,0 +1,16 @@
+USE  nuxt-go-chat-app;
+
+/*
+Create refresh_tokens table for token based authentication.
+It has 'id' which is SHA-256 of the refresh token,
+'user id', created time and expiry time.
+Fresh databases are created by init/setup.sql and do not need this.
+*/
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id VARCHAR(64) NOT NULL,
+    user_id INT UNSIGNED NOT NULL,
+    created_at DATETIME DEFAULT NULL,
+    expires_at DATETIME NOT NULL,
+    PRIMARY KEY (id),
+    KEY idx_refresh_tokens_user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,21 @@
+USE  nuxt-go-chat-app;
+
+/*
+Create api_keys table for automation, e.g. CI posting build notifications.
+It has 'id' which is SHA-256 of the key, 'prefix' which is the head of the key,
+'user id', 'name', 'scopes' separated with comma, created time,
+expiry time which is NULL when it never expires, and last used time.
+Fresh databases are created by init/setup.sql and do not need this.
+*/
+CREATE TABLE IF NOT EXISTS api_keys (
+    id VARCHAR(64) NOT NULL,
+    prefix VARCHAR(16) NOT NULL,
+    user_id INT UNSIGNED NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    scopes VARCHAR(255) NOT NULL,
+    created_at DATETIME DEFAULT NULL,
+    expires_at DATETIME DEFAULT NULL,
+    last_used_at DATETIME DEFAULT NULL,
+    PRIMARY KEY (id),
+    KEY idx_api_keys_user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
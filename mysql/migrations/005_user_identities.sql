@@ -0,0 +1,17 @@
+USE  nuxt-go-chat-app;
+
+/*
+Create user_identities table for login by OpenID Connect.
+It links an user to the account of a provider identified by 'issuer' and 'subject'.
+Fresh databases are created by init/setup.sql and do not need this.
+*/
+CREATE TABLE IF NOT EXISTS user_identities (
+    id INT UNSIGNED NOT NULL AUTO_INCREMENT,
+    user_id INT UNSIGNED NOT NULL,
+    issuer VARCHAR(255) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    created_at DATETIME DEFAULT NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_user_identities_issuer_subject (issuer, subject),
+    KEY idx_user_identities_user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,21 @@
+USE  nuxt-go-chat-app;
+
+/*
+Create user_totp and totp_recovery_codes tables for two-factor authentication.
+Recovery codes are stored only as SHA-256 hashes in 'id'.
+Fresh databases are created by init/setup.sql and do not need this.
+*/
+CREATE TABLE IF NOT EXISTS user_totp (
+    user_id INT UNSIGNED NOT NULL,
+    secret VARCHAR(64) NOT NULL,
+    enabled TINYINT(1) NOT NULL DEFAULT 0,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT NULL,
+    PRIMARY KEY (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS totp_recovery_codes (
+    id CHAR(64) NOT NULL,
+    user_id INT UNSIGNED NOT NULL,
+    PRIMARY KEY (user_id, id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,23 @@
+USE  nuxt-go-chat-app;
+
+/*
+Add 'email' and 'email verified' to users, and create user_tokens table
+for email verification and password reset.
+Tokens are stored only as SHA-256 hashes in 'id'.
+Fresh databases are created by init/setup.sql and do not need this.
+*/
+ALTER TABLE users
+    ADD COLUMN email VARCHAR(254) DEFAULT NULL AFTER password,
+    ADD COLUMN email_verified TINYINT(1) NOT NULL DEFAULT 0 AFTER email,
+    ADD UNIQUE KEY email (email);
+
+CREATE TABLE IF NOT EXISTS user_tokens (
+    id CHAR(64) NOT NULL,
+    user_id INT UNSIGNED NOT NULL,
+    purpose VARCHAR(32) NOT NULL,
+    email VARCHAR(254) NOT NULL,
+    created_at DATETIME DEFAULT NULL,
+    expires_at DATETIME NOT NULL,
+    PRIMARY KEY (id),
+    KEY user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,24 @@
+USE  nuxt-go-chat-app;
+
+/*
+Create user_roles and thread_moderators tables for role-based access control.
+Users without rows in user_roles have no role.
+Fresh databases are created by init/setup.sql and do not need this.
+*/
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id INT UNSIGNED NOT NULL,
+    role VARCHAR(32) NOT NULL,
+    PRIMARY KEY (user_id, role)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS thread_moderators (
+    thread_id INT UNSIGNED NOT NULL,
+    user_id INT UNSIGNED NOT NULL,
+    PRIMARY KEY (thread_id, user_id),
+    KEY user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+/*
+Creators of existing threads become their moderators, as new threads do.
+*/
+INSERT IGNORE INTO thread_moderators (thread_id, user_id) SELECT id, user_id FROM threads;
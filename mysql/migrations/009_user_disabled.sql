@@ -0,0 +1,8 @@
+USE  nuxt-go-chat-app;
+
+/*
+Add 'disabled' to users, which is set by chatadmin to stop the user from logging in.
+Fresh databases are created by init/setup.sql and do not need this.
+*/
+ALTER TABLE users
+    ADD COLUMN disabled TINYINT(1) NOT NULL DEFAULT 0 AFTER email_verified;
@@ -0,0 +1,37 @@
+USE  nuxt-go-chat-app;
+
+/*
+Add 'visibility' to threads. Existing threads stay public.
+Create thread_members and thread_invites, which decide who can read private threads.
+Fresh databases are created by init/setup.sql and do not need this.
+*/
+ALTER TABLE threads
+    ADD COLUMN visibility VARCHAR(16) NOT NULL DEFAULT 'public' AFTER title;
+
+CREATE TABLE IF NOT EXISTS thread_members (
+    thread_id INT UNSIGNED NOT NULL,
+    user_id INT UNSIGNED NOT NULL,
+    role VARCHAR(16) NOT NULL,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (thread_id, user_id),
+    KEY user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS thread_invites (
+    id CHAR(64) NOT NULL,
+    thread_id INT UNSIGNED NOT NULL,
+    user_id INT UNSIGNED NOT NULL,
+    max_uses INT UNSIGNED NOT NULL DEFAULT 0,
+    uses INT UNSIGNED NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL,
+    expires_at DATETIME NOT NULL,
+    PRIMARY KEY (id),
+    KEY thread_id (thread_id),
+    KEY user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+/*
+The creator of each existing thread becomes its owner.
+*/
+INSERT IGNORE INTO thread_members (thread_id, user_id, role, created_at)
+    SELECT id, user_id, 'owner', created_at FROM threads;
@@ -0,0 +1,29 @@
+USE  nuxt-go-chat-app;
+
+/*
+Make 'title' of threads nullable, because threads of direct conversations have no title
+and NULL does not conflict on the unique key.
+Create direct_conversations and user_blocks.
+Fresh databases are created by init/setup.sql and do not need this.
+*/
+ALTER TABLE threads
+    MODIFY COLUMN title VARCHAR(20) DEFAULT NULL;
+
+CREATE TABLE IF NOT EXISTS direct_conversations (
+    user_id1 INT UNSIGNED NOT NULL,
+    user_id2 INT UNSIGNED NOT NULL,
+    thread_id INT UNSIGNED NOT NULL,
+    last_message_at DATETIME NOT NULL,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (user_id1, user_id2),
+    UNIQUE KEY thread_id (thread_id),
+    KEY user_id2 (user_id2)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS user_blocks (
+    user_id INT UNSIGNED NOT NULL,
+    blocked_user_id INT UNSIGNED NOT NULL,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (user_id, blocked_user_id),
+    KEY blocked_user_id (blocked_user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
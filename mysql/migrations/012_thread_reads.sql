@@ -0,0 +1,18 @@
+USE  nuxt-go-chat-app;
+
+/*
+Create thread_reads, which has the last read comment of each user in each thread.
+Add the index (thread_id, id, user_id) to comments, so that unread comments are counted
+by scanning only the unread range of the index, and comments of a thread are listed without full scan.
+Fresh databases are created by init/setup.sql and do not need this.
+*/
+CREATE TABLE IF NOT EXISTS thread_reads (
+    thread_id INT UNSIGNED NOT NULL,
+    user_id INT UNSIGNED NOT NULL,
+    last_read_comment_id INT UNSIGNED NOT NULL,
+    updated_at DATETIME NOT NULL,
+    PRIMARY KEY (user_id, thread_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+ALTER TABLE comments
+    ADD KEY thread_id_id_user_id (thread_id, id, user_id);
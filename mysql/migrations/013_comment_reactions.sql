@@ -0,0 +1,16 @@
+USE  nuxt-go-chat-app;
+
+/*
+Create comment_reactions, which has reactions of users to comments with emojis.
+Reactions are aggregated by comment, so the primary key starts with comment_id,
+and user_id is indexed to delete reactions of the user with the account.
+Fresh databases are created by init/setup.sql and do not need this.
+*/
+CREATE TABLE IF NOT EXISTS comment_reactions (
+    comment_id INT UNSIGNED NOT NULL,
+    user_id INT UNSIGNED NOT NULL,
+    emoji VARCHAR(32) NOT NULL,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (comment_id, user_id, emoji),
+    KEY user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
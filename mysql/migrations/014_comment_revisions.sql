@@ -0,0 +1,23 @@
+USE  nuxt-go-chat-app;
+
+/*
+Add 'deleted_at' and 'deleted_by' to comments, so that deleted comments are kept as tombstones.
+The content of deleted comments is cleared by the retention job after COMMENT_RETENTION,
+which finds them by the index of deleted_at.
+Create comment_revisions, which has the content of comments before each edit.
+Fresh databases are created by init/setup.sql and do not need this.
+*/
+ALTER TABLE comments
+    ADD COLUMN deleted_at DATETIME DEFAULT NULL AFTER updated_at,
+    ADD COLUMN deleted_by INT UNSIGNED NOT NULL DEFAULT 0 AFTER deleted_at,
+    ADD KEY deleted_at (deleted_at);
+
+CREATE TABLE IF NOT EXISTS comment_revisions (
+    id INT UNSIGNED NOT NULL AUTO_INCREMENT,
+    comment_id INT UNSIGNED NOT NULL,
+    editor_id INT UNSIGNED NOT NULL,
+    content VARCHAR(200) NOT NULL,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (id),
+    KEY comment_id (comment_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
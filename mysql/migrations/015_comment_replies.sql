@@ -0,0 +1,12 @@
+USE  nuxt-go-chat-app;
+
+/*
+Add 'parent_comment_id' and 'depth' to comments, so that users can reply to comments.
+parent_comment_id is 0 for comments posted to the thread, and depth is the number of ancestors,
+which is limited by the server. Replies are paged and counted by the index (parent_comment_id, id).
+Fresh databases are created by init/setup.sql and do not need this.
+*/
+ALTER TABLE comments
+    ADD COLUMN parent_comment_id INT UNSIGNED NOT NULL DEFAULT 0 AFTER user_id,
+    ADD COLUMN depth TINYINT UNSIGNED NOT NULL DEFAULT 0 AFTER parent_comment_id,
+    ADD KEY parent_comment_id_id (parent_comment_id, id);
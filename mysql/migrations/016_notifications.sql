@@ -0,0 +1,30 @@
+USE  nuxt-go-chat-app;
+
+/*
+Create comment_mentions, which has users mentioned with @name by comments,
+and notifications, which is the inbox of each user.
+Notifications are listed by user in order of id, and unread ones are counted by user,
+so both are indexed with user_id first.
+Fresh databases are created by init/setup.sql and do not need this.
+*/
+CREATE TABLE IF NOT EXISTS comment_mentions (
+    comment_id INT UNSIGNED NOT NULL,
+    user_id INT UNSIGNED NOT NULL,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (comment_id, user_id),
+    KEY user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id INT UNSIGNED NOT NULL AUTO_INCREMENT,
+    user_id INT UNSIGNED NOT NULL,
+    kind VARCHAR(16) NOT NULL,
+    thread_id INT UNSIGNED NOT NULL,
+    comment_id INT UNSIGNED NOT NULL,
+    actor_id INT UNSIGNED NOT NULL,
+    created_at DATETIME NOT NULL,
+    read_at DATETIME DEFAULT NULL,
+    PRIMARY KEY (id),
+    KEY user_id_id (user_id, id),
+    KEY user_id_read_at (user_id, read_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,11 @@
+USE  nuxt-go-chat-app;
+
+/*
+Add the FULLTEXT index of content to comments with the ngram parser, so that comments can be searched.
+The parser splits text into tokens of ngram_token_size characters, which is 2 by default and is the min length of terms,
+so that Japanese without spaces between words is found. Building the index locks writes to comments for a while.
+This is not needed if SEARCH_INDEX is memory.
+Fresh databases are created by init/setup.sql and do not need this.
+*/
+ALTER TABLE comments
+    ADD FULLTEXT KEY content_fulltext (content) WITH PARSER ngram;
@@ -0,0 +1,22 @@
+USE  nuxt-go-chat-app;
+
+/*
+Create attachments, which has files attached to comments.
+Contents are stored in the blob store by sha256, so that files with the same content share a blob.
+Attachments are listed by comment, and counted by sha256 before the shared blob is deleted,
+so both are indexed.
+Fresh databases are created by init/setup.sql and do not need this.
+*/
+CREATE TABLE IF NOT EXISTS attachments (
+    id INT UNSIGNED NOT NULL AUTO_INCREMENT,
+    comment_id INT UNSIGNED NOT NULL,
+    user_id INT UNSIGNED NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(64) NOT NULL,
+    size BIGINT UNSIGNED NOT NULL,
+    sha256 CHAR(64) NOT NULL,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (id),
+    KEY comment_id (comment_id),
+    KEY sha256 (sha256)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,10 @@
+USE  nuxt-go-chat-app;
+
+/*
+Make 'name' of users unique, which was only checked before inserting and updating,
+so that concurrent requests can not take the same name.
+Users who have the same name must be renamed before this.
+Fresh databases are created by init/setup.sql and do not need this.
+*/
+ALTER TABLE users
+    ADD UNIQUE KEY name (name);
@@ -0,0 +1,12 @@
+USE  nuxt-go-chat-app;
+
+/*
+Make 'title' of threads unique only among public threads,
+so that creating a thread does not tell whether a private thread has the title.
+'public title' is the title of public threads and NULL for others, which does not conflict on the unique key.
+Fresh databases are created by init/setup.sql and do not need this.
+*/
+ALTER TABLE threads
+    DROP INDEX title,
+    ADD COLUMN public_title VARCHAR(20) AS (IF(visibility = 'public', title, NULL)) STORED AFTER visibility,
+    ADD UNIQUE KEY public_title (public_title);
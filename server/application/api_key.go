@@ -0,0 +1,135 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+	"github.com/hideUW/nuxt-go-chat-app/server/util"
+)
+
+// APIKeyService is the interface of APIKeyService.
+type APIKeyService interface {
+	CreateAPIKey(ctx context.Context, userID uint32, name string, scopes model.Scopes, expiresAt *time.Time) (*model.APIKey, error)
+	ListAPIKeys(ctx context.Context, userID uint32) ([]*model.APIKey, error)
+	RevokeAPIKey(ctx context.Context, userID uint32, id string) (*model.APIKey, error)
+	GetAPIKeyUser(ctx context.Context, key string) (*model.User, model.Scopes, error)
+}
+
+// apiKeyService is the service of API keys of user.
+type apiKeyService struct {
+	m                repository.DBManager
+	userRepository   repository.UserRepository
+	apiKeyRepository repository.APIKeyRepository
+	now              func() time.Time
+}
+
+// NewAPIKeyService generates and returns APIKeyService.
+func NewAPIKeyService(m repository.DBManager, uRepo repository.UserRepository, akRepo repository.APIKeyRepository) APIKeyService {
+	return &apiKeyService{
+		m:                m,
+		userRepository:   uRepo,
+		apiKeyRepository: akRepo,
+		now:              time.Now,
+	}
+}
+
+// CreateAPIKey generates and stores an API key of the user.
+// The returned API key has the key, which can not be got again.
+func (s *apiKeyService) CreateAPIKey(ctx context.Context, userID uint32, name string, scopes model.Scopes, expiresAt *time.Time) (*model.APIKey, error) {
+	now := s.now()
+	if err := model.ValidateAPIKey(name, scopes, expiresAt, now); err != nil {
+		return nil, err
+	}
+
+	secret, err := util.RandomToken(model.APIKeySize)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate API key")
+	}
+
+	apiKey := model.NewAPIKey(model.APIKeyPrefix+secret, userID, name, scopes, expiresAt, now)
+	if err := s.apiKeyRepository.InsertAPIKey(s.m, apiKey); err != nil {
+		return nil, errors.Wrap(err, "failed to insert API key")
+	}
+
+	return apiKey, nil
+}
+
+// ListAPIKeys returns API keys of the user including expired ones.
+func (s *apiKeyService) ListAPIKeys(ctx context.Context, userID uint32) ([]*model.APIKey, error) {
+	apiKeys, err := s.apiKeyRepository.GetAPIKeysByUserID(s.m, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get API keys by user id")
+	}
+	return apiKeys, nil
+}
+
+// RevokeAPIKey deletes the API key of the user specified by id and returns it.
+// This returns NoSuchDataError if the user does not have the API key,
+// so that the user can not tell whether API keys of other users exist.
+func (s *apiKeyService) RevokeAPIKey(ctx context.Context, userID uint32, id string) (*model.APIKey, error) {
+	apiKeys, err := s.apiKeyRepository.GetAPIKeysByUserID(s.m, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get API keys by user id")
+	}
+
+	for _, apiKey := range apiKeys {
+		if apiKey.ID != id {
+			continue
+		}
+
+		if err := s.apiKeyRepository.DeleteAPIKey(s.m, apiKey.ID); err != nil {
+			return nil, errors.Wrap(err, "failed to delete API key")
+		}
+		return apiKey, nil
+	}
+
+	return nil, errors.WithStack(&model.NoSuchDataError{
+		PropertyNameForDeveloper:    model.IDPropertyForDeveloper,
+		PropertyNameForUser:         model.IDPropertyForUser,
+		PropertyValue:               id,
+		DomainModelNameForDeveloper: model.DomainModelNameAPIKeyForDeveloper,
+		DomainModelNameForUser:      model.DomainModelNameAPIKeyForUser,
+	})
+}
+
+// GetAPIKeyUser returns the user of the API key and the scopes allowed to the key.
+// This returns AuthenticationErr if the API key does not exist or is expired.
+func (s *apiKeyService) GetAPIKeyUser(ctx context.Context, key string) (*model.User, model.Scopes, error) {
+	apiKey, err := s.apiKeyRepository.GetAPIKeyByKey(s.m, key)
+	if err != nil {
+		if _, ok := errors.Cause(err).(*model.NoSuchDataError); ok {
+			return nil, nil, errors.WithStack(&model.AuthenticationErr{BaseErr: err})
+		}
+		return nil, nil, errors.Wrap(err, "failed to get API key by key")
+	}
+
+	now := s.now()
+	if apiKey.IsExpired(now) {
+		return nil, nil, errors.WithStack(&model.AuthenticationErr{})
+	}
+
+	user, err := s.userRepository.GetUserByID(s.m, apiKey.UserID)
+	if err != nil {
+		if _, ok := errors.Cause(err).(*model.NoSuchDataError); ok {
+			return nil, nil, errors.WithStack(&model.AuthenticationErr{BaseErr: err})
+		}
+		return nil, nil, errors.Wrap(err, "failed to get user by id")
+	}
+
+	if user.Disabled {
+		return nil, nil, errors.WithStack(&model.AuthenticationErr{})
+	}
+
+	// last used is not updated on every request in the same way as last seen of session.
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= lastSeenInterval {
+		if err := s.apiKeyRepository.UpdateLastUsed(s.m, apiKey.ID, now); err != nil {
+			return nil, nil, errors.Wrap(err, "failed to update last used of API key")
+		}
+	}
+
+	return user, apiKey.Scopes, nil
+}
@@ -0,0 +1,156 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	mock_repository "github.com/hideUW/nuxt-go-chat-app/server/domain/repository/mock"
+	"github.com/hideUW/nuxt-go-chat-app/server/testutil"
+)
+
+func Test_apiKeyService_CreateAPIKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testutil.SetFakeTime(time.Now())
+	past := testutil.TimeNow()
+
+	tests := []struct {
+		name      string
+		keyName   string
+		scopes    model.Scopes
+		expiresAt *time.Time
+		wantErr   bool
+	}{
+		{
+			name:    "When given appropriate args, stores only hash of the key",
+			keyName: model.APIKeyNameForTest,
+			scopes:  model.Scopes{model.ScopePostComments},
+		},
+		{
+			name:    "When the scope is unknown, returns InvalidParamError",
+			keyName: model.APIKeyNameForTest,
+			scopes:  model.Scopes{"threads:delete"},
+			wantErr: true,
+		},
+		{
+			name:    "When no scope is given, returns RequiredError",
+			keyName: model.APIKeyNameForTest,
+			wantErr: true,
+		},
+		{
+			name:      "When the expiry is not in the future, returns InvalidParamError",
+			keyName:   model.APIKeyNameForTest,
+			scopes:    model.Scopes{model.ScopeReadThreads},
+			expiresAt: &past,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock_repository.NewMockDBManager(ctrl)
+			akr := mock_repository.NewMockAPIKeyRepository(ctrl)
+
+			if !tt.wantErr {
+				akr.EXPECT().InsertAPIKey(m, gomock.Any()).Return(nil)
+			}
+
+			s := &apiKeyService{
+				m:                m,
+				apiKeyRepository: akr,
+				now:              testutil.TimeNow,
+			}
+
+			got, err := s.CreateAPIKey(context.Background(), model.UserValidIDForTest, tt.keyName, tt.scopes, tt.expiresAt)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("apiKeyService.CreateAPIKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if !model.IsAPIKey(got.Key) || got.ID != model.APIKeyIDFromKey(got.Key) {
+				t.Errorf("apiKeyService.CreateAPIKey() = %v, want prefixed key identified by its hash", got)
+			}
+			if got.Prefix == got.Key || got.Key[:len(got.Prefix)] != got.Prefix {
+				t.Errorf("apiKeyService.CreateAPIKey() Prefix = %v, want head of the key", got.Prefix)
+			}
+		})
+	}
+}
+
+func Test_apiKeyService_GetAPIKeyUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testutil.SetFakeTime(time.Now())
+	now := testutil.TimeNow()
+	expired := now
+	recent := now.Add(-time.Second)
+
+	user := &model.User{ID: model.UserValidIDForTest}
+	scopes := model.Scopes{model.ScopeReadThreads}
+
+	tests := []struct {
+		name           string
+		apiKey         *model.APIKey
+		wantUpdateUsed bool
+		wantErr        bool
+	}{
+		{
+			name:           "When the API key has never been used, returns the user and scopes and updates last used",
+			apiKey:         &model.APIKey{ID: model.APIKeyIDFromKey(model.APIKeyForTest), UserID: user.ID, Scopes: scopes},
+			wantUpdateUsed: true,
+		},
+		{
+			name:   "When the API key was used recently, does not update last used",
+			apiKey: &model.APIKey{ID: model.APIKeyIDFromKey(model.APIKeyForTest), UserID: user.ID, Scopes: scopes, LastUsedAt: &recent},
+		},
+		{
+			name:    "When the API key is expired, returns AuthenticationErr",
+			apiKey:  &model.APIKey{ID: model.APIKeyIDFromKey(model.APIKeyForTest), UserID: user.ID, Scopes: scopes, ExpiresAt: &expired},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock_repository.NewMockDBManager(ctrl)
+			ur := mock_repository.NewMockUserRepository(ctrl)
+			akr := mock_repository.NewMockAPIKeyRepository(ctrl)
+
+			akr.EXPECT().GetAPIKeyByKey(m, model.APIKeyForTest).Return(tt.apiKey, nil)
+			if !tt.wantErr {
+				ur.EXPECT().GetUserByID(m, user.ID).Return(user, nil)
+			}
+			if tt.wantUpdateUsed {
+				akr.EXPECT().UpdateLastUsed(m, tt.apiKey.ID, now).Return(nil)
+			}
+
+			s := &apiKeyService{
+				m:                m,
+				userRepository:   ur,
+				apiKeyRepository: akr,
+				now:              testutil.TimeNow,
+			}
+
+			gotUser, gotScopes, err := s.GetAPIKeyUser(context.Background(), model.APIKeyForTest)
+			if tt.wantErr {
+				if _, ok := errors.Cause(err).(*model.AuthenticationErr); !ok {
+					t.Errorf("apiKeyService.GetAPIKeyUser() error = %v, want AuthenticationErr", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("apiKeyService.GetAPIKeyUser() error = %v", err)
+			}
+			if gotUser != user || gotScopes.String() != scopes.String() {
+				t.Errorf("apiKeyService.GetAPIKeyUser() = %v, %v, want %v, %v", gotUser, gotScopes, user, scopes)
+			}
+		})
+	}
+}
@@ -0,0 +1,381 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/service"
+	"github.com/hideUW/nuxt-go-chat-app/server/util"
+)
+
+// stagingBlobKeyPrefix is the prefix of keys which uploaded contents are put to before their hashes are known.
+const stagingBlobKeyPrefix = "staging/"
+
+// AttachmentService is the interface of AttachmentService.
+type AttachmentService interface {
+	UploadAttachment(ctx context.Context, userID, commentID uint32, fileName string, r io.Reader) (*model.Attachment, error)
+	OpenAttachment(ctx context.Context, userID, id uint32) (*model.Attachment, AttachmentContent, error)
+	PurgeDeletedAttachments(ctx context.Context, retention time.Duration) (int, error)
+}
+
+// AttachmentContent is the content of the attachment, which can be read from any offset for range requests.
+type AttachmentContent interface {
+	io.ReadSeeker
+	io.Closer
+}
+
+// AttachmentServiceDIInput is DI input of AttachmentService.
+type AttachmentServiceDIInput struct {
+	threadRepository     repository.ThreadRepository
+	commentRepository    repository.CommentRepository
+	attachmentRepository repository.AttachmentRepository
+	policyService        service.PolicyService
+	blobStore            service.BlobStore
+}
+
+// NewAttachmentServiceDIInput generates and returns AttachmentServiceDIInput.
+func NewAttachmentServiceDIInput(tRepo repository.ThreadRepository, cRepo repository.CommentRepository, aRepo repository.AttachmentRepository, pService service.PolicyService, blobStore service.BlobStore) *AttachmentServiceDIInput {
+	return &AttachmentServiceDIInput{
+		threadRepository:     tRepo,
+		commentRepository:    cRepo,
+		attachmentRepository: aRepo,
+		policyService:        pService,
+		blobStore:            blobStore,
+	}
+}
+
+// attachmentService is the service of files attached to comments.
+type attachmentService struct {
+	m                    repository.DBManager
+	threadRepository     repository.ThreadRepository
+	commentRepository    repository.CommentRepository
+	attachmentRepository repository.AttachmentRepository
+	policyService        service.PolicyService
+	blobStore            service.BlobStore
+	txCloser             CloseTransaction
+	now                  func() time.Time
+}
+
+// NewAttachmentService generates and returns AttachmentService.
+func NewAttachmentService(m repository.DBManager, diInput AttachmentServiceDIInput, txCloser CloseTransaction) AttachmentService {
+	return &attachmentService{
+		m:                    m,
+		threadRepository:     diInput.threadRepository,
+		commentRepository:    diInput.commentRepository,
+		attachmentRepository: diInput.attachmentRepository,
+		policyService:        diInput.policyService,
+		blobStore:            diInput.blobStore,
+		txCloser:             txCloser,
+		now:                  time.Now,
+	}
+}
+
+// UploadAttachment stores the content read from r as the file attached to the comment specified by commentID.
+// The content type is sniffed from the content, and InvalidParamError is returned if it is not allowed,
+// if the content is larger than MaxAttachmentSize, or if the comment has MaxAttachmentsPerComment files.
+// This returns ForbiddenError if the user is not the author, and NoSuchDataError if the comment is deleted.
+func (s *attachmentService) UploadAttachment(ctx context.Context, userID, commentID uint32, fileName string, r io.Reader) (*model.Attachment, error) {
+	comment, err := s.commentRepository.GetCommentByID(s.m, commentID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get comment by id")
+	}
+
+	if _, err := getReadableThread(s.m, s.threadRepository, s.policyService, userID, comment.ThreadID); err != nil {
+		return nil, err
+	}
+
+	if comment.UserID != userID {
+		return nil, errors.WithStack(&model.ForbiddenError{
+			InvalidReasonForDeveloper: "only the author can attach files to the comment",
+		})
+	}
+
+	if comment.IsDeleted() {
+		return nil, errors.WithStack(&model.NoSuchDataError{
+			PropertyNameForDeveloper:    model.CommentIDPropertyForDeveloper,
+			PropertyNameForUser:         model.CommentIDPropertyForUser,
+			PropertyValue:               commentID,
+			DomainModelNameForDeveloper: model.DomainModelNameCommentForDeveloper,
+			DomainModelNameForUser:      model.DomainModelNameCommentForUser,
+		})
+	}
+
+	count, err := s.attachmentRepository.CountAttachmentsByCommentID(s.m, commentID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to count attachments by comment id")
+	}
+	if count >= model.MaxAttachmentsPerComment {
+		return nil, errors.WithStack(&model.InvalidParamError{
+			PropertyNameForDeveloper:  model.FilePropertyForDeveloper,
+			PropertyNameForUser:       model.FilePropertyForUser,
+			PropertyValue:             count,
+			InvalidReasonForDeveloper: fmt.Sprintf("up to %d files can be attached to a comment", model.MaxAttachmentsPerComment),
+			InvalidReasonForUser:      fmt.Sprintf("ファイルは1つのコメントに%d個まで添付できます", model.MaxAttachmentsPerComment),
+		})
+	}
+
+	// the file name is checked before the content is stored.
+	if _, err := model.NormalizeAttachmentFileName(fileName); err != nil {
+		return nil, err
+	}
+
+	head := make([]byte, model.AttachmentSniffLength)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, errors.Wrap(err, "failed to read head of file")
+	}
+	head = head[:n]
+
+	contentType, err := model.SniffAttachmentType(head)
+	if err != nil {
+		return nil, err
+	}
+
+	staging, size, sum, err := s.stageBlob(ctx, io.MultiReader(bytes.NewReader(head), r))
+	if err != nil {
+		return nil, err
+	}
+
+	attachment, err := model.NewAttachment(comment, userID, fileName, contentType, size, sum, s.now())
+	if err != nil {
+		s.dropStagingBlob(ctx, staging)
+		return nil, errors.Wrap(err, "failed to validate attachment")
+	}
+
+	if err := s.insertAttachment(ctx, attachment, staging); err != nil {
+		s.dropStagingBlob(ctx, staging)
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+// insertAttachment inserts the attachment and renames the staging blob to the key of its hash in a tx.
+// The blob of the same content is overwritten with the same content rather than shared as it is,
+// because PurgeDeletedAttachments may delete it until the record is inserted.
+// Inserting the record waits while PurgeDeletedAttachments locks the hash, so that the blob is renamed after it is deleted,
+// and PurgeDeletedAttachments waits until the tx ends, so that it sees the record.
+func (s *attachmentService) insertAttachment(ctx context.Context, attachment *model.Attachment, staging string) (err error) {
+	tx, err := s.m.Begin()
+	if err != nil {
+		return beginTxErrorMsg(err)
+	}
+
+	defer func() {
+		if cErr := s.txCloser(tx, err); cErr != nil {
+			err = errors.Wrap(cErr, "failed to close tx")
+		}
+	}()
+
+	id, err := s.attachmentRepository.InsertAttachment(tx, attachment)
+	if err != nil {
+		return errors.Wrap(err, "failed to insert attachment")
+	}
+	attachment.ID = id
+
+	if err := s.blobStore.Rename(ctx, staging, attachment.BlobKey()); err != nil {
+		return errors.Wrap(err, "failed to rename blob")
+	}
+	return nil
+}
+
+// OpenAttachment returns the attachment specified by id and its content, which the caller must close.
+// Attachments of deleted comments are hidden as well as their content.
+// This returns NoSuchDataError if the user can not read the thread or the comment is deleted.
+func (s *attachmentService) OpenAttachment(ctx context.Context, userID, id uint32) (*model.Attachment, AttachmentContent, error) {
+	attachment, err := s.attachmentRepository.GetAttachmentByID(s.m, id)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to get attachment by id")
+	}
+
+	comment, err := s.commentRepository.GetCommentByID(s.m, attachment.CommentID)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to get comment by id")
+	}
+
+	if _, err := getReadableThread(s.m, s.threadRepository, s.policyService, userID, comment.ThreadID); err != nil {
+		return nil, nil, err
+	}
+
+	if comment.IsDeleted() {
+		return nil, nil, errors.WithStack(&model.NoSuchDataError{
+			PropertyNameForDeveloper:    model.IDPropertyForDeveloper,
+			PropertyNameForUser:         model.IDPropertyForUser,
+			PropertyValue:               id,
+			DomainModelNameForDeveloper: model.DomainModelNameAttachmentForDeveloper,
+			DomainModelNameForUser:      model.DomainModelNameAttachmentForUser,
+		})
+	}
+
+	// the blob is opened here, so that the missing blob is reported before the response starts.
+	r, err := s.blobStore.Open(ctx, attachment.BlobKey(), 0)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to open blob")
+	}
+
+	return attachment, &blobReader{
+		ctx:   ctx,
+		store: s.blobStore,
+		key:   attachment.BlobKey(),
+		size:  attachment.Size,
+		r:     r,
+	}, nil
+}
+
+// PurgeDeletedAttachments deletes files attached to comments deleted longer ago than retention,
+// and returns the number of them. Blobs are deleted when no attachment shares them.
+func (s *attachmentService) PurgeDeletedAttachments(ctx context.Context, retention time.Duration) (int, error) {
+	deletedBefore := s.now().Add(-retention)
+
+	attachments, err := s.attachmentRepository.ListAttachmentsOfDeletedComments(s.m, deletedBefore)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to list attachments of deleted comments")
+	}
+
+	for i, attachment := range attachments {
+		if err := s.purgeAttachment(ctx, attachment); err != nil {
+			return i, err
+		}
+	}
+
+	return len(attachments), nil
+}
+
+// purgeAttachment deletes the attachment, and its blob if no attachment shares it, in a tx.
+// The hash is locked while the blob is deleted, so that UploadAttachment of the same content
+// does not insert the record sharing the blob meanwhile, which is described at insertAttachment.
+// The record is restored if deleting the blob fails, so that failures do not leave records without blobs.
+func (s *attachmentService) purgeAttachment(ctx context.Context, attachment *model.Attachment) (err error) {
+	tx, err := s.m.Begin()
+	if err != nil {
+		return beginTxErrorMsg(err)
+	}
+
+	defer func() {
+		if cErr := s.txCloser(tx, err); cErr != nil {
+			err = errors.Wrap(cErr, "failed to close tx")
+		}
+	}()
+
+	if err := s.attachmentRepository.DeleteAttachment(tx, attachment.ID); err != nil {
+		return errors.Wrap(err, "failed to delete attachment")
+	}
+
+	count, err := s.attachmentRepository.CountAttachmentsBySHA256ForUpdate(tx, attachment.SHA256)
+	if err != nil {
+		return errors.Wrap(err, "failed to count attachments by sha256")
+	}
+	if count > 0 {
+		return nil
+	}
+
+	if err := s.blobStore.Delete(ctx, attachment.BlobKey()); err != nil {
+		return errors.Wrap(err, "failed to delete blob")
+	}
+	return nil
+}
+
+// stageBlob stores the content read from r as a staging blob, and returns its key, size and hex encoded SHA256.
+// The content is streamed to a staging key because the hash is known only after reading all of it,
+// and it is renamed to the key of the hash by insertAttachment.
+func (s *attachmentService) stageBlob(ctx context.Context, r io.Reader) (string, int64, string, error) {
+	token, err := util.RandomToken(16)
+	if err != nil {
+		return "", 0, "", errors.Wrap(err, "failed to generate staging key")
+	}
+	staging := stagingBlobKeyPrefix + token
+
+	// one more byte is read to know whether the content is too large.
+	h := sha256.New()
+	size, err := s.blobStore.Put(ctx, staging, io.TeeReader(io.LimitReader(r, model.MaxAttachmentSize+1), h))
+	if err != nil {
+		s.dropStagingBlob(ctx, staging)
+		return "", 0, "", errors.Wrap(err, "failed to put blob")
+	}
+	if size > model.MaxAttachmentSize {
+		s.dropStagingBlob(ctx, staging)
+		return "", 0, "", model.AttachmentTooLargeError()
+	}
+
+	return staging, size, hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// dropStagingBlob deletes the staging blob which is no longer needed.
+// The error is ignored, because the staging blob left behind is never referred to by attachments.
+func (s *attachmentService) dropStagingBlob(ctx context.Context, key string) {
+	_ = s.blobStore.Delete(ctx, key)
+}
+
+// blobReader reads the blob from any offset, which opens the blob again from the offset after seeking.
+type blobReader struct {
+	ctx    context.Context
+	store  service.BlobStore
+	key    string
+	size   int64
+	offset int64
+	// r reads the blob from offset, and is nil after seeking to another offset.
+	r io.ReadCloser
+}
+
+// Read reads the blob from the current offset.
+func (b *blobReader) Read(p []byte) (int, error) {
+	if b.offset >= b.size {
+		return 0, io.EOF
+	}
+
+	if b.r == nil {
+		r, err := b.store.Open(b.ctx, b.key, b.offset)
+		if err != nil {
+			return 0, errors.Wrap(err, "failed to open blob")
+		}
+		b.r = r
+	}
+
+	n, err := b.r.Read(p)
+	b.offset += int64(n)
+	return n, err
+}
+
+// Seek sets the offset of the next Read. The blob is not opened until it is read.
+func (b *blobReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += b.offset
+	case io.SeekEnd:
+		offset += b.size
+	default:
+		return 0, errors.Errorf("invalid whence %d", whence)
+	}
+	if offset < 0 {
+		return 0, errors.Errorf("negative offset %d", offset)
+	}
+
+	if offset != b.offset && b.r != nil {
+		if err := b.r.Close(); err != nil {
+			return 0, errors.Wrap(err, "failed to close blob")
+		}
+		b.r = nil
+	}
+	b.offset = offset
+	return offset, nil
+}
+
+// Close closes the blob if it is opened.
+func (b *blobReader) Close() error {
+	if b.r == nil {
+		return nil
+	}
+	err := b.r.Close()
+	b.r = nil
+	return err
+}
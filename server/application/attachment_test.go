@@ -0,0 +1,378 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+
+	mock_application "github.com/hideUW/nuxt-go-chat-app/server/application/mock"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+	mock_repository "github.com/hideUW/nuxt-go-chat-app/server/domain/repository/mock"
+	mock_service "github.com/hideUW/nuxt-go-chat-app/server/domain/service/mock"
+	"github.com/hideUW/nuxt-go-chat-app/server/testutil"
+)
+
+// pngForTest is the content which is sniffed as image/png.
+const pngForTest = "\x89PNG\r\n\x1a\n0123456789"
+
+func Test_attachmentService_UploadAttachment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	thread := &model.Thread{ID: model.ThreadValidIDForTest}
+
+	sum := sha256.Sum256([]byte(pngForTest))
+	hash := hex.EncodeToString(sum[:])
+
+	tests := []struct {
+		name       string
+		userID     uint32
+		content    string
+		count      int
+		insertErr  error
+		want       *model.Attachment
+		wantErr    bool
+		wantStored bool
+	}{
+		{
+			name:       "When the content is new, renames the staging blob to the key of its hash",
+			userID:     model.UserValidIDForTest,
+			content:    pngForTest,
+			wantStored: true,
+			want: &model.Attachment{
+				ID:          model.AttachmentValidIDForTest,
+				CommentID:   model.CommentValidIDForTest,
+				UserID:      model.UserValidIDForTest,
+				FileName:    model.AttachmentFileNameForTest,
+				ContentType: "image/png",
+				Size:        int64(len(pngForTest)),
+				SHA256:      hash,
+				CreatedAt:   testutil.TimeNow(),
+			},
+		},
+		{
+			name:       "When inserting the record fails, drops the staging blob without renaming it",
+			userID:     model.UserValidIDForTest,
+			content:    pngForTest,
+			insertErr:  errors.New(model.ErrorMessageForTest),
+			wantStored: true,
+			wantErr:    true,
+		},
+		{
+			name:    "When the user is not the author, returns error",
+			userID:  model.UserValidIDForTest + 1,
+			content: pngForTest,
+			wantErr: true,
+		},
+		{
+			name:    "When the comment has the max number of attachments, returns error",
+			userID:  model.UserValidIDForTest,
+			content: pngForTest,
+			count:   model.MaxAttachmentsPerComment,
+			wantErr: true,
+		},
+		{
+			name:    "When the type of the content is not allowed, returns error",
+			userID:  model.UserValidIDForTest,
+			content: "<html><script>alert(1)</script></html>",
+			wantErr: true,
+		},
+		{
+			name:    "When the content is larger than the max, drops the staging blob and returns error",
+			userID:  model.UserValidIDForTest,
+			content: pngForTest + strings.Repeat("0", model.MaxAttachmentSize),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock_repository.NewMockDBManager(ctrl)
+			tr := mock_repository.NewMockThreadRepository(ctrl)
+			cr := mock_repository.NewMockCommentRepository(ctrl)
+			ar := mock_repository.NewMockAttachmentRepository(ctrl)
+			ps := mock_service.NewMockPolicyService(ctrl)
+			bs := mock_service.NewMockBlobStore(ctrl)
+
+			comment := &model.Comment{ID: model.CommentValidIDForTest, ThreadID: thread.ID, UserID: model.UserValidIDForTest}
+			cr.EXPECT().GetCommentByID(m, model.CommentValidIDForTest).Return(comment, nil)
+			tr.EXPECT().GetThreadByID(m, thread.ID).Return(thread, nil)
+			ps.EXPECT().CanReadThread(tt.userID, thread).Return(true, nil)
+
+			if tt.userID == comment.UserID {
+				ar.EXPECT().CountAttachmentsByCommentID(m, model.CommentValidIDForTest).Return(tt.count, nil)
+			}
+
+			var staging string
+			if tt.count < model.MaxAttachmentsPerComment && strings.HasPrefix(tt.content, pngForTest) && tt.userID == comment.UserID {
+				bs.EXPECT().Put(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, key string, r io.Reader) (int64, error) {
+						if !strings.HasPrefix(key, stagingBlobKeyPrefix) {
+							t.Errorf("Put() key = %s, want staging key", key)
+						}
+						staging = key
+						b, err := ioutil.ReadAll(r)
+						return int64(len(b)), err
+					})
+				if tt.wantStored {
+					tx := mock_repository.NewMockTxManager(ctrl)
+					m.EXPECT().Begin().Return(tx, nil)
+					ar.EXPECT().InsertAttachment(tx, gomock.Any()).Return(model.AttachmentValidIDForTest, tt.insertErr)
+				}
+				// the blob of the same content is overwritten rather than shared as it is, since it may be being purged.
+				if tt.wantStored && tt.insertErr == nil {
+					bs.EXPECT().Rename(ctx, gomock.Any(), model.AttachmentBlobKey(hash)).DoAndReturn(
+						func(_ context.Context, from, _ string) error {
+							if from != staging {
+								t.Errorf("Rename() from = %s, want %s", from, staging)
+							}
+							return nil
+						})
+				} else {
+					bs.EXPECT().Delete(ctx, gomock.Any()).DoAndReturn(
+						func(_ context.Context, key string) error {
+							if key != staging {
+								t.Errorf("Delete() key = %s, want %s", key, staging)
+							}
+							return nil
+						})
+				}
+			}
+
+			s := &attachmentService{
+				m:                    m,
+				threadRepository:     tr,
+				commentRepository:    cr,
+				attachmentRepository: ar,
+				policyService:        ps,
+				blobStore:            bs,
+				txCloser:             mock_application.MockCloseTransaction,
+				now:                  testutil.TimeNow,
+			}
+
+			got, err := s.UploadAttachment(ctx, tt.userID, model.CommentValidIDForTest, model.AttachmentFileNameForTest, strings.NewReader(tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("attachmentService.UploadAttachment() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				testutil.Errorf(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_attachmentService_OpenAttachment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	thread := &model.Thread{ID: model.ThreadValidIDForTest}
+	attachment := &model.Attachment{
+		ID:        model.AttachmentValidIDForTest,
+		CommentID: model.CommentValidIDForTest,
+		Size:      int64(len(pngForTest)),
+		SHA256:    "abcdef",
+	}
+
+	tests := []struct {
+		name     string
+		canRead  bool
+		deleted  bool
+		wantErr  error
+		wantOpen bool
+	}{
+		{
+			name:     "When the user can read the thread, returns the content",
+			canRead:  true,
+			wantOpen: true,
+		},
+		{
+			name:    "When the user can not read the thread, returns NoSuchDataError of the thread",
+			canRead: false,
+			wantErr: &model.NoSuchDataError{
+				PropertyNameForDeveloper:    model.IDPropertyForDeveloper,
+				PropertyNameForUser:         model.IDPropertyForUser,
+				PropertyValue:               thread.ID,
+				DomainModelNameForDeveloper: model.DomainModelNameThreadForDeveloper,
+				DomainModelNameForUser:      model.DomainModelNameThreadForUser,
+			},
+		},
+		{
+			name:    "When the comment is deleted, returns NoSuchDataError of the attachment",
+			canRead: true,
+			deleted: true,
+			wantErr: &model.NoSuchDataError{
+				PropertyNameForDeveloper:    model.IDPropertyForDeveloper,
+				PropertyNameForUser:         model.IDPropertyForUser,
+				PropertyValue:               model.AttachmentValidIDForTest,
+				DomainModelNameForDeveloper: model.DomainModelNameAttachmentForDeveloper,
+				DomainModelNameForUser:      model.DomainModelNameAttachmentForUser,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock_repository.NewMockDBManager(ctrl)
+			tr := mock_repository.NewMockThreadRepository(ctrl)
+			cr := mock_repository.NewMockCommentRepository(ctrl)
+			ar := mock_repository.NewMockAttachmentRepository(ctrl)
+			ps := mock_service.NewMockPolicyService(ctrl)
+			bs := mock_service.NewMockBlobStore(ctrl)
+
+			comment := &model.Comment{ID: model.CommentValidIDForTest, ThreadID: thread.ID}
+			if tt.deleted {
+				comment.DeletedAt = testutil.TimeNow()
+			}
+			ar.EXPECT().GetAttachmentByID(m, model.AttachmentValidIDForTest).Return(attachment, nil)
+			cr.EXPECT().GetCommentByID(m, model.CommentValidIDForTest).Return(comment, nil)
+			tr.EXPECT().GetThreadByID(m, thread.ID).Return(thread, nil)
+			ps.EXPECT().CanReadThread(model.UserValidIDForTest, thread).Return(tt.canRead, nil)
+			if tt.wantOpen {
+				bs.EXPECT().Open(ctx, attachment.BlobKey(), int64(0)).Return(ioutil.NopCloser(strings.NewReader(pngForTest)), nil)
+			}
+
+			s := &attachmentService{
+				m:                    m,
+				threadRepository:     tr,
+				commentRepository:    cr,
+				attachmentRepository: ar,
+				policyService:        ps,
+				blobStore:            bs,
+			}
+
+			got, content, err := s.OpenAttachment(ctx, model.UserValidIDForTest, model.AttachmentValidIDForTest)
+			if tt.wantErr != nil {
+				if err == nil {
+					t.Fatal("attachmentService.OpenAttachment() should return error")
+				}
+				if !reflect.DeepEqual(errors.Cause(err), tt.wantErr) {
+					testutil.Errorf(t, tt.wantErr, errors.Cause(err))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("attachmentService.OpenAttachment() error = %v", err)
+			}
+			defer content.Close()
+
+			if !reflect.DeepEqual(got, attachment) {
+				testutil.Errorf(t, attachment, got)
+			}
+			b, err := ioutil.ReadAll(content)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != pngForTest {
+				t.Errorf("content = %q, want %q", b, pngForTest)
+			}
+		})
+	}
+}
+
+func Test_blobReader(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	content := "Hello, world!"
+	bs := mock_service.NewMockBlobStore(ctrl)
+
+	// the blob is opened again only when it is read after seeking to another offset.
+	bs.EXPECT().Open(ctx, "key", int64(7)).Return(ioutil.NopCloser(strings.NewReader(content[7:])), nil).Times(1)
+
+	b := &blobReader{
+		ctx:   ctx,
+		store: bs,
+		key:   "key",
+		size:  int64(len(content)),
+		r:     ioutil.NopCloser(strings.NewReader(content)),
+	}
+
+	if n, err := b.Seek(0, io.SeekEnd); err != nil || n != int64(len(content)) {
+		t.Errorf("Seek() to the end = %d, %v, want %d", n, err, len(content))
+	}
+	if n, err := b.Read(make([]byte, 1)); n != 0 || err != io.EOF {
+		t.Errorf("Read() at the end = %d, %v, want EOF", n, err)
+	}
+	if n, err := b.Seek(7, io.SeekStart); err != nil || n != 7 {
+		t.Errorf("Seek() = %d, %v, want 7", n, err)
+	}
+
+	var buf bytes.Buffer
+	if _, err := io.CopyN(&buf, b, 5); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != "world" {
+		t.Errorf("Read() after Seek() = %q, want %q", got, "world")
+	}
+	if _, err := b.Seek(-20, io.SeekCurrent); err == nil {
+		t.Error("Seek() to the negative offset should return error")
+	}
+	if err := b.Close(); err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_attachmentService_PurgeDeletedAttachments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	retention := 24 * time.Hour
+
+	m := mock_repository.NewMockDBManager(ctrl)
+	ar := mock_repository.NewMockAttachmentRepository(ctrl)
+	bs := mock_service.NewMockBlobStore(ctrl)
+
+	tx1 := mock_repository.NewMockTxManager(ctrl)
+	tx2 := mock_repository.NewMockTxManager(ctrl)
+
+	shared := &model.Attachment{ID: 1, SHA256: "shared"}
+	only := &model.Attachment{ID: 2, SHA256: "only"}
+
+	var closed []error
+	ar.EXPECT().ListAttachmentsOfDeletedComments(m, testutil.TimeNow().Add(-retention)).Return([]*model.Attachment{shared, only}, nil)
+	gomock.InOrder(
+		m.EXPECT().Begin().Return(tx1, nil),
+		ar.EXPECT().DeleteAttachment(tx1, shared.ID).Return(nil),
+		ar.EXPECT().CountAttachmentsBySHA256ForUpdate(tx1, shared.SHA256).Return(1, nil),
+		m.EXPECT().Begin().Return(tx2, nil),
+		ar.EXPECT().DeleteAttachment(tx2, only.ID).Return(nil),
+		ar.EXPECT().CountAttachmentsBySHA256ForUpdate(tx2, only.SHA256).Return(0, nil),
+		// only the blob which no attachment shares is deleted, while the hash is locked by the tx.
+		bs.EXPECT().Delete(ctx, only.BlobKey()).Return(nil),
+	)
+
+	s := &attachmentService{
+		m:                    m,
+		attachmentRepository: ar,
+		blobStore:            bs,
+		txCloser: func(_ repository.TxManager, err error) error {
+			closed = append(closed, err)
+			return nil
+		},
+		now: testutil.TimeNow,
+	}
+
+	got, err := s.PurgeDeletedAttachments(ctx, retention)
+	if err != nil {
+		t.Fatalf("attachmentService.PurgeDeletedAttachments() error = %v", err)
+	}
+	if got != 2 {
+		t.Errorf("attachmentService.PurgeDeletedAttachments() = %d, want 2", got)
+	}
+	if len(closed) != 2 {
+		t.Errorf("attachmentService.PurgeDeletedAttachments() closed %d txs, want 2", len(closed))
+	}
+}
@@ -2,120 +2,344 @@ package application
 
 import (
 	"context"
+	"time"
 
 	"github.com/pkg/errors"
 
 	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
 	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
 	"github.com/hideUW/nuxt-go-chat-app/server/domain/service"
+	"github.com/hideUW/nuxt-go-chat-app/server/util"
 )
 
+// lastSeenInterval is the interval to update last seen of session.
+const lastSeenInterval = time.Minute
+
+// PendingLoginTTL is the time in which the second factor must be sent after password.
+const PendingLoginTTL = 5 * time.Minute
+
+// dummyPasswordHash is compared when the user does not exist,
+// so that response time does not tell whether the name exists.
+var dummyPasswordHash, _ = util.HashPassword("dummy password")
+
 // AuthenticationService is the interface of AuthenticationService.
 type AuthenticationService interface {
-	SignUp(ctx context.Context, param *model.User) (*model.User, error)
+	SignUp(ctx context.Context, param *model.User, client *model.Client) (*model.User, *model.Session, error)
+	Login(ctx context.Context, param *model.User, client *model.Client) (*model.User, *model.Session, *model.PendingLogin, error)
+	LoginWithSecondFactor(ctx context.Context, pendingToken, code string, client *model.Client) (*model.User, *model.Session, error)
+	Authenticate(ctx context.Context, param *model.User, client *model.Client) (*model.User, error)
+	VerifySecondFactor(ctx context.Context, user *model.User, code string) error
+	GetSessionUser(ctx context.Context, token string, client *model.Client) (*model.User, error)
+	Logout(ctx context.Context, token string) error
 }
 
 // AuthenticationServiceDIInput is DI input of AuthenticationService.
 type AuthenticationServiceDIInput struct {
-	userRepository    repository.UserRepository
-	sessionRepository repository.SessionRepository
-	userService       service.UserService
-	sessionService    service.SessionService
+	userRepository         repository.UserRepository
+	sessionRepository      repository.SessionRepository
+	totpRepository         repository.TOTPRepository
+	pendingLoginRepository repository.PendingLoginRepository
+	userService            service.UserService
+	sessionService         service.SessionService
+	throttleService        service.ThrottleService
+	totpService            service.TOTPService
 }
 
 // NewAuthenticationServiceDIInput generates and returns AuthenticationServiceDIInput.
-func NewAuthenticationServiceDIInput(uRepo repository.UserRepository, sRepo repository.SessionRepository, uService service.UserService, sService service.SessionService) *AuthenticationServiceDIInput {
+func NewAuthenticationServiceDIInput(uRepo repository.UserRepository, sRepo repository.SessionRepository, totpRepo repository.TOTPRepository, plRepo repository.PendingLoginRepository, uService service.UserService, sService service.SessionService, tService service.ThrottleService, totpService service.TOTPService) *AuthenticationServiceDIInput {
 	return &AuthenticationServiceDIInput{
-		userRepository:    uRepo,
-		sessionRepository: sRepo,
-		userService:       uService,
-		sessionService:    sService,
+		userRepository:         uRepo,
+		sessionRepository:      sRepo,
+		totpRepository:         totpRepo,
+		pendingLoginRepository: plRepo,
+		userService:            uService,
+		sessionService:         sService,
+		throttleService:        tService,
+		totpService:            totpService,
 	}
 }
 
 // authenticationService is the service of authentication.
 type authenticationService struct {
-	m                 repository.DBManager
-	userRepository    repository.UserRepository
-	sessionRepository repository.SessionRepository
-	userService       service.UserService
-	sessionService    service.SessionService
-	txCloser          CloseTransaction
+	m                      repository.DBManager
+	userRepository         repository.UserRepository
+	sessionRepository      repository.SessionRepository
+	totpRepository         repository.TOTPRepository
+	pendingLoginRepository repository.PendingLoginRepository
+	userService            service.UserService
+	sessionService         service.SessionService
+	throttleService        service.ThrottleService
+	totpService            service.TOTPService
+	txCloser               CloseTransaction
+	now                    func() time.Time
 }
 
 // NewAuthenticationService generates and returns AuthenticationService.
 func NewAuthenticationService(m repository.DBManager, diInput AuthenticationServiceDIInput, txCloser CloseTransaction) AuthenticationService {
 	return &authenticationService{
-		m:                 m,
-		userRepository:    diInput.userRepository,
-		sessionRepository: diInput.sessionRepository,
-		userService:       diInput.userService,
-		sessionService:    diInput.sessionService,
-		txCloser:          txCloser,
+		m:                      m,
+		userRepository:         diInput.userRepository,
+		sessionRepository:      diInput.sessionRepository,
+		totpRepository:         diInput.totpRepository,
+		pendingLoginRepository: diInput.pendingLoginRepository,
+		userService:            diInput.userService,
+		sessionService:         diInput.sessionService,
+		throttleService:        diInput.throttleService,
+		totpService:            diInput.totpService,
+		txCloser:               txCloser,
+		now:                    time.Now,
 	}
 }
 
-// SignUp sign up an user.
-func (s *authenticationService) SignUp(ctx context.Context, param *model.User) (user *model.User, err error) {
+// SignUp sign up an user and creates the session of the client.
+func (s *authenticationService) SignUp(ctx context.Context, param *model.User, client *model.Client) (user *model.User, session *model.Session, err error) {
+	// every sign up is counted as failure, so that an IP can not create users one after another.
+	key := model.NewThrottleKey(model.ThrottleKindSignUpIP, client.IPAddress)
+	if _, err := s.throttleService.Reserve(ctx, key); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to pass throttle")
+	}
+
 	tx, err := s.m.Begin()
 	if err != nil {
-		return nil, beginTxErrorMsg(err)
+		return nil, nil, beginTxErrorMsg(err)
 	}
 
 	defer func() {
-		if err := s.txCloser(tx, err); err != nil {
-			err = errors.Wrap(err, "failed to close tx")
+		if cErr := s.txCloser(tx, err); cErr != nil {
+			err = errors.Wrap(cErr, "failed to close tx")
 		}
 	}()
 
 	user, err = s.userService.NewUser(param.Name, param.Password)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to new user")
+		return nil, nil, errors.Wrap(err, "failed to new user")
 	}
 
-	sessionID := s.sessionService.SessionID()
-	user.SessionID = sessionID
-
 	// create User
-	user, err = s.createUser(ctx, user)
+	user, err = s.createUser(ctx, tx, user)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to create user")
+		return nil, nil, errors.Wrap(err, "failed to create user")
 	}
 
-	session := s.sessionService.NewSession(user.ID)
-	session.ID = user.SessionID
-
 	// create Session
-	if _, err := s.createSession(ctx, session); err != nil {
-		return nil, errors.Wrap(err, "failed to create session")
+	session, err = s.createSession(ctx, tx, user.ID, client)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to create session")
+	}
+
+	return user, session, nil
+}
+
+// Login checks name and password of an user and creates a new session of the client.
+// Sessions of other clients are kept, so that the user can log in from some devices at the same time.
+// If TOTP of the user is enabled, this returns a pending login instead of session,
+// and the session is created by LoginWithSecondFactor.
+func (s *authenticationService) Login(ctx context.Context, param *model.User, client *model.Client) (user *model.User, session *model.Session, pending *model.PendingLogin, err error) {
+	user, err = s.Authenticate(ctx, param, client)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	totp, err := getEnabledTOTP(s.m, s.totpRepository, user.ID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if totp != nil {
+		pending, err = s.createPendingLogin(user.ID)
+		if err != nil {
+			return nil, nil, nil, errors.Wrap(err, "failed to create pending login")
+		}
+		return user, nil, pending, nil
+	}
+
+	session, err = s.createSession(ctx, s.m, user.ID, client)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "failed to create session")
+	}
+
+	return user, session, nil, nil
+}
+
+// LoginWithSecondFactor checks the code for the pending login and creates a new session of the client.
+// This returns AuthenticationErr if the pending login is unknown or expired, or the code is wrong.
+// The pending login is kept after a wrong code until it reaches the limit of attempts.
+func (s *authenticationService) LoginWithSecondFactor(ctx context.Context, pendingToken, code string, client *model.Client) (*model.User, *model.Session, error) {
+	pending, err := s.pendingLoginRepository.TakePendingLogin(model.PendingLoginIDFromToken(pendingToken))
+	if err != nil {
+		if _, ok := errors.Cause(err).(*model.NoSuchDataError); ok {
+			return nil, nil, errors.WithStack(&model.AuthenticationErr{BaseErr: err})
+		}
+		return nil, nil, errors.Wrap(err, "failed to take pending login")
+	}
+
+	if pending.IsExpired(s.now()) {
+		return nil, nil, errors.WithStack(&model.AuthenticationErr{})
+	}
+
+	user, err := s.userRepository.GetUserByID(s.m, pending.UserID)
+	if err != nil {
+		if _, ok := errors.Cause(err).(*model.NoSuchDataError); ok {
+			return nil, nil, errors.WithStack(&model.AuthenticationErr{BaseErr: err})
+		}
+		return nil, nil, errors.Wrap(err, "failed to get user by id")
+	}
+
+	if user.Disabled {
+		return nil, nil, errors.WithStack(&model.AuthenticationErr{})
+	}
+
+	if err := s.VerifySecondFactor(ctx, user, code); err != nil {
+		if _, ok := errors.Cause(err).(*model.AuthenticationErr); ok {
+			pending.Attempts++
+			if pending.Attempts < model.MaxPendingLoginAttempts {
+				if iErr := s.pendingLoginRepository.InsertPendingLogin(pending); iErr != nil {
+					return nil, nil, errors.Wrap(iErr, "failed to insert pending login")
+				}
+			}
+		}
+		return nil, nil, err
+	}
+
+	session, err := s.createSession(ctx, s.m, user.ID, client)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to create session")
+	}
+
+	return user, session, nil
+}
+
+// Authenticate checks name and password of an user and returns the user.
+// Failures are throttled per name and per IP of the client.
+func (s *authenticationService) Authenticate(ctx context.Context, param *model.User, client *model.Client) (*model.User, error) {
+	nameKey := model.NewThrottleKey(model.ThrottleKindLoginName, param.Name)
+	ipKey := model.NewThrottleKey(model.ThrottleKindLoginIP, client.IPAddress)
+	// the attempt is reserved before the password is checked, so that parallel requests can not guess together.
+	reservation, err := s.throttleService.Reserve(ctx, nameKey, ipKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to pass throttle")
+	}
+
+	user, err := s.authenticate(s.m, param.Name, param.Password)
+	if err != nil {
+		if _, ok := errors.Cause(err).(*model.AuthenticationErr); !ok {
+			if rErr := s.throttleService.Release(ctx, reservation); rErr != nil {
+				return nil, errors.Wrap(rErr, "failed to release throttle")
+			}
+		}
+		return nil, errors.Wrap(err, "failed to authenticate")
+	}
+
+	if err := s.throttleService.Release(ctx, reservation); err != nil {
+		return nil, errors.Wrap(err, "failed to release throttle")
+	}
+	// failures from the IP are not forgotten, so that one valid account does not unlock stuffing.
+	if err := s.throttleService.Reset(ctx, nameKey); err != nil {
+		return nil, errors.Wrap(err, "failed to reset throttle")
 	}
 
 	return user, nil
 }
 
-// createUser creates the user.
-func (s *authenticationService) createUser(ctx context.Context, user *model.User) (*model.User, error) {
-	// not allow duplicated name.
-	yes, err := s.userService.IsAlreadyExistName(ctx, user.Name)
-	if yes {
-		err = &model.AlreadyExistError{
-			PropertyNameForDeveloper:    model.NamePropertyForDeveloper,
-			PropertyNameForUser:         model.NamePropertyForUser,
-			PropertyValue:               user.Name,
-			DomainModelNameForDeveloper: model.DomainModelNameUserForDeveloper,
-			DomainModelNameForUser:      model.DomainModelNameUserForUser,
+// VerifySecondFactor checks the TOTP code or a recovery code of the user.
+// This returns nil without the code if TOTP of the user is not enabled,
+// and AuthenticationErr if the code is wrong.
+func (s *authenticationService) VerifySecondFactor(ctx context.Context, user *model.User, code string) error {
+	totp, err := getEnabledTOTP(s.m, s.totpRepository, user.ID)
+	if err != nil {
+		return err
+	}
+	if totp == nil {
+		return nil
+	}
+
+	return verifySecondFactor(ctx, s.m, s.totpRepository, s.totpService, s.throttleService, totp, code)
+}
+
+// GetSessionUser returns the user of the session and records that the session is used by the client.
+// This returns AuthenticationErr if the session or the user does not exist.
+func (s *authenticationService) GetSessionUser(ctx context.Context, token string, client *model.Client) (*model.User, error) {
+	session, err := s.sessionRepository.GetSessionByToken(s.m, token)
+	if err != nil {
+		if _, ok := errors.Cause(err).(*model.NoSuchDataError); ok {
+			return nil, errors.WithStack(&model.AuthenticationErr{BaseErr: err})
+		}
+		return nil, errors.Wrap(err, "failed to get session by token")
+	}
+
+	user, err := s.userRepository.GetUserByID(s.m, session.UserID)
+	if err != nil {
+		if _, ok := errors.Cause(err).(*model.NoSuchDataError); ok {
+			return nil, errors.WithStack(&model.AuthenticationErr{BaseErr: err})
 		}
+		return nil, errors.Wrap(err, "failed to get user by id")
+	}
+
+	if user.Disabled {
+		return nil, errors.WithStack(&model.AuthenticationErr{})
+	}
+
+	// last seen is not updated on every request so that reading does not always write.
+	now := s.now()
+	if now.Sub(session.LastSeenAt) >= lastSeenInterval || session.IPAddress != client.IPAddress {
+		if err := s.sessionRepository.UpdateLastSeen(s.m, session.ID, client.IPAddress, now); err != nil {
+			return nil, errors.Wrap(err, "failed to update last seen of session")
+		}
+	}
+
+	return user, nil
+}
 
-		return nil, errors.Wrap(err, "failed to check whether already exists name or not")
+// Logout deletes the session which has the token.
+// This returns nil if the session does not exist.
+func (s *authenticationService) Logout(ctx context.Context, token string) error {
+	session, err := s.sessionRepository.GetSessionByToken(s.m, token)
+	if err != nil {
+		if _, ok := errors.Cause(err).(*model.NoSuchDataError); ok {
+			return nil
+		}
+		return errors.Wrap(err, "failed to get session by token")
 	}
 
+	if err := s.sessionRepository.DeleteSession(s.m, session.ID); err != nil {
+		return errors.Wrap(err, "failed to delete session")
+	}
+
+	return nil
+}
+
+// authenticate checks name and password and returns the user.
+// This returns AuthenticationErr whether the name is wrong or the password is wrong.
+func (s *authenticationService) authenticate(m repository.SQLManager, name, password string) (*model.User, error) {
+	user, err := s.userRepository.GetUserByName(m, name)
 	if err != nil {
-		if _, ok := errors.Cause(err).(*model.NoSuchDataError); !ok {
-			return nil, errors.Wrap(err, "failed to check whether already exists name or not")
+		if _, ok := errors.Cause(err).(*model.NoSuchDataError); ok {
+			util.CheckHashOfPassword(password, dummyPasswordHash)
+			return nil, errors.WithStack(&model.AuthenticationErr{BaseErr: err})
 		}
+		return nil, errors.Wrap(err, "failed to get user by name")
+	}
+
+	if !util.CheckHashOfPassword(password, user.Password) {
+		return nil, errors.WithStack(&model.AuthenticationErr{})
+	}
+
+	// disabled user is not told apart from wrong password.
+	if user.Disabled {
+		return nil, errors.WithStack(&model.AuthenticationErr{})
+	}
+
+	return user, nil
+}
+
+// createUser creates the user.
+func (s *authenticationService) createUser(ctx context.Context, m repository.SQLManager, user *model.User) (*model.User, error) {
+	// not allow duplicated name.
+	if err := checkNameNotExist(ctx, s.userService, user.Name); err != nil {
+		return nil, err
 	}
 
-	id, err := s.userRepository.InsertUser(s.m, user)
+	id, err := s.userRepository.InsertUser(m, user)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to insert user")
 	}
@@ -124,21 +348,55 @@ func (s *authenticationService) createUser(ctx context.Context, user *model.User
 	return user, nil
 }
 
-// createSession creates the session.
-func (s *authenticationService) createSession(ctx context.Context, session *model.Session) (*model.Session, error) {
-	// ready for collision of UUID.
-	yes := true
-	var err error
-	for yes {
-		yes, err = s.sessionService.IsAlreadyExistID(ctx, session.ID)
-		if err != nil {
-			if _, ok := errors.Cause(err).(*model.NoSuchDataError); !ok {
-				return nil, errors.Wrap(err, "failed to check whether already exists id or not")
-			}
-		}
+// createPendingLogin creates the pending login of the user.
+func (s *authenticationService) createPendingLogin(userID uint32) (*model.PendingLogin, error) {
+	return createPendingLogin(s.pendingLoginRepository, userID, s.now())
+}
+
+// createPendingLogin creates the pending login of the user.
+// The returned login has the token which is sent to the client, but only its hash is stored.
+func createPendingLogin(plRepo repository.PendingLoginRepository, userID uint32, now time.Time) (*model.PendingLogin, error) {
+	token, err := util.RandomToken(model.PendingLoginTokenSize)
+	if err != nil {
+		return nil, errors.WithStack(&model.OtherServerError{
+			BaseErr:                   err,
+			InvalidReasonForDeveloper: "failed to generate pending login token",
+		})
+	}
+
+	pending := &model.PendingLogin{
+		ID:        model.PendingLoginIDFromToken(token),
+		Token:     token,
+		UserID:    userID,
+		ExpiresAt: now.Add(PendingLoginTTL),
+	}
+	if err := plRepo.InsertPendingLogin(pending); err != nil {
+		return nil, errors.Wrap(err, "failed to insert pending login")
 	}
+	return pending, nil
+}
+
+// createSession creates the session of the client.
+func (s *authenticationService) createSession(ctx context.Context, m repository.SQLManager, userID uint32, client *model.Client) (*model.Session, error) {
+	return createSession(m, s.sessionService, s.sessionRepository, userID, client)
+}
+
+// createSession creates the session of the client.
+// The returned session has the token which is sent to the client, but only its hash is stored.
+func createSession(m repository.SQLManager, sService service.SessionService, sRepo repository.SessionRepository, userID uint32, client *model.Client) (*model.Session, error) {
+	token, err := sService.SessionToken()
+	if err != nil {
+		return nil, errors.WithStack(&model.OtherServerError{
+			BaseErr:                   err,
+			InvalidReasonForDeveloper: "failed to generate session token",
+		})
+	}
+
+	session := sService.NewSession(userID, client)
+	session.Token = token
+	session.ID = model.SessionIDFromToken(token)
 
-	if err := s.sessionRepository.InsertSession(s.m, session); err != nil {
+	if err := sRepo.InsertSession(m, session); err != nil {
 		return nil, errors.Wrap(err, "failed to insert session")
 	}
 	return session, nil
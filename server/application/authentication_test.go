@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	mock_application "github.com/hideUW/nuxt-go-chat-app/server/application/mock"
 	mock_service "github.com/hideUW/nuxt-go-chat-app/server/domain/service/mock"
 
 	"github.com/hideUW/nuxt-go-chat-app/server/domain/service"
@@ -14,7 +15,9 @@ import (
 	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
 	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
 	mock_repository "github.com/hideUW/nuxt-go-chat-app/server/domain/repository/mock"
+	"github.com/hideUW/nuxt-go-chat-app/server/infra/memory"
 	"github.com/hideUW/nuxt-go-chat-app/server/testutil"
+	"github.com/hideUW/nuxt-go-chat-app/server/util"
 	"github.com/pkg/errors"
 )
 
@@ -28,11 +31,13 @@ func Test_authenticationService_SignUp(t *testing.T) {
 		sessionRepository repository.SessionRepository
 		userService       service.UserService
 		sessionService    service.SessionService
+		throttleService   service.ThrottleService
 		txCloser          CloseTransaction
 	}
 	type args struct {
-		ctx  context.Context
-		user *model.User
+		ctx    context.Context
+		user   *model.User
+		client *model.Client
 	}
 
 	type mockUserRepoArgs struct {
@@ -100,6 +105,7 @@ func Test_authenticationService_SignUp(t *testing.T) {
 				sessionRepository: mock_repository.NewMockSessionRepository(ctrl),
 				userService:       mock_service.NewMockUserService(ctrl),
 				sessionService:    mock_service.NewMockSessionService(ctrl),
+				throttleService:   mock_service.NewMockThrottleService(ctrl),
 				txCloser: func(tx repository.TxManager, err error) error {
 					return nil
 				},
@@ -110,12 +116,12 @@ func Test_authenticationService_SignUp(t *testing.T) {
 					Name:     model.UserNameForTest,
 					Password: model.PasswordForTest,
 				},
+				client: model.NewClient(model.ClientIPForTest, model.UserAgentForTest),
 			},
 			mockUserRepoArgs: mockUserRepoArgs{
 				user: &model.User{
 					ID:        model.UserValidIDForTest,
 					Name:      model.UserNameForTest,
-					SessionID: model.SessionValidIDForTest,
 					Password:  model.PasswordForTest,
 					CreatedAt: testutil.TimeNow(),
 					UpdatedAt: testutil.TimeNow(),
@@ -123,7 +129,8 @@ func Test_authenticationService_SignUp(t *testing.T) {
 			},
 			mockSessionRepoArgs: mockSessionRepoArgs{
 				session: &model.Session{
-					ID:        model.SessionValidIDForTest,
+					ID:        model.SessionIDFromToken(model.SessionTokenForTest),
+					Token:     model.SessionTokenForTest,
 					UserID:    model.UserValidIDForTest,
 					CreatedAt: testutil.TimeNow(),
 				},
@@ -168,7 +175,6 @@ func Test_authenticationService_SignUp(t *testing.T) {
 			wantUser: &model.User{
 				ID:        model.UserValidIDForTest,
 				Name:      model.UserNameForTest,
-				SessionID: model.SessionValidIDForTest,
 				Password:  model.PasswordForTest,
 				CreatedAt: testutil.TimeNow(),
 				UpdatedAt: testutil.TimeNow(),
@@ -183,7 +189,15 @@ func Test_authenticationService_SignUp(t *testing.T) {
 			if !ok {
 				t.Fatal("failed to assert MockUserRepository")
 			}
-			m.EXPECT().Begin().Return(mock_repository.NewMockTxManager(ctrl), nil)
+			tx := mock_repository.NewMockTxManager(ctrl)
+			m.EXPECT().Begin().Return(tx, nil)
+
+			th, ok := tt.fields.throttleService.(*mock_service.MockThrottleService)
+			if !ok {
+				t.Fatal("failed to assert MockThrottleService")
+			}
+			signUpKey := model.NewThrottleKey(model.ThrottleKindSignUpIP, tt.args.client.IPAddress)
+			th.EXPECT().Reserve(tt.args.ctx, signUpKey).Return(&model.ThrottleReservation{}, nil)
 
 			us, ok := tt.fields.userService.(*mock_service.MockUserService)
 			if !ok {
@@ -196,21 +210,20 @@ func Test_authenticationService_SignUp(t *testing.T) {
 			if !ok {
 				t.Fatal("failed to assert MockUserRepository")
 			}
-			ur.EXPECT().InsertUser(tt.fields.m, tt.mockUserRepoArgs.user).Return(tt.mockUserRepoReturns.id, tt.mockUserRepoReturns.err)
+			ur.EXPECT().InsertUser(tx, tt.mockUserRepoArgs.user).Return(tt.mockUserRepoReturns.id, tt.mockUserRepoReturns.err)
 
 			ss, ok := tt.fields.sessionService.(*mock_service.MockSessionService)
 			if !ok {
 				t.Fatal("failed to assert MockUserRepository")
 			}
-			ss.EXPECT().IsAlreadyExistID(tt.mockSessionServiceArgs.ctx, tt.mockSessionServiceArgs.id).Return(tt.mockSessionServiceReturns.found, tt.mockSessionServiceReturns.err)
-			ss.EXPECT().SessionID().Return(model.SessionValidIDForTest)
-			ss.EXPECT().NewSession(tt.mockSessionServiceArgs.userID).Return(tt.mockSessionServiceReturns.session)
+			ss.EXPECT().SessionToken().Return(model.SessionTokenForTest, nil)
+			ss.EXPECT().NewSession(tt.mockSessionServiceArgs.userID, tt.args.client).Return(tt.mockSessionServiceReturns.session)
 
 			sr, ok := tt.fields.sessionRepository.(*mock_repository.MockSessionRepository)
 			if !ok {
 				t.Fatal("failed to assert MockSessionRepository")
 			}
-			sr.EXPECT().InsertSession(tt.fields.m, tt.mockSessionRepoArgs.session).Return(tt.mockSessionRepoReturns.err)
+			sr.EXPECT().InsertSession(tx, tt.mockSessionRepoArgs.session).Return(tt.mockSessionRepoReturns.err)
 
 			a := &authenticationService{
 				m:                 tt.fields.m,
@@ -218,10 +231,11 @@ func Test_authenticationService_SignUp(t *testing.T) {
 				sessionRepository: tt.fields.sessionRepository,
 				userService:       tt.fields.userService,
 				sessionService:    tt.fields.sessionService,
+				throttleService:   tt.fields.throttleService,
 				txCloser:          tt.fields.txCloser,
 			}
 
-			gotUser, err := a.SignUp(tt.args.ctx, tt.args.user)
+			gotUser, gotSession, err := a.SignUp(tt.args.ctx, tt.args.user, tt.args.client)
 			if tt.wantErr != nil {
 				if errors.Cause(err).Error() != tt.wantErr.Error() {
 					t.Errorf("authenticationService.SignUp() error = %v, wantErr %v", err, tt.wantErr)
@@ -232,6 +246,451 @@ func Test_authenticationService_SignUp(t *testing.T) {
 			if !reflect.DeepEqual(gotUser, tt.wantUser) {
 				t.Errorf("authenticationService.SignUp() = %v, want %v", gotUser, tt.wantUser)
 			}
+			if !reflect.DeepEqual(gotSession, tt.mockSessionRepoArgs.session) {
+				t.Errorf("authenticationService.SignUp() session = %v, want %v", gotSession, tt.mockSessionRepoArgs.session)
+			}
+		})
+	}
+}
+
+func Test_authenticationService_Login(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	hashed, err := util.HashPassword(model.PasswordForTest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testutil.SetFakeTime(time.Now())
+
+	nameKey := model.NewThrottleKey(model.ThrottleKindLoginName, model.UserNameForTest)
+	ipKey := model.NewThrottleKey(model.ThrottleKindLoginIP, model.ClientIPForTest)
+
+	type args struct {
+		ctx    context.Context
+		user   *model.User
+		client *model.Client
+	}
+
+	tests := []struct {
+		name          string
+		args          args
+		throttleErr   error
+		storedUser    *model.User
+		storedUserErr error
+		storedTOTP    *model.TOTP
+		wantFailure   bool
+		wantSession   bool
+		wantPending   bool
+		wantErr       error
+	}{
+		{
+			name: "When name and password are valid, returns user and new session",
+			args: args{
+				ctx:    context.Background(),
+				user:   &model.User{Name: model.UserNameForTest, Password: model.PasswordForTest},
+				client: model.NewClient(model.ClientIPForTest, model.UserAgentForTest),
+			},
+			storedUser: &model.User{
+				ID:        model.UserValidIDForTest,
+				Name:      model.UserNameForTest,
+				Password:  hashed,
+				CreatedAt: testutil.TimeNow(),
+				UpdatedAt: testutil.TimeNow(),
+			},
+			wantSession: true,
+			wantErr:     nil,
+		},
+		{
+			name: "When TOTP of the user is enabled, returns pending login without session",
+			args: args{
+				ctx:    context.Background(),
+				user:   &model.User{Name: model.UserNameForTest, Password: model.PasswordForTest},
+				client: model.NewClient(model.ClientIPForTest, model.UserAgentForTest),
+			},
+			storedUser: &model.User{
+				ID:       model.UserValidIDForTest,
+				Name:     model.UserNameForTest,
+				Password: hashed,
+			},
+			storedTOTP: &model.TOTP{
+				UserID:  model.UserValidIDForTest,
+				Secret:  model.TOTPSecretForTest,
+				Enabled: true,
+			},
+			wantPending: true,
+		},
+		{
+			name: "When TOTP of the user is not confirmed yet, returns user and new session",
+			args: args{
+				ctx:    context.Background(),
+				user:   &model.User{Name: model.UserNameForTest, Password: model.PasswordForTest},
+				client: model.NewClient(model.ClientIPForTest, model.UserAgentForTest),
+			},
+			storedUser: &model.User{
+				ID:        model.UserValidIDForTest,
+				Name:      model.UserNameForTest,
+				Password:  hashed,
+				CreatedAt: testutil.TimeNow(),
+				UpdatedAt: testutil.TimeNow(),
+			},
+			storedTOTP: &model.TOTP{
+				UserID: model.UserValidIDForTest,
+				Secret: model.TOTPSecretForTest,
+			},
+			wantSession: true,
+		},
+		{
+			name: "When password is wrong, records failure and returns AuthenticationErr",
+			args: args{
+				ctx:    context.Background(),
+				user:   &model.User{Name: model.UserNameForTest, Password: "wrongPassword"},
+				client: model.NewClient(model.ClientIPForTest, model.UserAgentForTest),
+			},
+			storedUser: &model.User{
+				ID:       model.UserValidIDForTest,
+				Name:     model.UserNameForTest,
+				Password: hashed,
+			},
+			wantFailure: true,
+			wantErr:     &model.AuthenticationErr{},
+		},
+		{
+			name: "When user does not exist, records failure and returns AuthenticationErr",
+			args: args{
+				ctx:    context.Background(),
+				user:   &model.User{Name: model.UserNameForTest, Password: model.PasswordForTest},
+				client: model.NewClient(model.ClientIPForTest, model.UserAgentForTest),
+			},
+			storedUserErr: &model.NoSuchDataError{},
+			wantFailure:   true,
+			wantErr:       &model.AuthenticationErr{},
+		},
+		{
+			name: "When throttled, returns TooManyRequestsError without checking password",
+			args: args{
+				ctx:    context.Background(),
+				user:   &model.User{Name: model.UserNameForTest, Password: model.PasswordForTest},
+				client: model.NewClient(model.ClientIPForTest, model.UserAgentForTest),
+			},
+			throttleErr: &model.TooManyRequestsError{RetryAfter: time.Second},
+			wantErr:     &model.TooManyRequestsError{RetryAfter: time.Second},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock_repository.NewMockDBManager(ctrl)
+			ur := mock_repository.NewMockUserRepository(ctrl)
+			sr := mock_repository.NewMockSessionRepository(ctrl)
+			ss := mock_service.NewMockSessionService(ctrl)
+			th := mock_service.NewMockThrottleService(ctrl)
+			totpr := mock_repository.NewMockTOTPRepository(ctrl)
+			plr := memory.NewPendingLoginRepository()
+
+			reservation := &model.ThrottleReservation{Keys: []model.ThrottleKey{nameKey, ipKey}}
+			if tt.throttleErr != nil {
+				th.EXPECT().Reserve(tt.args.ctx, nameKey, ipKey).Return(nil, tt.throttleErr)
+			} else {
+				th.EXPECT().Reserve(tt.args.ctx, nameKey, ipKey).Return(reservation, nil)
+				ur.EXPECT().GetUserByName(m, tt.args.user.Name).Return(tt.storedUser, tt.storedUserErr)
+			}
+			// the reserved attempt is left as failure only when the password is wrong.
+			if tt.wantSession || tt.wantPending {
+				th.EXPECT().Release(tt.args.ctx, reservation).Return(nil)
+				th.EXPECT().Reset(tt.args.ctx, nameKey).Return(nil)
+				if tt.storedTOTP != nil {
+					totpr.EXPECT().GetTOTPByUserID(m, tt.storedUser.ID).Return(tt.storedTOTP, nil)
+				} else {
+					totpr.EXPECT().GetTOTPByUserID(m, tt.storedUser.ID).Return(nil, &model.NoSuchDataError{})
+				}
+			}
+			if tt.wantSession {
+				ss.EXPECT().SessionToken().Return(model.SessionTokenForTest, nil)
+				ss.EXPECT().NewSession(tt.storedUser.ID, tt.args.client).Return(&model.Session{UserID: tt.storedUser.ID, CreatedAt: testutil.TimeNow()})
+				sr.EXPECT().InsertSession(m, &model.Session{
+					ID:        model.SessionIDFromToken(model.SessionTokenForTest),
+					Token:     model.SessionTokenForTest,
+					UserID:    tt.storedUser.ID,
+					CreatedAt: testutil.TimeNow(),
+				}).Return(nil)
+			}
+
+			a := &authenticationService{
+				m:                      m,
+				userRepository:         ur,
+				sessionRepository:      sr,
+				totpRepository:         totpr,
+				pendingLoginRepository: plr,
+				sessionService:         ss,
+				throttleService:        th,
+				txCloser:               mock_application.MockCloseTransaction,
+				now:                    testutil.TimeNow,
+			}
+
+			_, gotSession, gotPending, err := a.Login(tt.args.ctx, tt.args.user, tt.args.client)
+			if tt.wantErr != nil {
+				if err == nil || errors.Cause(err).Error() != tt.wantErr.Error() {
+					t.Errorf("authenticationService.Login() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("authenticationService.Login() error = %v", err)
+			}
+
+			if tt.wantPending {
+				if gotSession != nil {
+					t.Errorf("authenticationService.Login() session = %v, want nil", gotSession)
+				}
+				if gotPending == nil || gotPending.UserID != tt.storedUser.ID || !gotPending.ExpiresAt.Equal(testutil.TimeNow().Add(PendingLoginTTL)) {
+					t.Fatalf("authenticationService.Login() pending = %v", gotPending)
+				}
+				if _, err := plr.TakePendingLogin(model.PendingLoginIDFromToken(gotPending.Token)); err != nil {
+					t.Errorf("pending login should be stored by the hash of its token: %v", err)
+				}
+				return
+			}
+			if gotPending != nil {
+				t.Errorf("authenticationService.Login() pending = %v, want nil", gotPending)
+			}
+
+			if gotSession.Token != model.SessionTokenForTest {
+				t.Errorf("authenticationService.Login() session token = %v, want %v", gotSession.Token, model.SessionTokenForTest)
+			}
+		})
+	}
+}
+
+func Test_authenticationService_LoginWithSecondFactor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testutil.SetFakeTime(time.Unix(59, 0))
+	defer testutil.ResetFakeTime()
+
+	ctx := context.Background()
+	client := model.NewClient(model.ClientIPForTest, model.UserAgentForTest)
+	user := &model.User{ID: model.UserValidIDForTest, Name: model.UserNameForTest}
+	key := model.NewThrottleKey(model.ThrottleKindSecondFactor, "1")
+
+	tests := []struct {
+		name string
+		// attempts is the number of wrong codes sent before.
+		attempts int
+		// elapsed is the time from the first step.
+		elapsed     time.Duration
+		token       string
+		code        string
+		stepUsedErr error
+		wantCheck   bool
+		wantSession bool
+		// wantKept is whether the pending login can be used again after the request.
+		wantKept bool
+	}{
+		{
+			name:        "When the TOTP code is valid, returns new session",
+			token:       model.PendingLoginTokenForTest,
+			code:        model.TOTPCodeForTest,
+			wantCheck:   true,
+			wantSession: true,
+		},
+		{
+			name:        "When the recovery code is valid, returns new session",
+			token:       model.PendingLoginTokenForTest,
+			code:        model.RecoveryCodeForTest,
+			wantCheck:   true,
+			wantSession: true,
+		},
+		{
+			name:      "When the code is wrong, returns AuthenticationErr and keeps the pending login",
+			token:     model.PendingLoginTokenForTest,
+			code:      "000000",
+			wantCheck: true,
+			wantKept:  true,
+		},
+		{
+			name:        "When the step of the code has been used by another request, returns AuthenticationErr",
+			token:       model.PendingLoginTokenForTest,
+			code:        model.TOTPCodeForTest,
+			stepUsedErr: &model.NoSuchDataError{},
+			wantCheck:   true,
+			wantKept:    true,
+		},
+		{
+			name:      "When the code is wrong at the last attempt, returns AuthenticationErr and drops the pending login",
+			attempts:  model.MaxPendingLoginAttempts - 1,
+			token:     model.PendingLoginTokenForTest,
+			code:      "000000",
+			wantCheck: true,
+		},
+		{
+			name:    "When the pending login is expired, returns AuthenticationErr",
+			elapsed: PendingLoginTTL,
+			token:   model.PendingLoginTokenForTest,
+			code:    model.TOTPCodeForTest,
+		},
+		{
+			name:     "When the pending token is unknown, returns AuthenticationErr",
+			token:    "unknownPendingLoginToken",
+			code:     model.TOTPCodeForTest,
+			wantKept: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock_repository.NewMockDBManager(ctrl)
+			ur := mock_repository.NewMockUserRepository(ctrl)
+			sr := mock_repository.NewMockSessionRepository(ctrl)
+			totpr := mock_repository.NewMockTOTPRepository(ctrl)
+			ss := mock_service.NewMockSessionService(ctrl)
+			th := mock_service.NewMockThrottleService(ctrl)
+			ts := mock_service.NewMockTOTPService(ctrl)
+			plr := memory.NewPendingLoginRepository()
+
+			start := time.Unix(59, 0).Add(-tt.elapsed)
+			if err := plr.InsertPendingLogin(&model.PendingLogin{
+				ID:        model.PendingLoginIDFromToken(model.PendingLoginTokenForTest),
+				UserID:    user.ID,
+				Attempts:  tt.attempts,
+				ExpiresAt: start.Add(PendingLoginTTL),
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.wantCheck {
+				ur.EXPECT().GetUserByID(m, user.ID).Return(user, nil)
+				totpr.EXPECT().GetTOTPByUserID(m, user.ID).Return(&model.TOTP{
+					UserID:  user.ID,
+					Secret:  model.TOTPSecretForTest,
+					Enabled: true,
+				}, nil)
+				th.EXPECT().Reserve(ctx, key).Return(&model.ThrottleReservation{Keys: []model.ThrottleKey{key}}, nil)
+
+				switch tt.code {
+				case model.TOTPCodeForTest:
+					ts.EXPECT().Verify(model.TOTPSecretForTest, tt.code, int64(0)).Return(int64(1), true)
+					totpr.EXPECT().UseTOTPStep(m, user.ID, int64(1)).Return(tt.stepUsedErr)
+				case model.RecoveryCodeForTest:
+					totpr.EXPECT().DeleteRecoveryCode(m, user.ID, model.RecoveryCodeIDFromCode(tt.code)).Return(nil)
+				default:
+					ts.EXPECT().Verify(model.TOTPSecretForTest, tt.code, int64(0)).Return(int64(0), false)
+				}
+			}
+			if tt.wantSession {
+				th.EXPECT().Reset(ctx, key).Return(nil)
+				ss.EXPECT().SessionToken().Return(model.SessionTokenForTest, nil)
+				ss.EXPECT().NewSession(user.ID, client).Return(&model.Session{UserID: user.ID})
+				sr.EXPECT().InsertSession(m, gomock.Any()).Return(nil)
+			}
+
+			a := &authenticationService{
+				m:                      m,
+				userRepository:         ur,
+				sessionRepository:      sr,
+				totpRepository:         totpr,
+				pendingLoginRepository: plr,
+				sessionService:         ss,
+				throttleService:        th,
+				totpService:            ts,
+				txCloser:               mock_application.MockCloseTransaction,
+				now:                    testutil.TimeNow,
+			}
+
+			_, gotSession, err := a.LoginWithSecondFactor(ctx, tt.token, tt.code, client)
+			if tt.wantSession {
+				if err != nil {
+					t.Fatalf("authenticationService.LoginWithSecondFactor() error = %v", err)
+				}
+				if gotSession.Token != model.SessionTokenForTest {
+					t.Errorf("authenticationService.LoginWithSecondFactor() session token = %v, want %v", gotSession.Token, model.SessionTokenForTest)
+				}
+			} else if _, ok := errors.Cause(err).(*model.AuthenticationErr); !ok {
+				t.Errorf("authenticationService.LoginWithSecondFactor() error = %v, want AuthenticationErr", err)
+			}
+
+			_, err = plr.TakePendingLogin(model.PendingLoginIDFromToken(model.PendingLoginTokenForTest))
+			if gotKept := err == nil; gotKept != tt.wantKept {
+				t.Errorf("pending login kept = %v, want %v", gotKept, tt.wantKept)
+			}
+		})
+	}
+}
+
+func Test_authenticationService_GetSessionUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testutil.SetFakeTime(time.Now())
+	client := model.NewClient(model.ClientIPForTest, model.UserAgentForTest)
+
+	tests := []struct {
+		name       string
+		lastSeenAt time.Time
+		ipAddress  string
+		disabled   bool
+		wantUpdate bool
+		wantErr    bool
+	}{
+		{
+			name:       "When the session was seen just now from the same IP, does not update last seen",
+			lastSeenAt: testutil.TimeNow().Add(-lastSeenInterval / 2),
+			ipAddress:  model.ClientIPForTest,
+		},
+		{
+			name:       "When the session was seen before the interval, updates last seen",
+			lastSeenAt: testutil.TimeNow().Add(-lastSeenInterval),
+			ipAddress:  model.ClientIPForTest,
+			wantUpdate: true,
+		},
+		{
+			name:       "When the session is used from another IP, updates last seen",
+			lastSeenAt: testutil.TimeNow(),
+			ipAddress:  "192.0.2.2",
+			wantUpdate: true,
+		},
+		{
+			name:       "When the user is disabled, returns AuthenticationErr",
+			lastSeenAt: testutil.TimeNow(),
+			ipAddress:  model.ClientIPForTest,
+			disabled:   true,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock_repository.NewMockDBManager(ctrl)
+			ur := mock_repository.NewMockUserRepository(ctrl)
+			sr := mock_repository.NewMockSessionRepository(ctrl)
+
+			sr.EXPECT().GetSessionByToken(m, model.SessionTokenForTest).Return(&model.Session{
+				ID:         model.SessionValidIDForTest,
+				UserID:     model.UserValidIDForTest,
+				IPAddress:  tt.ipAddress,
+				LastSeenAt: tt.lastSeenAt,
+			}, nil)
+			ur.EXPECT().GetUserByID(m, model.UserValidIDForTest).Return(&model.User{ID: model.UserValidIDForTest, Disabled: tt.disabled}, nil)
+			if tt.wantUpdate {
+				sr.EXPECT().UpdateLastSeen(m, model.SessionValidIDForTest, client.IPAddress, testutil.TimeNow()).Return(nil)
+			}
+
+			a := &authenticationService{
+				m:                 m,
+				userRepository:    ur,
+				sessionRepository: sr,
+				now:               testutil.TimeNow,
+			}
+
+			_, err := a.GetSessionUser(context.Background(), model.SessionTokenForTest, client)
+			if tt.wantErr {
+				if _, ok := errors.Cause(err).(*model.AuthenticationErr); !ok {
+					t.Errorf("authenticationService.GetSessionUser() error = %v, want AuthenticationErr", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("authenticationService.GetSessionUser() error = %v", err)
+			}
 		})
 	}
 }
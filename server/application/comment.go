@@ -0,0 +1,755 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/service"
+)
+
+// DefaultReplyPageLimit and MaxReplyPageLimit are the default and max number of replies listed at once.
+const (
+	DefaultReplyPageLimit = 50
+	MaxReplyPageLimit     = 100
+)
+
+// CommentService is the interface of CommentService.
+type CommentService interface {
+	ListComments(ctx context.Context, userID, threadID uint32) ([]*model.Comment, error)
+	PostComment(ctx context.Context, userID, threadID, parentCommentID uint32, content string) (*model.Comment, error)
+	ListReplies(ctx context.Context, userID, id, afterID uint32, limit int) (*model.ReplyPage, error)
+	EditComment(ctx context.Context, user *model.User, id uint32, content string) (*model.Comment, error)
+	DeleteComment(ctx context.Context, user *model.User, id uint32) (*model.Comment, error)
+	ListRevisions(ctx context.Context, userID, id uint32) ([]*model.CommentRevision, error)
+	AddReaction(ctx context.Context, userID, id uint32, emoji string) (*model.Comment, error)
+	RemoveReaction(ctx context.Context, userID, id uint32, emoji string) (*model.Comment, error)
+	PurgeDeletedComments(ctx context.Context, retention time.Duration) (int64, error)
+}
+
+// CommentServiceDIInput is DI input of CommentService.
+type CommentServiceDIInput struct {
+	threadRepository          repository.ThreadRepository
+	commentRepository         repository.CommentRepository
+	commentReactionRepository repository.CommentReactionRepository
+	commentRevisionRepository repository.CommentRevisionRepository
+	userRepository            repository.UserRepository
+	commentMentionRepository  repository.CommentMentionRepository
+	notificationRepository    repository.NotificationRepository
+	searchRepository          repository.SearchRepository
+	attachmentRepository      repository.AttachmentRepository
+	policyService             service.PolicyService
+}
+
+// NewCommentServiceDIInput generates and returns CommentServiceDIInput.
+func NewCommentServiceDIInput(tRepo repository.ThreadRepository, cRepo repository.CommentRepository, crRepo repository.CommentReactionRepository, cvRepo repository.CommentRevisionRepository, uRepo repository.UserRepository, cmRepo repository.CommentMentionRepository, nRepo repository.NotificationRepository, seRepo repository.SearchRepository, aRepo repository.AttachmentRepository, pService service.PolicyService) *CommentServiceDIInput {
+	return &CommentServiceDIInput{
+		threadRepository:          tRepo,
+		commentRepository:         cRepo,
+		commentReactionRepository: crRepo,
+		commentRevisionRepository: cvRepo,
+		userRepository:            uRepo,
+		commentMentionRepository:  cmRepo,
+		notificationRepository:    nRepo,
+		searchRepository:          seRepo,
+		attachmentRepository:      aRepo,
+		policyService:             pService,
+	}
+}
+
+// commentService is the service of comments in threads.
+type commentService struct {
+	m                         repository.DBManager
+	threadRepository          repository.ThreadRepository
+	commentRepository         repository.CommentRepository
+	commentReactionRepository repository.CommentReactionRepository
+	commentRevisionRepository repository.CommentRevisionRepository
+	userRepository            repository.UserRepository
+	commentMentionRepository  repository.CommentMentionRepository
+	notificationRepository    repository.NotificationRepository
+	searchRepository          repository.SearchRepository
+	attachmentRepository      repository.AttachmentRepository
+	policyService             service.PolicyService
+	txCloser                  CloseTransaction
+	now                       func() time.Time
+}
+
+// NewCommentService generates and returns CommentService.
+func NewCommentService(m repository.DBManager, diInput CommentServiceDIInput, txCloser CloseTransaction) CommentService {
+	return &commentService{
+		m:                         m,
+		threadRepository:          diInput.threadRepository,
+		commentRepository:         diInput.commentRepository,
+		commentReactionRepository: diInput.commentReactionRepository,
+		commentRevisionRepository: diInput.commentRevisionRepository,
+		userRepository:            diInput.userRepository,
+		commentMentionRepository:  diInput.commentMentionRepository,
+		notificationRepository:    diInput.notificationRepository,
+		searchRepository:          diInput.searchRepository,
+		attachmentRepository:      diInput.attachmentRepository,
+		policyService:             diInput.policyService,
+		txCloser:                  txCloser,
+		now:                       time.Now,
+	}
+}
+
+// ListComments returns comments of the thread in order of posting with their reactions and attachments.
+// Deleted comments are listed as tombstones unless the user can read deleted comments.
+// This returns NoSuchDataError if the thread does not exist or the user can not read it.
+func (s *commentService) ListComments(ctx context.Context, userID, threadID uint32) ([]*model.Comment, error) {
+	if _, err := getReadableThread(s.m, s.threadRepository, s.policyService, userID, threadID); err != nil {
+		return nil, err
+	}
+
+	comments, err := s.commentRepository.ListCommentsByThreadID(s.m, threadID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list comments by thread id")
+	}
+
+	if err := s.tombstoneDeletedComments(userID, threadID, comments); err != nil {
+		return nil, err
+	}
+
+	if err := setReactions(s.m, s.commentReactionRepository, userID, comments); err != nil {
+		return nil, err
+	}
+
+	if err := setAttachments(s.m, s.attachmentRepository, comments); err != nil {
+		return nil, err
+	}
+
+	if err := s.setReplySummaries(comments); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// ListReplies returns at most limit replies to the comment specified by id after afterID in order of posting,
+// with their reactions, attachments and summaries of replies to them. The default limit is used if limit is 0.
+// This returns NoSuchDataError if the comment does not exist or the user can not read its thread.
+func (s *commentService) ListReplies(ctx context.Context, userID, id, afterID uint32, limit int) (*model.ReplyPage, error) {
+	limit, err := pageLimit(limit, DefaultReplyPageLimit, MaxReplyPageLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	parent, err := s.getReadableComment(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// one more reply is read to know whether the next page exists.
+	replies, err := s.commentRepository.ListRepliesByParentID(s.m, id, afterID, limit+1)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list replies by parent id")
+	}
+
+	page := &model.ReplyPage{
+		ParentCommentID: id,
+		Replies:         replies,
+	}
+	if len(replies) > limit {
+		page.Replies = replies[:limit]
+		page.HasMore = true
+	}
+
+	if err := s.tombstoneDeletedComments(userID, parent.ThreadID, page.Replies); err != nil {
+		return nil, err
+	}
+
+	if err := setReactions(s.m, s.commentReactionRepository, userID, page.Replies); err != nil {
+		return nil, err
+	}
+
+	if err := setAttachments(s.m, s.attachmentRepository, page.Replies); err != nil {
+		return nil, err
+	}
+
+	if err := s.setReplySummaries(page.Replies); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// PostComment posts the comment to the thread by the user.
+// If parentCommentID is not 0, the comment replies to it, and NoSuchDataError is returned
+// if it is deleted or not in the thread.
+// Users mentioned with @name in the content are notified if they can read the thread.
+// Only members can post to the private thread because others can not read it,
+// and messages of direct conversations are posted by DirectMessageService.
+func (s *commentService) PostComment(ctx context.Context, userID, threadID, parentCommentID uint32, content string) (comment *model.Comment, err error) {
+	if err := model.ValidateCommentContent(strings.TrimSpace(content)); err != nil {
+		return nil, errors.Wrap(err, "failed to validate comment")
+	}
+
+	thread, err := getReadableThread(s.m, s.threadRepository, s.policyService, userID, threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	// blocks between the users are checked only by DirectMessageService.
+	if thread.IsDirect() {
+		return nil, errors.WithStack(&model.ForbiddenError{
+			InvalidReasonForDeveloper: "direct messages should be posted to the conversation",
+		})
+	}
+
+	comment, err = s.newComment(userID, threadID, parentCommentID, content)
+	if err != nil {
+		return nil, err
+	}
+
+	mentioned, notified, err := s.resolveMentions(userID, thread, content)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.m.Begin()
+	if err != nil {
+		return nil, beginTxErrorMsg(err)
+	}
+
+	defer func() {
+		if cErr := s.txCloser(tx, err); cErr != nil {
+			err = errors.Wrap(cErr, "failed to close tx")
+		}
+	}()
+
+	id, err := s.commentRepository.InsertComment(tx, comment)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to insert comment")
+	}
+	comment.ID = id
+
+	mentions := make([]*model.CommentMention, 0, len(mentioned))
+	for _, mentionedID := range mentioned {
+		mentions = append(mentions, model.NewCommentMention(comment, mentionedID))
+	}
+	if err := s.commentMentionRepository.InsertCommentMentions(tx, mentions); err != nil {
+		return nil, errors.Wrap(err, "failed to insert comment mentions")
+	}
+
+	notifications := make([]*model.Notification, 0, len(notified))
+	for _, notifiedID := range notified {
+		notifications = append(notifications, model.NewMentionNotification(comment, notifiedID))
+	}
+	if err := s.notificationRepository.InsertNotifications(tx, notifications); err != nil {
+		return nil, errors.Wrap(err, "failed to insert notifications")
+	}
+
+	if err := s.searchRepository.IndexComment(tx, comment); err != nil {
+		return nil, errors.Wrap(err, "failed to index comment")
+	}
+
+	return comment, nil
+}
+
+// EditComment changes the content of the comment specified by id, and keeps the previous content as a revision.
+// Users newly mentioned by the edit are notified like PostComment, and mentions removed by the edit are deleted.
+// Notifications already sent for removed mentions are kept.
+// This returns ForbiddenError if the user is not the author, and NoSuchDataError if the comment is deleted.
+func (s *commentService) EditComment(ctx context.Context, user *model.User, id uint32, content string) (comment *model.Comment, err error) {
+	content = strings.TrimSpace(content)
+	if err := model.ValidateCommentContent(content); err != nil {
+		return nil, errors.Wrap(err, "failed to validate comment")
+	}
+
+	comment, err = s.commentRepository.GetCommentByID(s.m, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get comment by id")
+	}
+
+	thread, err := getReadableThread(s.m, s.threadRepository, s.policyService, user.ID, comment.ThreadID)
+	if err != nil {
+		return nil, err
+	}
+
+	if comment.IsDeleted() {
+		return nil, errors.WithStack(&model.NoSuchDataError{
+			PropertyNameForDeveloper:    model.IDPropertyForDeveloper,
+			PropertyNameForUser:         model.IDPropertyForUser,
+			PropertyValue:               id,
+			DomainModelNameForDeveloper: model.DomainModelNameCommentForDeveloper,
+			DomainModelNameForUser:      model.DomainModelNameCommentForUser,
+		})
+	}
+
+	ok, err := s.policyService.CanEditComment(user, comment)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to check policy")
+	}
+	if !ok {
+		return nil, errors.WithStack(&model.ForbiddenError{
+			InvalidReasonForDeveloper: "only the author can edit the comment",
+		})
+	}
+
+	if content == comment.Content {
+		return comment, nil
+	}
+
+	mentioned, notified, err := s.resolveMentions(user.ID, thread, content)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	revision := model.NewCommentRevision(comment, user.ID, now)
+
+	tx, err := s.m.Begin()
+	if err != nil {
+		return nil, beginTxErrorMsg(err)
+	}
+
+	defer func() {
+		if cErr := s.txCloser(tx, err); cErr != nil {
+			err = errors.Wrap(cErr, "failed to close tx")
+		}
+	}()
+
+	if _, err := s.commentRevisionRepository.InsertCommentRevision(tx, revision); err != nil {
+		return nil, errors.Wrap(err, "failed to insert comment revision")
+	}
+
+	if err := s.commentRepository.UpdateCommentContent(tx, id, content, now); err != nil {
+		return nil, errors.Wrap(err, "failed to update comment content")
+	}
+
+	comment.Content = content
+	comment.UpdatedAt = now
+
+	if err := s.updateMentions(tx, comment, mentioned, notified, now); err != nil {
+		return nil, err
+	}
+
+	if err := s.searchRepository.IndexComment(tx, comment); err != nil {
+		return nil, errors.Wrap(err, "failed to index comment")
+	}
+	return comment, nil
+}
+
+// updateMentions replaces mentions of the edited comment with the mentioned users,
+// and notifies the users who are mentioned by the edit for the first time among the notified users.
+func (s *commentService) updateMentions(m repository.SQLManager, comment *model.Comment, mentioned, notified []uint32, now time.Time) error {
+	current, err := s.commentMentionRepository.GetMentionedUserIDsByCommentID(m, comment.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get mentioned user ids by comment id")
+	}
+
+	added := differenceOfIDs(mentioned, current)
+	mentions := make([]*model.CommentMention, 0, len(added))
+	for _, mentionedID := range added {
+		mention := model.NewCommentMention(comment, mentionedID)
+		mention.CreatedAt = now
+		mentions = append(mentions, mention)
+	}
+	if err := s.commentMentionRepository.InsertCommentMentions(m, mentions); err != nil {
+		return errors.Wrap(err, "failed to insert comment mentions")
+	}
+
+	if err := s.commentMentionRepository.DeleteCommentMentions(m, comment.ID, differenceOfIDs(current, mentioned)); err != nil {
+		return errors.Wrap(err, "failed to delete comment mentions")
+	}
+
+	addedIDs := make(map[uint32]bool, len(added))
+	for _, addedID := range added {
+		addedIDs[addedID] = true
+	}
+	notifications := make([]*model.Notification, 0, len(added))
+	for _, notifiedID := range notified {
+		if !addedIDs[notifiedID] {
+			continue
+		}
+		notification := model.NewMentionNotification(comment, notifiedID)
+		notification.CreatedAt = now
+		notifications = append(notifications, notification)
+	}
+	if err := s.notificationRepository.InsertNotifications(m, notifications); err != nil {
+		return errors.Wrap(err, "failed to insert notifications")
+	}
+	return nil
+}
+
+// differenceOfIDs returns ids in a which are not in b keeping the order of a.
+func differenceOfIDs(a, b []uint32) []uint32 {
+	inB := make(map[uint32]bool, len(b))
+	for _, id := range b {
+		inB[id] = true
+	}
+
+	diff := make([]uint32, 0, len(a))
+	for _, id := range a {
+		if !inB[id] {
+			diff = append(diff, id)
+		}
+	}
+	return diff
+}
+
+// DeleteComment deletes the comment specified by id and returns it.
+// The comment is kept as a tombstone, and its content is purged by PurgeDeletedComments later.
+// This returns ForbiddenError if the user is neither the author nor allowed to moderate the thread,
+// and NoSuchDataError if the user can not read the thread or the comment is already deleted.
+func (s *commentService) DeleteComment(ctx context.Context, user *model.User, id uint32) (*model.Comment, error) {
+	comment, err := s.getReadableComment(user.ID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := s.policyService.CanDeleteComment(user, comment)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to check policy")
+	}
+	if !ok {
+		return nil, errors.WithStack(&model.ForbiddenError{
+			InvalidReasonForDeveloper: "only the author or moderators can delete the comment",
+		})
+	}
+
+	now := s.now()
+	if err := s.softDeleteComment(id, user.ID, now); err != nil {
+		return nil, err
+	}
+	comment.DeletedAt = now
+	comment.DeletedBy = user.ID
+
+	// the index is updated after the tx is committed, so that the comment is not removed from it on rollback.
+	// comments left in the index by failure are not found because deleted comments are skipped.
+	if err := s.searchRepository.RemoveComment(s.m, id); err != nil {
+		return nil, errors.Wrap(err, "failed to remove comment from index")
+	}
+
+	return comment, nil
+}
+
+// softDeleteComment marks the comment as deleted and deletes its reactions in a tx.
+func (s *commentService) softDeleteComment(id, deletedBy uint32, deletedAt time.Time) (err error) {
+	tx, err := s.m.Begin()
+	if err != nil {
+		return beginTxErrorMsg(err)
+	}
+
+	defer func() {
+		if cErr := s.txCloser(tx, err); cErr != nil {
+			err = errors.Wrap(cErr, "failed to close tx")
+		}
+	}()
+
+	if err := s.commentRepository.SoftDeleteComment(tx, id, deletedBy, deletedAt); err != nil {
+		return errors.Wrap(err, "failed to delete comment")
+	}
+
+	if err := s.commentReactionRepository.DeleteCommentReactionsByCommentID(tx, id); err != nil {
+		return errors.Wrap(err, "failed to delete comment reactions")
+	}
+
+	return nil
+}
+
+// ListRevisions returns previous contents of the comment specified by id in order of editing.
+// This returns ForbiddenError if the comment is deleted and the user can not read deleted comments.
+func (s *commentService) ListRevisions(ctx context.Context, userID, id uint32) ([]*model.CommentRevision, error) {
+	comment, err := s.getReadableComment(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if comment.IsDeleted() {
+		ok, err := s.policyService.CanReadDeletedComments(userID, comment.ThreadID)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to check policy")
+		}
+		if !ok {
+			return nil, errors.WithStack(&model.ForbiddenError{
+				InvalidReasonForDeveloper: "only moderators can read history of deleted comments",
+			})
+		}
+	}
+
+	revisions, err := s.commentRevisionRepository.ListCommentRevisionsByCommentID(s.m, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list comment revisions by comment id")
+	}
+	return revisions, nil
+}
+
+// AddReaction adds the reaction of the user with the emoji to the comment specified by id,
+// and returns the comment with its reactions. Adding the same reaction again does nothing.
+// This returns NoSuchDataError if the user can not read the thread.
+func (s *commentService) AddReaction(ctx context.Context, userID, id uint32, emoji string) (*model.Comment, error) {
+	reaction, err := model.NewCommentReaction(id, userID, emoji, s.now())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to validate reaction")
+	}
+
+	comment, err := s.getReadableComment(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if comment.IsDeleted() {
+		return nil, errors.WithStack(&model.ForbiddenError{
+			InvalidReasonForDeveloper: "can not react to deleted comment",
+		})
+	}
+
+	if err := s.commentReactionRepository.InsertCommentReaction(s.m, reaction); err != nil {
+		return nil, errors.Wrap(err, "failed to insert comment reaction")
+	}
+
+	if err := setReactions(s.m, s.commentReactionRepository, userID, []*model.Comment{comment}); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// RemoveReaction removes the reaction of the user with the emoji from the comment specified by id,
+// and returns the comment with its reactions. Removing the reaction which does not exist does nothing.
+// This returns NoSuchDataError if the user can not read the thread.
+func (s *commentService) RemoveReaction(ctx context.Context, userID, id uint32, emoji string) (*model.Comment, error) {
+	if err := model.ValidateEmoji(emoji); err != nil {
+		return nil, errors.Wrap(err, "failed to validate reaction")
+	}
+
+	comment, err := s.getReadableComment(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.commentReactionRepository.DeleteCommentReaction(s.m, id, userID, emoji); err != nil {
+		return nil, errors.Wrap(err, "failed to delete comment reaction")
+	}
+
+	if err := setReactions(s.m, s.commentReactionRepository, userID, []*model.Comment{comment}); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// PurgeDeletedComments clears the content and revisions of comments deleted longer ago than retention,
+// and returns the number of purged comments. Tombstones are kept so that listings do not change.
+func (s *commentService) PurgeDeletedComments(ctx context.Context, retention time.Duration) (n int64, err error) {
+	deletedBefore := s.now().Add(-retention)
+
+	tx, err := s.m.Begin()
+	if err != nil {
+		return 0, beginTxErrorMsg(err)
+	}
+
+	defer func() {
+		if cErr := s.txCloser(tx, err); cErr != nil {
+			err = errors.Wrap(cErr, "failed to close tx")
+		}
+	}()
+
+	if err := s.commentRevisionRepository.DeleteRevisionsOfDeletedComments(tx, deletedBefore); err != nil {
+		return 0, errors.Wrap(err, "failed to delete revisions of deleted comments")
+	}
+
+	n, err = s.commentRepository.PurgeDeletedComments(tx, deletedBefore)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to purge deleted comments")
+	}
+	return n, nil
+}
+
+// newComment returns the comment posted to the thread, or the reply to the parent in the thread.
+func (s *commentService) newComment(userID, threadID, parentCommentID uint32, content string) (*model.Comment, error) {
+	if parentCommentID == 0 {
+		comment, err := model.NewComment(threadID, userID, content, s.now())
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to validate comment")
+		}
+		return comment, nil
+	}
+
+	parent, err := s.commentRepository.GetCommentByID(s.m, parentCommentID)
+	if err != nil {
+		if _, ok := errors.Cause(err).(*model.NoSuchDataError); !ok {
+			return nil, errors.Wrap(err, "failed to get parent comment")
+		}
+	}
+	if err != nil || parent.ThreadID != threadID {
+		return nil, errors.WithStack(&model.NoSuchDataError{
+			PropertyNameForDeveloper:    model.ParentCommentIDPropertyForDeveloper,
+			PropertyNameForUser:         model.ParentCommentIDPropertyForUser,
+			PropertyValue:               parentCommentID,
+			DomainModelNameForDeveloper: model.DomainModelNameCommentForDeveloper,
+			DomainModelNameForUser:      model.DomainModelNameCommentForUser,
+		})
+	}
+
+	comment, err := model.NewReply(parent, userID, content, s.now())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to validate reply")
+	}
+	return comment, nil
+}
+
+// resolveMentions returns ids of users mentioned in the content by the user, and of those to be notified among them.
+// Names of no user and the user themself are ignored, and users who can not read the thread are not notified,
+// so that mentions do not tell them about private threads.
+func (s *commentService) resolveMentions(userID uint32, thread *model.Thread, content string) (mentioned, notified []uint32, err error) {
+	seen := make(map[uint32]bool)
+	for _, name := range model.ParseMentions(content) {
+		user, err := s.userRepository.GetUserByName(s.m, name)
+		if err != nil {
+			if _, ok := errors.Cause(err).(*model.NoSuchDataError); ok {
+				continue
+			}
+			return nil, nil, errors.Wrap(err, "failed to get user by name")
+		}
+		if user.ID == userID || seen[user.ID] {
+			continue
+		}
+		seen[user.ID] = true
+		mentioned = append(mentioned, user.ID)
+
+		ok, err := s.policyService.CanReadThread(user.ID, thread)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "failed to check policy")
+		}
+		if ok {
+			notified = append(notified, user.ID)
+		}
+	}
+	return mentioned, notified, nil
+}
+
+// setReplySummaries sets summaries of replies to the comments by one query.
+func (s *commentService) setReplySummaries(comments []*model.Comment) error {
+	ids := make([]uint32, 0, len(comments))
+	byID := make(map[uint32]*model.Comment, len(comments))
+	for _, comment := range comments {
+		ids = append(ids, comment.ID)
+		byID[comment.ID] = comment
+	}
+
+	summaries, err := s.commentRepository.GetReplySummariesByParentIDs(s.m, ids)
+	if err != nil {
+		return errors.Wrap(err, "failed to get reply summaries by parent ids")
+	}
+
+	for _, summary := range summaries {
+		if comment, ok := byID[summary.ParentCommentID]; ok {
+			comment.Replies = summary
+		}
+	}
+	return nil
+}
+
+// tombstoneDeletedComments hides the content of deleted comments in the thread unless the user can read them.
+// The policy is not checked if no comment is deleted.
+func (s *commentService) tombstoneDeletedComments(userID, threadID uint32, comments []*model.Comment) error {
+	var deleted []*model.Comment
+	for _, comment := range comments {
+		if comment.IsDeleted() {
+			deleted = append(deleted, comment)
+		}
+	}
+	if len(deleted) == 0 {
+		return nil
+	}
+
+	ok, err := s.policyService.CanReadDeletedComments(userID, threadID)
+	if err != nil {
+		return errors.Wrap(err, "failed to check policy")
+	}
+	if ok {
+		return nil
+	}
+
+	for _, comment := range deleted {
+		comment.Tombstone()
+	}
+	return nil
+}
+
+// getReadableComment returns the comment specified by id if the user can read its thread.
+func (s *commentService) getReadableComment(userID, id uint32) (*model.Comment, error) {
+	comment, err := s.commentRepository.GetCommentByID(s.m, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get comment by id")
+	}
+
+	if _, err := getReadableThread(s.m, s.threadRepository, s.policyService, userID, comment.ThreadID); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// setReactions sets reactions to the comments for the user by one query.
+// Deleted comments have no reaction.
+func setReactions(m repository.SQLManager, crRepo repository.CommentReactionRepository, userID uint32, comments []*model.Comment) error {
+	ids := make([]uint32, 0, len(comments))
+	byID := make(map[uint32]*model.Comment, len(comments))
+	for _, comment := range comments {
+		comment.Reactions = make([]*model.ReactionCount, 0)
+		if comment.IsDeleted() {
+			continue
+		}
+		ids = append(ids, comment.ID)
+		byID[comment.ID] = comment
+	}
+
+	counts, err := crRepo.GetReactionCountsByCommentIDs(m, userID, ids)
+	if err != nil {
+		return errors.Wrap(err, "failed to get reaction counts by comment ids")
+	}
+
+	for _, count := range counts {
+		if comment, ok := byID[count.CommentID]; ok {
+			comment.Reactions = append(comment.Reactions, count)
+		}
+	}
+	return nil
+}
+
+// setAttachments sets files attached to the comments by one query.
+// Deleted comments have no attachment.
+func setAttachments(m repository.SQLManager, aRepo repository.AttachmentRepository, comments []*model.Comment) error {
+	ids := make([]uint32, 0, len(comments))
+	byID := make(map[uint32]*model.Comment, len(comments))
+	for _, comment := range comments {
+		comment.Attachments = make([]*model.Attachment, 0)
+		if comment.IsDeleted() {
+			continue
+		}
+		ids = append(ids, comment.ID)
+		byID[comment.ID] = comment
+	}
+
+	attachments, err := aRepo.ListAttachmentsByCommentIDs(m, ids)
+	if err != nil {
+		return errors.Wrap(err, "failed to list attachments by comment ids")
+	}
+
+	for _, attachment := range attachments {
+		if comment, ok := byID[attachment.CommentID]; ok {
+			comment.Attachments = append(comment.Attachments, attachment)
+		}
+	}
+	return nil
+}
+
+// pageLimit returns defaultLimit if limit is 0, and InvalidParamError if limit is not between 1 and maxLimit.
+func pageLimit(limit, defaultLimit, maxLimit int) (int, error) {
+	if limit == 0 {
+		return defaultLimit, nil
+	}
+	if limit < 0 || limit > maxLimit {
+		return 0, errors.WithStack(&model.InvalidParamError{
+			PropertyNameForDeveloper:  model.LimitPropertyForDeveloper,
+			PropertyNameForUser:       model.LimitPropertyForUser,
+			PropertyValue:             limit,
+			InvalidReasonForDeveloper: fmt.Sprintf("limit should be between 1 and %d", maxLimit),
+			InvalidReasonForUser:      fmt.Sprintf("件数は1から%dまでで指定してください", maxLimit),
+		})
+	}
+	return limit, nil
+}
@@ -0,0 +1,829 @@
+package application
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+
+	mock_application "github.com/hideUW/nuxt-go-chat-app/server/application/mock"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+	mock_repository "github.com/hideUW/nuxt-go-chat-app/server/domain/repository/mock"
+	mock_service "github.com/hideUW/nuxt-go-chat-app/server/domain/service/mock"
+	"github.com/hideUW/nuxt-go-chat-app/server/testutil"
+)
+
+func Test_commentService_DeleteComment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testutil.SetFakeTime(time.Now())
+	defer testutil.ResetFakeTime()
+
+	ctx := context.Background()
+	user := &model.User{ID: model.UserValidIDForTest}
+	thread := &model.Thread{ID: model.ThreadValidIDForTest, Visibility: model.ThreadVisibilityPublic}
+
+	tests := []struct {
+		name        string
+		storedErr   error
+		allowed     bool
+		reactionErr error
+		wantErr     error
+	}{
+		{
+			name:    "When the policy allows, deletes the comment",
+			allowed: true,
+		},
+		{
+			name:        "When deleting reactions fails, rolls back and keeps the comment in the index",
+			allowed:     true,
+			reactionErr: errors.New(model.ErrorMessageForTest),
+			wantErr:     errors.New(model.ErrorMessageForTest),
+		},
+		{
+			name:    "When the policy does not allow, returns ForbiddenError",
+			allowed: false,
+			wantErr: &model.ForbiddenError{InvalidReasonForDeveloper: "only the author or moderators can delete the comment"},
+		},
+		{
+			name:      "When the comment does not exist, returns NoSuchDataError",
+			storedErr: &model.NoSuchDataError{},
+			wantErr:   &model.NoSuchDataError{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock_repository.NewMockDBManager(ctrl)
+			tx := mock_repository.NewMockTxManager(ctrl)
+			tr := mock_repository.NewMockThreadRepository(ctrl)
+			cr := mock_repository.NewMockCommentRepository(ctrl)
+			crr := mock_repository.NewMockCommentReactionRepository(ctrl)
+			ser := mock_repository.NewMockSearchRepository(ctrl)
+			ps := mock_service.NewMockPolicyService(ctrl)
+
+			comment := &model.Comment{
+				ID:       model.CommentValidIDForTest,
+				ThreadID: model.ThreadValidIDForTest,
+				UserID:   model.UserInValidIDForTest,
+				Content:  model.CommentContentForTest,
+			}
+			if tt.storedErr != nil {
+				cr.EXPECT().GetCommentByID(m, model.CommentValidIDForTest).Return(nil, tt.storedErr)
+			} else {
+				cr.EXPECT().GetCommentByID(m, model.CommentValidIDForTest).Return(comment, nil)
+				tr.EXPECT().GetThreadByID(m, model.ThreadValidIDForTest).Return(thread, nil)
+				ps.EXPECT().CanReadThread(model.UserValidIDForTest, thread).Return(true, nil)
+				ps.EXPECT().CanDeleteComment(user, comment).Return(tt.allowed, nil)
+			}
+			var closedErr error
+			closed := false
+			if tt.allowed {
+				m.EXPECT().Begin().Return(tx, nil)
+				cr.EXPECT().SoftDeleteComment(tx, model.CommentValidIDForTest, model.UserValidIDForTest, testutil.TimeNow()).Return(nil)
+				crr.EXPECT().DeleteCommentReactionsByCommentID(tx, model.CommentValidIDForTest).Return(tt.reactionErr)
+			}
+			if tt.allowed && tt.reactionErr == nil {
+				// the index is updated after the tx is closed.
+				ser.EXPECT().RemoveComment(m, model.CommentValidIDForTest).DoAndReturn(func(_ interface{}, _ uint32) error {
+					if !closed {
+						t.Error("comment should be removed from index after tx is closed")
+					}
+					return nil
+				})
+			}
+
+			s := &commentService{
+				m:                         m,
+				threadRepository:          tr,
+				commentRepository:         cr,
+				commentReactionRepository: crr,
+				searchRepository:          ser,
+				policyService:             ps,
+				now:                       testutil.TimeNow,
+				txCloser: func(_ repository.TxManager, err error) error {
+					closed = true
+					closedErr = err
+					return nil
+				},
+			}
+
+			got, err := s.DeleteComment(ctx, user, model.CommentValidIDForTest)
+			if tt.reactionErr != nil && (!closed || closedErr == nil) {
+				t.Errorf("tx should be closed with error, closed = %v, err = %v", closed, closedErr)
+			}
+			if tt.wantErr != nil {
+				if err == nil || errors.Cause(err).Error() != tt.wantErr.Error() {
+					t.Errorf("commentService.DeleteComment() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if !closed || closedErr != nil {
+				t.Errorf("tx should be committed, closed = %v, err = %v", closed, closedErr)
+			}
+			if err != nil {
+				t.Fatalf("commentService.DeleteComment() error = %v", err)
+			}
+			if got != comment || !got.IsDeleted() || got.DeletedBy != model.UserValidIDForTest {
+				testutil.Errorf(t, comment, got)
+			}
+		})
+	}
+}
+
+func Test_commentService_ListComments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	m := mock_repository.NewMockDBManager(ctrl)
+	tr := mock_repository.NewMockThreadRepository(ctrl)
+	cr := mock_repository.NewMockCommentRepository(ctrl)
+	crr := mock_repository.NewMockCommentReactionRepository(ctrl)
+	ar := mock_repository.NewMockAttachmentRepository(ctrl)
+	ps := mock_service.NewMockPolicyService(ctrl)
+
+	thread := &model.Thread{ID: model.ThreadValidIDForTest, Visibility: model.ThreadVisibilityPublic}
+	comments := []*model.Comment{
+		{ID: 1, ThreadID: model.ThreadValidIDForTest},
+		{ID: 2, ThreadID: model.ThreadValidIDForTest},
+	}
+	counts := []*model.ReactionCount{
+		{CommentID: 1, Emoji: model.EmojiForTest, Count: 2, ReactedByMe: true},
+		{CommentID: 1, Emoji: "🎉", Count: 1},
+	}
+	attachments := []*model.Attachment{
+		{ID: model.AttachmentValidIDForTest, CommentID: 2, FileName: model.AttachmentFileNameForTest},
+	}
+
+	tr.EXPECT().GetThreadByID(m, model.ThreadValidIDForTest).Return(thread, nil)
+	ps.EXPECT().CanReadThread(model.UserValidIDForTest, thread).Return(true, nil)
+	cr.EXPECT().ListCommentsByThreadID(m, model.ThreadValidIDForTest).Return(comments, nil)
+	// reactions to all comments are aggregated by one call.
+	crr.EXPECT().GetReactionCountsByCommentIDs(m, model.UserValidIDForTest, []uint32{1, 2}).Return(counts, nil).Times(1)
+	ar.EXPECT().ListAttachmentsByCommentIDs(m, []uint32{1, 2}).Return(attachments, nil).Times(1)
+	cr.EXPECT().GetReplySummariesByParentIDs(m, []uint32{1, 2}).Return([]*model.ReplySummary{}, nil).Times(1)
+
+	s := &commentService{
+		m:                         m,
+		threadRepository:          tr,
+		commentRepository:         cr,
+		commentReactionRepository: crr,
+		attachmentRepository:      ar,
+		policyService:             ps,
+		now:                       testutil.TimeNow,
+	}
+
+	got, err := s.ListComments(ctx, model.UserValidIDForTest, model.ThreadValidIDForTest)
+	if err != nil {
+		t.Fatalf("commentService.ListComments() error = %v", err)
+	}
+
+	want := [][]*model.ReactionCount{counts, {}}
+	wantAttachments := [][]*model.Attachment{{}, attachments}
+	for i, comment := range got {
+		if !reflect.DeepEqual(comment.Reactions, want[i]) {
+			testutil.Errorf(t, want[i], comment.Reactions)
+		}
+		if !reflect.DeepEqual(comment.Attachments, wantAttachments[i]) {
+			testutil.Errorf(t, wantAttachments[i], comment.Attachments)
+		}
+	}
+}
+
+func Test_commentService_AddReaction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testutil.SetFakeTime(time.Now())
+	defer testutil.ResetFakeTime()
+
+	ctx := context.Background()
+	thread := &model.Thread{ID: model.ThreadValidIDForTest, Visibility: model.ThreadVisibilityPublic}
+
+	tests := []struct {
+		name       string
+		emoji      string
+		wantInsert bool
+		wantErr    error
+	}{
+		{
+			name:       "When the emoji is allowed, adds the reaction and returns the comment with reactions",
+			emoji:      model.EmojiForTest,
+			wantInsert: true,
+		},
+		{
+			name:  "When the emoji is not allowed, returns InvalidParamError",
+			emoji: "a",
+			wantErr: &model.InvalidParamError{
+				PropertyNameForDeveloper:  model.EmojiPropertyForDeveloper,
+				PropertyNameForUser:       model.EmojiPropertyForUser,
+				PropertyValue:             "a",
+				InvalidReasonForDeveloper: "not allowed emoji",
+				InvalidReasonForUser:      "この絵文字ではリアクションできません",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock_repository.NewMockDBManager(ctrl)
+			tr := mock_repository.NewMockThreadRepository(ctrl)
+			cr := mock_repository.NewMockCommentRepository(ctrl)
+			crr := mock_repository.NewMockCommentReactionRepository(ctrl)
+			ps := mock_service.NewMockPolicyService(ctrl)
+
+			counts := []*model.ReactionCount{{CommentID: model.CommentValidIDForTest, Emoji: tt.emoji, Count: 1, ReactedByMe: true}}
+			if tt.wantInsert {
+				cr.EXPECT().GetCommentByID(m, model.CommentValidIDForTest).Return(&model.Comment{
+					ID:       model.CommentValidIDForTest,
+					ThreadID: model.ThreadValidIDForTest,
+				}, nil)
+				tr.EXPECT().GetThreadByID(m, model.ThreadValidIDForTest).Return(thread, nil)
+				ps.EXPECT().CanReadThread(model.UserValidIDForTest, thread).Return(true, nil)
+				gomock.InOrder(
+					crr.EXPECT().InsertCommentReaction(m, &model.CommentReaction{
+						CommentID: model.CommentValidIDForTest,
+						UserID:    model.UserValidIDForTest,
+						Emoji:     tt.emoji,
+						CreatedAt: testutil.TimeNow(),
+					}).Return(nil),
+					crr.EXPECT().GetReactionCountsByCommentIDs(m, model.UserValidIDForTest, []uint32{model.CommentValidIDForTest}).Return(counts, nil),
+				)
+			}
+
+			s := &commentService{
+				m:                         m,
+				threadRepository:          tr,
+				commentRepository:         cr,
+				commentReactionRepository: crr,
+				policyService:             ps,
+				now:                       testutil.TimeNow,
+			}
+
+			got, err := s.AddReaction(ctx, model.UserValidIDForTest, model.CommentValidIDForTest, tt.emoji)
+			if tt.wantErr != nil {
+				if err == nil || errors.Cause(err).Error() != tt.wantErr.Error() {
+					t.Errorf("commentService.AddReaction() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("commentService.AddReaction() error = %v", err)
+			}
+			if !reflect.DeepEqual(got.Reactions, counts) {
+				testutil.Errorf(t, counts, got.Reactions)
+			}
+		})
+	}
+}
+
+func Test_commentService_ListComments_deleted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	thread := &model.Thread{ID: model.ThreadValidIDForTest, Visibility: model.ThreadVisibilityPublic}
+
+	tests := []struct {
+		name        string
+		canRead     bool
+		wantContent string
+	}{
+		{
+			name:        "When the user can not read deleted comments, lists the tombstone",
+			canRead:     false,
+			wantContent: "",
+		},
+		{
+			name:        "When the user moderates the thread, lists the original content",
+			canRead:     true,
+			wantContent: model.CommentContentForTest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock_repository.NewMockDBManager(ctrl)
+			tr := mock_repository.NewMockThreadRepository(ctrl)
+			cr := mock_repository.NewMockCommentRepository(ctrl)
+			crr := mock_repository.NewMockCommentReactionRepository(ctrl)
+			ar := mock_repository.NewMockAttachmentRepository(ctrl)
+			ps := mock_service.NewMockPolicyService(ctrl)
+
+			comments := []*model.Comment{{
+				ID:        model.CommentValidIDForTest,
+				ThreadID:  model.ThreadValidIDForTest,
+				UserID:    model.UserInValidIDForTest,
+				Content:   model.CommentContentForTest,
+				DeletedAt: testutil.TimeNow(),
+				DeletedBy: model.UserInValidIDForTest,
+			}}
+
+			tr.EXPECT().GetThreadByID(m, model.ThreadValidIDForTest).Return(thread, nil)
+			ps.EXPECT().CanReadThread(model.UserValidIDForTest, thread).Return(true, nil)
+			cr.EXPECT().ListCommentsByThreadID(m, model.ThreadValidIDForTest).Return(comments, nil)
+			ps.EXPECT().CanReadDeletedComments(model.UserValidIDForTest, model.ThreadValidIDForTest).Return(tt.canRead, nil)
+			// deleted comments have no reaction.
+			crr.EXPECT().GetReactionCountsByCommentIDs(m, model.UserValidIDForTest, []uint32{}).Return([]*model.ReactionCount{}, nil)
+			ar.EXPECT().ListAttachmentsByCommentIDs(m, []uint32{}).Return([]*model.Attachment{}, nil)
+			cr.EXPECT().GetReplySummariesByParentIDs(m, []uint32{model.CommentValidIDForTest}).Return([]*model.ReplySummary{}, nil)
+
+			s := &commentService{
+				m:                         m,
+				threadRepository:          tr,
+				commentRepository:         cr,
+				commentReactionRepository: crr,
+				attachmentRepository:      ar,
+				policyService:             ps,
+				now:                       testutil.TimeNow,
+			}
+
+			got, err := s.ListComments(ctx, model.UserValidIDForTest, model.ThreadValidIDForTest)
+			if err != nil {
+				t.Fatalf("commentService.ListComments() error = %v", err)
+			}
+			if len(got) != 1 || !got[0].IsDeleted() || got[0].Content != tt.wantContent {
+				testutil.Errorf(t, tt.wantContent, got)
+			}
+		})
+	}
+}
+
+func Test_commentService_EditComment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testutil.SetFakeTime(time.Now())
+	defer testutil.ResetFakeTime()
+
+	ctx := context.Background()
+	user := &model.User{ID: model.UserValidIDForTest}
+	thread := &model.Thread{ID: model.ThreadValidIDForTest, Visibility: model.ThreadVisibilityPublic}
+	newContent := "editedCommentContent"
+	alice := &model.User{ID: 11, Name: "alice"}
+	carol := &model.User{ID: 13, Name: "carol"}
+	const bobID = 12
+
+	tests := []struct {
+		name       string
+		content    string
+		deleted    bool
+		allowed    bool
+		wantUpdate bool
+		// mentionedUsers are users mentioned in the content in order.
+		mentionedUsers []*model.User
+		// currentMentions are ids of users mentioned before the edit.
+		currentMentions []uint32
+		wantAdded       []uint32
+		wantRemoved     []uint32
+		wantErr         error
+	}{
+		{
+			name:            "When the author edits, keeps the previous content as a revision and updates",
+			content:         newContent,
+			allowed:         true,
+			wantUpdate:      true,
+			currentMentions: []uint32{},
+			wantAdded:       []uint32{},
+			wantRemoved:     []uint32{},
+		},
+		{
+			name:            "When the edit changes mentions, notifies only added users and deletes removed mentions",
+			content:         "@alice and @carol",
+			allowed:         true,
+			wantUpdate:      true,
+			mentionedUsers:  []*model.User{alice, carol},
+			currentMentions: []uint32{bobID, carol.ID},
+			wantAdded:       []uint32{alice.ID},
+			wantRemoved:     []uint32{bobID},
+		},
+		{
+			name:    "When the content is not changed, does not keep a revision",
+			content: model.CommentContentForTest,
+			allowed: true,
+		},
+		{
+			name:    "When the user is not the author, returns ForbiddenError",
+			content: newContent,
+			allowed: false,
+			wantErr: &model.ForbiddenError{InvalidReasonForDeveloper: "only the author can edit the comment"},
+		},
+		{
+			name:    "When the comment is deleted, returns NoSuchDataError",
+			content: newContent,
+			deleted: true,
+			wantErr: &model.NoSuchDataError{
+				PropertyNameForDeveloper:    model.IDPropertyForDeveloper,
+				PropertyNameForUser:         model.IDPropertyForUser,
+				PropertyValue:               model.CommentValidIDForTest,
+				DomainModelNameForDeveloper: model.DomainModelNameCommentForDeveloper,
+				DomainModelNameForUser:      model.DomainModelNameCommentForUser,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock_repository.NewMockDBManager(ctrl)
+			tx := mock_repository.NewMockTxManager(ctrl)
+			tr := mock_repository.NewMockThreadRepository(ctrl)
+			cr := mock_repository.NewMockCommentRepository(ctrl)
+			cvr := mock_repository.NewMockCommentRevisionRepository(ctrl)
+			ur := mock_repository.NewMockUserRepository(ctrl)
+			cmr := mock_repository.NewMockCommentMentionRepository(ctrl)
+			nr := mock_repository.NewMockNotificationRepository(ctrl)
+			ser := mock_repository.NewMockSearchRepository(ctrl)
+			ps := mock_service.NewMockPolicyService(ctrl)
+
+			comment := &model.Comment{
+				ID:        model.CommentValidIDForTest,
+				ThreadID:  model.ThreadValidIDForTest,
+				UserID:    model.UserValidIDForTest,
+				Content:   model.CommentContentForTest,
+				CreatedAt: testutil.TimeNow().Add(-time.Minute),
+				UpdatedAt: testutil.TimeNow().Add(-time.Minute),
+			}
+			for _, mentioned := range tt.mentionedUsers {
+				ur.EXPECT().GetUserByName(m, mentioned.Name).Return(mentioned, nil)
+				ps.EXPECT().CanReadThread(mentioned.ID, thread).Return(true, nil)
+			}
+			if tt.deleted {
+				comment.DeletedAt = testutil.TimeNow()
+			}
+
+			cr.EXPECT().GetCommentByID(m, model.CommentValidIDForTest).Return(comment, nil)
+			tr.EXPECT().GetThreadByID(m, model.ThreadValidIDForTest).Return(thread, nil)
+			ps.EXPECT().CanReadThread(model.UserValidIDForTest, thread).Return(true, nil)
+			if !tt.deleted {
+				ps.EXPECT().CanEditComment(user, comment).Return(tt.allowed, nil)
+			}
+			if tt.wantUpdate {
+				m.EXPECT().Begin().Return(tx, nil)
+				gomock.InOrder(
+					cvr.EXPECT().InsertCommentRevision(tx, &model.CommentRevision{
+						CommentID: model.CommentValidIDForTest,
+						EditorID:  model.UserValidIDForTest,
+						Content:   model.CommentContentForTest,
+						CreatedAt: testutil.TimeNow(),
+					}).Return(uint32(1), nil),
+					cr.EXPECT().UpdateCommentContent(tx, model.CommentValidIDForTest, tt.content, testutil.TimeNow()).Return(nil),
+					cmr.EXPECT().GetMentionedUserIDsByCommentID(tx, model.CommentValidIDForTest).Return(tt.currentMentions, nil),
+					cmr.EXPECT().InsertCommentMentions(tx, gomock.Any()).DoAndReturn(func(_ interface{}, mentions []*model.CommentMention) error {
+						got := make([]uint32, 0, len(mentions))
+						for _, mention := range mentions {
+							got = append(got, mention.UserID)
+						}
+						if !reflect.DeepEqual(got, tt.wantAdded) {
+							t.Errorf("InsertCommentMentions() is called with users %v, want %v", got, tt.wantAdded)
+						}
+						return nil
+					}),
+					cmr.EXPECT().DeleteCommentMentions(tx, model.CommentValidIDForTest, tt.wantRemoved).Return(nil),
+					nr.EXPECT().InsertNotifications(tx, gomock.Any()).DoAndReturn(func(_ interface{}, notifications []*model.Notification) error {
+						got := make([]uint32, 0, len(notifications))
+						for _, notification := range notifications {
+							got = append(got, notification.UserID)
+						}
+						if !reflect.DeepEqual(got, tt.wantAdded) {
+							t.Errorf("InsertNotifications() is called with users %v, want %v", got, tt.wantAdded)
+						}
+						return nil
+					}),
+					ser.EXPECT().IndexComment(tx, gomock.Any()).DoAndReturn(func(_ interface{}, c *model.Comment) error {
+						if c.Content != tt.content {
+							t.Errorf("IndexComment() is called with content %q, want %q", c.Content, tt.content)
+						}
+						return nil
+					}),
+				)
+			}
+
+			s := &commentService{
+				m:                         m,
+				threadRepository:          tr,
+				commentRepository:         cr,
+				commentRevisionRepository: cvr,
+				userRepository:            ur,
+				commentMentionRepository:  cmr,
+				notificationRepository:    nr,
+				searchRepository:          ser,
+				policyService:             ps,
+				txCloser:                  mock_application.MockCloseTransaction,
+				now:                       testutil.TimeNow,
+			}
+
+			got, err := s.EditComment(ctx, user, model.CommentValidIDForTest, tt.content)
+			if tt.wantErr != nil {
+				if err == nil || errors.Cause(err).Error() != tt.wantErr.Error() {
+					t.Errorf("commentService.EditComment() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("commentService.EditComment() error = %v", err)
+			}
+			if got.Content != tt.content || got.IsEdited() != tt.wantUpdate {
+				testutil.Errorf(t, tt.content, got)
+			}
+		})
+	}
+}
+
+func Test_commentService_PostComment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testutil.SetFakeTime(time.Now())
+	defer testutil.ResetFakeTime()
+
+	ctx := context.Background()
+	thread := &model.Thread{ID: model.ThreadValidIDForTest, Visibility: model.ThreadVisibilityPublic}
+	var parentID uint32 = 10
+	var otherThreadID uint32 = 2
+	noSuchParent := &model.NoSuchDataError{
+		PropertyNameForDeveloper:    model.ParentCommentIDPropertyForDeveloper,
+		PropertyNameForUser:         model.ParentCommentIDPropertyForUser,
+		PropertyValue:               parentID,
+		DomainModelNameForDeveloper: model.DomainModelNameCommentForDeveloper,
+		DomainModelNameForUser:      model.DomainModelNameCommentForUser,
+	}
+
+	tests := []struct {
+		name            string
+		parentCommentID uint32
+		parent          *model.Comment
+		wantDepth       uint8
+		wantErr         error
+	}{
+		{
+			name: "When no parent is given, posts the comment to the thread",
+		},
+		{
+			name:            "When the parent is given, posts the reply one level deeper",
+			parentCommentID: parentID,
+			parent:          &model.Comment{ID: parentID, ThreadID: model.ThreadValidIDForTest, Depth: 1},
+			wantDepth:       2,
+		},
+		{
+			name:            "When the parent does not exist, returns NoSuchDataError",
+			parentCommentID: parentID,
+			wantErr:         noSuchParent,
+		},
+		{
+			name:            "When the parent is in another thread, returns NoSuchDataError",
+			parentCommentID: parentID,
+			parent:          &model.Comment{ID: parentID, ThreadID: otherThreadID},
+			wantErr:         noSuchParent,
+		},
+		{
+			name:            "When the parent is deleted, returns NoSuchDataError",
+			parentCommentID: parentID,
+			parent:          &model.Comment{ID: parentID, ThreadID: model.ThreadValidIDForTest, DeletedAt: testutil.TimeNow()},
+			wantErr:         noSuchParent,
+		},
+		{
+			name:            "When the parent is at the max depth, returns InvalidParamError",
+			parentCommentID: parentID,
+			parent:          &model.Comment{ID: parentID, ThreadID: model.ThreadValidIDForTest, Depth: model.MaxCommentDepth},
+			wantErr: &model.InvalidParamError{
+				PropertyNameForDeveloper:  model.ParentCommentIDPropertyForDeveloper,
+				PropertyNameForUser:       model.ParentCommentIDPropertyForUser,
+				PropertyValue:             parentID,
+				InvalidReasonForDeveloper: "replies can be nested up to 3 levels",
+				InvalidReasonForUser:      "返信は3段階までです",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock_repository.NewMockDBManager(ctrl)
+			tr := mock_repository.NewMockThreadRepository(ctrl)
+			cr := mock_repository.NewMockCommentRepository(ctrl)
+			ps := mock_service.NewMockPolicyService(ctrl)
+
+			tr.EXPECT().GetThreadByID(m, model.ThreadValidIDForTest).Return(thread, nil)
+			ps.EXPECT().CanReadThread(model.UserValidIDForTest, thread).Return(true, nil)
+			if tt.parentCommentID != 0 {
+				if tt.parent != nil {
+					cr.EXPECT().GetCommentByID(m, tt.parentCommentID).Return(tt.parent, nil)
+				} else {
+					cr.EXPECT().GetCommentByID(m, tt.parentCommentID).Return(nil, &model.NoSuchDataError{})
+				}
+			}
+			cmr := mock_repository.NewMockCommentMentionRepository(ctrl)
+			nr := mock_repository.NewMockNotificationRepository(ctrl)
+			ser := mock_repository.NewMockSearchRepository(ctrl)
+			if tt.wantErr == nil {
+				tx := mock_repository.NewMockTxManager(ctrl)
+				m.EXPECT().Begin().Return(tx, nil)
+				cr.EXPECT().InsertComment(tx, &model.Comment{
+					ThreadID:        model.ThreadValidIDForTest,
+					UserID:          model.UserValidIDForTest,
+					ParentCommentID: tt.parentCommentID,
+					Depth:           tt.wantDepth,
+					Content:         model.CommentContentForTest,
+					CreatedAt:       testutil.TimeNow(),
+					UpdatedAt:       testutil.TimeNow(),
+				}).Return(model.CommentValidIDForTest, nil)
+				cmr.EXPECT().InsertCommentMentions(tx, []*model.CommentMention{}).Return(nil)
+				nr.EXPECT().InsertNotifications(tx, []*model.Notification{}).Return(nil)
+				ser.EXPECT().IndexComment(tx, gomock.Any()).Return(nil)
+			}
+
+			s := &commentService{
+				m:                        m,
+				threadRepository:         tr,
+				commentRepository:        cr,
+				commentMentionRepository: cmr,
+				notificationRepository:   nr,
+				searchRepository:         ser,
+				policyService:            ps,
+				txCloser:                 mock_application.MockCloseTransaction,
+				now:                      testutil.TimeNow,
+			}
+
+			got, err := s.PostComment(ctx, model.UserValidIDForTest, model.ThreadValidIDForTest, tt.parentCommentID, model.CommentContentForTest)
+			if tt.wantErr != nil {
+				if err == nil || errors.Cause(err).Error() != tt.wantErr.Error() {
+					t.Errorf("commentService.PostComment() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("commentService.PostComment() error = %v", err)
+			}
+			if got.ID != model.CommentValidIDForTest {
+				testutil.Errorf(t, model.CommentValidIDForTest, got.ID)
+			}
+		})
+	}
+}
+
+func Test_commentService_PostComment_mentions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testutil.SetFakeTime(time.Now())
+	defer testutil.ResetFakeTime()
+
+	ctx := context.Background()
+	m := mock_repository.NewMockDBManager(ctrl)
+	tx := mock_repository.NewMockTxManager(ctrl)
+	tr := mock_repository.NewMockThreadRepository(ctrl)
+	cr := mock_repository.NewMockCommentRepository(ctrl)
+	ur := mock_repository.NewMockUserRepository(ctrl)
+	cmr := mock_repository.NewMockCommentMentionRepository(ctrl)
+	nr := mock_repository.NewMockNotificationRepository(ctrl)
+	ser := mock_repository.NewMockSearchRepository(ctrl)
+	ps := mock_service.NewMockPolicyService(ctrl)
+
+	thread := &model.Thread{ID: model.ThreadValidIDForTest, Visibility: model.ThreadVisibilityPrivate}
+	member := &model.User{ID: 2, Name: "太郎"}
+	outsider := &model.User{ID: 3, Name: "bob"}
+	// the author, the user who does not exist, the email address and the duplicated mention are ignored.
+	content := "@太郎さん, @bob. @" + model.UserNameForTest + " @nobody mail@example.com @太郎さん"
+
+	tr.EXPECT().GetThreadByID(m, thread.ID).Return(thread, nil)
+	ps.EXPECT().CanReadThread(model.UserValidIDForTest, thread).Return(true, nil)
+	ur.EXPECT().GetUserByName(m, "太郎さん").Return(member, nil)
+	ur.EXPECT().GetUserByName(m, "bob").Return(outsider, nil)
+	ur.EXPECT().GetUserByName(m, model.UserNameForTest).Return(&model.User{ID: model.UserValidIDForTest, Name: model.UserNameForTest}, nil)
+	ur.EXPECT().GetUserByName(m, "nobody").Return(nil, &model.NoSuchDataError{})
+	ps.EXPECT().CanReadThread(member.ID, thread).Return(true, nil)
+	ps.EXPECT().CanReadThread(outsider.ID, thread).Return(false, nil)
+	m.EXPECT().Begin().Return(tx, nil)
+	cr.EXPECT().InsertComment(tx, gomock.Any()).Return(model.CommentValidIDForTest, nil)
+	cmr.EXPECT().InsertCommentMentions(tx, []*model.CommentMention{
+		{CommentID: model.CommentValidIDForTest, UserID: member.ID, CreatedAt: testutil.TimeNow()},
+		{CommentID: model.CommentValidIDForTest, UserID: outsider.ID, CreatedAt: testutil.TimeNow()},
+	}).Return(nil)
+	// the user who can not read the private thread is not notified.
+	nr.EXPECT().InsertNotifications(tx, []*model.Notification{
+		{
+			UserID:    member.ID,
+			Kind:      model.NotificationKindMention,
+			ThreadID:  thread.ID,
+			CommentID: model.CommentValidIDForTest,
+			ActorID:   model.UserValidIDForTest,
+			CreatedAt: testutil.TimeNow(),
+		},
+	}).Return(nil)
+	ser.EXPECT().IndexComment(tx, gomock.Any()).Return(nil)
+
+	s := &commentService{
+		m:                        m,
+		threadRepository:         tr,
+		commentRepository:        cr,
+		userRepository:           ur,
+		commentMentionRepository: cmr,
+		notificationRepository:   nr,
+		searchRepository:         ser,
+		policyService:            ps,
+		txCloser:                 mock_application.MockCloseTransaction,
+		now:                      testutil.TimeNow,
+	}
+
+	if _, err := s.PostComment(ctx, model.UserValidIDForTest, thread.ID, 0, content); err != nil {
+		t.Fatalf("commentService.PostComment() error = %v", err)
+	}
+}
+
+func Test_commentService_ListReplies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	thread := &model.Thread{ID: model.ThreadValidIDForTest, Visibility: model.ThreadVisibilityPublic}
+	parent := &model.Comment{ID: model.CommentValidIDForTest, ThreadID: model.ThreadValidIDForTest}
+	var afterID uint32 = 20
+
+	tests := []struct {
+		name        string
+		limit       int
+		stored      []uint32
+		wantIDs     []uint32
+		wantHasMore bool
+		wantErr     error
+	}{
+		{
+			name:        "When more replies than limit exist, returns the page and HasMore",
+			limit:       2,
+			stored:      []uint32{21, 22, 23},
+			wantIDs:     []uint32{21, 22},
+			wantHasMore: true,
+		},
+		{
+			name:    "When the replies fit in the page, returns them without HasMore",
+			limit:   2,
+			stored:  []uint32{21},
+			wantIDs: []uint32{21},
+		},
+		{
+			name:  "When limit is over the max, returns InvalidParamError",
+			limit: MaxReplyPageLimit + 1,
+			wantErr: &model.InvalidParamError{
+				PropertyNameForDeveloper:  model.LimitPropertyForDeveloper,
+				PropertyNameForUser:       model.LimitPropertyForUser,
+				PropertyValue:             MaxReplyPageLimit + 1,
+				InvalidReasonForDeveloper: "limit should be between 1 and 100",
+				InvalidReasonForUser:      "件数は1から100までで指定してください",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock_repository.NewMockDBManager(ctrl)
+			tr := mock_repository.NewMockThreadRepository(ctrl)
+			cr := mock_repository.NewMockCommentRepository(ctrl)
+			crr := mock_repository.NewMockCommentReactionRepository(ctrl)
+			ar := mock_repository.NewMockAttachmentRepository(ctrl)
+			ps := mock_service.NewMockPolicyService(ctrl)
+
+			if tt.wantErr == nil {
+				stored := make([]*model.Comment, 0, len(tt.stored))
+				for _, id := range tt.stored {
+					stored = append(stored, &model.Comment{ID: id, ThreadID: model.ThreadValidIDForTest, ParentCommentID: parent.ID, Depth: 1})
+				}
+
+				cr.EXPECT().GetCommentByID(m, parent.ID).Return(parent, nil)
+				tr.EXPECT().GetThreadByID(m, model.ThreadValidIDForTest).Return(thread, nil)
+				ps.EXPECT().CanReadThread(model.UserValidIDForTest, thread).Return(true, nil)
+				cr.EXPECT().ListRepliesByParentID(m, parent.ID, afterID, tt.limit+1).Return(stored, nil)
+				crr.EXPECT().GetReactionCountsByCommentIDs(m, model.UserValidIDForTest, tt.wantIDs).Return([]*model.ReactionCount{}, nil)
+				ar.EXPECT().ListAttachmentsByCommentIDs(m, tt.wantIDs).Return([]*model.Attachment{}, nil)
+				cr.EXPECT().GetReplySummariesByParentIDs(m, tt.wantIDs).Return([]*model.ReplySummary{}, nil)
+			}
+
+			s := &commentService{
+				m:                         m,
+				threadRepository:          tr,
+				commentRepository:         cr,
+				commentReactionRepository: crr,
+				attachmentRepository:      ar,
+				policyService:             ps,
+				now:                       testutil.TimeNow,
+			}
+
+			got, err := s.ListReplies(ctx, model.UserValidIDForTest, parent.ID, afterID, tt.limit)
+			if tt.wantErr != nil {
+				if err == nil || errors.Cause(err).Error() != tt.wantErr.Error() {
+					t.Errorf("commentService.ListReplies() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("commentService.ListReplies() error = %v", err)
+			}
+
+			ids := make([]uint32, 0, len(got.Replies))
+			for _, reply := range got.Replies {
+				ids = append(ids, reply.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) || got.HasMore != tt.wantHasMore {
+				testutil.Errorf(t, tt.wantIDs, ids)
+			}
+		})
+	}
+}
@@ -0,0 +1,328 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+)
+
+// DirectMessageService is the interface of DirectMessageService.
+// This manages 1:1 conversations between users and blocks which stop them.
+type DirectMessageService interface {
+	ListConversations(ctx context.Context, userID uint32) ([]*model.DirectConversation, error)
+	GetOrCreateConversation(ctx context.Context, userID, otherUserID uint32) (*model.DirectConversation, error)
+	ListMessages(ctx context.Context, userID, threadID uint32) ([]*model.Comment, error)
+	PostMessage(ctx context.Context, userID, threadID uint32, content string) (*model.Comment, error)
+	ListBlocks(ctx context.Context, userID uint32) ([]*model.UserBlock, error)
+	BlockUser(ctx context.Context, userID, blockedUserID uint32) (*model.UserBlock, error)
+	UnblockUser(ctx context.Context, userID, blockedUserID uint32) error
+}
+
+// DirectMessageServiceDIInput is DI input of DirectMessageService.
+type DirectMessageServiceDIInput struct {
+	userRepository               repository.UserRepository
+	threadRepository             repository.ThreadRepository
+	threadMemberRepository       repository.ThreadMemberRepository
+	commentRepository            repository.CommentRepository
+	commentReactionRepository    repository.CommentReactionRepository
+	directConversationRepository repository.DirectConversationRepository
+	userBlockRepository          repository.UserBlockRepository
+	searchRepository             repository.SearchRepository
+	attachmentRepository         repository.AttachmentRepository
+}
+
+// NewDirectMessageServiceDIInput generates and returns DirectMessageServiceDIInput.
+func NewDirectMessageServiceDIInput(uRepo repository.UserRepository, tRepo repository.ThreadRepository, mRepo repository.ThreadMemberRepository, cRepo repository.CommentRepository, crRepo repository.CommentReactionRepository, dcRepo repository.DirectConversationRepository, ubRepo repository.UserBlockRepository, seRepo repository.SearchRepository, aRepo repository.AttachmentRepository) *DirectMessageServiceDIInput {
+	return &DirectMessageServiceDIInput{
+		userRepository:               uRepo,
+		threadRepository:             tRepo,
+		threadMemberRepository:       mRepo,
+		commentRepository:            cRepo,
+		commentReactionRepository:    crRepo,
+		directConversationRepository: dcRepo,
+		userBlockRepository:          ubRepo,
+		searchRepository:             seRepo,
+		attachmentRepository:         aRepo,
+	}
+}
+
+// directMessageService is the service of direct messages.
+// Each conversation has the thread whose visibility is direct, and messages are stored as its comments.
+type directMessageService struct {
+	m                            repository.DBManager
+	userRepository               repository.UserRepository
+	threadRepository             repository.ThreadRepository
+	threadMemberRepository       repository.ThreadMemberRepository
+	commentRepository            repository.CommentRepository
+	commentReactionRepository    repository.CommentReactionRepository
+	directConversationRepository repository.DirectConversationRepository
+	userBlockRepository          repository.UserBlockRepository
+	searchRepository             repository.SearchRepository
+	attachmentRepository         repository.AttachmentRepository
+	txCloser                     CloseTransaction
+	now                          func() time.Time
+}
+
+// NewDirectMessageService generates and returns DirectMessageService.
+func NewDirectMessageService(m repository.DBManager, diInput DirectMessageServiceDIInput, txCloser CloseTransaction) DirectMessageService {
+	return &directMessageService{
+		m:                            m,
+		userRepository:               diInput.userRepository,
+		threadRepository:             diInput.threadRepository,
+		threadMemberRepository:       diInput.threadMemberRepository,
+		commentRepository:            diInput.commentRepository,
+		commentReactionRepository:    diInput.commentReactionRepository,
+		directConversationRepository: diInput.directConversationRepository,
+		userBlockRepository:          diInput.userBlockRepository,
+		searchRepository:             diInput.searchRepository,
+		attachmentRepository:         diInput.attachmentRepository,
+		txCloser:                     txCloser,
+		now:                          time.Now,
+	}
+}
+
+// ListConversations returns conversations of the user in order of last activity, the newest first.
+func (s *directMessageService) ListConversations(ctx context.Context, userID uint32) ([]*model.DirectConversation, error) {
+	conversations, err := s.directConversationRepository.ListDirectConversationsByUserID(s.m, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list direct conversations")
+	}
+	return conversations, nil
+}
+
+// GetOrCreateConversation returns the conversation between the users, which is created if it does not exist yet.
+// This returns ForbiddenError on creating it if either of the users blocks the other.
+func (s *directMessageService) GetOrCreateConversation(ctx context.Context, userID, otherUserID uint32) (*model.DirectConversation, error) {
+	conversation, err := s.directConversationRepository.GetDirectConversationByUserIDs(s.m, userID, otherUserID)
+	if err == nil {
+		return conversation, nil
+	}
+	if _, ok := errors.Cause(err).(*model.NoSuchDataError); !ok {
+		return nil, errors.Wrap(err, "failed to get direct conversation")
+	}
+
+	conversation, thread, err := model.NewDirectConversation(userID, otherUserID, s.now())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to validate direct conversation")
+	}
+
+	if _, err := s.userRepository.GetUserByID(s.m, otherUserID); err != nil {
+		return nil, errors.Wrap(err, "failed to get user by id")
+	}
+
+	if err := s.checkNotBlocked(userID, otherUserID); err != nil {
+		return nil, err
+	}
+
+	if err := s.createConversation(conversation, thread); err != nil {
+		// the other may create it at the same time, and then it is returned.
+		if created, gErr := s.directConversationRepository.GetDirectConversationByUserIDs(s.m, userID, otherUserID); gErr == nil {
+			return created, nil
+		}
+		return nil, err
+	}
+
+	return conversation, nil
+}
+
+// createConversation inserts the conversation and its thread whose members are both users.
+func (s *directMessageService) createConversation(conversation *model.DirectConversation, thread *model.Thread) (err error) {
+	tx, err := s.m.Begin()
+	if err != nil {
+		return beginTxErrorMsg(err)
+	}
+
+	defer func() {
+		if cErr := s.txCloser(tx, err); cErr != nil {
+			err = errors.Wrap(cErr, "failed to close tx")
+		}
+	}()
+
+	id, err := s.threadRepository.InsertThread(tx, thread)
+	if err != nil {
+		return errors.Wrap(err, "failed to insert thread")
+	}
+	thread.ID = id
+	conversation.ThreadID = id
+
+	for _, userID := range []uint32{conversation.UserID1, conversation.UserID2} {
+		member := &model.ThreadMember{
+			ThreadID:  id,
+			UserID:    userID,
+			Role:      model.ThreadMemberRoleMember,
+			CreatedAt: conversation.CreatedAt,
+		}
+		if err := s.threadMemberRepository.InsertThreadMember(tx, member); err != nil {
+			return errors.Wrap(err, "failed to insert member of thread")
+		}
+	}
+
+	if err := s.directConversationRepository.InsertDirectConversation(tx, conversation); err != nil {
+		return errors.Wrap(err, "failed to insert direct conversation")
+	}
+
+	return nil
+}
+
+// ListMessages returns messages of the conversation in order of posting with their reactions and attachments.
+// Deleted messages are listed as tombstones.
+// This returns NoSuchDataError if the user is not in the conversation.
+func (s *directMessageService) ListMessages(ctx context.Context, userID, threadID uint32) ([]*model.Comment, error) {
+	if _, err := s.getConversation(userID, threadID); err != nil {
+		return nil, err
+	}
+
+	comments, err := s.commentRepository.ListCommentsByThreadID(s.m, threadID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list comments by thread id")
+	}
+
+	// nobody moderates direct conversations, so deleted messages are tombstones for both users.
+	for _, comment := range comments {
+		if comment.IsDeleted() {
+			comment.Tombstone()
+		}
+	}
+
+	if err := setReactions(s.m, s.commentReactionRepository, userID, comments); err != nil {
+		return nil, err
+	}
+
+	if err := setAttachments(s.m, s.attachmentRepository, comments); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// PostMessage posts the message to the conversation by the user.
+// This returns ForbiddenError if either of the users blocks the other.
+func (s *directMessageService) PostMessage(ctx context.Context, userID, threadID uint32, content string) (comment *model.Comment, err error) {
+	comment, err = model.NewComment(threadID, userID, content, s.now())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to validate comment")
+	}
+
+	conversation, err := s.getConversation(userID, threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkNotBlocked(userID, conversation.OtherUserID(userID)); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.m.Begin()
+	if err != nil {
+		return nil, beginTxErrorMsg(err)
+	}
+
+	defer func() {
+		if cErr := s.txCloser(tx, err); cErr != nil {
+			err = errors.Wrap(cErr, "failed to close tx")
+		}
+	}()
+
+	id, err := s.commentRepository.InsertComment(tx, comment)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to insert comment")
+	}
+	comment.ID = id
+
+	if err := s.directConversationRepository.UpdateLastMessageAt(tx, threadID, comment.CreatedAt); err != nil {
+		return nil, errors.Wrap(err, "failed to update last message of direct conversation")
+	}
+
+	if err := s.searchRepository.IndexComment(tx, comment); err != nil {
+		return nil, errors.Wrap(err, "failed to index comment")
+	}
+
+	return comment, nil
+}
+
+// ListBlocks returns blocks by the user in order of blocking.
+func (s *directMessageService) ListBlocks(ctx context.Context, userID uint32) ([]*model.UserBlock, error) {
+	blocks, err := s.userBlockRepository.GetUserBlocksByUserID(s.m, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get blocks by user id")
+	}
+	return blocks, nil
+}
+
+// BlockUser makes the user block the other, which stops new direct messages between them.
+// Existing conversations can still be read.
+func (s *directMessageService) BlockUser(ctx context.Context, userID, blockedUserID uint32) (*model.UserBlock, error) {
+	if userID == blockedUserID {
+		return nil, errors.WithStack(&model.InvalidParamError{
+			PropertyNameForDeveloper:  model.UserIDPropertyForDeveloper,
+			PropertyNameForUser:       model.UserIDPropertyForUser,
+			PropertyValue:             blockedUserID,
+			InvalidReasonForDeveloper: "can not block oneself",
+			InvalidReasonForUser:      "自分自身はブロックできません",
+		})
+	}
+
+	if _, err := s.userRepository.GetUserByID(s.m, blockedUserID); err != nil {
+		return nil, errors.Wrap(err, "failed to get user by id")
+	}
+
+	block := &model.UserBlock{
+		UserID:        userID,
+		BlockedUserID: blockedUserID,
+		CreatedAt:     s.now(),
+	}
+	if err := s.userBlockRepository.InsertUserBlock(s.m, block); err != nil {
+		return nil, errors.Wrap(err, "failed to insert block")
+	}
+
+	return block, nil
+}
+
+// UnblockUser removes the block of the other by the user.
+// This returns NoSuchDataError if the user does not block the other.
+func (s *directMessageService) UnblockUser(ctx context.Context, userID, blockedUserID uint32) error {
+	if err := s.userBlockRepository.DeleteUserBlock(s.m, userID, blockedUserID); err != nil {
+		return errors.Wrap(err, "failed to delete block")
+	}
+	return nil
+}
+
+// getConversation returns the conversation of the thread.
+// This returns NoSuchDataError if the user is not in the conversation, so that others' conversations are not revealed.
+func (s *directMessageService) getConversation(userID, threadID uint32) (*model.DirectConversation, error) {
+	conversation, err := s.directConversationRepository.GetDirectConversationByThreadID(s.m, threadID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get direct conversation by thread id")
+	}
+
+	if !conversation.HasUser(userID) {
+		return nil, errors.WithStack(&model.NoSuchDataError{
+			PropertyNameForDeveloper:    model.IDPropertyForDeveloper,
+			PropertyNameForUser:         model.IDPropertyForUser,
+			PropertyValue:               threadID,
+			DomainModelNameForDeveloper: model.DomainModelNameDirectConversationForDeveloper,
+			DomainModelNameForUser:      model.DomainModelNameDirectConversationForUser,
+		})
+	}
+
+	return conversation, nil
+}
+
+// checkNotBlocked returns ForbiddenError if either of the users blocks the other.
+// The message does not tell which of them blocks.
+func (s *directMessageService) checkNotBlocked(userID, otherUserID uint32) error {
+	blocks, err := s.userBlockRepository.GetUserBlocksBetween(s.m, userID, otherUserID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get blocks between users")
+	}
+
+	if len(blocks) > 0 {
+		return errors.WithStack(&model.ForbiddenError{
+			InvalidReasonForDeveloper: "can not send direct messages between users who block",
+		})
+	}
+	return nil
+}
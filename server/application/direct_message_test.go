@@ -0,0 +1,245 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+
+	mock_application "github.com/hideUW/nuxt-go-chat-app/server/application/mock"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	mock_repository "github.com/hideUW/nuxt-go-chat-app/server/domain/repository/mock"
+	"github.com/hideUW/nuxt-go-chat-app/server/testutil"
+)
+
+func Test_directMessageService_GetOrCreateConversation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testutil.SetFakeTime(time.Now())
+	defer testutil.ResetFakeTime()
+
+	ctx := context.Background()
+	existing := &model.DirectConversation{
+		ThreadID: model.ThreadValidIDForTest,
+		UserID1:  model.UserValidIDForTest,
+		UserID2:  model.UserInValidIDForTest,
+	}
+	forbidden := &model.ForbiddenError{InvalidReasonForDeveloper: "can not send direct messages between users who block"}
+
+	tests := []struct {
+		name        string
+		otherUserID uint32
+		stored      *model.DirectConversation
+		blocks      []*model.UserBlock
+		wantCreate  bool
+		wantErr     error
+	}{
+		{
+			name:        "When the conversation exists, returns it without creating",
+			otherUserID: model.UserInValidIDForTest,
+			stored:      existing,
+		},
+		{
+			name:        "When the conversation does not exist, creates it and its thread whose members are both users",
+			otherUserID: model.UserInValidIDForTest,
+			blocks:      []*model.UserBlock{},
+			wantCreate:  true,
+		},
+		{
+			name:        "When the other blocks the user, returns ForbiddenError",
+			otherUserID: model.UserInValidIDForTest,
+			blocks:      []*model.UserBlock{{UserID: model.UserInValidIDForTest, BlockedUserID: model.UserValidIDForTest}},
+			wantErr:     forbidden,
+		},
+		{
+			name:        "When the other is the user, returns InvalidParamError",
+			otherUserID: model.UserValidIDForTest,
+			wantErr: &model.InvalidParamError{
+				PropertyNameForDeveloper:  model.UserIDPropertyForDeveloper,
+				PropertyNameForUser:       model.UserIDPropertyForUser,
+				PropertyValue:             model.UserValidIDForTest,
+				InvalidReasonForDeveloper: "can not start conversation with oneself",
+				InvalidReasonForUser:      "自分自身にダイレクトメッセージは送れません",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock_repository.NewMockDBManager(ctrl)
+			tx := mock_repository.NewMockTxManager(ctrl)
+			ur := mock_repository.NewMockUserRepository(ctrl)
+			tr := mock_repository.NewMockThreadRepository(ctrl)
+			mr := mock_repository.NewMockThreadMemberRepository(ctrl)
+			dcr := mock_repository.NewMockDirectConversationRepository(ctrl)
+			ubr := mock_repository.NewMockUserBlockRepository(ctrl)
+
+			if tt.stored != nil {
+				dcr.EXPECT().GetDirectConversationByUserIDs(m, model.UserValidIDForTest, tt.otherUserID).Return(tt.stored, nil)
+			} else {
+				dcr.EXPECT().GetDirectConversationByUserIDs(m, model.UserValidIDForTest, tt.otherUserID).Return(nil, &model.NoSuchDataError{})
+			}
+			if tt.blocks != nil {
+				ur.EXPECT().GetUserByID(m, tt.otherUserID).Return(&model.User{ID: tt.otherUserID}, nil)
+				ubr.EXPECT().GetUserBlocksBetween(m, model.UserValidIDForTest, tt.otherUserID).Return(tt.blocks, nil)
+			}
+			if tt.wantCreate {
+				m.EXPECT().Begin().Return(tx, nil)
+				gomock.InOrder(
+					tr.EXPECT().InsertThread(tx, &model.Thread{
+						Visibility: model.ThreadVisibilityDirect,
+						UserID:     model.UserValidIDForTest,
+						CreatedAt:  testutil.TimeNow(),
+						UpdatedAt:  testutil.TimeNow(),
+					}).Return(model.ThreadValidIDForTest, nil),
+					mr.EXPECT().InsertThreadMember(tx, &model.ThreadMember{
+						ThreadID:  model.ThreadValidIDForTest,
+						UserID:    model.UserValidIDForTest,
+						Role:      model.ThreadMemberRoleMember,
+						CreatedAt: testutil.TimeNow(),
+					}).Return(nil),
+					mr.EXPECT().InsertThreadMember(tx, &model.ThreadMember{
+						ThreadID:  model.ThreadValidIDForTest,
+						UserID:    tt.otherUserID,
+						Role:      model.ThreadMemberRoleMember,
+						CreatedAt: testutil.TimeNow(),
+					}).Return(nil),
+					dcr.EXPECT().InsertDirectConversation(tx, &model.DirectConversation{
+						ThreadID:      model.ThreadValidIDForTest,
+						UserID1:       model.UserValidIDForTest,
+						UserID2:       tt.otherUserID,
+						LastMessageAt: testutil.TimeNow(),
+						CreatedAt:     testutil.TimeNow(),
+					}).Return(nil),
+				)
+			}
+
+			s := &directMessageService{
+				m:                            m,
+				userRepository:               ur,
+				threadRepository:             tr,
+				threadMemberRepository:       mr,
+				directConversationRepository: dcr,
+				userBlockRepository:          ubr,
+				txCloser:                     mock_application.MockCloseTransaction,
+				now:                          testutil.TimeNow,
+			}
+
+			got, err := s.GetOrCreateConversation(ctx, model.UserValidIDForTest, tt.otherUserID)
+			if tt.wantErr != nil {
+				if err == nil || errors.Cause(err).Error() != tt.wantErr.Error() {
+					t.Errorf("directMessageService.GetOrCreateConversation() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("directMessageService.GetOrCreateConversation() error = %v", err)
+			}
+			if got.ThreadID != model.ThreadValidIDForTest {
+				testutil.Errorf(t, model.ThreadValidIDForTest, got.ThreadID)
+			}
+		})
+	}
+}
+
+func Test_directMessageService_PostMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testutil.SetFakeTime(time.Now())
+	defer testutil.ResetFakeTime()
+
+	ctx := context.Background()
+	var strangerID uint32 = 3
+
+	tests := []struct {
+		name     string
+		userID   uint32
+		blocks   []*model.UserBlock
+		wantPost bool
+		wantErr  error
+	}{
+		{
+			name:     "When neither blocks the other, posts the message and updates last activity",
+			userID:   model.UserValidIDForTest,
+			blocks:   []*model.UserBlock{},
+			wantPost: true,
+		},
+		{
+			name:    "When the user blocks the other, returns ForbiddenError",
+			userID:  model.UserValidIDForTest,
+			blocks:  []*model.UserBlock{{UserID: model.UserValidIDForTest, BlockedUserID: model.UserInValidIDForTest}},
+			wantErr: &model.ForbiddenError{InvalidReasonForDeveloper: "can not send direct messages between users who block"},
+		},
+		{
+			name:   "When the user is not in the conversation, returns NoSuchDataError to hide it",
+			userID: strangerID,
+			wantErr: &model.NoSuchDataError{
+				PropertyNameForDeveloper:    model.IDPropertyForDeveloper,
+				PropertyNameForUser:         model.IDPropertyForUser,
+				PropertyValue:               model.ThreadValidIDForTest,
+				DomainModelNameForDeveloper: model.DomainModelNameDirectConversationForDeveloper,
+				DomainModelNameForUser:      model.DomainModelNameDirectConversationForUser,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock_repository.NewMockDBManager(ctrl)
+			tx := mock_repository.NewMockTxManager(ctrl)
+			cr := mock_repository.NewMockCommentRepository(ctrl)
+			dcr := mock_repository.NewMockDirectConversationRepository(ctrl)
+			ubr := mock_repository.NewMockUserBlockRepository(ctrl)
+			ser := mock_repository.NewMockSearchRepository(ctrl)
+
+			dcr.EXPECT().GetDirectConversationByThreadID(m, model.ThreadValidIDForTest).Return(&model.DirectConversation{
+				ThreadID: model.ThreadValidIDForTest,
+				UserID1:  model.UserValidIDForTest,
+				UserID2:  model.UserInValidIDForTest,
+			}, nil)
+			if tt.blocks != nil {
+				ubr.EXPECT().GetUserBlocksBetween(m, tt.userID, model.UserInValidIDForTest).Return(tt.blocks, nil)
+			}
+			if tt.wantPost {
+				m.EXPECT().Begin().Return(tx, nil)
+				gomock.InOrder(
+					cr.EXPECT().InsertComment(tx, &model.Comment{
+						ThreadID:  model.ThreadValidIDForTest,
+						UserID:    tt.userID,
+						Content:   model.CommentContentForTest,
+						CreatedAt: testutil.TimeNow(),
+						UpdatedAt: testutil.TimeNow(),
+					}).Return(model.CommentValidIDForTest, nil),
+					dcr.EXPECT().UpdateLastMessageAt(tx, model.ThreadValidIDForTest, testutil.TimeNow()).Return(nil),
+					ser.EXPECT().IndexComment(tx, gomock.Any()).Return(nil),
+				)
+			}
+
+			s := &directMessageService{
+				m:                            m,
+				commentRepository:            cr,
+				directConversationRepository: dcr,
+				userBlockRepository:          ubr,
+				searchRepository:             ser,
+				txCloser:                     mock_application.MockCloseTransaction,
+				now:                          testutil.TimeNow,
+			}
+
+			got, err := s.PostMessage(ctx, tt.userID, model.ThreadValidIDForTest, model.CommentContentForTest)
+			if tt.wantErr != nil {
+				if err == nil || errors.Cause(err).Error() != tt.wantErr.Error() {
+					t.Errorf("directMessageService.PostMessage() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("directMessageService.PostMessage() error = %v", err)
+			}
+			if got.ID != model.CommentValidIDForTest {
+				testutil.Errorf(t, model.CommentValidIDForTest, got.ID)
+			}
+		})
+	}
+}
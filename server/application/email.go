@@ -0,0 +1,436 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/service"
+	"github.com/hideUW/nuxt-go-chat-app/server/util"
+)
+
+// Lifetime of tokens sent by email.
+// Reset is short-lived because the token is as strong as the password.
+const (
+	EmailVerificationTTL = 24 * time.Hour
+	PasswordResetTTL     = time.Hour
+)
+
+// Paths of the client which the links in mails open with the token.
+const (
+	verifyEmailPath   = "/verify_email"
+	resetPasswordPath = "/reset_password"
+)
+
+// Mails sent to users.
+const (
+	verifyEmailSubject = "メールアドレスの確認"
+	verifyEmailBody    = `%s さん
+
+以下のURLを開いて、メールアドレスの確認を完了してください。
+%s
+
+このURLの有効期限は%d時間です。
+心当たりがない場合は、このメールを破棄してください。
+`
+	resetPasswordSubject = "パスワードの再設定"
+	resetPasswordBody    = `%s さん
+
+以下のURLを開いて、新しいパスワードを設定してください。
+%s
+
+このURLの有効期限は%d分です。
+パスワードを再設定すると、すべての端末からログアウトします。
+心当たりがない場合は、このメールを破棄してください。
+`
+)
+
+// EmailService is the interface of EmailService.
+// This verifies email of users and resets password by the verified email.
+type EmailService interface {
+	ChangeEmail(ctx context.Context, userID uint32, email string) (*model.User, error)
+	VerifyEmail(ctx context.Context, token string) (*model.User, error)
+	RequestPasswordReset(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, newPassword string) error
+}
+
+// EmailServiceDIInput is DI input of EmailService.
+type EmailServiceDIInput struct {
+	userRepository         repository.UserRepository
+	sessionRepository      repository.SessionRepository
+	refreshTokenRepository repository.RefreshTokenRepository
+	userTokenRepository    repository.UserTokenRepository
+	throttleService        service.ThrottleService
+	mailer                 service.Mailer
+	baseURL                string
+}
+
+// NewEmailServiceDIInput generates and returns EmailServiceDIInput.
+// baseURL is the URL of the client which links in mails are based on.
+func NewEmailServiceDIInput(uRepo repository.UserRepository, sRepo repository.SessionRepository, rtRepo repository.RefreshTokenRepository, utRepo repository.UserTokenRepository, tService service.ThrottleService, mailer service.Mailer, baseURL string) *EmailServiceDIInput {
+	return &EmailServiceDIInput{
+		userRepository:         uRepo,
+		sessionRepository:      sRepo,
+		refreshTokenRepository: rtRepo,
+		userTokenRepository:    utRepo,
+		throttleService:        tService,
+		mailer:                 mailer,
+		baseURL:                baseURL,
+	}
+}
+
+// emailService is the service of email of users.
+type emailService struct {
+	m                      repository.DBManager
+	userRepository         repository.UserRepository
+	sessionRepository      repository.SessionRepository
+	refreshTokenRepository repository.RefreshTokenRepository
+	userTokenRepository    repository.UserTokenRepository
+	throttleService        service.ThrottleService
+	mailer                 service.Mailer
+	baseURL                string
+	txCloser               CloseTransaction
+	now                    func() time.Time
+}
+
+// NewEmailService generates and returns EmailService.
+func NewEmailService(m repository.DBManager, diInput EmailServiceDIInput, txCloser CloseTransaction) EmailService {
+	return &emailService{
+		m:                      m,
+		userRepository:         diInput.userRepository,
+		sessionRepository:      diInput.sessionRepository,
+		refreshTokenRepository: diInput.refreshTokenRepository,
+		userTokenRepository:    diInput.userTokenRepository,
+		throttleService:        diInput.throttleService,
+		mailer:                 diInput.mailer,
+		baseURL:                diInput.baseURL,
+		txCloser:               txCloser,
+		now:                    time.Now,
+	}
+}
+
+// ChangeEmail sets the email of the user as unverified and sends the mail to verify it.
+// Setting the same email again resends the mail, and tokens sent before are invalidated.
+func (s *emailService) ChangeEmail(ctx context.Context, userID uint32, email string) (*model.User, error) {
+	email = model.NormalizeEmail(email)
+	if err := model.ValidateEmail(email); err != nil {
+		return nil, errors.Wrap(err, "failed to validate email")
+	}
+
+	user, err := s.userRepository.GetUserByID(s.m, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get user by id")
+	}
+
+	if user.Email == email && user.EmailVerified {
+		return user, nil
+	}
+
+	// not allow the email of another user.
+	other, err := s.userRepository.GetUserByEmail(s.m, email)
+	if err == nil && other.ID != user.ID {
+		return nil, errors.WithStack(&model.AlreadyExistError{
+			PropertyNameForDeveloper:    model.EmailPropertyForDeveloper,
+			PropertyNameForUser:         model.EmailPropertyForUser,
+			PropertyValue:               email,
+			DomainModelNameForDeveloper: model.DomainModelNameUserForDeveloper,
+			DomainModelNameForUser:      model.DomainModelNameUserForUser,
+		})
+	}
+	if err != nil {
+		if _, ok := errors.Cause(err).(*model.NoSuchDataError); !ok {
+			return nil, errors.Wrap(err, "failed to get user by email")
+		}
+	}
+
+	token, err := s.newUserToken(user.ID, model.UserTokenPurposeVerifyEmail, email, EmailVerificationTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	user.Email = email
+	user.EmailVerified = false
+	user.UpdatedAt = s.now()
+	if err := s.saveEmail(user, token); err != nil {
+		return nil, err
+	}
+
+	// the mail is sent after commit, so that the token in the mail surely exists.
+	body := fmt.Sprintf(verifyEmailBody, user.Name, s.link(verifyEmailPath, token.Token), int(EmailVerificationTTL.Hours()))
+	if err := s.mailer.Send(ctx, &model.Mail{To: email, Subject: verifyEmailSubject, Body: body}); err != nil {
+		return nil, errors.Wrap(err, "failed to send mail to verify email")
+	}
+
+	return user, nil
+}
+
+// saveEmail updates the email of the user and replaces the tokens to verify it.
+func (s *emailService) saveEmail(user *model.User, token *model.UserToken) (err error) {
+	tx, err := s.m.Begin()
+	if err != nil {
+		return beginTxErrorMsg(err)
+	}
+
+	defer func() {
+		if cErr := s.txCloser(tx, err); cErr != nil {
+			err = errors.Wrap(cErr, "failed to close tx")
+		}
+	}()
+
+	if err := s.userRepository.UpdateUser(tx, user.ID, user); err != nil {
+		return errors.Wrap(err, "failed to update user")
+	}
+
+	if err := s.userTokenRepository.DeleteUserTokensByPurpose(tx, user.ID, model.UserTokenPurposeVerifyEmail); err != nil {
+		return errors.Wrap(err, "failed to delete tokens to verify email")
+	}
+
+	if err := s.userTokenRepository.InsertUserToken(tx, token); err != nil {
+		return errors.Wrap(err, "failed to insert token to verify email")
+	}
+
+	return nil
+}
+
+// VerifyEmail marks the email of the user as verified by the token sent to it.
+// This returns AuthenticationErr if the token is unknown, expired or already used, or the email has been changed.
+func (s *emailService) VerifyEmail(ctx context.Context, token string) (user *model.User, err error) {
+	tx, err := s.m.Begin()
+	if err != nil {
+		return nil, beginTxErrorMsg(err)
+	}
+
+	defer func() {
+		if cErr := s.txCloser(tx, err); cErr != nil {
+			err = errors.Wrap(cErr, "failed to close tx")
+		}
+	}()
+
+	user, _, err = s.useUserToken(tx, token, model.UserTokenPurposeVerifyEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	user.EmailVerified = true
+	user.UpdatedAt = s.now()
+	if err := s.userRepository.UpdateUser(tx, user.ID, user); err != nil {
+		return nil, errors.Wrap(err, "failed to update user")
+	}
+
+	return user, nil
+}
+
+// RequestPasswordReset sends the mail to reset password to the verified email.
+// This returns nil even if no user has the email or the mail is throttled,
+// so that the response does not tell whether the email is registered.
+func (s *emailService) RequestPasswordReset(ctx context.Context, email string) error {
+	email = model.NormalizeEmail(email)
+	if err := model.ValidateEmail(email); err != nil {
+		return errors.Wrap(err, "failed to validate email")
+	}
+
+	// every request is counted, so that mails are not sent to an address one after another.
+	key := model.NewThrottleKey(model.ThrottleKindPasswordResetEmail, email)
+	if _, err := s.throttleService.Reserve(ctx, key); err != nil {
+		if _, ok := errors.Cause(err).(*model.TooManyRequestsError); ok {
+			return nil
+		}
+		return errors.Wrap(err, "failed to pass throttle")
+	}
+
+	user, err := s.userRepository.GetUserByEmail(s.m, email)
+	if err != nil {
+		if _, ok := errors.Cause(err).(*model.NoSuchDataError); ok {
+			return nil
+		}
+		return errors.Wrap(err, "failed to get user by email")
+	}
+
+	// password is not reset by unverified email, which may be a typo of an address of someone else.
+	// disabled user is enabled only by operators, so that the reset does not help.
+	if !user.EmailVerified || user.Disabled {
+		return nil
+	}
+
+	token, err := s.newUserToken(user.ID, model.UserTokenPurposeResetPassword, email, PasswordResetTTL)
+	if err != nil {
+		return err
+	}
+
+	if err := s.saveResetToken(token); err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf(resetPasswordBody, user.Name, s.link(resetPasswordPath, token.Token), int(PasswordResetTTL.Minutes()))
+	if err := s.mailer.Send(ctx, &model.Mail{To: email, Subject: resetPasswordSubject, Body: body}); err != nil {
+		return errors.Wrap(err, "failed to send mail to reset password")
+	}
+
+	return nil
+}
+
+// saveResetToken replaces the tokens to reset password of the user, so that only the latest mail is valid.
+func (s *emailService) saveResetToken(token *model.UserToken) (err error) {
+	tx, err := s.m.Begin()
+	if err != nil {
+		return beginTxErrorMsg(err)
+	}
+
+	defer func() {
+		if cErr := s.txCloser(tx, err); cErr != nil {
+			err = errors.Wrap(cErr, "failed to close tx")
+		}
+	}()
+
+	if err := s.userTokenRepository.DeleteUserTokensByPurpose(tx, token.UserID, model.UserTokenPurposeResetPassword); err != nil {
+		return errors.Wrap(err, "failed to delete tokens to reset password")
+	}
+
+	if err := s.userTokenRepository.InsertUserToken(tx, token); err != nil {
+		return errors.Wrap(err, "failed to insert token to reset password")
+	}
+
+	return nil
+}
+
+// ResetPassword changes the password of the user by the token sent to the email.
+// All sessions and refresh tokens of the user are revoked, so that the one who knows the old password is logged out.
+// This returns AuthenticationErr if the token is unknown, expired or already used, or the email has been changed.
+func (s *emailService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	if err := model.ValidatePassword(newPassword); err != nil {
+		return errors.Wrap(err, "failed to validate new password")
+	}
+
+	hashed, err := util.HashPassword(newPassword)
+	if err != nil {
+		return errors.Wrap(err, "failed to hash password")
+	}
+
+	user, err := s.resetPassword(token, hashed)
+	if err != nil {
+		return err
+	}
+
+	// the owner who has just proved the email should not be locked out by failures of someone else.
+	if err := s.throttleService.Reset(ctx, model.NewThrottleKey(model.ThrottleKindLoginName, user.Name)); err != nil {
+		return errors.Wrap(err, "failed to reset throttle")
+	}
+
+	return nil
+}
+
+// resetPassword uses the token and replaces the password of its user with hashed one.
+func (s *emailService) resetPassword(token, hashed string) (user *model.User, err error) {
+	tx, err := s.m.Begin()
+	if err != nil {
+		return nil, beginTxErrorMsg(err)
+	}
+
+	defer func() {
+		if cErr := s.txCloser(tx, err); cErr != nil {
+			err = errors.Wrap(cErr, "failed to close tx")
+		}
+	}()
+
+	user, _, err = s.useUserToken(tx, token, model.UserTokenPurposeResetPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	// the user has proved to receive mails at the email, even if it had not been verified.
+	user.Password = hashed
+	user.EmailVerified = true
+	user.UpdatedAt = s.now()
+	if err := s.userRepository.UpdateUser(tx, user.ID, user); err != nil {
+		return nil, errors.Wrap(err, "failed to update user")
+	}
+
+	if err := s.sessionRepository.DeleteSessionsByUserID(tx, user.ID); err != nil {
+		return nil, errors.Wrap(err, "failed to delete sessions")
+	}
+
+	if err := s.refreshTokenRepository.DeleteRefreshTokensByUserID(tx, user.ID); err != nil {
+		return nil, errors.Wrap(err, "failed to delete refresh tokens")
+	}
+
+	if err := s.userTokenRepository.DeleteUserTokensByPurpose(tx, user.ID, model.UserTokenPurposeResetPassword); err != nil {
+		return nil, errors.Wrap(err, "failed to delete tokens to reset password")
+	}
+
+	return user, nil
+}
+
+// useUserToken deletes the token for the purpose and returns its user.
+// The token is deleted before it is checked, so that it can be used only once even by requests at the same time.
+func (s *emailService) useUserToken(m repository.SQLManager, token string, purpose model.UserTokenPurpose) (*model.User, *model.UserToken, error) {
+	id := model.UserTokenIDFromToken(token)
+	userToken, err := s.userTokenRepository.GetUserToken(m, id)
+	if err != nil {
+		if _, ok := errors.Cause(err).(*model.NoSuchDataError); ok {
+			return nil, nil, errors.WithStack(&model.AuthenticationErr{BaseErr: err})
+		}
+		return nil, nil, errors.Wrap(err, "failed to get user token")
+	}
+
+	if userToken.Purpose != purpose {
+		return nil, nil, errors.WithStack(&model.AuthenticationErr{})
+	}
+
+	if err := s.userTokenRepository.DeleteUserToken(m, id); err != nil {
+		if _, ok := errors.Cause(err).(*model.NoSuchDataError); ok {
+			return nil, nil, errors.WithStack(&model.AuthenticationErr{BaseErr: err})
+		}
+		return nil, nil, errors.Wrap(err, "failed to delete user token")
+	}
+
+	if userToken.IsExpired(s.now()) {
+		return nil, nil, errors.WithStack(&model.AuthenticationErr{})
+	}
+
+	user, err := s.userRepository.GetUserByID(m, userToken.UserID)
+	if err != nil {
+		if _, ok := errors.Cause(err).(*model.NoSuchDataError); ok {
+			return nil, nil, errors.WithStack(&model.AuthenticationErr{BaseErr: err})
+		}
+		return nil, nil, errors.Wrap(err, "failed to get user by id")
+	}
+
+	if user.Email != userToken.Email {
+		return nil, nil, errors.WithStack(&model.AuthenticationErr{})
+	}
+
+	return user, userToken, nil
+}
+
+// newUserToken generates the token for the purpose, which is sent to the email.
+func (s *emailService) newUserToken(userID uint32, purpose model.UserTokenPurpose, email string, ttl time.Duration) (*model.UserToken, error) {
+	token, err := util.RandomToken(model.UserTokenSize)
+	if err != nil {
+		return nil, errors.WithStack(&model.OtherServerError{
+			BaseErr:                   err,
+			InvalidReasonForDeveloper: "failed to generate user token",
+		})
+	}
+
+	now := s.now()
+	return &model.UserToken{
+		ID:        model.UserTokenIDFromToken(token),
+		Token:     token,
+		UserID:    userID,
+		Purpose:   purpose,
+		Email:     email,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}, nil
+}
+
+// link returns the URL of the client which has the token.
+// The token is in fragment, so that it is not sent to servers or logged as referer.
+func (s *emailService) link(path, token string) string {
+	return s.baseURL + path + "#token=" + url.QueryEscape(token)
+}
@@ -0,0 +1,451 @@
+package application
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+
+	mock_application "github.com/hideUW/nuxt-go-chat-app/server/application/mock"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	mock_repository "github.com/hideUW/nuxt-go-chat-app/server/domain/repository/mock"
+	mock_service "github.com/hideUW/nuxt-go-chat-app/server/domain/service/mock"
+	"github.com/hideUW/nuxt-go-chat-app/server/infra/mail"
+	"github.com/hideUW/nuxt-go-chat-app/server/testutil"
+)
+
+const baseURLForTest = "http://localhost:8080"
+
+var tokenInMail = regexp.MustCompile(`#token=(\S+)`)
+
+// tokenFromMail returns the token in the link of the mail.
+func tokenFromMail(t *testing.T, m *model.Mail) string {
+	t.Helper()
+
+	found := tokenInMail.FindStringSubmatch(m.Body)
+	if found == nil {
+		t.Fatalf("no token in mail: %s", m.Body)
+	}
+	token, err := url.QueryUnescape(found[1])
+	if err != nil {
+		t.Fatalf("failed to unescape token: %v", err)
+	}
+	return token
+}
+
+func Test_emailService_ChangeEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testutil.SetFakeTime(time.Now())
+	defer testutil.ResetFakeTime()
+
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		email     string
+		otherUser *model.User
+		wantErr   error
+	}{
+		{
+			name:  "When the email is not used, sets it as unverified and sends the token to it",
+			email: " Test@Example.com ",
+		},
+		{
+			name:      "When the email is used by another user, returns AlreadyExistError",
+			email:     model.EmailForTest,
+			otherUser: &model.User{ID: model.UserInValidIDForTest, Email: model.EmailForTest},
+			wantErr: &model.AlreadyExistError{
+				PropertyNameForDeveloper:    model.EmailPropertyForDeveloper,
+				PropertyNameForUser:         model.EmailPropertyForUser,
+				PropertyValue:               model.EmailForTest,
+				DomainModelNameForDeveloper: model.DomainModelNameUserForDeveloper,
+				DomainModelNameForUser:      model.DomainModelNameUserForUser,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock_repository.NewMockDBManager(ctrl)
+			tx := mock_repository.NewMockTxManager(ctrl)
+			ur := mock_repository.NewMockUserRepository(ctrl)
+			utr := mock_repository.NewMockUserTokenRepository(ctrl)
+			mailer := mail.NewMemoryMailer()
+
+			ur.EXPECT().GetUserByID(m, model.UserValidIDForTest).Return(&model.User{
+				ID:   model.UserValidIDForTest,
+				Name: model.UserNameForTest,
+			}, nil)
+			if tt.otherUser != nil {
+				ur.EXPECT().GetUserByEmail(m, model.EmailForTest).Return(tt.otherUser, nil)
+			} else {
+				ur.EXPECT().GetUserByEmail(m, model.EmailForTest).Return(nil, &model.NoSuchDataError{})
+			}
+
+			var inserted *model.UserToken
+			if tt.wantErr == nil {
+				m.EXPECT().Begin().Return(tx, nil)
+				gomock.InOrder(
+					ur.EXPECT().UpdateUser(tx, model.UserValidIDForTest, &model.User{
+						ID:        model.UserValidIDForTest,
+						Name:      model.UserNameForTest,
+						Email:     model.EmailForTest,
+						UpdatedAt: testutil.TimeNow(),
+					}).Return(nil),
+					utr.EXPECT().DeleteUserTokensByPurpose(tx, model.UserValidIDForTest, model.UserTokenPurposeVerifyEmail).Return(nil),
+					utr.EXPECT().InsertUserToken(tx, gomock.Any()).DoAndReturn(func(_ interface{}, ut *model.UserToken) error {
+						inserted = ut
+						return nil
+					}),
+				)
+			}
+
+			s := &emailService{
+				m:                   m,
+				userRepository:      ur,
+				userTokenRepository: utr,
+				mailer:              mailer,
+				baseURL:             baseURLForTest,
+				txCloser:            mock_application.MockCloseTransaction,
+				now:                 testutil.TimeNow,
+			}
+
+			got, err := s.ChangeEmail(ctx, model.UserValidIDForTest, tt.email)
+			if tt.wantErr != nil {
+				if err == nil || errors.Cause(err).Error() != tt.wantErr.Error() {
+					t.Errorf("emailService.ChangeEmail() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if len(mailer.Mails()) != 0 {
+					t.Errorf("emailService.ChangeEmail() sent mails = %v, want none", mailer.Mails())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("emailService.ChangeEmail() error = %v", err)
+			}
+			if got.Email != model.EmailForTest || got.EmailVerified {
+				testutil.Errorf(t, model.EmailForTest, got)
+			}
+
+			mails := mailer.Mails()
+			if len(mails) != 1 || mails[0].To != model.EmailForTest {
+				t.Fatalf("emailService.ChangeEmail() sent mails = %v, want one to %s", mails, model.EmailForTest)
+			}
+			// only the hash of the token in the mail is stored.
+			token := tokenFromMail(t, mails[0])
+			if inserted.ID != model.UserTokenIDFromToken(token) || inserted.Email != model.EmailForTest ||
+				!inserted.ExpiresAt.Equal(testutil.TimeNow().Add(EmailVerificationTTL)) {
+				testutil.Errorf(t, model.UserTokenIDFromToken(token), inserted)
+			}
+		})
+	}
+}
+
+func Test_emailService_VerifyEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testutil.SetFakeTime(time.Now())
+	defer testutil.ResetFakeTime()
+
+	ctx := context.Background()
+	id := model.UserTokenIDFromToken(model.UserTokenForTest)
+
+	tests := []struct {
+		name       string
+		storedErr  error
+		userToken  *model.UserToken
+		deleteErr  error
+		storedUser *model.User
+		wantErr    error
+	}{
+		{
+			name: "When the token is valid, marks the email as verified",
+			userToken: &model.UserToken{
+				ID: id, UserID: model.UserValidIDForTest, Purpose: model.UserTokenPurposeVerifyEmail,
+				Email: model.EmailForTest, ExpiresAt: testutil.TimeNow().Add(time.Hour),
+			},
+			storedUser: &model.User{ID: model.UserValidIDForTest, Email: model.EmailForTest},
+		},
+		{
+			name:      "When the token is unknown, returns AuthenticationErr",
+			storedErr: &model.NoSuchDataError{},
+			wantErr:   &model.AuthenticationErr{},
+		},
+		{
+			name: "When the token is for another purpose, returns AuthenticationErr",
+			userToken: &model.UserToken{
+				ID: id, UserID: model.UserValidIDForTest, Purpose: model.UserTokenPurposeResetPassword,
+				Email: model.EmailForTest, ExpiresAt: testutil.TimeNow().Add(time.Hour),
+			},
+			wantErr: &model.AuthenticationErr{},
+		},
+		{
+			name: "When the token has been used at the same time, returns AuthenticationErr",
+			userToken: &model.UserToken{
+				ID: id, UserID: model.UserValidIDForTest, Purpose: model.UserTokenPurposeVerifyEmail,
+				Email: model.EmailForTest, ExpiresAt: testutil.TimeNow().Add(time.Hour),
+			},
+			deleteErr: &model.NoSuchDataError{},
+			wantErr:   &model.AuthenticationErr{},
+		},
+		{
+			name: "When the token is expired, returns AuthenticationErr",
+			userToken: &model.UserToken{
+				ID: id, UserID: model.UserValidIDForTest, Purpose: model.UserTokenPurposeVerifyEmail,
+				Email: model.EmailForTest, ExpiresAt: testutil.TimeNow(),
+			},
+			wantErr: &model.AuthenticationErr{},
+		},
+		{
+			name: "When the email has been changed after sent, returns AuthenticationErr",
+			userToken: &model.UserToken{
+				ID: id, UserID: model.UserValidIDForTest, Purpose: model.UserTokenPurposeVerifyEmail,
+				Email: model.EmailForTest, ExpiresAt: testutil.TimeNow().Add(time.Hour),
+			},
+			storedUser: &model.User{ID: model.UserValidIDForTest, Email: "other@example.com"},
+			wantErr:    &model.AuthenticationErr{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock_repository.NewMockDBManager(ctrl)
+			tx := mock_repository.NewMockTxManager(ctrl)
+			ur := mock_repository.NewMockUserRepository(ctrl)
+			utr := mock_repository.NewMockUserTokenRepository(ctrl)
+
+			m.EXPECT().Begin().Return(tx, nil)
+			utr.EXPECT().GetUserToken(tx, id).Return(tt.userToken, tt.storedErr)
+			if tt.userToken != nil && tt.userToken.Purpose == model.UserTokenPurposeVerifyEmail {
+				utr.EXPECT().DeleteUserToken(tx, id).Return(tt.deleteErr)
+			}
+			if tt.storedUser != nil {
+				ur.EXPECT().GetUserByID(tx, model.UserValidIDForTest).Return(tt.storedUser, nil)
+			}
+			if tt.wantErr == nil {
+				ur.EXPECT().UpdateUser(tx, model.UserValidIDForTest, &model.User{
+					ID:            model.UserValidIDForTest,
+					Email:         model.EmailForTest,
+					EmailVerified: true,
+					UpdatedAt:     testutil.TimeNow(),
+				}).Return(nil)
+			}
+
+			s := &emailService{
+				m:                   m,
+				userRepository:      ur,
+				userTokenRepository: utr,
+				txCloser:            mock_application.MockCloseTransaction,
+				now:                 testutil.TimeNow,
+			}
+
+			got, err := s.VerifyEmail(ctx, model.UserTokenForTest)
+			if tt.wantErr != nil {
+				if err == nil || errors.Cause(err).Error() != tt.wantErr.Error() {
+					t.Errorf("emailService.VerifyEmail() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("emailService.VerifyEmail() error = %v", err)
+			}
+			if !got.EmailVerified {
+				testutil.Errorf(t, true, got.EmailVerified)
+			}
+		})
+	}
+}
+
+func Test_emailService_RequestPasswordReset(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testutil.SetFakeTime(time.Now())
+	defer testutil.ResetFakeTime()
+
+	ctx := context.Background()
+	key := model.NewThrottleKey(model.ThrottleKindPasswordResetEmail, model.EmailForTest)
+
+	tests := []struct {
+		name        string
+		throttleErr error
+		storedUser  *model.User
+		storedErr   error
+		wantMail    bool
+	}{
+		{
+			name:       "When the email is verified, sends the token to it",
+			storedUser: &model.User{ID: model.UserValidIDForTest, Name: model.UserNameForTest, Email: model.EmailForTest, EmailVerified: true},
+			wantMail:   true,
+		},
+		{
+			name:       "When the email is not verified, sends nothing without error",
+			storedUser: &model.User{ID: model.UserValidIDForTest, Name: model.UserNameForTest, Email: model.EmailForTest},
+		},
+		{
+			name:      "When no user has the email, sends nothing without error",
+			storedErr: &model.NoSuchDataError{},
+		},
+		{
+			name:        "When requests to the email are throttled, sends nothing without error",
+			throttleErr: &model.TooManyRequestsError{RetryAfter: time.Minute},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock_repository.NewMockDBManager(ctrl)
+			tx := mock_repository.NewMockTxManager(ctrl)
+			ur := mock_repository.NewMockUserRepository(ctrl)
+			utr := mock_repository.NewMockUserTokenRepository(ctrl)
+			th := mock_service.NewMockThrottleService(ctrl)
+			mailer := mail.NewMemoryMailer()
+
+			if tt.throttleErr != nil {
+				th.EXPECT().Reserve(ctx, key).Return(nil, tt.throttleErr)
+			} else {
+				th.EXPECT().Reserve(ctx, key).Return(&model.ThrottleReservation{Keys: []model.ThrottleKey{key}}, nil)
+				ur.EXPECT().GetUserByEmail(m, model.EmailForTest).Return(tt.storedUser, tt.storedErr)
+			}
+
+			var inserted *model.UserToken
+			if tt.wantMail {
+				m.EXPECT().Begin().Return(tx, nil)
+				gomock.InOrder(
+					utr.EXPECT().DeleteUserTokensByPurpose(tx, model.UserValidIDForTest, model.UserTokenPurposeResetPassword).Return(nil),
+					utr.EXPECT().InsertUserToken(tx, gomock.Any()).DoAndReturn(func(_ interface{}, ut *model.UserToken) error {
+						inserted = ut
+						return nil
+					}),
+				)
+			}
+
+			s := &emailService{
+				m:                   m,
+				userRepository:      ur,
+				userTokenRepository: utr,
+				throttleService:     th,
+				mailer:              mailer,
+				baseURL:             baseURLForTest,
+				txCloser:            mock_application.MockCloseTransaction,
+				now:                 testutil.TimeNow,
+			}
+
+			if err := s.RequestPasswordReset(ctx, model.EmailForTest); err != nil {
+				t.Fatalf("emailService.RequestPasswordReset() error = %v", err)
+			}
+
+			mails := mailer.Mails()
+			if !tt.wantMail {
+				if len(mails) != 0 {
+					t.Errorf("emailService.RequestPasswordReset() sent mails = %v, want none", mails)
+				}
+				return
+			}
+			if len(mails) != 1 || mails[0].To != model.EmailForTest {
+				t.Fatalf("emailService.RequestPasswordReset() sent mails = %v, want one to %s", mails, model.EmailForTest)
+			}
+			token := tokenFromMail(t, mails[0])
+			if inserted.ID != model.UserTokenIDFromToken(token) || inserted.Purpose != model.UserTokenPurposeResetPassword ||
+				!inserted.ExpiresAt.Equal(testutil.TimeNow().Add(PasswordResetTTL)) {
+				testutil.Errorf(t, model.UserTokenIDFromToken(token), inserted)
+			}
+		})
+	}
+}
+
+func Test_emailService_ResetPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testutil.SetFakeTime(time.Now())
+	defer testutil.ResetFakeTime()
+
+	ctx := context.Background()
+	id := model.UserTokenIDFromToken(model.UserTokenForTest)
+	nameKey := model.NewThrottleKey(model.ThrottleKindLoginName, model.UserNameForTest)
+
+	tests := []struct {
+		name        string
+		newPassword string
+		storedErr   error
+		wantErr     error
+	}{
+		{
+			name:        "When the token is valid, changes password and revokes all sessions",
+			newPassword: model.PasswordForTest,
+		},
+		{
+			name:        "When the token is unknown, returns AuthenticationErr",
+			newPassword: model.PasswordForTest,
+			storedErr:   &model.NoSuchDataError{},
+			wantErr:     &model.AuthenticationErr{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock_repository.NewMockDBManager(ctrl)
+			tx := mock_repository.NewMockTxManager(ctrl)
+			ur := mock_repository.NewMockUserRepository(ctrl)
+			sr := mock_repository.NewMockSessionRepository(ctrl)
+			rtr := mock_repository.NewMockRefreshTokenRepository(ctrl)
+			utr := mock_repository.NewMockUserTokenRepository(ctrl)
+			th := mock_service.NewMockThrottleService(ctrl)
+
+			m.EXPECT().Begin().Return(tx, nil)
+			if tt.storedErr != nil {
+				utr.EXPECT().GetUserToken(tx, id).Return(nil, tt.storedErr)
+			} else {
+				utr.EXPECT().GetUserToken(tx, id).Return(&model.UserToken{
+					ID: id, UserID: model.UserValidIDForTest, Purpose: model.UserTokenPurposeResetPassword,
+					Email: model.EmailForTest, ExpiresAt: testutil.TimeNow().Add(time.Minute),
+				}, nil)
+				gomock.InOrder(
+					utr.EXPECT().DeleteUserToken(tx, id).Return(nil),
+					ur.EXPECT().GetUserByID(tx, model.UserValidIDForTest).Return(&model.User{
+						ID:       model.UserValidIDForTest,
+						Name:     model.UserNameForTest,
+						Password: "oldHashedPassword",
+						Email:    model.EmailForTest,
+					}, nil),
+					ur.EXPECT().UpdateUser(tx, model.UserValidIDForTest, gomock.Any()).DoAndReturn(func(_ interface{}, _ uint32, u *model.User) error {
+						if u.Password == "oldHashedPassword" || u.Password == tt.newPassword || !u.EmailVerified {
+							t.Errorf("emailService.ResetPassword() updated user = %+v", u)
+						}
+						return nil
+					}),
+					sr.EXPECT().DeleteSessionsByUserID(tx, model.UserValidIDForTest).Return(nil),
+					rtr.EXPECT().DeleteRefreshTokensByUserID(tx, model.UserValidIDForTest).Return(nil),
+					utr.EXPECT().DeleteUserTokensByPurpose(tx, model.UserValidIDForTest, model.UserTokenPurposeResetPassword).Return(nil),
+					th.EXPECT().Reset(ctx, nameKey).Return(nil),
+				)
+			}
+
+			s := &emailService{
+				m:                      m,
+				userRepository:         ur,
+				sessionRepository:      sr,
+				refreshTokenRepository: rtr,
+				userTokenRepository:    utr,
+				throttleService:        th,
+				txCloser:               mock_application.MockCloseTransaction,
+				now:                    testutil.TimeNow,
+			}
+
+			err := s.ResetPassword(ctx, model.UserTokenForTest, tt.newPassword)
+			if tt.wantErr != nil {
+				if err == nil || errors.Cause(err).Error() != tt.wantErr.Error() {
+					t.Errorf("emailService.ResetPassword() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("emailService.ResetPassword() error = %v", err)
+			}
+		})
+	}
+}
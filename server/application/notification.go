@@ -0,0 +1,111 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+)
+
+// DefaultNotificationPageLimit and MaxNotificationPageLimit are the default and max number of notifications listed at once.
+const (
+	DefaultNotificationPageLimit = 20
+	MaxNotificationPageLimit     = 100
+)
+
+// NotificationService is the interface of NotificationService.
+type NotificationService interface {
+	ListNotifications(ctx context.Context, userID, beforeID uint32, limit int) (*model.NotificationPage, error)
+	MarkRead(ctx context.Context, userID, id uint32) (*model.Notification, error)
+	MarkAllRead(ctx context.Context, userID uint32) error
+}
+
+// notificationService is the service of the inbox of notifications of users.
+type notificationService struct {
+	m                      repository.DBManager
+	notificationRepository repository.NotificationRepository
+	now                    func() time.Time
+}
+
+// NewNotificationService generates and returns NotificationService.
+func NewNotificationService(m repository.DBManager, nRepo repository.NotificationRepository) NotificationService {
+	return &notificationService{
+		m:                      m,
+		notificationRepository: nRepo,
+		now:                    time.Now,
+	}
+}
+
+// ListNotifications returns at most limit notifications of the user older than beforeID in order of newest first,
+// with the number of all unread ones. The newest ones are returned if beforeID is 0, and the default limit is used if limit is 0.
+func (s *notificationService) ListNotifications(ctx context.Context, userID, beforeID uint32, limit int) (*model.NotificationPage, error) {
+	limit, err := pageLimit(limit, DefaultNotificationPageLimit, MaxNotificationPageLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	// one more notification is read to know whether the next page exists.
+	notifications, err := s.notificationRepository.ListNotificationsByUserID(s.m, userID, beforeID, limit+1)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list notifications by user id")
+	}
+
+	unread, err := s.notificationRepository.CountUnreadNotificationsByUserID(s.m, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to count unread notifications by user id")
+	}
+
+	page := &model.NotificationPage{
+		Notifications: notifications,
+		UnreadCount:   unread,
+	}
+	if len(notifications) > limit {
+		page.Notifications = notifications[:limit]
+		page.HasMore = true
+	}
+	return page, nil
+}
+
+// MarkRead marks the notification of the user specified by id as read and returns it.
+// Marking the read notification again does nothing.
+// This returns NoSuchDataError if the notification is not of the user,
+// so that the user can not tell whether notifications of other users exist.
+func (s *notificationService) MarkRead(ctx context.Context, userID, id uint32) (*model.Notification, error) {
+	notification, err := s.notificationRepository.GetNotificationByID(s.m, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get notification by id")
+	}
+
+	if notification.UserID != userID {
+		return nil, errors.WithStack(&model.NoSuchDataError{
+			PropertyNameForDeveloper:    model.IDPropertyForDeveloper,
+			PropertyNameForUser:         model.IDPropertyForUser,
+			PropertyValue:               id,
+			DomainModelNameForDeveloper: model.DomainModelNameNotificationForDeveloper,
+			DomainModelNameForUser:      model.DomainModelNameNotificationForUser,
+		})
+	}
+
+	if notification.IsRead() {
+		return notification, nil
+	}
+
+	now := s.now()
+	if err := s.notificationRepository.MarkNotificationRead(s.m, id, now); err != nil {
+		return nil, errors.Wrap(err, "failed to mark notification read")
+	}
+	notification.ReadAt = now
+
+	return notification, nil
+}
+
+// MarkAllRead marks all unread notifications of the user as read.
+func (s *notificationService) MarkAllRead(ctx context.Context, userID uint32) error {
+	if err := s.notificationRepository.MarkAllNotificationsRead(s.m, userID, s.now()); err != nil {
+		return errors.Wrap(err, "failed to mark all notifications read")
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package application
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	mock_repository "github.com/hideUW/nuxt-go-chat-app/server/domain/repository/mock"
+	"github.com/hideUW/nuxt-go-chat-app/server/testutil"
+)
+
+func Test_notificationService_ListNotifications(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	var beforeID uint32 = 30
+
+	tests := []struct {
+		name        string
+		limit       int
+		wantLimit   int
+		stored      []uint32
+		wantIDs     []uint32
+		wantHasMore bool
+		wantErr     error
+	}{
+		{
+			name:        "When more notifications than limit exist, returns the page and HasMore",
+			limit:       2,
+			wantLimit:   2,
+			stored:      []uint32{29, 28, 27},
+			wantIDs:     []uint32{29, 28},
+			wantHasMore: true,
+		},
+		{
+			name:      "When limit is 0, uses the default limit",
+			wantLimit: DefaultNotificationPageLimit,
+			stored:    []uint32{29},
+			wantIDs:   []uint32{29},
+		},
+		{
+			name:  "When limit is negative, returns InvalidParamError",
+			limit: -1,
+			wantErr: &model.InvalidParamError{
+				PropertyNameForDeveloper:  model.LimitPropertyForDeveloper,
+				PropertyNameForUser:       model.LimitPropertyForUser,
+				PropertyValue:             -1,
+				InvalidReasonForDeveloper: "limit should be between 1 and 100",
+				InvalidReasonForUser:      "件数は1から100までで指定してください",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock_repository.NewMockDBManager(ctrl)
+			nr := mock_repository.NewMockNotificationRepository(ctrl)
+
+			if tt.wantErr == nil {
+				stored := make([]*model.Notification, 0, len(tt.stored))
+				for _, id := range tt.stored {
+					stored = append(stored, &model.Notification{ID: id, UserID: model.UserValidIDForTest})
+				}
+
+				nr.EXPECT().ListNotificationsByUserID(m, model.UserValidIDForTest, beforeID, tt.wantLimit+1).Return(stored, nil)
+				nr.EXPECT().CountUnreadNotificationsByUserID(m, model.UserValidIDForTest).Return(uint32(5), nil)
+			}
+
+			s := &notificationService{
+				m:                      m,
+				notificationRepository: nr,
+				now:                    testutil.TimeNow,
+			}
+
+			got, err := s.ListNotifications(ctx, model.UserValidIDForTest, beforeID, tt.limit)
+			if tt.wantErr != nil {
+				if err == nil || errors.Cause(err).Error() != tt.wantErr.Error() {
+					t.Errorf("notificationService.ListNotifications() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("notificationService.ListNotifications() error = %v", err)
+			}
+
+			ids := make([]uint32, 0, len(got.Notifications))
+			for _, n := range got.Notifications {
+				ids = append(ids, n.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) || got.HasMore != tt.wantHasMore || got.UnreadCount != 5 {
+				testutil.Errorf(t, tt.wantIDs, ids)
+			}
+		})
+	}
+}
+
+func Test_notificationService_MarkRead(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testutil.SetFakeTime(time.Now())
+	defer testutil.ResetFakeTime()
+
+	ctx := context.Background()
+	var id uint32 = 10
+
+	tests := []struct {
+		name         string
+		notification *model.Notification
+		wantMark     bool
+		wantErr      error
+	}{
+		{
+			name:         "When the notification is unread, marks it read",
+			notification: &model.Notification{ID: id, UserID: model.UserValidIDForTest},
+			wantMark:     true,
+		},
+		{
+			name:         "When the notification is already read, returns it without update",
+			notification: &model.Notification{ID: id, UserID: model.UserValidIDForTest, ReadAt: testutil.TimeNow().Add(-time.Hour)},
+		},
+		{
+			name:         "When the notification is of another user, returns NoSuchDataError",
+			notification: &model.Notification{ID: id, UserID: model.UserInValidIDForTest},
+			wantErr: &model.NoSuchDataError{
+				PropertyNameForDeveloper:    model.IDPropertyForDeveloper,
+				PropertyNameForUser:         model.IDPropertyForUser,
+				PropertyValue:               id,
+				DomainModelNameForDeveloper: model.DomainModelNameNotificationForDeveloper,
+				DomainModelNameForUser:      model.DomainModelNameNotificationForUser,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock_repository.NewMockDBManager(ctrl)
+			nr := mock_repository.NewMockNotificationRepository(ctrl)
+
+			nr.EXPECT().GetNotificationByID(m, id).Return(tt.notification, nil)
+			if tt.wantMark {
+				nr.EXPECT().MarkNotificationRead(m, id, testutil.TimeNow()).Return(nil)
+			}
+
+			s := &notificationService{
+				m:                      m,
+				notificationRepository: nr,
+				now:                    testutil.TimeNow,
+			}
+
+			got, err := s.MarkRead(ctx, model.UserValidIDForTest, id)
+			if tt.wantErr != nil {
+				if err == nil || errors.Cause(err).Error() != tt.wantErr.Error() {
+					t.Errorf("notificationService.MarkRead() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("notificationService.MarkRead() error = %v", err)
+			}
+			if !got.IsRead() {
+				t.Errorf("notificationService.MarkRead() returns unread notification")
+			}
+		})
+	}
+}
@@ -0,0 +1,270 @@
+package application
+
+import (
+	"context"
+	"crypto/subtle"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/service"
+	"github.com/hideUW/nuxt-go-chat-app/server/util"
+)
+
+// OIDCLoginTTL is the time allowed to log in at the provider.
+const OIDCLoginTTL = 10 * time.Minute
+
+// maxNameCandidates is the number of names tried with numbers when the name of provisioned user is taken.
+const maxNameCandidates = 100
+
+// OIDCService is the interface of OIDCService.
+// This logs users in by OpenID Connect authorization code flow with PKCE.
+type OIDCService interface {
+	StartLogin(ctx context.Context) (*model.OIDCLogin, string, error)
+	FinishLogin(ctx context.Context, token, state, code string, client *model.Client) (*model.User, *model.Session, *model.PendingLogin, error)
+}
+
+// OIDCServiceDIInput is DI input of OIDCService.
+type OIDCServiceDIInput struct {
+	userRepository         repository.UserRepository
+	sessionRepository      repository.SessionRepository
+	identityRepository     repository.IdentityRepository
+	oidcLoginRepository    repository.OIDCLoginRepository
+	totpRepository         repository.TOTPRepository
+	pendingLoginRepository repository.PendingLoginRepository
+	userService            service.UserService
+	sessionService         service.SessionService
+	provider               service.OIDCProvider
+}
+
+// NewOIDCServiceDIInput generates and returns OIDCServiceDIInput.
+func NewOIDCServiceDIInput(uRepo repository.UserRepository, sRepo repository.SessionRepository, iRepo repository.IdentityRepository, olRepo repository.OIDCLoginRepository, totpRepo repository.TOTPRepository, plRepo repository.PendingLoginRepository, uService service.UserService, sService service.SessionService, provider service.OIDCProvider) *OIDCServiceDIInput {
+	return &OIDCServiceDIInput{
+		userRepository:         uRepo,
+		sessionRepository:      sRepo,
+		identityRepository:     iRepo,
+		oidcLoginRepository:    olRepo,
+		totpRepository:         totpRepo,
+		pendingLoginRepository: plRepo,
+		userService:            uService,
+		sessionService:         sService,
+		provider:               provider,
+	}
+}
+
+// oidcService is the service of login by OpenID Connect.
+type oidcService struct {
+	m                      repository.DBManager
+	userRepository         repository.UserRepository
+	sessionRepository      repository.SessionRepository
+	identityRepository     repository.IdentityRepository
+	oidcLoginRepository    repository.OIDCLoginRepository
+	totpRepository         repository.TOTPRepository
+	pendingLoginRepository repository.PendingLoginRepository
+	userService            service.UserService
+	sessionService         service.SessionService
+	provider               service.OIDCProvider
+	txCloser               CloseTransaction
+	now                    func() time.Time
+}
+
+// NewOIDCService generates and returns OIDCService.
+func NewOIDCService(m repository.DBManager, diInput OIDCServiceDIInput, txCloser CloseTransaction) OIDCService {
+	return &oidcService{
+		m:                      m,
+		userRepository:         diInput.userRepository,
+		sessionRepository:      diInput.sessionRepository,
+		identityRepository:     diInput.identityRepository,
+		oidcLoginRepository:    diInput.oidcLoginRepository,
+		totpRepository:         diInput.totpRepository,
+		pendingLoginRepository: diInput.pendingLoginRepository,
+		userService:            diInput.userService,
+		sessionService:         diInput.sessionService,
+		provider:               diInput.provider,
+		txCloser:               txCloser,
+		now:                    time.Now,
+	}
+}
+
+// StartLogin starts login and returns it with the URL of the provider.
+// The token of the returned login must be sent to the browser as cookie,
+// so that only the browser which started the login can finish it.
+func (s *oidcService) StartLogin(ctx context.Context) (*model.OIDCLogin, string, error) {
+	secrets := make([]string, 4)
+	for i := range secrets {
+		secret, err := util.RandomToken(model.OIDCLoginTokenSize)
+		if err != nil {
+			return nil, "", errors.WithStack(&model.OtherServerError{
+				BaseErr:                   err,
+				InvalidReasonForDeveloper: "failed to generate secrets of login",
+			})
+		}
+		secrets[i] = secret
+	}
+
+	login := &model.OIDCLogin{
+		ID:           model.OIDCLoginIDFromToken(secrets[0]),
+		Token:        secrets[0],
+		State:        secrets[1],
+		Nonce:        secrets[2],
+		CodeVerifier: secrets[3],
+		ExpiresAt:    s.now().Add(OIDCLoginTTL),
+	}
+	if err := s.oidcLoginRepository.InsertOIDCLogin(login); err != nil {
+		return nil, "", errors.Wrap(err, "failed to insert login")
+	}
+
+	return login, s.provider.AuthCodeURL(login.State, login.Nonce, login.CodeChallenge()), nil
+}
+
+// FinishLogin checks the callback from the provider and creates a new session of the client.
+// The user linked to the identity is logged in, and a new user is provisioned for unknown identity.
+// If TOTP of the user is enabled, this returns a pending login instead of session like password login,
+// so that the provider does not bypass the second factor.
+// This returns AuthenticationErr if the login is unknown, expired or already used, or state or nonce does not match.
+func (s *oidcService) FinishLogin(ctx context.Context, token, state, code string, client *model.Client) (user *model.User, session *model.Session, pending *model.PendingLogin, err error) {
+	login, err := s.oidcLoginRepository.TakeOIDCLogin(model.OIDCLoginIDFromToken(token))
+	if err != nil {
+		if _, ok := errors.Cause(err).(*model.NoSuchDataError); ok {
+			return nil, nil, nil, errors.WithStack(&model.AuthenticationErr{BaseErr: err})
+		}
+		return nil, nil, nil, errors.Wrap(err, "failed to take login")
+	}
+
+	if login.IsExpired(s.now()) || subtle.ConstantTimeCompare([]byte(state), []byte(login.State)) != 1 {
+		return nil, nil, nil, errors.WithStack(&model.AuthenticationErr{})
+	}
+
+	claims, err := s.provider.Exchange(ctx, code, login.CodeVerifier)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "failed to exchange code")
+	}
+
+	// nonce binds the ID token to the login, so that a token issued for another login is not replayed.
+	if subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(login.Nonce)) != 1 {
+		return nil, nil, nil, errors.WithStack(&model.AuthenticationErr{})
+	}
+
+	tx, err := s.m.Begin()
+	if err != nil {
+		return nil, nil, nil, beginTxErrorMsg(err)
+	}
+
+	defer func() {
+		if cErr := s.txCloser(tx, err); cErr != nil {
+			err = errors.Wrap(cErr, "failed to close tx")
+		}
+	}()
+
+	user, err = s.identityUser(ctx, tx, claims)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	totp, err := getEnabledTOTP(tx, s.totpRepository, user.ID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if totp != nil {
+		pending, err = createPendingLogin(s.pendingLoginRepository, user.ID, s.now())
+		if err != nil {
+			return nil, nil, nil, errors.Wrap(err, "failed to create pending login")
+		}
+		return user, nil, pending, nil
+	}
+
+	session, err = createSession(tx, s.sessionService, s.sessionRepository, user.ID, client)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "failed to create session")
+	}
+
+	return user, session, nil, nil
+}
+
+// identityUser returns the user linked to the identity of the claims.
+// If the identity is unknown, this provisions a new user and links it.
+// The provisioned user has a random password, so that the user logs in only by the provider.
+func (s *oidcService) identityUser(ctx context.Context, m repository.SQLManager, claims *model.OIDCClaims) (*model.User, error) {
+	identity, err := s.identityRepository.GetIdentity(m, claims.Issuer, claims.Subject)
+	if err == nil {
+		user, err := s.userRepository.GetUserByID(m, identity.UserID)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get user by id")
+		}
+		if user.Disabled {
+			return nil, errors.WithStack(&model.AuthenticationErr{})
+		}
+		return user, nil
+	}
+	if _, ok := errors.Cause(err).(*model.NoSuchDataError); !ok {
+		return nil, errors.Wrap(err, "failed to get identity")
+	}
+
+	name, err := s.uniqueName(m, claims.UserName())
+	if err != nil {
+		return nil, err
+	}
+
+	password, err := util.RandomToken(model.OIDCLoginTokenSize)
+	if err != nil {
+		return nil, errors.WithStack(&model.OtherServerError{
+			BaseErr:                   err,
+			InvalidReasonForDeveloper: "failed to generate password",
+		})
+	}
+
+	user, err := s.userService.NewUser(name, password)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to new user")
+	}
+
+	user.ID, err = s.userRepository.InsertUser(m, user)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to insert user")
+	}
+
+	identity = &model.Identity{
+		UserID:    user.ID,
+		Issuer:    claims.Issuer,
+		Subject:   claims.Subject,
+		CreatedAt: s.now(),
+	}
+	if _, err := s.identityRepository.InsertIdentity(m, identity); err != nil {
+		return nil, errors.Wrap(err, "failed to insert identity")
+	}
+
+	return user, nil
+}
+
+// uniqueName returns the name which is not taken, numbering it from 2 if the name is taken.
+// The name is truncated so that it fits in the column with the number.
+func (s *oidcService) uniqueName(m repository.SQLManager, base string) (string, error) {
+	for i := 1; i <= maxNameCandidates; i++ {
+		name := base
+		if i > 1 {
+			suffix := strconv.Itoa(i)
+			name = model.TruncateUserName(base, model.MaxUserNameLength-len(suffix)) + suffix
+		}
+
+		_, err := s.userRepository.GetUserByName(m, name)
+		if err == nil {
+			continue
+		}
+		if _, ok := errors.Cause(err).(*model.NoSuchDataError); ok {
+			return name, nil
+		}
+		return "", errors.Wrap(err, "failed to get user by name")
+	}
+
+	return "", errors.WithStack(&model.AlreadyExistError{
+		PropertyNameForDeveloper:    model.NamePropertyForDeveloper,
+		PropertyNameForUser:         model.NamePropertyForUser,
+		PropertyValue:               base,
+		DomainModelNameForDeveloper: model.DomainModelNameUserForDeveloper,
+		DomainModelNameForUser:      model.DomainModelNameUserForUser,
+	})
+}
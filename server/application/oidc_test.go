@@ -0,0 +1,178 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+
+	mock_application "github.com/hideUW/nuxt-go-chat-app/server/application/mock"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	mock_repository "github.com/hideUW/nuxt-go-chat-app/server/domain/repository/mock"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/service"
+	"github.com/hideUW/nuxt-go-chat-app/server/infra/memory"
+	"github.com/hideUW/nuxt-go-chat-app/server/infra/oidc"
+	"github.com/hideUW/nuxt-go-chat-app/server/testutil/fakeoidc"
+)
+
+// Test_oidcService_Login checks the flow from the start of login to the callback
+// against the in-process provider, with the real client of the provider.
+func Test_oidcService_Login(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := model.NewClient(model.ClientIPForTest, model.UserAgentForTest)
+	existing := &model.User{ID: model.UserValidIDForTest, Name: "testUser"}
+
+	tests := []struct {
+		name string
+		// setUp sets expectations of repositories and returns whether login succeeds.
+		setUp func(fake *fakeoidc.Provider, tx *mock_repository.MockTxManager, ur *mock_repository.MockUserRepository, ir *mock_repository.MockIdentityRepository) bool
+		// state overrides the state in the callback if not empty.
+		state string
+		// totpEnabled makes TOTP of the user enabled, so that a pending login is returned instead of session.
+		totpEnabled bool
+	}{
+		{
+			name: "When the identity is unknown and the name is taken, provisions the user with numbered name",
+			setUp: func(fake *fakeoidc.Provider, tx *mock_repository.MockTxManager, ur *mock_repository.MockUserRepository, ir *mock_repository.MockIdentityRepository) bool {
+				ir.EXPECT().GetIdentity(tx, fake.Issuer(), fake.User.Subject).Return(nil, &model.NoSuchDataError{})
+				ur.EXPECT().GetUserByName(tx, "testUser").Return(existing, nil)
+				ur.EXPECT().GetUserByName(tx, "testUser2").Return(nil, &model.NoSuchDataError{})
+				ur.EXPECT().InsertUser(tx, gomock.Any()).DoAndReturn(func(_ interface{}, u *model.User) (uint32, error) {
+					if u.Name != "testUser2" {
+						t.Errorf("InsertUser() is called with name %v, want testUser2", u.Name)
+					}
+					return model.UserInValidIDForTest, nil
+				})
+				ir.EXPECT().InsertIdentity(tx, gomock.Any()).DoAndReturn(func(_ interface{}, i *model.Identity) (uint32, error) {
+					if i.UserID != model.UserInValidIDForTest || i.Issuer != fake.Issuer() || i.Subject != fake.User.Subject {
+						t.Errorf("InsertIdentity() is called with %v", i)
+					}
+					return 1, nil
+				})
+				return true
+			},
+		},
+		{
+			name: "When the identity is known, logs in the linked user",
+			setUp: func(fake *fakeoidc.Provider, tx *mock_repository.MockTxManager, ur *mock_repository.MockUserRepository, ir *mock_repository.MockIdentityRepository) bool {
+				ir.EXPECT().GetIdentity(tx, fake.Issuer(), fake.User.Subject).Return(&model.Identity{UserID: existing.ID}, nil)
+				ur.EXPECT().GetUserByID(tx, existing.ID).Return(existing, nil)
+				return true
+			},
+		},
+		{
+			name:        "When TOTP of the linked user is enabled, returns pending login without session",
+			totpEnabled: true,
+			setUp: func(fake *fakeoidc.Provider, tx *mock_repository.MockTxManager, ur *mock_repository.MockUserRepository, ir *mock_repository.MockIdentityRepository) bool {
+				ir.EXPECT().GetIdentity(tx, fake.Issuer(), fake.User.Subject).Return(&model.Identity{UserID: existing.ID}, nil)
+				ur.EXPECT().GetUserByID(tx, existing.ID).Return(existing, nil)
+				return true
+			},
+		},
+		{
+			name:  "When the state does not match, returns AuthenticationErr",
+			state: "otherState",
+			setUp: func(*fakeoidc.Provider, *mock_repository.MockTxManager, *mock_repository.MockUserRepository, *mock_repository.MockIdentityRepository) bool {
+				return false
+			},
+		},
+		{
+			name: "When the nonce does not match, returns AuthenticationErr",
+			setUp: func(fake *fakeoidc.Provider, _ *mock_repository.MockTxManager, _ *mock_repository.MockUserRepository, _ *mock_repository.MockIdentityRepository) bool {
+				fake.Tamper = func(claims map[string]interface{}) { claims["nonce"] = "otherNonce" }
+				return false
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := fakeoidc.NewProvider(t)
+			defer fake.Close()
+
+			provider, err := oidc.NewProvider(context.Background(), oidc.Config{
+				Issuer:       fake.Issuer(),
+				ClientID:     fakeoidc.ClientID,
+				ClientSecret: fakeoidc.ClientSecret,
+				RedirectURL:  "http://localhost:8080/api/oidc/callback",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			m := mock_repository.NewMockDBManager(ctrl)
+			tx := mock_repository.NewMockTxManager(ctrl)
+			ur := mock_repository.NewMockUserRepository(ctrl)
+			sr := mock_repository.NewMockSessionRepository(ctrl)
+			ir := mock_repository.NewMockIdentityRepository(ctrl)
+			totpr := mock_repository.NewMockTOTPRepository(ctrl)
+			plr := memory.NewPendingLoginRepository()
+
+			wantSuccess := tt.setUp(fake, tx, ur, ir)
+			if wantSuccess {
+				m.EXPECT().Begin().Return(tx, nil)
+				if tt.totpEnabled {
+					totpr.EXPECT().GetTOTPByUserID(tx, gomock.Any()).Return(&model.TOTP{UserID: existing.ID, Enabled: true}, nil)
+				} else {
+					totpr.EXPECT().GetTOTPByUserID(tx, gomock.Any()).Return(nil, &model.NoSuchDataError{})
+					sr.EXPECT().InsertSession(tx, gomock.Any()).Return(nil)
+				}
+			}
+
+			s := &oidcService{
+				m:                      m,
+				userRepository:         ur,
+				sessionRepository:      sr,
+				identityRepository:     ir,
+				oidcLoginRepository:    memory.NewOIDCLoginRepository(),
+				totpRepository:         totpr,
+				pendingLoginRepository: plr,
+				userService:            service.NewUserService(m, ur),
+				sessionService:         service.NewSessionService(m, sr),
+				provider:               provider,
+				txCloser:               mock_application.MockCloseTransaction,
+				now:                    time.Now,
+			}
+
+			login, authURL, err := s.StartLogin(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			callback := fake.Authorize(t, authURL).Query()
+			state := callback.Get("state")
+			if tt.state != "" {
+				state = tt.state
+			}
+
+			user, session, pending, err := s.FinishLogin(context.Background(), login.Token, state, callback.Get("code"), client)
+			if !wantSuccess {
+				if _, ok := errors.Cause(err).(*model.AuthenticationErr); !ok {
+					t.Errorf("oidcService.FinishLogin() error = %v, want AuthenticationErr", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("oidcService.FinishLogin() error = %v", err)
+			}
+			if tt.totpEnabled {
+				if session != nil || pending == nil || pending.UserID != user.ID {
+					t.Fatalf("oidcService.FinishLogin() = %v, %v, want pending login of the user without session", session, pending)
+				}
+				stored, err := plr.TakePendingLogin(model.PendingLoginIDFromToken(pending.Token))
+				if err != nil || stored.UserID != existing.ID {
+					t.Errorf("pending login is not stored, got %v, error = %v", stored, err)
+				}
+			} else if user == nil || pending != nil || session.UserID != user.ID || session.ID != model.SessionIDFromToken(session.Token) {
+				t.Errorf("oidcService.FinishLogin() = %v, %v, want session of the user", user, session)
+			}
+
+			// the login can be used only once.
+			if _, _, _, err := s.FinishLogin(context.Background(), login.Token, state, callback.Get("code"), client); err == nil {
+				t.Error("oidcService.FinishLogin() for used login error = nil, want AuthenticationErr")
+			}
+		})
+	}
+}
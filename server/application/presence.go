@@ -0,0 +1,130 @@
+package application
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/service"
+)
+
+// PresenceService is the interface of PresenceService.
+type PresenceService interface {
+	Heartbeat(ctx context.Context, userID uint32, active, away bool) (*model.Presence, error)
+	GoOffline(ctx context.Context, userID uint32) error
+	GetThreadPresence(ctx context.Context, userID, threadID uint32) (*model.ThreadPresence, error)
+	StartTyping(ctx context.Context, userID, threadID uint32) error
+	StopTyping(ctx context.Context, userID, threadID uint32) error
+}
+
+// PresenceServiceDIInput is DI input of PresenceService.
+type PresenceServiceDIInput struct {
+	threadRepository       repository.ThreadRepository
+	threadMemberRepository repository.ThreadMemberRepository
+	policyService          service.PolicyService
+	presenceService        service.PresenceService
+}
+
+// NewPresenceServiceDIInput generates and returns PresenceServiceDIInput.
+func NewPresenceServiceDIInput(tRepo repository.ThreadRepository, mRepo repository.ThreadMemberRepository, pService service.PolicyService, prService service.PresenceService) *PresenceServiceDIInput {
+	return &PresenceServiceDIInput{
+		threadRepository:       tRepo,
+		threadMemberRepository: mRepo,
+		policyService:          pService,
+		presenceService:        prService,
+	}
+}
+
+// presenceService is the service of presence of users and typing in threads.
+type presenceService struct {
+	m                      repository.DBManager
+	threadRepository       repository.ThreadRepository
+	threadMemberRepository repository.ThreadMemberRepository
+	policyService          service.PolicyService
+	presenceService        service.PresenceService
+}
+
+// NewPresenceService generates and returns PresenceService.
+func NewPresenceService(m repository.DBManager, diInput PresenceServiceDIInput) PresenceService {
+	return &presenceService{
+		m:                      m,
+		threadRepository:       diInput.threadRepository,
+		threadMemberRepository: diInput.threadMemberRepository,
+		policyService:          diInput.policyService,
+		presenceService:        diInput.presenceService,
+	}
+}
+
+// Heartbeat records that the client of the user is connected and returns the status of the user.
+func (s *presenceService) Heartbeat(ctx context.Context, userID uint32, active, away bool) (*model.Presence, error) {
+	presence, err := s.presenceService.Heartbeat(ctx, userID, active, away)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to record heartbeat")
+	}
+	return presence, nil
+}
+
+// GoOffline makes the user offline at once without waiting for the heartbeat to expire.
+func (s *presenceService) GoOffline(ctx context.Context, userID uint32) error {
+	if err := s.presenceService.Offline(ctx, userID); err != nil {
+		return errors.Wrap(err, "failed to go offline")
+	}
+	return nil
+}
+
+// GetThreadPresence returns presence of members of the thread and users typing in it.
+// This returns NoSuchDataError if the user can not read the thread.
+func (s *presenceService) GetThreadPresence(ctx context.Context, userID, threadID uint32) (*model.ThreadPresence, error) {
+	if _, err := getReadableThread(s.m, s.threadRepository, s.policyService, userID, threadID); err != nil {
+		return nil, err
+	}
+
+	members, err := s.threadMemberRepository.GetThreadMembersByThreadID(s.m, threadID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get members of thread")
+	}
+
+	ids := make([]uint32, 0, len(members))
+	for _, member := range members {
+		ids = append(ids, member.UserID)
+	}
+
+	presences, err := s.presenceService.GetPresences(ctx, ids)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get presences")
+	}
+
+	typingUserIDs, err := s.presenceService.GetTypingUserIDs(ctx, threadID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get typing users")
+	}
+
+	return &model.ThreadPresence{
+		ThreadID:      threadID,
+		Presences:     presences,
+		TypingUserIDs: typingUserIDs,
+	}, nil
+}
+
+// StartTyping records that the user is typing in the thread.
+// Throttled events are ignored without error, so that clients do not need to throttle.
+func (s *presenceService) StartTyping(ctx context.Context, userID, threadID uint32) error {
+	if _, err := getReadableThread(s.m, s.threadRepository, s.policyService, userID, threadID); err != nil {
+		return err
+	}
+
+	if _, err := s.presenceService.StartTyping(ctx, threadID, userID); err != nil {
+		return errors.Wrap(err, "failed to start typing")
+	}
+	return nil
+}
+
+// StopTyping ends typing of the user in the thread.
+func (s *presenceService) StopTyping(ctx context.Context, userID, threadID uint32) error {
+	if err := s.presenceService.StopTyping(ctx, threadID, userID); err != nil {
+		return errors.Wrap(err, "failed to stop typing")
+	}
+	return nil
+}
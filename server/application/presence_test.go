@@ -0,0 +1,97 @@
+package application
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	mock_repository "github.com/hideUW/nuxt-go-chat-app/server/domain/repository/mock"
+	mock_service "github.com/hideUW/nuxt-go-chat-app/server/domain/service/mock"
+	"github.com/hideUW/nuxt-go-chat-app/server/testutil"
+)
+
+func Test_presenceService_GetThreadPresence(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	thread := &model.Thread{ID: model.ThreadValidIDForTest, Visibility: model.ThreadVisibilityPrivate}
+	presences := []*model.Presence{
+		{UserID: model.UserValidIDForTest, Status: model.PresenceStatusOnline},
+		{UserID: model.UserInValidIDForTest, Status: model.PresenceStatusOffline},
+	}
+
+	tests := []struct {
+		name    string
+		canRead bool
+		want    *model.ThreadPresence
+		wantErr error
+	}{
+		{
+			name:    "When the user can read the thread, returns presence of its members and typing users",
+			canRead: true,
+			want: &model.ThreadPresence{
+				ThreadID:      model.ThreadValidIDForTest,
+				Presences:     presences,
+				TypingUserIDs: []uint32{model.UserInValidIDForTest},
+			},
+		},
+		{
+			name:    "When the user can not read the thread, returns NoSuchDataError to hide it",
+			canRead: false,
+			wantErr: &model.NoSuchDataError{
+				PropertyNameForDeveloper:    model.IDPropertyForDeveloper,
+				PropertyNameForUser:         model.IDPropertyForUser,
+				PropertyValue:               model.ThreadValidIDForTest,
+				DomainModelNameForDeveloper: model.DomainModelNameThreadForDeveloper,
+				DomainModelNameForUser:      model.DomainModelNameThreadForUser,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock_repository.NewMockDBManager(ctrl)
+			tr := mock_repository.NewMockThreadRepository(ctrl)
+			mr := mock_repository.NewMockThreadMemberRepository(ctrl)
+			ps := mock_service.NewMockPolicyService(ctrl)
+			prs := mock_service.NewMockPresenceService(ctrl)
+
+			tr.EXPECT().GetThreadByID(m, model.ThreadValidIDForTest).Return(thread, nil)
+			ps.EXPECT().CanReadThread(model.UserValidIDForTest, thread).Return(tt.canRead, nil)
+			if tt.canRead {
+				mr.EXPECT().GetThreadMembersByThreadID(m, model.ThreadValidIDForTest).Return([]*model.ThreadMember{
+					{ThreadID: model.ThreadValidIDForTest, UserID: model.UserValidIDForTest},
+					{ThreadID: model.ThreadValidIDForTest, UserID: model.UserInValidIDForTest},
+				}, nil)
+				prs.EXPECT().GetPresences(ctx, []uint32{model.UserValidIDForTest, model.UserInValidIDForTest}).Return(presences, nil)
+				prs.EXPECT().GetTypingUserIDs(ctx, model.ThreadValidIDForTest).Return([]uint32{model.UserInValidIDForTest}, nil)
+			}
+
+			s := &presenceService{
+				m:                      m,
+				threadRepository:       tr,
+				threadMemberRepository: mr,
+				policyService:          ps,
+				presenceService:        prs,
+			}
+
+			got, err := s.GetThreadPresence(ctx, model.UserValidIDForTest, model.ThreadValidIDForTest)
+			if tt.wantErr != nil {
+				if err == nil || errors.Cause(err).Error() != tt.wantErr.Error() {
+					t.Errorf("presenceService.GetThreadPresence() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("presenceService.GetThreadPresence() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				testutil.Errorf(t, tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package application
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/service"
+)
+
+// RoleService is the interface of RoleService.
+// This manages roles of users and moderators of threads.
+type RoleService interface {
+	GetRoles(ctx context.Context, userID uint32) (model.Roles, error)
+	GrantRole(ctx context.Context, actor *model.User, userID uint32, role model.Role) error
+	RevokeRole(ctx context.Context, actor *model.User, userID uint32, role model.Role) error
+	ListThreadModerators(ctx context.Context, userID, threadID uint32) ([]uint32, error)
+	AddThreadModerator(ctx context.Context, actor *model.User, threadID, userID uint32) error
+	RemoveThreadModerator(ctx context.Context, actor *model.User, threadID, userID uint32) error
+}
+
+// RoleServiceDIInput is DI input of RoleService.
+type RoleServiceDIInput struct {
+	userRepository            repository.UserRepository
+	threadRepository          repository.ThreadRepository
+	roleRepository            repository.RoleRepository
+	threadModeratorRepository repository.ThreadModeratorRepository
+	policyService             service.PolicyService
+}
+
+// NewRoleServiceDIInput generates and returns RoleServiceDIInput.
+func NewRoleServiceDIInput(uRepo repository.UserRepository, tRepo repository.ThreadRepository, rRepo repository.RoleRepository, tmRepo repository.ThreadModeratorRepository, pService service.PolicyService) *RoleServiceDIInput {
+	return &RoleServiceDIInput{
+		userRepository:            uRepo,
+		threadRepository:          tRepo,
+		roleRepository:            rRepo,
+		threadModeratorRepository: tmRepo,
+		policyService:             pService,
+	}
+}
+
+// roleService is the service of roles.
+type roleService struct {
+	m                         repository.DBManager
+	userRepository            repository.UserRepository
+	threadRepository          repository.ThreadRepository
+	roleRepository            repository.RoleRepository
+	threadModeratorRepository repository.ThreadModeratorRepository
+	policyService             service.PolicyService
+}
+
+// NewRoleService generates and returns RoleService.
+func NewRoleService(m repository.DBManager, diInput RoleServiceDIInput) RoleService {
+	return &roleService{
+		m:                         m,
+		userRepository:            diInput.userRepository,
+		threadRepository:          diInput.threadRepository,
+		roleRepository:            diInput.roleRepository,
+		threadModeratorRepository: diInput.threadModeratorRepository,
+		policyService:             diInput.policyService,
+	}
+}
+
+// GetRoles returns roles of the user.
+func (s *roleService) GetRoles(ctx context.Context, userID uint32) (model.Roles, error) {
+	return s.policyService.GetRoles(userID)
+}
+
+// GrantRole grants the role to the user.
+// This returns ForbiddenError if the actor is not allowed to manage roles.
+func (s *roleService) GrantRole(ctx context.Context, actor *model.User, userID uint32, role model.Role) error {
+	if err := s.requireManageRoles(actor); err != nil {
+		return err
+	}
+
+	if err := model.ValidateRole(role); err != nil {
+		return err
+	}
+
+	if _, err := s.userRepository.GetUserByID(s.m, userID); err != nil {
+		return errors.Wrap(err, "failed to get user by id")
+	}
+
+	if err := s.roleRepository.InsertRole(s.m, userID, role); err != nil {
+		return errors.Wrap(err, "failed to insert role")
+	}
+	return nil
+}
+
+// RevokeRole revokes the role from the user.
+// Admins can not revoke admin from themselves, so that the service is not left without admins by mistake.
+func (s *roleService) RevokeRole(ctx context.Context, actor *model.User, userID uint32, role model.Role) error {
+	if err := s.requireManageRoles(actor); err != nil {
+		return err
+	}
+
+	if actor.ID == userID && role == model.RoleAdmin {
+		return errors.WithStack(&model.ForbiddenError{
+			InvalidReasonForDeveloper: "admin can not revoke admin from itself",
+		})
+	}
+
+	if err := s.roleRepository.DeleteRole(s.m, userID, role); err != nil {
+		return errors.Wrap(err, "failed to delete role")
+	}
+	return nil
+}
+
+// requireManageRoles returns ForbiddenError if the actor is not allowed to manage roles.
+func (s *roleService) requireManageRoles(actor *model.User) error {
+	ok, err := s.policyService.CanManageRoles(actor)
+	if err != nil {
+		return errors.Wrap(err, "failed to check policy")
+	}
+	if !ok {
+		return errors.WithStack(&model.ForbiddenError{
+			InvalidReasonForDeveloper: "only admins can manage roles",
+		})
+	}
+	return nil
+}
+
+// ListThreadModerators returns ids of moderators of the thread.
+// This returns NoSuchDataError if the user can not read the thread.
+func (s *roleService) ListThreadModerators(ctx context.Context, userID, threadID uint32) ([]uint32, error) {
+	if _, err := getReadableThread(s.m, s.threadRepository, s.policyService, userID, threadID); err != nil {
+		return nil, err
+	}
+
+	ids, err := s.threadModeratorRepository.GetModeratorIDsByThreadID(s.m, threadID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get moderators of thread")
+	}
+	return ids, nil
+}
+
+// AddThreadModerator appoints the user as a moderator of the thread.
+// This returns ForbiddenError if the actor is not allowed to manage moderators of the thread.
+// Only members can be appointed to the private thread because others can not read it.
+func (s *roleService) AddThreadModerator(ctx context.Context, actor *model.User, threadID, userID uint32) error {
+	thread, err := s.requireManageModerators(actor, threadID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.userRepository.GetUserByID(s.m, userID); err != nil {
+		return errors.Wrap(err, "failed to get user by id")
+	}
+
+	ok, err := s.policyService.CanReadThread(userID, thread)
+	if err != nil {
+		return errors.Wrap(err, "failed to check policy")
+	}
+	if !ok {
+		return errors.WithStack(&model.NoSuchDataError{
+			PropertyNameForDeveloper:    model.UserIDPropertyForDeveloper,
+			PropertyNameForUser:         model.UserIDPropertyForUser,
+			PropertyValue:               userID,
+			DomainModelNameForDeveloper: model.DomainModelNameThreadMemberForDeveloper,
+			DomainModelNameForUser:      model.DomainModelNameThreadMemberForUser,
+		})
+	}
+
+	if err := s.threadModeratorRepository.InsertThreadModerator(s.m, threadID, userID); err != nil {
+		return errors.Wrap(err, "failed to insert moderator of thread")
+	}
+	return nil
+}
+
+// RemoveThreadModerator dismisses the user from moderators of the thread.
+// This returns ForbiddenError unless the actor is the owner of the thread or an admin,
+// but moderators can dismiss themselves.
+func (s *roleService) RemoveThreadModerator(ctx context.Context, actor *model.User, threadID, userID uint32) error {
+	thread, err := getReadableThread(s.m, s.threadRepository, s.policyService, actor.ID, threadID)
+	if err != nil {
+		return err
+	}
+
+	ok, err := s.policyService.CanDismissModerator(actor, thread, userID)
+	if err != nil {
+		return errors.Wrap(err, "failed to check policy")
+	}
+	if !ok {
+		return errors.WithStack(&model.ForbiddenError{
+			InvalidReasonForDeveloper: "only admins or the owner of the thread can dismiss its moderators",
+		})
+	}
+
+	if err := s.threadModeratorRepository.DeleteThreadModerator(s.m, threadID, userID); err != nil {
+		return errors.Wrap(err, "failed to delete moderator of thread")
+	}
+	return nil
+}
+
+// requireManageModerators returns the thread,
+// or ForbiddenError if the actor is not allowed to manage moderators of the thread.
+// NoSuchDataError is returned first if the thread does not exist or the actor can not read it.
+func (s *roleService) requireManageModerators(actor *model.User, threadID uint32) (*model.Thread, error) {
+	thread, err := getReadableThread(s.m, s.threadRepository, s.policyService, actor.ID, threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := s.policyService.CanManageModerators(actor, threadID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to check policy")
+	}
+	if !ok {
+		return nil, errors.WithStack(&model.ForbiddenError{
+			InvalidReasonForDeveloper: "only admins or moderators of the thread can manage its moderators",
+		})
+	}
+	return thread, nil
+}
@@ -0,0 +1,259 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	mock_repository "github.com/hideUW/nuxt-go-chat-app/server/domain/repository/mock"
+	mock_service "github.com/hideUW/nuxt-go-chat-app/server/domain/service/mock"
+)
+
+func Test_roleService_GrantRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	actor := &model.User{ID: model.UserValidIDForTest}
+
+	tests := []struct {
+		name    string
+		allowed bool
+		role    model.Role
+		wantErr error
+	}{
+		{
+			name:    "When the actor can manage roles, grants the role",
+			allowed: true,
+			role:    model.RoleModerator,
+		},
+		{
+			name:    "When the actor can not manage roles, returns ForbiddenError",
+			allowed: false,
+			role:    model.RoleModerator,
+			wantErr: &model.ForbiddenError{InvalidReasonForDeveloper: "only admins can manage roles"},
+		},
+		{
+			name:    "When the role is unknown, returns InvalidParamError",
+			allowed: true,
+			role:    "owner",
+			wantErr: &model.InvalidParamError{
+				PropertyNameForDeveloper:  model.RolePropertyForDeveloper,
+				PropertyNameForUser:       model.RolePropertyForUser,
+				PropertyValue:             model.Role("owner"),
+				InvalidReasonForDeveloper: "unknown role",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock_repository.NewMockDBManager(ctrl)
+			ur := mock_repository.NewMockUserRepository(ctrl)
+			rr := mock_repository.NewMockRoleRepository(ctrl)
+			ps := mock_service.NewMockPolicyService(ctrl)
+
+			ps.EXPECT().CanManageRoles(actor).Return(tt.allowed, nil)
+			if tt.wantErr == nil {
+				ur.EXPECT().GetUserByID(m, model.UserInValidIDForTest).Return(&model.User{ID: model.UserInValidIDForTest}, nil)
+				rr.EXPECT().InsertRole(m, model.UserInValidIDForTest, tt.role).Return(nil)
+			}
+
+			s := &roleService{
+				m:              m,
+				userRepository: ur,
+				roleRepository: rr,
+				policyService:  ps,
+			}
+
+			err := s.GrantRole(ctx, actor, model.UserInValidIDForTest, tt.role)
+			if tt.wantErr != nil {
+				if err == nil || errors.Cause(err).Error() != tt.wantErr.Error() {
+					t.Errorf("roleService.GrantRole() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("roleService.GrantRole() error = %v", err)
+			}
+		})
+	}
+}
+
+func Test_roleService_RevokeRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	actor := &model.User{ID: model.UserValidIDForTest}
+
+	tests := []struct {
+		name    string
+		userID  uint32
+		role    model.Role
+		wantErr error
+	}{
+		{
+			name:   "When the admin revokes admin from another user, revokes it",
+			userID: model.UserInValidIDForTest,
+			role:   model.RoleAdmin,
+		},
+		{
+			name:    "When the admin revokes admin from itself, returns ForbiddenError",
+			userID:  model.UserValidIDForTest,
+			role:    model.RoleAdmin,
+			wantErr: &model.ForbiddenError{InvalidReasonForDeveloper: "admin can not revoke admin from itself"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock_repository.NewMockDBManager(ctrl)
+			rr := mock_repository.NewMockRoleRepository(ctrl)
+			ps := mock_service.NewMockPolicyService(ctrl)
+
+			ps.EXPECT().CanManageRoles(actor).Return(true, nil)
+			if tt.wantErr == nil {
+				rr.EXPECT().DeleteRole(m, tt.userID, tt.role).Return(nil)
+			}
+
+			s := &roleService{
+				m:              m,
+				roleRepository: rr,
+				policyService:  ps,
+			}
+
+			err := s.RevokeRole(ctx, actor, tt.userID, tt.role)
+			if tt.wantErr != nil {
+				if err == nil || errors.Cause(err).Error() != tt.wantErr.Error() {
+					t.Errorf("roleService.RevokeRole() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("roleService.RevokeRole() error = %v", err)
+			}
+		})
+	}
+}
+
+func Test_roleService_AddThreadModerator(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	actor := &model.User{ID: model.UserValidIDForTest}
+
+	tests := []struct {
+		name    string
+		allowed bool
+		wantErr error
+	}{
+		{
+			name:    "When the actor moderates the thread, appoints the user",
+			allowed: true,
+		},
+		{
+			name:    "When the actor does not moderate the thread, returns ForbiddenError",
+			allowed: false,
+			wantErr: &model.ForbiddenError{InvalidReasonForDeveloper: "only admins or moderators of the thread can manage its moderators"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock_repository.NewMockDBManager(ctrl)
+			ur := mock_repository.NewMockUserRepository(ctrl)
+			tr := mock_repository.NewMockThreadRepository(ctrl)
+			tmr := mock_repository.NewMockThreadModeratorRepository(ctrl)
+			ps := mock_service.NewMockPolicyService(ctrl)
+
+			thread := &model.Thread{ID: model.ThreadValidIDForTest, Visibility: model.ThreadVisibilityPublic}
+			tr.EXPECT().GetThreadByID(m, model.ThreadValidIDForTest).Return(thread, nil)
+			ps.EXPECT().CanReadThread(model.UserValidIDForTest, thread).Return(true, nil)
+			ps.EXPECT().CanManageModerators(actor, model.ThreadValidIDForTest).Return(tt.allowed, nil)
+			if tt.wantErr == nil {
+				ur.EXPECT().GetUserByID(m, model.UserInValidIDForTest).Return(&model.User{ID: model.UserInValidIDForTest}, nil)
+				ps.EXPECT().CanReadThread(model.UserInValidIDForTest, thread).Return(true, nil)
+				tmr.EXPECT().InsertThreadModerator(m, model.ThreadValidIDForTest, model.UserInValidIDForTest).Return(nil)
+			}
+
+			s := &roleService{
+				m:                         m,
+				userRepository:            ur,
+				threadRepository:          tr,
+				threadModeratorRepository: tmr,
+				policyService:             ps,
+			}
+
+			err := s.AddThreadModerator(ctx, actor, model.ThreadValidIDForTest, model.UserInValidIDForTest)
+			if tt.wantErr != nil {
+				if err == nil || errors.Cause(err).Error() != tt.wantErr.Error() {
+					t.Errorf("roleService.AddThreadModerator() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("roleService.AddThreadModerator() error = %v", err)
+			}
+		})
+	}
+}
+
+func Test_roleService_RemoveThreadModerator(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	actor := &model.User{ID: model.UserValidIDForTest}
+
+	tests := []struct {
+		name    string
+		allowed bool
+		wantErr error
+	}{
+		{
+			name:    "When the actor is allowed to dismiss the moderator, dismisses the moderator",
+			allowed: true,
+		},
+		{
+			name:    "When the actor is a moderator dismissing the owner, returns ForbiddenError",
+			allowed: false,
+			wantErr: &model.ForbiddenError{InvalidReasonForDeveloper: "only admins or the owner of the thread can dismiss its moderators"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock_repository.NewMockDBManager(ctrl)
+			tr := mock_repository.NewMockThreadRepository(ctrl)
+			tmr := mock_repository.NewMockThreadModeratorRepository(ctrl)
+			ps := mock_service.NewMockPolicyService(ctrl)
+
+			thread := &model.Thread{ID: model.ThreadValidIDForTest, UserID: model.UserInValidIDForTest, Visibility: model.ThreadVisibilityPublic}
+			tr.EXPECT().GetThreadByID(m, model.ThreadValidIDForTest).Return(thread, nil)
+			ps.EXPECT().CanReadThread(model.UserValidIDForTest, thread).Return(true, nil)
+			ps.EXPECT().CanDismissModerator(actor, thread, model.UserInValidIDForTest).Return(tt.allowed, nil)
+			if tt.wantErr == nil {
+				tmr.EXPECT().DeleteThreadModerator(m, model.ThreadValidIDForTest, model.UserInValidIDForTest).Return(nil)
+			}
+
+			s := &roleService{
+				m:                         m,
+				threadRepository:          tr,
+				threadModeratorRepository: tmr,
+				policyService:             ps,
+			}
+
+			err := s.RemoveThreadModerator(ctx, actor, model.ThreadValidIDForTest, model.UserInValidIDForTest)
+			if tt.wantErr != nil {
+				if err == nil || errors.Cause(err).Error() != tt.wantErr.Error() {
+					t.Errorf("roleService.RemoveThreadModerator() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("roleService.RemoveThreadModerator() error = %v", err)
+			}
+		})
+	}
+}
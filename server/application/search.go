@@ -0,0 +1,210 @@
+package application
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/service"
+)
+
+// DefaultSearchPageLimit and MaxSearchPageLimit are the default and max number of comments found at once.
+const (
+	DefaultSearchPageLimit = 20
+	MaxSearchPageLimit     = 50
+)
+
+// maxSearchRounds is how many times candidates are searched for a page,
+// so that a page is not empty only because the user can not read the newest candidates.
+const maxSearchRounds = 5
+
+// reindexBatchSize is the number of comments read at once to build the index.
+const reindexBatchSize = 500
+
+// SearchService is the interface of SearchService.
+type SearchService interface {
+	Search(ctx context.Context, userID uint32, q string, beforeID uint32, limit int) (*model.SearchResult, error)
+	Reindex(ctx context.Context) (int, error)
+}
+
+// SearchServiceDIInput is DI input of SearchService.
+type SearchServiceDIInput struct {
+	userRepository    repository.UserRepository
+	threadRepository  repository.ThreadRepository
+	commentRepository repository.CommentRepository
+	searchRepository  repository.SearchRepository
+	policyService     service.PolicyService
+}
+
+// NewSearchServiceDIInput generates and returns SearchServiceDIInput.
+func NewSearchServiceDIInput(uRepo repository.UserRepository, tRepo repository.ThreadRepository, cRepo repository.CommentRepository, seRepo repository.SearchRepository, pService service.PolicyService) *SearchServiceDIInput {
+	return &SearchServiceDIInput{
+		userRepository:    uRepo,
+		threadRepository:  tRepo,
+		commentRepository: cRepo,
+		searchRepository:  seRepo,
+		policyService:     pService,
+	}
+}
+
+// searchService is the service of full-text search of comments.
+type searchService struct {
+	m                 repository.DBManager
+	userRepository    repository.UserRepository
+	threadRepository  repository.ThreadRepository
+	commentRepository repository.CommentRepository
+	searchRepository  repository.SearchRepository
+	policyService     service.PolicyService
+}
+
+// NewSearchService generates and returns SearchService.
+func NewSearchService(m repository.DBManager, diInput SearchServiceDIInput) SearchService {
+	return &searchService{
+		m:                 m,
+		userRepository:    diInput.userRepository,
+		threadRepository:  diInput.threadRepository,
+		commentRepository: diInput.commentRepository,
+		searchRepository:  diInput.searchRepository,
+		policyService:     diInput.policyService,
+	}
+}
+
+// Search returns at most limit comments which match the query q and the user can read,
+// whose ids are less than beforeID in order of newest first, with ranges of their content where the terms appear.
+// The newest ones are returned if beforeID is 0, and the default limit is used if limit is 0.
+// This returns no comment if the user of from: does not exist,
+// and NoSuchDataError if the thread of in: does not exist or the user can not read it.
+func (s *searchService) Search(ctx context.Context, userID uint32, q string, beforeID uint32, limit int) (*model.SearchResult, error) {
+	limit, err := pageLimit(limit, DefaultSearchPageLimit, MaxSearchPageLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	query, err := model.ParseSearchQuery(q)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse search query")
+	}
+
+	result := &model.SearchResult{
+		Hits: make([]*model.SearchHit, 0),
+	}
+
+	if query.FromUserName != "" {
+		user, err := s.userRepository.GetUserByName(s.m, query.FromUserName)
+		if err != nil {
+			if _, ok := errors.Cause(err).(*model.NoSuchDataError); ok {
+				return result, nil
+			}
+			return nil, errors.Wrap(err, "failed to get user by name")
+		}
+		query.FromUserID = user.ID
+	}
+
+	if query.ThreadID != 0 {
+		if _, err := getReadableThread(s.m, s.threadRepository, s.policyService, userID, query.ThreadID); err != nil {
+			return nil, err
+		}
+	}
+
+	readable := make(map[uint32]bool)
+	cursor := beforeID
+	for round := 0; round < maxSearchRounds; round++ {
+		// one more candidate is searched to know whether more candidates exist.
+		ids, err := s.searchRepository.SearchCommentIDs(s.m, query, cursor, limit+1)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to search comment ids")
+		}
+
+		// candidates which are not found are left in the index by failed transactions.
+		comments, err := s.commentRepository.GetCommentsByIDs(s.m, ids)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get comments by ids")
+		}
+
+		for _, comment := range comments {
+			if comment.IsDeleted() {
+				continue
+			}
+
+			ok, err := s.canReadThread(userID, comment.ThreadID, readable)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+
+			if len(result.Hits) == limit {
+				result.HasMore = true
+				result.NextBeforeID = result.Hits[limit-1].Comment.ID
+				return result, nil
+			}
+
+			result.Hits = append(result.Hits, &model.SearchHit{
+				Comment:    comment,
+				Highlights: model.HighlightTerms(comment.Content, query.Terms),
+			})
+		}
+
+		if len(ids) <= limit {
+			return result, nil
+		}
+		cursor = ids[len(ids)-1]
+	}
+
+	// the rest of candidates are searched by the next page.
+	result.HasMore = true
+	result.NextBeforeID = cursor
+	return result, nil
+}
+
+// Reindex adds all comments which are not deleted to the index, and returns the number of them.
+// This is needed at start by the index which is not persisted.
+func (s *searchService) Reindex(ctx context.Context) (int, error) {
+	var n int
+	var afterID uint32
+	for {
+		comments, err := s.commentRepository.ListUndeletedCommentsAfterID(s.m, afterID, reindexBatchSize)
+		if err != nil {
+			return n, errors.Wrap(err, "failed to list undeleted comments after id")
+		}
+
+		for _, comment := range comments {
+			if err := s.searchRepository.IndexComment(s.m, comment); err != nil {
+				return n, errors.Wrap(err, "failed to index comment")
+			}
+		}
+		n += len(comments)
+
+		if len(comments) < reindexBatchSize {
+			return n, nil
+		}
+		afterID = comments[len(comments)-1].ID
+	}
+}
+
+// canReadThread returns whether the user can read the thread, which is cached for the search.
+// Threads which do not exist are not readable.
+func (s *searchService) canReadThread(userID, threadID uint32, cache map[uint32]bool) (bool, error) {
+	if ok, found := cache[threadID]; found {
+		return ok, nil
+	}
+
+	thread, err := s.threadRepository.GetThreadByID(s.m, threadID)
+	if err != nil {
+		if _, ok := errors.Cause(err).(*model.NoSuchDataError); !ok {
+			return false, errors.Wrap(err, "failed to get thread by id")
+		}
+		cache[threadID] = false
+		return false, nil
+	}
+
+	ok, err := s.policyService.CanReadThread(userID, thread)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to check policy")
+	}
+	cache[threadID] = ok
+	return ok, nil
+}
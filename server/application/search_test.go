@@ -0,0 +1,197 @@
+package application
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	mock_repository "github.com/hideUW/nuxt-go-chat-app/server/domain/repository/mock"
+	mock_service "github.com/hideUW/nuxt-go-chat-app/server/domain/service/mock"
+	"github.com/hideUW/nuxt-go-chat-app/server/testutil"
+)
+
+func Test_searchService_Search(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	readableThread := &model.Thread{ID: model.ThreadValidIDForTest}
+	privateThread := &model.Thread{ID: model.ThreadValidIDForTest + 1}
+	comment := func(id, threadID uint32) *model.Comment {
+		return &model.Comment{ID: id, ThreadID: threadID, UserID: model.UserInValidIDForTest, Content: "Hello world"}
+	}
+
+	m := mock_repository.NewMockDBManager(ctrl)
+	tr := mock_repository.NewMockThreadRepository(ctrl)
+	cr := mock_repository.NewMockCommentRepository(ctrl)
+	ser := mock_repository.NewMockSearchRepository(ctrl)
+	ps := mock_service.NewMockPolicyService(ctrl)
+
+	deleted := comment(8, readableThread.ID)
+	deleted.DeletedAt = testutil.TimeNow()
+
+	// the first round finds 3 candidates for the limit 2, and only 9 is readable among them,
+	// so that the next round is searched before 8.
+	gomock.InOrder(
+		ser.EXPECT().SearchCommentIDs(m, gomock.Any(), uint32(0), 3).Return([]uint32{10, 9, 8}, nil),
+		cr.EXPECT().GetCommentsByIDs(m, []uint32{10, 9, 8}).Return([]*model.Comment{
+			comment(10, privateThread.ID),
+			comment(9, readableThread.ID),
+			deleted,
+		}, nil),
+		ser.EXPECT().SearchCommentIDs(m, gomock.Any(), uint32(8), 3).Return([]uint32{7, 6, 5}, nil),
+		cr.EXPECT().GetCommentsByIDs(m, []uint32{7, 6, 5}).Return([]*model.Comment{
+			comment(7, readableThread.ID),
+			comment(6, privateThread.ID),
+			comment(5, readableThread.ID),
+		}, nil),
+	)
+	tr.EXPECT().GetThreadByID(m, privateThread.ID).Return(privateThread, nil)
+	tr.EXPECT().GetThreadByID(m, readableThread.ID).Return(readableThread, nil)
+	ps.EXPECT().CanReadThread(model.UserValidIDForTest, privateThread).Return(false, nil)
+	ps.EXPECT().CanReadThread(model.UserValidIDForTest, readableThread).Return(true, nil)
+
+	s := &searchService{
+		m:                 m,
+		threadRepository:  tr,
+		commentRepository: cr,
+		searchRepository:  ser,
+		policyService:     ps,
+	}
+
+	got, err := s.Search(ctx, model.UserValidIDForTest, "world", 0, 2)
+	if err != nil {
+		t.Fatalf("searchService.Search() error = %v", err)
+	}
+
+	want := &model.SearchResult{
+		Hits: []*model.SearchHit{
+			{Comment: comment(9, readableThread.ID), Highlights: []model.TextRange{{Start: 6, End: 11}}},
+			{Comment: comment(7, readableThread.ID), Highlights: []model.TextRange{{Start: 6, End: 11}}},
+		},
+		HasMore:      true,
+		NextBeforeID: 7,
+	}
+	if !reflect.DeepEqual(got, want) {
+		testutil.Errorf(t, want, got)
+	}
+}
+
+func Test_searchService_Search_filters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		q          string
+		storedUser *model.User
+		storedErr  error
+		wantErr    error
+	}{
+		{
+			name:       "When the user of from: exists, searches comments of the user",
+			q:          "hello from:@" + model.UserNameForTest,
+			storedUser: &model.User{ID: model.UserValidIDForTest, Name: model.UserNameForTest},
+		},
+		{
+			name:      "When the user of from: does not exist, returns no comment",
+			q:         "hello from:" + model.UserNameForTest,
+			storedErr: &model.NoSuchDataError{},
+		},
+		{
+			name: "When the query has neither terms nor filters, returns RequiredError",
+			q:    "  ",
+			wantErr: &model.RequiredError{
+				PropertyNameForDeveloper: model.QueryPropertyForDeveloper,
+				PropertyNameForUser:      model.QueryPropertyForUser,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock_repository.NewMockDBManager(ctrl)
+			ur := mock_repository.NewMockUserRepository(ctrl)
+			ser := mock_repository.NewMockSearchRepository(ctrl)
+			cr := mock_repository.NewMockCommentRepository(ctrl)
+
+			if tt.wantErr == nil {
+				ur.EXPECT().GetUserByName(m, model.UserNameForTest).Return(tt.storedUser, tt.storedErr)
+			}
+			if tt.storedUser != nil {
+				ser.EXPECT().SearchCommentIDs(m, gomock.Any(), uint32(0), DefaultSearchPageLimit+1).DoAndReturn(
+					func(_ interface{}, query *model.SearchQuery, _ uint32, _ int) ([]uint32, error) {
+						if query.FromUserID != tt.storedUser.ID {
+							t.Errorf("SearchCommentIDs() is called with FromUserID %d, want %d", query.FromUserID, tt.storedUser.ID)
+						}
+						return []uint32{}, nil
+					})
+				cr.EXPECT().GetCommentsByIDs(m, []uint32{}).Return([]*model.Comment{}, nil)
+			}
+
+			s := &searchService{
+				m:                 m,
+				userRepository:    ur,
+				commentRepository: cr,
+				searchRepository:  ser,
+			}
+
+			got, err := s.Search(ctx, model.UserValidIDForTest, tt.q, 0, 0)
+			if tt.wantErr != nil {
+				if err == nil || errors.Cause(err).Error() != tt.wantErr.Error() {
+					t.Errorf("searchService.Search() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("searchService.Search() error = %v", err)
+			}
+
+			want := &model.SearchResult{Hits: make([]*model.SearchHit, 0)}
+			if !reflect.DeepEqual(got, want) {
+				testutil.Errorf(t, want, got)
+			}
+		})
+	}
+}
+
+func Test_searchService_Reindex(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mock_repository.NewMockDBManager(ctrl)
+	cr := mock_repository.NewMockCommentRepository(ctrl)
+	ser := mock_repository.NewMockSearchRepository(ctrl)
+
+	first := make([]*model.Comment, 0, reindexBatchSize)
+	for i := 1; i <= reindexBatchSize; i++ {
+		first = append(first, &model.Comment{ID: uint32(i)})
+	}
+	last := []*model.Comment{{ID: reindexBatchSize + 1}}
+
+	gomock.InOrder(
+		cr.EXPECT().ListUndeletedCommentsAfterID(m, uint32(0), reindexBatchSize).Return(first, nil),
+		cr.EXPECT().ListUndeletedCommentsAfterID(m, uint32(reindexBatchSize), reindexBatchSize).Return(last, nil),
+	)
+	ser.EXPECT().IndexComment(m, gomock.Any()).Return(nil).Times(reindexBatchSize + 1)
+
+	s := &searchService{
+		m:                 m,
+		commentRepository: cr,
+		searchRepository:  ser,
+	}
+
+	got, err := s.Reindex(context.Background())
+	if err != nil {
+		t.Fatalf("searchService.Reindex() error = %v", err)
+	}
+	if got != reindexBatchSize+1 {
+		t.Errorf("searchService.Reindex() = %d, want %d", got, reindexBatchSize+1)
+	}
+}
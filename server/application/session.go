@@ -0,0 +1,77 @@
+package application
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+)
+
+// SessionService is the interface of SessionService.
+type SessionService interface {
+	ListSessions(ctx context.Context, userID uint32) ([]*model.Session, error)
+	RevokeSession(ctx context.Context, userID uint32, id string) (*model.Session, error)
+	RevokeAllSessions(ctx context.Context, userID uint32) error
+}
+
+// sessionService is the service of sessions of user.
+type sessionService struct {
+	m                 repository.DBManager
+	sessionRepository repository.SessionRepository
+}
+
+// NewSessionService generates and returns SessionService.
+func NewSessionService(m repository.DBManager, sRepo repository.SessionRepository) SessionService {
+	return &sessionService{
+		m:                 m,
+		sessionRepository: sRepo,
+	}
+}
+
+// ListSessions returns active sessions of the user in order of last seen.
+func (s *sessionService) ListSessions(ctx context.Context, userID uint32) ([]*model.Session, error) {
+	sessions, err := s.sessionRepository.GetSessionsByUserID(s.m, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get sessions by user id")
+	}
+	return sessions, nil
+}
+
+// RevokeSession deletes the session of the user specified by id and returns it.
+// This returns NoSuchDataError if the user does not have the session,
+// so that the user can not tell whether sessions of other users exist.
+func (s *sessionService) RevokeSession(ctx context.Context, userID uint32, id string) (*model.Session, error) {
+	sessions, err := s.sessionRepository.GetSessionsByUserID(s.m, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get sessions by user id")
+	}
+
+	for _, session := range sessions {
+		if session.ID != id {
+			continue
+		}
+
+		if err := s.sessionRepository.DeleteSession(s.m, session.ID); err != nil {
+			return nil, errors.Wrap(err, "failed to delete session")
+		}
+		return session, nil
+	}
+
+	return nil, errors.WithStack(&model.NoSuchDataError{
+		PropertyNameForDeveloper:    model.IDPropertyForDeveloper,
+		PropertyNameForUser:         model.IDPropertyForUser,
+		PropertyValue:               id,
+		DomainModelNameForDeveloper: model.DomainModelNameSessionForDeveloper,
+		DomainModelNameForUser:      model.DomainModelNameSessionForUser,
+	})
+}
+
+// RevokeAllSessions deletes all sessions of the user including the current one.
+func (s *sessionService) RevokeAllSessions(ctx context.Context, userID uint32) error {
+	if err := s.sessionRepository.DeleteSessionsByUserID(s.m, userID); err != nil {
+		return errors.Wrap(err, "failed to delete sessions by user id")
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	mock_repository "github.com/hideUW/nuxt-go-chat-app/server/domain/repository/mock"
+)
+
+func Test_sessionService_RevokeSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	own := &model.Session{ID: model.SessionIDFromToken(model.SessionTokenForTest), UserID: model.UserValidIDForTest}
+
+	tests := []struct {
+		name       string
+		id         string
+		wantDelete bool
+		wantErr    bool
+	}{
+		{
+			name:       "When the user has the session, deletes it",
+			id:         own.ID,
+			wantDelete: true,
+		},
+		{
+			name:    "When the session belongs to another user, returns NoSuchDataError",
+			id:      model.SessionValidIDForTest,
+			wantErr: true,
+		},
+		{
+			name:    "When the token is given instead of id, returns NoSuchDataError",
+			id:      model.SessionTokenForTest,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock_repository.NewMockDBManager(ctrl)
+			sr := mock_repository.NewMockSessionRepository(ctrl)
+
+			sr.EXPECT().GetSessionsByUserID(m, model.UserValidIDForTest).Return([]*model.Session{own}, nil)
+			if tt.wantDelete {
+				sr.EXPECT().DeleteSession(m, own.ID).Return(nil)
+			}
+
+			s := &sessionService{
+				m:                 m,
+				sessionRepository: sr,
+			}
+
+			got, err := s.RevokeSession(context.Background(), model.UserValidIDForTest, tt.id)
+			if tt.wantErr {
+				if _, ok := errors.Cause(err).(*model.NoSuchDataError); !ok {
+					t.Errorf("sessionService.RevokeSession() error = %v, want NoSuchDataError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("sessionService.RevokeSession() error = %v", err)
+			}
+			if got != own {
+				t.Errorf("sessionService.RevokeSession() = %v, want %v", got, own)
+			}
+		})
+	}
+}
@@ -0,0 +1,239 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/service"
+)
+
+// ThreadService is the interface of ThreadService.
+type ThreadService interface {
+	ListThreads(ctx context.Context, userID uint32) ([]*model.ThreadSummary, error)
+	GetThread(ctx context.Context, userID, id uint32) (*model.Thread, error)
+	CreateThread(ctx context.Context, userID uint32, title string, visibility model.ThreadVisibility) (*model.Thread, error)
+	MarkThreadRead(ctx context.Context, userID, threadID, commentID uint32) error
+}
+
+// ThreadServiceDIInput is DI input of ThreadService.
+type ThreadServiceDIInput struct {
+	threadRepository          repository.ThreadRepository
+	threadModeratorRepository repository.ThreadModeratorRepository
+	threadMemberRepository    repository.ThreadMemberRepository
+	threadReadRepository      repository.ThreadReadRepository
+	commentRepository         repository.CommentRepository
+	policyService             service.PolicyService
+}
+
+// NewThreadServiceDIInput generates and returns ThreadServiceDIInput.
+func NewThreadServiceDIInput(tRepo repository.ThreadRepository, tmRepo repository.ThreadModeratorRepository, mRepo repository.ThreadMemberRepository, trRepo repository.ThreadReadRepository, cRepo repository.CommentRepository, pService service.PolicyService) *ThreadServiceDIInput {
+	return &ThreadServiceDIInput{
+		threadRepository:          tRepo,
+		threadModeratorRepository: tmRepo,
+		threadMemberRepository:    mRepo,
+		threadReadRepository:      trRepo,
+		commentRepository:         cRepo,
+		policyService:             pService,
+	}
+}
+
+// threadService is the service of threads.
+type threadService struct {
+	m                         repository.DBManager
+	threadRepository          repository.ThreadRepository
+	threadModeratorRepository repository.ThreadModeratorRepository
+	threadMemberRepository    repository.ThreadMemberRepository
+	threadReadRepository      repository.ThreadReadRepository
+	commentRepository         repository.CommentRepository
+	policyService             service.PolicyService
+	txCloser                  CloseTransaction
+	now                       func() time.Time
+}
+
+// NewThreadService generates and returns ThreadService.
+func NewThreadService(m repository.DBManager, diInput ThreadServiceDIInput, txCloser CloseTransaction) ThreadService {
+	return &threadService{
+		m:                         m,
+		threadRepository:          diInput.threadRepository,
+		threadModeratorRepository: diInput.threadModeratorRepository,
+		threadMemberRepository:    diInput.threadMemberRepository,
+		threadReadRepository:      diInput.threadReadRepository,
+		commentRepository:         diInput.commentRepository,
+		policyService:             diInput.policyService,
+		txCloser:                  txCloser,
+		now:                       time.Now,
+	}
+}
+
+// ListThreads returns threads which the user can read with their unread comments, the newest first.
+// Unread comments of all threads are counted at once, so that this does not query each thread.
+func (s *threadService) ListThreads(ctx context.Context, userID uint32) ([]*model.ThreadSummary, error) {
+	threads, err := s.threadRepository.ListThreadsForUser(s.m, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list threads")
+	}
+
+	ids := make([]uint32, 0, len(threads))
+	for _, thread := range threads {
+		ids = append(ids, thread.ID)
+	}
+
+	unreads, err := s.threadReadRepository.GetThreadUnreadsByThreadIDs(s.m, userID, ids)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get unread comments of threads")
+	}
+
+	unreadByThreadID := make(map[uint32]*model.ThreadUnread, len(unreads))
+	for _, unread := range unreads {
+		unreadByThreadID[unread.ThreadID] = unread
+	}
+
+	summaries := make([]*model.ThreadSummary, 0, len(threads))
+	for _, thread := range threads {
+		summary := &model.ThreadSummary{Thread: thread}
+		if unread, ok := unreadByThreadID[thread.ID]; ok {
+			summary.UnreadCount = unread.UnreadCount
+			summary.FirstUnreadCommentID = unread.FirstUnreadCommentID
+		}
+		summaries = append(summaries, summary)
+	}
+
+	return summaries, nil
+}
+
+// GetThread returns the thread specified by id.
+// This returns NoSuchDataError if the user can not read the thread.
+func (s *threadService) GetThread(ctx context.Context, userID, id uint32) (*model.Thread, error) {
+	return getReadableThread(s.m, s.threadRepository, s.policyService, userID, id)
+}
+
+// CreateThread creates the thread by the user.
+// The creator becomes the owner and a moderator of the thread, who can delete comments and appoint other moderators.
+// Titles are unique only among public threads, so that the title of a private thread is not revealed to non-members
+// by AlreadyExistError.
+func (s *threadService) CreateThread(ctx context.Context, userID uint32, title string, visibility model.ThreadVisibility) (thread *model.Thread, err error) {
+	thread, err = model.NewThread(title, visibility, userID, s.now())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to validate thread")
+	}
+
+	if !thread.IsPrivate() {
+		if err := s.checkPublicTitle(thread.Title); err != nil {
+			return nil, err
+		}
+	}
+
+	tx, err := s.m.Begin()
+	if err != nil {
+		return nil, beginTxErrorMsg(err)
+	}
+
+	defer func() {
+		if cErr := s.txCloser(tx, err); cErr != nil {
+			err = errors.Wrap(cErr, "failed to close tx")
+		}
+	}()
+
+	id, err := s.threadRepository.InsertThread(tx, thread)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to insert thread")
+	}
+	thread.ID = id
+
+	if err := s.threadModeratorRepository.InsertThreadModerator(tx, thread.ID, userID); err != nil {
+		return nil, errors.Wrap(err, "failed to insert moderator of thread")
+	}
+
+	owner := &model.ThreadMember{
+		ThreadID:  thread.ID,
+		UserID:    userID,
+		Role:      model.ThreadMemberRoleOwner,
+		CreatedAt: thread.CreatedAt,
+	}
+	if err := s.threadMemberRepository.InsertThreadMember(tx, owner); err != nil {
+		return nil, errors.Wrap(err, "failed to insert owner of thread")
+	}
+
+	return thread, nil
+}
+
+// checkPublicTitle returns AlreadyExistError if a public thread has the title.
+func (s *threadService) checkPublicTitle(title string) error {
+	_, err := s.threadRepository.GetPublicThreadByTitle(s.m, title)
+	if err == nil {
+		return errors.WithStack(&model.AlreadyExistError{
+			PropertyNameForDeveloper:    model.TitlePropertyForDeveloper,
+			PropertyNameForUser:         model.TitlePropertyForUser,
+			PropertyValue:               title,
+			DomainModelNameForDeveloper: model.DomainModelNameThreadForDeveloper,
+			DomainModelNameForUser:      model.DomainModelNameThreadForUser,
+		})
+	}
+	if _, ok := errors.Cause(err).(*model.NoSuchDataError); !ok {
+		return errors.Wrap(err, "failed to get public thread by title")
+	}
+	return nil
+}
+
+// MarkThreadRead records that the user has read the thread up to the comment.
+// This returns NoSuchDataError if the comment is not of the thread, and does nothing to the record if newer comments are already read.
+func (s *threadService) MarkThreadRead(ctx context.Context, userID, threadID, commentID uint32) error {
+	if _, err := getReadableThread(s.m, s.threadRepository, s.policyService, userID, threadID); err != nil {
+		return err
+	}
+
+	comment, err := s.commentRepository.GetCommentByID(s.m, commentID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get comment by id")
+	}
+	if comment.ThreadID != threadID {
+		return errors.WithStack(&model.NoSuchDataError{
+			PropertyNameForDeveloper:    model.CommentIDPropertyForDeveloper,
+			PropertyNameForUser:         model.CommentIDPropertyForUser,
+			PropertyValue:               commentID,
+			DomainModelNameForDeveloper: model.DomainModelNameCommentForDeveloper,
+			DomainModelNameForUser:      model.DomainModelNameCommentForUser,
+		})
+	}
+
+	read := &model.ThreadRead{
+		ThreadID:          threadID,
+		UserID:            userID,
+		LastReadCommentID: commentID,
+		UpdatedAt:         s.now(),
+	}
+	if err := s.threadReadRepository.UpsertThreadRead(s.m, read); err != nil {
+		return errors.Wrap(err, "failed to upsert read of thread")
+	}
+
+	return nil
+}
+
+// getReadableThread returns the thread specified by id if the user can read it.
+// This returns NoSuchDataError also if the user can not read the private thread, so that it is not revealed.
+func getReadableThread(m repository.SQLManager, tRepo repository.ThreadRepository, pService service.PolicyService, userID, id uint32) (*model.Thread, error) {
+	thread, err := tRepo.GetThreadByID(m, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get thread by id")
+	}
+
+	ok, err := pService.CanReadThread(userID, thread)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to check policy")
+	}
+	if !ok {
+		return nil, errors.WithStack(&model.NoSuchDataError{
+			PropertyNameForDeveloper:    model.IDPropertyForDeveloper,
+			PropertyNameForUser:         model.IDPropertyForUser,
+			PropertyValue:               id,
+			DomainModelNameForDeveloper: model.DomainModelNameThreadForDeveloper,
+			DomainModelNameForUser:      model.DomainModelNameThreadForUser,
+		})
+	}
+
+	return thread, nil
+}
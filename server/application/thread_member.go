@@ -0,0 +1,271 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/service"
+	"github.com/hideUW/nuxt-go-chat-app/server/util"
+)
+
+// ThreadMemberService is the interface of ThreadMemberService.
+// This manages members of threads and invitations to them.
+type ThreadMemberService interface {
+	ListMembers(ctx context.Context, userID, threadID uint32) ([]*model.ThreadMember, error)
+	JoinThread(ctx context.Context, userID, threadID uint32) (*model.ThreadMember, error)
+	LeaveThread(ctx context.Context, userID, threadID uint32) error
+	CreateInvite(ctx context.Context, user *model.User, threadID uint32, ttl time.Duration, maxUses uint32) (*model.ThreadInvite, error)
+	AcceptInvite(ctx context.Context, userID uint32, token string) (*model.Thread, error)
+}
+
+// ThreadMemberServiceDIInput is DI input of ThreadMemberService.
+type ThreadMemberServiceDIInput struct {
+	threadRepository          repository.ThreadRepository
+	threadMemberRepository    repository.ThreadMemberRepository
+	threadInviteRepository    repository.ThreadInviteRepository
+	threadModeratorRepository repository.ThreadModeratorRepository
+	policyService             service.PolicyService
+}
+
+// NewThreadMemberServiceDIInput generates and returns ThreadMemberServiceDIInput.
+func NewThreadMemberServiceDIInput(tRepo repository.ThreadRepository, mRepo repository.ThreadMemberRepository, tiRepo repository.ThreadInviteRepository, tmRepo repository.ThreadModeratorRepository, pService service.PolicyService) *ThreadMemberServiceDIInput {
+	return &ThreadMemberServiceDIInput{
+		threadRepository:          tRepo,
+		threadMemberRepository:    mRepo,
+		threadInviteRepository:    tiRepo,
+		threadModeratorRepository: tmRepo,
+		policyService:             pService,
+	}
+}
+
+// threadMemberService is the service of members of threads.
+type threadMemberService struct {
+	m                         repository.DBManager
+	threadRepository          repository.ThreadRepository
+	threadMemberRepository    repository.ThreadMemberRepository
+	threadInviteRepository    repository.ThreadInviteRepository
+	threadModeratorRepository repository.ThreadModeratorRepository
+	policyService             service.PolicyService
+	txCloser                  CloseTransaction
+	now                       func() time.Time
+}
+
+// NewThreadMemberService generates and returns ThreadMemberService.
+func NewThreadMemberService(m repository.DBManager, diInput ThreadMemberServiceDIInput, txCloser CloseTransaction) ThreadMemberService {
+	return &threadMemberService{
+		m:                         m,
+		threadRepository:          diInput.threadRepository,
+		threadMemberRepository:    diInput.threadMemberRepository,
+		threadInviteRepository:    diInput.threadInviteRepository,
+		threadModeratorRepository: diInput.threadModeratorRepository,
+		policyService:             diInput.policyService,
+		txCloser:                  txCloser,
+		now:                       time.Now,
+	}
+}
+
+// ListMembers returns members of the thread in order of joining.
+// This returns NoSuchDataError if the user can not read the thread.
+func (s *threadMemberService) ListMembers(ctx context.Context, userID, threadID uint32) ([]*model.ThreadMember, error) {
+	if _, err := getReadableThread(s.m, s.threadRepository, s.policyService, userID, threadID); err != nil {
+		return nil, err
+	}
+
+	members, err := s.threadMemberRepository.GetThreadMembersByThreadID(s.m, threadID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get members of thread")
+	}
+	return members, nil
+}
+
+// JoinThread makes the user a member of the public thread.
+// The private thread is joined only by invitation, and this returns NoSuchDataError unless the user is already a member.
+func (s *threadMemberService) JoinThread(ctx context.Context, userID, threadID uint32) (*model.ThreadMember, error) {
+	if _, err := getReadableThread(s.m, s.threadRepository, s.policyService, userID, threadID); err != nil {
+		return nil, err
+	}
+
+	member, err := s.threadMemberRepository.GetThreadMember(s.m, threadID, userID)
+	if err == nil {
+		return member, nil
+	}
+	if _, ok := errors.Cause(err).(*model.NoSuchDataError); !ok {
+		return nil, errors.Wrap(err, "failed to get member of thread")
+	}
+
+	member = &model.ThreadMember{
+		ThreadID:  threadID,
+		UserID:    userID,
+		Role:      model.ThreadMemberRoleMember,
+		CreatedAt: s.now(),
+	}
+	if err := s.threadMemberRepository.InsertThreadMember(s.m, member); err != nil {
+		return nil, errors.Wrap(err, "failed to insert member of thread")
+	}
+
+	return member, nil
+}
+
+// LeaveThread removes the user from members and moderators of the thread.
+// The owner can not leave, so that the thread is not left without the owner,
+// and users of the direct conversation can not leave it either.
+func (s *threadMemberService) LeaveThread(ctx context.Context, userID, threadID uint32) (err error) {
+	thread, err := getReadableThread(s.m, s.threadRepository, s.policyService, userID, threadID)
+	if err != nil {
+		return err
+	}
+
+	if thread.IsDirect() {
+		return errors.WithStack(&model.ForbiddenError{
+			InvalidReasonForDeveloper: "can not leave the direct conversation",
+		})
+	}
+
+	member, err := s.threadMemberRepository.GetThreadMember(s.m, threadID, userID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get member of thread")
+	}
+
+	if member.Role == model.ThreadMemberRoleOwner {
+		return errors.WithStack(&model.ForbiddenError{
+			InvalidReasonForDeveloper: "owner can not leave the thread",
+		})
+	}
+
+	tx, err := s.m.Begin()
+	if err != nil {
+		return beginTxErrorMsg(err)
+	}
+
+	defer func() {
+		if cErr := s.txCloser(tx, err); cErr != nil {
+			err = errors.Wrap(cErr, "failed to close tx")
+		}
+	}()
+
+	if err := s.threadMemberRepository.DeleteThreadMember(tx, threadID, userID); err != nil {
+		return errors.Wrap(err, "failed to delete member of thread")
+	}
+
+	if err := s.threadModeratorRepository.DeleteThreadModerator(tx, threadID, userID); err != nil {
+		if _, ok := errors.Cause(err).(*model.NoSuchDataError); !ok {
+			return errors.Wrap(err, "failed to delete moderator of thread")
+		}
+	}
+
+	return nil
+}
+
+// CreateInvite creates the invitation to the thread which expires after ttl and is used up after maxUses.
+// This returns ForbiddenError if the user is neither the owner nor a moderator of the thread.
+func (s *threadMemberService) CreateInvite(ctx context.Context, user *model.User, threadID uint32, ttl time.Duration, maxUses uint32) (*model.ThreadInvite, error) {
+	if _, err := getReadableThread(s.m, s.threadRepository, s.policyService, user.ID, threadID); err != nil {
+		return nil, err
+	}
+
+	ok, err := s.policyService.CanInviteToThread(user, threadID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to check policy")
+	}
+	if !ok {
+		return nil, errors.WithStack(&model.ForbiddenError{
+			InvalidReasonForDeveloper: "only the owner or moderators of the thread can invite",
+		})
+	}
+
+	token, err := util.RandomToken(model.ThreadInviteTokenSize)
+	if err != nil {
+		return nil, errors.WithStack(&model.OtherServerError{
+			BaseErr:                   err,
+			InvalidReasonForDeveloper: "failed to generate token of invitation",
+		})
+	}
+
+	invite, err := model.NewThreadInvite(token, threadID, user.ID, ttl, maxUses, s.now())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to validate invitation")
+	}
+
+	if err := s.threadInviteRepository.InsertThreadInvite(s.m, invite); err != nil {
+		return nil, errors.Wrap(err, "failed to insert invitation")
+	}
+
+	return invite, nil
+}
+
+// AcceptInvite makes the user a member of the thread by the invitation and returns the thread.
+// This returns NoSuchDataError if the invitation does not exist, is expired or is used up.
+// Members who accept it again do not use it up.
+func (s *threadMemberService) AcceptInvite(ctx context.Context, userID uint32, token string) (thread *model.Thread, err error) {
+	invite, err := s.threadInviteRepository.GetThreadInviteByToken(s.m, token)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get invitation by token")
+	}
+
+	now := s.now()
+	if invite.IsExpired(now) {
+		if err := s.threadInviteRepository.DeleteThreadInvite(s.m, invite.ID); err != nil {
+			return nil, errors.Wrap(err, "failed to delete expired invitation")
+		}
+		return nil, errors.WithStack(noSuchThreadInviteError(invite.ID))
+	}
+
+	thread, err = s.threadRepository.GetThreadByID(s.m, invite.ThreadID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get thread by id")
+	}
+
+	if _, err := s.threadMemberRepository.GetThreadMember(s.m, thread.ID, userID); err == nil {
+		return thread, nil
+	} else if _, ok := errors.Cause(err).(*model.NoSuchDataError); !ok {
+		return nil, errors.Wrap(err, "failed to get member of thread")
+	}
+
+	if invite.IsUsedUp() {
+		return nil, errors.WithStack(noSuchThreadInviteError(invite.ID))
+	}
+
+	tx, err := s.m.Begin()
+	if err != nil {
+		return nil, beginTxErrorMsg(err)
+	}
+
+	defer func() {
+		if cErr := s.txCloser(tx, err); cErr != nil {
+			err = errors.Wrap(cErr, "failed to close tx")
+		}
+	}()
+
+	// the limit is checked again by the update, because others may use it at the same time.
+	if err := s.threadInviteRepository.UseThreadInvite(tx, invite.ID, now); err != nil {
+		return nil, errors.Wrap(err, "failed to use invitation")
+	}
+
+	member := &model.ThreadMember{
+		ThreadID:  thread.ID,
+		UserID:    userID,
+		Role:      model.ThreadMemberRoleMember,
+		CreatedAt: now,
+	}
+	if err := s.threadMemberRepository.InsertThreadMember(tx, member); err != nil {
+		return nil, errors.Wrap(err, "failed to insert member of thread")
+	}
+
+	return thread, nil
+}
+
+// noSuchThreadInviteError returns NoSuchDataError of the invitation,
+// which is the same whether it does not exist, is expired or is used up.
+func noSuchThreadInviteError(id string) *model.NoSuchDataError {
+	return &model.NoSuchDataError{
+		PropertyNameForDeveloper:    model.TokenPropertyForDeveloper,
+		PropertyNameForUser:         model.TokenPropertyForUser,
+		PropertyValue:               id,
+		DomainModelNameForDeveloper: model.DomainModelNameThreadInviteForDeveloper,
+		DomainModelNameForUser:      model.DomainModelNameThreadInviteForUser,
+	}
+}
@@ -0,0 +1,206 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+
+	mock_application "github.com/hideUW/nuxt-go-chat-app/server/application/mock"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	mock_repository "github.com/hideUW/nuxt-go-chat-app/server/domain/repository/mock"
+	mock_service "github.com/hideUW/nuxt-go-chat-app/server/domain/service/mock"
+	"github.com/hideUW/nuxt-go-chat-app/server/testutil"
+)
+
+func Test_threadMemberService_LeaveThread(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	thread := &model.Thread{ID: model.ThreadValidIDForTest, Visibility: model.ThreadVisibilityPrivate}
+
+	tests := []struct {
+		name    string
+		role    model.ThreadMemberRole
+		wantErr error
+	}{
+		{
+			name: "When the user is a member, removes the user from members and moderators",
+			role: model.ThreadMemberRoleMember,
+		},
+		{
+			name:    "When the user is the owner, returns ForbiddenError",
+			role:    model.ThreadMemberRoleOwner,
+			wantErr: &model.ForbiddenError{InvalidReasonForDeveloper: "owner can not leave the thread"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock_repository.NewMockDBManager(ctrl)
+			tx := mock_repository.NewMockTxManager(ctrl)
+			tr := mock_repository.NewMockThreadRepository(ctrl)
+			mr := mock_repository.NewMockThreadMemberRepository(ctrl)
+			tmr := mock_repository.NewMockThreadModeratorRepository(ctrl)
+			ps := mock_service.NewMockPolicyService(ctrl)
+
+			tr.EXPECT().GetThreadByID(m, model.ThreadValidIDForTest).Return(thread, nil)
+			ps.EXPECT().CanReadThread(model.UserValidIDForTest, thread).Return(true, nil)
+			mr.EXPECT().GetThreadMember(m, model.ThreadValidIDForTest, model.UserValidIDForTest).Return(&model.ThreadMember{
+				ThreadID: model.ThreadValidIDForTest,
+				UserID:   model.UserValidIDForTest,
+				Role:     tt.role,
+			}, nil)
+			if tt.wantErr == nil {
+				m.EXPECT().Begin().Return(tx, nil)
+				mr.EXPECT().DeleteThreadMember(tx, model.ThreadValidIDForTest, model.UserValidIDForTest).Return(nil)
+				tmr.EXPECT().DeleteThreadModerator(tx, model.ThreadValidIDForTest, model.UserValidIDForTest).Return(&model.NoSuchDataError{})
+			}
+
+			s := &threadMemberService{
+				m:                         m,
+				threadRepository:          tr,
+				threadMemberRepository:    mr,
+				threadModeratorRepository: tmr,
+				policyService:             ps,
+				txCloser:                  mock_application.MockCloseTransaction,
+			}
+
+			err := s.LeaveThread(ctx, model.UserValidIDForTest, model.ThreadValidIDForTest)
+			if tt.wantErr != nil {
+				if err == nil || errors.Cause(err).Error() != tt.wantErr.Error() {
+					t.Errorf("threadMemberService.LeaveThread() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("threadMemberService.LeaveThread() error = %v", err)
+			}
+		})
+	}
+}
+
+func Test_threadMemberService_AcceptInvite(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testutil.SetFakeTime(time.Now())
+	defer testutil.ResetFakeTime()
+
+	ctx := context.Background()
+	id := model.ThreadInviteIDFromToken(model.ThreadInviteTokenForTest)
+	thread := &model.Thread{ID: model.ThreadValidIDForTest, Visibility: model.ThreadVisibilityPrivate}
+	noSuchInvite := noSuchThreadInviteError(id)
+
+	tests := []struct {
+		name       string
+		expiresAt  time.Time
+		maxUses    uint32
+		uses       uint32
+		isMember   bool
+		useErr     error
+		wantJoin   bool
+		wantDelete bool
+		wantErr    error
+	}{
+		{
+			name:      "When the invitation is valid, makes the user a member",
+			expiresAt: testutil.TimeNow().Add(time.Hour),
+			maxUses:   2,
+			uses:      1,
+			wantJoin:  true,
+		},
+		{
+			name:       "When the invitation is expired, deletes it and returns NoSuchDataError",
+			expiresAt:  testutil.TimeNow(),
+			wantDelete: true,
+			wantErr:    noSuchInvite,
+		},
+		{
+			name:      "When the invitation is used up, returns NoSuchDataError",
+			expiresAt: testutil.TimeNow().Add(time.Hour),
+			maxUses:   1,
+			uses:      1,
+			wantErr:   noSuchInvite,
+		},
+		{
+			name:      "When the user is already a member, returns the thread without using the invitation",
+			expiresAt: testutil.TimeNow().Add(time.Hour),
+			maxUses:   1,
+			uses:      1,
+			isMember:  true,
+		},
+		{
+			name:      "When the invitation is used up by others at the same time, returns NoSuchDataError",
+			expiresAt: testutil.TimeNow().Add(time.Hour),
+			maxUses:   1,
+			useErr:    noSuchInvite,
+			wantErr:   noSuchInvite,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock_repository.NewMockDBManager(ctrl)
+			tx := mock_repository.NewMockTxManager(ctrl)
+			tr := mock_repository.NewMockThreadRepository(ctrl)
+			mr := mock_repository.NewMockThreadMemberRepository(ctrl)
+			tir := mock_repository.NewMockThreadInviteRepository(ctrl)
+
+			tir.EXPECT().GetThreadInviteByToken(m, model.ThreadInviteTokenForTest).Return(&model.ThreadInvite{
+				ID:        id,
+				Token:     model.ThreadInviteTokenForTest,
+				ThreadID:  model.ThreadValidIDForTest,
+				MaxUses:   tt.maxUses,
+				Uses:      tt.uses,
+				ExpiresAt: tt.expiresAt,
+			}, nil)
+			if tt.wantDelete {
+				tir.EXPECT().DeleteThreadInvite(m, id).Return(nil)
+			} else {
+				tr.EXPECT().GetThreadByID(m, model.ThreadValidIDForTest).Return(thread, nil)
+				if tt.isMember {
+					mr.EXPECT().GetThreadMember(m, model.ThreadValidIDForTest, model.UserValidIDForTest).Return(&model.ThreadMember{}, nil)
+				} else {
+					mr.EXPECT().GetThreadMember(m, model.ThreadValidIDForTest, model.UserValidIDForTest).Return(nil, &model.NoSuchDataError{})
+				}
+			}
+			if tt.wantJoin || tt.useErr != nil {
+				m.EXPECT().Begin().Return(tx, nil)
+				tir.EXPECT().UseThreadInvite(tx, id, testutil.TimeNow()).Return(tt.useErr)
+			}
+			if tt.wantJoin {
+				mr.EXPECT().InsertThreadMember(tx, &model.ThreadMember{
+					ThreadID:  model.ThreadValidIDForTest,
+					UserID:    model.UserValidIDForTest,
+					Role:      model.ThreadMemberRoleMember,
+					CreatedAt: testutil.TimeNow(),
+				}).Return(nil)
+			}
+
+			s := &threadMemberService{
+				m:                      m,
+				threadRepository:       tr,
+				threadMemberRepository: mr,
+				threadInviteRepository: tir,
+				txCloser:               mock_application.MockCloseTransaction,
+				now:                    testutil.TimeNow,
+			}
+
+			got, err := s.AcceptInvite(ctx, model.UserValidIDForTest, model.ThreadInviteTokenForTest)
+			if tt.wantErr != nil {
+				if err == nil || errors.Cause(err).Error() != tt.wantErr.Error() {
+					t.Errorf("threadMemberService.AcceptInvite() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("threadMemberService.AcceptInvite() error = %v", err)
+			}
+			if got != thread {
+				testutil.Errorf(t, thread, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,307 @@
+package application
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+
+	mock_application "github.com/hideUW/nuxt-go-chat-app/server/application/mock"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	mock_repository "github.com/hideUW/nuxt-go-chat-app/server/domain/repository/mock"
+	mock_service "github.com/hideUW/nuxt-go-chat-app/server/domain/service/mock"
+	"github.com/hideUW/nuxt-go-chat-app/server/testutil"
+)
+
+func Test_threadService_CreateThread(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testutil.SetFakeTime(time.Now())
+	defer testutil.ResetFakeTime()
+
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		title      string
+		visibility model.ThreadVisibility
+		stored     *model.Thread
+		storedErr  error
+		wantErr    error
+	}{
+		{
+			name:       "When the title is not used, creates the thread and makes the creator its owner and moderator",
+			title:      model.ThreadTitleForTest,
+			visibility: model.ThreadVisibilityPublic,
+			storedErr:  &model.NoSuchDataError{},
+		},
+		{
+			name:       "When the title is used only by a private thread, creates the public thread",
+			title:      model.ThreadTitleForTest,
+			visibility: model.ThreadVisibilityPublic,
+			// private threads are never returned by the title.
+			storedErr: &model.NoSuchDataError{},
+		},
+		{
+			name:       "When a non-member creates a private thread with the title of a private thread, creates it without checking the title",
+			title:      model.ThreadTitleForTest,
+			visibility: model.ThreadVisibilityPrivate,
+		},
+		{
+			name:       "When the title is already used by a public thread, returns AlreadyExistError",
+			title:      model.ThreadTitleForTest,
+			visibility: model.ThreadVisibilityPublic,
+			stored:     &model.Thread{ID: model.ThreadValidIDForTest, Title: model.ThreadTitleForTest, Visibility: model.ThreadVisibilityPublic},
+			wantErr: &model.AlreadyExistError{
+				PropertyNameForDeveloper:    model.TitlePropertyForDeveloper,
+				PropertyNameForUser:         model.TitlePropertyForUser,
+				PropertyValue:               model.ThreadTitleForTest,
+				DomainModelNameForDeveloper: model.DomainModelNameThreadForDeveloper,
+				DomainModelNameForUser:      model.DomainModelNameThreadForUser,
+			},
+		},
+		{
+			name:       "When the title is empty, returns RequiredError",
+			title:      " ",
+			visibility: model.ThreadVisibilityPublic,
+			wantErr: &model.RequiredError{
+				PropertyNameForDeveloper: model.TitlePropertyForDeveloper,
+				PropertyNameForUser:      model.TitlePropertyForUser,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock_repository.NewMockDBManager(ctrl)
+			tx := mock_repository.NewMockTxManager(ctrl)
+			tr := mock_repository.NewMockThreadRepository(ctrl)
+			tmr := mock_repository.NewMockThreadModeratorRepository(ctrl)
+			mr := mock_repository.NewMockThreadMemberRepository(ctrl)
+
+			if tt.stored != nil || tt.storedErr != nil {
+				tr.EXPECT().GetPublicThreadByTitle(m, tt.title).Return(tt.stored, tt.storedErr)
+			}
+			if tt.wantErr == nil {
+				m.EXPECT().Begin().Return(tx, nil)
+				gomock.InOrder(
+					tr.EXPECT().InsertThread(tx, &model.Thread{
+						Title:      tt.title,
+						Visibility: tt.visibility,
+						UserID:     model.UserValidIDForTest,
+						CreatedAt:  testutil.TimeNow(),
+						UpdatedAt:  testutil.TimeNow(),
+					}).Return(model.ThreadValidIDForTest, nil),
+					tmr.EXPECT().InsertThreadModerator(tx, model.ThreadValidIDForTest, model.UserValidIDForTest).Return(nil),
+					mr.EXPECT().InsertThreadMember(tx, &model.ThreadMember{
+						ThreadID:  model.ThreadValidIDForTest,
+						UserID:    model.UserValidIDForTest,
+						Role:      model.ThreadMemberRoleOwner,
+						CreatedAt: testutil.TimeNow(),
+					}).Return(nil),
+				)
+			}
+
+			s := &threadService{
+				m:                         m,
+				threadRepository:          tr,
+				threadModeratorRepository: tmr,
+				threadMemberRepository:    mr,
+				txCloser:                  mock_application.MockCloseTransaction,
+				now:                       testutil.TimeNow,
+			}
+
+			got, err := s.CreateThread(ctx, model.UserValidIDForTest, tt.title, tt.visibility)
+			if tt.wantErr != nil {
+				if err == nil || errors.Cause(err).Error() != tt.wantErr.Error() {
+					t.Errorf("threadService.CreateThread() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("threadService.CreateThread() error = %v", err)
+			}
+			if got.ID != model.ThreadValidIDForTest {
+				testutil.Errorf(t, model.ThreadValidIDForTest, got.ID)
+			}
+		})
+	}
+}
+
+func Test_threadService_GetThread(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	thread := &model.Thread{ID: model.ThreadValidIDForTest, Visibility: model.ThreadVisibilityPrivate}
+
+	tests := []struct {
+		name    string
+		canRead bool
+		wantErr error
+	}{
+		{
+			name:    "When the user is a member of the private thread, returns the thread",
+			canRead: true,
+		},
+		{
+			name:    "When the user is not a member of the private thread, returns NoSuchDataError to hide it",
+			canRead: false,
+			wantErr: &model.NoSuchDataError{
+				PropertyNameForDeveloper:    model.IDPropertyForDeveloper,
+				PropertyNameForUser:         model.IDPropertyForUser,
+				PropertyValue:               model.ThreadValidIDForTest,
+				DomainModelNameForDeveloper: model.DomainModelNameThreadForDeveloper,
+				DomainModelNameForUser:      model.DomainModelNameThreadForUser,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock_repository.NewMockDBManager(ctrl)
+			tr := mock_repository.NewMockThreadRepository(ctrl)
+			ps := mock_service.NewMockPolicyService(ctrl)
+
+			tr.EXPECT().GetThreadByID(m, model.ThreadValidIDForTest).Return(thread, nil)
+			ps.EXPECT().CanReadThread(model.UserValidIDForTest, thread).Return(tt.canRead, nil)
+
+			s := &threadService{
+				m:                m,
+				threadRepository: tr,
+				policyService:    ps,
+			}
+
+			got, err := s.GetThread(ctx, model.UserValidIDForTest, model.ThreadValidIDForTest)
+			if tt.wantErr != nil {
+				if err == nil || errors.Cause(err).Error() != tt.wantErr.Error() {
+					t.Errorf("threadService.GetThread() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("threadService.GetThread() error = %v", err)
+			}
+			if got != thread {
+				testutil.Errorf(t, thread, got)
+			}
+		})
+	}
+}
+
+func Test_threadService_ListThreads(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	threads := []*model.Thread{{ID: 2}, {ID: 1}}
+
+	m := mock_repository.NewMockDBManager(ctrl)
+	tr := mock_repository.NewMockThreadRepository(ctrl)
+	trr := mock_repository.NewMockThreadReadRepository(ctrl)
+
+	tr.EXPECT().ListThreadsForUser(m, model.UserValidIDForTest).Return(threads, nil)
+	// unread comments of all threads are got by one call.
+	trr.EXPECT().GetThreadUnreadsByThreadIDs(m, model.UserValidIDForTest, []uint32{2, 1}).Return([]*model.ThreadUnread{
+		{ThreadID: 1, UnreadCount: 3, FirstUnreadCommentID: 10},
+	}, nil)
+
+	s := &threadService{
+		m:                    m,
+		threadRepository:     tr,
+		threadReadRepository: trr,
+	}
+
+	got, err := s.ListThreads(ctx, model.UserValidIDForTest)
+	if err != nil {
+		t.Fatalf("threadService.ListThreads() error = %v", err)
+	}
+
+	want := []*model.ThreadSummary{
+		{Thread: threads[0]},
+		{Thread: threads[1], UnreadCount: 3, FirstUnreadCommentID: 10},
+	}
+	if !reflect.DeepEqual(got, want) {
+		testutil.Errorf(t, want, got)
+	}
+}
+
+func Test_threadService_MarkThreadRead(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testutil.SetFakeTime(time.Now())
+	defer testutil.ResetFakeTime()
+
+	ctx := context.Background()
+	thread := &model.Thread{ID: model.ThreadValidIDForTest, Visibility: model.ThreadVisibilityPublic}
+	var otherThreadID uint32 = 2
+
+	tests := []struct {
+		name     string
+		threadID uint32
+		wantErr  error
+	}{
+		{
+			name:     "When the comment is of the thread, records the read",
+			threadID: model.ThreadValidIDForTest,
+		},
+		{
+			name:     "When the comment is of another thread, returns NoSuchDataError",
+			threadID: otherThreadID,
+			wantErr: &model.NoSuchDataError{
+				PropertyNameForDeveloper:    model.CommentIDPropertyForDeveloper,
+				PropertyNameForUser:         model.CommentIDPropertyForUser,
+				PropertyValue:               model.CommentValidIDForTest,
+				DomainModelNameForDeveloper: model.DomainModelNameCommentForDeveloper,
+				DomainModelNameForUser:      model.DomainModelNameCommentForUser,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock_repository.NewMockDBManager(ctrl)
+			tr := mock_repository.NewMockThreadRepository(ctrl)
+			cr := mock_repository.NewMockCommentRepository(ctrl)
+			trr := mock_repository.NewMockThreadReadRepository(ctrl)
+			ps := mock_service.NewMockPolicyService(ctrl)
+
+			tr.EXPECT().GetThreadByID(m, tt.threadID).Return(thread, nil)
+			ps.EXPECT().CanReadThread(model.UserValidIDForTest, thread).Return(true, nil)
+			cr.EXPECT().GetCommentByID(m, model.CommentValidIDForTest).Return(&model.Comment{
+				ID:       model.CommentValidIDForTest,
+				ThreadID: model.ThreadValidIDForTest,
+			}, nil)
+			if tt.wantErr == nil {
+				trr.EXPECT().UpsertThreadRead(m, &model.ThreadRead{
+					ThreadID:          model.ThreadValidIDForTest,
+					UserID:            model.UserValidIDForTest,
+					LastReadCommentID: model.CommentValidIDForTest,
+					UpdatedAt:         testutil.TimeNow(),
+				}).Return(nil)
+			}
+
+			s := &threadService{
+				m:                    m,
+				threadRepository:     tr,
+				commentRepository:    cr,
+				threadReadRepository: trr,
+				policyService:        ps,
+				now:                  testutil.TimeNow,
+			}
+
+			err := s.MarkThreadRead(ctx, model.UserValidIDForTest, tt.threadID, model.CommentValidIDForTest)
+			if tt.wantErr != nil {
+				if err == nil || errors.Cause(err).Error() != tt.wantErr.Error() {
+					t.Errorf("threadService.MarkThreadRead() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("threadService.MarkThreadRead() error = %v", err)
+			}
+		})
+	}
+}
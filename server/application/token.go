@@ -0,0 +1,233 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/service"
+	"github.com/hideUW/nuxt-go-chat-app/server/util"
+)
+
+// Lifetime of tokens.
+// Access token is short-lived because it can not be revoked.
+const (
+	AccessTokenTTL  = 15 * time.Minute
+	RefreshTokenTTL = 30 * 24 * time.Hour
+)
+
+// TokenService is the interface of TokenService.
+// This issues tokens for clients which can not keep cookie, as an alternative to session.
+type TokenService interface {
+	IssueTokens(ctx context.Context, param *model.User, code string, client *model.Client) (*model.TokenPair, error)
+	RefreshTokens(ctx context.Context, refreshToken string) (*model.TokenPair, error)
+	RevokeRefreshToken(ctx context.Context, refreshToken string) error
+	GetAccessTokenUser(ctx context.Context, accessToken string) (*model.User, error)
+	PublicKeys(ctx context.Context) []*model.PublicKey
+}
+
+// TokenServiceDIInput is DI input of TokenService.
+type TokenServiceDIInput struct {
+	authenticationService  AuthenticationService
+	userRepository         repository.UserRepository
+	refreshTokenRepository repository.RefreshTokenRepository
+	accessTokenService     service.AccessTokenService
+}
+
+// NewTokenServiceDIInput generates and returns TokenServiceDIInput.
+func NewTokenServiceDIInput(aApp AuthenticationService, uRepo repository.UserRepository, rtRepo repository.RefreshTokenRepository, atService service.AccessTokenService) *TokenServiceDIInput {
+	return &TokenServiceDIInput{
+		authenticationService:  aApp,
+		userRepository:         uRepo,
+		refreshTokenRepository: rtRepo,
+		accessTokenService:     atService,
+	}
+}
+
+// tokenService is the service of access token and refresh token.
+type tokenService struct {
+	m                      repository.DBManager
+	authenticationService  AuthenticationService
+	userRepository         repository.UserRepository
+	refreshTokenRepository repository.RefreshTokenRepository
+	accessTokenService     service.AccessTokenService
+	txCloser               CloseTransaction
+	now                    func() time.Time
+}
+
+// NewTokenService generates and returns TokenService.
+func NewTokenService(m repository.DBManager, diInput TokenServiceDIInput, txCloser CloseTransaction) TokenService {
+	return &tokenService{
+		m:                      m,
+		authenticationService:  diInput.authenticationService,
+		userRepository:         diInput.userRepository,
+		refreshTokenRepository: diInput.refreshTokenRepository,
+		accessTokenService:     diInput.accessTokenService,
+		txCloser:               txCloser,
+		now:                    time.Now,
+	}
+}
+
+// IssueTokens checks name and password of an user and issues tokens.
+// The code of the second factor is required at the same time if TOTP of the user is enabled.
+// Failures are throttled in the same way as login.
+func (s *tokenService) IssueTokens(ctx context.Context, param *model.User, code string, client *model.Client) (*model.TokenPair, error) {
+	user, err := s.authenticationService.Authenticate(ctx, param, client)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authenticationService.VerifySecondFactor(ctx, user, code); err != nil {
+		return nil, err
+	}
+
+	return s.issue(s.m, user.ID)
+}
+
+// RefreshTokens issues new tokens by the refresh token.
+// The refresh token is rotated, so that it can be used only once.
+// The rotation is checked by deleting it in the tx, because concurrent requests may read the same token.
+func (s *tokenService) RefreshTokens(ctx context.Context, refreshToken string) (pair *model.TokenPair, err error) {
+	old, err := s.getRefreshToken(refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// the user may have been deleted or disabled after the refresh token was issued.
+	user, err := s.userRepository.GetUserByID(s.m, old.UserID)
+	if err != nil {
+		if _, ok := errors.Cause(err).(*model.NoSuchDataError); ok {
+			return nil, errors.WithStack(&model.AuthenticationErr{BaseErr: err})
+		}
+		return nil, errors.Wrap(err, "failed to get user by id")
+	}
+
+	if user.Disabled {
+		return nil, errors.WithStack(&model.AuthenticationErr{})
+	}
+
+	tx, err := s.m.Begin()
+	if err != nil {
+		return nil, beginTxErrorMsg(err)
+	}
+
+	defer func() {
+		if cErr := s.txCloser(tx, err); cErr != nil {
+			err = errors.Wrap(cErr, "failed to close tx")
+		}
+	}()
+
+	if err := s.refreshTokenRepository.UseRefreshToken(tx, old.ID, s.now()); err != nil {
+		if _, ok := errors.Cause(err).(*model.NoSuchDataError); ok {
+			return nil, errors.WithStack(&model.AuthenticationErr{BaseErr: err})
+		}
+		return nil, errors.Wrap(err, "failed to use refresh token")
+	}
+
+	return s.issue(tx, old.UserID)
+}
+
+// RevokeRefreshToken deletes the refresh token.
+// This returns nil if the refresh token does not exist.
+func (s *tokenService) RevokeRefreshToken(ctx context.Context, refreshToken string) error {
+	id := model.RefreshTokenIDFromToken(refreshToken)
+	if err := s.refreshTokenRepository.DeleteRefreshToken(s.m, id); err != nil {
+		return errors.Wrap(err, "failed to delete refresh token")
+	}
+	return nil
+}
+
+// GetAccessTokenUser verifies the access token and returns its user.
+// This returns AuthenticationErr if the token is invalid or the user does not exist or is disabled.
+func (s *tokenService) GetAccessTokenUser(ctx context.Context, accessToken string) (*model.User, error) {
+	claims, err := s.accessTokenService.Verify(accessToken)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to verify access token")
+	}
+
+	user, err := s.userRepository.GetUserByID(s.m, claims.UserID)
+	if err != nil {
+		if _, ok := errors.Cause(err).(*model.NoSuchDataError); ok {
+			return nil, errors.WithStack(&model.AuthenticationErr{BaseErr: err})
+		}
+		return nil, errors.Wrap(err, "failed to get user by id")
+	}
+
+	if user.Disabled {
+		return nil, errors.WithStack(&model.AuthenticationErr{})
+	}
+
+	return user, nil
+}
+
+// PublicKeys returns public keys which verify access tokens.
+func (s *tokenService) PublicKeys(ctx context.Context) []*model.PublicKey {
+	return s.accessTokenService.PublicKeys()
+}
+
+// getRefreshToken returns the refresh token which is not expired.
+// This returns AuthenticationErr if the refresh token does not exist or is expired.
+func (s *tokenService) getRefreshToken(token string) (*model.RefreshToken, error) {
+	refreshToken, err := s.refreshTokenRepository.GetRefreshTokenByToken(s.m, token)
+	if err != nil {
+		if _, ok := errors.Cause(err).(*model.NoSuchDataError); ok {
+			return nil, errors.WithStack(&model.AuthenticationErr{BaseErr: err})
+		}
+		return nil, errors.Wrap(err, "failed to get refresh token by token")
+	}
+
+	if refreshToken.IsExpired(s.now()) {
+		if err := s.refreshTokenRepository.DeleteRefreshToken(s.m, refreshToken.ID); err != nil {
+			return nil, errors.Wrap(err, "failed to delete expired refresh token")
+		}
+		return nil, errors.WithStack(&model.AuthenticationErr{})
+	}
+
+	return refreshToken, nil
+}
+
+// issue issues an access token and a refresh token of the user.
+func (s *tokenService) issue(m repository.SQLManager, userID uint32) (*model.TokenPair, error) {
+	now := s.now()
+
+	token, err := util.RandomToken(model.RefreshTokenSize)
+	if err != nil {
+		return nil, errors.WithStack(&model.OtherServerError{
+			BaseErr:                   err,
+			InvalidReasonForDeveloper: "failed to generate refresh token",
+		})
+	}
+
+	refreshToken := &model.RefreshToken{
+		ID:        model.RefreshTokenIDFromToken(token),
+		Token:     token,
+		UserID:    userID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(RefreshTokenTTL),
+	}
+	if err := s.refreshTokenRepository.InsertRefreshToken(m, refreshToken); err != nil {
+		return nil, errors.Wrap(err, "failed to insert refresh token")
+	}
+
+	claims := &model.AccessTokenClaims{
+		UserID:    userID,
+		IssuedAt:  now,
+		ExpiresAt: now.Add(AccessTokenTTL),
+	}
+	accessToken, err := s.accessTokenService.Sign(claims)
+	if err != nil {
+		return nil, errors.WithStack(&model.OtherServerError{
+			BaseErr:                   err,
+			InvalidReasonForDeveloper: "failed to sign access token",
+		})
+	}
+
+	return &model.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken.Token,
+		ExpiresAt:    claims.ExpiresAt,
+	}, nil
+}
@@ -0,0 +1,125 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+
+	mock_application "github.com/hideUW/nuxt-go-chat-app/server/application/mock"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	mock_repository "github.com/hideUW/nuxt-go-chat-app/server/domain/repository/mock"
+	mock_service "github.com/hideUW/nuxt-go-chat-app/server/domain/service/mock"
+	"github.com/hideUW/nuxt-go-chat-app/server/testutil"
+)
+
+func Test_tokenService_RefreshTokens(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testutil.SetFakeTime(time.Now())
+
+	const refreshToken = "testRefreshToken"
+	const accessToken = "testAccessToken"
+
+	tests := []struct {
+		name      string
+		expiresAt time.Time
+		storedErr error
+		usedErr   error
+		wantErr   bool
+	}{
+		{
+			name:      "When the refresh token is valid, rotates it and returns new tokens",
+			expiresAt: testutil.TimeNow().Add(time.Second),
+		},
+		{
+			name:      "When the refresh token is expired, deletes it and returns AuthenticationErr",
+			expiresAt: testutil.TimeNow(),
+			wantErr:   true,
+		},
+		{
+			name:      "When the refresh token does not exist, returns AuthenticationErr",
+			storedErr: &model.NoSuchDataError{},
+			wantErr:   true,
+		},
+		{
+			name:      "When the refresh token has been rotated by another request, returns AuthenticationErr",
+			expiresAt: testutil.TimeNow().Add(time.Second),
+			usedErr:   &model.NoSuchDataError{},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock_repository.NewMockDBManager(ctrl)
+			ur := mock_repository.NewMockUserRepository(ctrl)
+			rtr := mock_repository.NewMockRefreshTokenRepository(ctrl)
+			ats := mock_service.NewMockAccessTokenService(ctrl)
+
+			stored := &model.RefreshToken{
+				ID:        model.RefreshTokenIDFromToken(refreshToken),
+				Token:     refreshToken,
+				UserID:    model.UserValidIDForTest,
+				ExpiresAt: tt.expiresAt,
+			}
+			if tt.storedErr != nil {
+				rtr.EXPECT().GetRefreshTokenByToken(m, refreshToken).Return(nil, tt.storedErr)
+			} else {
+				rtr.EXPECT().GetRefreshTokenByToken(m, refreshToken).Return(stored, nil)
+			}
+
+			switch {
+			case tt.storedErr != nil:
+			case tt.usedErr != nil:
+				tx := mock_repository.NewMockTxManager(ctrl)
+				m.EXPECT().Begin().Return(tx, nil)
+				ur.EXPECT().GetUserByID(m, model.UserValidIDForTest).Return(&model.User{ID: model.UserValidIDForTest}, nil)
+				rtr.EXPECT().UseRefreshToken(tx, stored.ID, testutil.TimeNow()).Return(tt.usedErr)
+			case tt.wantErr:
+				rtr.EXPECT().DeleteRefreshToken(m, stored.ID).Return(nil)
+			default:
+				tx := mock_repository.NewMockTxManager(ctrl)
+				m.EXPECT().Begin().Return(tx, nil)
+				ur.EXPECT().GetUserByID(m, model.UserValidIDForTest).Return(&model.User{ID: model.UserValidIDForTest}, nil)
+				rtr.EXPECT().UseRefreshToken(tx, stored.ID, testutil.TimeNow()).Return(nil)
+				rtr.EXPECT().InsertRefreshToken(tx, gomock.Any()).DoAndReturn(func(_ interface{}, rt *model.RefreshToken) error {
+					if rt.ID != model.RefreshTokenIDFromToken(rt.Token) || rt.Token == refreshToken {
+						t.Errorf("InsertRefreshToken() is called with %v, want new token stored as hash", rt)
+					}
+					return nil
+				})
+				ats.EXPECT().Sign(&model.AccessTokenClaims{
+					UserID:    model.UserValidIDForTest,
+					IssuedAt:  testutil.TimeNow(),
+					ExpiresAt: testutil.TimeNow().Add(AccessTokenTTL),
+				}).Return(accessToken, nil)
+			}
+
+			s := &tokenService{
+				m:                      m,
+				userRepository:         ur,
+				refreshTokenRepository: rtr,
+				accessTokenService:     ats,
+				txCloser:               mock_application.MockCloseTransaction,
+				now:                    testutil.TimeNow,
+			}
+
+			got, err := s.RefreshTokens(context.Background(), refreshToken)
+			if tt.wantErr {
+				if _, ok := errors.Cause(err).(*model.AuthenticationErr); !ok {
+					t.Errorf("tokenService.RefreshTokens() error = %v, want AuthenticationErr", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("tokenService.RefreshTokens() error = %v", err)
+			}
+			if got.AccessToken != accessToken || got.RefreshToken == refreshToken {
+				t.Errorf("tokenService.RefreshTokens() = %v, want new tokens", got)
+			}
+		})
+	}
+}
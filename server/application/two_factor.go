@@ -0,0 +1,291 @@
+package application
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/service"
+)
+
+// TwoFactorService is the interface of TwoFactorService.
+// This manages TOTP of the user, which is required at login after it is enabled.
+type TwoFactorService interface {
+	StartTOTPEnrollment(ctx context.Context, userID uint32) (*model.TOTP, string, error)
+	ConfirmTOTPEnrollment(ctx context.Context, userID uint32, code string) ([]string, error)
+	DisableTOTP(ctx context.Context, userID uint32, code string) error
+}
+
+// TwoFactorServiceDIInput is DI input of TwoFactorService.
+type TwoFactorServiceDIInput struct {
+	userRepository  repository.UserRepository
+	totpRepository  repository.TOTPRepository
+	totpService     service.TOTPService
+	throttleService service.ThrottleService
+}
+
+// NewTwoFactorServiceDIInput generates and returns TwoFactorServiceDIInput.
+func NewTwoFactorServiceDIInput(uRepo repository.UserRepository, totpRepo repository.TOTPRepository, totpService service.TOTPService, tService service.ThrottleService) *TwoFactorServiceDIInput {
+	return &TwoFactorServiceDIInput{
+		userRepository:  uRepo,
+		totpRepository:  totpRepo,
+		totpService:     totpService,
+		throttleService: tService,
+	}
+}
+
+// twoFactorService is the service of two-factor authentication.
+type twoFactorService struct {
+	m               repository.DBManager
+	userRepository  repository.UserRepository
+	totpRepository  repository.TOTPRepository
+	totpService     service.TOTPService
+	throttleService service.ThrottleService
+	txCloser        CloseTransaction
+	now             func() time.Time
+}
+
+// NewTwoFactorService generates and returns TwoFactorService.
+func NewTwoFactorService(m repository.DBManager, diInput TwoFactorServiceDIInput, txCloser CloseTransaction) TwoFactorService {
+	return &twoFactorService{
+		m:               m,
+		userRepository:  diInput.userRepository,
+		totpRepository:  diInput.totpRepository,
+		totpService:     diInput.totpService,
+		throttleService: diInput.throttleService,
+		txCloser:        txCloser,
+		now:             time.Now,
+	}
+}
+
+// StartTOTPEnrollment generates a new secret of the user and returns it with its provisioning URI.
+// The secret is not used at login until it is confirmed by a code, so that the user is not locked out by a wrong registration.
+// This returns AlreadyExistError if TOTP of the user is already enabled.
+func (s *twoFactorService) StartTOTPEnrollment(ctx context.Context, userID uint32) (*model.TOTP, string, error) {
+	user, err := s.userRepository.GetUserByID(s.m, userID)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to get user by id")
+	}
+
+	current, err := getEnabledTOTP(s.m, s.totpRepository, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	if current != nil {
+		return nil, "", errors.WithStack(totpAlreadyEnabledError(userID))
+	}
+
+	secret, err := s.totpService.GenerateSecret()
+	if err != nil {
+		return nil, "", errors.WithStack(&model.OtherServerError{
+			BaseErr:                   err,
+			InvalidReasonForDeveloper: "failed to generate TOTP secret",
+		})
+	}
+
+	totp := &model.TOTP{
+		UserID:    userID,
+		Secret:    secret,
+		CreatedAt: s.now(),
+	}
+	if err := s.totpRepository.SaveTOTP(s.m, totp); err != nil {
+		return nil, "", errors.Wrap(err, "failed to save TOTP")
+	}
+
+	return totp, s.totpService.ProvisioningURI(secret, user.Name), nil
+}
+
+// ConfirmTOTPEnrollment enables TOTP of the user if the code is valid, and returns new recovery codes.
+// Recovery codes are returned only here and only their hashes are stored.
+func (s *twoFactorService) ConfirmTOTPEnrollment(ctx context.Context, userID uint32, code string) (codes []string, err error) {
+	totp, err := s.totpRepository.GetTOTPByUserID(s.m, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get TOTP by user id")
+	}
+	if totp.Enabled {
+		return nil, errors.WithStack(totpAlreadyEnabledError(userID))
+	}
+
+	if err := verifySecondFactor(ctx, s.m, s.totpRepository, s.totpService, s.throttleService, totp, code); err != nil {
+		return nil, err
+	}
+
+	codes, err = s.totpService.GenerateRecoveryCodes()
+	if err != nil {
+		return nil, errors.WithStack(&model.OtherServerError{
+			BaseErr:                   err,
+			InvalidReasonForDeveloper: "failed to generate recovery codes",
+		})
+	}
+
+	ids := make([]string, 0, len(codes))
+	for _, c := range codes {
+		ids = append(ids, model.RecoveryCodeIDFromCode(c))
+	}
+
+	tx, err := s.m.Begin()
+	if err != nil {
+		return nil, beginTxErrorMsg(err)
+	}
+
+	defer func() {
+		if cErr := s.txCloser(tx, err); cErr != nil {
+			err = errors.Wrap(cErr, "failed to close tx")
+		}
+	}()
+
+	totp.Enabled = true
+	if err := s.totpRepository.SaveTOTP(tx, totp); err != nil {
+		return nil, errors.Wrap(err, "failed to save TOTP")
+	}
+
+	if err := s.totpRepository.DeleteRecoveryCodesByUserID(tx, userID); err != nil {
+		return nil, errors.Wrap(err, "failed to delete recovery codes")
+	}
+
+	if err := s.totpRepository.InsertRecoveryCodes(tx, userID, ids); err != nil {
+		return nil, errors.Wrap(err, "failed to insert recovery codes")
+	}
+
+	return codes, nil
+}
+
+// DisableTOTP deletes TOTP and recovery codes of the user if the code is valid.
+// A recovery code is also accepted, so that the user who lost the authenticator can disable it.
+func (s *twoFactorService) DisableTOTP(ctx context.Context, userID uint32, code string) (err error) {
+	totp, err := getEnabledTOTP(s.m, s.totpRepository, userID)
+	if err != nil {
+		return err
+	}
+	if totp == nil {
+		return errors.WithStack(&model.NoSuchDataError{
+			PropertyNameForDeveloper:    model.UserIDPropertyForDeveloper,
+			PropertyNameForUser:         model.UserIDPropertyForUser,
+			PropertyValue:               userID,
+			DomainModelNameForDeveloper: model.DomainModelNameTOTPForDeveloper,
+			DomainModelNameForUser:      model.DomainModelNameTOTPForUser,
+		})
+	}
+
+	if err := verifySecondFactor(ctx, s.m, s.totpRepository, s.totpService, s.throttleService, totp, code); err != nil {
+		return err
+	}
+
+	tx, err := s.m.Begin()
+	if err != nil {
+		return beginTxErrorMsg(err)
+	}
+
+	defer func() {
+		if cErr := s.txCloser(tx, err); cErr != nil {
+			err = errors.Wrap(cErr, "failed to close tx")
+		}
+	}()
+
+	if err := s.totpRepository.DeleteTOTP(tx, userID); err != nil {
+		return errors.Wrap(err, "failed to delete TOTP")
+	}
+
+	if err := s.totpRepository.DeleteRecoveryCodesByUserID(tx, userID); err != nil {
+		return errors.Wrap(err, "failed to delete recovery codes")
+	}
+
+	return nil
+}
+
+// getEnabledTOTP returns TOTP of the user if it is enabled, and nil otherwise.
+func getEnabledTOTP(m repository.SQLManager, totpRepo repository.TOTPRepository, userID uint32) (*model.TOTP, error) {
+	totp, err := totpRepo.GetTOTPByUserID(m, userID)
+	if err != nil {
+		if _, ok := errors.Cause(err).(*model.NoSuchDataError); ok {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to get TOTP by user id")
+	}
+
+	if !totp.Enabled {
+		return nil, nil
+	}
+	return totp, nil
+}
+
+// verifySecondFactor checks the TOTP code or a recovery code of the user.
+// This returns AuthenticationErr if the code is wrong or already used.
+// Failures are throttled per user apart from password, so that 6 digits can not be guessed by logging in again and again.
+func verifySecondFactor(ctx context.Context, m repository.SQLManager, totpRepo repository.TOTPRepository, totpService service.TOTPService, tService service.ThrottleService, totp *model.TOTP, code string) error {
+	key := model.NewThrottleKey(model.ThrottleKindSecondFactor, strconv.FormatUint(uint64(totp.UserID), 10))
+	reservation, err := tService.Reserve(ctx, key)
+	if err != nil {
+		return errors.Wrap(err, "failed to pass throttle")
+	}
+
+	ok, err := useSecondFactor(m, totpRepo, totpService, totp, code)
+	if err != nil {
+		if rErr := tService.Release(ctx, reservation); rErr != nil {
+			return errors.Wrap(rErr, "failed to release throttle")
+		}
+		return err
+	}
+
+	// the reserved attempt is left as failure, and forgotten by reset on success.
+	if !ok {
+		return errors.WithStack(&model.AuthenticationErr{})
+	}
+
+	if err := tService.Reset(ctx, key); err != nil {
+		return errors.Wrap(err, "failed to reset throttle")
+	}
+
+	return nil
+}
+
+// useSecondFactor consumes the code and returns whether it is valid.
+// A TOTP code is consumed by its step and a recovery code is deleted, so that neither can be used twice.
+func useSecondFactor(m repository.SQLManager, totpRepo repository.TOTPRepository, totpService service.TOTPService, totp *model.TOTP, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+
+	if len(code) == model.TOTPDigits {
+		step, ok := totpService.Verify(totp.Secret, code, totp.LastUsedStep)
+		if !ok {
+			return false, nil
+		}
+
+		if err := totpRepo.UseTOTPStep(m, totp.UserID, step); err != nil {
+			if _, ok := errors.Cause(err).(*model.NoSuchDataError); ok {
+				return false, nil
+			}
+			return false, errors.Wrap(err, "failed to use TOTP step")
+		}
+		totp.LastUsedStep = step
+		return true, nil
+	}
+
+	// recovery codes exist only while TOTP is enabled.
+	if !totp.Enabled {
+		return false, nil
+	}
+
+	if err := totpRepo.DeleteRecoveryCode(m, totp.UserID, model.RecoveryCodeIDFromCode(code)); err != nil {
+		if _, ok := errors.Cause(err).(*model.NoSuchDataError); ok {
+			return false, nil
+		}
+		return false, errors.Wrap(err, "failed to delete recovery code")
+	}
+	return true, nil
+}
+
+// totpAlreadyEnabledError returns the error that TOTP of the user is already enabled.
+func totpAlreadyEnabledError(userID uint32) *model.AlreadyExistError {
+	return &model.AlreadyExistError{
+		PropertyNameForDeveloper:    model.UserIDPropertyForDeveloper,
+		PropertyNameForUser:         model.UserIDPropertyForUser,
+		PropertyValue:               userID,
+		DomainModelNameForDeveloper: model.DomainModelNameTOTPForDeveloper,
+		DomainModelNameForUser:      model.DomainModelNameTOTPForUser,
+	}
+}
@@ -0,0 +1,123 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+
+	mock_application "github.com/hideUW/nuxt-go-chat-app/server/application/mock"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	mock_repository "github.com/hideUW/nuxt-go-chat-app/server/domain/repository/mock"
+	mock_service "github.com/hideUW/nuxt-go-chat-app/server/domain/service/mock"
+	"github.com/hideUW/nuxt-go-chat-app/server/testutil"
+)
+
+func Test_twoFactorService_ConfirmTOTPEnrollment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testutil.SetFakeTime(time.Unix(59, 0))
+	defer testutil.ResetFakeTime()
+
+	ctx := context.Background()
+	key := model.NewThrottleKey(model.ThrottleKindSecondFactor, "1")
+	recoveryCodes := []string{model.RecoveryCodeForTest, "qrst-uvwx-yz23-4567"}
+
+	tests := []struct {
+		name       string
+		storedTOTP *model.TOTP
+		code       string
+		valid      bool
+		wantCodes  []string
+		wantErr    error
+	}{
+		{
+			name:       "When the code is valid, enables TOTP and returns recovery codes",
+			storedTOTP: &model.TOTP{UserID: model.UserValidIDForTest, Secret: model.TOTPSecretForTest},
+			code:       model.TOTPCodeForTest,
+			valid:      true,
+			wantCodes:  recoveryCodes,
+		},
+		{
+			name:       "When the code is wrong, returns AuthenticationErr",
+			storedTOTP: &model.TOTP{UserID: model.UserValidIDForTest, Secret: model.TOTPSecretForTest},
+			code:       "000000",
+			wantErr:    &model.AuthenticationErr{},
+		},
+		{
+			name:       "When the code looks like recovery code before enabled, returns AuthenticationErr",
+			storedTOTP: &model.TOTP{UserID: model.UserValidIDForTest, Secret: model.TOTPSecretForTest},
+			code:       model.RecoveryCodeForTest,
+			wantErr:    &model.AuthenticationErr{},
+		},
+		{
+			name:       "When TOTP is already enabled, returns AlreadyExistError",
+			storedTOTP: &model.TOTP{UserID: model.UserValidIDForTest, Secret: model.TOTPSecretForTest, Enabled: true},
+			code:       model.TOTPCodeForTest,
+			wantErr:    totpAlreadyEnabledError(model.UserValidIDForTest),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock_repository.NewMockDBManager(ctrl)
+			tx := mock_repository.NewMockTxManager(ctrl)
+			totpr := mock_repository.NewMockTOTPRepository(ctrl)
+			th := mock_service.NewMockThrottleService(ctrl)
+			ts := mock_service.NewMockTOTPService(ctrl)
+
+			totpr.EXPECT().GetTOTPByUserID(m, model.UserValidIDForTest).Return(tt.storedTOTP, nil)
+			if !tt.storedTOTP.Enabled {
+				th.EXPECT().Reserve(ctx, key).Return(&model.ThrottleReservation{Keys: []model.ThrottleKey{key}}, nil)
+				if len(tt.code) == model.TOTPDigits {
+					ts.EXPECT().Verify(model.TOTPSecretForTest, tt.code, int64(0)).Return(int64(1), tt.valid)
+				}
+			}
+			if tt.valid {
+				totpr.EXPECT().UseTOTPStep(m, model.UserValidIDForTest, int64(1)).Return(nil)
+				th.EXPECT().Reset(ctx, key).Return(nil)
+				ts.EXPECT().GenerateRecoveryCodes().Return(recoveryCodes, nil)
+				m.EXPECT().Begin().Return(tx, nil)
+				gomock.InOrder(
+					totpr.EXPECT().SaveTOTP(tx, &model.TOTP{
+						UserID:       model.UserValidIDForTest,
+						Secret:       model.TOTPSecretForTest,
+						Enabled:      true,
+						LastUsedStep: 1,
+					}).Return(nil),
+					totpr.EXPECT().DeleteRecoveryCodesByUserID(tx, model.UserValidIDForTest).Return(nil),
+					// only hashes of recovery codes are stored.
+					totpr.EXPECT().InsertRecoveryCodes(tx, model.UserValidIDForTest, []string{
+						model.RecoveryCodeIDFromCode(recoveryCodes[0]),
+						model.RecoveryCodeIDFromCode(recoveryCodes[1]),
+					}).Return(nil),
+				)
+			}
+
+			s := &twoFactorService{
+				m:               m,
+				totpRepository:  totpr,
+				totpService:     ts,
+				throttleService: th,
+				txCloser:        mock_application.MockCloseTransaction,
+				now:             testutil.TimeNow,
+			}
+
+			got, err := s.ConfirmTOTPEnrollment(ctx, model.UserValidIDForTest, tt.code)
+			if tt.wantErr != nil {
+				if err == nil || errors.Cause(err).Error() != tt.wantErr.Error() {
+					t.Errorf("twoFactorService.ConfirmTOTPEnrollment() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("twoFactorService.ConfirmTOTPEnrollment() error = %v", err)
+			}
+			if len(got) != len(tt.wantCodes) {
+				testutil.Errorf(t, tt.wantCodes, got)
+			}
+		})
+	}
+}
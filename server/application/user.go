@@ -1,25 +1,389 @@
 package application
 
 import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
 	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
 	"github.com/hideUW/nuxt-go-chat-app/server/domain/service"
+	"github.com/hideUW/nuxt-go-chat-app/server/util"
 )
 
-// UserService is an interface.
+// UserService is the interface of UserService.
 type UserService interface {
+	GetMe(ctx context.Context, id uint32) (*model.User, error)
+	ChangeName(ctx context.Context, id uint32, name string) (*model.User, error)
+	ChangePassword(ctx context.Context, id uint32, token, oldPassword, newPassword string) error
+	DeleteAccount(ctx context.Context, id uint32) error
+}
+
+// UserServiceDIInput is DI input of UserService.
+type UserServiceDIInput struct {
+	userRepository            repository.UserRepository
+	sessionRepository         repository.SessionRepository
+	refreshTokenRepository    repository.RefreshTokenRepository
+	apiKeyRepository          repository.APIKeyRepository
+	identityRepository        repository.IdentityRepository
+	totpRepository            repository.TOTPRepository
+	userTokenRepository       repository.UserTokenRepository
+	roleRepository            repository.RoleRepository
+	threadRepository          repository.ThreadRepository
+	threadModeratorRepository repository.ThreadModeratorRepository
+	threadMemberRepository    repository.ThreadMemberRepository
+	threadInviteRepository    repository.ThreadInviteRepository
+	userBlockRepository       repository.UserBlockRepository
+	threadReadRepository      repository.ThreadReadRepository
+	commentReactionRepository repository.CommentReactionRepository
+	commentMentionRepository  repository.CommentMentionRepository
+	notificationRepository    repository.NotificationRepository
+	userService               service.UserService
+	throttleService           service.ThrottleService
+}
+
+// NewUserServiceDIInput generates and returns UserServiceDIInput.
+func NewUserServiceDIInput(uRepo repository.UserRepository, sRepo repository.SessionRepository, rtRepo repository.RefreshTokenRepository, akRepo repository.APIKeyRepository, iRepo repository.IdentityRepository, totpRepo repository.TOTPRepository, utRepo repository.UserTokenRepository, rRepo repository.RoleRepository, thRepo repository.ThreadRepository, tmRepo repository.ThreadModeratorRepository, mRepo repository.ThreadMemberRepository, tiRepo repository.ThreadInviteRepository, ubRepo repository.UserBlockRepository, trRepo repository.ThreadReadRepository, crRepo repository.CommentReactionRepository, cmRepo repository.CommentMentionRepository, nRepo repository.NotificationRepository, uService service.UserService, tService service.ThrottleService) *UserServiceDIInput {
+	return &UserServiceDIInput{
+		userRepository:            uRepo,
+		sessionRepository:         sRepo,
+		refreshTokenRepository:    rtRepo,
+		apiKeyRepository:          akRepo,
+		identityRepository:        iRepo,
+		totpRepository:            totpRepo,
+		userTokenRepository:       utRepo,
+		roleRepository:            rRepo,
+		threadRepository:          thRepo,
+		threadModeratorRepository: tmRepo,
+		threadMemberRepository:    mRepo,
+		threadInviteRepository:    tiRepo,
+		userBlockRepository:       ubRepo,
+		threadReadRepository:      trRepo,
+		commentReactionRepository: crRepo,
+		commentMentionRepository:  cmRepo,
+		notificationRepository:    nRepo,
+		userService:               uService,
+		throttleService:           tService,
+	}
 }
 
+// userService is the service of user account.
 type userService struct {
-	m        repository.DBManager
-	uFactory service.UserRepoFactory
-	txCloser CloseTransaction
+	m                         repository.DBManager
+	userRepository            repository.UserRepository
+	sessionRepository         repository.SessionRepository
+	refreshTokenRepository    repository.RefreshTokenRepository
+	apiKeyRepository          repository.APIKeyRepository
+	identityRepository        repository.IdentityRepository
+	totpRepository            repository.TOTPRepository
+	userTokenRepository       repository.UserTokenRepository
+	roleRepository            repository.RoleRepository
+	threadRepository          repository.ThreadRepository
+	threadModeratorRepository repository.ThreadModeratorRepository
+	threadMemberRepository    repository.ThreadMemberRepository
+	threadInviteRepository    repository.ThreadInviteRepository
+	userBlockRepository       repository.UserBlockRepository
+	threadReadRepository      repository.ThreadReadRepository
+	commentReactionRepository repository.CommentReactionRepository
+	commentMentionRepository  repository.CommentMentionRepository
+	notificationRepository    repository.NotificationRepository
+	userService               service.UserService
+	throttleService           service.ThrottleService
+	txCloser                  CloseTransaction
 }
 
-// NewUserService creates an interface called UserService and returns it.
-func NewUserService(m repository.DBManager, f service.UserRepoFactory, txCloser CloseTransaction) UserService {
+// NewUserService generates and returns UserService.
+func NewUserService(m repository.DBManager, diInput UserServiceDIInput, txCloser CloseTransaction) UserService {
 	return &userService{
-		m:        m,
-		uFactory: f,
-		txCloser: txCloser,
+		m:                         m,
+		userRepository:            diInput.userRepository,
+		sessionRepository:         diInput.sessionRepository,
+		refreshTokenRepository:    diInput.refreshTokenRepository,
+		apiKeyRepository:          diInput.apiKeyRepository,
+		identityRepository:        diInput.identityRepository,
+		totpRepository:            diInput.totpRepository,
+		userTokenRepository:       diInput.userTokenRepository,
+		roleRepository:            diInput.roleRepository,
+		threadRepository:          diInput.threadRepository,
+		threadModeratorRepository: diInput.threadModeratorRepository,
+		threadMemberRepository:    diInput.threadMemberRepository,
+		threadInviteRepository:    diInput.threadInviteRepository,
+		userBlockRepository:       diInput.userBlockRepository,
+		threadReadRepository:      diInput.threadReadRepository,
+		commentReactionRepository: diInput.commentReactionRepository,
+		commentMentionRepository:  diInput.commentMentionRepository,
+		notificationRepository:    diInput.notificationRepository,
+		userService:               diInput.userService,
+		throttleService:           diInput.throttleService,
+		txCloser:                  txCloser,
+	}
+}
+
+// GetMe returns the user specified by id.
+func (s *userService) GetMe(ctx context.Context, id uint32) (*model.User, error) {
+	user, err := s.userRepository.GetUserByID(s.m, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get user by id")
+	}
+	return user, nil
+}
+
+// ChangeName changes the name of the user.
+func (s *userService) ChangeName(ctx context.Context, id uint32, name string) (user *model.User, err error) {
+	if err := model.ValidateUserName(name); err != nil {
+		return nil, errors.Wrap(err, "failed to validate name")
+	}
+
+	user, err = s.userRepository.GetUserByID(s.m, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get user by id")
+	}
+
+	if user.Name == name {
+		return user, nil
+	}
+
+	if err := checkNameNotExist(ctx, s.userService, name); err != nil {
+		return nil, errors.Wrap(err, "failed to check name")
+	}
+
+	tx, err := s.m.Begin()
+	if err != nil {
+		return nil, beginTxErrorMsg(err)
+	}
+
+	defer func() {
+		if cErr := s.txCloser(tx, err); cErr != nil {
+			err = errors.Wrap(cErr, "failed to close tx")
+		}
+	}()
+
+	user.Name = name
+	user.UpdatedAt = time.Now()
+	if err := s.userRepository.UpdateUser(tx, user.ID, user); err != nil {
+		return nil, errors.Wrap(err, "failed to update user")
+	}
+
+	return user, nil
+}
+
+// ChangePassword verifies the old password and changes it to the new one.
+// Sessions other than the one which has the token and all refresh tokens are revoked,
+// so that the one who knows the old password is logged out. All sessions are revoked if the token is empty.
+func (s *userService) ChangePassword(ctx context.Context, id uint32, token, oldPassword, newPassword string) (err error) {
+	if err := model.ValidatePassword(newPassword); err != nil {
+		return errors.Wrap(err, "failed to validate new password")
+	}
+
+	user, err := s.userRepository.GetUserByID(s.m, id)
+	if err != nil {
+		return errors.Wrap(err, "failed to get user by id")
+	}
+
+	// guessing old password is throttled in the same way as login.
+	nameKey := model.NewThrottleKey(model.ThrottleKindLoginName, user.Name)
+	if _, err := s.throttleService.Reserve(ctx, nameKey); err != nil {
+		return errors.Wrap(err, "failed to pass throttle")
+	}
+
+	// the reserved attempt is left as failure, and forgotten by reset on success.
+	if !util.CheckHashOfPassword(oldPassword, user.Password) {
+		return errors.WithStack(&model.AuthenticationErr{})
+	}
+
+	if err := s.throttleService.Reset(ctx, nameKey); err != nil {
+		return errors.Wrap(err, "failed to reset throttle")
+	}
+
+	hashed, err := util.HashPassword(newPassword)
+	if err != nil {
+		return errors.Wrap(err, "failed to hash password")
+	}
+
+	tx, err := s.m.Begin()
+	if err != nil {
+		return beginTxErrorMsg(err)
+	}
+
+	defer func() {
+		if cErr := s.txCloser(tx, err); cErr != nil {
+			err = errors.Wrap(cErr, "failed to close tx")
+		}
+	}()
+
+	user.Password = hashed
+	user.UpdatedAt = time.Now()
+	if err := s.userRepository.UpdateUser(tx, user.ID, user); err != nil {
+		return errors.Wrap(err, "failed to update user")
+	}
+
+	if token == "" {
+		if err := s.sessionRepository.DeleteSessionsByUserID(tx, user.ID); err != nil {
+			return errors.Wrap(err, "failed to delete sessions")
+		}
+	} else if err := s.sessionRepository.DeleteOtherSessions(tx, user.ID, model.SessionIDFromToken(token)); err != nil {
+		return errors.Wrap(err, "failed to delete other sessions")
+	}
+
+	if err := s.refreshTokenRepository.DeleteRefreshTokensByUserID(tx, user.ID); err != nil {
+		return errors.Wrap(err, "failed to delete refresh tokens")
+	}
+
+	return nil
+}
+
+// DeleteAccount deletes the user and all sessions, refresh tokens, API keys, identities and TOTP of the user.
+// Threads the user owns are passed to their oldest other members.
+func (s *userService) DeleteAccount(ctx context.Context, id uint32) (err error) {
+	tx, err := s.m.Begin()
+	if err != nil {
+		return beginTxErrorMsg(err)
+	}
+
+	defer func() {
+		if cErr := s.txCloser(tx, err); cErr != nil {
+			err = errors.Wrap(cErr, "failed to close tx")
+		}
+	}()
+
+	if err := s.sessionRepository.DeleteSessionsByUserID(tx, id); err != nil {
+		return errors.Wrap(err, "failed to delete sessions")
 	}
+
+	if err := s.refreshTokenRepository.DeleteRefreshTokensByUserID(tx, id); err != nil {
+		return errors.Wrap(err, "failed to delete refresh tokens")
+	}
+
+	if err := s.apiKeyRepository.DeleteAPIKeysByUserID(tx, id); err != nil {
+		return errors.Wrap(err, "failed to delete API keys")
+	}
+
+	if err := s.identityRepository.DeleteIdentitiesByUserID(tx, id); err != nil {
+		return errors.Wrap(err, "failed to delete identities")
+	}
+
+	if err := s.totpRepository.DeleteTOTP(tx, id); err != nil {
+		return errors.Wrap(err, "failed to delete TOTP")
+	}
+
+	if err := s.totpRepository.DeleteRecoveryCodesByUserID(tx, id); err != nil {
+		return errors.Wrap(err, "failed to delete recovery codes")
+	}
+
+	if err := s.userTokenRepository.DeleteUserTokensByUserID(tx, id); err != nil {
+		return errors.Wrap(err, "failed to delete user tokens")
+	}
+
+	if err := s.roleRepository.DeleteRolesByUserID(tx, id); err != nil {
+		return errors.Wrap(err, "failed to delete roles")
+	}
+
+	if err := s.transferOwnedThreads(tx, id); err != nil {
+		return err
+	}
+
+	if err := s.threadModeratorRepository.DeleteThreadModeratorsByUserID(tx, id); err != nil {
+		return errors.Wrap(err, "failed to delete moderators of threads")
+	}
+
+	if err := s.threadMemberRepository.DeleteThreadMembersByUserID(tx, id); err != nil {
+		return errors.Wrap(err, "failed to delete members of threads")
+	}
+
+	if err := s.threadInviteRepository.DeleteThreadInvitesByUserID(tx, id); err != nil {
+		return errors.Wrap(err, "failed to delete invitations to threads")
+	}
+
+	if err := s.userBlockRepository.DeleteUserBlocksByUserID(tx, id); err != nil {
+		return errors.Wrap(err, "failed to delete blocks")
+	}
+
+	if err := s.threadReadRepository.DeleteThreadReadsByUserID(tx, id); err != nil {
+		return errors.Wrap(err, "failed to delete reads of threads")
+	}
+
+	if err := s.commentReactionRepository.DeleteCommentReactionsByUserID(tx, id); err != nil {
+		return errors.Wrap(err, "failed to delete reactions to comments")
+	}
+
+	if err := s.commentMentionRepository.DeleteCommentMentionsByUserID(tx, id); err != nil {
+		return errors.Wrap(err, "failed to delete mentions")
+	}
+
+	if err := s.notificationRepository.DeleteNotificationsByUserID(tx, id); err != nil {
+		return errors.Wrap(err, "failed to delete notifications")
+	}
+
+	if err := s.userRepository.DeleteUser(tx, id); err != nil {
+		return errors.Wrap(err, "failed to delete user")
+	}
+
+	return nil
+}
+
+// transferOwnedThreads passes each thread the user owns to its oldest other member, who also becomes a moderator,
+// so that private threads are still managed after the owner leaves.
+// Threads without other members are left as they are, because nobody else can read them.
+func (s *userService) transferOwnedThreads(m repository.SQLManager, userID uint32) error {
+	memberships, err := s.threadMemberRepository.GetThreadMembersByUserID(m, userID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get memberships of threads")
+	}
+
+	for _, membership := range memberships {
+		if membership.Role != model.ThreadMemberRoleOwner {
+			continue
+		}
+
+		members, err := s.threadMemberRepository.GetThreadMembersByThreadID(m, membership.ThreadID)
+		if err != nil {
+			return errors.Wrap(err, "failed to get members of thread")
+		}
+
+		for _, member := range members {
+			if member.UserID == userID {
+				continue
+			}
+
+			if err := s.threadMemberRepository.UpdateThreadMemberRole(m, member.ThreadID, member.UserID, model.ThreadMemberRoleOwner); err != nil {
+				return errors.Wrap(err, "failed to update role of member")
+			}
+
+			if err := s.threadRepository.UpdateThreadOwner(m, member.ThreadID, member.UserID); err != nil {
+				return errors.Wrap(err, "failed to update owner of thread")
+			}
+
+			if err := s.threadModeratorRepository.InsertThreadModerator(m, member.ThreadID, member.UserID); err != nil {
+				return errors.Wrap(err, "failed to insert moderator")
+			}
+			break
+		}
+	}
+
+	return nil
+}
+
+// checkNameNotExist returns AlreadyExistError if the user which has the name exists.
+func checkNameNotExist(ctx context.Context, uService service.UserService, name string) error {
+	yes, err := uService.IsAlreadyExistName(ctx, name)
+	if yes {
+		err = &model.AlreadyExistError{
+			PropertyNameForDeveloper:    model.NamePropertyForDeveloper,
+			PropertyNameForUser:         model.NamePropertyForUser,
+			PropertyValue:               name,
+			DomainModelNameForDeveloper: model.DomainModelNameUserForDeveloper,
+			DomainModelNameForUser:      model.DomainModelNameUserForUser,
+		}
+
+		return errors.Wrap(err, "failed to check whether already exists name or not")
+	}
+
+	if err != nil {
+		if _, ok := errors.Cause(err).(*model.NoSuchDataError); !ok {
+			return errors.Wrap(err, "failed to check whether already exists name or not")
+		}
+	}
+
+	return nil
 }
@@ -0,0 +1,390 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+
+	mock_application "github.com/hideUW/nuxt-go-chat-app/server/application/mock"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+	mock_repository "github.com/hideUW/nuxt-go-chat-app/server/domain/repository/mock"
+	mock_service "github.com/hideUW/nuxt-go-chat-app/server/domain/service/mock"
+	"github.com/hideUW/nuxt-go-chat-app/server/testutil"
+	"github.com/hideUW/nuxt-go-chat-app/server/util"
+)
+
+func Test_userService_ChangeName(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testutil.SetFakeTime(time.Now())
+
+	const newName = "newUserName"
+
+	tests := []struct {
+		name       string
+		newName    string
+		found      bool
+		wantUpdate bool
+		wantErr    error
+	}{
+		{
+			name:       "When the name is not used, updates name",
+			newName:    newName,
+			wantUpdate: true,
+		},
+		{
+			name:    "When the name is used by another user, returns AlreadyExistError",
+			newName: newName,
+			found:   true,
+			wantErr: &model.AlreadyExistError{
+				PropertyNameForDeveloper:    model.NamePropertyForDeveloper,
+				PropertyNameForUser:         model.NamePropertyForUser,
+				PropertyValue:               newName,
+				DomainModelNameForDeveloper: model.DomainModelNameUserForDeveloper,
+				DomainModelNameForUser:      model.DomainModelNameUserForUser,
+			},
+		},
+		{
+			name:    "When the name is same as current one, returns user without update",
+			newName: model.UserNameForTest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			m := mock_repository.NewMockDBManager(ctrl)
+			ur := mock_repository.NewMockUserRepository(ctrl)
+			us := mock_service.NewMockUserService(ctrl)
+
+			ur.EXPECT().GetUserByID(m, model.UserValidIDForTest).Return(&model.User{
+				ID:   model.UserValidIDForTest,
+				Name: model.UserNameForTest,
+			}, nil)
+			if tt.newName != model.UserNameForTest {
+				us.EXPECT().IsAlreadyExistName(ctx, tt.newName).Return(tt.found, nil)
+			}
+			if tt.wantUpdate {
+				tx := mock_repository.NewMockTxManager(ctrl)
+				m.EXPECT().Begin().Return(tx, nil)
+				ur.EXPECT().UpdateUser(tx, model.UserValidIDForTest, gomock.Any()).Return(nil)
+			}
+
+			s := &userService{
+				m:              m,
+				userRepository: ur,
+				userService:    us,
+				txCloser:       mock_application.MockCloseTransaction,
+			}
+
+			got, err := s.ChangeName(ctx, model.UserValidIDForTest, tt.newName)
+			if tt.wantErr != nil {
+				if err == nil || errors.Cause(err).Error() != tt.wantErr.Error() {
+					t.Errorf("userService.ChangeName() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("userService.ChangeName() error = %v", err)
+			}
+			if got.Name != tt.newName {
+				t.Errorf("userService.ChangeName() Name = %v, want %v", got.Name, tt.newName)
+			}
+		})
+	}
+}
+
+func Test_userService_ChangePassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	hashed, err := util.HashPassword(model.PasswordForTest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const newPassword = "newTestPassword"
+	nameKey := model.NewThrottleKey(model.ThrottleKindLoginName, model.UserNameForTest)
+
+	tests := []struct {
+		name        string
+		token       string
+		oldPassword string
+		newPassword string
+		wantFailure bool
+		wantUpdate  bool
+		wantErr     error
+	}{
+		{
+			name:        "When old password is valid, updates password and revokes other sessions",
+			token:       model.SessionTokenForTest,
+			oldPassword: model.PasswordForTest,
+			newPassword: newPassword,
+			wantUpdate:  true,
+		},
+		{
+			name:        "When the request has no session, updates password and revokes all sessions",
+			token:       "",
+			oldPassword: model.PasswordForTest,
+			newPassword: newPassword,
+			wantUpdate:  true,
+		},
+		{
+			name:        "When old password is wrong, records failure and returns AuthenticationErr",
+			token:       model.SessionTokenForTest,
+			oldPassword: "wrongPassword",
+			newPassword: newPassword,
+			wantFailure: true,
+			wantErr:     &model.AuthenticationErr{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			m := mock_repository.NewMockDBManager(ctrl)
+			ur := mock_repository.NewMockUserRepository(ctrl)
+			sr := mock_repository.NewMockSessionRepository(ctrl)
+			rtr := mock_repository.NewMockRefreshTokenRepository(ctrl)
+			th := mock_service.NewMockThrottleService(ctrl)
+
+			ur.EXPECT().GetUserByID(m, model.UserValidIDForTest).Return(&model.User{
+				ID:       model.UserValidIDForTest,
+				Name:     model.UserNameForTest,
+				Password: hashed,
+			}, nil)
+			th.EXPECT().Reserve(ctx, nameKey).Return(&model.ThrottleReservation{Keys: []model.ThrottleKey{nameKey}}, nil)
+			if tt.wantUpdate {
+				tx := mock_repository.NewMockTxManager(ctrl)
+				m.EXPECT().Begin().Return(tx, nil)
+				th.EXPECT().Reset(ctx, nameKey).Return(nil)
+				ur.EXPECT().UpdateUser(tx, model.UserValidIDForTest, gomock.Any()).DoAndReturn(func(_ interface{}, _ uint32, user *model.User) error {
+					if !util.CheckHashOfPassword(tt.newPassword, user.Password) {
+						t.Errorf("UpdateUser() is called with password which is not hash of new password")
+					}
+					return nil
+				})
+				if tt.token == "" {
+					sr.EXPECT().DeleteSessionsByUserID(tx, model.UserValidIDForTest).Return(nil)
+				} else {
+					sr.EXPECT().DeleteOtherSessions(tx, model.UserValidIDForTest, model.SessionIDFromToken(tt.token)).Return(nil)
+				}
+				rtr.EXPECT().DeleteRefreshTokensByUserID(tx, model.UserValidIDForTest).Return(nil)
+			}
+
+			s := &userService{
+				m:                      m,
+				userRepository:         ur,
+				sessionRepository:      sr,
+				refreshTokenRepository: rtr,
+				throttleService:        th,
+				txCloser:               mock_application.MockCloseTransaction,
+			}
+
+			err := s.ChangePassword(ctx, model.UserValidIDForTest, tt.token, tt.oldPassword, tt.newPassword)
+			if tt.wantErr != nil {
+				if err == nil || errors.Cause(err).Error() != tt.wantErr.Error() {
+					t.Errorf("userService.ChangePassword() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("userService.ChangePassword() error = %v", err)
+			}
+		})
+	}
+}
+
+func Test_userService_DeleteAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	m := mock_repository.NewMockDBManager(ctrl)
+	ur := mock_repository.NewMockUserRepository(ctrl)
+	sr := mock_repository.NewMockSessionRepository(ctrl)
+	rtr := mock_repository.NewMockRefreshTokenRepository(ctrl)
+	akr := mock_repository.NewMockAPIKeyRepository(ctrl)
+	ir := mock_repository.NewMockIdentityRepository(ctrl)
+	totpr := mock_repository.NewMockTOTPRepository(ctrl)
+	utr := mock_repository.NewMockUserTokenRepository(ctrl)
+	rr := mock_repository.NewMockRoleRepository(ctrl)
+	tmr := mock_repository.NewMockThreadModeratorRepository(ctrl)
+	mr := mock_repository.NewMockThreadMemberRepository(ctrl)
+	tir := mock_repository.NewMockThreadInviteRepository(ctrl)
+	ubr := mock_repository.NewMockUserBlockRepository(ctrl)
+	trr := mock_repository.NewMockThreadReadRepository(ctrl)
+	crr := mock_repository.NewMockCommentReactionRepository(ctrl)
+	cmr := mock_repository.NewMockCommentMentionRepository(ctrl)
+	nr := mock_repository.NewMockNotificationRepository(ctrl)
+	tx := mock_repository.NewMockTxManager(ctrl)
+
+	var closedErr error
+	closed := false
+	m.EXPECT().Begin().Return(tx, nil)
+	gomock.InOrder(
+		sr.EXPECT().DeleteSessionsByUserID(tx, model.UserValidIDForTest).Return(nil),
+		rtr.EXPECT().DeleteRefreshTokensByUserID(tx, model.UserValidIDForTest).Return(nil),
+		akr.EXPECT().DeleteAPIKeysByUserID(tx, model.UserValidIDForTest).Return(nil),
+		ir.EXPECT().DeleteIdentitiesByUserID(tx, model.UserValidIDForTest).Return(nil),
+		totpr.EXPECT().DeleteTOTP(tx, model.UserValidIDForTest).Return(nil),
+		totpr.EXPECT().DeleteRecoveryCodesByUserID(tx, model.UserValidIDForTest).Return(nil),
+		utr.EXPECT().DeleteUserTokensByUserID(tx, model.UserValidIDForTest).Return(nil),
+		rr.EXPECT().DeleteRolesByUserID(tx, model.UserValidIDForTest).Return(nil),
+		mr.EXPECT().GetThreadMembersByUserID(tx, model.UserValidIDForTest).Return([]*model.ThreadMember{}, nil),
+		tmr.EXPECT().DeleteThreadModeratorsByUserID(tx, model.UserValidIDForTest).Return(nil),
+		mr.EXPECT().DeleteThreadMembersByUserID(tx, model.UserValidIDForTest).Return(nil),
+		tir.EXPECT().DeleteThreadInvitesByUserID(tx, model.UserValidIDForTest).Return(nil),
+		ubr.EXPECT().DeleteUserBlocksByUserID(tx, model.UserValidIDForTest).Return(nil),
+		trr.EXPECT().DeleteThreadReadsByUserID(tx, model.UserValidIDForTest).Return(nil),
+		crr.EXPECT().DeleteCommentReactionsByUserID(tx, model.UserValidIDForTest).Return(nil),
+		cmr.EXPECT().DeleteCommentMentionsByUserID(tx, model.UserValidIDForTest).Return(nil),
+		nr.EXPECT().DeleteNotificationsByUserID(tx, model.UserValidIDForTest).Return(nil),
+		ur.EXPECT().DeleteUser(tx, model.UserValidIDForTest).Return(errors.New(model.ErrorMessageForTest)),
+	)
+
+	s := &userService{
+		m:                         m,
+		userRepository:            ur,
+		sessionRepository:         sr,
+		refreshTokenRepository:    rtr,
+		apiKeyRepository:          akr,
+		identityRepository:        ir,
+		totpRepository:            totpr,
+		userTokenRepository:       utr,
+		roleRepository:            rr,
+		threadModeratorRepository: tmr,
+		threadMemberRepository:    mr,
+		threadInviteRepository:    tir,
+		userBlockRepository:       ubr,
+		threadReadRepository:      trr,
+		commentReactionRepository: crr,
+		commentMentionRepository:  cmr,
+		notificationRepository:    nr,
+		txCloser: func(_ repository.TxManager, err error) error {
+			closed = true
+			closedErr = err
+			return nil
+		},
+	}
+
+	// sessions, refresh tokens, API keys, identities and TOTP are deleted in the same tx which is rolled back when deleting user fails.
+	if err := s.DeleteAccount(ctx, model.UserValidIDForTest); err == nil {
+		t.Error("userService.DeleteAccount() error = nil, want error")
+	}
+	if !closed || closedErr == nil {
+		t.Errorf("tx should be closed with error, closed = %v, err = %v", closed, closedErr)
+	}
+}
+
+func Test_userService_DeleteAccount_ownedThreads(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	const (
+		ownerOnlyThreadID uint32 = 2
+		joinedThreadID    uint32 = 3
+		oldestMemberID    uint32 = 2
+		newerMemberID     uint32 = 3
+	)
+
+	ctx := context.Background()
+	m := mock_repository.NewMockDBManager(ctrl)
+	ur := mock_repository.NewMockUserRepository(ctrl)
+	sr := mock_repository.NewMockSessionRepository(ctrl)
+	rtr := mock_repository.NewMockRefreshTokenRepository(ctrl)
+	akr := mock_repository.NewMockAPIKeyRepository(ctrl)
+	ir := mock_repository.NewMockIdentityRepository(ctrl)
+	totpr := mock_repository.NewMockTOTPRepository(ctrl)
+	utr := mock_repository.NewMockUserTokenRepository(ctrl)
+	rr := mock_repository.NewMockRoleRepository(ctrl)
+	thr := mock_repository.NewMockThreadRepository(ctrl)
+	tmr := mock_repository.NewMockThreadModeratorRepository(ctrl)
+	mr := mock_repository.NewMockThreadMemberRepository(ctrl)
+	tir := mock_repository.NewMockThreadInviteRepository(ctrl)
+	ubr := mock_repository.NewMockUserBlockRepository(ctrl)
+	trr := mock_repository.NewMockThreadReadRepository(ctrl)
+	crr := mock_repository.NewMockCommentReactionRepository(ctrl)
+	cmr := mock_repository.NewMockCommentMentionRepository(ctrl)
+	nr := mock_repository.NewMockNotificationRepository(ctrl)
+	tx := mock_repository.NewMockTxManager(ctrl)
+
+	memberships := []*model.ThreadMember{
+		{ThreadID: model.ThreadValidIDForTest, UserID: model.UserValidIDForTest, Role: model.ThreadMemberRoleOwner},
+		{ThreadID: ownerOnlyThreadID, UserID: model.UserValidIDForTest, Role: model.ThreadMemberRoleOwner},
+		{ThreadID: joinedThreadID, UserID: model.UserValidIDForTest, Role: model.ThreadMemberRoleMember},
+	}
+	members := []*model.ThreadMember{
+		{ThreadID: model.ThreadValidIDForTest, UserID: model.UserValidIDForTest, Role: model.ThreadMemberRoleOwner},
+		{ThreadID: model.ThreadValidIDForTest, UserID: oldestMemberID, Role: model.ThreadMemberRoleMember},
+		{ThreadID: model.ThreadValidIDForTest, UserID: newerMemberID, Role: model.ThreadMemberRoleMember},
+	}
+
+	var closedErr error
+	closed := false
+	m.EXPECT().Begin().Return(tx, nil)
+	gomock.InOrder(
+		sr.EXPECT().DeleteSessionsByUserID(tx, model.UserValidIDForTest).Return(nil),
+		rtr.EXPECT().DeleteRefreshTokensByUserID(tx, model.UserValidIDForTest).Return(nil),
+		akr.EXPECT().DeleteAPIKeysByUserID(tx, model.UserValidIDForTest).Return(nil),
+		ir.EXPECT().DeleteIdentitiesByUserID(tx, model.UserValidIDForTest).Return(nil),
+		totpr.EXPECT().DeleteTOTP(tx, model.UserValidIDForTest).Return(nil),
+		totpr.EXPECT().DeleteRecoveryCodesByUserID(tx, model.UserValidIDForTest).Return(nil),
+		utr.EXPECT().DeleteUserTokensByUserID(tx, model.UserValidIDForTest).Return(nil),
+		rr.EXPECT().DeleteRolesByUserID(tx, model.UserValidIDForTest).Return(nil),
+		mr.EXPECT().GetThreadMembersByUserID(tx, model.UserValidIDForTest).Return(memberships, nil),
+		// the private thread is passed to the oldest other member.
+		mr.EXPECT().GetThreadMembersByThreadID(tx, model.ThreadValidIDForTest).Return(members, nil),
+		mr.EXPECT().UpdateThreadMemberRole(tx, model.ThreadValidIDForTest, oldestMemberID, model.ThreadMemberRoleOwner).Return(nil),
+		thr.EXPECT().UpdateThreadOwner(tx, model.ThreadValidIDForTest, oldestMemberID).Return(nil),
+		tmr.EXPECT().InsertThreadModerator(tx, model.ThreadValidIDForTest, oldestMemberID).Return(nil),
+		// the thread which has no other member is left as it is.
+		mr.EXPECT().GetThreadMembersByThreadID(tx, ownerOnlyThreadID).Return(members[:1], nil),
+		tmr.EXPECT().DeleteThreadModeratorsByUserID(tx, model.UserValidIDForTest).Return(nil),
+		mr.EXPECT().DeleteThreadMembersByUserID(tx, model.UserValidIDForTest).Return(nil),
+		tir.EXPECT().DeleteThreadInvitesByUserID(tx, model.UserValidIDForTest).Return(nil),
+		ubr.EXPECT().DeleteUserBlocksByUserID(tx, model.UserValidIDForTest).Return(nil),
+		trr.EXPECT().DeleteThreadReadsByUserID(tx, model.UserValidIDForTest).Return(nil),
+		crr.EXPECT().DeleteCommentReactionsByUserID(tx, model.UserValidIDForTest).Return(nil),
+		cmr.EXPECT().DeleteCommentMentionsByUserID(tx, model.UserValidIDForTest).Return(nil),
+		nr.EXPECT().DeleteNotificationsByUserID(tx, model.UserValidIDForTest).Return(nil),
+		ur.EXPECT().DeleteUser(tx, model.UserValidIDForTest).Return(nil),
+	)
+
+	s := &userService{
+		m:                         m,
+		userRepository:            ur,
+		sessionRepository:         sr,
+		refreshTokenRepository:    rtr,
+		apiKeyRepository:          akr,
+		identityRepository:        ir,
+		totpRepository:            totpr,
+		userTokenRepository:       utr,
+		roleRepository:            rr,
+		threadRepository:          thr,
+		threadModeratorRepository: tmr,
+		threadMemberRepository:    mr,
+		threadInviteRepository:    tir,
+		userBlockRepository:       ubr,
+		threadReadRepository:      trr,
+		commentReactionRepository: crr,
+		commentMentionRepository:  cmr,
+		notificationRepository:    nr,
+		txCloser: func(_ repository.TxManager, err error) error {
+			closed = true
+			closedErr = err
+			return nil
+		},
+	}
+
+	// ownership is transferred in the same tx as the deletion, before the memberships of the user are deleted.
+	if err := s.DeleteAccount(ctx, model.UserValidIDForTest); err != nil {
+		t.Errorf("userService.DeleteAccount() error = %v", err)
+	}
+	if !closed || closedErr != nil {
+		t.Errorf("tx should be committed, closed = %v, err = %v", closed, closedErr)
+	}
+}
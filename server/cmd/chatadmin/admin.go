@@ -0,0 +1,327 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/application"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+	"github.com/hideUW/nuxt-go-chat-app/server/infra/db"
+	"github.com/hideUW/nuxt-go-chat-app/server/util"
+)
+
+// admin operates users, sessions and roles on behalf of operators.
+// Unlike application services, this is trusted and is not throttled nor checked by the policy.
+type admin struct {
+	m                      repository.DBManager
+	userRepository         repository.UserRepository
+	sessionRepository      repository.SessionRepository
+	refreshTokenRepository repository.RefreshTokenRepository
+	roleRepository         repository.RoleRepository
+	txCloser               application.CloseTransaction
+	now                    func() time.Time
+}
+
+// newAdmin generates and returns admin which works on the database.
+func newAdmin(ctx context.Context, m repository.DBManager) *admin {
+	return &admin{
+		m:                      m,
+		userRepository:         db.NewUserRepository(ctx),
+		sessionRepository:      db.NewSessionRepository(ctx),
+		refreshTokenRepository: db.NewRefreshTokenRepository(ctx),
+		roleRepository:         db.NewRoleRepository(ctx),
+		txCloser:               db.CloseTransaction,
+		now:                    time.Now,
+	}
+}
+
+// CreateUser creates the user with the roles.
+// Email given by operators is regarded as verified.
+func (a *admin) CreateUser(name, password, email string, roles []model.Role) (user *model.User, err error) {
+	user, err = model.NewUser(name, password)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to new user")
+	}
+
+	for _, role := range roles {
+		if err := model.ValidateRole(role); err != nil {
+			return nil, errors.Wrap(err, "failed to validate role")
+		}
+	}
+
+	if email != "" {
+		email = model.NormalizeEmail(email)
+		if err := model.ValidateEmail(email); err != nil {
+			return nil, errors.Wrap(err, "failed to validate email")
+		}
+		if err := a.checkNotExist(model.EmailPropertyForDeveloper, email, a.userRepository.GetUserByEmail); err != nil {
+			return nil, errors.Wrap(err, "failed to check email")
+		}
+		user.Email = email
+		user.EmailVerified = true
+	}
+
+	if err := a.checkNotExist(model.NamePropertyForDeveloper, name, a.userRepository.GetUserByName); err != nil {
+		return nil, errors.Wrap(err, "failed to check name")
+	}
+
+	hashed, err := util.HashPassword(password)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to hash password")
+	}
+	user.Password = hashed
+	user.CreatedAt = a.now()
+	user.UpdatedAt = user.CreatedAt
+
+	tx, err := a.m.Begin()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to begin tx")
+	}
+
+	defer func() {
+		if cErr := a.txCloser(tx, err); cErr != nil {
+			err = errors.Wrap(cErr, "failed to close tx")
+		}
+	}()
+
+	id, err := a.userRepository.InsertUser(tx, user)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to insert user")
+	}
+	user.ID = id
+
+	for _, role := range roles {
+		if err := a.roleRepository.InsertRole(tx, id, role); err != nil {
+			return nil, errors.Wrap(err, "failed to insert role")
+		}
+	}
+
+	return user, nil
+}
+
+// ResetPassword sets the password of the user and revokes all sessions and refresh tokens of the user.
+func (a *admin) ResetPassword(name, password string) (user *model.User, err error) {
+	if err := model.ValidatePassword(password); err != nil {
+		return nil, errors.Wrap(err, "failed to validate password")
+	}
+
+	user, err = a.userRepository.GetUserByName(a.m, name)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get user by name")
+	}
+
+	hashed, err := util.HashPassword(password)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to hash password")
+	}
+
+	tx, err := a.m.Begin()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to begin tx")
+	}
+
+	defer func() {
+		if cErr := a.txCloser(tx, err); cErr != nil {
+			err = errors.Wrap(cErr, "failed to close tx")
+		}
+	}()
+
+	user.Password = hashed
+	user.UpdatedAt = a.now()
+	if err := a.userRepository.UpdateUser(tx, user.ID, user); err != nil {
+		return nil, errors.Wrap(err, "failed to update user")
+	}
+
+	if err := a.revokeAll(tx, user.ID); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// ListSessions returns sessions of the user in order of last seen.
+func (a *admin) ListSessions(name string) ([]*model.Session, error) {
+	user, err := a.userRepository.GetUserByName(a.m, name)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get user by name")
+	}
+
+	sessions, err := a.sessionRepository.GetSessionsByUserID(a.m, user.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get sessions by user id")
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession deletes the session of the user.
+// This returns NoSuchDataError if the session does not exist or belongs to another user.
+func (a *admin) RevokeSession(name, id string) error {
+	user, err := a.userRepository.GetUserByName(a.m, name)
+	if err != nil {
+		return errors.Wrap(err, "failed to get user by name")
+	}
+
+	session, err := a.sessionRepository.GetSessionByID(a.m, id)
+	if err != nil {
+		return errors.Wrap(err, "failed to get session by id")
+	}
+
+	if session.UserID != user.ID {
+		return errors.WithStack(&model.NoSuchDataError{
+			PropertyNameForDeveloper:    model.IDPropertyForDeveloper,
+			PropertyNameForUser:         model.IDPropertyForUser,
+			PropertyValue:               id,
+			DomainModelNameForDeveloper: model.DomainModelNameSessionForDeveloper,
+			DomainModelNameForUser:      model.DomainModelNameSessionForUser,
+		})
+	}
+
+	if err := a.sessionRepository.DeleteSession(a.m, session.ID); err != nil {
+		return errors.Wrap(err, "failed to delete session")
+	}
+
+	return nil
+}
+
+// RevokeSessions deletes all sessions and refresh tokens of the user.
+func (a *admin) RevokeSessions(name string) (err error) {
+	user, err := a.userRepository.GetUserByName(a.m, name)
+	if err != nil {
+		return errors.Wrap(err, "failed to get user by name")
+	}
+
+	tx, err := a.m.Begin()
+	if err != nil {
+		return errors.Wrap(err, "failed to begin tx")
+	}
+
+	defer func() {
+		if cErr := a.txCloser(tx, err); cErr != nil {
+			err = errors.Wrap(cErr, "failed to close tx")
+		}
+	}()
+
+	return a.revokeAll(tx, user.ID)
+}
+
+// GrantRole grants the role to the user.
+func (a *admin) GrantRole(name string, role model.Role) (*model.User, error) {
+	if err := model.ValidateRole(role); err != nil {
+		return nil, errors.Wrap(err, "failed to validate role")
+	}
+
+	user, err := a.userRepository.GetUserByName(a.m, name)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get user by name")
+	}
+
+	if err := a.roleRepository.InsertRole(a.m, user.ID, role); err != nil {
+		return nil, errors.Wrap(err, "failed to insert role")
+	}
+
+	return user, nil
+}
+
+// RevokeRole revokes the role from the user.
+// This returns NoSuchDataError if the user does not have the role.
+func (a *admin) RevokeRole(name string, role model.Role) (*model.User, error) {
+	if err := model.ValidateRole(role); err != nil {
+		return nil, errors.Wrap(err, "failed to validate role")
+	}
+
+	user, err := a.userRepository.GetUserByName(a.m, name)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get user by name")
+	}
+
+	if err := a.roleRepository.DeleteRole(a.m, user.ID, role); err != nil {
+		return nil, errors.Wrap(err, "failed to delete role")
+	}
+
+	return user, nil
+}
+
+// SetDisabled disables or enables the user.
+// Disabling also revokes all sessions and refresh tokens, so that the user must log in again after enabled.
+// API keys are kept and are usable again after enabled, because they are refused while disabled.
+func (a *admin) SetDisabled(name string, disabled bool) (user *model.User, err error) {
+	user, err = a.userRepository.GetUserByName(a.m, name)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get user by name")
+	}
+
+	if user.Disabled == disabled {
+		return user, nil
+	}
+
+	tx, err := a.m.Begin()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to begin tx")
+	}
+
+	defer func() {
+		if cErr := a.txCloser(tx, err); cErr != nil {
+			err = errors.Wrap(cErr, "failed to close tx")
+		}
+	}()
+
+	user.Disabled = disabled
+	user.UpdatedAt = a.now()
+	if err := a.userRepository.UpdateUser(tx, user.ID, user); err != nil {
+		return nil, errors.Wrap(err, "failed to update user")
+	}
+
+	if disabled {
+		if err := a.revokeAll(tx, user.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return user, nil
+}
+
+// GetRoles returns roles of the user.
+func (a *admin) GetRoles(userID uint32) (model.Roles, error) {
+	roles, err := a.roleRepository.GetRolesByUserID(a.m, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get roles by user id")
+	}
+	return roles, nil
+}
+
+// revokeAll deletes all sessions and refresh tokens of the user.
+func (a *admin) revokeAll(m repository.SQLManager, userID uint32) error {
+	if err := a.sessionRepository.DeleteSessionsByUserID(m, userID); err != nil {
+		return errors.Wrap(err, "failed to delete sessions")
+	}
+
+	if err := a.refreshTokenRepository.DeleteRefreshTokensByUserID(m, userID); err != nil {
+		return errors.Wrap(err, "failed to delete refresh tokens")
+	}
+
+	return nil
+}
+
+// checkNotExist returns AlreadyExistError if the user which has the value of the property is found by get.
+func (a *admin) checkNotExist(property model.PropertyNameForDeveloper, value string, get func(m repository.SQLManager, value string) (*model.User, error)) error {
+	_, err := get(a.m, value)
+	if err == nil {
+		return errors.WithStack(&model.AlreadyExistError{
+			PropertyNameForDeveloper:    property,
+			PropertyNameForUser:         model.PropertyNameKV[property],
+			PropertyValue:               value,
+			DomainModelNameForDeveloper: model.DomainModelNameUserForDeveloper,
+			DomainModelNameForUser:      model.DomainModelNameUserForUser,
+		})
+	}
+
+	if _, ok := errors.Cause(err).(*model.NoSuchDataError); !ok {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,346 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/application/mock"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository/mock"
+	"github.com/hideUW/nuxt-go-chat-app/server/testutil"
+	"github.com/hideUW/nuxt-go-chat-app/server/util"
+)
+
+// testAdmin has the admin whose repositories are mocks.
+type testAdmin struct {
+	*admin
+	m  *mock_repository.MockDBManager
+	ur *mock_repository.MockUserRepository
+	sr *mock_repository.MockSessionRepository
+	rr *mock_repository.MockRefreshTokenRepository
+	ro *mock_repository.MockRoleRepository
+}
+
+func newTestAdmin(ctrl *gomock.Controller) *testAdmin {
+	ta := &testAdmin{
+		m:  mock_repository.NewMockDBManager(ctrl),
+		ur: mock_repository.NewMockUserRepository(ctrl),
+		sr: mock_repository.NewMockSessionRepository(ctrl),
+		rr: mock_repository.NewMockRefreshTokenRepository(ctrl),
+		ro: mock_repository.NewMockRoleRepository(ctrl),
+	}
+	ta.admin = &admin{
+		m:                      ta.m,
+		userRepository:         ta.ur,
+		sessionRepository:      ta.sr,
+		refreshTokenRepository: ta.rr,
+		roleRepository:         ta.ro,
+		txCloser:               mock_application.MockCloseTransaction,
+		now:                    testutil.TimeNow,
+	}
+	return ta
+}
+
+// selectUserQuery is the query of users without conditions.
+const selectUserQuery = "SELECT id, name, password, email, email_verified, disabled, created_at, updated_at FROM users"
+
+// userColumns is columns of selectUserQuery.
+var userColumns = []string{"id", "name", "password", "email", "email_verified", "disabled", "created_at", "updated_at"}
+
+// sqlmockDB is DBManager of sqlmock, so that the admin works on repositories of the database.
+type sqlmockDB struct {
+	*sql.DB
+}
+
+// Begin begins tx of sqlmock.
+func (db sqlmockDB) Begin() (repository.TxManager, error) {
+	return db.DB.Begin()
+}
+
+// newSQLMockAdmin returns the admin whose repositories work on sqlmock.
+func newSQLMockAdmin(t *testing.T) (*admin, sqlmock.Sqlmock, *sql.DB) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := newAdmin(context.Background(), sqlmockDB{db})
+	a.now = testutil.TimeNow
+	return a, mock, db
+}
+
+// expectGetUserByName expects the user of UserNameForTest is read.
+func expectGetUserByName(mock sqlmock.Sqlmock, createdAt time.Time, disabled bool) {
+	mock.ExpectPrepare(regexp.QuoteMeta(selectUserQuery + " WHERE name=?")).ExpectQuery().
+		WithArgs(model.UserNameForTest).
+		WillReturnRows(sqlmock.NewRows(userColumns).
+			AddRow(model.UserValidIDForTest, model.UserNameForTest, model.PasswordForTest, nil, false, disabled, createdAt, createdAt))
+}
+
+// expectRevokeAll expects all sessions and refresh tokens of the user are deleted.
+func expectRevokeAll(mock sqlmock.Sqlmock) {
+	mock.ExpectPrepare(regexp.QuoteMeta("DELETE FROM sessions WHERE user_id=?")).ExpectExec().
+		WithArgs(model.UserValidIDForTest).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectPrepare(regexp.QuoteMeta("DELETE FROM refresh_tokens WHERE user_id=?")).ExpectExec().
+		WithArgs(model.UserValidIDForTest).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+// hashOfPassword matches the hash of the password.
+type hashOfPassword string
+
+// Match returns whether the value is the hash of the password.
+func (p hashOfPassword) Match(v driver.Value) bool {
+	hashed, ok := v.(string)
+	return ok && util.CheckHashOfPassword(string(p), hashed)
+}
+
+func Test_dispatch_createUser(t *testing.T) {
+	testutil.SetFakeTime(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC))
+	defer testutil.ResetFakeTime()
+
+	a, mock, db := newSQLMockAdmin(t)
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(selectUserQuery + " WHERE email=?")).ExpectQuery().
+		WithArgs("admin@example.com").
+		WillReturnRows(sqlmock.NewRows(userColumns))
+	mock.ExpectPrepare(regexp.QuoteMeta(selectUserQuery + " WHERE name=?")).ExpectQuery().
+		WithArgs(model.UserNameForTest).
+		WillReturnRows(sqlmock.NewRows(userColumns))
+	mock.ExpectBegin()
+	mock.ExpectPrepare("INSERT INTO users").ExpectExec().
+		WithArgs(model.UserNameForTest, hashOfPassword(model.PasswordForTest), "admin@example.com", true, false, testutil.TimeNow(), testutil.TimeNow()).
+		WillReturnResult(sqlmock.NewResult(int64(model.UserValidIDForTest), 1))
+	mock.ExpectPrepare("INSERT IGNORE INTO user_roles").ExpectExec().
+		WithArgs(model.UserValidIDForTest, model.RoleAdmin).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectPrepare("SELECT role FROM user_roles").ExpectQuery().
+		WithArgs(model.UserValidIDForTest).
+		WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow(model.RoleAdmin))
+
+	args := []string{"create-user", "-name", model.UserNameForTest, "-email", "Admin@example.com ", "-roles", "admin"}
+	var stdout, stderr bytes.Buffer
+	if code := dispatch(a, formatJSON, args, strings.NewReader(model.PasswordForTest+"\n"), &stdout, &stderr); code != 0 {
+		t.Fatalf("dispatch() = %d, stderr = %s", code, stderr.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+
+	var got userView
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("failed to unmarshal output %q: %v", stdout.String(), err)
+	}
+	want := userView{
+		ID:            model.UserValidIDForTest,
+		Name:          model.UserNameForTest,
+		Email:         "admin@example.com",
+		EmailVerified: true,
+		Roles:         []string{"admin"},
+		CreatedAt:     testutil.TimeNow(),
+	}
+	if got.ID != want.ID || got.Name != want.Name || got.Email != want.Email || !got.EmailVerified ||
+		strings.Join(got.Roles, ",") != strings.Join(want.Roles, ",") || !got.CreatedAt.Equal(want.CreatedAt) {
+		testutil.Errorf(t, want, got)
+	}
+}
+
+func Test_dispatch_listSessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ta := newTestAdmin(ctrl)
+	ta.ur.EXPECT().GetUserByName(ta.m, model.UserNameForTest).Return(&model.User{ID: model.UserValidIDForTest}, nil)
+	ta.sr.EXPECT().GetSessionsByUserID(ta.m, model.UserValidIDForTest).Return([]*model.Session{{
+		ID:        model.SessionValidIDForTest,
+		UserID:    model.UserValidIDForTest,
+		IPAddress: model.ClientIPForTest,
+		UserAgent: model.UserAgentForTest,
+	}}, nil)
+
+	var stdout, stderr bytes.Buffer
+	if code := dispatch(ta.admin, formatTable, []string{"list-sessions", "-name", model.UserNameForTest}, nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("dispatch() = %d, stderr = %s", code, stderr.String())
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("dispatch() printed %d lines, want header and a session: %q", len(lines), stdout.String())
+	}
+	if !strings.HasPrefix(lines[0], "ID") || !strings.HasPrefix(lines[1], model.SessionValidIDForTest) {
+		t.Errorf("dispatch() printed %q", stdout.String())
+	}
+}
+
+func Test_dispatch_usage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		name   string
+		format string
+		args   []string
+	}{
+		{
+			name:   "When no command is given, exits with 2",
+			format: formatTable,
+		},
+		{
+			name:   "When the command is unknown, exits with 2",
+			format: formatTable,
+			args:   []string{"drop-database"},
+		},
+		{
+			name:   "When the format is unknown, exits with 2",
+			format: "yaml",
+			args:   []string{"list-sessions"},
+		},
+		{
+			name:   "When the flag is unknown, exits with 2",
+			format: formatTable,
+			args:   []string{"list-sessions", "-user", model.UserNameForTest},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := dispatch(newTestAdmin(ctrl).admin, tt.format, tt.args, nil, &stdout, &stderr); code != 2 {
+				t.Errorf("dispatch() = %d, want 2", code)
+			}
+		})
+	}
+}
+
+func Test_dispatch_setDisabled(t *testing.T) {
+	testutil.SetFakeTime(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC))
+	defer testutil.ResetFakeTime()
+
+	tests := []struct {
+		name       string
+		command    string
+		disabled   bool
+		wantUpdate bool
+		wantRevoke bool
+	}{
+		{
+			name:       "When the user is disabled, stores it and deletes sessions and refresh tokens",
+			command:    "disable",
+			wantUpdate: true,
+			wantRevoke: true,
+		},
+		{
+			name:       "When the user is enabled, stores it and keeps sessions",
+			command:    "enable",
+			disabled:   true,
+			wantUpdate: true,
+		},
+		{
+			name:     "When the user is already disabled, stores nothing",
+			command:  "disable",
+			disabled: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, mock, db := newSQLMockAdmin(t)
+			defer db.Close()
+
+			createdAt := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+			expectGetUserByName(mock, createdAt, tt.disabled)
+			if tt.wantUpdate {
+				mock.ExpectBegin()
+				mock.ExpectPrepare("UPDATE users").ExpectExec().
+					WithArgs(model.UserNameForTest, model.PasswordForTest, nil, false, !tt.disabled, testutil.TimeNow(), model.UserValidIDForTest).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				if tt.wantRevoke {
+					expectRevokeAll(mock)
+				}
+				mock.ExpectCommit()
+			}
+			mock.ExpectPrepare("SELECT role FROM user_roles").ExpectQuery().
+				WithArgs(model.UserValidIDForTest).
+				WillReturnRows(sqlmock.NewRows([]string{"role"}))
+
+			var stdout, stderr bytes.Buffer
+			if code := dispatch(a, formatJSON, []string{tt.command, "-name", model.UserNameForTest}, nil, &stdout, &stderr); code != 0 {
+				t.Fatalf("dispatch() = %d, stderr = %s", code, stderr.String())
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func Test_dispatch_revokeSessions(t *testing.T) {
+	a, mock, db := newSQLMockAdmin(t)
+	defer db.Close()
+
+	expectGetUserByName(mock, time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC), false)
+	mock.ExpectBegin()
+	expectRevokeAll(mock)
+	mock.ExpectCommit()
+
+	var stdout, stderr bytes.Buffer
+	if code := dispatch(a, formatTable, []string{"revoke-sessions", "-name", model.UserNameForTest}, nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("dispatch() = %d, stderr = %s", code, stderr.String())
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_admin_RevokeSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		name       string
+		ownerID    uint32
+		wantDelete bool
+	}{
+		{
+			name:       "When the session belongs to the user, deletes it",
+			ownerID:    model.UserValidIDForTest,
+			wantDelete: true,
+		},
+		{
+			name:    "When the session belongs to another user, returns NoSuchDataError",
+			ownerID: model.UserValidIDForTest + 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ta := newTestAdmin(ctrl)
+			ta.ur.EXPECT().GetUserByName(ta.m, model.UserNameForTest).Return(&model.User{ID: model.UserValidIDForTest}, nil)
+			ta.sr.EXPECT().GetSessionByID(ta.m, model.SessionValidIDForTest).Return(&model.Session{ID: model.SessionValidIDForTest, UserID: tt.ownerID}, nil)
+			if tt.wantDelete {
+				ta.sr.EXPECT().DeleteSession(ta.m, model.SessionValidIDForTest).Return(nil)
+			}
+
+			err := ta.RevokeSession(model.UserNameForTest, model.SessionValidIDForTest)
+			if tt.wantDelete {
+				if err != nil {
+					t.Errorf("admin.RevokeSession() error = %v", err)
+				}
+			} else if _, ok := errors.Cause(err).(*model.NoSuchDataError); !ok {
+				t.Errorf("admin.RevokeSession() error = %v, want NoSuchDataError", err)
+			}
+		})
+	}
+}
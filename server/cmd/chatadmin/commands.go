@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+)
+
+// cli is what a command runs with.
+type cli struct {
+	admin *admin
+	in    io.Reader
+	out   *printer
+}
+
+// command is a subcommand of chatadmin.
+// setup defines flags of the command and returns the function which runs it after flags are parsed.
+type command struct {
+	summary string
+	setup   func(c *cli, fs *flag.FlagSet) func() error
+}
+
+// commands are subcommands of chatadmin by name.
+var commands = map[string]command{
+	"create-user": {
+		summary: "create a user, the password is read from stdin",
+		setup: func(c *cli, fs *flag.FlagSet) func() error {
+			name := fs.String("name", "", "name of the user")
+			email := fs.String("email", "", "email of the user, which is regarded as verified")
+			roles := fs.String("roles", "", "roles of the user separated by comma")
+			return func() error {
+				password, err := c.readPassword()
+				if err != nil {
+					return err
+				}
+				user, err := c.admin.CreateUser(*name, password, *email, parseRoles(*roles))
+				if err != nil {
+					return err
+				}
+				return c.printUser(user)
+			}
+		},
+	},
+	"reset-password": {
+		summary: "set the password read from stdin and revoke all sessions of a user",
+		setup: func(c *cli, fs *flag.FlagSet) func() error {
+			name := fs.String("name", "", "name of the user")
+			return func() error {
+				password, err := c.readPassword()
+				if err != nil {
+					return err
+				}
+				user, err := c.admin.ResetPassword(*name, password)
+				if err != nil {
+					return err
+				}
+				return c.printUser(user)
+			}
+		},
+	},
+	"list-sessions": {
+		summary: "list sessions of a user",
+		setup: func(c *cli, fs *flag.FlagSet) func() error {
+			name := fs.String("name", "", "name of the user")
+			return func() error {
+				sessions, err := c.admin.ListSessions(*name)
+				if err != nil {
+					return err
+				}
+				return c.out.printSessions(sessions)
+			}
+		},
+	},
+	"revoke-sessions": {
+		summary: "revoke a session, or all sessions and refresh tokens of a user",
+		setup: func(c *cli, fs *flag.FlagSet) func() error {
+			name := fs.String("name", "", "name of the user")
+			id := fs.String("id", "", "id of the session shown by list-sessions, all sessions if empty")
+			return func() error {
+				if *id != "" {
+					if err := c.admin.RevokeSession(*name, *id); err != nil {
+						return err
+					}
+					return c.out.printResult("revoked session " + *id)
+				}
+				if err := c.admin.RevokeSessions(*name); err != nil {
+					return err
+				}
+				return c.out.printResult("revoked all sessions of " + *name)
+			}
+		},
+	},
+	"grant-role": {
+		summary: "grant a role to a user",
+		setup: func(c *cli, fs *flag.FlagSet) func() error {
+			name := fs.String("name", "", "name of the user")
+			role := fs.String("role", "", "role to grant, admin or moderator")
+			return func() error {
+				user, err := c.admin.GrantRole(*name, model.Role(*role))
+				if err != nil {
+					return err
+				}
+				return c.printUser(user)
+			}
+		},
+	},
+	"revoke-role": {
+		summary: "revoke a role from a user",
+		setup: func(c *cli, fs *flag.FlagSet) func() error {
+			name := fs.String("name", "", "name of the user")
+			role := fs.String("role", "", "role to revoke, admin or moderator")
+			return func() error {
+				user, err := c.admin.RevokeRole(*name, model.Role(*role))
+				if err != nil {
+					return err
+				}
+				return c.printUser(user)
+			}
+		},
+	},
+	"disable": {
+		summary: "disable a user and revoke all sessions of the user",
+		setup: func(c *cli, fs *flag.FlagSet) func() error {
+			return c.setDisabled(fs, true)
+		},
+	},
+	"enable": {
+		summary: "enable a disabled user",
+		setup: func(c *cli, fs *flag.FlagSet) func() error {
+			return c.setDisabled(fs, false)
+		},
+	},
+}
+
+// setDisabled defines flags of disable and enable.
+func (c *cli) setDisabled(fs *flag.FlagSet, disabled bool) func() error {
+	name := fs.String("name", "", "name of the user")
+	return func() error {
+		user, err := c.admin.SetDisabled(*name, disabled)
+		if err != nil {
+			return err
+		}
+		return c.printUser(user)
+	}
+}
+
+// printUser prints the user with roles.
+func (c *cli) printUser(user *model.User) error {
+	roles, err := c.admin.GetRoles(user.ID)
+	if err != nil {
+		return err
+	}
+	return c.out.printUser(user, roles)
+}
+
+// readPassword reads the first line of the input as the password.
+func (c *cli) readPassword() (string, error) {
+	line, err := bufio.NewReader(c.in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", errors.Wrap(err, "failed to read password")
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// parseRoles parses roles separated by comma.
+func parseRoles(v string) []model.Role {
+	var roles []model.Role
+	for _, r := range strings.Split(v, ",") {
+		if r = strings.TrimSpace(r); r != "" {
+			roles = append(roles, model.Role(r))
+		}
+	}
+	return roles
+}
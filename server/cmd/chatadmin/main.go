@@ -0,0 +1,109 @@
+// Command chatadmin is the command line tool for operators of the chat.
+// It manages users, sessions and roles directly on the database used by the server.
+//
+// Usage:
+//
+//	chatadmin [-dsn dsn] [-format table|json] <command> [flags]
+//
+// The data source name is taken from -dsn, CHAT_DB_DSN or the one of the docker compose environment in this order.
+// Passwords are read from the first line of stdin, so that they are not left in the shell history.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/infra/db"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run parses global flags, connects to the database and runs the command.
+// This returns the exit status.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("chatadmin", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	dsn := fs.String("dsn", os.Getenv("CHAT_DB_DSN"), "data source name of the database")
+	format := fs.String("format", formatTable, "output format, table or json")
+	fs.Usage = func() { usage(fs) }
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if *dsn == "" {
+		*dsn = db.DefaultDSN
+	}
+
+	m, err := db.NewDBManagerWithDSN(*dsn)
+	if err != nil {
+		fmt.Fprintf(stderr, "chatadmin: failed to open database: %v\n", err)
+		return 1
+	}
+
+	return dispatch(newAdmin(context.Background(), m), *format, fs.Args(), stdin, stdout, stderr)
+}
+
+// dispatch runs the command of args[0] by the admin and returns the exit status.
+func dispatch(a *admin, format string, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	p, err := newPrinter(stdout, format)
+	if err != nil {
+		fmt.Fprintf(stderr, "chatadmin: %v\n", err)
+		return 2
+	}
+
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, "chatadmin: command is required")
+		printCommands(stderr)
+		return 2
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "chatadmin: unknown command %q\n", args[0])
+		printCommands(stderr)
+		return 2
+	}
+
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	c := &cli{admin: a, in: stdin, out: p}
+	exec := cmd.setup(c, fs)
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	if err := exec(); err != nil {
+		fmt.Fprintf(stderr, "chatadmin: %s: %v\n", args[0], err)
+		return 1
+	}
+
+	return 0
+}
+
+// usage prints the usage of chatadmin.
+func usage(fs *flag.FlagSet) {
+	out := fs.Output()
+	fmt.Fprintln(out, "Usage: chatadmin [flags] <command> [flags]")
+	fs.PrintDefaults()
+	printCommands(out)
+}
+
+// printCommands prints names and summaries of commands.
+func printCommands(w io.Writer) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(w, "Commands:")
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-16s %s\n", name, commands[name].summary)
+	}
+}
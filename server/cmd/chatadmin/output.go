@@ -0,0 +1,142 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+)
+
+// Output formats.
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+)
+
+// userView is the user printed by chatadmin, which has no password.
+type userView struct {
+	ID            uint32    `json:"id"`
+	Name          string    `json:"name"`
+	Email         string    `json:"email"`
+	EmailVerified bool      `json:"emailVerified"`
+	Disabled      bool      `json:"disabled"`
+	Roles         []string  `json:"roles"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+// sessionView is the session printed by chatadmin, which has no token.
+type sessionView struct {
+	ID         string    `json:"id"`
+	UserAgent  string    `json:"userAgent"`
+	IPAddress  string    `json:"ipAddress"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastSeenAt time.Time `json:"lastSeenAt"`
+}
+
+// resultView is the result of the command which has nothing to print.
+type resultView struct {
+	Result string `json:"result"`
+}
+
+// printer prints results of commands as a table or JSON.
+type printer struct {
+	w      io.Writer
+	format string
+}
+
+// newPrinter generates and returns printer of the format.
+func newPrinter(w io.Writer, format string) (*printer, error) {
+	if format != formatTable && format != formatJSON {
+		return nil, errors.Errorf("format should be %s or %s, but %q", formatTable, formatJSON, format)
+	}
+	return &printer{w: w, format: format}, nil
+}
+
+// printUser prints the user with roles.
+func (p *printer) printUser(user *model.User, roles model.Roles) error {
+	v := &userView{
+		ID:            user.ID,
+		Name:          user.Name,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerified,
+		Disabled:      user.Disabled,
+		Roles:         make([]string, 0, len(roles)),
+		CreatedAt:     user.CreatedAt,
+	}
+	for _, r := range roles {
+		v.Roles = append(v.Roles, r.String())
+	}
+
+	if p.format == formatJSON {
+		return p.json(v)
+	}
+
+	return p.table(
+		[]string{"ID", "NAME", "EMAIL", "VERIFIED", "DISABLED", "ROLES", "CREATED"},
+		[]string{fmt.Sprint(v.ID), v.Name, v.Email, fmt.Sprint(v.EmailVerified), fmt.Sprint(v.Disabled), strings.Join(v.Roles, ","), formatTime(v.CreatedAt)},
+	)
+}
+
+// printSessions prints sessions.
+func (p *printer) printSessions(sessions []*model.Session) error {
+	vs := make([]*sessionView, 0, len(sessions))
+	rows := make([][]string, 0, len(sessions))
+	for _, s := range sessions {
+		v := &sessionView{
+			ID:         s.ID,
+			UserAgent:  s.UserAgent,
+			IPAddress:  s.IPAddress,
+			CreatedAt:  s.CreatedAt,
+			LastSeenAt: s.LastSeenAt,
+		}
+		vs = append(vs, v)
+		rows = append(rows, []string{v.ID, v.IPAddress, formatTime(v.CreatedAt), formatTime(v.LastSeenAt), v.UserAgent})
+	}
+
+	if p.format == formatJSON {
+		return p.json(vs)
+	}
+
+	return p.table([]string{"ID", "IP", "CREATED", "LAST SEEN", "USER AGENT"}, rows...)
+}
+
+// printResult prints the result of the command.
+func (p *printer) printResult(result string) error {
+	if p.format == formatJSON {
+		return p.json(&resultView{Result: result})
+	}
+
+	_, err := fmt.Fprintln(p.w, result)
+	return errors.WithStack(err)
+}
+
+// json prints v as indented JSON.
+func (p *printer) json(v interface{}) error {
+	e := json.NewEncoder(p.w)
+	e.SetIndent("", "  ")
+	return errors.WithStack(e.Encode(v))
+}
+
+// table prints rows aligned under the header.
+func (p *printer) table(header []string, rows ...[]string) error {
+	w := tabwriter.NewWriter(p.w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return errors.WithStack(w.Flush())
+}
+
+// formatTime formats the time in RFC 3339 for tables.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
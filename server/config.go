@@ -0,0 +1,300 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/application"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/service"
+	"github.com/hideUW/nuxt-go-chat-app/server/infra/blob"
+	"github.com/hideUW/nuxt-go-chat-app/server/infra/db"
+	"github.com/hideUW/nuxt-go-chat-app/server/infra/mail"
+	"github.com/hideUW/nuxt-go-chat-app/server/infra/memory"
+	"github.com/hideUW/nuxt-go-chat-app/server/infra/oidc"
+	"github.com/hideUW/nuxt-go-chat-app/server/interface/controller"
+	"github.com/hideUW/nuxt-go-chat-app/server/util"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ed25519"
+)
+
+// sessionMaxAge is the max age of the session cookie in seconds.
+const sessionMaxAge = 86400
+
+// allowedOrigins returns origins allowed to send state changing requests other than the server itself.
+// This is set by ALLOWED_ORIGINS separated by comma, e.g. http://localhost:3000 for the dev server of Nuxt.
+func allowedOrigins() []string {
+	v := os.Getenv("ALLOWED_ORIGINS")
+	if v == "" {
+		return nil
+	}
+	return strings.Split(v, ",")
+}
+
+// cookieConfig returns the configuration of the session cookie from environment variables.
+//
+// SESSION_COOKIE_KEYS is the keys separated by comma as <id>:<base64 secret>, the first one signs new cookies.
+// SESSION_COOKIE_SECURE, SESSION_COOKIE_HOST_PREFIX and SESSION_COOKIE_ENCRYPT are "true" to enable.
+// SESSION_COOKIE_DOMAIN is the domain, and SESSION_COOKIE_SAME_SITE is strict or lax.
+func cookieConfig() (controller.CookieConfig, error) {
+	config := controller.CookieConfig{
+		Domain:     os.Getenv("SESSION_COOKIE_DOMAIN"),
+		Secure:     os.Getenv("SESSION_COOKIE_SECURE") == "true",
+		HostPrefix: os.Getenv("SESSION_COOKIE_HOST_PREFIX") == "true",
+		Encrypt:    os.Getenv("SESSION_COOKIE_ENCRYPT") == "true",
+		MaxAge:     sessionMaxAge,
+	}
+
+	switch strings.ToLower(os.Getenv("SESSION_COOKIE_SAME_SITE")) {
+	case "strict":
+		config.SameSite = http.SameSiteStrictMode
+	default:
+		config.SameSite = http.SameSiteLaxMode
+	}
+
+	keys, err := cookieKeys(os.Getenv("SESSION_COOKIE_KEYS"))
+	if err != nil {
+		return controller.CookieConfig{}, err
+	}
+	config.Keys = keys
+
+	return config, nil
+}
+
+// cookieKeys parses keys of cookie.
+// If no key is given, a random key is generated, so that cookies are invalidated by restart.
+func cookieKeys(v string) ([]controller.CookieKey, error) {
+	if v == "" {
+		log.Warn("SESSION_COOKIE_KEYS is not set, sessions are invalidated by restart")
+		secret, err := util.RandomToken(32)
+		if err != nil {
+			return nil, err
+		}
+		return []controller.CookieKey{{ID: "ephemeral", Secret: []byte(secret)}}, nil
+	}
+
+	var keys []controller.CookieKey
+	for _, kv := range strings.Split(v, ",") {
+		i := strings.Index(kv, ":")
+		if i < 0 {
+			return nil, errors.Errorf("cookie key should be <id>:<base64 secret>, but %q", kv)
+		}
+
+		secret, err := base64.StdEncoding.DecodeString(kv[i+1:])
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decode secret of cookie key %q", kv[:i])
+		}
+
+		keys = append(keys, controller.CookieKey{ID: kv[:i], Secret: secret})
+	}
+
+	return keys, nil
+}
+
+// accessTokenKeys returns the keys of access token from ACCESS_TOKEN_KEYS.
+// This is the keys separated by comma as <id>:<base64 seed of Ed25519>, the first one signs new tokens.
+// To rotate keys, put a new key first and keep the old one until tokens signed by it expire.
+// If no key is given, a random key is generated, so that access tokens are invalidated by restart.
+func accessTokenKeys() ([]service.AccessTokenKey, error) {
+	v := os.Getenv("ACCESS_TOKEN_KEYS")
+	if v == "" {
+		log.Warn("ACCESS_TOKEN_KEYS is not set, access tokens are invalidated by restart")
+		_, key, err := ed25519.GenerateKey(nil)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return []service.AccessTokenKey{{ID: "ephemeral", PrivateKey: key}}, nil
+	}
+
+	var keys []service.AccessTokenKey
+	for _, kv := range strings.Split(v, ",") {
+		i := strings.Index(kv, ":")
+		if i < 0 {
+			return nil, errors.Errorf("access token key should be <id>:<base64 seed>, but %q", kv)
+		}
+
+		seed, err := base64.StdEncoding.DecodeString(kv[i+1:])
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decode seed of access token key %q", kv[:i])
+		}
+		if len(seed) != ed25519.SeedSize {
+			return nil, errors.Errorf("seed of access token key %q should be %d bytes", kv[:i], ed25519.SeedSize)
+		}
+
+		keys = append(keys, service.AccessTokenKey{ID: kv[:i], PrivateKey: ed25519.NewKeyFromSeed(seed)})
+	}
+
+	return keys, nil
+}
+
+// oidcConfig returns the configuration of OpenID Connect from environment variables.
+// This returns false if OIDC_ISSUER is not set, and login by OpenID Connect is disabled.
+//
+// OIDC_CLIENT_ID and OIDC_CLIENT_SECRET are the client registered to the provider,
+// and OIDC_REDIRECT_URL is the URL of /api/oidc/callback seen from the browser.
+func oidcConfig() (oidc.Config, bool) {
+	issuer := os.Getenv("OIDC_ISSUER")
+	if issuer == "" {
+		return oidc.Config{}, false
+	}
+
+	return oidc.Config{
+		Issuer:       issuer,
+		ClientID:     os.Getenv("OIDC_CLIENT_ID"),
+		ClientSecret: os.Getenv("OIDC_CLIENT_SECRET"),
+		RedirectURL:  os.Getenv("OIDC_REDIRECT_URL"),
+	}, true
+}
+
+// oidcLoginCookieConfig returns the configuration of the cookie of login in progress.
+// This is signed by the same keys as the session cookie, and is always SameSite=Lax
+// because the browser comes back from the provider by cross-site navigation.
+func oidcLoginCookieConfig(session controller.CookieConfig) controller.CookieConfig {
+	return controller.CookieConfig{
+		Name:       model.OIDCLoginAtCookie,
+		HostPrefix: session.HostPrefix,
+		Secure:     session.Secure,
+		SameSite:   http.SameSiteLaxMode,
+		MaxAge:     int(application.OIDCLoginTTL.Seconds()),
+		Keys:       session.Keys,
+		Encrypt:    session.Encrypt,
+	}
+}
+
+// metricsAddr returns the address which metrics are served on, which should not be reachable from the public network.
+// This is set by METRICS_ADDR, and only the loopback interface is listened by default.
+func metricsAddr() string {
+	if v := os.Getenv("METRICS_ADDR"); v != "" {
+		return v
+	}
+	return "127.0.0.1:8081"
+}
+
+// appBaseURL returns the URL of the client seen from the browser, which links in mails are based on.
+// This is set by APP_BASE_URL, e.g. https://chat.example.com.
+func appBaseURL() string {
+	v := os.Getenv("APP_BASE_URL")
+	if v == "" {
+		return "http://localhost:8080"
+	}
+	return strings.TrimSuffix(v, "/")
+}
+
+// newMailer returns the mailer from environment variables.
+//
+// If SMTP_HOST is set, mails are sent by SMTP with SMTP_PORT, SMTP_USERNAME, SMTP_PASSWORD and SMTP_FROM.
+// Otherwise mails are written to MAIL_DIR as files, so that they can be read in development.
+func newMailer() (service.Mailer, error) {
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		dir := os.Getenv("MAIL_DIR")
+		if dir == "" {
+			d, err := ioutil.TempDir("", "mail")
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			dir = d
+		}
+		log.Warnf("SMTP_HOST is not set, mails are written to %s", dir)
+		return mail.NewFileMailer(dir)
+	}
+
+	port := 587
+	if v := os.Getenv("SMTP_PORT"); v != "" {
+		p, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "SMTP_PORT should be number, but %q", v)
+		}
+		port = p
+	}
+
+	from := os.Getenv("SMTP_FROM")
+	if from == "" {
+		return nil, errors.New("SMTP_FROM should be set to send mails by SMTP")
+	}
+
+	return mail.NewSMTPMailer(mail.SMTPConfig{
+		Host:     host,
+		Port:     port,
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     from,
+	}), nil
+}
+
+// newBlobStore returns BlobStore of attachments by BLOB_STORE, which is local (default) or s3.
+// The local store writes to BLOB_DIR, or a temporary directory when it is not set, which is only for development.
+func newBlobStore() (service.BlobStore, error) {
+	switch kind := os.Getenv("BLOB_STORE"); kind {
+	case "", "local":
+		dir := os.Getenv("BLOB_DIR")
+		if dir == "" {
+			d, err := ioutil.TempDir("", "blob")
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			dir = d
+			log.Warnf("BLOB_DIR is not set, attachments are written to %s", dir)
+		}
+		return blob.NewLocalStore(dir)
+	case "s3":
+		region := os.Getenv("S3_REGION")
+		if region == "" {
+			region = "us-east-1"
+		}
+		return blob.NewS3Store(blob.S3Config{
+			Endpoint:        os.Getenv("S3_ENDPOINT"),
+			Region:          region,
+			Bucket:          os.Getenv("S3_BUCKET"),
+			AccessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+		})
+	default:
+		return nil, errors.Errorf("BLOB_STORE should be local or s3, but %q", kind)
+	}
+}
+
+// defaultCommentRetention is how long the content of deleted comments is kept by default.
+const defaultCommentRetention = 30 * 24 * time.Hour
+
+// commentRetention returns how long the content of deleted comments is kept for moderators before it is purged.
+// This is set by COMMENT_RETENTION as duration, e.g. 720h.
+func commentRetention() (time.Duration, error) {
+	v := os.Getenv("COMMENT_RETENTION")
+	if v == "" {
+		return defaultCommentRetention, nil
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, errors.Wrapf(err, "COMMENT_RETENTION should be duration, but %q", v)
+	}
+	if d < 0 {
+		return 0, errors.Errorf("COMMENT_RETENTION should not be negative, but %q", v)
+	}
+	return d, nil
+}
+
+// newSearchRepository returns the index of search from environment variables, and whether it is in memory.
+//
+// SEARCH_INDEX is mysql to use the FULLTEXT index of MySQL, which is the default,
+// or memory to build the index in memory at start for databases without the FULLTEXT index with the ngram parser.
+func newSearchRepository(ctx context.Context) (repository.SearchRepository, bool, error) {
+	switch v := os.Getenv("SEARCH_INDEX"); v {
+	case "", "mysql":
+		return db.NewSearchRepository(ctx), false, nil
+	case "memory":
+		log.Warn("SEARCH_INDEX is memory, comments are indexed at start and the index is not shared between processes")
+		return memory.NewSearchRepository(), true, nil
+	default:
+		return nil, false, errors.Errorf("SEARCH_INDEX should be mysql or memory, but %q", v)
+	}
+}
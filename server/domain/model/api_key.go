@@ -0,0 +1,186 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// APIKeyPrefix is the prefix of API key.
+// This tells API key from access token in Authorization header, and lets secret scanners find leaked keys.
+const APIKeyPrefix = "ncak_"
+
+// APIKeySize is the bytes of entropy of API key.
+const APIKeySize = 32
+
+// apiKeyDisplayLength is the length of the head of API key kept in plain,
+// so that the user can identify the key after it is shown once.
+const apiKeyDisplayLength = len(APIKeyPrefix) + 6
+
+// MaxAPIKeyNameLength is the max length of the name of API key.
+const MaxAPIKeyNameLength = 100
+
+// Scope is the operation which API key is allowed.
+type Scope string
+
+// Scopes of API key.
+const (
+	// ScopeReadThreads allows to read threads and their comments.
+	ScopeReadThreads Scope = "threads:read"
+	// ScopePostComments allows to post comments to threads.
+	ScopePostComments Scope = "comments:write"
+	// ScopeAdmin allows everything including management of the account, e.g. sessions and API keys.
+	ScopeAdmin Scope = "admin"
+)
+
+// Scopes is the set of scopes.
+type Scopes []Scope
+
+// AllScopes has every scope, which is granted to session and access token.
+var AllScopes = Scopes{ScopeReadThreads, ScopePostComments, ScopeAdmin}
+
+// Has returns whether the scopes allow the scope.
+// ScopeAdmin allows every scope.
+func (s Scopes) Has(scope Scope) bool {
+	for _, sc := range s {
+		if sc == scope || sc == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns scopes separated with comma.
+func (s Scopes) String() string {
+	strs := make([]string, 0, len(s))
+	for _, sc := range s {
+		strs = append(strs, string(sc))
+	}
+	return strings.Join(strs, ",")
+}
+
+// ParseScopes parses scopes separated with comma.
+func ParseScopes(str string) Scopes {
+	scopes := make(Scopes, 0)
+	for _, sc := range strings.Split(str, ",") {
+		if sc != "" {
+			scopes = append(scopes, Scope(sc))
+		}
+	}
+	return scopes
+}
+
+// APIKey is APIKey model
+// This is owned by an user and used by automation, e.g. CI posting build notifications.
+// Key is shown to the user only once and only its hash is stored as ID.
+// Prefix is the head of the key which identifies the key in the list.
+type APIKey struct {
+	ID         string
+	Key        string `json:"-" secret:"true"`
+	Prefix     string
+	UserID     uint32
+	Name       string
+	Scopes     Scopes
+	CreatedAt  time.Time
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+}
+
+// NewAPIKey generates and returns APIKey which has the key.
+func NewAPIKey(key string, userID uint32, name string, scopes Scopes, expiresAt *time.Time, now time.Time) *APIKey {
+	prefix := key
+	if len(prefix) > apiKeyDisplayLength {
+		prefix = prefix[:apiKeyDisplayLength]
+	}
+
+	return &APIKey{
+		ID:        APIKeyIDFromKey(key),
+		Key:       key,
+		Prefix:    prefix,
+		UserID:    userID,
+		Name:      name,
+		Scopes:    scopes,
+		CreatedAt: now,
+		ExpiresAt: expiresAt,
+	}
+}
+
+// APIKeyIDFromKey returns the id of the API key.
+func APIKeyIDFromKey(key string) string {
+	return hashToken(key)
+}
+
+// IsAPIKey returns whether the token in Authorization header is API key.
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, APIKeyPrefix)
+}
+
+// IsExpired returns whether the API key is expired at the time.
+// API key without expiry never expires.
+func (k *APIKey) IsExpired(now time.Time) bool {
+	return k.ExpiresAt != nil && !now.Before(*k.ExpiresAt)
+}
+
+// ValidateAPIKey checks name, scopes and expiry of the API key which is going to be created.
+func ValidateAPIKey(name string, scopes Scopes, expiresAt *time.Time, now time.Time) error {
+	if name == "" {
+		return errors.WithStack(&RequiredError{
+			PropertyNameForDeveloper: NamePropertyForDeveloper,
+			PropertyNameForUser:      NamePropertyForUser,
+		})
+	}
+
+	if utf8.RuneCountInString(name) > MaxAPIKeyNameLength {
+		return errors.WithStack(&InvalidParamError{
+			PropertyNameForDeveloper:  NamePropertyForDeveloper,
+			PropertyNameForUser:       NamePropertyForUser,
+			PropertyValue:             name,
+			InvalidReasonForDeveloper: fmt.Sprintf("longer than %d characters", MaxAPIKeyNameLength),
+			InvalidReasonForUser:      fmt.Sprintf("名前は%d文字以内で入力してください", MaxAPIKeyNameLength),
+		})
+	}
+
+	if len(scopes) == 0 {
+		return errors.WithStack(&RequiredError{
+			PropertyNameForDeveloper: ScopePropertyForDeveloper,
+			PropertyNameForUser:      ScopePropertyForUser,
+		})
+	}
+
+	for _, sc := range scopes {
+		if !isKnownScope(sc) {
+			return errors.WithStack(&InvalidParamError{
+				PropertyNameForDeveloper:  ScopePropertyForDeveloper,
+				PropertyNameForUser:       ScopePropertyForUser,
+				PropertyValue:             sc,
+				InvalidReasonForDeveloper: "unknown scope",
+				InvalidReasonForUser:      fmt.Sprintf("%sは存在しないスコープです", sc),
+			})
+		}
+	}
+
+	if expiresAt != nil && !now.Before(*expiresAt) {
+		return errors.WithStack(&InvalidParamError{
+			PropertyNameForDeveloper:  ExpiresAtPropertyForDeveloper,
+			PropertyNameForUser:       ExpiresAtPropertyForUser,
+			PropertyValue:             *expiresAt,
+			InvalidReasonForDeveloper: "not in the future",
+			InvalidReasonForUser:      "有効期限には未来の日時を指定してください",
+		})
+	}
+
+	return nil
+}
+
+// isKnownScope returns whether the scope is defined.
+func isKnownScope(scope Scope) bool {
+	for _, sc := range AllScopes {
+		if sc == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,156 @@
+package model
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// MaxAttachmentSize is the max size of a file attached to the comment in bytes.
+const MaxAttachmentSize = 10 << 20
+
+// MaxAttachmentsPerComment is the max number of files attached to a comment.
+const MaxAttachmentsPerComment = 5
+
+// MaxAttachmentFileNameLength is the max length of the file name, which is the size of the column.
+const MaxAttachmentFileNameLength = 255
+
+// AttachmentSniffLength is the number of leading bytes which the content type is sniffed from.
+const AttachmentSniffLength = 512
+
+// AllowedAttachmentTypes is the content types sniffed from files which users can attach.
+// This is an allowlist, so that files which browsers run as HTML or scripts are not served from the origin.
+var AllowedAttachmentTypes = []string{
+	"image/png",
+	"image/jpeg",
+	"image/gif",
+	"image/webp",
+	"text/plain; charset=utf-8",
+	"application/pdf",
+	"application/zip",
+	"application/x-gzip",
+}
+
+// Attachment is Attachment model
+// This is a file attached to the comment by its author.
+// Files with the same content share a blob, which is found by SHA256 of the content.
+type Attachment struct {
+	ID          uint32
+	CommentID   uint32
+	UserID      uint32
+	FileName    string
+	ContentType string
+	Size        int64
+	// SHA256 is the hex encoded hash of the content.
+	SHA256    string
+	CreatedAt time.Time
+}
+
+// NewAttachment checks given file name and returns Attachment of the content uploaded to the comment by the user.
+func NewAttachment(comment *Comment, userID uint32, fileName, contentType string, size int64, sha256 string, now time.Time) (*Attachment, error) {
+	fileName, err := NormalizeAttachmentFileName(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Attachment{
+		CommentID:   comment.ID,
+		UserID:      userID,
+		FileName:    fileName,
+		ContentType: contentType,
+		Size:        size,
+		SHA256:      sha256,
+		CreatedAt:   now,
+	}, nil
+}
+
+// NormalizeAttachmentFileName returns the base name of the file name sent by the client without control characters.
+// Browsers on Windows may send the full path separated by backslashes.
+func NormalizeAttachmentFileName(fileName string) (string, error) {
+	fileName = path.Base(strings.Replace(fileName, `\`, "/", -1))
+	fileName = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == utf8.RuneError {
+			return -1
+		}
+		return r
+	}, fileName)
+	fileName = strings.TrimSpace(fileName)
+
+	if fileName == "" || fileName == "." || fileName == "/" || fileName == ".." {
+		return "", errors.WithStack(&RequiredError{
+			PropertyNameForDeveloper: FileNamePropertyForDeveloper,
+			PropertyNameForUser:      FileNamePropertyForUser,
+		})
+	}
+
+	if utf8.RuneCountInString(fileName) > MaxAttachmentFileNameLength {
+		return "", errors.WithStack(&InvalidParamError{
+			PropertyNameForDeveloper:  FileNamePropertyForDeveloper,
+			PropertyNameForUser:       FileNamePropertyForUser,
+			PropertyValue:             fileName,
+			InvalidReasonForDeveloper: fmt.Sprintf("longer than %d characters", MaxAttachmentFileNameLength),
+			InvalidReasonForUser:      fmt.Sprintf("ファイル名は%d文字以内にしてください", MaxAttachmentFileNameLength),
+		})
+	}
+	return fileName, nil
+}
+
+// SniffAttachmentType returns the content type sniffed from the leading bytes of the file,
+// and InvalidParamError if it is not in AllowedAttachmentTypes.
+// The type sent by the client is not trusted, because it is chosen by the client.
+func SniffAttachmentType(head []byte) (string, error) {
+	if len(head) == 0 {
+		return "", errors.WithStack(&RequiredError{
+			PropertyNameForDeveloper: FilePropertyForDeveloper,
+			PropertyNameForUser:      FilePropertyForUser,
+		})
+	}
+
+	contentType := http.DetectContentType(head)
+	for _, allowed := range AllowedAttachmentTypes {
+		if contentType == allowed {
+			return contentType, nil
+		}
+	}
+
+	return "", errors.WithStack(&InvalidParamError{
+		PropertyNameForDeveloper:  FilePropertyForDeveloper,
+		PropertyNameForUser:       FilePropertyForUser,
+		PropertyValue:             contentType,
+		InvalidReasonForDeveloper: fmt.Sprintf("content type %s is not allowed", contentType),
+		InvalidReasonForUser:      "この種類のファイルは添付できません",
+	})
+}
+
+// AttachmentTooLargeError returns InvalidParamError of the file larger than MaxAttachmentSize.
+func AttachmentTooLargeError() error {
+	return errors.WithStack(&InvalidParamError{
+		PropertyNameForDeveloper:  FilePropertyForDeveloper,
+		PropertyNameForUser:       FilePropertyForUser,
+		PropertyValue:             fmt.Sprintf("more than %d bytes", MaxAttachmentSize),
+		InvalidReasonForDeveloper: fmt.Sprintf("larger than %d bytes", MaxAttachmentSize),
+		InvalidReasonForUser:      fmt.Sprintf("ファイルは%dMBまでです", MaxAttachmentSize>>20),
+	})
+}
+
+// AttachmentBlobKey returns the key of the blob which has the content of the hex encoded SHA256.
+// Keys are split by the first 2 characters, so that a directory does not have too many files.
+func AttachmentBlobKey(sha256 string) string {
+	return "sha256/" + sha256[:2] + "/" + sha256
+}
+
+// BlobKey returns the key of the blob which has the content of the attachment.
+func (a *Attachment) BlobKey() string {
+	return AttachmentBlobKey(a.SHA256)
+}
+
+// IsImage returns whether the attachment is an image, which browsers can show inline.
+func (a *Attachment) IsImage() bool {
+	return strings.HasPrefix(a.ContentType, "image/")
+}
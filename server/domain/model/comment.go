@@ -0,0 +1,150 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// MaxCommentContentLength is the max length of the content of comment, which is the size of the column.
+const MaxCommentContentLength = 200
+
+// MaxCommentDepth is the max depth of replies.
+// Comments posted to the thread are at 0, and replies to them are at 1.
+const MaxCommentDepth = 3
+
+// Comment is Comment model
+// This is a message posted to the thread by the user.
+type Comment struct {
+	ID       uint32
+	ThreadID uint32
+	UserID   uint32
+	// ParentCommentID is 0 unless the comment is a reply, and Depth is the number of its ancestors.
+	ParentCommentID uint32
+	Depth           uint8
+	Content         string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	// DeletedAt is zero unless the comment is deleted by DeletedBy.
+	// The content of the deleted comment is kept for moderators until it is purged.
+	DeletedAt time.Time
+	DeletedBy uint32
+	// Reactions is filled only when the comments are listed for the user, in order of the first reaction.
+	Reactions []*ReactionCount
+	// Replies is filled only when the comments are listed, and is nil if the comment has no reply.
+	Replies *ReplySummary
+	// Attachments is filled only when the comments are listed, in order of upload.
+	Attachments []*Attachment
+}
+
+// ReplySummary is the summary of replies to the comment, which is shown without listing them.
+type ReplySummary struct {
+	ParentCommentID   uint32
+	ReplyCount        uint32
+	LatestReplyID     uint32
+	LatestReplyUserID uint32
+	LatestReplyAt     time.Time
+}
+
+// ReplyPage is a page of replies to the comment in order of posting.
+// HasMore is whether replies follow the last one in the page.
+type ReplyPage struct {
+	ParentCommentID uint32
+	Replies         []*Comment
+	HasMore         bool
+}
+
+// NewComment checks given content and returns Comment posted to the thread by the user.
+func NewComment(threadID, userID uint32, content string, now time.Time) (*Comment, error) {
+	content = strings.TrimSpace(content)
+	if err := ValidateCommentContent(content); err != nil {
+		return nil, err
+	}
+
+	return &Comment{
+		ThreadID:  threadID,
+		UserID:    userID,
+		Content:   content,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// NewReply checks given content and returns Comment which replies to the parent in its thread.
+// This returns NoSuchDataError if the parent is deleted, and InvalidParamError if the reply is nested too deep.
+func NewReply(parent *Comment, userID uint32, content string, now time.Time) (*Comment, error) {
+	if parent.IsDeleted() {
+		return nil, errors.WithStack(&NoSuchDataError{
+			PropertyNameForDeveloper:    ParentCommentIDPropertyForDeveloper,
+			PropertyNameForUser:         ParentCommentIDPropertyForUser,
+			PropertyValue:               parent.ID,
+			DomainModelNameForDeveloper: DomainModelNameCommentForDeveloper,
+			DomainModelNameForUser:      DomainModelNameCommentForUser,
+		})
+	}
+
+	if parent.Depth >= MaxCommentDepth {
+		return nil, errors.WithStack(&InvalidParamError{
+			PropertyNameForDeveloper:  ParentCommentIDPropertyForDeveloper,
+			PropertyNameForUser:       ParentCommentIDPropertyForUser,
+			PropertyValue:             parent.ID,
+			InvalidReasonForDeveloper: fmt.Sprintf("replies can be nested up to %d levels", MaxCommentDepth),
+			InvalidReasonForUser:      fmt.Sprintf("返信は%d段階までです", MaxCommentDepth),
+		})
+	}
+
+	comment, err := NewComment(parent.ThreadID, userID, content, now)
+	if err != nil {
+		return nil, err
+	}
+	comment.ParentCommentID = parent.ID
+	comment.Depth = parent.Depth + 1
+	return comment, nil
+}
+
+// ValidateCommentContent checks the content of comment.
+func ValidateCommentContent(content string) error {
+	if content == "" {
+		return errors.WithStack(&RequiredError{
+			PropertyNameForDeveloper: ContentPropertyForDeveloper,
+			PropertyNameForUser:      ContentPropertyForUser,
+		})
+	}
+
+	if utf8.RuneCountInString(content) > MaxCommentContentLength {
+		return errors.WithStack(&InvalidParamError{
+			PropertyNameForDeveloper:  ContentPropertyForDeveloper,
+			PropertyNameForUser:       ContentPropertyForUser,
+			PropertyValue:             content,
+			InvalidReasonForDeveloper: fmt.Sprintf("longer than %d characters", MaxCommentContentLength),
+			InvalidReasonForUser:      fmt.Sprintf("コメントは%d文字以内で入力してください", MaxCommentContentLength),
+		})
+	}
+	return nil
+}
+
+// IsDeleted returns whether the comment is deleted.
+func (c *Comment) IsDeleted() bool {
+	return !c.DeletedAt.IsZero()
+}
+
+// IsEdited returns whether the comment is edited after posting.
+func (c *Comment) IsEdited() bool {
+	return c.UpdatedAt.After(c.CreatedAt)
+}
+
+// IsReply returns whether the comment is a reply to another comment.
+func (c *Comment) IsReply() bool {
+	return c.ParentCommentID != 0
+}
+
+// Tombstone hides the content, reactions and attachments of the deleted comment,
+// which is listed only to show that it existed. Replies to it are still listed.
+func (c *Comment) Tombstone() {
+	c.Content = ""
+	c.Reactions = nil
+	c.Attachments = nil
+}
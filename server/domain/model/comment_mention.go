@@ -0,0 +1,73 @@
+package model
+
+import (
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxMentionsPerComment is the max number of users mentioned by a comment.
+// Mentions after that are ignored, so that a comment can not notify everyone.
+const MaxMentionsPerComment = 20
+
+// CommentMention is CommentMention model
+// The comment mentions the user with @name in its content.
+type CommentMention struct {
+	CommentID uint32
+	UserID    uint32
+	CreatedAt time.Time
+}
+
+// NewCommentMention returns CommentMention of the user by the comment.
+func NewCommentMention(comment *Comment, userID uint32) *CommentMention {
+	return &CommentMention{
+		CommentID: comment.ID,
+		UserID:    userID,
+		CreatedAt: comment.CreatedAt,
+	}
+}
+
+// ParseMentions returns names mentioned with @name in the content in order of appearance without duplication.
+// The name is letters, digits, marks, '_', '-' and '.' of any script, but does not end with '-' or '.',
+// which are taken as punctuation. '@' preceded by a letter, digit, mark or '_' is not a mention,
+// so that email addresses are not taken. Names longer than MaxUserNameLength are ignored.
+func ParseMentions(content string) []string {
+	names := make([]string, 0)
+	seen := make(map[string]bool)
+
+	runes := []rune(content)
+	for i := 0; i < len(runes) && len(names) < MaxMentionsPerComment; i++ {
+		if runes[i] != '@' || (i > 0 && isWordRune(runes[i-1])) {
+			continue
+		}
+
+		end := i + 1
+		for end < len(runes) && isMentionRune(runes[end]) {
+			end++
+		}
+		for end > i+1 && (runes[end-1] == '-' || runes[end-1] == '.') {
+			end--
+		}
+
+		name := string(runes[i+1 : end])
+		i = end - 1
+		if name == "" || utf8.RuneCountInString(name) > MaxUserNameLength || seen[name] {
+			continue
+		}
+
+		seen[name] = true
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// isWordRune returns whether the rune is a part of a word in any script.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r) || r == '_'
+}
+
+// isMentionRune returns whether the rune can be a part of the mentioned name.
+func isMentionRune(r rune) bool {
+	return isWordRune(r) || r == '-' || r == '.'
+}
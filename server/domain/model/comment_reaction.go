@@ -0,0 +1,69 @@
+package model
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// AllowedReactionEmojis is the emojis which users can react to comments with.
+// This is an allowlist rather than any grapheme cluster, so that the client can render all of them.
+var AllowedReactionEmojis = []string{
+	"👍", "👎", "😄", "🎉", "😕", "❤️", "🚀", "👀", "🙏", "😢",
+}
+
+// CommentReaction is CommentReaction model
+// The user reacts to the comment with the emoji. Each user can react with each emoji once.
+type CommentReaction struct {
+	CommentID uint32
+	UserID    uint32
+	Emoji     string
+	CreatedAt time.Time
+}
+
+// NewCommentReaction checks given emoji and returns CommentReaction of the user to the comment.
+func NewCommentReaction(commentID, userID uint32, emoji string, now time.Time) (*CommentReaction, error) {
+	if err := ValidateEmoji(emoji); err != nil {
+		return nil, err
+	}
+
+	return &CommentReaction{
+		CommentID: commentID,
+		UserID:    userID,
+		Emoji:     emoji,
+		CreatedAt: now,
+	}, nil
+}
+
+// ValidateEmoji checks that the emoji is in AllowedReactionEmojis.
+func ValidateEmoji(emoji string) error {
+	if emoji == "" {
+		return errors.WithStack(&RequiredError{
+			PropertyNameForDeveloper: EmojiPropertyForDeveloper,
+			PropertyNameForUser:      EmojiPropertyForUser,
+		})
+	}
+
+	for _, allowed := range AllowedReactionEmojis {
+		if emoji == allowed {
+			return nil
+		}
+	}
+
+	return errors.WithStack(&InvalidParamError{
+		PropertyNameForDeveloper:  EmojiPropertyForDeveloper,
+		PropertyNameForUser:       EmojiPropertyForUser,
+		PropertyValue:             emoji,
+		InvalidReasonForDeveloper: "not allowed emoji",
+		InvalidReasonForUser:      "この絵文字ではリアクションできません",
+	})
+}
+
+// ReactionCount is the number of reactions with the emoji to the comment.
+// ReactedByMe is whether the user who lists comments is one of them.
+type ReactionCount struct {
+	CommentID   uint32
+	Emoji       string
+	Count       uint32
+	ReactedByMe bool
+}
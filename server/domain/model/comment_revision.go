@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// CommentRevision is CommentRevision model
+// This is the content of the comment before EditorID edited it at CreatedAt.
+type CommentRevision struct {
+	ID        uint32
+	CommentID uint32
+	EditorID  uint32
+	Content   string
+	CreatedAt time.Time
+}
+
+// NewCommentRevision returns CommentRevision which keeps the current content of the comment edited by the editor.
+func NewCommentRevision(comment *Comment, editorID uint32, now time.Time) *CommentRevision {
+	return &CommentRevision{
+		CommentID: comment.ID,
+		EditorID:  editorID,
+		Content:   comment.Content,
+		CreatedAt: now,
+	}
+}
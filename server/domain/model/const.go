@@ -6,6 +6,10 @@ const InvalidID = 0
 // SessionIDAtCookie is for test.
 const SessionIDAtCookie = "SESSION_ID"
 
+// CSRFTokenAtCookie is the name of cookie which has CSRF token.
+// This is the default name of axios so that the client sends it back as header without setting.
+const CSRFTokenAtCookie = "XSRF-TOKEN"
+
 // InvalidReasonForDeveloper is InvalidReason message for developer.
 type InvalidReasonForDeveloper string
 
@@ -14,6 +18,11 @@ func (p InvalidReasonForDeveloper) String() string {
 	return string(p)
 }
 
+// Reason for developer.
+const (
+	FailedToBeginTx InvalidReasonForDeveloper = "failed to begin tx"
+)
+
 // DomainModelNameForDeveloper is Model name for developer.
 type DomainModelNameForDeveloper string
 
@@ -23,8 +32,33 @@ func (p DomainModelNameForDeveloper) String() string {
 
 // Model name for developer.
 const (
-	DomainModelNameUserForDeveloper    DomainModelNameForDeveloper = "User"
-	DomainModelNameSessionForDeveloper DomainModelNameForDeveloper = "Session"
+	DomainModelNameUserForDeveloper               DomainModelNameForDeveloper = "User"
+	DomainModelNameSessionForDeveloper            DomainModelNameForDeveloper = "Session"
+	DomainModelNameThrottleRecordForDeveloper     DomainModelNameForDeveloper = "ThrottleRecord"
+	DomainModelNameRefreshTokenForDeveloper       DomainModelNameForDeveloper = "RefreshToken"
+	DomainModelNameAPIKeyForDeveloper             DomainModelNameForDeveloper = "APIKey"
+	DomainModelNameIdentityForDeveloper           DomainModelNameForDeveloper = "Identity"
+	DomainModelNameOIDCLoginForDeveloper          DomainModelNameForDeveloper = "OIDCLogin"
+	DomainModelNameTOTPForDeveloper               DomainModelNameForDeveloper = "TOTP"
+	DomainModelNameRecoveryCodeForDeveloper       DomainModelNameForDeveloper = "RecoveryCode"
+	DomainModelNamePendingLoginForDeveloper       DomainModelNameForDeveloper = "PendingLogin"
+	DomainModelNameUserTokenForDeveloper          DomainModelNameForDeveloper = "UserToken"
+	DomainModelNameThreadForDeveloper             DomainModelNameForDeveloper = "Thread"
+	DomainModelNameCommentForDeveloper            DomainModelNameForDeveloper = "Comment"
+	DomainModelNameRoleForDeveloper               DomainModelNameForDeveloper = "Role"
+	DomainModelNameThreadModeratorForDeveloper    DomainModelNameForDeveloper = "ThreadModerator"
+	DomainModelNameThreadMemberForDeveloper       DomainModelNameForDeveloper = "ThreadMember"
+	DomainModelNameThreadInviteForDeveloper       DomainModelNameForDeveloper = "ThreadInvite"
+	DomainModelNameDirectConversationForDeveloper DomainModelNameForDeveloper = "DirectConversation"
+	DomainModelNamePresenceForDeveloper           DomainModelNameForDeveloper = "Presence"
+	DomainModelNameTypingForDeveloper             DomainModelNameForDeveloper = "Typing"
+	DomainModelNameThreadReadForDeveloper         DomainModelNameForDeveloper = "ThreadRead"
+	DomainModelNameCommentReactionForDeveloper    DomainModelNameForDeveloper = "CommentReaction"
+	DomainModelNameCommentRevisionForDeveloper    DomainModelNameForDeveloper = "CommentRevision"
+	DomainModelNameCommentMentionForDeveloper     DomainModelNameForDeveloper = "CommentMention"
+	DomainModelNameNotificationForDeveloper       DomainModelNameForDeveloper = "Notification"
+	DomainModelNameUserBlockForDeveloper          DomainModelNameForDeveloper = "UserBlock"
+	DomainModelNameAttachmentForDeveloper         DomainModelNameForDeveloper = "Attachment"
 )
 
 // DomainModelNameForUser is Model name for user.
@@ -37,8 +71,33 @@ func (p DomainModelNameForUser) String() string {
 
 // Model name for user.
 const (
-	DomainModelNameUserForUser    DomainModelNameForUser = "ユーザー"
-	DomainModelNameSessionForUser DomainModelNameForUser = "セッション"
+	DomainModelNameUserForUser               DomainModelNameForUser = "ユーザー"
+	DomainModelNameSessionForUser            DomainModelNameForUser = "セッション"
+	DomainModelNameThrottleRecordForUser     DomainModelNameForUser = "試行記録"
+	DomainModelNameRefreshTokenForUser       DomainModelNameForUser = "リフレッシュトークン"
+	DomainModelNameAPIKeyForUser             DomainModelNameForUser = "APIキー"
+	DomainModelNameIdentityForUser           DomainModelNameForUser = "外部アカウント"
+	DomainModelNameOIDCLoginForUser          DomainModelNameForUser = "外部ログイン"
+	DomainModelNameTOTPForUser               DomainModelNameForUser = "二段階認証"
+	DomainModelNameRecoveryCodeForUser       DomainModelNameForUser = "リカバリーコード"
+	DomainModelNamePendingLoginForUser       DomainModelNameForUser = "認証待ちのログイン"
+	DomainModelNameUserTokenForUser          DomainModelNameForUser = "確認用トークン"
+	DomainModelNameThreadForUser             DomainModelNameForUser = "スレッド"
+	DomainModelNameCommentForUser            DomainModelNameForUser = "コメント"
+	DomainModelNameRoleForUser               DomainModelNameForUser = "ロール"
+	DomainModelNameThreadModeratorForUser    DomainModelNameForUser = "スレッドのモデレーター"
+	DomainModelNameThreadMemberForUser       DomainModelNameForUser = "スレッドのメンバー"
+	DomainModelNameThreadInviteForUser       DomainModelNameForUser = "スレッドへの招待"
+	DomainModelNameDirectConversationForUser DomainModelNameForUser = "ダイレクトメッセージ"
+	DomainModelNamePresenceForUser           DomainModelNameForUser = "オンライン状態"
+	DomainModelNameTypingForUser             DomainModelNameForUser = "入力中"
+	DomainModelNameThreadReadForUser         DomainModelNameForUser = "既読"
+	DomainModelNameCommentReactionForUser    DomainModelNameForUser = "リアクション"
+	DomainModelNameCommentRevisionForUser    DomainModelNameForUser = "編集履歴"
+	DomainModelNameCommentMentionForUser     DomainModelNameForUser = "メンション"
+	DomainModelNameNotificationForUser       DomainModelNameForUser = "通知"
+	DomainModelNameUserBlockForUser          DomainModelNameForUser = "ブロック"
+	DomainModelNameAttachmentForUser         DomainModelNameForUser = "添付ファイル"
 )
 
 // PropertyNameForDeveloper is property name for developer.
@@ -51,9 +110,32 @@ func (p PropertyNameForDeveloper) String() string {
 
 // Property name for developer.
 const (
-	IDPropertyForDeveloper       PropertyNameForDeveloper = "id"
-	NamePropertyForDeveloper     PropertyNameForDeveloper = "name"
-	PassWordPropertyForDeveloper PropertyNameForDeveloper = "password"
+	IDPropertyForDeveloper              PropertyNameForDeveloper = "id"
+	NamePropertyForDeveloper            PropertyNameForDeveloper = "name"
+	PassWordPropertyForDeveloper        PropertyNameForDeveloper = "password"
+	KeyPropertyForDeveloper             PropertyNameForDeveloper = "key"
+	ScopePropertyForDeveloper           PropertyNameForDeveloper = "scope"
+	ExpiresAtPropertyForDeveloper       PropertyNameForDeveloper = "expiresAt"
+	SubjectPropertyForDeveloper         PropertyNameForDeveloper = "subject"
+	UserIDPropertyForDeveloper          PropertyNameForDeveloper = "userID"
+	CodePropertyForDeveloper            PropertyNameForDeveloper = "code"
+	EmailPropertyForDeveloper           PropertyNameForDeveloper = "email"
+	TitlePropertyForDeveloper           PropertyNameForDeveloper = "title"
+	ContentPropertyForDeveloper         PropertyNameForDeveloper = "content"
+	RolePropertyForDeveloper            PropertyNameForDeveloper = "role"
+	ThreadIDPropertyForDeveloper        PropertyNameForDeveloper = "threadID"
+	VisibilityPropertyForDeveloper      PropertyNameForDeveloper = "visibility"
+	ExpiresInPropertyForDeveloper       PropertyNameForDeveloper = "expiresIn"
+	TokenPropertyForDeveloper           PropertyNameForDeveloper = "token"
+	CommentIDPropertyForDeveloper       PropertyNameForDeveloper = "commentID"
+	EmojiPropertyForDeveloper           PropertyNameForDeveloper = "emoji"
+	ParentCommentIDPropertyForDeveloper PropertyNameForDeveloper = "parentCommentID"
+	AfterPropertyForDeveloper           PropertyNameForDeveloper = "after"
+	LimitPropertyForDeveloper           PropertyNameForDeveloper = "limit"
+	BeforePropertyForDeveloper          PropertyNameForDeveloper = "before"
+	QueryPropertyForDeveloper           PropertyNameForDeveloper = "q"
+	FilePropertyForDeveloper            PropertyNameForDeveloper = "file"
+	FileNamePropertyForDeveloper        PropertyNameForDeveloper = "fileName"
 )
 
 // PropertyNameForUser is Property name for user.
@@ -66,16 +148,62 @@ func (p PropertyNameForUser) String() string {
 
 // Property name for user.
 const (
-	IDPropertyForUser       PropertyNameForUser = "ID"
-	NamePropertyForUser     PropertyNameForUser = "名前"
-	PassWordPropertyForUser PropertyNameForUser = "パスワード"
+	IDPropertyForUser              PropertyNameForUser = "ID"
+	NamePropertyForUser            PropertyNameForUser = "名前"
+	PassWordPropertyForUser        PropertyNameForUser = "パスワード"
+	KeyPropertyForUser             PropertyNameForUser = "キー"
+	ScopePropertyForUser           PropertyNameForUser = "スコープ"
+	ExpiresAtPropertyForUser       PropertyNameForUser = "有効期限"
+	SubjectPropertyForUser         PropertyNameForUser = "外部アカウントID"
+	UserIDPropertyForUser          PropertyNameForUser = "ユーザーID"
+	CodePropertyForUser            PropertyNameForUser = "コード"
+	EmailPropertyForUser           PropertyNameForUser = "メールアドレス"
+	TitlePropertyForUser           PropertyNameForUser = "タイトル"
+	ContentPropertyForUser         PropertyNameForUser = "内容"
+	RolePropertyForUser            PropertyNameForUser = "ロール"
+	ThreadIDPropertyForUser        PropertyNameForUser = "スレッドID"
+	VisibilityPropertyForUser      PropertyNameForUser = "公開範囲"
+	ExpiresInPropertyForUser       PropertyNameForUser = "有効期間"
+	TokenPropertyForUser           PropertyNameForUser = "トークン"
+	CommentIDPropertyForUser       PropertyNameForUser = "コメントID"
+	EmojiPropertyForUser           PropertyNameForUser = "絵文字"
+	ParentCommentIDPropertyForUser PropertyNameForUser = "返信先コメントID"
+	AfterPropertyForUser           PropertyNameForUser = "開始位置"
+	LimitPropertyForUser           PropertyNameForUser = "件数"
+	BeforePropertyForUser          PropertyNameForUser = "終了位置"
+	QueryPropertyForUser           PropertyNameForUser = "検索条件"
+	FilePropertyForUser            PropertyNameForUser = "ファイル"
+	FileNamePropertyForUser        PropertyNameForUser = "ファイル名"
 )
 
 // PropertyNameKV is the Key/Value of PropertyNameForDeveloper and PropertyNameForUser.
 var PropertyNameKV = map[PropertyNameForDeveloper]PropertyNameForUser{
-	IDPropertyForDeveloper:       IDPropertyForUser,
-	NamePropertyForDeveloper:     NamePropertyForUser,
-	PassWordPropertyForDeveloper: PassWordPropertyForUser,
+	IDPropertyForDeveloper:              IDPropertyForUser,
+	NamePropertyForDeveloper:            NamePropertyForUser,
+	PassWordPropertyForDeveloper:        PassWordPropertyForUser,
+	KeyPropertyForDeveloper:             KeyPropertyForUser,
+	ScopePropertyForDeveloper:           ScopePropertyForUser,
+	ExpiresAtPropertyForDeveloper:       ExpiresAtPropertyForUser,
+	SubjectPropertyForDeveloper:         SubjectPropertyForUser,
+	UserIDPropertyForDeveloper:          UserIDPropertyForUser,
+	CodePropertyForDeveloper:            CodePropertyForUser,
+	EmailPropertyForDeveloper:           EmailPropertyForUser,
+	TitlePropertyForDeveloper:           TitlePropertyForUser,
+	ContentPropertyForDeveloper:         ContentPropertyForUser,
+	RolePropertyForDeveloper:            RolePropertyForUser,
+	ThreadIDPropertyForDeveloper:        ThreadIDPropertyForUser,
+	VisibilityPropertyForDeveloper:      VisibilityPropertyForUser,
+	ExpiresInPropertyForDeveloper:       ExpiresInPropertyForUser,
+	TokenPropertyForDeveloper:           TokenPropertyForUser,
+	CommentIDPropertyForDeveloper:       CommentIDPropertyForUser,
+	EmojiPropertyForDeveloper:           EmojiPropertyForUser,
+	ParentCommentIDPropertyForDeveloper: ParentCommentIDPropertyForUser,
+	AfterPropertyForDeveloper:           AfterPropertyForUser,
+	LimitPropertyForDeveloper:           LimitPropertyForUser,
+	BeforePropertyForDeveloper:          BeforePropertyForUser,
+	QueryPropertyForDeveloper:           QueryPropertyForUser,
+	FilePropertyForDeveloper:            FilePropertyForUser,
+	FileNamePropertyForDeveloper:        FileNamePropertyForUser,
 }
 
 // == for test ==
@@ -85,12 +213,60 @@ const (
 	PasswordForTest             = "testPassword"
 	UserValidIDForTest   uint32 = 1
 	UserInValidIDForTest uint32 = 2
+	EmailForTest                = "test@example.com"
 )
 
 // Session
 const (
 	SessionValidIDForTest   = "testValidSessionID12345678"
 	SessionInValidIDForTest = "testInvalidSessionID12345678"
+	SessionTokenForTest     = "testSessionToken12345678"
+)
+
+// APIKey
+const (
+	APIKeyForTest     = APIKeyPrefix + "testAPIKey12345678"
+	APIKeyNameForTest = "testAPIKeyName"
+)
+
+// TOTP
+// The secret is "12345678901234567890" in base32, which is the key of test vectors of RFC 6238.
+const (
+	TOTPSecretForTest        = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	TOTPCodeForTest          = "287082"
+	RecoveryCodeForTest      = "abcd-efgh-ijkl-mnop"
+	PendingLoginTokenForTest = "testPendingLoginToken12345678"
+)
+
+// UserToken
+const (
+	UserTokenForTest = "testUserToken12345678"
+)
+
+// Thread
+const (
+	ThreadTitleForTest              = "testThreadTitle"
+	ThreadValidIDForTest     uint32 = 1
+	ThreadInviteTokenForTest        = "testThreadInviteToken12345678"
+)
+
+// Comment
+const (
+	CommentContentForTest        = "testCommentContent"
+	CommentValidIDForTest uint32 = 1
+	EmojiForTest                 = "👍"
+)
+
+// Attachment
+const (
+	AttachmentValidIDForTest  uint32 = 1
+	AttachmentFileNameForTest        = "testAttachment.png"
+)
+
+// Client
+const (
+	ClientIPForTest  = "192.0.2.1"
+	UserAgentForTest = "testUserAgent"
 )
 
 // error message for test
@@ -0,0 +1,68 @@
+package model
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DirectConversation is DirectConversation model
+// This is the 1:1 conversation between two users, whose messages are comments of the thread specified by ThreadID.
+// The pair of users is unordered, and UserID1 is always less than UserID2 so that the pair has only one conversation.
+type DirectConversation struct {
+	ThreadID      uint32
+	UserID1       uint32
+	UserID2       uint32
+	LastMessageAt time.Time
+	CreatedAt     time.Time
+}
+
+// NewDirectConversation checks given users and returns DirectConversation between them and its thread.
+// ThreadID of the conversation is set after the thread is inserted.
+func NewDirectConversation(userID, otherUserID uint32, now time.Time) (*DirectConversation, *Thread, error) {
+	if userID == otherUserID {
+		return nil, nil, errors.WithStack(&InvalidParamError{
+			PropertyNameForDeveloper:  UserIDPropertyForDeveloper,
+			PropertyNameForUser:       UserIDPropertyForUser,
+			PropertyValue:             otherUserID,
+			InvalidReasonForDeveloper: "can not start conversation with oneself",
+			InvalidReasonForUser:      "自分自身にダイレクトメッセージは送れません",
+		})
+	}
+
+	userID1, userID2 := DirectConversationUserIDs(userID, otherUserID)
+	conversation := &DirectConversation{
+		UserID1:       userID1,
+		UserID2:       userID2,
+		LastMessageAt: now,
+		CreatedAt:     now,
+	}
+	thread := &Thread{
+		Visibility: ThreadVisibilityDirect,
+		UserID:     userID,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	return conversation, thread, nil
+}
+
+// DirectConversationUserIDs returns ids of the pair of users in the order of DirectConversation.
+func DirectConversationUserIDs(userID, otherUserID uint32) (uint32, uint32) {
+	if userID < otherUserID {
+		return userID, otherUserID
+	}
+	return otherUserID, userID
+}
+
+// HasUser returns whether the user is either of the conversation.
+func (c *DirectConversation) HasUser(userID uint32) bool {
+	return c.UserID1 == userID || c.UserID2 == userID
+}
+
+// OtherUserID returns id of the partner of the user in the conversation.
+func (c *DirectConversation) OtherUserID(userID uint32) uint32 {
+	if c.UserID1 == userID {
+		return c.UserID2
+	}
+	return c.UserID1
+}
@@ -1,6 +1,9 @@
 package model
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // RepositoryMethod define the methods of repository.
 type RepositoryMethod string
@@ -118,6 +121,51 @@ func (e *AuthenticationErr) Error() string {
 	return "invalid name or password"
 }
 
+// TooManyRequestsError represents that requests have been throttled.
+type TooManyRequestsError struct {
+	BaseErr    error
+	RetryAfter time.Duration
+}
+
+// Error returns error message.
+func (e *TooManyRequestsError) Error() string {
+	return fmt.Sprintf("too many requests, retry after %s", e.RetryAfter)
+}
+
+// InsufficientScopeError represents that the credential of the request is not allowed the operation.
+type InsufficientScopeError struct {
+	BaseErr error
+	Scope   Scope
+}
+
+// Error returns error message.
+func (e *InsufficientScopeError) Error() string {
+	return fmt.Sprintf("insufficient scope, %s is required", e.Scope)
+}
+
+// ForbiddenError represents that the user is not allowed the operation by roles.
+// This differs from InsufficientScopeError, which is about the credential of the request.
+type ForbiddenError struct {
+	BaseErr                   error
+	InvalidReasonForDeveloper string
+}
+
+// Error returns error message.
+func (e *ForbiddenError) Error() string {
+	return fmt.Sprintf("forbidden, %s", e.InvalidReasonForDeveloper)
+}
+
+// CSRFError represents that the request may be forged by another site.
+type CSRFError struct {
+	BaseErr                   error
+	InvalidReasonForDeveloper string
+}
+
+// Error returns error message.
+func (e *CSRFError) Error() string {
+	return fmt.Sprintf("csrf check failed, %s", e.InvalidReasonForDeveloper)
+}
+
 // OtherServerError is other server error.
 type OtherServerError struct {
 	BaseErr                   error
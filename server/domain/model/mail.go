@@ -0,0 +1,9 @@
+package model
+
+// Mail is the mail sent to the user.
+// Body is plain text, which may have a token, so that mails must not be logged.
+type Mail struct {
+	To      string
+	Subject string
+	Body    string `json:"-" secret:"true"`
+}
@@ -0,0 +1,52 @@
+package model
+
+import "time"
+
+// NotificationKind is the kind of notification.
+type NotificationKind string
+
+// Kinds of notification.
+const (
+	// NotificationKindMention notifies that the actor mentioned the user in the comment.
+	NotificationKindMention NotificationKind = "mention"
+)
+
+// Notification is Notification model
+// The notification is in the inbox of the user, and unread until ReadAt is set.
+// This refers to the comment but does not have its content,
+// so that the content is read through the thread which checks the policy.
+type Notification struct {
+	ID        uint32
+	UserID    uint32
+	Kind      NotificationKind
+	ThreadID  uint32
+	CommentID uint32
+	ActorID   uint32
+	CreatedAt time.Time
+	ReadAt    time.Time
+}
+
+// NewMentionNotification returns Notification to the user mentioned by the comment.
+func NewMentionNotification(comment *Comment, userID uint32) *Notification {
+	return &Notification{
+		UserID:    userID,
+		Kind:      NotificationKindMention,
+		ThreadID:  comment.ThreadID,
+		CommentID: comment.ID,
+		ActorID:   comment.UserID,
+		CreatedAt: comment.CreatedAt,
+	}
+}
+
+// IsRead returns whether the user has read the notification.
+func (n *Notification) IsRead() bool {
+	return !n.ReadAt.IsZero()
+}
+
+// NotificationPage is a page of notifications of the user in order of newest first.
+// UnreadCount is the number of all unread notifications of the user, not only in the page.
+type NotificationPage struct {
+	Notifications []*Notification
+	UnreadCount   uint32
+	HasMore       bool
+}
@@ -0,0 +1,83 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"time"
+)
+
+// OIDCLoginAtCookie is the name of cookie which binds the login in progress to the browser.
+const OIDCLoginAtCookie = "OIDC_LOGIN"
+
+// OIDCLoginTokenSize is the bytes of entropy of the token, state, nonce and code verifier of login.
+const OIDCLoginTokenSize = 32
+
+// defaultProvisionedUserName is the name of provisioned user when the provider tells no name.
+const defaultProvisionedUserName = "user"
+
+// Identity is Identity model
+// This links an user to the account of an OpenID Connect provider, which is identified by issuer and subject.
+type Identity struct {
+	ID        uint32
+	UserID    uint32
+	Issuer    string
+	Subject   string
+	CreatedAt time.Time
+}
+
+// OIDCLogin is the login in progress, which is kept until the provider redirects back.
+// Token is sent only as cookie and only its hash is stored as ID,
+// so that the callback is accepted only from the browser which started the login.
+type OIDCLogin struct {
+	ID           string
+	Token        string `json:"-" secret:"true"`
+	State        string `json:"-" secret:"true"`
+	Nonce        string `json:"-" secret:"true"`
+	CodeVerifier string `json:"-" secret:"true"`
+	ExpiresAt    time.Time
+}
+
+// OIDCLoginIDFromToken returns the id of the login which has the token.
+func OIDCLoginIDFromToken(token string) string {
+	return hashToken(token)
+}
+
+// IsExpired returns whether the login is expired at the time.
+func (l *OIDCLogin) IsExpired(now time.Time) bool {
+	return !now.Before(l.ExpiresAt)
+}
+
+// CodeChallenge returns the code challenge of PKCE by S256 method.
+func (l *OIDCLogin) CodeChallenge() string {
+	sum := sha256.Sum256([]byte(l.CodeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// OIDCClaims is the claims of the verified ID token.
+type OIDCClaims struct {
+	Issuer            string
+	Subject           string
+	Nonce             string
+	PreferredUsername string
+	Name              string
+	Email             string
+}
+
+// UserName returns the name of the user provisioned by the claims.
+// This is the first one told by the provider among preferred username, name and local part of email,
+// and is truncated to fit in the column.
+func (c *OIDCClaims) UserName() string {
+	name := defaultProvisionedUserName
+	email := c.Email
+	if i := strings.Index(email, "@"); i >= 0 {
+		email = email[:i]
+	}
+	for _, n := range []string{c.PreferredUsername, c.Name, email} {
+		if n = strings.TrimSpace(n); n != "" {
+			name = n
+			break
+		}
+	}
+	return TruncateUserName(name, MaxUserNameLength)
+}
@@ -0,0 +1,68 @@
+package model
+
+import "time"
+
+// PresenceStatus is whether the user is online.
+type PresenceStatus string
+
+// String returns as string.
+func (s PresenceStatus) String() string {
+	return string(s)
+}
+
+// Statuses of presence.
+const (
+	// PresenceStatusOnline is the user who is active in the client.
+	PresenceStatusOnline PresenceStatus = "online"
+	// PresenceStatusIdle is the user whose client is open but who has not been active for a while.
+	PresenceStatusIdle PresenceStatus = "idle"
+	// PresenceStatusAway is the user who set away or has not been active for long.
+	PresenceStatusAway PresenceStatus = "away"
+	// PresenceStatusOffline is the user whose client has not sent heartbeats.
+	PresenceStatusOffline PresenceStatus = "offline"
+)
+
+// PresenceRecord is PresenceRecord model
+// This is the last heartbeat of the user, which is forgotten after ExpiresAt.
+// LastActiveAt is the last heartbeat sent while the user was active, and Away is set by the user.
+type PresenceRecord struct {
+	UserID          uint32
+	LastHeartbeatAt time.Time
+	LastActiveAt    time.Time
+	Away            bool
+	ExpiresAt       time.Time
+}
+
+// IsExpired returns whether the client has stopped sending heartbeats.
+func (r *PresenceRecord) IsExpired(now time.Time) bool {
+	return !now.Before(r.ExpiresAt)
+}
+
+// Presence is the current status of the user.
+// LastActiveAt is zero if the user has not sent heartbeats recently.
+type Presence struct {
+	UserID       uint32
+	Status       PresenceStatus
+	LastActiveAt time.Time
+}
+
+// Typing is Typing model
+// This is the ephemeral event that the user is typing in the thread, which ends at ExpiresAt.
+type Typing struct {
+	ThreadID  uint32
+	UserID    uint32
+	StartedAt time.Time
+	ExpiresAt time.Time
+}
+
+// IsExpired returns whether the user has stopped typing.
+func (t *Typing) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
+
+// ThreadPresence is presence of participants of the thread and users typing in it.
+type ThreadPresence struct {
+	ThreadID      uint32
+	Presences     []*Presence
+	TypingUserIDs []uint32
+}
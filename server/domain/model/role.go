@@ -0,0 +1,87 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// Role is the role granted to the user, which gives permissions over the whole service.
+// Users without roles can still manage what they own, e.g. delete their own comments.
+type Role string
+
+// String returns as string.
+func (r Role) String() string {
+	return string(r)
+}
+
+// Roles of user.
+const (
+	// RoleAdmin can do everything including granting roles.
+	RoleAdmin Role = "admin"
+	// RoleModerator moderates every thread.
+	RoleModerator Role = "moderator"
+)
+
+// Permission is the operation which roles allow.
+type Permission string
+
+// String returns as string.
+func (p Permission) String() string {
+	return string(p)
+}
+
+// Permissions of roles.
+const (
+	// PermissionDeleteAnyComment allows to delete comments of others in any thread.
+	PermissionDeleteAnyComment Permission = "comments:delete_any"
+	// PermissionManageModerators allows to appoint and dismiss moderators of any thread.
+	PermissionManageModerators Permission = "moderators:manage"
+	// PermissionManageRoles allows to grant and revoke roles of users.
+	PermissionManageRoles Permission = "roles:manage"
+)
+
+// rolePermissions is the permissions which each role allows.
+var rolePermissions = map[Role][]Permission{
+	RoleAdmin:     {PermissionDeleteAnyComment, PermissionManageModerators, PermissionManageRoles},
+	RoleModerator: {PermissionDeleteAnyComment},
+}
+
+// Roles is the set of roles.
+type Roles []Role
+
+// Has returns whether the roles include the role.
+func (rs Roles) Has(role Role) bool {
+	for _, r := range rs {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Can returns whether any of the roles allows the permission.
+func (rs Roles) Can(permission Permission) bool {
+	for _, r := range rs {
+		for _, p := range rolePermissions[r] {
+			if p == permission {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ValidateRole checks the role is known.
+func ValidateRole(role Role) error {
+	if _, ok := rolePermissions[role]; !ok {
+		return errors.WithStack(&InvalidParamError{
+			PropertyNameForDeveloper:  RolePropertyForDeveloper,
+			PropertyNameForUser:       RolePropertyForUser,
+			PropertyValue:             role,
+			InvalidReasonForDeveloper: "unknown role",
+			InvalidReasonForUser:      fmt.Sprintf("%sは存在しないロールです", role),
+		})
+	}
+	return nil
+}
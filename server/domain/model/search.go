@@ -0,0 +1,265 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// MinSearchTermLength is the min length of terms, which is the token size of the ngram parser of MySQL.
+const MinSearchTermLength = 2
+
+// MaxSearchTerms is the max number of terms in a query.
+const MaxSearchTerms = 10
+
+// SearchDateLayout is the layout of dates given to after: and before: of queries.
+const SearchDateLayout = "2006-01-02"
+
+// SearchQuery is SearchQuery model
+// Comments match if their content contains all Terms case-insensitively and they match all filters which are set.
+// FromUserID is resolved from FromUserName by the application, because the query has only the name.
+// Since is inclusive and Until is exclusive.
+type SearchQuery struct {
+	Terms        []string
+	FromUserName string
+	FromUserID   uint32
+	ThreadID     uint32
+	Since        time.Time
+	Until        time.Time
+}
+
+// ParseSearchQuery parses the query of search.
+// Words separated by spaces and phrases in double quotes are terms. Filters are the following words:
+//
+//	from:name      comments posted by the user, where '@' before the name is allowed.
+//	in:id          comments in the thread.
+//	after:date     comments posted on or after the date such as 2006-01-02.
+//	before:date    comments posted before the date.
+//
+// Dates are in the local time zone of the server.
+func ParseSearchQuery(q string) (*SearchQuery, error) {
+	query := &SearchQuery{
+		Terms: make([]string, 0),
+	}
+
+	runes := []rune(q)
+	for i := 0; i < len(runes); {
+		switch {
+		case unicode.IsSpace(runes[i]):
+			i++
+		case runes[i] == '"':
+			// the phrase which is not closed lasts to the end.
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if phrase := strings.TrimSpace(string(runes[i+1 : end])); phrase != "" {
+				query.Terms = append(query.Terms, phrase)
+			}
+			i = end + 1
+		default:
+			end := i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) && runes[end] != '"' {
+				end++
+			}
+			if err := query.addWord(string(runes[i:end])); err != nil {
+				return nil, err
+			}
+			i = end
+		}
+	}
+
+	if err := query.validate(q); err != nil {
+		return nil, err
+	}
+	return query, nil
+}
+
+// addWord adds the word to the query as a filter if it is, or as a term.
+func (q *SearchQuery) addWord(word string) error {
+	colon := strings.Index(word, ":")
+	if colon <= 0 || colon == len(word)-1 {
+		q.Terms = append(q.Terms, word)
+		return nil
+	}
+
+	key, value := strings.ToLower(word[:colon]), word[colon+1:]
+	switch key {
+	case "from":
+		q.FromUserName = strings.TrimPrefix(value, "@")
+	case "in":
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil || id == 0 {
+			return invalidSearchQueryError(word, err, "in: should be id of thread", "in:にはスレッドのIDを指定してください")
+		}
+		q.ThreadID = uint32(id)
+	case "after", "before":
+		date, err := time.ParseInLocation(SearchDateLayout, value, time.Local)
+		if err != nil {
+			return invalidSearchQueryError(word, err,
+				fmt.Sprintf("%s: should be date such as %s", key, SearchDateLayout),
+				fmt.Sprintf("%s:には%sの形式で日付を指定してください", key, SearchDateLayout))
+		}
+		if key == "after" {
+			q.Since = date
+		} else {
+			q.Until = date
+		}
+	default:
+		q.Terms = append(q.Terms, word)
+	}
+	return nil
+}
+
+// validate checks the parsed query.
+func (q *SearchQuery) validate(raw string) error {
+	if len(q.Terms) == 0 && !q.HasFilter() {
+		return errors.WithStack(&RequiredError{
+			PropertyNameForDeveloper: QueryPropertyForDeveloper,
+			PropertyNameForUser:      QueryPropertyForUser,
+		})
+	}
+
+	if len(q.Terms) > MaxSearchTerms {
+		return invalidSearchQueryError(raw, nil,
+			fmt.Sprintf("more than %d terms", MaxSearchTerms),
+			fmt.Sprintf("検索語は%d個までです", MaxSearchTerms))
+	}
+
+	for _, term := range q.Terms {
+		if utf8.RuneCountInString(term) < MinSearchTermLength {
+			return invalidSearchQueryError(term, nil,
+				fmt.Sprintf("terms should be at least %d characters", MinSearchTermLength),
+				fmt.Sprintf("検索語は%d文字以上で入力してください", MinSearchTermLength))
+		}
+	}
+
+	if !q.Since.IsZero() && !q.Until.IsZero() && !q.Since.Before(q.Until) {
+		return invalidSearchQueryError(raw, nil, "after: should be earlier than before:", "after:はbefore:より前の日付を指定してください")
+	}
+	return nil
+}
+
+// HasFilter returns whether any filter other than terms is set.
+func (q *SearchQuery) HasFilter() bool {
+	return q.FromUserName != "" || q.ThreadID != 0 || !q.Since.IsZero() || !q.Until.IsZero()
+}
+
+// Matches returns whether the comment matches the query.
+// FromUserID is compared instead of FromUserName, so that this is called after it is resolved.
+func (q *SearchQuery) Matches(comment *Comment) bool {
+	if q.FromUserID != 0 && comment.UserID != q.FromUserID {
+		return false
+	}
+	if q.ThreadID != 0 && comment.ThreadID != q.ThreadID {
+		return false
+	}
+	if !q.Since.IsZero() && comment.CreatedAt.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && !comment.CreatedAt.Before(q.Until) {
+		return false
+	}
+
+	content := foldRunes(comment.Content)
+	for _, term := range q.Terms {
+		if indexRunes(content, foldRunes(term), 0) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// invalidSearchQueryError returns InvalidParamError of the query.
+func invalidSearchQueryError(value string, err error, reasonForDeveloper, reasonForUser string) error {
+	return errors.WithStack(&InvalidParamError{
+		BaseErr:                   err,
+		PropertyNameForDeveloper:  QueryPropertyForDeveloper,
+		PropertyNameForUser:       QueryPropertyForUser,
+		PropertyValue:             value,
+		InvalidReasonForDeveloper: reasonForDeveloper,
+		InvalidReasonForUser:      reasonForUser,
+	})
+}
+
+// TextRange is the range of the text from Start to before End, which are counted in characters rather than bytes.
+type TextRange struct {
+	Start int
+	End   int
+}
+
+// HighlightTerms returns ranges of the content where the terms appear case-insensitively, in order without overlap.
+func HighlightTerms(content string, terms []string) []TextRange {
+	folded := foldRunes(content)
+
+	ranges := make([]TextRange, 0)
+	for _, term := range terms {
+		t := foldRunes(term)
+		if len(t) == 0 {
+			continue
+		}
+		for i := indexRunes(folded, t, 0); i >= 0; i = indexRunes(folded, t, i+len(t)) {
+			ranges = append(ranges, TextRange{Start: i, End: i + len(t)})
+		}
+	}
+
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].Start < ranges[j].Start
+	})
+
+	merged := make([]TextRange, 0, len(ranges))
+	for _, r := range ranges {
+		if n := len(merged); n > 0 && r.Start <= merged[n-1].End {
+			if r.End > merged[n-1].End {
+				merged[n-1].End = r.End
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// foldRunes returns runes of the text in lower case.
+// Each rune is folded by itself, so that indexes are the same as the text.
+func foldRunes(text string) []rune {
+	runes := []rune(text)
+	for i, r := range runes {
+		runes[i] = unicode.ToLower(r)
+	}
+	return runes
+}
+
+// indexRunes returns the index of the first sub in s from the index from, or -1 if it does not appear.
+func indexRunes(s, sub []rune, from int) int {
+	for i := from; i+len(sub) <= len(s); i++ {
+		j := 0
+		for j < len(sub) && s[i+j] == sub[j] {
+			j++
+		}
+		if j == len(sub) {
+			return i
+		}
+	}
+	return -1
+}
+
+// SearchHit is the comment which matches the query, with ranges of its content where the terms appear.
+type SearchHit struct {
+	Comment    *Comment
+	Highlights []TextRange
+}
+
+// SearchResult is a page of comments which match the query in order of newest first.
+// The next page is searched before NextBeforeID if HasMore is true.
+type SearchResult struct {
+	Hits         []*SearchHit
+	HasMore      bool
+	NextBeforeID uint32
+}
@@ -1,12 +1,62 @@
 package model
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"time"
+	"unicode/utf8"
 )
 
+// MaxUserAgentLength is the max length of user agent kept in session.
+const MaxUserAgentLength = 255
+
+// SessionTokenSize is the bytes of entropy of session token.
+const SessionTokenSize = 32
+
 // Session is Session model
+// A user has sessions as many as devices which the user logs in from.
+// Token is sent only as cookie and only its hash is stored as ID,
+// so that sessions can not be taken over by reading the database.
 type Session struct {
-	ID        string
-	UserID    uint32
-	CreatedAt time.Time
+	ID         string
+	Token      string `json:"-" secret:"true"`
+	UserID     uint32
+	UserAgent  string
+	IPAddress  string
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+}
+
+// SessionIDFromToken returns the id of the session which has the token.
+func SessionIDFromToken(token string) string {
+	return hashToken(token)
+}
+
+// hashToken returns SHA-256 of the token in hex, which is same as SHA2(token, 256) of MySQL.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Client is the client which sends the request.
+type Client struct {
+	IPAddress string
+	UserAgent string
+}
+
+// NewClient returns Client.
+// User agent is truncated at the boundary of character so that it fits in the column.
+func NewClient(ipAddress, userAgent string) *Client {
+	if len(userAgent) > MaxUserAgentLength {
+		end := MaxUserAgentLength
+		for end > 0 && !utf8.RuneStart(userAgent[end]) {
+			end--
+		}
+		userAgent = userAgent[:end]
+	}
+
+	return &Client{
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+	}
 }
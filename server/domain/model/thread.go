@@ -0,0 +1,113 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// MaxThreadTitleLength is the max length of the title of thread, which is the size of the column.
+const MaxThreadTitleLength = 20
+
+// ThreadVisibility is who can read the thread.
+type ThreadVisibility string
+
+// String returns as string.
+func (v ThreadVisibility) String() string {
+	return string(v)
+}
+
+// Visibility of thread.
+const (
+	// ThreadVisibilityPublic allows everyone to read the thread.
+	ThreadVisibilityPublic ThreadVisibility = "public"
+	// ThreadVisibilityPrivate allows only members of the thread to read it.
+	ThreadVisibilityPrivate ThreadVisibility = "private"
+	// ThreadVisibilityDirect allows only the two users of the direct conversation to read it.
+	// This is set only by DirectConversation and can not be chosen on creating threads.
+	ThreadVisibilityDirect ThreadVisibility = "direct"
+)
+
+// Thread is Thread model
+// This is a room of chat which users post comments to, and UserID is the user who created it.
+// Title is unique among threads, and empty for the thread of the direct conversation.
+type Thread struct {
+	ID         uint32
+	Title      string
+	Visibility ThreadVisibility
+	UserID     uint32
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// NewThread checks given title and visibility and returns Thread created by the user.
+// Empty visibility means public.
+func NewThread(title string, visibility ThreadVisibility, userID uint32, now time.Time) (*Thread, error) {
+	title = strings.TrimSpace(title)
+	if err := ValidateThreadTitle(title); err != nil {
+		return nil, err
+	}
+
+	if visibility == "" {
+		visibility = ThreadVisibilityPublic
+	}
+	if err := ValidateThreadVisibility(visibility); err != nil {
+		return nil, err
+	}
+
+	return &Thread{
+		Title:      title,
+		Visibility: visibility,
+		UserID:     userID,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}, nil
+}
+
+// IsPrivate returns whether only members can read the thread.
+func (t *Thread) IsPrivate() bool {
+	return t.Visibility != ThreadVisibilityPublic
+}
+
+// IsDirect returns whether the thread is of the direct conversation.
+func (t *Thread) IsDirect() bool {
+	return t.Visibility == ThreadVisibilityDirect
+}
+
+// ValidateThreadTitle checks the title of thread.
+func ValidateThreadTitle(title string) error {
+	if title == "" {
+		return errors.WithStack(&RequiredError{
+			PropertyNameForDeveloper: TitlePropertyForDeveloper,
+			PropertyNameForUser:      TitlePropertyForUser,
+		})
+	}
+
+	if utf8.RuneCountInString(title) > MaxThreadTitleLength {
+		return errors.WithStack(&InvalidParamError{
+			PropertyNameForDeveloper:  TitlePropertyForDeveloper,
+			PropertyNameForUser:       TitlePropertyForUser,
+			PropertyValue:             title,
+			InvalidReasonForDeveloper: fmt.Sprintf("longer than %d characters", MaxThreadTitleLength),
+			InvalidReasonForUser:      fmt.Sprintf("タイトルは%d文字以内で入力してください", MaxThreadTitleLength),
+		})
+	}
+	return nil
+}
+
+// ValidateThreadVisibility checks the visibility of thread.
+func ValidateThreadVisibility(visibility ThreadVisibility) error {
+	if visibility != ThreadVisibilityPublic && visibility != ThreadVisibilityPrivate {
+		return errors.WithStack(&InvalidParamError{
+			PropertyNameForDeveloper:  VisibilityPropertyForDeveloper,
+			PropertyNameForUser:       VisibilityPropertyForUser,
+			PropertyValue:             visibility,
+			InvalidReasonForDeveloper: "not public nor private",
+			InvalidReasonForUser:      "公開範囲はpublicかprivateを指定してください",
+		})
+	}
+	return nil
+}
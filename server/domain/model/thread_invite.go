@@ -0,0 +1,83 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Invitation to thread.
+const (
+	// ThreadInviteTokenSize is the bytes of entropy of the token of invite link.
+	ThreadInviteTokenSize = 32
+	// DefaultThreadInviteTTL is the lifetime of the invitation if it is not specified.
+	DefaultThreadInviteTTL = 7 * 24 * time.Hour
+	// MaxThreadInviteTTL is the max lifetime of the invitation, so that a leaked link does not work forever.
+	MaxThreadInviteTTL = 30 * 24 * time.Hour
+)
+
+// ThreadInvite is ThreadInvite model
+// This is the link which lets users join the thread, and UserID is the user who created it.
+// Token is shown only when it is created and only its hash is stored as ID.
+// MaxUses is the number of users who can join by the invitation, and 0 means no limit.
+type ThreadInvite struct {
+	ID        string
+	Token     string `json:"-" secret:"true"`
+	ThreadID  uint32
+	UserID    uint32
+	MaxUses   uint32
+	Uses      uint32
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// ThreadInviteIDFromToken returns the id of the invitation which has the token.
+func ThreadInviteIDFromToken(token string) string {
+	return hashToken(token)
+}
+
+// NewThreadInvite checks given lifetime and limit of uses and returns ThreadInvite of the thread.
+// Zero ttl means DefaultThreadInviteTTL.
+func NewThreadInvite(token string, threadID, userID uint32, ttl time.Duration, maxUses uint32, now time.Time) (*ThreadInvite, error) {
+	if ttl == 0 {
+		ttl = DefaultThreadInviteTTL
+	}
+	if err := ValidateThreadInviteTTL(ttl); err != nil {
+		return nil, err
+	}
+
+	return &ThreadInvite{
+		ID:        ThreadInviteIDFromToken(token),
+		Token:     token,
+		ThreadID:  threadID,
+		UserID:    userID,
+		MaxUses:   maxUses,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}, nil
+}
+
+// ValidateThreadInviteTTL checks the lifetime of invitation.
+func ValidateThreadInviteTTL(ttl time.Duration) error {
+	if ttl < 0 || ttl > MaxThreadInviteTTL {
+		return errors.WithStack(&InvalidParamError{
+			PropertyNameForDeveloper:  ExpiresInPropertyForDeveloper,
+			PropertyNameForUser:       ExpiresInPropertyForUser,
+			PropertyValue:             ttl,
+			InvalidReasonForDeveloper: fmt.Sprintf("not between 0 and %s", MaxThreadInviteTTL),
+			InvalidReasonForUser:      fmt.Sprintf("有効期間は%d日以内で指定してください", int(MaxThreadInviteTTL.Hours()/24)),
+		})
+	}
+	return nil
+}
+
+// IsExpired returns whether the invitation is expired at the time.
+func (i *ThreadInvite) IsExpired(now time.Time) bool {
+	return !now.Before(i.ExpiresAt)
+}
+
+// IsUsedUp returns whether the invitation has been used as many times as the limit.
+func (i *ThreadInvite) IsUsedUp() bool {
+	return i.MaxUses != 0 && i.Uses >= i.MaxUses
+}
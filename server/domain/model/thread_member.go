@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// ThreadMemberRole is the role of the member in the thread.
+type ThreadMemberRole string
+
+// String returns as string.
+func (r ThreadMemberRole) String() string {
+	return string(r)
+}
+
+// Roles of thread member.
+const (
+	// ThreadMemberRoleOwner is the creator of the thread, who can not leave it.
+	ThreadMemberRoleOwner ThreadMemberRole = "owner"
+	// ThreadMemberRoleMember is the user who joined the thread.
+	ThreadMemberRoleMember ThreadMemberRole = "member"
+)
+
+// ThreadMember is ThreadMember model
+// Members can read the private thread, and users join public threads to follow them.
+type ThreadMember struct {
+	ThreadID  uint32
+	UserID    uint32
+	Role      ThreadMemberRole
+	CreatedAt time.Time
+}
@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// ThreadRead is ThreadRead model
+// The user has read comments of the thread up to LastReadCommentID, and comments whose ids are greater are unread.
+type ThreadRead struct {
+	ThreadID          uint32
+	UserID            uint32
+	LastReadCommentID uint32
+	UpdatedAt         time.Time
+}
+
+// ThreadUnread is unread comments of the thread for the user.
+// Comments posted by the user are not counted.
+type ThreadUnread struct {
+	ThreadID             uint32
+	UnreadCount          uint32
+	FirstUnreadCommentID uint32
+}
+
+// ThreadSummary is the thread with its unread comments for the user, which is shown in the list of threads.
+// FirstUnreadCommentID is 0 if there is no unread comment.
+type ThreadSummary struct {
+	Thread               *Thread
+	UnreadCount          uint32
+	FirstUnreadCommentID uint32
+}
@@ -0,0 +1,106 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// ThrottleKind is the kind of the key which failures are counted by.
+type ThrottleKind string
+
+// String returns as string.
+func (k ThrottleKind) String() string {
+	return string(k)
+}
+
+// Kind of throttle key.
+const (
+	ThrottleKindLoginName ThrottleKind = "login_name"
+	ThrottleKindLoginIP   ThrottleKind = "login_ip"
+	ThrottleKindSignUpIP  ThrottleKind = "signup_ip"
+	// ThrottleKindSecondFactor is counted by user id apart from password,
+	// so that passing password again does not reset failures of codes.
+	ThrottleKindSecondFactor ThrottleKind = "second_factor"
+	// ThrottleKindPasswordResetEmail is counted by email on every request of reset,
+	// so that mails are not sent to an address one after another.
+	ThrottleKindPasswordResetEmail ThrottleKind = "password_reset_email"
+)
+
+// ThrottleKey is the key which failures are counted by.
+type ThrottleKey struct {
+	Kind  ThrottleKind
+	Value string
+}
+
+// NewThrottleKey generates and returns ThrottleKey.
+func NewThrottleKey(kind ThrottleKind, value string) ThrottleKey {
+	return ThrottleKey{
+		Kind:  kind,
+		Value: value,
+	}
+}
+
+// String returns as string.
+func (k ThrottleKey) String() string {
+	return fmt.Sprintf("%s:%s", k.Kind, k.Value)
+}
+
+// ThrottleRecord is the record of failures counted by ThrottleKey.
+type ThrottleRecord struct {
+	Key         ThrottleKey
+	Failures    []time.Time
+	LockedUntil time.Time
+}
+
+// FailuresSince returns the number of failures after since.
+func (r *ThrottleRecord) FailuresSince(since time.Time) int {
+	count := 0
+	for _, f := range r.Failures {
+		if f.After(since) {
+			count++
+		}
+	}
+	return count
+}
+
+// LastFailure returns the time of the latest failure.
+func (r *ThrottleRecord) LastFailure() time.Time {
+	var last time.Time
+	for _, f := range r.Failures {
+		if f.After(last) {
+			last = f
+		}
+	}
+	return last
+}
+
+// WithoutFailure returns a copy of the record without one failure at the time.
+func (r *ThrottleRecord) WithoutFailure(at time.Time) *ThrottleRecord {
+	failures := make([]time.Time, 0, len(r.Failures))
+	removed := false
+	for _, f := range r.Failures {
+		if !removed && f.Equal(at) {
+			removed = true
+			continue
+		}
+		failures = append(failures, f)
+	}
+
+	return &ThrottleRecord{
+		Key:         r.Key,
+		Failures:    failures,
+		LockedUntil: r.LockedUntil,
+	}
+}
+
+// IsLocked returns whether the key is locked at now.
+func (r *ThrottleRecord) IsLocked(now time.Time) bool {
+	return now.Before(r.LockedUntil)
+}
+
+// ThrottleReservation is the attempt recorded to keys before the credential is checked.
+// The attempt is counted as failure unless it is released.
+type ThrottleReservation struct {
+	Keys []ThrottleKey
+	At   time.Time
+}
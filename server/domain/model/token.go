@@ -0,0 +1,50 @@
+package model
+
+import (
+	"time"
+)
+
+// RefreshTokenSize is the bytes of entropy of refresh token.
+const RefreshTokenSize = 32
+
+// AccessTokenClaims is the claims of signed access token.
+// Access token is not stored, so that it can not be revoked until it expires.
+type AccessTokenClaims struct {
+	UserID    uint32
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+// RefreshToken is RefreshToken model
+// This issues access tokens for clients which can not keep cookie, e.g. CLI tools and bots.
+// Token is sent only to the client and only its hash is stored as ID.
+type RefreshToken struct {
+	ID        string
+	Token     string `json:"-" secret:"true"`
+	UserID    uint32
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// RefreshTokenIDFromToken returns the id of the refresh token.
+func RefreshTokenIDFromToken(token string) string {
+	return hashToken(token)
+}
+
+// IsExpired returns whether the refresh token is expired at the time.
+func (t *RefreshToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
+
+// TokenPair is the pair of tokens issued to the client.
+type TokenPair struct {
+	AccessToken  string `json:"-" secret:"true"`
+	RefreshToken string `json:"-" secret:"true"`
+	ExpiresAt    time.Time
+}
+
+// PublicKey is the public key which verifies access tokens.
+type PublicKey struct {
+	ID  string
+	Key []byte
+}
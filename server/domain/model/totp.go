@@ -0,0 +1,63 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// Parameters of TOTP, which are the defaults of RFC 6238 so that every authenticator app supports them.
+const (
+	TOTPSecretSize = 20
+	TOTPDigits     = 6
+	TOTPPeriod     = 30 * time.Second
+)
+
+// RecoveryCodeCount is the number of recovery codes generated when TOTP is enabled.
+const RecoveryCodeCount = 10
+
+// RecoveryCodeSize is the bytes of entropy of recovery code.
+const RecoveryCodeSize = 10
+
+// PendingLoginTokenSize is the bytes of entropy of the token of pending login.
+const PendingLoginTokenSize = 32
+
+// MaxPendingLoginAttempts is the number of wrong codes allowed to a pending login.
+const MaxPendingLoginAttempts = 5
+
+// TOTP is TOTP model
+// This is the secret of the authenticator of the user, which is used only after Enabled.
+// LastUsedStep is the time step of the code used last, so that a code can not be used twice.
+type TOTP struct {
+	UserID       uint32
+	Secret       string `json:"-" secret:"true"`
+	Enabled      bool
+	LastUsedStep int64
+	CreatedAt    time.Time
+}
+
+// RecoveryCodeIDFromCode returns the id of the recovery code.
+// Hyphens, spaces and case are ignored so that the user can type the code as it is shown.
+func RecoveryCodeIDFromCode(code string) string {
+	code = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	return hashToken(code)
+}
+
+// PendingLogin is the login which has passed password and waits for the second factor.
+// Token is sent only to the client and only its hash is stored as ID.
+type PendingLogin struct {
+	ID        string
+	Token     string `json:"-" secret:"true"`
+	UserID    uint32
+	Attempts  int
+	ExpiresAt time.Time
+}
+
+// PendingLoginIDFromToken returns the id of the pending login which has the token.
+func PendingLoginIDFromToken(token string) string {
+	return hashToken(token)
+}
+
+// IsExpired returns whether the pending login is expired at the time.
+func (l *PendingLogin) IsExpired(now time.Time) bool {
+	return !now.Before(l.ExpiresAt)
+}
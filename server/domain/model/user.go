@@ -1,15 +1,107 @@
 package model
 
 import (
+	"net/mail"
+	"strings"
 	"time"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
 )
 
+// MaxUserNameLength is the max length of the name of user, which is the size of the column.
+const MaxUserNameLength = 30
+
+// MaxEmailLength is the max length of email address, which is the limit of SMTP path.
+const MaxEmailLength = 254
+
 // User is User model
+// This is internal representation and must not be serialized as it is.
+// Field tagged secret must never appear in response.
+// Email is optional and empty if not set, and password is reset only by the verified email.
+// Disabled user is kept but can not log in or use any credential until enabled by operators.
 type User struct {
-	ID        uint32    `json:"id"`
-	Name      string    `json:"name"`
-	SessionID string    `json:"sessionId"`
-	Password  string    `json:"password"`
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
+	ID            uint32
+	Name          string
+	Password      string `json:"-" secret:"true"`
+	Email         string
+	EmailVerified bool
+	Disabled      bool
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// NewUser checks given name and password and returns User.
+// Password is not hashed here.
+func NewUser(name, password string) (*User, error) {
+	if err := ValidateUserName(name); err != nil {
+		return nil, err
+	}
+
+	if err := ValidatePassword(password); err != nil {
+		return nil, err
+	}
+
+	return &User{
+		Name:     name,
+		Password: password,
+	}, nil
+}
+
+// ValidateUserName checks the name of user.
+func ValidateUserName(name string) error {
+	if name == "" {
+		return errors.WithStack(&RequiredError{
+			PropertyNameForDeveloper: NamePropertyForDeveloper,
+			PropertyNameForUser:      NamePropertyForUser,
+		})
+	}
+	return nil
+}
+
+// ValidatePassword checks the raw password of user.
+func ValidatePassword(password string) error {
+	if password == "" {
+		return errors.WithStack(&RequiredError{
+			PropertyNameForDeveloper: PassWordPropertyForDeveloper,
+			PropertyNameForUser:      PassWordPropertyForUser,
+		})
+	}
+	return nil
+}
+
+// NormalizeEmail trims spaces and lowercases the email, so that the same address is not registered twice.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// ValidateEmail checks the email of user, which must be a bare address such as user@example.com.
+func ValidateEmail(email string) error {
+	if email == "" {
+		return errors.WithStack(&RequiredError{
+			PropertyNameForDeveloper: EmailPropertyForDeveloper,
+			PropertyNameForUser:      EmailPropertyForUser,
+		})
+	}
+
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email || addr.Name != "" || len(email) > MaxEmailLength {
+		return errors.WithStack(&InvalidParamError{
+			BaseErr:                   err,
+			PropertyNameForDeveloper:  EmailPropertyForDeveloper,
+			PropertyNameForUser:       EmailPropertyForUser,
+			PropertyValue:             email,
+			InvalidReasonForDeveloper: "not an address such as user@example.com",
+			InvalidReasonForUser:      "メールアドレスはuser@example.comの形式で入力してください",
+		})
+	}
+	return nil
+}
+
+// TruncateUserName truncates the name to max characters.
+func TruncateUserName(name string, max int) string {
+	if utf8.RuneCountInString(name) <= max {
+		return name
+	}
+	return string([]rune(name)[:max])
 }
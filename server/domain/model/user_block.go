@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+// UserBlock is UserBlock model
+// The user specified by UserID blocks BlockedUserID, and neither of them can send direct messages to the other.
+type UserBlock struct {
+	UserID        uint32
+	BlockedUserID uint32
+	CreatedAt     time.Time
+}
@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+// UserTokenSize is the bytes of entropy of the token sent by email.
+const UserTokenSize = 32
+
+// UserTokenPurpose is the purpose which the token is used for.
+type UserTokenPurpose string
+
+// String returns as string.
+func (p UserTokenPurpose) String() string {
+	return string(p)
+}
+
+// Purpose of user token.
+const (
+	UserTokenPurposeVerifyEmail   UserTokenPurpose = "verify_email"
+	UserTokenPurposeResetPassword UserTokenPurpose = "reset_password"
+)
+
+// UserToken is UserToken model
+// This is the token sent to the email of the user to prove that the user receives mails at the address.
+// Token is sent only by email and only its hash is stored as ID, and the token is deleted when it is used.
+// Email is the address which the token was sent to, so that the token is invalid after the email is changed.
+type UserToken struct {
+	ID        string
+	Token     string `json:"-" secret:"true"`
+	UserID    uint32
+	Purpose   UserTokenPurpose
+	Email     string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// UserTokenIDFromToken returns the id of the user token which has the token.
+func UserTokenIDFromToken(token string) string {
+	return hashToken(token)
+}
+
+// IsExpired returns whether the token is expired at the time.
+func (t *UserToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
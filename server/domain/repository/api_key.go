@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+)
+
+// APIKeyRepository is repository of API key.
+// API key is stored by ID which is hash of the key, and the key itself is never stored.
+type APIKeyRepository interface {
+	GetAPIKeyByKey(m SQLManager, key string) (*model.APIKey, error)
+	GetAPIKeysByUserID(m SQLManager, userID uint32) ([]*model.APIKey, error)
+	InsertAPIKey(m SQLManager, apiKey *model.APIKey) error
+	UpdateLastUsed(m SQLManager, id string, at time.Time) error
+	DeleteAPIKey(m SQLManager, id string) error
+	DeleteAPIKeysByUserID(m SQLManager, userID uint32) error
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+)
+
+// AttachmentRepository is repository of files attached to comments.
+type AttachmentRepository interface {
+	// ListAttachmentsByCommentIDs returns attachments of the comments by one query, in order of comment id and then of upload.
+	// This returns empty list if commentIDs is empty.
+	ListAttachmentsByCommentIDs(m SQLManager, commentIDs []uint32) ([]*model.Attachment, error)
+	// ListAttachmentsOfDeletedComments returns attachments of comments deleted before the time.
+	ListAttachmentsOfDeletedComments(m SQLManager, deletedBefore time.Time) ([]*model.Attachment, error)
+	GetAttachmentByID(m SQLManager, id uint32) (*model.Attachment, error)
+	CountAttachmentsByCommentID(m SQLManager, commentID uint32) (int, error)
+	// CountAttachmentsBySHA256ForUpdate counts attachments which share the blob of the content in the tx,
+	// and locks the hash until the tx ends, so that no attachment of the hash is inserted meanwhile.
+	CountAttachmentsBySHA256ForUpdate(m SQLManager, sha256 string) (int, error)
+	InsertAttachment(m SQLManager, attachment *model.Attachment) (uint32, error)
+	DeleteAttachment(m SQLManager, id uint32) error
+}
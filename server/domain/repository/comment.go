@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+)
+
+// CommentRepository is repository of comment.
+type CommentRepository interface {
+	// ListCommentsByThreadID returns comments posted to the thread which are not replies.
+	// Deleted comments are returned too, so that they are listed as tombstones.
+	ListCommentsByThreadID(m SQLManager, threadID uint32) ([]*model.Comment, error)
+	// ListRepliesByParentID returns at most limit replies to the comment whose ids are greater than afterID.
+	ListRepliesByParentID(m SQLManager, parentID, afterID uint32, limit int) ([]*model.Comment, error)
+	// GetReplySummariesByParentIDs summarizes replies to the comments by one query.
+	GetReplySummariesByParentIDs(m SQLManager, parentIDs []uint32) ([]*model.ReplySummary, error)
+	GetCommentByID(m SQLManager, id uint32) (*model.Comment, error)
+	// GetCommentsByIDs returns comments which exist among the ids in order of newest first.
+	GetCommentsByIDs(m SQLManager, ids []uint32) ([]*model.Comment, error)
+	// ListUndeletedCommentsAfterID returns at most limit comments which are not deleted whose ids are greater than afterID.
+	ListUndeletedCommentsAfterID(m SQLManager, afterID uint32, limit int) ([]*model.Comment, error)
+	InsertComment(m SQLManager, comment *model.Comment) (uint32, error)
+	UpdateCommentContent(m SQLManager, id uint32, content string, updatedAt time.Time) error
+	// SoftDeleteComment returns NoSuchDataError if the comment does not exist or is already deleted.
+	SoftDeleteComment(m SQLManager, id, deletedBy uint32, deletedAt time.Time) error
+	// PurgeDeletedComments clears content of comments deleted before the time, and returns the number of them.
+	PurgeDeletedComments(m SQLManager, deletedBefore time.Time) (int64, error)
+}
@@ -0,0 +1,13 @@
+package repository
+
+import "github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+
+// CommentMentionRepository is repository of users mentioned by comments.
+type CommentMentionRepository interface {
+	GetMentionedUserIDsByCommentID(m SQLManager, commentID uint32) ([]uint32, error)
+	// InsertCommentMentions inserts all mentions by one query, and does nothing if mentions is empty.
+	InsertCommentMentions(m SQLManager, mentions []*model.CommentMention) error
+	// DeleteCommentMentions deletes mentions of the users by the comment, and does nothing if userIDs is empty.
+	DeleteCommentMentions(m SQLManager, commentID uint32, userIDs []uint32) error
+	DeleteCommentMentionsByUserID(m SQLManager, userID uint32) error
+}
@@ -0,0 +1,15 @@
+package repository
+
+import "github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+
+// CommentReactionRepository is repository of reactions to comments.
+type CommentReactionRepository interface {
+	// GetReactionCountsByCommentIDs aggregates reactions to the comments by one query.
+	GetReactionCountsByCommentIDs(m SQLManager, userID uint32, commentIDs []uint32) ([]*model.ReactionCount, error)
+	// InsertCommentReaction does nothing if the same reaction exists.
+	InsertCommentReaction(m SQLManager, reaction *model.CommentReaction) error
+	// DeleteCommentReaction does nothing if the reaction does not exist.
+	DeleteCommentReaction(m SQLManager, commentID, userID uint32, emoji string) error
+	DeleteCommentReactionsByCommentID(m SQLManager, commentID uint32) error
+	DeleteCommentReactionsByUserID(m SQLManager, userID uint32) error
+}
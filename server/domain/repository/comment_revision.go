@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+)
+
+// CommentRevisionRepository is repository of edit history of comments.
+type CommentRevisionRepository interface {
+	ListCommentRevisionsByCommentID(m SQLManager, commentID uint32) ([]*model.CommentRevision, error)
+	InsertCommentRevision(m SQLManager, revision *model.CommentRevision) (uint32, error)
+	// DeleteRevisionsOfDeletedComments deletes revisions of comments deleted before the time, which are purged with them.
+	DeleteRevisionsOfDeletedComments(m SQLManager, deletedBefore time.Time) error
+}
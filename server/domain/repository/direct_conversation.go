@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+)
+
+// DirectConversationRepository is repository of direct conversations.
+type DirectConversationRepository interface {
+	// GetDirectConversationByUserIDs returns NoSuchDataError if the pair of users has no conversation.
+	// The order of given ids does not matter.
+	GetDirectConversationByUserIDs(m SQLManager, userID, otherUserID uint32) (*model.DirectConversation, error)
+	GetDirectConversationByThreadID(m SQLManager, threadID uint32) (*model.DirectConversation, error)
+	// ListDirectConversationsByUserID returns conversations of the user in order of last activity, the newest first.
+	ListDirectConversationsByUserID(m SQLManager, userID uint32) ([]*model.DirectConversation, error)
+	InsertDirectConversation(m SQLManager, conversation *model.DirectConversation) error
+	UpdateLastMessageAt(m SQLManager, threadID uint32, at time.Time) error
+}
@@ -0,0 +1,10 @@
+package repository
+
+import "github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+
+// IdentityRepository is repository of identity of external provider.
+type IdentityRepository interface {
+	GetIdentity(m SQLManager, issuer, subject string) (*model.Identity, error)
+	InsertIdentity(m SQLManager, identity *model.Identity) (uint32, error)
+	DeleteIdentitiesByUserID(m SQLManager, userID uint32) error
+}
@@ -6,6 +6,7 @@ package mock_repository
 
 import (
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	model "github.com/hideUW/nuxt-go-chat-app/server/domain/model"
@@ -50,6 +51,36 @@ func (mr *MockSessionRepositoryMockRecorder) GetSessionByID(m, id interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSessionByID", reflect.TypeOf((*MockSessionRepository)(nil).GetSessionByID), m, id)
 }
 
+// GetSessionByToken mocks base method
+func (m_2 *MockSessionRepository) GetSessionByToken(m repository.SQLManager, token string) (*model.Session, error) {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "GetSessionByToken", m, token)
+	ret0, _ := ret[0].(*model.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSessionByToken indicates an expected call of GetSessionByToken
+func (mr *MockSessionRepositoryMockRecorder) GetSessionByToken(m, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSessionByToken", reflect.TypeOf((*MockSessionRepository)(nil).GetSessionByToken), m, token)
+}
+
+// GetSessionsByUserID mocks base method
+func (m_2 *MockSessionRepository) GetSessionsByUserID(m repository.SQLManager, userID uint32) ([]*model.Session, error) {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "GetSessionsByUserID", m, userID)
+	ret0, _ := ret[0].([]*model.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSessionsByUserID indicates an expected call of GetSessionsByUserID
+func (mr *MockSessionRepositoryMockRecorder) GetSessionsByUserID(m, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSessionsByUserID", reflect.TypeOf((*MockSessionRepository)(nil).GetSessionsByUserID), m, userID)
+}
+
 // InsertSession mocks base method
 func (m_2 *MockSessionRepository) InsertSession(m repository.SQLManager, user *model.Session) error {
 	m_2.ctrl.T.Helper()
@@ -64,6 +95,20 @@ func (mr *MockSessionRepositoryMockRecorder) InsertSession(m, user interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertSession", reflect.TypeOf((*MockSessionRepository)(nil).InsertSession), m, user)
 }
 
+// UpdateLastSeen mocks base method
+func (m_2 *MockSessionRepository) UpdateLastSeen(m repository.SQLManager, id, ipAddress string, at time.Time) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "UpdateLastSeen", m, id, ipAddress, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLastSeen indicates an expected call of UpdateLastSeen
+func (mr *MockSessionRepositoryMockRecorder) UpdateLastSeen(m, id, ipAddress, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLastSeen", reflect.TypeOf((*MockSessionRepository)(nil).UpdateLastSeen), m, id, ipAddress, at)
+}
+
 // DeleteSession mocks base method
 func (m_2 *MockSessionRepository) DeleteSession(m repository.SQLManager, id string) error {
 	m_2.ctrl.T.Helper()
@@ -77,3 +122,31 @@ func (mr *MockSessionRepositoryMockRecorder) DeleteSession(m, id interface{}) *g
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSession", reflect.TypeOf((*MockSessionRepository)(nil).DeleteSession), m, id)
 }
+
+// DeleteSessionsByUserID mocks base method
+func (m_2 *MockSessionRepository) DeleteSessionsByUserID(m repository.SQLManager, userID uint32) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "DeleteSessionsByUserID", m, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSessionsByUserID indicates an expected call of DeleteSessionsByUserID
+func (mr *MockSessionRepositoryMockRecorder) DeleteSessionsByUserID(m, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSessionsByUserID", reflect.TypeOf((*MockSessionRepository)(nil).DeleteSessionsByUserID), m, userID)
+}
+
+// DeleteOtherSessions mocks base method
+func (m_2 *MockSessionRepository) DeleteOtherSessions(m repository.SQLManager, userID uint32, id string) error {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "DeleteOtherSessions", m, userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOtherSessions indicates an expected call of DeleteOtherSessions
+func (mr *MockSessionRepositoryMockRecorder) DeleteOtherSessions(m, userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOtherSessions", reflect.TypeOf((*MockSessionRepository)(nil).DeleteOtherSessions), m, userID, id)
+}
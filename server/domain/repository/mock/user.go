@@ -65,6 +65,21 @@ func (mr *MockUserRepositoryMockRecorder) GetUserByName(m, name interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByName", reflect.TypeOf((*MockUserRepository)(nil).GetUserByName), m, name)
 }
 
+// GetUserByEmail mocks base method
+func (m_2 *MockUserRepository) GetUserByEmail(m repository.SQLManager, email string) (*model.User, error) {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "GetUserByEmail", m, email)
+	ret0, _ := ret[0].(*model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByEmail indicates an expected call of GetUserByEmail
+func (mr *MockUserRepositoryMockRecorder) GetUserByEmail(m, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockUserRepository)(nil).GetUserByEmail), m, email)
+}
+
 // InsertUser mocks base method
 func (m_2 *MockUserRepository) InsertUser(m repository.SQLManager, user *model.User) (uint32, error) {
 	m_2.ctrl.T.Helper()
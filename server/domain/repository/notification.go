@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+)
+
+// NotificationRepository is repository of notifications in the inbox of each user.
+type NotificationRepository interface {
+	// ListNotificationsByUserID returns at most limit notifications of the user older than beforeID in order of newest first.
+	// beforeID 0 means from the newest one.
+	ListNotificationsByUserID(m SQLManager, userID, beforeID uint32, limit int) ([]*model.Notification, error)
+	CountUnreadNotificationsByUserID(m SQLManager, userID uint32) (uint32, error)
+	GetNotificationByID(m SQLManager, id uint32) (*model.Notification, error)
+	// InsertNotifications inserts all notifications by one query, and does nothing if notifications is empty.
+	InsertNotifications(m SQLManager, notifications []*model.Notification) error
+	MarkNotificationRead(m SQLManager, id uint32, readAt time.Time) error
+	MarkAllNotificationsRead(m SQLManager, userID uint32, readAt time.Time) error
+	DeleteNotificationsByUserID(m SQLManager, userID uint32) error
+}
@@ -0,0 +1,11 @@
+package repository
+
+import "github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+
+// OIDCLoginRepository is repository of login in progress.
+// This is not bound to SQL because logins live only for minutes.
+type OIDCLoginRepository interface {
+	InsertOIDCLogin(login *model.OIDCLogin) error
+	// TakeOIDCLogin gets and deletes the login, so that it can be used only once.
+	TakeOIDCLogin(id string) (*model.OIDCLogin, error)
+}
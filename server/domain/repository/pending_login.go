@@ -0,0 +1,11 @@
+package repository
+
+import "github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+
+// PendingLoginRepository is repository of login which waits for the second factor.
+// This is not bound to SQL because pending logins live only for minutes.
+type PendingLoginRepository interface {
+	InsertPendingLogin(login *model.PendingLogin) error
+	// TakePendingLogin gets and deletes the login, so that it can be used only once.
+	TakePendingLogin(id string) (*model.PendingLogin, error)
+}
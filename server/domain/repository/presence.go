@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+)
+
+// PresenceRepository is repository of presence of users and typing in threads.
+// This is not bound to SQL because records live only for seconds,
+// and it can be implemented by a store shared among servers.
+type PresenceRepository interface {
+	// GetPresenceRecord returns NoSuchDataError if the user has no record, which may be expired.
+	GetPresenceRecord(userID uint32) (*model.PresenceRecord, error)
+	// GetPresenceRecordsByUserIDs returns records of the users which are not expired at now.
+	GetPresenceRecordsByUserIDs(userIDs []uint32, now time.Time) ([]*model.PresenceRecord, error)
+	PutPresenceRecord(record *model.PresenceRecord) error
+	DeletePresenceRecord(userID uint32) error
+	// GetTyping returns NoSuchDataError if the user has no typing in the thread, which may be expired.
+	GetTyping(threadID, userID uint32) (*model.Typing, error)
+	// GetTypingsByThreadID returns typings in the thread which are not expired at now.
+	GetTypingsByThreadID(threadID uint32, now time.Time) ([]*model.Typing, error)
+	PutTyping(typing *model.Typing) error
+	DeleteTyping(threadID, userID uint32) error
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+)
+
+// RefreshTokenRepository is repository of refresh token.
+// Refresh token is stored by ID which is hash of the token, and the token itself is never stored.
+type RefreshTokenRepository interface {
+	GetRefreshTokenByToken(m SQLManager, token string) (*model.RefreshToken, error)
+	InsertRefreshToken(m SQLManager, refreshToken *model.RefreshToken) error
+	UseRefreshToken(m SQLManager, id string, at time.Time) error
+	DeleteRefreshToken(m SQLManager, id string) error
+	DeleteRefreshTokensByUserID(m SQLManager, userID uint32) error
+}
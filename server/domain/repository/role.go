@@ -0,0 +1,14 @@
+package repository
+
+import "github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+
+// RoleRepository is repository of roles of users.
+type RoleRepository interface {
+	// GetRolesByUserID returns empty roles if the user has no role.
+	GetRolesByUserID(m SQLManager, userID uint32) (model.Roles, error)
+	// InsertRole does nothing if the user already has the role.
+	InsertRole(m SQLManager, userID uint32, role model.Role) error
+	// DeleteRole returns NoSuchDataError if the user does not have the role.
+	DeleteRole(m SQLManager, userID uint32, role model.Role) error
+	DeleteRolesByUserID(m SQLManager, userID uint32) error
+}
@@ -0,0 +1,15 @@
+package repository
+
+import "github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+
+// SearchRepository is repository of the index for full-text search of comments.
+// This finds candidates only, and the application reads comments and checks whether the user can read them.
+type SearchRepository interface {
+	// IndexComment adds the comment to the index, or replaces the one which has the same id.
+	IndexComment(m SQLManager, comment *model.Comment) error
+	// RemoveComment does nothing if the comment is not indexed.
+	RemoveComment(m SQLManager, commentID uint32) error
+	// SearchCommentIDs returns at most limit ids of comments which are not deleted and match the query,
+	// whose ids are less than beforeID in order of newest first. beforeID 0 means from the newest one.
+	SearchCommentIDs(m SQLManager, query *model.SearchQuery, beforeID uint32, limit int) ([]uint32, error)
+}
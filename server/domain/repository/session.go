@@ -1,12 +1,20 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
 )
 
 // SessionRepository is repository of session.
+// Session is stored by ID which is hash of the token, and the token itself is never stored.
 type SessionRepository interface {
 	GetSessionByID(m SQLManager, id string) (*model.Session, error)
+	GetSessionByToken(m SQLManager, token string) (*model.Session, error)
+	GetSessionsByUserID(m SQLManager, userID uint32) ([]*model.Session, error)
 	InsertSession(m SQLManager, user *model.Session) error
+	UpdateLastSeen(m SQLManager, id string, ipAddress string, at time.Time) error
 	DeleteSession(m SQLManager, id string) error
+	DeleteSessionsByUserID(m SQLManager, userID uint32) error
+	DeleteOtherSessions(m SQLManager, userID uint32, id string) error
 }
@@ -0,0 +1,15 @@
+package repository
+
+import "github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+
+// ThreadRepository is repository of thread.
+type ThreadRepository interface {
+	// ListThreadsForUser returns public threads and private threads which the user is a member of.
+	ListThreadsForUser(m SQLManager, userID uint32) ([]*model.Thread, error)
+	GetThreadByID(m SQLManager, id uint32) (*model.Thread, error)
+	// GetPublicThreadByTitle returns the public thread which has the title.
+	// Titles are unique only among public threads, and private threads are never returned.
+	GetPublicThreadByTitle(m SQLManager, title string) (*model.Thread, error)
+	InsertThread(m SQLManager, thread *model.Thread) (uint32, error)
+	UpdateThreadOwner(m SQLManager, id, userID uint32) error
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+)
+
+// ThreadInviteRepository is repository of invitations to threads.
+// Invitation is stored by ID which is hash of the token, and the token itself is never stored.
+type ThreadInviteRepository interface {
+	GetThreadInviteByToken(m SQLManager, token string) (*model.ThreadInvite, error)
+	InsertThreadInvite(m SQLManager, invite *model.ThreadInvite) error
+	// UseThreadInvite counts a use of the invitation,
+	// and returns NoSuchDataError if it is used up or expired at the time, so that the limit is kept under concurrent uses.
+	UseThreadInvite(m SQLManager, id string, at time.Time) error
+	DeleteThreadInvite(m SQLManager, id string) error
+	DeleteThreadInvitesByUserID(m SQLManager, userID uint32) error
+}
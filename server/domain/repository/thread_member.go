@@ -0,0 +1,19 @@
+package repository
+
+import "github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+
+// ThreadMemberRepository is repository of members of each thread.
+type ThreadMemberRepository interface {
+	// GetThreadMember returns NoSuchDataError if the user is not a member of the thread.
+	GetThreadMember(m SQLManager, threadID, userID uint32) (*model.ThreadMember, error)
+	// GetThreadMembersByThreadID returns members of the thread in order of joining.
+	GetThreadMembersByThreadID(m SQLManager, threadID uint32) ([]*model.ThreadMember, error)
+	// GetThreadMembersByUserID returns memberships of the user in all threads.
+	GetThreadMembersByUserID(m SQLManager, userID uint32) ([]*model.ThreadMember, error)
+	// InsertThreadMember does nothing if the user is already a member of the thread.
+	InsertThreadMember(m SQLManager, member *model.ThreadMember) error
+	// DeleteThreadMember returns NoSuchDataError if the user is not a member of the thread.
+	DeleteThreadMember(m SQLManager, threadID, userID uint32) error
+	UpdateThreadMemberRole(m SQLManager, threadID, userID uint32, role model.ThreadMemberRole) error
+	DeleteThreadMembersByUserID(m SQLManager, userID uint32) error
+}
@@ -0,0 +1,13 @@
+package repository
+
+// ThreadModeratorRepository is repository of moderators of each thread.
+type ThreadModeratorRepository interface {
+	// GetModeratorIDsByThreadID returns ids of moderators of the thread, which is empty if no one moderates it.
+	GetModeratorIDsByThreadID(m SQLManager, threadID uint32) ([]uint32, error)
+	IsThreadModerator(m SQLManager, threadID, userID uint32) (bool, error)
+	// InsertThreadModerator does nothing if the user already moderates the thread.
+	InsertThreadModerator(m SQLManager, threadID, userID uint32) error
+	// DeleteThreadModerator returns NoSuchDataError if the user does not moderate the thread.
+	DeleteThreadModerator(m SQLManager, threadID, userID uint32) error
+	DeleteThreadModeratorsByUserID(m SQLManager, userID uint32) error
+}
@@ -0,0 +1,13 @@
+package repository
+
+import "github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+
+// ThreadReadRepository is repository of how far each user has read threads.
+type ThreadReadRepository interface {
+	// GetThreadUnreadsByThreadIDs returns unread comments of the threads for the user by one query.
+	// Threads which have no unread comment are not included.
+	GetThreadUnreadsByThreadIDs(m SQLManager, userID uint32, threadIDs []uint32) ([]*model.ThreadUnread, error)
+	// UpsertThreadRead does not move LastReadCommentID backwards.
+	UpsertThreadRead(m SQLManager, read *model.ThreadRead) error
+	DeleteThreadReadsByUserID(m SQLManager, userID uint32) error
+}
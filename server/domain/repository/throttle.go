@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+)
+
+// ThrottleRepository is repository of throttle record.
+// This is not bound to SQL so that it can be implemented by a store shared among servers.
+type ThrottleRepository interface {
+	GetThrottleRecord(key model.ThrottleKey) (*model.ThrottleRecord, error)
+	AddThrottleFailure(key model.ThrottleKey, at time.Time, window time.Duration) (*model.ThrottleRecord, error)
+	RemoveThrottleFailure(key model.ThrottleKey, at time.Time) error
+	LockThrottleRecord(key model.ThrottleKey, until time.Time) error
+	DeleteThrottleRecord(key model.ThrottleKey) error
+}
@@ -0,0 +1,18 @@
+package repository
+
+import "github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+
+// TOTPRepository is repository of TOTP and recovery codes of users.
+type TOTPRepository interface {
+	GetTOTPByUserID(m SQLManager, userID uint32) (*model.TOTP, error)
+	// SaveTOTP inserts the TOTP or replaces the one of the user.
+	SaveTOTP(m SQLManager, totp *model.TOTP) error
+	// UseTOTPStep records the step as used only if it is after the last used one,
+	// and returns NoSuchDataError otherwise, so that the same code can not pass twice even at the same time.
+	UseTOTPStep(m SQLManager, userID uint32, step int64) error
+	DeleteTOTP(m SQLManager, userID uint32) error
+	InsertRecoveryCodes(m SQLManager, userID uint32, ids []string) error
+	// DeleteRecoveryCode returns NoSuchDataError if the code does not exist, so that a code is used only once.
+	DeleteRecoveryCode(m SQLManager, userID uint32, id string) error
+	DeleteRecoveryCodesByUserID(m SQLManager, userID uint32) error
+}
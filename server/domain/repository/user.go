@@ -6,6 +6,7 @@ import "github.com/hideUW/nuxt-go-chat-app/server/domain/model"
 type UserRepository interface {
 	GetUserByID(m SQLManager, id uint32) (*model.User, error)
 	GetUserByName(m SQLManager, name string) (*model.User, error)
+	GetUserByEmail(m SQLManager, email string) (*model.User, error)
 	InsertUser(m SQLManager, user *model.User) (uint32, error)
 	UpdateUser(m SQLManager, id uint32, user *model.User) error
 	DeleteUser(m SQLManager, id uint32) error
@@ -0,0 +1,17 @@
+package repository
+
+import "github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+
+// UserBlockRepository is repository of blocks between users.
+type UserBlockRepository interface {
+	// GetUserBlocksBetween returns blocks in either direction between the users, which is empty if neither blocks the other.
+	GetUserBlocksBetween(m SQLManager, userID, otherUserID uint32) ([]*model.UserBlock, error)
+	// GetUserBlocksByUserID returns blocks by the user in order of blocking.
+	GetUserBlocksByUserID(m SQLManager, userID uint32) ([]*model.UserBlock, error)
+	// InsertUserBlock does nothing if the user already blocks the other.
+	InsertUserBlock(m SQLManager, block *model.UserBlock) error
+	// DeleteUserBlock returns NoSuchDataError if the user does not block the other.
+	DeleteUserBlock(m SQLManager, userID, blockedUserID uint32) error
+	// DeleteUserBlocksByUserID deletes blocks by and against the user.
+	DeleteUserBlocksByUserID(m SQLManager, userID uint32) error
+}
@@ -0,0 +1,13 @@
+package repository
+
+import "github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+
+// UserTokenRepository is repository of tokens sent to users by email.
+type UserTokenRepository interface {
+	GetUserToken(m SQLManager, id string) (*model.UserToken, error)
+	InsertUserToken(m SQLManager, token *model.UserToken) error
+	// DeleteUserToken returns NoSuchDataError if the token does not exist, so that a token is used only once.
+	DeleteUserToken(m SQLManager, id string) error
+	DeleteUserTokensByPurpose(m SQLManager, userID uint32, purpose model.UserTokenPurpose) error
+	DeleteUserTokensByUserID(m SQLManager, userID uint32) error
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ed25519"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+)
+
+// accessTokenAlgorithm is the algorithm of access token, which is Ed25519 in JWS.
+const accessTokenAlgorithm = "EdDSA"
+
+// AccessTokenService is interface of domain service of access token.
+// Access token is JWT signed by Ed25519, which can be verified only with public keys.
+type AccessTokenService interface {
+	Sign(claims *model.AccessTokenClaims) (string, error)
+	Verify(token string) (*model.AccessTokenClaims, error)
+	PublicKeys() []*model.PublicKey
+}
+
+// AccessTokenKey is the key which signs access tokens.
+type AccessTokenKey struct {
+	ID         string
+	PrivateKey ed25519.PrivateKey
+}
+
+type accessTokenService struct {
+	// keys[0] signs new tokens, and the others are kept only to verify tokens signed before rotation.
+	keys []AccessTokenKey
+	now  func() time.Time
+}
+
+// NewAccessTokenService returns AccessTokenService.
+// The first key signs new tokens, and all keys verify tokens.
+func NewAccessTokenService(keys []AccessTokenKey) (AccessTokenService, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("at least one access token key is required")
+	}
+
+	ids := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		if k.ID == "" || ids[k.ID] || len(k.PrivateKey) != ed25519.PrivateKeySize {
+			return nil, errors.Errorf("access token key %q should have unique id and Ed25519 private key", k.ID)
+		}
+		ids[k.ID] = true
+	}
+
+	return &accessTokenService{
+		keys: keys,
+		now:  time.Now,
+	}, nil
+}
+
+// accessTokenHeader is the header of JWT.
+type accessTokenHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	Type      string `json:"typ"`
+}
+
+// accessTokenPayload is the payload of JWT.
+type accessTokenPayload struct {
+	Subject   string `json:"sub"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// Sign signs the claims by the current key and returns the token.
+func (s *accessTokenService) Sign(claims *model.AccessTokenClaims) (string, error) {
+	key := s.keys[0]
+
+	header, err := json.Marshal(&accessTokenHeader{
+		Algorithm: accessTokenAlgorithm,
+		KeyID:     key.ID,
+		Type:      "JWT",
+	})
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	payload, err := json.Marshal(&accessTokenPayload{
+		Subject:   strconv.FormatUint(uint64(claims.UserID), 10),
+		IssuedAt:  claims.IssuedAt.Unix(),
+		ExpiresAt: claims.ExpiresAt.Unix(),
+	})
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	signature := ed25519.Sign(key.PrivateKey, []byte(signingInput))
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// Verify verifies the signature and the expiry of the token and returns its claims.
+// This returns AuthenticationErr if the token is invalid.
+func (s *accessTokenService) Verify(token string) (*model.AccessTokenClaims, error) {
+	claims, err := s.verify(token)
+	if err != nil {
+		return nil, errors.WithStack(&model.AuthenticationErr{BaseErr: err})
+	}
+	return claims, nil
+}
+
+func (s *accessTokenService) verify(token string) (*model.AccessTokenClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("access token should have 3 parts")
+	}
+
+	header := &accessTokenHeader{}
+	if err := decodeSegment(parts[0], header); err != nil {
+		return nil, errors.Wrap(err, "failed to decode header of access token")
+	}
+	// alg is checked so that a token can not choose weaker algorithm.
+	if header.Algorithm != accessTokenAlgorithm {
+		return nil, errors.Errorf("algorithm of access token should be %s, but %s", accessTokenAlgorithm, header.Algorithm)
+	}
+
+	key, ok := s.publicKey(header.KeyID)
+	if !ok {
+		return nil, errors.Errorf("key %q of access token is unknown", header.KeyID)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode signature of access token")
+	}
+	if !ed25519.Verify(key, []byte(parts[0]+"."+parts[1]), signature) {
+		return nil, errors.New("signature of access token is invalid")
+	}
+
+	payload := &accessTokenPayload{}
+	if err := decodeSegment(parts[1], payload); err != nil {
+		return nil, errors.Wrap(err, "failed to decode payload of access token")
+	}
+
+	userID, err := strconv.ParseUint(payload.Subject, 10, 32)
+	if err != nil {
+		return nil, errors.Wrap(err, "subject of access token should be user id")
+	}
+
+	claims := &model.AccessTokenClaims{
+		UserID:    uint32(userID),
+		IssuedAt:  time.Unix(payload.IssuedAt, 0),
+		ExpiresAt: time.Unix(payload.ExpiresAt, 0),
+	}
+	if !s.now().Before(claims.ExpiresAt) {
+		return nil, errors.New("access token is expired")
+	}
+
+	return claims, nil
+}
+
+// PublicKeys returns public keys of all keys including rotated ones.
+func (s *accessTokenService) PublicKeys() []*model.PublicKey {
+	keys := make([]*model.PublicKey, 0, len(s.keys))
+	for _, k := range s.keys {
+		keys = append(keys, &model.PublicKey{
+			ID:  k.ID,
+			Key: []byte(k.PrivateKey.Public().(ed25519.PublicKey)),
+		})
+	}
+	return keys
+}
+
+// publicKey returns the public key specified by id.
+func (s *accessTokenService) publicKey(id string) (ed25519.PublicKey, bool) {
+	for _, k := range s.keys {
+		if k.ID == id {
+			return k.PrivateKey.Public().(ed25519.PublicKey), true
+		}
+	}
+	return nil, false
+}
+
+// decodeSegment decodes a segment of JWT to v.
+// Unknown fields are rejected so that a token which has unexpected meaning is not accepted.
+func decodeSegment(segment string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.DisallowUnknownFields()
+	return errors.WithStack(d.Decode(v))
+}
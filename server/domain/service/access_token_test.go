@@ -0,0 +1,156 @@
+package service
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ed25519"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/testutil"
+)
+
+func newAccessTokenKeyForTest(t *testing.T, id string) AccessTokenKey {
+	t.Helper()
+
+	_, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return AccessTokenKey{ID: id, PrivateKey: key}
+}
+
+func Test_accessTokenService_Verify(t *testing.T) {
+	testutil.SetFakeTime(time.Now())
+	defer testutil.ResetFakeTime()
+
+	oldKey := newAccessTokenKeyForTest(t, "old")
+	newKey := newAccessTokenKeyForTest(t, "new")
+
+	claims := &model.AccessTokenClaims{
+		UserID:    model.UserValidIDForTest,
+		IssuedAt:  testutil.TimeNow().Truncate(time.Second),
+		ExpiresAt: testutil.TimeNow().Truncate(time.Second).Add(time.Minute),
+	}
+
+	tests := []struct {
+		name       string
+		signKeys   []AccessTokenKey
+		verifyKeys []AccessTokenKey
+		claims     *model.AccessTokenClaims
+		tamper     func(token string) string
+		wantErr    bool
+	}{
+		{
+			name:       "When the token is signed by the current key, returns claims",
+			signKeys:   []AccessTokenKey{newKey},
+			verifyKeys: []AccessTokenKey{newKey, oldKey},
+			claims:     claims,
+		},
+		{
+			name:       "When the token is signed by the rotated key, returns claims",
+			signKeys:   []AccessTokenKey{oldKey},
+			verifyKeys: []AccessTokenKey{newKey, oldKey},
+			claims:     claims,
+		},
+		{
+			name:       "When the key of the token has been removed, returns AuthenticationErr",
+			signKeys:   []AccessTokenKey{oldKey},
+			verifyKeys: []AccessTokenKey{newKey},
+			claims:     claims,
+			wantErr:    true,
+		},
+		{
+			name:       "When the token is signed by another key which has the same id, returns AuthenticationErr",
+			signKeys:   []AccessTokenKey{{ID: newKey.ID, PrivateKey: oldKey.PrivateKey}},
+			verifyKeys: []AccessTokenKey{newKey},
+			claims:     claims,
+			wantErr:    true,
+		},
+		{
+			name:       "When the token is expired, returns AuthenticationErr",
+			signKeys:   []AccessTokenKey{newKey},
+			verifyKeys: []AccessTokenKey{newKey},
+			claims: &model.AccessTokenClaims{
+				UserID:    model.UserValidIDForTest,
+				IssuedAt:  claims.IssuedAt.Add(-time.Hour),
+				ExpiresAt: testutil.TimeNow(),
+			},
+			wantErr: true,
+		},
+		{
+			name:       "When the payload is tampered, returns AuthenticationErr",
+			signKeys:   []AccessTokenKey{newKey},
+			verifyKeys: []AccessTokenKey{newKey},
+			claims:     claims,
+			tamper: func(token string) string {
+				parts := strings.Split(token, ".")
+				parts[1] = base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"2","iat":0,"exp":9999999999}`))
+				return strings.Join(parts, ".")
+			},
+			wantErr: true,
+		},
+		{
+			name:       "When the algorithm is none, returns AuthenticationErr",
+			signKeys:   []AccessTokenKey{newKey},
+			verifyKeys: []AccessTokenKey{newKey},
+			claims:     claims,
+			tamper: func(token string) string {
+				parts := strings.Split(token, ".")
+				parts[0] = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","kid":"new","typ":"JWT"}`))
+				parts[2] = ""
+				return strings.Join(parts, ".")
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signer, err := NewAccessTokenService(tt.signKeys)
+			if err != nil {
+				t.Fatal(err)
+			}
+			verifier, err := NewAccessTokenService(tt.verifyKeys)
+			if err != nil {
+				t.Fatal(err)
+			}
+			verifier.(*accessTokenService).now = testutil.TimeNow
+
+			token, err := signer.Sign(tt.claims)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.tamper != nil {
+				token = tt.tamper(token)
+			}
+
+			got, err := verifier.Verify(token)
+			if tt.wantErr {
+				if _, ok := errors.Cause(err).(*model.AuthenticationErr); !ok {
+					t.Errorf("accessTokenService.Verify() error = %v, want AuthenticationErr", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("accessTokenService.Verify() error = %v", err)
+			}
+			if *got != *tt.claims {
+				t.Errorf("accessTokenService.Verify() = %v, want %v", got, tt.claims)
+			}
+		})
+	}
+}
+
+func TestNewAccessTokenService(t *testing.T) {
+	key := newAccessTokenKeyForTest(t, "key")
+
+	if _, err := NewAccessTokenService(nil); err == nil {
+		t.Error("NewAccessTokenService() with no key should return error")
+	}
+	if _, err := NewAccessTokenService([]AccessTokenKey{key, key}); err == nil {
+		t.Error("NewAccessTokenService() with duplicated key id should return error")
+	}
+}
@@ -0,0 +1,21 @@
+package service
+
+import (
+	"context"
+	"io"
+)
+
+// BlobStore is interface of storing contents of files by key.
+// Keys are paths separated by slashes, which are generated by the server and never by clients.
+type BlobStore interface {
+	// Put stores the content read from r until EOF as key, and returns its size.
+	// The existing blob of key is overwritten.
+	Put(ctx context.Context, key string, r io.Reader) (int64, error)
+	// Open returns the content of key from offset, and NoSuchDataError if the blob does not exist.
+	Open(ctx context.Context, key string, offset int64) (io.ReadCloser, error)
+	Exists(ctx context.Context, key string) (bool, error)
+	// Rename moves the blob from the key to another key, which is overwritten if it exists.
+	Rename(ctx context.Context, from, to string) error
+	// Delete does nothing if the blob does not exist.
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,12 @@
+package service
+
+import (
+	"context"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+)
+
+// Mailer is interface of sending mail to users.
+type Mailer interface {
+	Send(ctx context.Context, mail *model.Mail) error
+}
@@ -36,31 +36,32 @@ func (m *MockSessionService) EXPECT() *MockSessionServiceMockRecorder {
 }
 
 // NewSession mocks base method
-func (m *MockSessionService) NewSession(userID uint32) *model.Session {
+func (m *MockSessionService) NewSession(userID uint32, client *model.Client) *model.Session {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "NewSession", userID)
+	ret := m.ctrl.Call(m, "NewSession", userID, client)
 	ret0, _ := ret[0].(*model.Session)
 	return ret0
 }
 
 // NewSession indicates an expected call of NewSession
-func (mr *MockSessionServiceMockRecorder) NewSession(userID interface{}) *gomock.Call {
+func (mr *MockSessionServiceMockRecorder) NewSession(userID, client interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewSession", reflect.TypeOf((*MockSessionService)(nil).NewSession), userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewSession", reflect.TypeOf((*MockSessionService)(nil).NewSession), userID, client)
 }
 
-// SessionID mocks base method
-func (m *MockSessionService) SessionID() string {
+// SessionToken mocks base method
+func (m *MockSessionService) SessionToken() (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SessionID")
+	ret := m.ctrl.Call(m, "SessionToken")
 	ret0, _ := ret[0].(string)
-	return ret0
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// SessionID indicates an expected call of SessionID
-func (mr *MockSessionServiceMockRecorder) SessionID() *gomock.Call {
+// SessionToken indicates an expected call of SessionToken
+func (mr *MockSessionServiceMockRecorder) SessionToken() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SessionID", reflect.TypeOf((*MockSessionService)(nil).SessionID))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SessionToken", reflect.TypeOf((*MockSessionService)(nil).SessionToken))
 }
 
 // IsAlreadyExistID mocks base method
@@ -0,0 +1,20 @@
+package service
+
+import (
+	"context"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+)
+
+// OIDCProvider is interface of OpenID Connect provider.
+// This is implemented in infra because it talks to the provider over HTTP.
+type OIDCProvider interface {
+	// Issuer returns the issuer which identifies the provider.
+	Issuer() string
+	// AuthCodeURL returns the URL of the provider to which the browser is redirected to log in.
+	AuthCodeURL(state, nonce, codeChallenge string) string
+	// Exchange exchanges the authorization code for the ID token and returns its verified claims.
+	// This returns AuthenticationErr if the code or the ID token is invalid.
+	// The nonce is not checked here, and the caller must compare it with the one of the login.
+	Exchange(ctx context.Context, code, codeVerifier string) (*model.OIDCClaims, error)
+}
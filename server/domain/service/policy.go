@@ -0,0 +1,154 @@
+package service
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+)
+
+// PolicyService is interface of domain service which decides what users are allowed.
+// Application services ask this before operations on what others own,
+// and return ForbiddenError if it is not allowed.
+type PolicyService interface {
+	GetRoles(userID uint32) (model.Roles, error)
+	CanDeleteComment(user *model.User, comment *model.Comment) (bool, error)
+	CanEditComment(user *model.User, comment *model.Comment) (bool, error)
+	CanReadDeletedComments(userID, threadID uint32) (bool, error)
+	CanManageModerators(user *model.User, threadID uint32) (bool, error)
+	CanDismissModerator(user *model.User, thread *model.Thread, moderatorID uint32) (bool, error)
+	CanManageRoles(user *model.User) (bool, error)
+	CanReadThread(userID uint32, thread *model.Thread) (bool, error)
+	CanInviteToThread(user *model.User, threadID uint32) (bool, error)
+}
+
+type policyService struct {
+	m                         repository.DBManager
+	roleRepository            repository.RoleRepository
+	threadModeratorRepository repository.ThreadModeratorRepository
+	threadMemberRepository    repository.ThreadMemberRepository
+}
+
+// NewPolicyService returns PolicyService.
+func NewPolicyService(m repository.DBManager, rRepo repository.RoleRepository, tmRepo repository.ThreadModeratorRepository, mRepo repository.ThreadMemberRepository) PolicyService {
+	return &policyService{
+		m:                         m,
+		roleRepository:            rRepo,
+		threadModeratorRepository: tmRepo,
+		threadMemberRepository:    mRepo,
+	}
+}
+
+// GetRoles returns roles of the user.
+func (s *policyService) GetRoles(userID uint32) (model.Roles, error) {
+	roles, err := s.roleRepository.GetRolesByUserID(s.m, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get roles by user id")
+	}
+	return roles, nil
+}
+
+// CanDeleteComment returns whether the user can delete the comment.
+// The author, moderators of the thread and users whose role allows it can delete.
+func (s *policyService) CanDeleteComment(user *model.User, comment *model.Comment) (bool, error) {
+	if comment.UserID == user.ID {
+		return true, nil
+	}
+	return s.can(user.ID, model.PermissionDeleteAnyComment, comment.ThreadID)
+}
+
+// CanEditComment returns whether the user can edit the comment.
+// Only the author can edit, so that nobody can put words in the mouth of others.
+func (s *policyService) CanEditComment(user *model.User, comment *model.Comment) (bool, error) {
+	return comment.UserID == user.ID, nil
+}
+
+// CanReadDeletedComments returns whether the user can read the content and history of deleted comments in the thread.
+// Those who can delete comments of others can read them, so that they can review what was deleted.
+func (s *policyService) CanReadDeletedComments(userID, threadID uint32) (bool, error) {
+	return s.can(userID, model.PermissionDeleteAnyComment, threadID)
+}
+
+// CanManageModerators returns whether the user can appoint and dismiss moderators of the thread.
+// Moderators of the thread can appoint others, so that the creator of the thread can share moderation.
+func (s *policyService) CanManageModerators(user *model.User, threadID uint32) (bool, error) {
+	return s.can(user.ID, model.PermissionManageModerators, threadID)
+}
+
+// CanDismissModerator returns whether the user can dismiss the moderator from the thread.
+// Only the owner of the thread and users whose role allows it can dismiss others,
+// so that moderators appointed by the owner can not dismiss the owner nor each other.
+// Moderators can dismiss themselves.
+func (s *policyService) CanDismissModerator(user *model.User, thread *model.Thread, moderatorID uint32) (bool, error) {
+	if moderatorID == user.ID || thread.UserID == user.ID {
+		return true, nil
+	}
+
+	roles, err := s.GetRoles(user.ID)
+	if err != nil {
+		return false, err
+	}
+	return roles.Can(model.PermissionManageModerators), nil
+}
+
+// CanManageRoles returns whether the user can grant and revoke roles.
+func (s *policyService) CanManageRoles(user *model.User) (bool, error) {
+	roles, err := s.GetRoles(user.ID)
+	if err != nil {
+		return false, err
+	}
+	return roles.Can(model.PermissionManageRoles), nil
+}
+
+// CanReadThread returns whether the user can read the thread and its comments.
+// Everyone can read public threads, and only members can read private threads whatever roles they have.
+func (s *policyService) CanReadThread(userID uint32, thread *model.Thread) (bool, error) {
+	if !thread.IsPrivate() {
+		return true, nil
+	}
+
+	if _, err := s.threadMemberRepository.GetThreadMember(s.m, thread.ID, userID); err != nil {
+		if _, ok := errors.Cause(err).(*model.NoSuchDataError); ok {
+			return false, nil
+		}
+		return false, errors.Wrap(err, "failed to get member of thread")
+	}
+	return true, nil
+}
+
+// CanInviteToThread returns whether the user can create invitations to the thread.
+// The owner and moderators of the thread can invite.
+func (s *policyService) CanInviteToThread(user *model.User, threadID uint32) (bool, error) {
+	member, err := s.threadMemberRepository.GetThreadMember(s.m, threadID, user.ID)
+	if err == nil && member.Role == model.ThreadMemberRoleOwner {
+		return true, nil
+	}
+	if err != nil {
+		if _, ok := errors.Cause(err).(*model.NoSuchDataError); !ok {
+			return false, errors.Wrap(err, "failed to get member of thread")
+		}
+	}
+
+	ok, err := s.threadModeratorRepository.IsThreadModerator(s.m, threadID, user.ID)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to check moderator of thread")
+	}
+	return ok, nil
+}
+
+// can returns whether roles of the user allow the permission or the user moderates the thread.
+func (s *policyService) can(userID uint32, permission model.Permission, threadID uint32) (bool, error) {
+	roles, err := s.GetRoles(userID)
+	if err != nil {
+		return false, err
+	}
+	if roles.Can(permission) {
+		return true, nil
+	}
+
+	ok, err := s.threadModeratorRepository.IsThreadModerator(s.m, threadID, userID)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to check moderator of thread")
+	}
+	return ok, nil
+}
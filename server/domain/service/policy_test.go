@@ -0,0 +1,242 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	mock_repository "github.com/hideUW/nuxt-go-chat-app/server/domain/repository/mock"
+)
+
+func Test_policyService_CanDeleteComment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := &model.User{ID: model.UserValidIDForTest}
+	comment := &model.Comment{ID: model.CommentValidIDForTest, ThreadID: model.ThreadValidIDForTest, UserID: model.UserInValidIDForTest}
+
+	tests := []struct {
+		name        string
+		comment     *model.Comment
+		roles       model.Roles
+		isModerator bool
+		want        bool
+	}{
+		{
+			name:    "When the user is the author, returns true without looking up roles",
+			comment: &model.Comment{ID: model.CommentValidIDForTest, ThreadID: model.ThreadValidIDForTest, UserID: model.UserValidIDForTest},
+			want:    true,
+		},
+		{
+			name:    "When the user is an admin, returns true",
+			comment: comment,
+			roles:   model.Roles{model.RoleAdmin},
+			want:    true,
+		},
+		{
+			name:    "When the user is a moderator of every thread, returns true",
+			comment: comment,
+			roles:   model.Roles{model.RoleModerator},
+			want:    true,
+		},
+		{
+			name:        "When the user moderates the thread, returns true",
+			comment:     comment,
+			roles:       model.Roles{},
+			isModerator: true,
+			want:        true,
+		},
+		{
+			name:    "When the user has no role and does not moderate the thread, returns false",
+			comment: comment,
+			roles:   model.Roles{},
+			want:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock_repository.NewMockDBManager(ctrl)
+			rr := mock_repository.NewMockRoleRepository(ctrl)
+			tmr := mock_repository.NewMockThreadModeratorRepository(ctrl)
+
+			if tt.roles != nil {
+				rr.EXPECT().GetRolesByUserID(m, model.UserValidIDForTest).Return(tt.roles, nil)
+				if !tt.roles.Can(model.PermissionDeleteAnyComment) {
+					tmr.EXPECT().IsThreadModerator(m, model.ThreadValidIDForTest, model.UserValidIDForTest).Return(tt.isModerator, nil)
+				}
+			}
+
+			s := NewPolicyService(m, rr, tmr, nil)
+			got, err := s.CanDeleteComment(user, tt.comment)
+			if err != nil {
+				t.Fatalf("policyService.CanDeleteComment() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("policyService.CanDeleteComment() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_policyService_CanManageRoles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		name  string
+		roles model.Roles
+		want  bool
+	}{
+		{
+			name:  "When the user is an admin, returns true",
+			roles: model.Roles{model.RoleAdmin},
+			want:  true,
+		},
+		{
+			name:  "When the user is a moderator, returns false",
+			roles: model.Roles{model.RoleModerator},
+			want:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock_repository.NewMockDBManager(ctrl)
+			rr := mock_repository.NewMockRoleRepository(ctrl)
+			tmr := mock_repository.NewMockThreadModeratorRepository(ctrl)
+
+			rr.EXPECT().GetRolesByUserID(m, model.UserValidIDForTest).Return(tt.roles, nil)
+
+			s := NewPolicyService(m, rr, tmr, nil)
+			got, err := s.CanManageRoles(&model.User{ID: model.UserValidIDForTest})
+			if err != nil {
+				t.Fatalf("policyService.CanManageRoles() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("policyService.CanManageRoles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_policyService_CanReadThread(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		name       string
+		visibility model.ThreadVisibility
+		isMember   bool
+		want       bool
+	}{
+		{
+			name:       "When the thread is public, returns true without looking up members",
+			visibility: model.ThreadVisibilityPublic,
+			want:       true,
+		},
+		{
+			name:       "When the user is a member of the private thread, returns true",
+			visibility: model.ThreadVisibilityPrivate,
+			isMember:   true,
+			want:       true,
+		},
+		{
+			name:       "When the user is not a member of the private thread, returns false",
+			visibility: model.ThreadVisibilityPrivate,
+			want:       false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock_repository.NewMockDBManager(ctrl)
+			mr := mock_repository.NewMockThreadMemberRepository(ctrl)
+
+			if tt.visibility == model.ThreadVisibilityPrivate {
+				if tt.isMember {
+					mr.EXPECT().GetThreadMember(m, model.ThreadValidIDForTest, model.UserValidIDForTest).Return(&model.ThreadMember{}, nil)
+				} else {
+					mr.EXPECT().GetThreadMember(m, model.ThreadValidIDForTest, model.UserValidIDForTest).Return(nil, &model.NoSuchDataError{})
+				}
+			}
+
+			s := NewPolicyService(m, nil, nil, mr)
+			got, err := s.CanReadThread(model.UserValidIDForTest, &model.Thread{ID: model.ThreadValidIDForTest, Visibility: tt.visibility})
+			if err != nil {
+				t.Fatalf("policyService.CanReadThread() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("policyService.CanReadThread() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_policyService_CanDismissModerator(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	const ownerID = model.UserValidIDForTest + 10
+	thread := &model.Thread{ID: model.ThreadValidIDForTest, UserID: ownerID}
+
+	tests := []struct {
+		name        string
+		userID      uint32
+		moderatorID uint32
+		roles       model.Roles
+		want        bool
+	}{
+		{
+			name:        "When the user is the owner, returns true without looking up roles",
+			userID:      ownerID,
+			moderatorID: model.UserInValidIDForTest,
+			want:        true,
+		},
+		{
+			name:        "When the moderator dismisses themselves, returns true without looking up roles",
+			userID:      model.UserValidIDForTest,
+			moderatorID: model.UserValidIDForTest,
+			want:        true,
+		},
+		{
+			name:        "When the user is an admin, returns true",
+			userID:      model.UserValidIDForTest,
+			moderatorID: ownerID,
+			roles:       model.Roles{model.RoleAdmin},
+			want:        true,
+		},
+		{
+			name:        "When a moderator dismisses the owner, returns false",
+			userID:      model.UserValidIDForTest,
+			moderatorID: ownerID,
+			roles:       model.Roles{},
+			want:        false,
+		},
+		{
+			name:        "When a moderator dismisses another moderator, returns false",
+			userID:      model.UserValidIDForTest,
+			moderatorID: model.UserInValidIDForTest,
+			roles:       model.Roles{model.RoleModerator},
+			want:        false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mock_repository.NewMockDBManager(ctrl)
+			rr := mock_repository.NewMockRoleRepository(ctrl)
+			tmr := mock_repository.NewMockThreadModeratorRepository(ctrl)
+
+			if tt.roles != nil {
+				rr.EXPECT().GetRolesByUserID(m, tt.userID).Return(tt.roles, nil)
+			}
+
+			s := NewPolicyService(m, rr, tmr, nil)
+			got, err := s.CanDismissModerator(&model.User{ID: tt.userID}, thread, tt.moderatorID)
+			if err != nil {
+				t.Fatalf("policyService.CanDismissModerator() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("policyService.CanDismissModerator() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
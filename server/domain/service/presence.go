@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+	"github.com/pkg/errors"
+)
+
+// PresenceService is interface of domain service of presence.
+// This tracks heartbeats of users and typing in threads, and decides the status of each user.
+type PresenceService interface {
+	Heartbeat(ctx context.Context, userID uint32, active, away bool) (*model.Presence, error)
+	Offline(ctx context.Context, userID uint32) error
+	GetPresences(ctx context.Context, userIDs []uint32) ([]*model.Presence, error)
+	// StartTyping returns false if the event is throttled because the user has just started typing in the thread.
+	StartTyping(ctx context.Context, threadID, userID uint32) (bool, error)
+	StopTyping(ctx context.Context, threadID, userID uint32) error
+	GetTypingUserIDs(ctx context.Context, threadID uint32) ([]uint32, error)
+}
+
+// PresencePolicy is the policy of presence and typing.
+type PresencePolicy struct {
+	// HeartbeatTTL is how long the user is regarded as connected after a heartbeat.
+	// Clients should send heartbeats more often than this.
+	HeartbeatTTL time.Duration
+	// IdleAfter and AwayAfter are how long after the last activity the user becomes idle and away.
+	IdleAfter time.Duration
+	AwayAfter time.Duration
+	// TypingTTL is how long the typing lasts after it is started.
+	TypingTTL time.Duration
+	// TypingThrottle is the interval in which repeated typing events of the user in the thread are ignored.
+	TypingThrottle time.Duration
+}
+
+// DefaultPresencePolicy is the default policy of presence.
+var DefaultPresencePolicy = PresencePolicy{
+	HeartbeatTTL:   90 * time.Second,
+	IdleAfter:      5 * time.Minute,
+	AwayAfter:      30 * time.Minute,
+	TypingTTL:      6 * time.Second,
+	TypingThrottle: 3 * time.Second,
+}
+
+type presenceService struct {
+	repo   repository.PresenceRepository
+	policy PresencePolicy
+	now    func() time.Time
+}
+
+// NewPresenceService returns PresenceService which reads the current time from now.
+// time.Now is used if now is nil.
+func NewPresenceService(repo repository.PresenceRepository, policy PresencePolicy, now func() time.Time) PresenceService {
+	if now == nil {
+		now = time.Now
+	}
+	return &presenceService{
+		repo:   repo,
+		policy: policy,
+		now:    now,
+	}
+}
+
+// Heartbeat records that the client of the user is connected, and returns the status of the user.
+// active is whether the user has operated the client since the last heartbeat, and away is set by the user.
+func (s *presenceService) Heartbeat(ctx context.Context, userID uint32, active, away bool) (*model.Presence, error) {
+	now := s.now()
+
+	record := &model.PresenceRecord{
+		UserID:          userID,
+		LastHeartbeatAt: now,
+		LastActiveAt:    now,
+		Away:            away,
+		ExpiresAt:       now.Add(s.policy.HeartbeatTTL),
+	}
+
+	if !active {
+		stored, err := s.repo.GetPresenceRecord(userID)
+		if err == nil && !stored.IsExpired(now) {
+			record.LastActiveAt = stored.LastActiveAt
+		} else if err != nil {
+			if _, ok := errors.Cause(err).(*model.NoSuchDataError); !ok {
+				return nil, errors.Wrap(err, "failed to get presence record")
+			}
+		}
+	}
+
+	if err := s.repo.PutPresenceRecord(record); err != nil {
+		return nil, errors.Wrap(err, "failed to put presence record")
+	}
+
+	return s.presence(record, now), nil
+}
+
+// Offline forgets the heartbeat of the user, e.g. on logout, so that the user is offline at once.
+func (s *presenceService) Offline(ctx context.Context, userID uint32) error {
+	if err := s.repo.DeletePresenceRecord(userID); err != nil {
+		return errors.Wrap(err, "failed to delete presence record")
+	}
+	return nil
+}
+
+// GetPresences returns presences of the users in the given order.
+// Users who have not sent heartbeats within HeartbeatTTL are offline.
+func (s *presenceService) GetPresences(ctx context.Context, userIDs []uint32) ([]*model.Presence, error) {
+	now := s.now()
+
+	records, err := s.repo.GetPresenceRecordsByUserIDs(userIDs, now)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get presence records")
+	}
+
+	recordByUserID := make(map[uint32]*model.PresenceRecord, len(records))
+	for _, r := range records {
+		recordByUserID[r.UserID] = r
+	}
+
+	presences := make([]*model.Presence, 0, len(userIDs))
+	for _, id := range userIDs {
+		r, ok := recordByUserID[id]
+		if !ok {
+			presences = append(presences, &model.Presence{UserID: id, Status: model.PresenceStatusOffline})
+			continue
+		}
+		presences = append(presences, s.presence(r, now))
+	}
+
+	return presences, nil
+}
+
+// StartTyping records that the user is typing in the thread until TypingTTL passes.
+// Events within TypingThrottle after the last recorded one are ignored, so that clients can send one per keystroke.
+func (s *presenceService) StartTyping(ctx context.Context, threadID, userID uint32) (bool, error) {
+	now := s.now()
+
+	stored, err := s.repo.GetTyping(threadID, userID)
+	if err == nil && now.Before(stored.StartedAt.Add(s.policy.TypingThrottle)) {
+		return false, nil
+	}
+	if err != nil {
+		if _, ok := errors.Cause(err).(*model.NoSuchDataError); !ok {
+			return false, errors.Wrap(err, "failed to get typing")
+		}
+	}
+
+	typing := &model.Typing{
+		ThreadID:  threadID,
+		UserID:    userID,
+		StartedAt: now,
+		ExpiresAt: now.Add(s.policy.TypingTTL),
+	}
+	if err := s.repo.PutTyping(typing); err != nil {
+		return false, errors.Wrap(err, "failed to put typing")
+	}
+
+	return true, nil
+}
+
+// StopTyping ends typing of the user in the thread, e.g. when the user posts the comment.
+func (s *presenceService) StopTyping(ctx context.Context, threadID, userID uint32) error {
+	if err := s.repo.DeleteTyping(threadID, userID); err != nil {
+		return errors.Wrap(err, "failed to delete typing")
+	}
+	return nil
+}
+
+// GetTypingUserIDs returns ids of users typing in the thread in order of starting.
+func (s *presenceService) GetTypingUserIDs(ctx context.Context, threadID uint32) ([]uint32, error) {
+	typings, err := s.repo.GetTypingsByThreadID(threadID, s.now())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get typings by thread id")
+	}
+
+	ids := make([]uint32, 0, len(typings))
+	for _, t := range typings {
+		ids = append(ids, t.UserID)
+	}
+	return ids, nil
+}
+
+// presence decides the status of the user from the record which is not expired.
+func (s *presenceService) presence(record *model.PresenceRecord, now time.Time) *model.Presence {
+	inactive := now.Sub(record.LastActiveAt)
+
+	status := model.PresenceStatusOnline
+	switch {
+	case record.Away || inactive >= s.policy.AwayAfter:
+		status = model.PresenceStatusAway
+	case inactive >= s.policy.IdleAfter:
+		status = model.PresenceStatusIdle
+	}
+
+	return &model.Presence{
+		UserID:       record.UserID,
+		Status:       status,
+		LastActiveAt: record.LastActiveAt,
+	}
+}
@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/testutil"
+	"github.com/pkg/errors"
+)
+
+// fakePresenceRepository is PresenceRepository in memory for tests.
+type fakePresenceRepository struct {
+	records map[uint32]*model.PresenceRecord
+	typings map[[2]uint32]*model.Typing
+}
+
+func newFakePresenceRepository() *fakePresenceRepository {
+	return &fakePresenceRepository{
+		records: make(map[uint32]*model.PresenceRecord),
+		typings: make(map[[2]uint32]*model.Typing),
+	}
+}
+
+func (repo *fakePresenceRepository) GetPresenceRecord(userID uint32) (*model.PresenceRecord, error) {
+	r, ok := repo.records[userID]
+	if !ok {
+		return nil, errors.WithStack(&model.NoSuchDataError{})
+	}
+	copied := *r
+	return &copied, nil
+}
+
+func (repo *fakePresenceRepository) GetPresenceRecordsByUserIDs(userIDs []uint32, now time.Time) ([]*model.PresenceRecord, error) {
+	list := make([]*model.PresenceRecord, 0, len(userIDs))
+	for _, id := range userIDs {
+		if r, ok := repo.records[id]; ok && !r.IsExpired(now) {
+			copied := *r
+			list = append(list, &copied)
+		}
+	}
+	return list, nil
+}
+
+func (repo *fakePresenceRepository) PutPresenceRecord(record *model.PresenceRecord) error {
+	copied := *record
+	repo.records[record.UserID] = &copied
+	return nil
+}
+
+func (repo *fakePresenceRepository) DeletePresenceRecord(userID uint32) error {
+	delete(repo.records, userID)
+	return nil
+}
+
+func (repo *fakePresenceRepository) GetTyping(threadID, userID uint32) (*model.Typing, error) {
+	t, ok := repo.typings[[2]uint32{threadID, userID}]
+	if !ok {
+		return nil, errors.WithStack(&model.NoSuchDataError{})
+	}
+	copied := *t
+	return &copied, nil
+}
+
+func (repo *fakePresenceRepository) GetTypingsByThreadID(threadID uint32, now time.Time) ([]*model.Typing, error) {
+	list := make([]*model.Typing, 0)
+	for k, t := range repo.typings {
+		if k[0] == threadID && !t.IsExpired(now) {
+			copied := *t
+			list = append(list, &copied)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].StartedAt.Equal(list[j].StartedAt) {
+			return list[i].StartedAt.Before(list[j].StartedAt)
+		}
+		return list[i].UserID < list[j].UserID
+	})
+	return list, nil
+}
+
+func (repo *fakePresenceRepository) PutTyping(typing *model.Typing) error {
+	copied := *typing
+	repo.typings[[2]uint32{typing.ThreadID, typing.UserID}] = &copied
+	return nil
+}
+
+func (repo *fakePresenceRepository) DeleteTyping(threadID, userID uint32) error {
+	delete(repo.typings, [2]uint32{threadID, userID})
+	return nil
+}
+
+var presencePolicyForTest = PresencePolicy{
+	HeartbeatTTL:   time.Minute,
+	IdleAfter:      5 * time.Minute,
+	AwayAfter:      30 * time.Minute,
+	TypingTTL:      6 * time.Second,
+	TypingThrottle: 3 * time.Second,
+}
+
+func Test_presenceService_GetPresences(t *testing.T) {
+	type heartbeat struct {
+		// ago is the duration from the time of getting presences.
+		ago    time.Duration
+		active bool
+		away   bool
+	}
+
+	tests := []struct {
+		name       string
+		heartbeats []heartbeat
+		want       model.PresenceStatus
+	}{
+		{
+			name:       "When the user has not sent heartbeats, returns offline",
+			heartbeats: nil,
+			want:       model.PresenceStatusOffline,
+		},
+		{
+			name:       "When the user is active, returns online",
+			heartbeats: []heartbeat{{ago: 10 * time.Second, active: true}},
+			want:       model.PresenceStatusOnline,
+		},
+		{
+			name:       "When the heartbeat is older than TTL, returns offline",
+			heartbeats: []heartbeat{{ago: time.Minute, active: true}},
+			want:       model.PresenceStatusOffline,
+		},
+		{
+			name: "When the user has been inactive longer than IdleAfter, returns idle",
+			heartbeats: []heartbeat{
+				{ago: 6 * time.Minute, active: true},
+				{ago: 5*time.Minute + 10*time.Second},
+				{ago: 4*time.Minute + 20*time.Second},
+				{ago: 3*time.Minute + 30*time.Second},
+				{ago: 2*time.Minute + 40*time.Second},
+				{ago: time.Minute + 50*time.Second},
+				{ago: time.Minute},
+				{ago: 10 * time.Second},
+			},
+			want: model.PresenceStatusIdle,
+		},
+		{
+			name:       "When the user sets away, returns away even if active",
+			heartbeats: []heartbeat{{ago: 10 * time.Second, active: true, away: true}},
+			want:       model.PresenceStatusAway,
+		},
+		{
+			name: "When the heartbeats stop once, forgets the last activity",
+			heartbeats: []heartbeat{
+				{ago: 10 * time.Minute, active: true},
+				{ago: 10 * time.Second},
+			},
+			want: model.PresenceStatusOnline,
+		},
+	}
+
+	now := time.Date(2019, 5, 1, 12, 0, 0, 0, time.UTC)
+	defer testutil.ResetFakeTime()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewPresenceService(newFakePresenceRepository(), presencePolicyForTest, testutil.TimeNow)
+
+			for _, h := range tt.heartbeats {
+				testutil.SetFakeTime(now.Add(-h.ago))
+				if _, err := s.Heartbeat(context.Background(), model.UserValidIDForTest, h.active, h.away); err != nil {
+					t.Fatalf("presenceService.Heartbeat() error = %v", err)
+				}
+			}
+
+			testutil.SetFakeTime(now)
+			got, err := s.GetPresences(context.Background(), []uint32{model.UserValidIDForTest})
+			if err != nil {
+				t.Fatalf("presenceService.GetPresences() error = %v", err)
+			}
+			if len(got) != 1 || got[0].Status != tt.want {
+				testutil.Errorf(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_presenceService_StartTyping(t *testing.T) {
+	now := time.Date(2019, 5, 1, 12, 0, 0, 0, time.UTC)
+	defer testutil.ResetFakeTime()
+
+	s := NewPresenceService(newFakePresenceRepository(), presencePolicyForTest, testutil.TimeNow)
+	ctx := context.Background()
+
+	steps := []struct {
+		name       string
+		at         time.Duration
+		userID     uint32
+		wantStored bool
+		wantTyping []uint32
+	}{
+		{
+			name:       "the first event is stored",
+			at:         0,
+			userID:     model.UserValidIDForTest,
+			wantStored: true,
+			wantTyping: []uint32{model.UserValidIDForTest},
+		},
+		{
+			name:       "the event within throttle is ignored",
+			at:         2 * time.Second,
+			userID:     model.UserValidIDForTest,
+			wantStored: false,
+			wantTyping: []uint32{model.UserValidIDForTest},
+		},
+		{
+			name:       "the event of another user is not throttled",
+			at:         2 * time.Second,
+			userID:     model.UserInValidIDForTest,
+			wantStored: true,
+			wantTyping: []uint32{model.UserValidIDForTest, model.UserInValidIDForTest},
+		},
+		{
+			name:       "the event after throttle extends typing",
+			at:         4 * time.Second,
+			userID:     model.UserValidIDForTest,
+			wantStored: true,
+			wantTyping: []uint32{model.UserInValidIDForTest, model.UserValidIDForTest},
+		},
+	}
+	for _, st := range steps {
+		testutil.SetFakeTime(now.Add(st.at))
+
+		stored, err := s.StartTyping(ctx, model.ThreadValidIDForTest, st.userID)
+		if err != nil {
+			t.Fatalf("%s: presenceService.StartTyping() error = %v", st.name, err)
+		}
+		if stored != st.wantStored {
+			t.Errorf("%s: presenceService.StartTyping() = %v, want %v", st.name, stored, st.wantStored)
+		}
+
+		got, err := s.GetTypingUserIDs(ctx, model.ThreadValidIDForTest)
+		if err != nil {
+			t.Fatalf("%s: presenceService.GetTypingUserIDs() error = %v", st.name, err)
+		}
+		if !reflect.DeepEqual(got, st.wantTyping) {
+			t.Errorf("%s: presenceService.GetTypingUserIDs() = %v, want %v", st.name, got, st.wantTyping)
+		}
+	}
+
+	// typing of the other user started at 2s expires after TypingTTL.
+	testutil.SetFakeTime(now.Add(8 * time.Second))
+	got, err := s.GetTypingUserIDs(ctx, model.ThreadValidIDForTest)
+	if err != nil {
+		t.Fatalf("presenceService.GetTypingUserIDs() error = %v", err)
+	}
+	if want := []uint32{model.UserValidIDForTest}; !reflect.DeepEqual(got, want) {
+		testutil.Errorf(t, want, got)
+	}
+}
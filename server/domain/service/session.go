@@ -12,8 +12,8 @@ import (
 
 // SessionService is inferface of domain service of session.
 type SessionService interface {
-	NewSession(userID uint32) *model.Session
-	SessionID() string
+	NewSession(userID uint32, client *model.Client) *model.Session
+	SessionToken() (string, error)
 	IsAlreadyExistID(ctx context.Context, id string) (bool, error)
 }
 
@@ -33,17 +33,23 @@ func NewSessionService(m repository.DBManager, repo repository.SessionRepository
 	}
 }
 
-// NewSession generates and returns Session.
-func (s *sessionService) NewSession(userID uint32) *model.Session {
+// NewSession generates and returns Session of the client.
+func (s *sessionService) NewSession(userID uint32, client *model.Client) *model.Session {
+	now := time.Now()
 	session := &model.Session{
-		UserID:    userID,
-		CreatedAt: time.Now(),
+		UserID:     userID,
+		UserAgent:  client.UserAgent,
+		IPAddress:  client.IPAddress,
+		CreatedAt:  now,
+		LastSeenAt: now,
 	}
 	return session
 }
 
-func (s *sessionService) SessionID() string {
-	return util.UUID()
+// SessionToken generates and returns random token of session.
+// This has enough entropy that collision is not checked.
+func (s *sessionService) SessionToken() (string, error) {
+	return util.RandomToken(model.SessionTokenSize)
 }
 
 func (s sessionService) IsAlreadyExistID(ctx context.Context, id string) (bool, error) {
@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+	"github.com/pkg/errors"
+)
+
+// ThrottleService is interface of domain service of throttle.
+// This counts attempts of credential checks and decides whether the attempt is allowed.
+type ThrottleService interface {
+	Reserve(ctx context.Context, keys ...model.ThrottleKey) (*model.ThrottleReservation, error)
+	Release(ctx context.Context, reservation *model.ThrottleReservation) error
+	Reset(ctx context.Context, keys ...model.ThrottleKey) error
+}
+
+// ThrottlePolicy is the policy of throttle applied to a kind of key.
+type ThrottlePolicy struct {
+	// Window is the sliding window which failures are counted in.
+	Window time.Duration
+	// FreeFailures is the number of failures allowed without delay.
+	FreeFailures int
+	// BaseDelay is the delay after the first failure over FreeFailures.
+	// This is doubled every failure and capped by MaxDelay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// LockoutFailures is the number of failures which lock the key out.
+	LockoutFailures int
+	LockoutDuration time.Duration
+}
+
+// DefaultThrottlePolicies is the default policies of throttle.
+// IP is looser than name because many users may share an IP.
+var DefaultThrottlePolicies = map[model.ThrottleKind]ThrottlePolicy{
+	model.ThrottleKindLoginName: {
+		Window:          15 * time.Minute,
+		FreeFailures:    3,
+		BaseDelay:       time.Second,
+		MaxDelay:        time.Minute,
+		LockoutFailures: 10,
+		LockoutDuration: 15 * time.Minute,
+	},
+	model.ThrottleKindLoginIP: {
+		Window:          15 * time.Minute,
+		FreeFailures:    10,
+		BaseDelay:       time.Second,
+		MaxDelay:        time.Minute,
+		LockoutFailures: 50,
+		LockoutDuration: 30 * time.Minute,
+	},
+	model.ThrottleKindSignUpIP: {
+		Window:          time.Hour,
+		FreeFailures:    5,
+		BaseDelay:       10 * time.Second,
+		MaxDelay:        10 * time.Minute,
+		LockoutFailures: 20,
+		LockoutDuration: time.Hour,
+	},
+	model.ThrottleKindPasswordResetEmail: {
+		Window:          time.Hour,
+		FreeFailures:    3,
+		BaseDelay:       time.Minute,
+		MaxDelay:        time.Hour,
+		LockoutFailures: 10,
+		LockoutDuration: time.Hour,
+	},
+	model.ThrottleKindSecondFactor: {
+		Window:          time.Hour,
+		FreeFailures:    3,
+		BaseDelay:       time.Second,
+		MaxDelay:        time.Minute,
+		LockoutFailures: 10,
+		LockoutDuration: time.Hour,
+	},
+}
+
+type throttleService struct {
+	repo     repository.ThrottleRepository
+	policies map[model.ThrottleKind]ThrottlePolicy
+	now      func() time.Time
+}
+
+// NewThrottleService returns ThrottleService.
+func NewThrottleService(repo repository.ThrottleRepository, policies map[model.ThrottleKind]ThrottlePolicy) ThrottleService {
+	return &throttleService{
+		repo:     repo,
+		policies: policies,
+		now:      time.Now,
+	}
+}
+
+// Reserve records an attempt to each of keys before the credential is checked, and returns TooManyRequestsError
+// if any of keys is locked or delayed by the attempts before it. RetryAfter is the longest wait among keys.
+// The attempt is recorded and counted by one write to the repository, so that parallel attempts can not
+// pass together, and it is counted as failure unless it is released.
+func (s *throttleService) Reserve(ctx context.Context, keys ...model.ThrottleKey) (*model.ThrottleReservation, error) {
+	now := s.now()
+	reservation := &model.ThrottleReservation{
+		Keys: make([]model.ThrottleKey, 0, len(keys)),
+		At:   now,
+	}
+
+	var retryAfter time.Duration
+	for _, key := range keys {
+		policy, ok := s.policy(key)
+		if !ok {
+			continue
+		}
+
+		record, err := s.repo.AddThrottleFailure(key, now, policy.Window)
+		if err != nil {
+			return nil, s.releaseOnError(ctx, reservation, errors.Wrap(err, "failed to add throttle failure"))
+		}
+		reservation.Keys = append(reservation.Keys, key)
+
+		// the attempt waits for the attempts before it, including ones which are being checked.
+		before := record.WithoutFailure(now)
+		if policy.LockoutFailures > 0 && !before.IsLocked(now) && before.FailuresSince(now.Add(-policy.Window)) >= policy.LockoutFailures {
+			before.LockedUntil = now.Add(policy.LockoutDuration)
+			if err := s.repo.LockThrottleRecord(key, before.LockedUntil); err != nil {
+				return nil, s.releaseOnError(ctx, reservation, errors.Wrap(err, "failed to lock throttle record"))
+			}
+		}
+
+		if wait := s.wait(policy, before, now); wait > retryAfter {
+			retryAfter = wait
+		}
+	}
+
+	if retryAfter > 0 {
+		// the rejected attempt is not counted, because the credential is not checked.
+		return nil, s.releaseOnError(ctx, reservation, errors.WithStack(&model.TooManyRequestsError{
+			RetryAfter: retryAfter,
+		}))
+	}
+
+	return reservation, nil
+}
+
+// Release forgets the attempt of the reservation,
+// which is called when the credential is valid or it is not checked by other errors.
+func (s *throttleService) Release(ctx context.Context, reservation *model.ThrottleReservation) error {
+	for _, key := range reservation.Keys {
+		if err := s.repo.RemoveThrottleFailure(key, reservation.At); err != nil {
+			return errors.Wrap(err, "failed to remove throttle failure")
+		}
+	}
+
+	return nil
+}
+
+// releaseOnError releases the reservation and returns err, or the error of releasing if it fails.
+func (s *throttleService) releaseOnError(ctx context.Context, reservation *model.ThrottleReservation, err error) error {
+	if rErr := s.Release(ctx, reservation); rErr != nil {
+		return rErr
+	}
+	return err
+}
+
+// Reset forgets failures of keys.
+func (s *throttleService) Reset(ctx context.Context, keys ...model.ThrottleKey) error {
+	for _, key := range keys {
+		if _, ok := s.policy(key); !ok {
+			continue
+		}
+
+		if err := s.repo.DeleteThrottleRecord(key); err != nil {
+			return errors.Wrap(err, "failed to delete throttle record")
+		}
+	}
+
+	return nil
+}
+
+// policy returns the policy of key.
+// Key which has empty value is not throttled.
+func (s *throttleService) policy(key model.ThrottleKey) (ThrottlePolicy, bool) {
+	if key.Value == "" {
+		return ThrottlePolicy{}, false
+	}
+
+	policy, ok := s.policies[key.Kind]
+	return policy, ok
+}
+
+// wait returns the duration until next check is allowed.
+func (s *throttleService) wait(policy ThrottlePolicy, record *model.ThrottleRecord, now time.Time) time.Duration {
+	if record.IsLocked(now) {
+		return record.LockedUntil.Sub(now)
+	}
+
+	over := record.FailuresSince(now.Add(-policy.Window)) - policy.FreeFailures
+	if over <= 0 || policy.BaseDelay <= 0 {
+		return 0
+	}
+
+	delay := policy.BaseDelay
+	for i := 1; i < over && (policy.MaxDelay <= 0 || delay < policy.MaxDelay); i++ {
+		delay *= 2
+	}
+	if policy.MaxDelay > 0 && delay > policy.MaxDelay {
+		delay = policy.MaxDelay
+	}
+
+	if next := record.LastFailure().Add(delay); now.Before(next) {
+		return next.Sub(now)
+	}
+
+	return 0
+}
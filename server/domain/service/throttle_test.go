@@ -0,0 +1,399 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/testutil"
+	"github.com/pkg/errors"
+)
+
+// fakeThrottleRepository is ThrottleRepository in memory for tests, which is safe for concurrent use.
+type fakeThrottleRepository struct {
+	mu      sync.Mutex
+	records map[model.ThrottleKey]*model.ThrottleRecord
+}
+
+func newFakeThrottleRepository() *fakeThrottleRepository {
+	return &fakeThrottleRepository{
+		records: make(map[model.ThrottleKey]*model.ThrottleRecord),
+	}
+}
+
+func (repo *fakeThrottleRepository) GetThrottleRecord(key model.ThrottleKey) (*model.ThrottleRecord, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	r, ok := repo.records[key]
+	if !ok {
+		return nil, errors.WithStack(&model.NoSuchDataError{})
+	}
+	return r.WithoutFailure(time.Time{}), nil
+}
+
+func (repo *fakeThrottleRepository) AddThrottleFailure(key model.ThrottleKey, at time.Time, window time.Duration) (*model.ThrottleRecord, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	r, ok := repo.records[key]
+	if !ok {
+		r = &model.ThrottleRecord{Key: key}
+		repo.records[key] = r
+	}
+	r.Failures = append(r.Failures, at)
+	return r.WithoutFailure(time.Time{}), nil
+}
+
+func (repo *fakeThrottleRepository) RemoveThrottleFailure(key model.ThrottleKey, at time.Time) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if r, ok := repo.records[key]; ok {
+		repo.records[key] = r.WithoutFailure(at)
+	}
+	return nil
+}
+
+func (repo *fakeThrottleRepository) LockThrottleRecord(key model.ThrottleKey, until time.Time) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	r, ok := repo.records[key]
+	if !ok {
+		return errors.WithStack(&model.NoSuchDataError{})
+	}
+	r.LockedUntil = until
+	return nil
+}
+
+func (repo *fakeThrottleRepository) DeleteThrottleRecord(key model.ThrottleKey) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	delete(repo.records, key)
+	return nil
+}
+
+// failures returns the number of failures of key.
+func (repo *fakeThrottleRepository) failures(key model.ThrottleKey) int {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if r, ok := repo.records[key]; ok {
+		return len(r.Failures)
+	}
+	return 0
+}
+
+func Test_throttleService_Reserve(t *testing.T) {
+	policy := ThrottlePolicy{
+		Window:          10 * time.Minute,
+		FreeFailures:    2,
+		BaseDelay:       time.Second,
+		MaxDelay:        4 * time.Second,
+		LockoutFailures: 6,
+		LockoutDuration: time.Hour,
+	}
+
+	nameKey := model.NewThrottleKey(model.ThrottleKindLoginName, model.UserNameForTest)
+	ipKey := model.NewThrottleKey(model.ThrottleKindLoginIP, model.ClientIPForTest)
+
+	type failure struct {
+		key model.ThrottleKey
+		// ago is the duration from the time of check.
+		ago time.Duration
+	}
+
+	tests := []struct {
+		name           string
+		failures       []failure
+		keys           []model.ThrottleKey
+		wantRetryAfter time.Duration
+	}{
+		{
+			name:           "When no failure has been recorded, returns nil",
+			failures:       nil,
+			keys:           []model.ThrottleKey{nameKey, ipKey},
+			wantRetryAfter: 0,
+		},
+		{
+			name: "When failures are within free failures, returns nil",
+			failures: []failure{
+				{key: nameKey, ago: 0},
+				{key: nameKey, ago: 0},
+			},
+			keys:           []model.ThrottleKey{nameKey},
+			wantRetryAfter: 0,
+		},
+		{
+			name: "When failures exceed free failures by 1, returns base delay",
+			failures: []failure{
+				{key: nameKey, ago: 0},
+				{key: nameKey, ago: 0},
+				{key: nameKey, ago: 0},
+			},
+			keys:           []model.ThrottleKey{nameKey},
+			wantRetryAfter: time.Second,
+		},
+		{
+			name: "When failures exceed free failures by 3, returns exponential delay",
+			failures: []failure{
+				{key: nameKey, ago: 0},
+				{key: nameKey, ago: 0},
+				{key: nameKey, ago: 0},
+				{key: nameKey, ago: 0},
+				{key: nameKey, ago: 0},
+			},
+			keys:           []model.ThrottleKey{nameKey},
+			wantRetryAfter: 4 * time.Second,
+		},
+		{
+			name: "When delay has already passed, returns nil",
+			failures: []failure{
+				{key: nameKey, ago: 3 * time.Second},
+				{key: nameKey, ago: 3 * time.Second},
+				{key: nameKey, ago: 3 * time.Second},
+			},
+			keys:           []model.ThrottleKey{nameKey},
+			wantRetryAfter: 0,
+		},
+		{
+			name: "When failures are out of window, returns nil",
+			failures: []failure{
+				{key: nameKey, ago: 20 * time.Minute},
+				{key: nameKey, ago: 20 * time.Minute},
+				{key: nameKey, ago: 20 * time.Minute},
+				{key: nameKey, ago: 0},
+			},
+			keys:           []model.ThrottleKey{nameKey},
+			wantRetryAfter: 0,
+		},
+		{
+			name: "When failures reach lockout failures, locks the key",
+			failures: []failure{
+				{key: nameKey, ago: time.Minute},
+				{key: nameKey, ago: time.Minute},
+				{key: nameKey, ago: time.Minute},
+				{key: nameKey, ago: time.Minute},
+				{key: nameKey, ago: time.Minute},
+				{key: nameKey, ago: time.Minute},
+			},
+			keys:           []model.ThrottleKey{nameKey},
+			wantRetryAfter: time.Hour,
+		},
+		{
+			name: "When only another key has failures, returns the delay of the key",
+			failures: []failure{
+				{key: ipKey, ago: 0},
+				{key: ipKey, ago: 0},
+				{key: ipKey, ago: 0},
+			},
+			keys:           []model.ThrottleKey{nameKey, ipKey},
+			wantRetryAfter: time.Second,
+		},
+		{
+			name: "When key has empty value, returns nil",
+			failures: []failure{
+				{key: model.NewThrottleKey(model.ThrottleKindLoginName, ""), ago: 0},
+				{key: model.NewThrottleKey(model.ThrottleKindLoginName, ""), ago: 0},
+				{key: model.NewThrottleKey(model.ThrottleKindLoginName, ""), ago: 0},
+			},
+			keys:           []model.ThrottleKey{model.NewThrottleKey(model.ThrottleKindLoginName, "")},
+			wantRetryAfter: 0,
+		},
+	}
+
+	now := time.Date(2019, 5, 1, 12, 0, 0, 0, time.UTC)
+	defer testutil.ResetFakeTime()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeThrottleRepository()
+			s := &throttleService{
+				repo: repo,
+				policies: map[model.ThrottleKind]ThrottlePolicy{
+					model.ThrottleKindLoginName: policy,
+					model.ThrottleKindLoginIP:   policy,
+				},
+				now: testutil.TimeNow,
+			}
+
+			for _, f := range tt.failures {
+				if _, err := repo.AddThrottleFailure(f.key, now.Add(-f.ago), policy.Window); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			testutil.SetFakeTime(now)
+			_, err := s.Reserve(context.Background(), tt.keys...)
+			if tt.wantRetryAfter == 0 {
+				if err != nil {
+					t.Errorf("throttleService.Reserve() error = %v, want nil", err)
+				}
+				return
+			}
+
+			tmErr, ok := errors.Cause(err).(*model.TooManyRequestsError)
+			if !ok {
+				t.Fatalf("throttleService.Reserve() error = %v, want TooManyRequestsError", err)
+			}
+			if tmErr.RetryAfter != tt.wantRetryAfter {
+				t.Errorf("throttleService.Reserve() RetryAfter = %v, want %v", tmErr.RetryAfter, tt.wantRetryAfter)
+			}
+
+			// the rejected attempt is not counted.
+			for _, key := range tt.keys {
+				want := 0
+				for _, f := range tt.failures {
+					if f.key == key {
+						want++
+					}
+				}
+				if got := repo.failures(key); got != want {
+					t.Errorf("failures of %s = %d, want %d", key, got, want)
+				}
+			}
+		})
+	}
+}
+
+func Test_throttleService_Reset(t *testing.T) {
+	nameKey := model.NewThrottleKey(model.ThrottleKindLoginName, model.UserNameForTest)
+	ipKey := model.NewThrottleKey(model.ThrottleKindLoginIP, model.ClientIPForTest)
+
+	tests := []struct {
+		name      string
+		resetKeys []model.ThrottleKey
+		checkKey  model.ThrottleKey
+		wantErr   bool
+	}{
+		{
+			name:      "When the key is reset, returns nil",
+			resetKeys: []model.ThrottleKey{nameKey},
+			checkKey:  nameKey,
+			wantErr:   false,
+		},
+		{
+			name:      "When another key is reset, returns TooManyRequestsError",
+			resetKeys: []model.ThrottleKey{nameKey},
+			checkKey:  ipKey,
+			wantErr:   true,
+		},
+	}
+
+	testutil.SetFakeTime(time.Now())
+	defer testutil.ResetFakeTime()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeThrottleRepository()
+			s := &throttleService{
+				repo:     repo,
+				policies: DefaultThrottlePolicies,
+				now:      testutil.TimeNow,
+			}
+
+			for i := 0; i < 20; i++ {
+				for _, key := range []model.ThrottleKey{nameKey, ipKey} {
+					if _, err := repo.AddThrottleFailure(key, testutil.TimeNow(), time.Hour); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			if err := s.Reset(context.Background(), tt.resetKeys...); err != nil {
+				t.Fatalf("throttleService.Reset() error = %v", err)
+			}
+
+			_, err := s.Reserve(context.Background(), tt.checkKey)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("throttleService.Reserve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_throttleService_Reserve_parallel(t *testing.T) {
+	policy := ThrottlePolicy{
+		Window:          10 * time.Minute,
+		FreeFailures:    2,
+		BaseDelay:       time.Second,
+		MaxDelay:        4 * time.Second,
+		LockoutFailures: 6,
+		LockoutDuration: time.Hour,
+	}
+	nameKey := model.NewThrottleKey(model.ThrottleKindLoginName, model.UserNameForTest)
+
+	testutil.SetFakeTime(time.Date(2019, 5, 1, 12, 0, 0, 0, time.UTC))
+	defer testutil.ResetFakeTime()
+
+	repo := newFakeThrottleRepository()
+	s := &throttleService{
+		repo:     repo,
+		policies: map[model.ThrottleKind]ThrottlePolicy{model.ThrottleKindLoginName: policy},
+		now:      testutil.TimeNow,
+	}
+
+	// all attempts are in progress together, so that none of them is released or reset.
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	passed := 0
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := s.Reserve(context.Background(), nameKey); err == nil {
+				mu.Lock()
+				passed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	// only the free failures and the first attempt over them pass, and the others wait for them.
+	if want := policy.FreeFailures + 1; passed != want {
+		t.Errorf("passed attempts = %d, want %d", passed, want)
+	}
+	if got := repo.failures(nameKey); got != passed {
+		t.Errorf("failures = %d, want %d", got, passed)
+	}
+}
+
+func Test_throttleService_Release(t *testing.T) {
+	nameKey := model.NewThrottleKey(model.ThrottleKindLoginName, model.UserNameForTest)
+	ipKey := model.NewThrottleKey(model.ThrottleKindLoginIP, model.ClientIPForTest)
+
+	testutil.SetFakeTime(time.Date(2019, 5, 1, 12, 0, 0, 0, time.UTC))
+	defer testutil.ResetFakeTime()
+
+	repo := newFakeThrottleRepository()
+	s := &throttleService{
+		repo:     repo,
+		policies: DefaultThrottlePolicies,
+		now:      testutil.TimeNow,
+	}
+
+	reservation, err := s.Reserve(context.Background(), nameKey, ipKey)
+	if err != nil {
+		t.Fatalf("throttleService.Reserve() error = %v", err)
+	}
+	if _, err := s.Reserve(context.Background(), ipKey); err != nil {
+		t.Fatalf("throttleService.Reserve() error = %v", err)
+	}
+
+	if err := s.Release(context.Background(), reservation); err != nil {
+		t.Fatalf("throttleService.Release() error = %v", err)
+	}
+
+	// only the released attempt is forgotten.
+	if got := repo.failures(nameKey); got != 0 {
+		t.Errorf("failures of name = %d, want 0", got)
+	}
+	if got := repo.failures(ipKey); got != 1 {
+		t.Errorf("failures of IP = %d, want 1", got)
+	}
+}
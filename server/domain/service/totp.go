@@ -0,0 +1,129 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+)
+
+// totpSkew is the number of steps allowed before and after the current one,
+// so that the code is accepted across the boundary of steps and with a little clock drift.
+const totpSkew = 1
+
+// base32NoPadding is the encoding of TOTP secret which authenticator apps read.
+var base32NoPadding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// TOTPService is interface of domain service of TOTP.
+// This implements TOTP of RFC 6238 with HMAC-SHA1, 6 digits and 30 seconds step.
+type TOTPService interface {
+	GenerateSecret() (string, error)
+	ProvisioningURI(secret, accountName string) string
+	// Verify returns the step of the code if the code is valid and its step is after lastUsedStep.
+	Verify(secret, code string, lastUsedStep int64) (int64, bool)
+	GenerateRecoveryCodes() ([]string, error)
+}
+
+type totpService struct {
+	issuer string
+	now    func() time.Time
+}
+
+// NewTOTPService returns TOTPService.
+// issuer is the name of the service shown in authenticator apps.
+func NewTOTPService(issuer string) TOTPService {
+	return &totpService{
+		issuer: issuer,
+		now:    time.Now,
+	}
+}
+
+// GenerateSecret generates a random secret in base32.
+func (s *totpService) GenerateSecret() (string, error) {
+	b := make([]byte, model.TOTPSecretSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "failed to read random bytes")
+	}
+	return base32NoPadding.EncodeToString(b), nil
+}
+
+// ProvisioningURI returns otpauth URI which is shown as QR code to register the secret to authenticator apps.
+func (s *totpService) ProvisioningURI(secret, accountName string) string {
+	v := url.Values{}
+	v.Set("secret", secret)
+	v.Set("issuer", s.issuer)
+	v.Set("algorithm", "SHA1")
+	v.Set("digits", fmt.Sprint(model.TOTPDigits))
+	v.Set("period", fmt.Sprint(int(model.TOTPPeriod.Seconds())))
+
+	label := url.PathEscape(s.issuer) + ":" + url.PathEscape(accountName)
+	return "otpauth://totp/" + label + "?" + v.Encode()
+}
+
+// Verify checks the code against steps around now.
+// The step must be after lastUsedStep, so that the code seen by someone can not be replayed.
+func (s *totpService) Verify(secret, code string, lastUsedStep int64) (int64, bool) {
+	key, err := base32NoPadding.DecodeString(strings.ToUpper(secret))
+	if err != nil || len(code) != model.TOTPDigits {
+		return 0, false
+	}
+
+	current := s.now().Unix() / int64(model.TOTPPeriod.Seconds())
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if step <= lastUsedStep {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(totpCode(key, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// GenerateRecoveryCodes generates random recovery codes formatted as xxxx-xxxx-xxxx-xxxx.
+func (s *totpService) GenerateRecoveryCodes() ([]string, error) {
+	codes := make([]string, 0, model.RecoveryCodeCount)
+	for i := 0; i < model.RecoveryCodeCount; i++ {
+		b := make([]byte, model.RecoveryCodeSize)
+		if _, err := rand.Read(b); err != nil {
+			return nil, errors.Wrap(err, "failed to read random bytes")
+		}
+
+		raw := strings.ToLower(base32NoPadding.EncodeToString(b))
+		groups := make([]string, 0, len(raw)/4)
+		for j := 0; j < len(raw); j += 4 {
+			groups = append(groups, raw[j:j+4])
+		}
+		codes = append(codes, strings.Join(groups, "-"))
+	}
+	return codes, nil
+}
+
+// totpCode returns the code of the step by HOTP of RFC 4226.
+func totpCode(key []byte, step int64) string {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, uint64(step))
+
+	h := hmac.New(sha1.New, key)
+	h.Write(msg)
+	sum := h.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	bin := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < model.TOTPDigits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", model.TOTPDigits, bin%mod)
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/testutil"
+)
+
+func Test_totpService_Verify(t *testing.T) {
+	defer testutil.ResetFakeTime()
+
+	// codes are the last 6 digits of the test vectors of SHA1 in RFC 6238.
+	tests := []struct {
+		name         string
+		now          time.Time
+		code         string
+		lastUsedStep int64
+		wantStep     int64
+		wantOK       bool
+	}{
+		{
+			name:     "When the code is of the current step, returns the step",
+			now:      time.Unix(59, 0),
+			code:     "287082",
+			wantStep: 1,
+			wantOK:   true,
+		},
+		{
+			name:     "When the code is of the current step at large time, returns the step",
+			now:      time.Unix(1111111109, 0),
+			code:     "081804",
+			wantStep: 37037036,
+			wantOK:   true,
+		},
+		{
+			name:     "When the code is of the previous step, returns the step",
+			now:      time.Unix(1111111111, 0),
+			code:     "081804",
+			wantStep: 37037036,
+			wantOK:   true,
+		},
+		{
+			name:     "When the code is of the next step, returns the step",
+			now:      time.Unix(1111111109, 0),
+			code:     "050471",
+			wantStep: 37037037,
+			wantOK:   true,
+		},
+		{
+			name:   "When the code is of two steps before, returns false",
+			now:    time.Unix(1111111109+60, 0),
+			code:   "081804",
+			wantOK: false,
+		},
+		{
+			name:         "When the step of the code has been used, returns false",
+			now:          time.Unix(59, 0),
+			code:         "287082",
+			lastUsedStep: 1,
+			wantOK:       false,
+		},
+		{
+			name:   "When the code is wrong, returns false",
+			now:    time.Unix(59, 0),
+			code:   "287083",
+			wantOK: false,
+		},
+		{
+			name:   "When the code is not 6 digits, returns false",
+			now:    time.Unix(59, 0),
+			code:   "94287082",
+			wantOK: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testutil.SetFakeTime(tt.now)
+
+			s := NewTOTPService("testIssuer")
+			s.(*totpService).now = testutil.TimeNow
+
+			gotStep, gotOK := s.Verify(model.TOTPSecretForTest, tt.code, tt.lastUsedStep)
+			if gotOK != tt.wantOK {
+				testutil.Errorf(t, tt.wantOK, gotOK)
+			}
+			if gotStep != tt.wantStep {
+				testutil.Errorf(t, tt.wantStep, gotStep)
+			}
+		})
+	}
+}
+
+func Test_totpService_ProvisioningURI(t *testing.T) {
+	s := NewTOTPService("test Issuer")
+
+	got, err := url.Parse(s.ProvisioningURI(model.TOTPSecretForTest, model.UserNameForTest))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Scheme != "otpauth" || got.Host != "totp" {
+		t.Errorf("unexpected uri: %s", got)
+	}
+	if want := "/test Issuer:" + model.UserNameForTest; got.Path != want {
+		testutil.Errorf(t, want, got.Path)
+	}
+
+	q := got.Query()
+	for k, want := range map[string]string{
+		"secret":    model.TOTPSecretForTest,
+		"issuer":    "test Issuer",
+		"algorithm": "SHA1",
+		"digits":    "6",
+		"period":    "30",
+	} {
+		if q.Get(k) != want {
+			testutil.Errorf(t, want, q.Get(k))
+		}
+	}
+}
+
+func Test_totpService_GenerateRecoveryCodes(t *testing.T) {
+	s := NewTOTPService("testIssuer")
+
+	codes, err := s.GenerateRecoveryCodes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(codes) != model.RecoveryCodeCount {
+		testutil.Errorf(t, model.RecoveryCodeCount, len(codes))
+	}
+
+	seen := make(map[string]bool)
+	for _, code := range codes {
+		if len(strings.Replace(code, "-", "", -1)) != 16 {
+			t.Errorf("unexpected format of code: %s", code)
+		}
+		if seen[code] {
+			t.Errorf("duplicated code: %s", code)
+		}
+		seen[code] = true
+	}
+}
@@ -57,7 +57,6 @@ func Test_userService_IsAlreadyExistID(t *testing.T) {
 				user: &model.User{
 					ID:        model.UserValidIDForTest,
 					Name:      model.UserNameForTest,
-					SessionID: model.SessionValidIDForTest,
 					Password:  model.PasswordForTest,
 					CreatedAt: testutil.TimeNow(),
 					UpdatedAt: testutil.TimeNow(),
@@ -169,7 +168,6 @@ func Test_userService_IsAlreadyExistName(t *testing.T) {
 				user: &model.User{
 					ID:        model.UserValidIDForTest,
 					Name:      model.UserNameForTest,
-					SessionID: model.SessionValidIDForTest,
 					Password:  model.PasswordForTest,
 					CreatedAt: testutil.TimeNow(),
 					UpdatedAt: testutil.TimeNow(),
@@ -0,0 +1,166 @@
+package blob
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/service"
+)
+
+// localStore stores blobs as files under dir, for a single server or development without S3.
+type localStore struct {
+	dir string
+}
+
+// NewLocalStore generates and returns BlobStore which stores blobs as files under dir.
+func NewLocalStore(dir string) (service.BlobStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, errors.Wrap(err, "failed to make directory of blobs")
+	}
+	return &localStore{
+		dir: dir,
+	}, nil
+}
+
+// Put writes the content to a temporary file and renames it to the file of key,
+// so that the incomplete content is never read as key.
+func (s *localStore) Put(ctx context.Context, key string, r io.Reader) (int64, error) {
+	name, err := s.path(key)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(filepath.Dir(name), 0700); err != nil {
+		return 0, errors.Wrap(err, "failed to make directory of blob")
+	}
+
+	f, err := ioutil.TempFile(filepath.Dir(name), ".put-")
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to create temporary file of blob")
+	}
+	defer os.Remove(f.Name())
+
+	n, err := io.Copy(f, r)
+	if cErr := f.Close(); err == nil {
+		err = cErr
+	}
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to write blob")
+	}
+
+	if err := os.Rename(f.Name(), name); err != nil {
+		return 0, errors.Wrap(err, "failed to rename blob")
+	}
+	return n, nil
+}
+
+// Open opens the file of key and seeks to offset.
+func (s *localStore) Open(ctx context.Context, key string, offset int64) (io.ReadCloser, error) {
+	name, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, noSuchBlobError(key, err)
+		}
+		return nil, errors.Wrap(err, "failed to open blob")
+	}
+
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		f.Close()
+		return nil, errors.Wrap(err, "failed to seek blob")
+	}
+	return f, nil
+}
+
+// Exists returns whether the file of key exists.
+func (s *localStore) Exists(ctx context.Context, key string) (bool, error) {
+	name, err := s.path(key)
+	if err != nil {
+		return false, err
+	}
+
+	if _, err := os.Stat(name); err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, errors.Wrap(err, "failed to stat blob")
+	}
+	return true, nil
+}
+
+// Rename renames the file of the key to the file of another key.
+func (s *localStore) Rename(ctx context.Context, from, to string) error {
+	fromName, err := s.path(from)
+	if err != nil {
+		return err
+	}
+	toName, err := s.path(to)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(toName), 0700); err != nil {
+		return errors.Wrap(err, "failed to make directory of blob")
+	}
+	if err := os.Rename(fromName, toName); err != nil {
+		if os.IsNotExist(err) {
+			return noSuchBlobError(from, err)
+		}
+		return errors.Wrap(err, "failed to rename blob")
+	}
+	return nil
+}
+
+// Delete removes the file of key.
+func (s *localStore) Delete(ctx context.Context, key string) error {
+	name, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(name); err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "failed to remove blob")
+	}
+	return nil
+}
+
+// path returns the path of the file of key under dir.
+// Keys which go out of dir are rejected, although they are generated by the server.
+func (s *localStore) path(key string) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
+
+// validateKey checks that key is a relative path without empty, . and .. segments.
+func validateKey(key string) error {
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." || strings.ContainsRune(segment, '\\') {
+			return errors.Errorf("invalid key of blob %q", key)
+		}
+	}
+	return nil
+}
+
+// noSuchBlobError returns NoSuchDataError of the blob of key.
+func noSuchBlobError(key string, err error) error {
+	return errors.WithStack(&model.NoSuchDataError{
+		BaseErr:                     err,
+		PropertyNameForDeveloper:    model.FilePropertyForDeveloper,
+		PropertyNameForUser:         model.FilePropertyForUser,
+		PropertyValue:               key,
+		DomainModelNameForDeveloper: model.DomainModelNameAttachmentForDeveloper,
+		DomainModelNameForUser:      model.DomainModelNameAttachmentForUser,
+	})
+}
@@ -0,0 +1,401 @@
+package blob
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/service"
+)
+
+// s3Service is the service name in the scope of signature.
+const s3Service = "s3"
+
+// s3SignatureAlgorithm is the algorithm of Signature Version 4.
+const s3SignatureAlgorithm = "AWS4-HMAC-SHA256"
+
+// s3DateLayout and s3TimeLayout are the layouts of the date in the scope and of X-Amz-Date.
+const (
+	s3DateLayout = "20060102"
+	s3TimeLayout = "20060102T150405Z"
+)
+
+// maxS3ErrorSize is the max bytes of error response read from S3, which is put into the error.
+const maxS3ErrorSize = 1 << 10
+
+// emptyPayloadHash is the hex encoded SHA256 of empty payload.
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// S3Config is the configuration of the bucket of S3 or a compatible storage.
+type S3Config struct {
+	// Endpoint is the URL of the storage, e.g. https://s3.ap-northeast-1.amazonaws.com or http://localhost:9000.
+	// Objects are addressed by path, because compatible storages may not resolve buckets as subdomains.
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// HTTPClient talks to the storage, and http.DefaultClient is used if nil.
+	HTTPClient *http.Client
+}
+
+// s3Store stores blobs as objects of the bucket, which is shared by servers.
+// Requests are signed by Signature Version 4.
+type s3Store struct {
+	config   S3Config
+	endpoint *url.URL
+	client   *http.Client
+	now      func() time.Time
+}
+
+// NewS3Store generates and returns BlobStore which stores blobs as objects of the bucket.
+func NewS3Store(config S3Config) (service.BlobStore, error) {
+	if config.Endpoint == "" || config.Region == "" || config.Bucket == "" || config.AccessKeyID == "" || config.SecretAccessKey == "" {
+		return nil, errors.New("endpoint, region, bucket and credentials of S3 are required")
+	}
+
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse endpoint of S3 %q", config.Endpoint)
+	}
+	if endpoint.Scheme != "http" && endpoint.Scheme != "https" {
+		return nil, errors.Errorf("endpoint of S3 should be http or https, but %q", config.Endpoint)
+	}
+
+	s := &s3Store{
+		config:   config,
+		endpoint: endpoint,
+		client:   config.HTTPClient,
+		now:      time.Now,
+	}
+	if s.client == nil {
+		s.client = http.DefaultClient
+	}
+	return s, nil
+}
+
+// Put uploads the content as the object of key.
+// S3 needs the length and the hash of the content before it, so the content is spooled to a temporary file.
+func (s *s3Store) Put(ctx context.Context, key string, r io.Reader) (int64, error) {
+	if err := validateKey(key); err != nil {
+		return 0, err
+	}
+
+	f, err := ioutil.TempFile("", "blob-")
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to create temporary file of blob")
+	}
+	defer func() {
+		f.Close()
+		os.Remove(f.Name())
+	}()
+
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(f, h), r)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to spool blob")
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return 0, errors.Wrap(err, "failed to seek spooled blob")
+	}
+
+	req, err := s.newRequest(ctx, http.MethodPut, key, ioutil.NopCloser(f), hex.EncodeToString(h.Sum(nil)))
+	if err != nil {
+		return 0, err
+	}
+	req.ContentLength = n
+
+	res, err := s.do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return 0, unexpectedResponseError(req, res)
+	}
+	return n, nil
+}
+
+// Open downloads the object of key from offset by the range request.
+func (s *s3Store) Open(ctx context.Context, key string, offset int64) (io.ReadCloser, error) {
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+
+	req, err := s.newRequest(ctx, http.MethodGet, key, nil, emptyPayloadHash)
+	if err != nil {
+		return nil, err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		s.sign(req, emptyPayloadHash)
+	}
+
+	res, err := s.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch res.StatusCode {
+	case http.StatusOK, http.StatusPartialContent:
+		return res.Body, nil
+	case http.StatusRequestedRangeNotSatisfiable:
+		// the offset is at the end of the object.
+		res.Body.Close()
+		return ioutil.NopCloser(strings.NewReader("")), nil
+	case http.StatusNotFound:
+		res.Body.Close()
+		return nil, noSuchBlobError(key, nil)
+	default:
+		defer res.Body.Close()
+		return nil, unexpectedResponseError(req, res)
+	}
+}
+
+// Exists returns whether the object of key exists.
+func (s *s3Store) Exists(ctx context.Context, key string) (bool, error) {
+	if err := validateKey(key); err != nil {
+		return false, err
+	}
+
+	req, err := s.newRequest(ctx, http.MethodHead, key, nil, emptyPayloadHash)
+	if err != nil {
+		return false, err
+	}
+
+	res, err := s.do(req)
+	if err != nil {
+		return false, err
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, unexpectedResponseError(req, res)
+	}
+}
+
+// Rename copies the object of the key to another key and deletes it, because S3 can not rename objects.
+func (s *s3Store) Rename(ctx context.Context, from, to string) error {
+	if err := validateKey(from); err != nil {
+		return err
+	}
+	if err := validateKey(to); err != nil {
+		return err
+	}
+
+	req, err := s.newRequest(ctx, http.MethodPut, to, nil, emptyPayloadHash)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Amz-Copy-Source", "/"+s.config.Bucket+"/"+escapePath(from))
+	s.sign(req, emptyPayloadHash)
+
+	res, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return noSuchBlobError(from, nil)
+	}
+	if res.StatusCode != http.StatusOK {
+		return unexpectedResponseError(req, res)
+	}
+
+	// copying can fail after the status is sent, and then the error is in the body.
+	body, err := ioutil.ReadAll(io.LimitReader(res.Body, maxS3ErrorSize))
+	if err != nil {
+		return errors.Wrap(err, "failed to read response of copying blob")
+	}
+	if strings.Contains(string(body), "<Error>") {
+		return errors.Errorf("failed to copy blob %q to %q: %s", from, to, body)
+	}
+
+	return s.Delete(ctx, from)
+}
+
+// Delete deletes the object of key. S3 does not return error if it does not exist.
+func (s *s3Store) Delete(ctx context.Context, key string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+
+	req, err := s.newRequest(ctx, http.MethodDelete, key, nil, emptyPayloadHash)
+	if err != nil {
+		return err
+	}
+
+	res, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound:
+		return nil
+	default:
+		return unexpectedResponseError(req, res)
+	}
+}
+
+// newRequest returns the signed request to the object of key.
+// Requests which have more headers must be signed again after setting them.
+func (s *s3Store) newRequest(ctx context.Context, method, key string, body io.ReadCloser, payloadHash string) (*http.Request, error) {
+	u := *s.endpoint
+	u.Path = strings.TrimSuffix(s.endpoint.Path, "/") + "/" + s.config.Bucket + "/" + key
+	u.RawPath = escapePath(u.Path)
+
+	req, err := http.NewRequest(method, u.String(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to make request to S3")
+	}
+	if body != nil {
+		req.Body = body
+	}
+
+	req = req.WithContext(ctx)
+	s.sign(req, payloadHash)
+	return req, nil
+}
+
+// do sends the request.
+func (s *s3Store) do(req *http.Request) (*http.Response, error) {
+	res, err := s.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to request %s %s", req.Method, req.URL.Path)
+	}
+	return res, nil
+}
+
+// sign sets the headers of Signature Version 4 to the request.
+// Every header set to the request is signed with Host.
+func (s *s3Store) sign(req *http.Request, payloadHash string) {
+	now := s.now().UTC()
+	req.Header.Set("X-Amz-Date", now.Format(s3TimeLayout))
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	req.Header.Del("Authorization")
+
+	scope := strings.Join([]string{now.Format(s3DateLayout), s.config.Region, s3Service, "aws4_request"}, "/")
+	canonicalRequest, signedHeaders := canonicalRequest(req, payloadHash)
+
+	stringToSign := strings.Join([]string{
+		s3SignatureAlgorithm,
+		now.Format(s3TimeLayout),
+		scope,
+		hexSHA256(canonicalRequest),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.config.SecretAccessKey), now.Format(s3DateLayout))
+	key = hmacSHA256(key, s.config.Region)
+	key = hmacSHA256(key, s3Service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3SignatureAlgorithm, s.config.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// canonicalRequest returns the canonical request of Signature Version 4 and the signed headers in it.
+func canonicalRequest(req *http.Request, payloadHash string) (string, string) {
+	headers := map[string]string{"host": req.Host}
+	if req.Host == "" {
+		headers["host"] = req.URL.Host
+	}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.Join(strings.Fields(strings.Join(values, ",")), " ")
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	return strings.Join([]string{
+		req.Method,
+		escapePath(req.URL.Path),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n"), signedHeaders
+}
+
+// canonicalQuery returns the query sorted by name with values encoded by escape.
+func canonicalQuery(query url.Values) string {
+	pairs := make([]string, 0, len(query))
+	for name, values := range query {
+		for _, value := range values {
+			pairs = append(pairs, escape(name, true)+"="+escape(value, true))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// escapePath encodes the path by escape keeping slashes.
+func escapePath(path string) string {
+	return escape(path, false)
+}
+
+// escape encodes characters other than unreserved ones of RFC 3986 as Signature Version 4 requires.
+func escape(s string, escapeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '.', c == '_', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !escapeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// hexSHA256 returns the hex encoded SHA256 of s.
+func hexSHA256(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// hmacSHA256 returns HMAC-SHA256 of data with key.
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// unexpectedResponseError returns the error of the response with the head of its body.
+func unexpectedResponseError(req *http.Request, res *http.Response) error {
+	body, _ := ioutil.ReadAll(io.LimitReader(res.Body, maxS3ErrorSize))
+	return errors.Errorf("S3 responded %s to %s %s: %s", res.Status, req.Method, req.URL.Path, body)
+}
@@ -0,0 +1,210 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+	log "github.com/sirupsen/logrus"
+)
+
+// apiKeyRepository is repository of API key.
+type apiKeyRepository struct {
+	ctx context.Context
+}
+
+// NewAPIKeyRepository generates and returns apiKeyRepository.
+func NewAPIKeyRepository(ctx context.Context) repository.APIKeyRepository {
+	return &apiKeyRepository{
+		ctx: ctx,
+	}
+}
+
+// ErrorMsg generates and returns error message.
+func (repo *apiKeyRepository) ErrorMsg(method model.RepositoryMethod, err error) error {
+	return &model.RepositoryError{
+		BaseErr:                     err,
+		RepositoryMethod:            method,
+		DomainModelNameForDeveloper: model.DomainModelNameAPIKeyForDeveloper,
+		DomainModelNameForUser:      model.DomainModelNameAPIKeyForUser,
+	}
+}
+
+// GetAPIKeyByKey gets and returns a record which has the key.
+// Key is hashed before querying because only the hash is stored.
+func (repo *apiKeyRepository) GetAPIKeyByKey(m repository.SQLManager, key string) (*model.APIKey, error) {
+	query := "SELECT id, prefix, user_id, name, scopes, created_at, expires_at, last_used_at FROM api_keys WHERE id=?"
+
+	id := model.APIKeyIDFromKey(key)
+	list, err := repo.list(m, model.RepositoryMethodREAD, query, id)
+
+	if len(list) == 0 {
+		err = &model.NoSuchDataError{
+			BaseErr:                     err,
+			PropertyNameForDeveloper:    model.IDPropertyForDeveloper,
+			PropertyNameForUser:         model.IDPropertyForUser,
+			PropertyValue:               id,
+			DomainModelNameForDeveloper: model.DomainModelNameAPIKeyForDeveloper,
+			DomainModelNameForUser:      model.DomainModelNameAPIKeyForUser,
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	if err != nil {
+		return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+
+	list[0].Key = key
+	return list[0], nil
+}
+
+// GetAPIKeysByUserID gets and returns records of the user in order of creation.
+// This returns empty list if the user has no API key.
+func (repo *apiKeyRepository) GetAPIKeysByUserID(m repository.SQLManager, userID uint32) ([]*model.APIKey, error) {
+	query := "SELECT id, prefix, user_id, name, scopes, created_at, expires_at, last_used_at FROM api_keys WHERE user_id=? ORDER BY created_at DESC"
+
+	list, err := repo.list(m, model.RepositoryMethodREAD, query, userID)
+	if err != nil {
+		return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+
+	return list, nil
+}
+
+// list gets and returns list of records.
+func (repo *apiKeyRepository) list(m repository.SQLManager, method model.RepositoryMethod, query string, args ...interface{}) (apiKeys []*model.APIKey, err error) {
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return nil, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	rows, err := stmt.QueryContext(repo.ctx, args...)
+	if err != nil {
+		return nil, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+	defer func() {
+		err = rows.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	list := make([]*model.APIKey, 0)
+	for rows.Next() {
+		apiKey := &model.APIKey{}
+		var scopes string
+
+		err = rows.Scan(
+			&apiKey.ID,
+			&apiKey.Prefix,
+			&apiKey.UserID,
+			&apiKey.Name,
+			&scopes,
+			&apiKey.CreatedAt,
+			&apiKey.ExpiresAt,
+			&apiKey.LastUsedAt,
+		)
+
+		if err != nil {
+			return nil, repo.ErrorMsg(method, errors.WithStack(err))
+		}
+
+		apiKey.Scopes = model.ParseScopes(scopes)
+		list = append(list, apiKey)
+	}
+
+	return list, nil
+}
+
+// InsertAPIKey insert a record.
+func (repo *apiKeyRepository) InsertAPIKey(m repository.SQLManager, apiKey *model.APIKey) error {
+	query := "INSERT INTO api_keys (id, prefix, user_id, name, scopes, created_at, expires_at) VALUES (?, ?, ?, ?, ?, ?, ?)"
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return repo.ErrorMsg(model.RepositoryMethodInsert, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	result, err := stmt.ExecContext(repo.ctx, apiKey.ID, apiKey.Prefix, apiKey.UserID, apiKey.Name, apiKey.Scopes.String(), apiKey.CreatedAt, apiKey.ExpiresAt)
+	if err != nil {
+		return repo.ErrorMsg(model.RepositoryMethodInsert, errors.WithStack(err))
+	}
+
+	affect, err := result.RowsAffected()
+	if affect != 1 {
+		err = fmt.Errorf("total affected: %d ", affect)
+		return repo.ErrorMsg(model.RepositoryMethodInsert, errors.WithStack(err))
+	}
+
+	return nil
+}
+
+// UpdateLastUsed updates the time when the API key was used last.
+func (repo *apiKeyRepository) UpdateLastUsed(m repository.SQLManager, id string, at time.Time) error {
+	query := "UPDATE api_keys SET last_used_at=? WHERE id=?"
+
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return repo.ErrorMsg(model.RepositoryMethodUPDATE, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	// affected rows is not checked because it is 0 when nothing is changed.
+	if _, err := stmt.ExecContext(repo.ctx, at, id); err != nil {
+		return repo.ErrorMsg(model.RepositoryMethodUPDATE, errors.WithStack(err))
+	}
+
+	return nil
+}
+
+// DeleteAPIKey delete a record.
+func (repo *apiKeyRepository) DeleteAPIKey(m repository.SQLManager, id string) error {
+	query := "DELETE FROM api_keys WHERE id=?"
+	return repo.delete(m, query, id)
+}
+
+// DeleteAPIKeysByUserID deletes all records of the user.
+func (repo *apiKeyRepository) DeleteAPIKeysByUserID(m repository.SQLManager, userID uint32) error {
+	query := "DELETE FROM api_keys WHERE user_id=?"
+	return repo.delete(m, query, userID)
+}
+
+// delete deletes records.
+func (repo *apiKeyRepository) delete(m repository.SQLManager, query string, args ...interface{}) error {
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return repo.ErrorMsg(model.RepositoryMethodDELETE, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	if _, err := stmt.ExecContext(repo.ctx, args...); err != nil {
+		return repo.ErrorMsg(model.RepositoryMethodDELETE, errors.WithStack(err))
+	}
+
+	return nil
+}
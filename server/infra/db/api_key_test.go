@@ -0,0 +1,99 @@
+package db
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/testutil"
+	"github.com/pkg/errors"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func Test_apiKeyRepository_GetAPIKeyByKey(t *testing.T) {
+	// set sqlmock
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Close()
+
+	testutil.SetFakeTime(time.Now())
+	expiresAt := testutil.TimeNow().Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		want    *model.APIKey
+		wantErr bool
+	}{
+		{
+			name: "When the API key has expiry, returns it with scopes",
+			want: &model.APIKey{
+				ID:         model.APIKeyIDFromKey(model.APIKeyForTest),
+				Key:        model.APIKeyForTest,
+				Prefix:     model.APIKeyForTest[:11],
+				UserID:     model.UserValidIDForTest,
+				Name:       model.APIKeyNameForTest,
+				Scopes:     model.Scopes{model.ScopeReadThreads, model.ScopePostComments},
+				CreatedAt:  testutil.TimeNow(),
+				ExpiresAt:  &expiresAt,
+				LastUsedAt: &expiresAt,
+			},
+		},
+		{
+			name: "When the API key has neither expiry nor last used, returns it with nil",
+			want: &model.APIKey{
+				ID:        model.APIKeyIDFromKey(model.APIKeyForTest),
+				Key:       model.APIKeyForTest,
+				Prefix:    model.APIKeyForTest[:11],
+				UserID:    model.UserValidIDForTest,
+				Name:      model.APIKeyNameForTest,
+				Scopes:    model.Scopes{model.ScopeAdmin},
+				CreatedAt: testutil.TimeNow(),
+			},
+		},
+		{
+			name:    "When the API key does not exist, returns NoSuchDataError",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := "SELECT id, prefix, user_id, name, scopes, created_at, expires_at, last_used_at FROM api_keys WHERE id=?"
+			rows := sqlmock.NewRows([]string{"id", "prefix", "user_id", "name", "scopes", "created_at", "expires_at", "last_used_at"})
+			if tt.want != nil {
+				var e, l interface{}
+				if tt.want.ExpiresAt != nil {
+					e = *tt.want.ExpiresAt
+				}
+				if tt.want.LastUsedAt != nil {
+					l = *tt.want.LastUsedAt
+				}
+				rows.AddRow(tt.want.ID, tt.want.Prefix, tt.want.UserID, tt.want.Name, tt.want.Scopes.String(), tt.want.CreatedAt, e, l)
+			}
+			mock.ExpectPrepare(q).ExpectQuery().WithArgs(model.APIKeyIDFromKey(model.APIKeyForTest)).WillReturnRows(rows)
+
+			repo := &apiKeyRepository{
+				ctx: context.Background(),
+			}
+			got, err := repo.GetAPIKeyByKey(db, model.APIKeyForTest)
+
+			if tt.wantErr {
+				if _, ok := errors.Cause(err).(*model.NoSuchDataError); !ok {
+					t.Errorf("apiKeyRepository.GetAPIKeyByKey() error = %v, want NoSuchDataError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("apiKeyRepository.GetAPIKeyByKey() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("apiKeyRepository.GetAPIKeyByKey() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+	log "github.com/sirupsen/logrus"
+)
+
+// attachmentColumns is the columns of attachments which are read into Attachment.
+const attachmentColumns = "attachments.id, attachments.comment_id, attachments.user_id, attachments.file_name, " +
+	"attachments.content_type, attachments.size, attachments.sha256, attachments.created_at"
+
+// attachmentRepository is repository of files attached to comments.
+type attachmentRepository struct {
+	ctx context.Context
+}
+
+// NewAttachmentRepository generates and returns AttachmentRepository.
+func NewAttachmentRepository(ctx context.Context) repository.AttachmentRepository {
+	return &attachmentRepository{
+		ctx: ctx,
+	}
+}
+
+// ErrorMsg generates and returns error message.
+func (repo *attachmentRepository) ErrorMsg(method model.RepositoryMethod, err error) error {
+	return &model.RepositoryError{
+		BaseErr:                     err,
+		RepositoryMethod:            method,
+		DomainModelNameForDeveloper: model.DomainModelNameAttachmentForDeveloper,
+		DomainModelNameForUser:      model.DomainModelNameAttachmentForUser,
+	}
+}
+
+// ListAttachmentsByCommentIDs gets and returns records of the comments in order of comment id and then of id.
+// This returns empty list if commentIDs is empty.
+func (repo *attachmentRepository) ListAttachmentsByCommentIDs(m repository.SQLManager, commentIDs []uint32) ([]*model.Attachment, error) {
+	if len(commentIDs) == 0 {
+		return make([]*model.Attachment, 0), nil
+	}
+
+	query := "SELECT " + attachmentColumns + " FROM attachments " +
+		"WHERE comment_id IN (?" + strings.Repeat(", ?", len(commentIDs)-1) + ") ORDER BY comment_id, id"
+
+	args := make([]interface{}, 0, len(commentIDs))
+	for _, id := range commentIDs {
+		args = append(args, id)
+	}
+
+	return repo.list(m, model.RepositoryMethodREAD, query, args...)
+}
+
+// ListAttachmentsOfDeletedComments gets and returns records of comments deleted before the time.
+func (repo *attachmentRepository) ListAttachmentsOfDeletedComments(m repository.SQLManager, deletedBefore time.Time) ([]*model.Attachment, error) {
+	query := "SELECT " + attachmentColumns + " FROM attachments " +
+		"INNER JOIN comments ON comments.id=attachments.comment_id WHERE comments.deleted_at<? ORDER BY attachments.id"
+
+	return repo.list(m, model.RepositoryMethodREAD, query, deletedBefore)
+}
+
+// GetAttachmentByID gets and returns a record specified by id.
+func (repo *attachmentRepository) GetAttachmentByID(m repository.SQLManager, id uint32) (*model.Attachment, error) {
+	query := "SELECT " + attachmentColumns + " FROM attachments WHERE id=?"
+
+	list, err := repo.list(m, model.RepositoryMethodREAD, query, id)
+
+	if len(list) == 0 {
+		err = &model.NoSuchDataError{
+			BaseErr:                     err,
+			PropertyNameForDeveloper:    model.IDPropertyForDeveloper,
+			PropertyNameForUser:         model.IDPropertyForUser,
+			PropertyValue:               id,
+			DomainModelNameForDeveloper: model.DomainModelNameAttachmentForDeveloper,
+			DomainModelNameForUser:      model.DomainModelNameAttachmentForUser,
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	if err != nil {
+		return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+
+	return list[0], nil
+}
+
+// CountAttachmentsByCommentID counts records of the comment.
+func (repo *attachmentRepository) CountAttachmentsByCommentID(m repository.SQLManager, commentID uint32) (int, error) {
+	query := "SELECT COUNT(*) FROM attachments WHERE comment_id=?"
+
+	return repo.count(m, query, commentID)
+}
+
+// CountAttachmentsBySHA256ForUpdate counts records which have the content of the hash.
+// The key of sha256 is locked including the gap even if no record has the hash,
+// so that inserting the record of the hash waits until the tx ends.
+func (repo *attachmentRepository) CountAttachmentsBySHA256ForUpdate(m repository.SQLManager, sha256 string) (int, error) {
+	query := "SELECT COUNT(*) FROM attachments WHERE sha256=? FOR UPDATE"
+
+	return repo.count(m, query, sha256)
+}
+
+// InsertAttachment insert a record.
+func (repo *attachmentRepository) InsertAttachment(m repository.SQLManager, attachment *model.Attachment) (uint32, error) {
+	query := "INSERT INTO attachments (comment_id, user_id, file_name, content_type, size, sha256, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)"
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return model.InvalidID, repo.ErrorMsg(model.RepositoryMethodInsert, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	result, err := stmt.ExecContext(repo.ctx, attachment.CommentID, attachment.UserID, attachment.FileName, attachment.ContentType, attachment.Size, attachment.SHA256, attachment.CreatedAt)
+	if err != nil {
+		return model.InvalidID, repo.ErrorMsg(model.RepositoryMethodInsert, errors.WithStack(err))
+	}
+
+	affect, err := result.RowsAffected()
+	if affect != 1 {
+		err = fmt.Errorf("total affected: %d ", affect)
+		return model.InvalidID, repo.ErrorMsg(model.RepositoryMethodInsert, errors.WithStack(err))
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return model.InvalidID, repo.ErrorMsg(model.RepositoryMethodInsert, errors.WithStack(err))
+	}
+
+	return uint32(id), nil
+}
+
+// DeleteAttachment deletes a record specified by id.
+func (repo *attachmentRepository) DeleteAttachment(m repository.SQLManager, id uint32) error {
+	query := "DELETE FROM attachments WHERE id=?"
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return repo.ErrorMsg(model.RepositoryMethodDELETE, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	if _, err := stmt.ExecContext(repo.ctx, id); err != nil {
+		return repo.ErrorMsg(model.RepositoryMethodDELETE, errors.WithStack(err))
+	}
+
+	return nil
+}
+
+// count gets and returns the number of records counted by the query.
+func (repo *attachmentRepository) count(m repository.SQLManager, query string, args ...interface{}) (count int, err error) {
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return 0, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	if err := stmt.QueryRowContext(repo.ctx, args...).Scan(&count); err != nil {
+		return 0, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+
+	return count, nil
+}
+
+// list gets and returns list of records.
+func (repo *attachmentRepository) list(m repository.SQLManager, method model.RepositoryMethod, query string, args ...interface{}) (attachments []*model.Attachment, err error) {
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return nil, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	rows, err := stmt.QueryContext(repo.ctx, args...)
+	if err != nil {
+		return nil, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+	defer func() {
+		err = rows.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	list := make([]*model.Attachment, 0)
+	for rows.Next() {
+		attachment := &model.Attachment{}
+
+		err = rows.Scan(
+			&attachment.ID,
+			&attachment.CommentID,
+			&attachment.UserID,
+			&attachment.FileName,
+			&attachment.ContentType,
+			&attachment.Size,
+			&attachment.SHA256,
+			&attachment.CreatedAt,
+		)
+
+		if err != nil {
+			return nil, repo.ErrorMsg(method, errors.WithStack(err))
+		}
+
+		list = append(list, attachment)
+	}
+
+	return list, nil
+}
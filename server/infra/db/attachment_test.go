@@ -0,0 +1,77 @@
+package db
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/testutil"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func Test_attachmentRepository_ListAttachmentsByCommentIDs(t *testing.T) {
+	// set sqlmock
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Close()
+
+	createdAt := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	columns := []string{"id", "comment_id", "user_id", "file_name", "content_type", "size", "sha256", "created_at"}
+
+	tests := []struct {
+		name       string
+		commentIDs []uint32
+		want       []*model.Attachment
+	}{
+		{
+			name:       "When comment ids are given, lists attachments of them",
+			commentIDs: []uint32{1, 2},
+			want: []*model.Attachment{
+				{ID: 1, CommentID: 1, UserID: model.UserValidIDForTest, FileName: model.AttachmentFileNameForTest, ContentType: "image/png", Size: 10, SHA256: "abc", CreatedAt: createdAt},
+				{ID: 2, CommentID: 2, UserID: model.UserValidIDForTest, FileName: model.AttachmentFileNameForTest, ContentType: "image/png", Size: 10, SHA256: "abc", CreatedAt: createdAt},
+			},
+		},
+		{
+			name:       "When no comment id is given, returns empty list without query",
+			commentIDs: []uint32{},
+			want:       []*model.Attachment{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if len(tt.commentIDs) > 0 {
+				rows := sqlmock.NewRows(columns)
+				for _, a := range tt.want {
+					rows.AddRow(a.ID, a.CommentID, a.UserID, a.FileName, a.ContentType, a.Size, a.SHA256, a.CreatedAt)
+				}
+
+				query := "SELECT attachments.id, attachments.comment_id, attachments.user_id, attachments.file_name, " +
+					"attachments.content_type, attachments.size, attachments.sha256, attachments.created_at " +
+					"FROM attachments WHERE comment_id IN \\(\\?, \\?\\) ORDER BY comment_id, id"
+				mock.ExpectPrepare(query).ExpectQuery().
+					WithArgs(1, 2).
+					WillReturnRows(rows)
+			}
+
+			repo := &attachmentRepository{
+				ctx: context.Background(),
+			}
+
+			got, err := repo.ListAttachmentsByCommentIDs(db, tt.commentIDs)
+			if err != nil {
+				t.Fatalf("attachmentRepository.ListAttachmentsByCommentIDs() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				testutil.Errorf(t, tt.want, got)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,340 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+	log "github.com/sirupsen/logrus"
+)
+
+// commentRepository is repository of comment.
+type commentRepository struct {
+	ctx context.Context
+}
+
+// NewCommentRepository generates and returns CommentRepository.
+func NewCommentRepository(ctx context.Context) repository.CommentRepository {
+	return &commentRepository{
+		ctx: ctx,
+	}
+}
+
+// ErrorMsg generates and returns error message.
+func (repo *commentRepository) ErrorMsg(method model.RepositoryMethod, err error) error {
+	return &model.RepositoryError{
+		BaseErr:                     err,
+		RepositoryMethod:            method,
+		DomainModelNameForDeveloper: model.DomainModelNameCommentForDeveloper,
+		DomainModelNameForUser:      model.DomainModelNameCommentForUser,
+	}
+}
+
+// ListCommentsByThreadID gets and returns records of the thread which are not replies in order of posting, including deleted ones.
+// This returns empty list if the thread has no comment.
+func (repo *commentRepository) ListCommentsByThreadID(m repository.SQLManager, threadID uint32) ([]*model.Comment, error) {
+	query := "SELECT id, thread_id, user_id, parent_comment_id, depth, content, created_at, updated_at, deleted_at, deleted_by FROM comments WHERE thread_id=? AND parent_comment_id=0 ORDER BY id"
+
+	list, err := repo.list(m, model.RepositoryMethodREAD, query, threadID)
+	if err != nil {
+		return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+
+	return list, nil
+}
+
+// ListRepliesByParentID gets and returns at most limit records replying to the parent after afterID in order of posting.
+// This returns empty list if there is no more reply.
+func (repo *commentRepository) ListRepliesByParentID(m repository.SQLManager, parentID, afterID uint32, limit int) ([]*model.Comment, error) {
+	query := "SELECT id, thread_id, user_id, parent_comment_id, depth, content, created_at, updated_at, deleted_at, deleted_by FROM comments WHERE parent_comment_id=? AND id>? ORDER BY id LIMIT ?"
+
+	list, err := repo.list(m, model.RepositoryMethodREAD, query, parentID, afterID, limit)
+	if err != nil {
+		return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+
+	return list, nil
+}
+
+// GetReplySummariesByParentIDs counts replies to the parents and gets the latest one of each.
+// Parents which have no reply are not included, and this returns empty list if parentIDs is empty.
+func (repo *commentRepository) GetReplySummariesByParentIDs(m repository.SQLManager, parentIDs []uint32) (summaries []*model.ReplySummary, err error) {
+	if len(parentIDs) == 0 {
+		return make([]*model.ReplySummary, 0), nil
+	}
+
+	query := "SELECT s.parent_comment_id, s.reply_count, l.id, l.user_id, l.created_at FROM " +
+		"(SELECT parent_comment_id, COUNT(*) AS reply_count, MAX(id) AS latest_id FROM comments " +
+		"WHERE parent_comment_id IN (?" + strings.Repeat(", ?", len(parentIDs)-1) + ") GROUP BY parent_comment_id) s " +
+		"INNER JOIN comments l ON l.id=s.latest_id ORDER BY s.parent_comment_id"
+
+	args := make([]interface{}, 0, len(parentIDs))
+	for _, id := range parentIDs {
+		args = append(args, id)
+	}
+
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	rows, err := stmt.QueryContext(repo.ctx, args...)
+	if err != nil {
+		return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+	defer func() {
+		err = rows.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	list := make([]*model.ReplySummary, 0)
+	for rows.Next() {
+		summary := &model.ReplySummary{}
+
+		err = rows.Scan(
+			&summary.ParentCommentID,
+			&summary.ReplyCount,
+			&summary.LatestReplyID,
+			&summary.LatestReplyUserID,
+			&summary.LatestReplyAt,
+		)
+
+		if err != nil {
+			return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+		}
+
+		list = append(list, summary)
+	}
+
+	return list, nil
+}
+
+// GetCommentByID gets and returns a record specified by id.
+func (repo *commentRepository) GetCommentByID(m repository.SQLManager, id uint32) (*model.Comment, error) {
+	query := "SELECT id, thread_id, user_id, parent_comment_id, depth, content, created_at, updated_at, deleted_at, deleted_by FROM comments WHERE id=?"
+
+	list, err := repo.list(m, model.RepositoryMethodREAD, query, id)
+
+	if len(list) == 0 {
+		err = &model.NoSuchDataError{
+			BaseErr:                     err,
+			PropertyNameForDeveloper:    model.IDPropertyForDeveloper,
+			PropertyNameForUser:         model.IDPropertyForUser,
+			PropertyValue:               id,
+			DomainModelNameForDeveloper: model.DomainModelNameCommentForDeveloper,
+			DomainModelNameForUser:      model.DomainModelNameCommentForUser,
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	if err != nil {
+		return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+
+	return list[0], nil
+}
+
+// GetCommentsByIDs gets and returns records specified by ids in order of newest first.
+// Ids which do not exist are skipped, and this returns empty list if ids is empty.
+func (repo *commentRepository) GetCommentsByIDs(m repository.SQLManager, ids []uint32) ([]*model.Comment, error) {
+	if len(ids) == 0 {
+		return make([]*model.Comment, 0), nil
+	}
+
+	query := "SELECT id, thread_id, user_id, parent_comment_id, depth, content, created_at, updated_at, deleted_at, deleted_by FROM comments " +
+		"WHERE id IN (?" + strings.Repeat(", ?", len(ids)-1) + ") ORDER BY id DESC"
+
+	args := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		args = append(args, id)
+	}
+
+	list, err := repo.list(m, model.RepositoryMethodREAD, query, args...)
+	if err != nil {
+		return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+
+	return list, nil
+}
+
+// ListUndeletedCommentsAfterID gets and returns at most limit records which are not deleted whose ids are greater than afterID in order of id.
+// This returns empty list if there is no more record.
+func (repo *commentRepository) ListUndeletedCommentsAfterID(m repository.SQLManager, afterID uint32, limit int) ([]*model.Comment, error) {
+	query := "SELECT id, thread_id, user_id, parent_comment_id, depth, content, created_at, updated_at, deleted_at, deleted_by FROM comments WHERE id>? AND deleted_at IS NULL ORDER BY id LIMIT ?"
+
+	list, err := repo.list(m, model.RepositoryMethodREAD, query, afterID, limit)
+	if err != nil {
+		return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+
+	return list, nil
+}
+
+// list gets and returns list of records.
+func (repo *commentRepository) list(m repository.SQLManager, method model.RepositoryMethod, query string, args ...interface{}) (comments []*model.Comment, err error) {
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return nil, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	rows, err := stmt.QueryContext(repo.ctx, args...)
+	if err != nil {
+		return nil, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+	defer func() {
+		err = rows.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	list := make([]*model.Comment, 0)
+	for rows.Next() {
+		comment := &model.Comment{}
+		var deletedAt mysql.NullTime
+
+		err = rows.Scan(
+			&comment.ID,
+			&comment.ThreadID,
+			&comment.UserID,
+			&comment.ParentCommentID,
+			&comment.Depth,
+			&comment.Content,
+			&comment.CreatedAt,
+			&comment.UpdatedAt,
+			&deletedAt,
+			&comment.DeletedBy,
+		)
+
+		if err != nil {
+			return nil, repo.ErrorMsg(method, errors.WithStack(err))
+		}
+
+		if deletedAt.Valid {
+			comment.DeletedAt = deletedAt.Time
+		}
+
+		list = append(list, comment)
+	}
+
+	return list, nil
+}
+
+// InsertComment insert a record and returns its id.
+func (repo *commentRepository) InsertComment(m repository.SQLManager, comment *model.Comment) (uint32, error) {
+	query := "INSERT INTO comments (thread_id, user_id, parent_comment_id, depth, content, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?)"
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return model.InvalidID, repo.ErrorMsg(model.RepositoryMethodInsert, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	result, err := stmt.ExecContext(repo.ctx, comment.ThreadID, comment.UserID, comment.ParentCommentID, comment.Depth, comment.Content, comment.CreatedAt, comment.UpdatedAt)
+	if err != nil {
+		return model.InvalidID, repo.ErrorMsg(model.RepositoryMethodInsert, errors.WithStack(err))
+	}
+
+	affect, err := result.RowsAffected()
+	if affect != 1 {
+		err = fmt.Errorf("total affected: %d ", affect)
+		return model.InvalidID, repo.ErrorMsg(model.RepositoryMethodInsert, errors.WithStack(err))
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return model.InvalidID, repo.ErrorMsg(model.RepositoryMethodInsert, errors.WithStack(err))
+	}
+
+	return uint32(id), nil
+}
+
+// UpdateCommentContent updates the content of the record specified by id.
+func (repo *commentRepository) UpdateCommentContent(m repository.SQLManager, id uint32, content string, updatedAt time.Time) error {
+	query := "UPDATE comments SET content=?, updated_at=? WHERE id=?"
+
+	_, err := repo.exec(m, model.RepositoryMethodUPDATE, query, content, updatedAt, id)
+	return err
+}
+
+// SoftDeleteComment marks the record as deleted and keeps the content.
+// This returns NoSuchDataError if the record is already deleted, so that the first deletion is kept.
+func (repo *commentRepository) SoftDeleteComment(m repository.SQLManager, id, deletedBy uint32, deletedAt time.Time) error {
+	query := "UPDATE comments SET deleted_at=?, deleted_by=? WHERE id=? AND deleted_at IS NULL"
+
+	affect, err := repo.exec(m, model.RepositoryMethodUPDATE, query, deletedAt, deletedBy, id)
+	if err != nil {
+		return err
+	}
+
+	if affect == 0 {
+		err := &model.NoSuchDataError{
+			PropertyNameForDeveloper:    model.IDPropertyForDeveloper,
+			PropertyNameForUser:         model.IDPropertyForUser,
+			PropertyValue:               id,
+			DomainModelNameForDeveloper: model.DomainModelNameCommentForDeveloper,
+			DomainModelNameForUser:      model.DomainModelNameCommentForUser,
+		}
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+// PurgeDeletedComments clears the content of records deleted before the time.
+// Records are kept as tombstones, and the number of newly purged ones is returned.
+func (repo *commentRepository) PurgeDeletedComments(m repository.SQLManager, deletedBefore time.Time) (int64, error) {
+	query := "UPDATE comments SET content='' WHERE deleted_at<? AND content<>''"
+
+	return repo.exec(m, model.RepositoryMethodUPDATE, query, deletedBefore)
+}
+
+// exec executes the query and returns the number of affected rows.
+func (repo *commentRepository) exec(m repository.SQLManager, method model.RepositoryMethod, query string, args ...interface{}) (int64, error) {
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return 0, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	result, err := stmt.ExecContext(repo.ctx, args...)
+	if err != nil {
+		return 0, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+
+	affect, err := result.RowsAffected()
+	if err != nil {
+		return 0, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+
+	return affect, nil
+}
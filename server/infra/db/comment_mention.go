@@ -0,0 +1,135 @@
+package db
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+	log "github.com/sirupsen/logrus"
+)
+
+// commentMentionRepository is repository of users mentioned by comments.
+type commentMentionRepository struct {
+	ctx context.Context
+}
+
+// NewCommentMentionRepository generates and returns CommentMentionRepository.
+func NewCommentMentionRepository(ctx context.Context) repository.CommentMentionRepository {
+	return &commentMentionRepository{
+		ctx: ctx,
+	}
+}
+
+// ErrorMsg generates and returns error message.
+func (repo *commentMentionRepository) ErrorMsg(method model.RepositoryMethod, err error) error {
+	return &model.RepositoryError{
+		BaseErr:                     err,
+		RepositoryMethod:            method,
+		DomainModelNameForDeveloper: model.DomainModelNameCommentMentionForDeveloper,
+		DomainModelNameForUser:      model.DomainModelNameCommentMentionForUser,
+	}
+}
+
+// GetMentionedUserIDsByCommentID returns ids of users mentioned by the comment.
+func (repo *commentMentionRepository) GetMentionedUserIDsByCommentID(m repository.SQLManager, commentID uint32) (ids []uint32, err error) {
+	query := "SELECT user_id FROM comment_mentions WHERE comment_id=? ORDER BY user_id"
+
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	rows, err := stmt.QueryContext(repo.ctx, commentID)
+	if err != nil {
+		return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+	defer func() {
+		err = rows.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	ids = make([]uint32, 0)
+	for rows.Next() {
+		var id uint32
+		if err := rows.Scan(&id); err != nil {
+			return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// InsertCommentMentions inserts records by one query.
+// This does nothing if mentions is empty, and ignores the mention which already exists.
+func (repo *commentMentionRepository) InsertCommentMentions(m repository.SQLManager, mentions []*model.CommentMention) error {
+	if len(mentions) == 0 {
+		return nil
+	}
+
+	query := "INSERT IGNORE INTO comment_mentions (comment_id, user_id, created_at) VALUES (?, ?, ?)" +
+		strings.Repeat(", (?, ?, ?)", len(mentions)-1)
+
+	args := make([]interface{}, 0, len(mentions)*3)
+	for _, mention := range mentions {
+		args = append(args, mention.CommentID, mention.UserID, mention.CreatedAt)
+	}
+
+	return repo.exec(m, model.RepositoryMethodInsert, query, args...)
+}
+
+// DeleteCommentMentions deletes records of the users mentioned by the comment by one query.
+// This does nothing if userIDs is empty.
+func (repo *commentMentionRepository) DeleteCommentMentions(m repository.SQLManager, commentID uint32, userIDs []uint32) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	query := "DELETE FROM comment_mentions WHERE comment_id=? AND user_id IN (?" + strings.Repeat(", ?", len(userIDs)-1) + ")"
+
+	args := make([]interface{}, 0, len(userIDs)+1)
+	args = append(args, commentID)
+	for _, userID := range userIDs {
+		args = append(args, userID)
+	}
+
+	return repo.exec(m, model.RepositoryMethodDELETE, query, args...)
+}
+
+// DeleteCommentMentionsByUserID deletes all records mentioning the user.
+func (repo *commentMentionRepository) DeleteCommentMentionsByUserID(m repository.SQLManager, userID uint32) error {
+	query := "DELETE FROM comment_mentions WHERE user_id=?"
+
+	return repo.exec(m, model.RepositoryMethodDELETE, query, userID)
+}
+
+// exec executes the query.
+func (repo *commentMentionRepository) exec(m repository.SQLManager, method model.RepositoryMethod, query string, args ...interface{}) error {
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return repo.ErrorMsg(method, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	if _, err := stmt.ExecContext(repo.ctx, args...); err != nil {
+		return repo.ErrorMsg(method, errors.WithStack(err))
+	}
+
+	return nil
+}
@@ -0,0 +1,145 @@
+package db
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+	log "github.com/sirupsen/logrus"
+)
+
+// commentReactionRepository is repository of reactions to comments.
+type commentReactionRepository struct {
+	ctx context.Context
+}
+
+// NewCommentReactionRepository generates and returns CommentReactionRepository.
+func NewCommentReactionRepository(ctx context.Context) repository.CommentReactionRepository {
+	return &commentReactionRepository{
+		ctx: ctx,
+	}
+}
+
+// ErrorMsg generates and returns error message.
+func (repo *commentReactionRepository) ErrorMsg(method model.RepositoryMethod, err error) error {
+	return &model.RepositoryError{
+		BaseErr:                     err,
+		RepositoryMethod:            method,
+		DomainModelNameForDeveloper: model.DomainModelNameCommentReactionForDeveloper,
+		DomainModelNameForUser:      model.DomainModelNameCommentReactionForUser,
+	}
+}
+
+// GetReactionCountsByCommentIDs counts reactions to the comments for each emoji,
+// in order of comment id and then of the first reaction with the emoji.
+// This returns empty list if commentIDs is empty.
+func (repo *commentReactionRepository) GetReactionCountsByCommentIDs(m repository.SQLManager, userID uint32, commentIDs []uint32) (counts []*model.ReactionCount, err error) {
+	if len(commentIDs) == 0 {
+		return make([]*model.ReactionCount, 0), nil
+	}
+
+	query := "SELECT comment_id, emoji, COUNT(*), MAX(user_id=?) FROM comment_reactions " +
+		"WHERE comment_id IN (?" + strings.Repeat(", ?", len(commentIDs)-1) + ") " +
+		"GROUP BY comment_id, emoji ORDER BY comment_id, MIN(created_at), emoji"
+
+	args := make([]interface{}, 0, len(commentIDs)+1)
+	args = append(args, userID)
+	for _, id := range commentIDs {
+		args = append(args, id)
+	}
+
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	rows, err := stmt.QueryContext(repo.ctx, args...)
+	if err != nil {
+		return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+	defer func() {
+		err = rows.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	list := make([]*model.ReactionCount, 0)
+	for rows.Next() {
+		count := &model.ReactionCount{}
+
+		err = rows.Scan(
+			&count.CommentID,
+			&count.Emoji,
+			&count.Count,
+			&count.ReactedByMe,
+		)
+
+		if err != nil {
+			return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+		}
+
+		list = append(list, count)
+	}
+
+	return list, nil
+}
+
+// InsertCommentReaction insert a record.
+// This does nothing if the user already reacts to the comment with the emoji, so that reacting is idempotent.
+func (repo *commentReactionRepository) InsertCommentReaction(m repository.SQLManager, reaction *model.CommentReaction) error {
+	query := "INSERT IGNORE INTO comment_reactions (comment_id, user_id, emoji, created_at) VALUES (?, ?, ?, ?)"
+
+	return repo.exec(m, model.RepositoryMethodInsert, query, reaction.CommentID, reaction.UserID, reaction.Emoji, reaction.CreatedAt)
+}
+
+// DeleteCommentReaction deletes the reaction of the user to the comment with the emoji.
+// This does not return NoSuchDataError, so that removing reactions is idempotent.
+func (repo *commentReactionRepository) DeleteCommentReaction(m repository.SQLManager, commentID, userID uint32, emoji string) error {
+	query := "DELETE FROM comment_reactions WHERE comment_id=? AND user_id=? AND emoji=?"
+
+	return repo.exec(m, model.RepositoryMethodDELETE, query, commentID, userID, emoji)
+}
+
+// DeleteCommentReactionsByCommentID deletes all reactions to the comment.
+func (repo *commentReactionRepository) DeleteCommentReactionsByCommentID(m repository.SQLManager, commentID uint32) error {
+	query := "DELETE FROM comment_reactions WHERE comment_id=?"
+
+	return repo.exec(m, model.RepositoryMethodDELETE, query, commentID)
+}
+
+// DeleteCommentReactionsByUserID deletes all reactions of the user.
+func (repo *commentReactionRepository) DeleteCommentReactionsByUserID(m repository.SQLManager, userID uint32) error {
+	query := "DELETE FROM comment_reactions WHERE user_id=?"
+
+	return repo.exec(m, model.RepositoryMethodDELETE, query, userID)
+}
+
+// exec executes the query.
+func (repo *commentReactionRepository) exec(m repository.SQLManager, method model.RepositoryMethod, query string, args ...interface{}) error {
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return repo.ErrorMsg(method, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	if _, err := stmt.ExecContext(repo.ctx, args...); err != nil {
+		return repo.ErrorMsg(method, errors.WithStack(err))
+	}
+
+	return nil
+}
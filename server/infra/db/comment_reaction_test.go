@@ -0,0 +1,74 @@
+package db
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/testutil"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func Test_commentReactionRepository_GetReactionCountsByCommentIDs(t *testing.T) {
+	// set sqlmock
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Close()
+
+	tests := []struct {
+		name       string
+		commentIDs []uint32
+		want       []*model.ReactionCount
+	}{
+		{
+			name:       "When comments are given, counts reactions to all of them by one query",
+			commentIDs: []uint32{1, 2, 3},
+			want: []*model.ReactionCount{
+				{CommentID: 1, Emoji: model.EmojiForTest, Count: 2, ReactedByMe: true},
+				{CommentID: 1, Emoji: "🎉", Count: 1, ReactedByMe: false},
+				{CommentID: 3, Emoji: model.EmojiForTest, Count: 1, ReactedByMe: false},
+			},
+		},
+		{
+			name:       "When no comment is given, returns empty list without query",
+			commentIDs: []uint32{},
+			want:       []*model.ReactionCount{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if len(tt.commentIDs) > 0 {
+				rows := sqlmock.NewRows([]string{"comment_id", "emoji", "count", "max"})
+				for _, c := range tt.want {
+					rows.AddRow(c.CommentID, c.Emoji, c.Count, c.ReactedByMe)
+				}
+
+				query := "SELECT comment_id, emoji, COUNT\\(\\*\\), MAX\\(user_id=\\?\\) FROM comment_reactions " +
+					"WHERE comment_id IN \\(\\?, \\?, \\?\\) " +
+					"GROUP BY comment_id, emoji ORDER BY comment_id, MIN\\(created_at\\), emoji"
+				mock.ExpectPrepare(query).ExpectQuery().
+					WithArgs(model.UserValidIDForTest, 1, 2, 3).
+					WillReturnRows(rows)
+			}
+
+			repo := &commentReactionRepository{
+				ctx: context.Background(),
+			}
+
+			got, err := repo.GetReactionCountsByCommentIDs(db, model.UserValidIDForTest, tt.commentIDs)
+			if err != nil {
+				t.Fatalf("commentReactionRepository.GetReactionCountsByCommentIDs() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				testutil.Errorf(t, tt.want, got)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+	log "github.com/sirupsen/logrus"
+)
+
+// commentRevisionRepository is repository of edit history of comments.
+type commentRevisionRepository struct {
+	ctx context.Context
+}
+
+// NewCommentRevisionRepository generates and returns CommentRevisionRepository.
+func NewCommentRevisionRepository(ctx context.Context) repository.CommentRevisionRepository {
+	return &commentRevisionRepository{
+		ctx: ctx,
+	}
+}
+
+// ErrorMsg generates and returns error message.
+func (repo *commentRevisionRepository) ErrorMsg(method model.RepositoryMethod, err error) error {
+	return &model.RepositoryError{
+		BaseErr:                     err,
+		RepositoryMethod:            method,
+		DomainModelNameForDeveloper: model.DomainModelNameCommentRevisionForDeveloper,
+		DomainModelNameForUser:      model.DomainModelNameCommentRevisionForUser,
+	}
+}
+
+// ListCommentRevisionsByCommentID gets and returns records of the comment in order of editing.
+// This returns empty list if the comment has never been edited.
+func (repo *commentRevisionRepository) ListCommentRevisionsByCommentID(m repository.SQLManager, commentID uint32) (revisions []*model.CommentRevision, err error) {
+	query := "SELECT id, comment_id, editor_id, content, created_at FROM comment_revisions WHERE comment_id=? ORDER BY id"
+
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	rows, err := stmt.QueryContext(repo.ctx, commentID)
+	if err != nil {
+		return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+	defer func() {
+		err = rows.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	list := make([]*model.CommentRevision, 0)
+	for rows.Next() {
+		revision := &model.CommentRevision{}
+
+		err = rows.Scan(
+			&revision.ID,
+			&revision.CommentID,
+			&revision.EditorID,
+			&revision.Content,
+			&revision.CreatedAt,
+		)
+
+		if err != nil {
+			return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+		}
+
+		list = append(list, revision)
+	}
+
+	return list, nil
+}
+
+// InsertCommentRevision insert a record and returns its id.
+func (repo *commentRevisionRepository) InsertCommentRevision(m repository.SQLManager, revision *model.CommentRevision) (uint32, error) {
+	query := "INSERT INTO comment_revisions (comment_id, editor_id, content, created_at) VALUES (?, ?, ?, ?)"
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return model.InvalidID, repo.ErrorMsg(model.RepositoryMethodInsert, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	result, err := stmt.ExecContext(repo.ctx, revision.CommentID, revision.EditorID, revision.Content, revision.CreatedAt)
+	if err != nil {
+		return model.InvalidID, repo.ErrorMsg(model.RepositoryMethodInsert, errors.WithStack(err))
+	}
+
+	affect, err := result.RowsAffected()
+	if affect != 1 {
+		err = fmt.Errorf("total affected: %d ", affect)
+		return model.InvalidID, repo.ErrorMsg(model.RepositoryMethodInsert, errors.WithStack(err))
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return model.InvalidID, repo.ErrorMsg(model.RepositoryMethodInsert, errors.WithStack(err))
+	}
+
+	return uint32(id), nil
+}
+
+// DeleteRevisionsOfDeletedComments deletes records of comments deleted before the time.
+func (repo *commentRevisionRepository) DeleteRevisionsOfDeletedComments(m repository.SQLManager, deletedBefore time.Time) error {
+	query := "DELETE r FROM comment_revisions r INNER JOIN comments c ON c.id=r.comment_id WHERE c.deleted_at<?"
+
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return repo.ErrorMsg(model.RepositoryMethodDELETE, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	if _, err := stmt.ExecContext(repo.ctx, deletedBefore); err != nil {
+		return repo.ErrorMsg(model.RepositoryMethodDELETE, errors.WithStack(err))
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package db
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/testutil"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func Test_commentRepository_GetReplySummariesByParentIDs(t *testing.T) {
+	// set sqlmock
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Close()
+
+	tests := []struct {
+		name      string
+		parentIDs []uint32
+		want      []*model.ReplySummary
+	}{
+		{
+			name:      "When parents are given, summarizes replies to all of them by one query",
+			parentIDs: []uint32{1, 2, 3},
+			want: []*model.ReplySummary{
+				{ParentCommentID: 1, ReplyCount: 2, LatestReplyID: 12, LatestReplyUserID: model.UserValidIDForTest, LatestReplyAt: testutil.TimeNow()},
+				{ParentCommentID: 3, ReplyCount: 1, LatestReplyID: 31, LatestReplyUserID: model.UserInValidIDForTest, LatestReplyAt: testutil.TimeNow()},
+			},
+		},
+		{
+			name:      "When no parent is given, returns empty list without query",
+			parentIDs: []uint32{},
+			want:      []*model.ReplySummary{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if len(tt.parentIDs) > 0 {
+				rows := sqlmock.NewRows([]string{"parent_comment_id", "reply_count", "id", "user_id", "created_at"})
+				for _, s := range tt.want {
+					rows.AddRow(s.ParentCommentID, s.ReplyCount, s.LatestReplyID, s.LatestReplyUserID, s.LatestReplyAt)
+				}
+
+				query := "SELECT s.parent_comment_id, s.reply_count, l.id, l.user_id, l.created_at FROM " +
+					"\\(SELECT parent_comment_id, COUNT\\(\\*\\) AS reply_count, MAX\\(id\\) AS latest_id FROM comments " +
+					"WHERE parent_comment_id IN \\(\\?, \\?, \\?\\) GROUP BY parent_comment_id\\) s " +
+					"INNER JOIN comments l ON l.id=s.latest_id ORDER BY s.parent_comment_id"
+				mock.ExpectPrepare(query).ExpectQuery().
+					WithArgs(1, 2, 3).
+					WillReturnRows(rows)
+			}
+
+			repo := &commentRepository{
+				ctx: context.Background(),
+			}
+
+			got, err := repo.GetReplySummariesByParentIDs(db, tt.parentIDs)
+			if err != nil {
+				t.Fatalf("commentRepository.GetReplySummariesByParentIDs() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				testutil.Errorf(t, tt.want, got)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -16,16 +16,29 @@ type dbManager struct {
 	Conn *sql.DB
 }
 
+// DefaultDSN is the data source name of the database in the docker compose environment.
+const DefaultDSN = "root@tcp(nvgdb:3306)/nuxt-go-chat-app?charset=utf8mb4&parseTime=True"
+
 // NewDBManager generates and returns SQLManager.
 func NewDBManager() repository.DBManager {
-	conn, err := sql.Open("mysql", "root@tcp(nvgdb:3306)/nuxt-go-chat-app?charset=utf8mb4&parseTime=True")
+	m, err := NewDBManagerWithDSN(DefaultDSN)
 	if err != nil {
 		panic(err.Error())
 	}
 
+	return m
+}
+
+// NewDBManagerWithDSN generates and returns SQLManager connected to the data source name.
+func NewDBManagerWithDSN(dsn string) (repository.DBManager, error) {
+	conn, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return nil, err
+	}
+
 	return &dbManager{
 		Conn: conn,
-	}
+	}, nil
 }
 
 // Exec executes SQL.
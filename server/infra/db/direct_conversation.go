@@ -0,0 +1,189 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+	log "github.com/sirupsen/logrus"
+)
+
+// directConversationRepository is repository of direct conversations.
+type directConversationRepository struct {
+	ctx context.Context
+}
+
+// NewDirectConversationRepository generates and returns DirectConversationRepository.
+func NewDirectConversationRepository(ctx context.Context) repository.DirectConversationRepository {
+	return &directConversationRepository{
+		ctx: ctx,
+	}
+}
+
+// ErrorMsg generates and returns error message.
+func (repo *directConversationRepository) ErrorMsg(method model.RepositoryMethod, err error) error {
+	return &model.RepositoryError{
+		BaseErr:                     err,
+		RepositoryMethod:            method,
+		DomainModelNameForDeveloper: model.DomainModelNameDirectConversationForDeveloper,
+		DomainModelNameForUser:      model.DomainModelNameDirectConversationForUser,
+	}
+}
+
+// GetDirectConversationByUserIDs gets and returns a record of the pair of users.
+func (repo *directConversationRepository) GetDirectConversationByUserIDs(m repository.SQLManager, userID, otherUserID uint32) (*model.DirectConversation, error) {
+	query := "SELECT thread_id, user_id1, user_id2, last_message_at, created_at FROM direct_conversations WHERE user_id1=? AND user_id2=?"
+
+	userID1, userID2 := model.DirectConversationUserIDs(userID, otherUserID)
+	list, err := repo.list(m, model.RepositoryMethodREAD, query, userID1, userID2)
+
+	if len(list) == 0 {
+		err = &model.NoSuchDataError{
+			BaseErr:                     err,
+			PropertyNameForDeveloper:    model.UserIDPropertyForDeveloper,
+			PropertyNameForUser:         model.UserIDPropertyForUser,
+			PropertyValue:               otherUserID,
+			DomainModelNameForDeveloper: model.DomainModelNameDirectConversationForDeveloper,
+			DomainModelNameForUser:      model.DomainModelNameDirectConversationForUser,
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	if err != nil {
+		return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+
+	return list[0], nil
+}
+
+// GetDirectConversationByThreadID gets and returns a record specified by id of the thread.
+func (repo *directConversationRepository) GetDirectConversationByThreadID(m repository.SQLManager, threadID uint32) (*model.DirectConversation, error) {
+	query := "SELECT thread_id, user_id1, user_id2, last_message_at, created_at FROM direct_conversations WHERE thread_id=?"
+
+	list, err := repo.list(m, model.RepositoryMethodREAD, query, threadID)
+
+	if len(list) == 0 {
+		err = &model.NoSuchDataError{
+			BaseErr:                     err,
+			PropertyNameForDeveloper:    model.IDPropertyForDeveloper,
+			PropertyNameForUser:         model.IDPropertyForUser,
+			PropertyValue:               threadID,
+			DomainModelNameForDeveloper: model.DomainModelNameDirectConversationForDeveloper,
+			DomainModelNameForUser:      model.DomainModelNameDirectConversationForUser,
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	if err != nil {
+		return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+
+	return list[0], nil
+}
+
+// ListDirectConversationsByUserID gets and returns records of the user in order of last activity, the newest first.
+// This returns empty list if the user has no conversation.
+func (repo *directConversationRepository) ListDirectConversationsByUserID(m repository.SQLManager, userID uint32) ([]*model.DirectConversation, error) {
+	query := "SELECT thread_id, user_id1, user_id2, last_message_at, created_at FROM direct_conversations " +
+		"WHERE user_id1=? OR user_id2=? ORDER BY last_message_at DESC, thread_id DESC"
+
+	list, err := repo.list(m, model.RepositoryMethodREAD, query, userID, userID)
+	if err != nil {
+		return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+
+	return list, nil
+}
+
+// list gets and returns list of records.
+func (repo *directConversationRepository) list(m repository.SQLManager, method model.RepositoryMethod, query string, args ...interface{}) (conversations []*model.DirectConversation, err error) {
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return nil, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	rows, err := stmt.QueryContext(repo.ctx, args...)
+	if err != nil {
+		return nil, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+	defer func() {
+		err = rows.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	list := make([]*model.DirectConversation, 0)
+	for rows.Next() {
+		conversation := &model.DirectConversation{}
+
+		err = rows.Scan(
+			&conversation.ThreadID,
+			&conversation.UserID1,
+			&conversation.UserID2,
+			&conversation.LastMessageAt,
+			&conversation.CreatedAt,
+		)
+
+		if err != nil {
+			return nil, repo.ErrorMsg(method, errors.WithStack(err))
+		}
+
+		list = append(list, conversation)
+	}
+
+	return list, nil
+}
+
+// InsertDirectConversation insert a record.
+// This fails if the pair of users already has a conversation because the pair is the primary key.
+func (repo *directConversationRepository) InsertDirectConversation(m repository.SQLManager, conversation *model.DirectConversation) error {
+	query := "INSERT INTO direct_conversations (user_id1, user_id2, thread_id, last_message_at, created_at) VALUES (?, ?, ?, ?, ?)"
+
+	_, err := repo.exec(m, model.RepositoryMethodInsert, query,
+		conversation.UserID1, conversation.UserID2, conversation.ThreadID, conversation.LastMessageAt, conversation.CreatedAt)
+	return err
+}
+
+// UpdateLastMessageAt updates the time of the last message of a record.
+func (repo *directConversationRepository) UpdateLastMessageAt(m repository.SQLManager, threadID uint32, at time.Time) error {
+	query := "UPDATE direct_conversations SET last_message_at=? WHERE thread_id=?"
+
+	_, err := repo.exec(m, model.RepositoryMethodUPDATE, query, at, threadID)
+	return err
+}
+
+// exec executes the query and returns the number of affected rows.
+func (repo *directConversationRepository) exec(m repository.SQLManager, method model.RepositoryMethod, query string, args ...interface{}) (int64, error) {
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return 0, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	result, err := stmt.ExecContext(repo.ctx, args...)
+	if err != nil {
+		return 0, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+
+	affect, err := result.RowsAffected()
+	if err != nil {
+		return 0, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+
+	return affect, nil
+}
@@ -0,0 +1,159 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+	log "github.com/sirupsen/logrus"
+)
+
+// identityRepository is repository of identity of external provider.
+type identityRepository struct {
+	ctx context.Context
+}
+
+// NewIdentityRepository generates and returns identityRepository.
+func NewIdentityRepository(ctx context.Context) repository.IdentityRepository {
+	return &identityRepository{
+		ctx: ctx,
+	}
+}
+
+// ErrorMsg generates and returns error message.
+func (repo *identityRepository) ErrorMsg(method model.RepositoryMethod, err error) error {
+	return &model.RepositoryError{
+		BaseErr:                     err,
+		RepositoryMethod:            method,
+		DomainModelNameForDeveloper: model.DomainModelNameIdentityForDeveloper,
+		DomainModelNameForUser:      model.DomainModelNameIdentityForUser,
+	}
+}
+
+// GetIdentity gets and returns a record specified by issuer and subject.
+func (repo *identityRepository) GetIdentity(m repository.SQLManager, issuer, subject string) (*model.Identity, error) {
+	query := "SELECT id, user_id, issuer, subject, created_at FROM user_identities WHERE issuer=? AND subject=?"
+
+	list, err := repo.list(m, model.RepositoryMethodREAD, query, issuer, subject)
+
+	if len(list) == 0 {
+		err = &model.NoSuchDataError{
+			BaseErr:                     err,
+			PropertyNameForDeveloper:    model.SubjectPropertyForDeveloper,
+			PropertyNameForUser:         model.SubjectPropertyForUser,
+			PropertyValue:               subject,
+			DomainModelNameForDeveloper: model.DomainModelNameIdentityForDeveloper,
+			DomainModelNameForUser:      model.DomainModelNameIdentityForUser,
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	if err != nil {
+		return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+
+	return list[0], nil
+}
+
+// list gets and returns list of records.
+func (repo *identityRepository) list(m repository.SQLManager, method model.RepositoryMethod, query string, args ...interface{}) (identities []*model.Identity, err error) {
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return nil, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	rows, err := stmt.QueryContext(repo.ctx, args...)
+	if err != nil {
+		return nil, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+	defer func() {
+		err = rows.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	list := make([]*model.Identity, 0)
+	for rows.Next() {
+		identity := &model.Identity{}
+
+		err = rows.Scan(
+			&identity.ID,
+			&identity.UserID,
+			&identity.Issuer,
+			&identity.Subject,
+			&identity.CreatedAt,
+		)
+
+		if err != nil {
+			return nil, repo.ErrorMsg(method, errors.WithStack(err))
+		}
+
+		list = append(list, identity)
+	}
+
+	return list, nil
+}
+
+// InsertIdentity insert a record and returns its id.
+func (repo *identityRepository) InsertIdentity(m repository.SQLManager, identity *model.Identity) (uint32, error) {
+	query := "INSERT INTO user_identities (user_id, issuer, subject, created_at) VALUES (?, ?, ?, ?)"
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return model.InvalidID, repo.ErrorMsg(model.RepositoryMethodInsert, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	result, err := stmt.ExecContext(repo.ctx, identity.UserID, identity.Issuer, identity.Subject, identity.CreatedAt)
+	if err != nil {
+		return model.InvalidID, repo.ErrorMsg(model.RepositoryMethodInsert, errors.WithStack(err))
+	}
+
+	affect, err := result.RowsAffected()
+	if affect != 1 {
+		err = fmt.Errorf("total affected: %d ", affect)
+		return model.InvalidID, repo.ErrorMsg(model.RepositoryMethodInsert, errors.WithStack(err))
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return model.InvalidID, repo.ErrorMsg(model.RepositoryMethodInsert, errors.WithStack(err))
+	}
+
+	return uint32(id), nil
+}
+
+// DeleteIdentitiesByUserID deletes all records of the user.
+func (repo *identityRepository) DeleteIdentitiesByUserID(m repository.SQLManager, userID uint32) error {
+	query := "DELETE FROM user_identities WHERE user_id=?"
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return repo.ErrorMsg(model.RepositoryMethodDELETE, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	if _, err := stmt.ExecContext(repo.ctx, userID); err != nil {
+		return repo.ErrorMsg(model.RepositoryMethodDELETE, errors.WithStack(err))
+	}
+
+	return nil
+}
@@ -0,0 +1,213 @@
+package db
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+	log "github.com/sirupsen/logrus"
+)
+
+// notificationRepository is repository of notifications in the inbox of each user.
+type notificationRepository struct {
+	ctx context.Context
+}
+
+// NewNotificationRepository generates and returns NotificationRepository.
+func NewNotificationRepository(ctx context.Context) repository.NotificationRepository {
+	return &notificationRepository{
+		ctx: ctx,
+	}
+}
+
+// ErrorMsg generates and returns error message.
+func (repo *notificationRepository) ErrorMsg(method model.RepositoryMethod, err error) error {
+	return &model.RepositoryError{
+		BaseErr:                     err,
+		RepositoryMethod:            method,
+		DomainModelNameForDeveloper: model.DomainModelNameNotificationForDeveloper,
+		DomainModelNameForUser:      model.DomainModelNameNotificationForUser,
+	}
+}
+
+// ListNotificationsByUserID gets and returns at most limit records of the user whose ids are less than beforeID in order of newest first.
+// All records are targeted if beforeID is 0, and this returns empty list if there is no more record.
+func (repo *notificationRepository) ListNotificationsByUserID(m repository.SQLManager, userID, beforeID uint32, limit int) ([]*model.Notification, error) {
+	query := "SELECT id, user_id, kind, thread_id, comment_id, actor_id, created_at, read_at FROM notifications WHERE user_id=? ORDER BY id DESC LIMIT ?"
+	args := []interface{}{userID, limit}
+	if beforeID != 0 {
+		query = "SELECT id, user_id, kind, thread_id, comment_id, actor_id, created_at, read_at FROM notifications WHERE user_id=? AND id<? ORDER BY id DESC LIMIT ?"
+		args = []interface{}{userID, beforeID, limit}
+	}
+
+	list, err := repo.list(m, model.RepositoryMethodREAD, query, args...)
+	if err != nil {
+		return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+
+	return list, nil
+}
+
+// CountUnreadNotificationsByUserID counts records of the user which are not read.
+func (repo *notificationRepository) CountUnreadNotificationsByUserID(m repository.SQLManager, userID uint32) (count uint32, err error) {
+	query := "SELECT COUNT(*) FROM notifications WHERE user_id=? AND read_at IS NULL"
+
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return 0, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	if err := stmt.QueryRowContext(repo.ctx, userID).Scan(&count); err != nil {
+		return 0, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+
+	return count, nil
+}
+
+// GetNotificationByID gets and returns a record specified by id.
+func (repo *notificationRepository) GetNotificationByID(m repository.SQLManager, id uint32) (*model.Notification, error) {
+	query := "SELECT id, user_id, kind, thread_id, comment_id, actor_id, created_at, read_at FROM notifications WHERE id=?"
+
+	list, err := repo.list(m, model.RepositoryMethodREAD, query, id)
+
+	if len(list) == 0 {
+		err = &model.NoSuchDataError{
+			BaseErr:                     err,
+			PropertyNameForDeveloper:    model.IDPropertyForDeveloper,
+			PropertyNameForUser:         model.IDPropertyForUser,
+			PropertyValue:               id,
+			DomainModelNameForDeveloper: model.DomainModelNameNotificationForDeveloper,
+			DomainModelNameForUser:      model.DomainModelNameNotificationForUser,
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	if err != nil {
+		return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+
+	return list[0], nil
+}
+
+// list gets and returns list of records.
+func (repo *notificationRepository) list(m repository.SQLManager, method model.RepositoryMethod, query string, args ...interface{}) (notifications []*model.Notification, err error) {
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return nil, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	rows, err := stmt.QueryContext(repo.ctx, args...)
+	if err != nil {
+		return nil, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+	defer func() {
+		err = rows.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	list := make([]*model.Notification, 0)
+	for rows.Next() {
+		notification := &model.Notification{}
+		var readAt mysql.NullTime
+
+		err = rows.Scan(
+			&notification.ID,
+			&notification.UserID,
+			&notification.Kind,
+			&notification.ThreadID,
+			&notification.CommentID,
+			&notification.ActorID,
+			&notification.CreatedAt,
+			&readAt,
+		)
+
+		if err != nil {
+			return nil, repo.ErrorMsg(method, errors.WithStack(err))
+		}
+
+		if readAt.Valid {
+			notification.ReadAt = readAt.Time
+		}
+
+		list = append(list, notification)
+	}
+
+	return list, nil
+}
+
+// InsertNotifications inserts records by one query, and does nothing if notifications is empty.
+func (repo *notificationRepository) InsertNotifications(m repository.SQLManager, notifications []*model.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	query := "INSERT INTO notifications (user_id, kind, thread_id, comment_id, actor_id, created_at) VALUES (?, ?, ?, ?, ?, ?)" +
+		strings.Repeat(", (?, ?, ?, ?, ?, ?)", len(notifications)-1)
+
+	args := make([]interface{}, 0, len(notifications)*6)
+	for _, n := range notifications {
+		args = append(args, n.UserID, n.Kind, n.ThreadID, n.CommentID, n.ActorID, n.CreatedAt)
+	}
+
+	return repo.exec(m, model.RepositoryMethodInsert, query, args...)
+}
+
+// MarkNotificationRead sets the time read to the record specified by id if it is not read yet.
+func (repo *notificationRepository) MarkNotificationRead(m repository.SQLManager, id uint32, readAt time.Time) error {
+	query := "UPDATE notifications SET read_at=? WHERE id=? AND read_at IS NULL"
+
+	return repo.exec(m, model.RepositoryMethodUPDATE, query, readAt, id)
+}
+
+// MarkAllNotificationsRead sets the time read to all records of the user which are not read yet.
+func (repo *notificationRepository) MarkAllNotificationsRead(m repository.SQLManager, userID uint32, readAt time.Time) error {
+	query := "UPDATE notifications SET read_at=? WHERE user_id=? AND read_at IS NULL"
+
+	return repo.exec(m, model.RepositoryMethodUPDATE, query, readAt, userID)
+}
+
+// DeleteNotificationsByUserID deletes all records in the inbox of the user.
+func (repo *notificationRepository) DeleteNotificationsByUserID(m repository.SQLManager, userID uint32) error {
+	query := "DELETE FROM notifications WHERE user_id=?"
+
+	return repo.exec(m, model.RepositoryMethodDELETE, query, userID)
+}
+
+// exec executes the query.
+func (repo *notificationRepository) exec(m repository.SQLManager, method model.RepositoryMethod, query string, args ...interface{}) error {
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return repo.ErrorMsg(method, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	if _, err := stmt.ExecContext(repo.ctx, args...); err != nil {
+		return repo.ErrorMsg(method, errors.WithStack(err))
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/testutil"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func Test_notificationRepository_ListNotificationsByUserID(t *testing.T) {
+	// set sqlmock
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Close()
+
+	testutil.SetFakeTime(time.Now())
+	defer testutil.ResetFakeTime()
+
+	unread := &model.Notification{ID: 3, UserID: model.UserValidIDForTest, Kind: model.NotificationKindMention, ThreadID: model.ThreadValidIDForTest, CommentID: 2, ActorID: model.UserInValidIDForTest, CreatedAt: testutil.TimeNow()}
+	read := &model.Notification{ID: 2, UserID: model.UserValidIDForTest, Kind: model.NotificationKindMention, ThreadID: model.ThreadValidIDForTest, CommentID: 1, ActorID: model.UserInValidIDForTest, CreatedAt: testutil.TimeNow(), ReadAt: testutil.TimeNow()}
+
+	tests := []struct {
+		name     string
+		beforeID uint32
+		query    string
+		args     []driver.Value
+	}{
+		{
+			name:  "When beforeID is 0, lists from the newest one",
+			query: "SELECT id, user_id, kind, thread_id, comment_id, actor_id, created_at, read_at FROM notifications WHERE user_id=\\? ORDER BY id DESC LIMIT \\?",
+			args:  []driver.Value{model.UserValidIDForTest, 3},
+		},
+		{
+			name:     "When beforeID is given, lists older ones than it",
+			beforeID: 4,
+			query:    "SELECT id, user_id, kind, thread_id, comment_id, actor_id, created_at, read_at FROM notifications WHERE user_id=\\? AND id<\\? ORDER BY id DESC LIMIT \\?",
+			args:     []driver.Value{model.UserValidIDForTest, 4, 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := sqlmock.NewRows([]string{"id", "user_id", "kind", "thread_id", "comment_id", "actor_id", "created_at", "read_at"}).
+				AddRow(unread.ID, unread.UserID, unread.Kind, unread.ThreadID, unread.CommentID, unread.ActorID, unread.CreatedAt, nil).
+				AddRow(read.ID, read.UserID, read.Kind, read.ThreadID, read.CommentID, read.ActorID, read.CreatedAt, read.ReadAt)
+
+			mock.ExpectPrepare(tt.query).ExpectQuery().
+				WithArgs(tt.args...).
+				WillReturnRows(rows)
+
+			repo := &notificationRepository{
+				ctx: context.Background(),
+			}
+
+			got, err := repo.ListNotificationsByUserID(db, model.UserValidIDForTest, tt.beforeID, 3)
+			if err != nil {
+				t.Fatalf("notificationRepository.ListNotificationsByUserID() error = %v", err)
+			}
+			want := []*model.Notification{unread, read}
+			if !reflect.DeepEqual(got, want) {
+				testutil.Errorf(t, want, got)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+	log "github.com/sirupsen/logrus"
+)
+
+// refreshTokenRepository is repository of refresh token.
+type refreshTokenRepository struct {
+	ctx context.Context
+}
+
+// NewRefreshTokenRepository generates and returns refreshTokenRepository.
+func NewRefreshTokenRepository(ctx context.Context) repository.RefreshTokenRepository {
+	return &refreshTokenRepository{
+		ctx: ctx,
+	}
+}
+
+// ErrorMsg generates and returns error message.
+func (repo *refreshTokenRepository) ErrorMsg(method model.RepositoryMethod, err error) error {
+	return &model.RepositoryError{
+		BaseErr:                     err,
+		RepositoryMethod:            method,
+		DomainModelNameForDeveloper: model.DomainModelNameRefreshTokenForDeveloper,
+		DomainModelNameForUser:      model.DomainModelNameRefreshTokenForUser,
+	}
+}
+
+// GetRefreshTokenByToken gets and returns a record which has the token.
+// Token is hashed before querying because only the hash is stored.
+func (repo *refreshTokenRepository) GetRefreshTokenByToken(m repository.SQLManager, token string) (*model.RefreshToken, error) {
+	query := "SELECT id, user_id, created_at, expires_at FROM refresh_tokens WHERE id=?"
+
+	id := model.RefreshTokenIDFromToken(token)
+	list, err := repo.list(m, model.RepositoryMethodREAD, query, id)
+
+	if len(list) == 0 {
+		err = &model.NoSuchDataError{
+			BaseErr:                     err,
+			PropertyNameForDeveloper:    model.IDPropertyForDeveloper,
+			PropertyNameForUser:         model.IDPropertyForUser,
+			PropertyValue:               id,
+			DomainModelNameForDeveloper: model.DomainModelNameRefreshTokenForDeveloper,
+			DomainModelNameForUser:      model.DomainModelNameRefreshTokenForUser,
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	if err != nil {
+		return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+
+	list[0].Token = token
+	return list[0], nil
+}
+
+// list gets and returns list of records.
+func (repo *refreshTokenRepository) list(m repository.SQLManager, method model.RepositoryMethod, query string, args ...interface{}) (refreshTokens []*model.RefreshToken, err error) {
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return nil, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	rows, err := stmt.QueryContext(repo.ctx, args...)
+	if err != nil {
+		return nil, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+	defer func() {
+		err = rows.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	list := make([]*model.RefreshToken, 0)
+	for rows.Next() {
+		refreshToken := &model.RefreshToken{}
+
+		err = rows.Scan(
+			&refreshToken.ID,
+			&refreshToken.UserID,
+			&refreshToken.CreatedAt,
+			&refreshToken.ExpiresAt,
+		)
+
+		if err != nil {
+			return nil, repo.ErrorMsg(method, errors.WithStack(err))
+		}
+
+		list = append(list, refreshToken)
+	}
+
+	return list, nil
+}
+
+// InsertRefreshToken insert a record.
+func (repo *refreshTokenRepository) InsertRefreshToken(m repository.SQLManager, refreshToken *model.RefreshToken) error {
+	query := "INSERT INTO refresh_tokens (id, user_id, created_at, expires_at) VALUES (?, ?, ?, ?)"
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return repo.ErrorMsg(model.RepositoryMethodInsert, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	result, err := stmt.ExecContext(repo.ctx, refreshToken.ID, refreshToken.UserID, refreshToken.CreatedAt, refreshToken.ExpiresAt)
+	if err != nil {
+		return repo.ErrorMsg(model.RepositoryMethodInsert, errors.WithStack(err))
+	}
+
+	affect, err := result.RowsAffected()
+	if affect != 1 {
+		err = fmt.Errorf("total affected: %d ", affect)
+		return repo.ErrorMsg(model.RepositoryMethodInsert, errors.WithStack(err))
+	}
+
+	return nil
+}
+
+// UseRefreshToken deletes a record which is not expired, in order to rotate it.
+// This returns NoSuchDataError if nothing is deleted, so that concurrent rotations of the same token
+// can not both succeed.
+func (repo *refreshTokenRepository) UseRefreshToken(m repository.SQLManager, id string, at time.Time) error {
+	query := "DELETE FROM refresh_tokens WHERE id=? AND expires_at>?"
+
+	affect, err := repo.delete(m, query, id, at)
+	if err != nil {
+		return err
+	}
+
+	if affect == 0 {
+		err := &model.NoSuchDataError{
+			PropertyNameForDeveloper:    model.IDPropertyForDeveloper,
+			PropertyNameForUser:         model.IDPropertyForUser,
+			PropertyValue:               id,
+			DomainModelNameForDeveloper: model.DomainModelNameRefreshTokenForDeveloper,
+			DomainModelNameForUser:      model.DomainModelNameRefreshTokenForUser,
+		}
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+// DeleteRefreshToken delete a record.
+// This does not fail even if the record does not exist, so that revocation is idempotent.
+func (repo *refreshTokenRepository) DeleteRefreshToken(m repository.SQLManager, id string) error {
+	query := "DELETE FROM refresh_tokens WHERE id=?"
+
+	_, err := repo.delete(m, query, id)
+	return err
+}
+
+// DeleteRefreshTokensByUserID deletes all records of the user.
+func (repo *refreshTokenRepository) DeleteRefreshTokensByUserID(m repository.SQLManager, userID uint32) error {
+	query := "DELETE FROM refresh_tokens WHERE user_id=?"
+
+	_, err := repo.delete(m, query, userID)
+	return err
+}
+
+// delete deletes records and returns the number of deleted records.
+func (repo *refreshTokenRepository) delete(m repository.SQLManager, query string, args ...interface{}) (int64, error) {
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return 0, repo.ErrorMsg(model.RepositoryMethodDELETE, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	result, err := stmt.ExecContext(repo.ctx, args...)
+	if err != nil {
+		return 0, repo.ErrorMsg(model.RepositoryMethodDELETE, errors.WithStack(err))
+	}
+
+	affect, err := result.RowsAffected()
+	if err != nil {
+		return 0, repo.ErrorMsg(model.RepositoryMethodDELETE, errors.WithStack(err))
+	}
+
+	return affect, nil
+}
@@ -0,0 +1,138 @@
+package db
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+	log "github.com/sirupsen/logrus"
+)
+
+// roleRepository is repository of roles of users.
+type roleRepository struct {
+	ctx context.Context
+}
+
+// NewRoleRepository generates and returns RoleRepository.
+func NewRoleRepository(ctx context.Context) repository.RoleRepository {
+	return &roleRepository{
+		ctx: ctx,
+	}
+}
+
+// ErrorMsg generates and returns error message.
+func (repo *roleRepository) ErrorMsg(method model.RepositoryMethod, err error) error {
+	return &model.RepositoryError{
+		BaseErr:                     err,
+		RepositoryMethod:            method,
+		DomainModelNameForDeveloper: model.DomainModelNameRoleForDeveloper,
+		DomainModelNameForUser:      model.DomainModelNameRoleForUser,
+	}
+}
+
+// GetRolesByUserID gets and returns roles of the user.
+// This returns empty roles if the user has no role.
+func (repo *roleRepository) GetRolesByUserID(m repository.SQLManager, userID uint32) (roles model.Roles, err error) {
+	query := "SELECT role FROM user_roles WHERE user_id=? ORDER BY role"
+
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	rows, err := stmt.QueryContext(repo.ctx, userID)
+	if err != nil {
+		return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+	defer func() {
+		err = rows.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	roles = make(model.Roles, 0)
+	for rows.Next() {
+		var role string
+		if err := rows.Scan(&role); err != nil {
+			return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+		}
+		roles = append(roles, model.Role(role))
+	}
+
+	return roles, nil
+}
+
+// InsertRole insert a record.
+// This does nothing if the user already has the role, so that granting is idempotent.
+func (repo *roleRepository) InsertRole(m repository.SQLManager, userID uint32, role model.Role) error {
+	query := "INSERT IGNORE INTO user_roles (user_id, role) VALUES (?, ?)"
+
+	_, err := repo.exec(m, model.RepositoryMethodInsert, query, userID, role.String())
+	return err
+}
+
+// DeleteRole delete a record.
+func (repo *roleRepository) DeleteRole(m repository.SQLManager, userID uint32, role model.Role) error {
+	query := "DELETE FROM user_roles WHERE user_id=? AND role=?"
+
+	affect, err := repo.exec(m, model.RepositoryMethodDELETE, query, userID, role.String())
+	if err != nil {
+		return err
+	}
+
+	if affect == 0 {
+		err := &model.NoSuchDataError{
+			PropertyNameForDeveloper:    model.RolePropertyForDeveloper,
+			PropertyNameForUser:         model.RolePropertyForUser,
+			PropertyValue:               role,
+			DomainModelNameForDeveloper: model.DomainModelNameRoleForDeveloper,
+			DomainModelNameForUser:      model.DomainModelNameRoleForUser,
+		}
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+// DeleteRolesByUserID deletes all records of the user.
+func (repo *roleRepository) DeleteRolesByUserID(m repository.SQLManager, userID uint32) error {
+	query := "DELETE FROM user_roles WHERE user_id=?"
+
+	_, err := repo.exec(m, model.RepositoryMethodDELETE, query, userID)
+	return err
+}
+
+// exec executes the query and returns the number of affected rows.
+func (repo *roleRepository) exec(m repository.SQLManager, method model.RepositoryMethod, query string, args ...interface{}) (int64, error) {
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return 0, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	result, err := stmt.ExecContext(repo.ctx, args...)
+	if err != nil {
+		return 0, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+
+	affect, err := result.RowsAffected()
+	if err != nil {
+		return 0, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+
+	return affect, nil
+}
@@ -0,0 +1,120 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/testutil"
+	"github.com/pkg/errors"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func Test_roleRepository_GetRolesByUserID(t *testing.T) {
+	// set sqlmock
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Close()
+
+	tests := []struct {
+		name  string
+		roles []string
+		want  model.Roles
+	}{
+		{
+			name:  "When the user has roles, returns them",
+			roles: []string{"admin", "moderator"},
+			want:  model.Roles{model.RoleAdmin, model.RoleModerator},
+		},
+		{
+			name:  "When the user has no role, returns empty roles",
+			roles: []string{},
+			want:  model.Roles{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := sqlmock.NewRows([]string{"role"})
+			for _, r := range tt.roles {
+				rows.AddRow(r)
+			}
+
+			query := "SELECT role FROM user_roles WHERE user_id=\\? ORDER BY role"
+			mock.ExpectPrepare(query).ExpectQuery().WithArgs(model.UserValidIDForTest).WillReturnRows(rows)
+
+			repo := &roleRepository{
+				ctx: context.Background(),
+			}
+
+			got, err := repo.GetRolesByUserID(db, model.UserValidIDForTest)
+			if err != nil {
+				t.Fatalf("roleRepository.GetRolesByUserID() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				testutil.Errorf(t, tt.want, got)
+				return
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					testutil.Errorf(t, tt.want, got)
+				}
+			}
+		})
+	}
+}
+
+func Test_roleRepository_DeleteRole(t *testing.T) {
+	// set sqlmock
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Close()
+
+	tests := []struct {
+		name        string
+		rowAffected int64
+		wantErr     error
+	}{
+		{
+			name:        "When the user has the role, returns nil",
+			rowAffected: 1,
+		},
+		{
+			name:        "When the user does not have the role, returns NoSuchDataError",
+			rowAffected: 0,
+			wantErr: &model.NoSuchDataError{
+				PropertyNameForDeveloper:    model.RolePropertyForDeveloper,
+				PropertyNameForUser:         model.RolePropertyForUser,
+				PropertyValue:               model.RoleModerator,
+				DomainModelNameForDeveloper: model.DomainModelNameRoleForDeveloper,
+				DomainModelNameForUser:      model.DomainModelNameRoleForUser,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := "DELETE FROM user_roles WHERE user_id=\\? AND role=\\?"
+			mock.ExpectPrepare(query).ExpectExec().WithArgs(model.UserValidIDForTest, "moderator").WillReturnResult(sqlmock.NewResult(0, tt.rowAffected))
+
+			repo := &roleRepository{
+				ctx: context.Background(),
+			}
+
+			err := repo.DeleteRole(db, model.UserValidIDForTest, model.RoleModerator)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("roleRepository.DeleteRole() error = %v, wantErr nil", err)
+				}
+				return
+			}
+			if err == nil || errors.Cause(err).Error() != tt.wantErr.Error() {
+				t.Errorf("roleRepository.DeleteRole() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package db
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+	log "github.com/sirupsen/logrus"
+)
+
+// searchRepository is repository of the FULLTEXT index of comments with the ngram parser,
+// which splits Japanese text without spaces into words.
+// MySQL updates the index with comments, so that indexing does nothing.
+type searchRepository struct {
+	ctx context.Context
+}
+
+// NewSearchRepository generates and returns SearchRepository.
+func NewSearchRepository(ctx context.Context) repository.SearchRepository {
+	return &searchRepository{
+		ctx: ctx,
+	}
+}
+
+// ErrorMsg generates and returns error message.
+func (repo *searchRepository) ErrorMsg(method model.RepositoryMethod, err error) error {
+	return &model.RepositoryError{
+		BaseErr:                     err,
+		RepositoryMethod:            method,
+		DomainModelNameForDeveloper: model.DomainModelNameCommentForDeveloper,
+		DomainModelNameForUser:      model.DomainModelNameCommentForUser,
+	}
+}
+
+// IndexComment does nothing because the FULLTEXT index is updated with the record.
+func (repo *searchRepository) IndexComment(m repository.SQLManager, comment *model.Comment) error {
+	return nil
+}
+
+// RemoveComment does nothing because deleted records are excluded by the query.
+func (repo *searchRepository) RemoveComment(m repository.SQLManager, commentID uint32) error {
+	return nil
+}
+
+// SearchCommentIDs gets and returns ids of at most limit records which are not deleted and match the query
+// whose ids are less than beforeID in order of newest first.
+// Each term is required as a phrase in boolean mode, so that it matches as a substring of ngrams.
+func (repo *searchRepository) SearchCommentIDs(m repository.SQLManager, query *model.SearchQuery, beforeID uint32, limit int) (ids []uint32, err error) {
+	conditions := []string{"deleted_at IS NULL"}
+	args := make([]interface{}, 0)
+
+	if len(query.Terms) > 0 {
+		conditions = append(conditions, "MATCH(content) AGAINST(? IN BOOLEAN MODE)")
+		args = append(args, booleanModeQuery(query.Terms))
+	}
+	if query.FromUserID != 0 {
+		conditions = append(conditions, "user_id=?")
+		args = append(args, query.FromUserID)
+	}
+	if query.ThreadID != 0 {
+		conditions = append(conditions, "thread_id=?")
+		args = append(args, query.ThreadID)
+	}
+	if !query.Since.IsZero() {
+		conditions = append(conditions, "created_at>=?")
+		args = append(args, query.Since)
+	}
+	if !query.Until.IsZero() {
+		conditions = append(conditions, "created_at<?")
+		args = append(args, query.Until)
+	}
+	if beforeID != 0 {
+		conditions = append(conditions, "id<?")
+		args = append(args, beforeID)
+	}
+	args = append(args, limit)
+
+	q := "SELECT id FROM comments WHERE " + strings.Join(conditions, " AND ") + " ORDER BY id DESC LIMIT ?"
+
+	stmt, err := m.PrepareContext(repo.ctx, q)
+	if err != nil {
+		return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	rows, err := stmt.QueryContext(repo.ctx, args...)
+	if err != nil {
+		return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+	defer func() {
+		err = rows.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	list := make([]uint32, 0)
+	for rows.Next() {
+		var id uint32
+		if err := rows.Scan(&id); err != nil {
+			return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+		}
+		list = append(list, id)
+	}
+
+	return list, nil
+}
+
+// booleanModeQuery returns the search string of boolean mode which requires all terms as phrases.
+// Double quotes in terms are replaced with spaces, because they can not be escaped in phrases.
+func booleanModeQuery(terms []string) string {
+	phrases := make([]string, 0, len(terms))
+	for _, term := range terms {
+		phrases = append(phrases, `+"`+strings.Replace(term, `"`, " ", -1)+`"`)
+	}
+	return strings.Join(phrases, " ")
+}
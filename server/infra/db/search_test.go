@@ -0,0 +1,78 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/testutil"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func Test_searchRepository_SearchCommentIDs(t *testing.T) {
+	// set sqlmock
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Close()
+
+	since := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		query    *model.SearchQuery
+		beforeID uint32
+		sql      string
+		args     []driver.Value
+	}{
+		{
+			name:  "When only terms are given, requires all of them as phrases",
+			query: &model.SearchQuery{Terms: []string{"hello", `say "hi"`}},
+			sql:   "SELECT id FROM comments WHERE deleted_at IS NULL AND MATCH\\(content\\) AGAINST\\(\\? IN BOOLEAN MODE\\) ORDER BY id DESC LIMIT \\?",
+			args:  []driver.Value{`+"hello" +"say  hi "`, 3},
+		},
+		{
+			name:     "When filters and beforeID are given, adds conditions of them",
+			query:    &model.SearchQuery{Terms: []string{"hello"}, FromUserID: model.UserValidIDForTest, ThreadID: model.ThreadValidIDForTest, Since: since},
+			beforeID: 10,
+			sql:      "SELECT id FROM comments WHERE deleted_at IS NULL AND MATCH\\(content\\) AGAINST\\(\\? IN BOOLEAN MODE\\) AND user_id=\\? AND thread_id=\\? AND created_at>=\\? AND id<\\? ORDER BY id DESC LIMIT \\?",
+			args:     []driver.Value{`+"hello"`, model.UserValidIDForTest, model.ThreadValidIDForTest, since, 10, 3},
+		},
+		{
+			name:  "When only filters are given, does not use the FULLTEXT index",
+			query: &model.SearchQuery{Terms: []string{}, FromUserID: model.UserValidIDForTest},
+			sql:   "SELECT id FROM comments WHERE deleted_at IS NULL AND user_id=\\? ORDER BY id DESC LIMIT \\?",
+			args:  []driver.Value{model.UserValidIDForTest, 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := sqlmock.NewRows([]string{"id"}).AddRow(2).AddRow(1)
+
+			mock.ExpectPrepare(tt.sql).ExpectQuery().
+				WithArgs(tt.args...).
+				WillReturnRows(rows)
+
+			repo := &searchRepository{
+				ctx: context.Background(),
+			}
+
+			got, err := repo.SearchCommentIDs(db, tt.query, tt.beforeID, 3)
+			if err != nil {
+				t.Fatalf("searchRepository.SearchCommentIDs() error = %v", err)
+			}
+			want := []uint32{2, 1}
+			if !reflect.DeepEqual(got, want) {
+				testutil.Errorf(t, want, got)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
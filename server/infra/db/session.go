@@ -3,6 +3,7 @@ package db
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -37,7 +38,7 @@ func (repo *sessionRepository) ErrorMsg(method model.RepositoryMethod, err error
 
 // GetSessionByID gets and returns a record specified by id.
 func (repo *sessionRepository) GetSessionByID(m repository.SQLManager, id string) (*model.Session, error) {
-	query := "SELECT id, user_id, created_at FROM sessions WHERE id=?"
+	query := "SELECT id, user_id, user_agent, ip_address, created_at, last_seen_at FROM sessions WHERE id=?"
 
 	list, err := repo.list(m, model.RepositoryMethodREAD, query, id)
 
@@ -60,6 +61,31 @@ func (repo *sessionRepository) GetSessionByID(m repository.SQLManager, id string
 	return list[0], nil
 }
 
+// GetSessionByToken gets and returns a record which has the token.
+// Token is hashed before querying because only the hash is stored.
+func (repo *sessionRepository) GetSessionByToken(m repository.SQLManager, token string) (*model.Session, error) {
+	session, err := repo.GetSessionByID(m, model.SessionIDFromToken(token))
+	if err != nil {
+		return nil, err
+	}
+
+	session.Token = token
+	return session, nil
+}
+
+// GetSessionsByUserID gets and returns records of the user in order of last seen.
+// This returns empty list if the user has no session.
+func (repo *sessionRepository) GetSessionsByUserID(m repository.SQLManager, userID uint32) ([]*model.Session, error) {
+	query := "SELECT id, user_id, user_agent, ip_address, created_at, last_seen_at FROM sessions WHERE user_id=? ORDER BY last_seen_at DESC"
+
+	list, err := repo.list(m, model.RepositoryMethodREAD, query, userID)
+	if err != nil {
+		return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+
+	return list, nil
+}
+
 // list gets and returns list of records.
 func (repo *sessionRepository) list(m repository.SQLManager, method model.RepositoryMethod, query string, args ...interface{}) (sessions []*model.Session, err error) {
 	stmt, err := m.PrepareContext(repo.ctx, query)
@@ -92,7 +118,10 @@ func (repo *sessionRepository) list(m repository.SQLManager, method model.Reposi
 		err = rows.Scan(
 			&session.ID,
 			&session.UserID,
+			&session.UserAgent,
+			&session.IPAddress,
 			&session.CreatedAt,
+			&session.LastSeenAt,
 		)
 
 		if err != nil {
@@ -107,7 +136,7 @@ func (repo *sessionRepository) list(m repository.SQLManager, method model.Reposi
 
 // InsertSession insert a record.
 func (repo *sessionRepository) InsertSession(m repository.SQLManager, session *model.Session) error {
-	query := "INSERT INTO sessions (id, user_id, created_at) VALUES (?, ?, ?, ?)"
+	query := "INSERT INTO sessions (id, user_id, user_agent, ip_address, created_at, last_seen_at) VALUES (?, ?, ?, ?, ?, ?)"
 	stmt, err := m.PrepareContext(repo.ctx, query)
 	if err != nil {
 		return errors.WithStack(repo.ErrorMsg(model.RepositoryMethodInsert, err))
@@ -119,7 +148,7 @@ func (repo *sessionRepository) InsertSession(m repository.SQLManager, session *m
 		}
 	}()
 
-	result, err := stmt.ExecContext(repo.ctx, session.ID, session.UserID, session.CreatedAt)
+	result, err := stmt.ExecContext(repo.ctx, session.ID, session.UserID, session.UserAgent, session.IPAddress, session.CreatedAt, session.LastSeenAt)
 	if err != nil {
 		return errors.WithStack(repo.ErrorMsg(model.RepositoryMethodInsert, err))
 	}
@@ -133,6 +162,29 @@ func (repo *sessionRepository) InsertSession(m repository.SQLManager, session *m
 	return nil
 }
 
+// UpdateLastSeen updates the time and the IP address when the session was used last.
+func (repo *sessionRepository) UpdateLastSeen(m repository.SQLManager, id string, ipAddress string, at time.Time) error {
+	query := "UPDATE sessions SET ip_address=?, last_seen_at=? WHERE id=?"
+
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return repo.ErrorMsg(model.RepositoryMethodUPDATE, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	// affected rows is not checked because it is 0 when nothing is changed.
+	if _, err := stmt.ExecContext(repo.ctx, ipAddress, at, id); err != nil {
+		return repo.ErrorMsg(model.RepositoryMethodUPDATE, errors.WithStack(err))
+	}
+
+	return nil
+}
+
 // DeleteSession delete a record.
 func (repo *sessionRepository) DeleteSession(m repository.SQLManager, id string) error {
 	query := "DELETE FROM sessions WHERE id=?"
@@ -164,3 +216,36 @@ func (repo *sessionRepository) DeleteSession(m repository.SQLManager, id string)
 
 	return nil
 }
+
+// DeleteSessionsByUserID deletes all records of the user.
+func (repo *sessionRepository) DeleteSessionsByUserID(m repository.SQLManager, userID uint32) error {
+	query := "DELETE FROM sessions WHERE user_id=?"
+	return repo.deleteMany(m, query, userID)
+}
+
+// DeleteOtherSessions deletes records of the user except the one specified by id.
+func (repo *sessionRepository) DeleteOtherSessions(m repository.SQLManager, userID uint32, id string) error {
+	query := "DELETE FROM sessions WHERE user_id=? AND id<>?"
+	return repo.deleteMany(m, query, userID, id)
+}
+
+// deleteMany deletes records.
+// This does not fail even if no record is deleted.
+func (repo *sessionRepository) deleteMany(m repository.SQLManager, query string, args ...interface{}) error {
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return repo.ErrorMsg(model.RepositoryMethodDELETE, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	if _, err := stmt.ExecContext(repo.ctx, args...); err != nil {
+		return repo.ErrorMsg(model.RepositoryMethodDELETE, errors.WithStack(err))
+	}
+
+	return nil
+}
@@ -124,9 +124,12 @@ func Test_sessionRepository_GetSessionByID(t *testing.T) {
 				id: model.SessionValidIDForTest,
 			},
 			want: &model.Session{
-				ID:        model.SessionValidIDForTest,
-				UserID:    model.UserValidIDForTest,
-				CreatedAt: testutil.TimeNow(),
+				ID:         model.SessionValidIDForTest,
+				UserID:     model.UserValidIDForTest,
+				UserAgent:  model.UserAgentForTest,
+				IPAddress:  model.ClientIPForTest,
+				CreatedAt:  testutil.TimeNow(),
+				LastSeenAt: testutil.TimeNow(),
 			},
 			wantErr: nil,
 		},
@@ -151,14 +154,14 @@ func Test_sessionRepository_GetSessionByID(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			q := "SELECT id, user_id, created_at FROM sessions WHERE id=?"
+			q := "SELECT id, user_id, user_agent, ip_address, created_at, last_seen_at FROM sessions WHERE id=?"
 			prep := mock.ExpectPrepare(q)
 
 			if tt.wantErr != nil {
 				prep.ExpectQuery().WillReturnError(tt.wantErr)
 			} else {
-				rows := sqlmock.NewRows([]string{"id", "user_id", "created_at"}).
-					AddRow(tt.want.ID, tt.want.UserID, tt.want.CreatedAt)
+				rows := sqlmock.NewRows([]string{"id", "user_id", "user_agent", "ip_address", "created_at", "last_seen_at"}).
+					AddRow(tt.want.ID, tt.want.UserID, tt.want.UserAgent, tt.want.IPAddress, tt.want.CreatedAt, tt.want.LastSeenAt)
 				prep.ExpectQuery().WithArgs(tt.want.ID).WillReturnRows(rows)
 			}
 
@@ -293,9 +296,9 @@ func Test_sessionRepository_InsertSession(t *testing.T) {
 			prep := mock.ExpectPrepare(query)
 
 			if tt.args.err != nil {
-				prep.ExpectExec().WithArgs(tt.args.session.ID, tt.args.session.UserID, tt.args.session.CreatedAt).WillReturnError(tt.args.err)
+				prep.ExpectExec().WithArgs(tt.args.session.ID, tt.args.session.UserID, tt.args.session.UserAgent, tt.args.session.IPAddress, tt.args.session.CreatedAt, tt.args.session.LastSeenAt).WillReturnError(tt.args.err)
 			} else {
-				prep.ExpectExec().WithArgs(tt.args.session.ID, tt.args.session.UserID, tt.args.session.CreatedAt).WillReturnResult(sqlmock.NewResult(1, tt.rowAffected))
+				prep.ExpectExec().WithArgs(tt.args.session.ID, tt.args.session.UserID, tt.args.session.UserAgent, tt.args.session.IPAddress, tt.args.session.CreatedAt, tt.args.session.LastSeenAt).WillReturnResult(sqlmock.NewResult(1, tt.rowAffected))
 			}
 
 			repo := &sessionRepository{
@@ -424,3 +427,129 @@ func Test_sessionRepository_DeleteSession(t *testing.T) {
 		})
 	}
 }
+
+func Test_sessionRepository_DeleteOtherSessions(t *testing.T) {
+	// set sqlmock
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type args struct {
+		m      repository.SQLManager
+		userID uint32
+		id     string
+		err    error
+	}
+
+	tests := []struct {
+		name        string
+		rowAffected int64
+		args        args
+		wantErr     *model.RepositoryError
+	}{
+		{
+			name:        "When other sessions exist, returns nil",
+			rowAffected: 2,
+			args: args{
+				m:      db,
+				userID: model.UserValidIDForTest,
+				id:     model.SessionValidIDForTest,
+			},
+			wantErr: nil,
+		},
+		{
+			name:        "When no other session exists, returns nil",
+			rowAffected: 0,
+			args: args{
+				m:      db,
+				userID: model.UserValidIDForTest,
+				id:     model.SessionValidIDForTest,
+			},
+			wantErr: nil,
+		},
+		{
+			name: "when DB error has occurred、returns error",
+			args: args{
+				m:      db,
+				userID: model.UserValidIDForTest,
+				id:     model.SessionValidIDForTest,
+				err:    errors.New(model.ErrorMessageForTest),
+			},
+			wantErr: &model.RepositoryError{
+				RepositoryMethod:            model.RepositoryMethodDELETE,
+				DomainModelNameForDeveloper: model.DomainModelNameSessionForDeveloper,
+				DomainModelNameForUser:      model.DomainModelNameSessionForUser,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := "DELETE FROM sessions WHERE user_id=\\? AND id<>\\?"
+			prep := mock.ExpectPrepare(query)
+
+			if tt.args.err != nil {
+				prep.ExpectExec().WithArgs(tt.args.userID, tt.args.id).WillReturnError(tt.args.err)
+			} else {
+				prep.ExpectExec().WithArgs(tt.args.userID, tt.args.id).WillReturnResult(sqlmock.NewResult(0, tt.rowAffected))
+			}
+
+			repo := &sessionRepository{
+				ctx: context.Background(),
+			}
+
+			err := repo.DeleteOtherSessions(tt.args.m, tt.args.userID, tt.args.id)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("sessionRepository.DeleteOtherSessions() error = %v, wantErr nil", err)
+				}
+				return
+			}
+			if err == nil || errors.Cause(err).Error() != tt.wantErr.Error() {
+				t.Errorf("sessionRepository.DeleteOtherSessions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_sessionRepository_GetSessionByToken(t *testing.T) {
+	// set sqlmock
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	testutil.SetFakeTime(time.Now())
+
+	id := model.SessionIDFromToken(model.SessionTokenForTest)
+	want := &model.Session{
+		ID:         id,
+		Token:      model.SessionTokenForTest,
+		UserID:     model.UserValidIDForTest,
+		UserAgent:  model.UserAgentForTest,
+		IPAddress:  model.ClientIPForTest,
+		CreatedAt:  testutil.TimeNow(),
+		LastSeenAt: testutil.TimeNow(),
+	}
+
+	// only the hash of the token is given to the database.
+	rows := sqlmock.NewRows([]string{"id", "user_id", "user_agent", "ip_address", "created_at", "last_seen_at"}).
+		AddRow(want.ID, want.UserID, want.UserAgent, want.IPAddress, want.CreatedAt, want.LastSeenAt)
+	mock.ExpectPrepare("SELECT id, user_id, user_agent, ip_address, created_at, last_seen_at FROM sessions WHERE id=?").
+		ExpectQuery().WithArgs(id).WillReturnRows(rows)
+
+	repo := &sessionRepository{
+		ctx: context.Background(),
+	}
+	got, err := repo.GetSessionByToken(db, model.SessionTokenForTest)
+	if err != nil {
+		t.Fatalf("sessionRepository.GetSessionByToken() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sessionRepository.GetSessionByToken() = %v, want %v", got, want)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
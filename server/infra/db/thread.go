@@ -0,0 +1,222 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+	log "github.com/sirupsen/logrus"
+)
+
+// threadRepository is repository of thread.
+type threadRepository struct {
+	ctx context.Context
+}
+
+// NewThreadRepository generates and returns ThreadRepository.
+func NewThreadRepository(ctx context.Context) repository.ThreadRepository {
+	return &threadRepository{
+		ctx: ctx,
+	}
+}
+
+// ErrorMsg generates and returns error message.
+func (repo *threadRepository) ErrorMsg(method model.RepositoryMethod, err error) error {
+	return &model.RepositoryError{
+		BaseErr:                     err,
+		RepositoryMethod:            method,
+		DomainModelNameForDeveloper: model.DomainModelNameThreadForDeveloper,
+		DomainModelNameForUser:      model.DomainModelNameThreadForUser,
+	}
+}
+
+// ListThreadsForUser gets and returns public records and private records which the user is a member of
+// in order of creation, the newest first. Threads of direct conversations are not included.
+// This returns empty list if there is no thread.
+func (repo *threadRepository) ListThreadsForUser(m repository.SQLManager, userID uint32) ([]*model.Thread, error) {
+	query := "SELECT t.id, t.title, t.visibility, t.user_id, t.created_at, t.updated_at FROM threads t " +
+		"WHERE t.visibility<>? AND (t.visibility=? OR EXISTS (SELECT 1 FROM thread_members tm WHERE tm.thread_id=t.id AND tm.user_id=?)) ORDER BY t.id DESC"
+
+	list, err := repo.list(m, model.RepositoryMethodREAD, query, model.ThreadVisibilityDirect, model.ThreadVisibilityPublic, userID)
+	if err != nil {
+		return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+
+	return list, nil
+}
+
+// GetThreadByID gets and returns a record specified by id.
+func (repo *threadRepository) GetThreadByID(m repository.SQLManager, id uint32) (*model.Thread, error) {
+	query := "SELECT id, title, visibility, user_id, created_at, updated_at FROM threads WHERE id=?"
+
+	list, err := repo.list(m, model.RepositoryMethodREAD, query, id)
+
+	if len(list) == 0 {
+		err = &model.NoSuchDataError{
+			BaseErr:                     err,
+			PropertyNameForDeveloper:    model.IDPropertyForDeveloper,
+			PropertyNameForUser:         model.IDPropertyForUser,
+			PropertyValue:               id,
+			DomainModelNameForDeveloper: model.DomainModelNameThreadForDeveloper,
+			DomainModelNameForUser:      model.DomainModelNameThreadForUser,
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	if err != nil {
+		return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+
+	return list[0], nil
+}
+
+// GetPublicThreadByTitle gets and returns a public record specified by title.
+func (repo *threadRepository) GetPublicThreadByTitle(m repository.SQLManager, title string) (*model.Thread, error) {
+	query := "SELECT id, title, visibility, user_id, created_at, updated_at FROM threads WHERE public_title=?"
+
+	list, err := repo.list(m, model.RepositoryMethodREAD, query, title)
+
+	if len(list) == 0 {
+		err = &model.NoSuchDataError{
+			BaseErr:                     err,
+			PropertyNameForDeveloper:    model.TitlePropertyForDeveloper,
+			PropertyNameForUser:         model.TitlePropertyForUser,
+			PropertyValue:               title,
+			DomainModelNameForDeveloper: model.DomainModelNameThreadForDeveloper,
+			DomainModelNameForUser:      model.DomainModelNameThreadForUser,
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	if err != nil {
+		return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+
+	return list[0], nil
+}
+
+// list gets and returns list of records.
+func (repo *threadRepository) list(m repository.SQLManager, method model.RepositoryMethod, query string, args ...interface{}) (threads []*model.Thread, err error) {
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return nil, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	rows, err := stmt.QueryContext(repo.ctx, args...)
+	if err != nil {
+		return nil, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+	defer func() {
+		err = rows.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	list := make([]*model.Thread, 0)
+	for rows.Next() {
+		thread := &model.Thread{}
+		// title of the thread of the direct conversation is NULL.
+		var title sql.NullString
+
+		err = rows.Scan(
+			&thread.ID,
+			&title,
+			&thread.Visibility,
+			&thread.UserID,
+			&thread.CreatedAt,
+			&thread.UpdatedAt,
+		)
+
+		if err != nil {
+			return nil, repo.ErrorMsg(method, errors.WithStack(err))
+		}
+		thread.Title = title.String
+
+		list = append(list, thread)
+	}
+
+	return list, nil
+}
+
+// InsertThread insert a record and returns its id.
+// Empty title is stored as NULL, so that threads of direct conversations do not conflict on the unique key of title.
+func (repo *threadRepository) InsertThread(m repository.SQLManager, thread *model.Thread) (uint32, error) {
+	query := "INSERT INTO threads (title, visibility, user_id, created_at, updated_at) VALUES (?, ?, ?, ?, ?)"
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return model.InvalidID, repo.ErrorMsg(model.RepositoryMethodInsert, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	title := sql.NullString{String: thread.Title, Valid: thread.Title != ""}
+	result, err := stmt.ExecContext(repo.ctx, title, thread.Visibility, thread.UserID, thread.CreatedAt, thread.UpdatedAt)
+	if err != nil {
+		// only public threads conflict on the key, so that the error tells nothing about private threads.
+		if isDuplicateEntry(err, "public_title") {
+			return model.InvalidID, errors.WithStack(titleAlreadyExistError(thread.Title, err))
+		}
+		return model.InvalidID, repo.ErrorMsg(model.RepositoryMethodInsert, errors.WithStack(err))
+	}
+
+	affect, err := result.RowsAffected()
+	if affect != 1 {
+		err = fmt.Errorf("total affected: %d ", affect)
+		return model.InvalidID, repo.ErrorMsg(model.RepositoryMethodInsert, errors.WithStack(err))
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return model.InvalidID, repo.ErrorMsg(model.RepositoryMethodInsert, errors.WithStack(err))
+	}
+
+	return uint32(id), nil
+}
+
+// titleAlreadyExistError returns AlreadyExistError of the title, which the unique key of public titles rejects.
+func titleAlreadyExistError(title string, err error) error {
+	return &model.AlreadyExistError{
+		BaseErr:                     err,
+		PropertyNameForDeveloper:    model.TitlePropertyForDeveloper,
+		PropertyNameForUser:         model.TitlePropertyForUser,
+		PropertyValue:               title,
+		DomainModelNameForDeveloper: model.DomainModelNameThreadForDeveloper,
+		DomainModelNameForUser:      model.DomainModelNameThreadForUser,
+	}
+}
+
+// UpdateThreadOwner updates the user who owns the record specified by id.
+func (repo *threadRepository) UpdateThreadOwner(m repository.SQLManager, id, userID uint32) error {
+	query := "UPDATE threads SET user_id=? WHERE id=?"
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return repo.ErrorMsg(model.RepositoryMethodUPDATE, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	if _, err := stmt.ExecContext(repo.ctx, userID, id); err != nil {
+		return repo.ErrorMsg(model.RepositoryMethodUPDATE, errors.WithStack(err))
+	}
+
+	return nil
+}
@@ -0,0 +1,185 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+	log "github.com/sirupsen/logrus"
+)
+
+// threadInviteRepository is repository of invitations to threads.
+type threadInviteRepository struct {
+	ctx context.Context
+}
+
+// NewThreadInviteRepository generates and returns ThreadInviteRepository.
+func NewThreadInviteRepository(ctx context.Context) repository.ThreadInviteRepository {
+	return &threadInviteRepository{
+		ctx: ctx,
+	}
+}
+
+// ErrorMsg generates and returns error message.
+func (repo *threadInviteRepository) ErrorMsg(method model.RepositoryMethod, err error) error {
+	return &model.RepositoryError{
+		BaseErr:                     err,
+		RepositoryMethod:            method,
+		DomainModelNameForDeveloper: model.DomainModelNameThreadInviteForDeveloper,
+		DomainModelNameForUser:      model.DomainModelNameThreadInviteForUser,
+	}
+}
+
+// GetThreadInviteByToken gets and returns a record which has the token.
+// Token is hashed before querying because only the hash is stored.
+func (repo *threadInviteRepository) GetThreadInviteByToken(m repository.SQLManager, token string) (*model.ThreadInvite, error) {
+	query := "SELECT id, thread_id, user_id, max_uses, uses, created_at, expires_at FROM thread_invites WHERE id=?"
+
+	id := model.ThreadInviteIDFromToken(token)
+	list, err := repo.list(m, model.RepositoryMethodREAD, query, id)
+
+	if len(list) == 0 {
+		err = &model.NoSuchDataError{
+			BaseErr:                     err,
+			PropertyNameForDeveloper:    model.TokenPropertyForDeveloper,
+			PropertyNameForUser:         model.TokenPropertyForUser,
+			PropertyValue:               id,
+			DomainModelNameForDeveloper: model.DomainModelNameThreadInviteForDeveloper,
+			DomainModelNameForUser:      model.DomainModelNameThreadInviteForUser,
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	if err != nil {
+		return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+
+	list[0].Token = token
+	return list[0], nil
+}
+
+// list gets and returns list of records.
+func (repo *threadInviteRepository) list(m repository.SQLManager, method model.RepositoryMethod, query string, args ...interface{}) (invites []*model.ThreadInvite, err error) {
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return nil, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	rows, err := stmt.QueryContext(repo.ctx, args...)
+	if err != nil {
+		return nil, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+	defer func() {
+		err = rows.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	list := make([]*model.ThreadInvite, 0)
+	for rows.Next() {
+		invite := &model.ThreadInvite{}
+
+		err = rows.Scan(
+			&invite.ID,
+			&invite.ThreadID,
+			&invite.UserID,
+			&invite.MaxUses,
+			&invite.Uses,
+			&invite.CreatedAt,
+			&invite.ExpiresAt,
+		)
+
+		if err != nil {
+			return nil, repo.ErrorMsg(method, errors.WithStack(err))
+		}
+
+		list = append(list, invite)
+	}
+
+	return list, nil
+}
+
+// InsertThreadInvite insert a record.
+func (repo *threadInviteRepository) InsertThreadInvite(m repository.SQLManager, invite *model.ThreadInvite) error {
+	query := "INSERT INTO thread_invites (id, thread_id, user_id, max_uses, uses, created_at, expires_at) VALUES (?, ?, ?, ?, ?, ?, ?)"
+
+	_, err := repo.exec(m, model.RepositoryMethodInsert, query,
+		invite.ID, invite.ThreadID, invite.UserID, invite.MaxUses, invite.Uses, invite.CreatedAt, invite.ExpiresAt)
+	return err
+}
+
+// UseThreadInvite increments uses of a record which is neither used up nor expired.
+// The condition is checked by the update itself, so that concurrent uses do not exceed the limit.
+func (repo *threadInviteRepository) UseThreadInvite(m repository.SQLManager, id string, at time.Time) error {
+	query := "UPDATE thread_invites SET uses=uses+1 WHERE id=? AND (max_uses=0 OR uses<max_uses) AND expires_at>?"
+
+	affect, err := repo.exec(m, model.RepositoryMethodUPDATE, query, id, at)
+	if err != nil {
+		return err
+	}
+
+	if affect == 0 {
+		err := &model.NoSuchDataError{
+			PropertyNameForDeveloper:    model.IDPropertyForDeveloper,
+			PropertyNameForUser:         model.IDPropertyForUser,
+			PropertyValue:               id,
+			DomainModelNameForDeveloper: model.DomainModelNameThreadInviteForDeveloper,
+			DomainModelNameForUser:      model.DomainModelNameThreadInviteForUser,
+		}
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+// DeleteThreadInvite delete a record.
+func (repo *threadInviteRepository) DeleteThreadInvite(m repository.SQLManager, id string) error {
+	query := "DELETE FROM thread_invites WHERE id=?"
+
+	_, err := repo.exec(m, model.RepositoryMethodDELETE, query, id)
+	return err
+}
+
+// DeleteThreadInvitesByUserID deletes all records created by the user.
+func (repo *threadInviteRepository) DeleteThreadInvitesByUserID(m repository.SQLManager, userID uint32) error {
+	query := "DELETE FROM thread_invites WHERE user_id=?"
+
+	_, err := repo.exec(m, model.RepositoryMethodDELETE, query, userID)
+	return err
+}
+
+// exec executes the query and returns the number of affected rows.
+func (repo *threadInviteRepository) exec(m repository.SQLManager, method model.RepositoryMethod, query string, args ...interface{}) (int64, error) {
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return 0, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	result, err := stmt.ExecContext(repo.ctx, args...)
+	if err != nil {
+		return 0, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+
+	affect, err := result.RowsAffected()
+	if err != nil {
+		return 0, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+
+	return affect, nil
+}
@@ -0,0 +1,203 @@
+package db
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+	log "github.com/sirupsen/logrus"
+)
+
+// threadMemberRepository is repository of members of each thread.
+type threadMemberRepository struct {
+	ctx context.Context
+}
+
+// NewThreadMemberRepository generates and returns ThreadMemberRepository.
+func NewThreadMemberRepository(ctx context.Context) repository.ThreadMemberRepository {
+	return &threadMemberRepository{
+		ctx: ctx,
+	}
+}
+
+// ErrorMsg generates and returns error message.
+func (repo *threadMemberRepository) ErrorMsg(method model.RepositoryMethod, err error) error {
+	return &model.RepositoryError{
+		BaseErr:                     err,
+		RepositoryMethod:            method,
+		DomainModelNameForDeveloper: model.DomainModelNameThreadMemberForDeveloper,
+		DomainModelNameForUser:      model.DomainModelNameThreadMemberForUser,
+	}
+}
+
+// GetThreadMember gets and returns a record of the user in the thread.
+func (repo *threadMemberRepository) GetThreadMember(m repository.SQLManager, threadID, userID uint32) (*model.ThreadMember, error) {
+	query := "SELECT thread_id, user_id, role, created_at FROM thread_members WHERE thread_id=? AND user_id=?"
+
+	list, err := repo.list(m, model.RepositoryMethodREAD, query, threadID, userID)
+
+	if len(list) == 0 {
+		err = &model.NoSuchDataError{
+			BaseErr:                     err,
+			PropertyNameForDeveloper:    model.UserIDPropertyForDeveloper,
+			PropertyNameForUser:         model.UserIDPropertyForUser,
+			PropertyValue:               userID,
+			DomainModelNameForDeveloper: model.DomainModelNameThreadMemberForDeveloper,
+			DomainModelNameForUser:      model.DomainModelNameThreadMemberForUser,
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	if err != nil {
+		return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+
+	return list[0], nil
+}
+
+// GetThreadMembersByThreadID gets and returns records of the thread in order of joining.
+// This returns empty list if the thread has no member.
+func (repo *threadMemberRepository) GetThreadMembersByThreadID(m repository.SQLManager, threadID uint32) ([]*model.ThreadMember, error) {
+	query := "SELECT thread_id, user_id, role, created_at FROM thread_members WHERE thread_id=? ORDER BY created_at, user_id"
+
+	list, err := repo.list(m, model.RepositoryMethodREAD, query, threadID)
+	if err != nil {
+		return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+
+	return list, nil
+}
+
+// GetThreadMembersByUserID gets and returns records of the user.
+// This returns empty list if the user is not a member of any thread.
+func (repo *threadMemberRepository) GetThreadMembersByUserID(m repository.SQLManager, userID uint32) ([]*model.ThreadMember, error) {
+	query := "SELECT thread_id, user_id, role, created_at FROM thread_members WHERE user_id=? ORDER BY thread_id"
+
+	list, err := repo.list(m, model.RepositoryMethodREAD, query, userID)
+	if err != nil {
+		return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+
+	return list, nil
+}
+
+// list gets and returns list of records.
+func (repo *threadMemberRepository) list(m repository.SQLManager, method model.RepositoryMethod, query string, args ...interface{}) (members []*model.ThreadMember, err error) {
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return nil, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	rows, err := stmt.QueryContext(repo.ctx, args...)
+	if err != nil {
+		return nil, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+	defer func() {
+		err = rows.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	list := make([]*model.ThreadMember, 0)
+	for rows.Next() {
+		member := &model.ThreadMember{}
+
+		err = rows.Scan(
+			&member.ThreadID,
+			&member.UserID,
+			&member.Role,
+			&member.CreatedAt,
+		)
+
+		if err != nil {
+			return nil, repo.ErrorMsg(method, errors.WithStack(err))
+		}
+
+		list = append(list, member)
+	}
+
+	return list, nil
+}
+
+// InsertThreadMember insert a record.
+// This does nothing if the user is already a member, so that joining is idempotent.
+func (repo *threadMemberRepository) InsertThreadMember(m repository.SQLManager, member *model.ThreadMember) error {
+	query := "INSERT IGNORE INTO thread_members (thread_id, user_id, role, created_at) VALUES (?, ?, ?, ?)"
+
+	_, err := repo.exec(m, model.RepositoryMethodInsert, query, member.ThreadID, member.UserID, member.Role, member.CreatedAt)
+	return err
+}
+
+// DeleteThreadMember delete a record.
+func (repo *threadMemberRepository) DeleteThreadMember(m repository.SQLManager, threadID, userID uint32) error {
+	query := "DELETE FROM thread_members WHERE thread_id=? AND user_id=?"
+
+	affect, err := repo.exec(m, model.RepositoryMethodDELETE, query, threadID, userID)
+	if err != nil {
+		return err
+	}
+
+	if affect == 0 {
+		err := &model.NoSuchDataError{
+			PropertyNameForDeveloper:    model.UserIDPropertyForDeveloper,
+			PropertyNameForUser:         model.UserIDPropertyForUser,
+			PropertyValue:               userID,
+			DomainModelNameForDeveloper: model.DomainModelNameThreadMemberForDeveloper,
+			DomainModelNameForUser:      model.DomainModelNameThreadMemberForUser,
+		}
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+// UpdateThreadMemberRole updates the role of the user in the thread.
+func (repo *threadMemberRepository) UpdateThreadMemberRole(m repository.SQLManager, threadID, userID uint32, role model.ThreadMemberRole) error {
+	query := "UPDATE thread_members SET role=? WHERE thread_id=? AND user_id=?"
+
+	_, err := repo.exec(m, model.RepositoryMethodUPDATE, query, role, threadID, userID)
+	return err
+}
+
+// DeleteThreadMembersByUserID deletes all records of the user.
+func (repo *threadMemberRepository) DeleteThreadMembersByUserID(m repository.SQLManager, userID uint32) error {
+	query := "DELETE FROM thread_members WHERE user_id=?"
+
+	_, err := repo.exec(m, model.RepositoryMethodDELETE, query, userID)
+	return err
+}
+
+// exec executes the query and returns the number of affected rows.
+func (repo *threadMemberRepository) exec(m repository.SQLManager, method model.RepositoryMethod, query string, args ...interface{}) (int64, error) {
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return 0, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	result, err := stmt.ExecContext(repo.ctx, args...)
+	if err != nil {
+		return 0, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+
+	affect, err := result.RowsAffected()
+	if err != nil {
+		return 0, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+
+	return affect, nil
+}
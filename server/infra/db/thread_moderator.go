@@ -0,0 +1,153 @@
+package db
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+	log "github.com/sirupsen/logrus"
+)
+
+// threadModeratorRepository is repository of moderators of each thread.
+type threadModeratorRepository struct {
+	ctx context.Context
+}
+
+// NewThreadModeratorRepository generates and returns ThreadModeratorRepository.
+func NewThreadModeratorRepository(ctx context.Context) repository.ThreadModeratorRepository {
+	return &threadModeratorRepository{
+		ctx: ctx,
+	}
+}
+
+// ErrorMsg generates and returns error message.
+func (repo *threadModeratorRepository) ErrorMsg(method model.RepositoryMethod, err error) error {
+	return &model.RepositoryError{
+		BaseErr:                     err,
+		RepositoryMethod:            method,
+		DomainModelNameForDeveloper: model.DomainModelNameThreadModeratorForDeveloper,
+		DomainModelNameForUser:      model.DomainModelNameThreadModeratorForUser,
+	}
+}
+
+// GetModeratorIDsByThreadID gets and returns ids of users who moderate the thread.
+// This returns empty list if no one moderates the thread.
+func (repo *threadModeratorRepository) GetModeratorIDsByThreadID(m repository.SQLManager, threadID uint32) ([]uint32, error) {
+	query := "SELECT user_id FROM thread_moderators WHERE thread_id=? ORDER BY user_id"
+	return repo.userIDs(m, query, threadID)
+}
+
+// IsThreadModerator returns whether the user moderates the thread.
+func (repo *threadModeratorRepository) IsThreadModerator(m repository.SQLManager, threadID, userID uint32) (bool, error) {
+	query := "SELECT user_id FROM thread_moderators WHERE thread_id=? AND user_id=?"
+
+	ids, err := repo.userIDs(m, query, threadID, userID)
+	if err != nil {
+		return false, err
+	}
+	return len(ids) > 0, nil
+}
+
+// userIDs gets and returns ids of users.
+func (repo *threadModeratorRepository) userIDs(m repository.SQLManager, query string, args ...interface{}) (ids []uint32, err error) {
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	rows, err := stmt.QueryContext(repo.ctx, args...)
+	if err != nil {
+		return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+	defer func() {
+		err = rows.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	ids = make([]uint32, 0)
+	for rows.Next() {
+		var id uint32
+		if err := rows.Scan(&id); err != nil {
+			return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// InsertThreadModerator insert a record.
+// This does nothing if the user already moderates the thread, so that appointing is idempotent.
+func (repo *threadModeratorRepository) InsertThreadModerator(m repository.SQLManager, threadID, userID uint32) error {
+	query := "INSERT IGNORE INTO thread_moderators (thread_id, user_id) VALUES (?, ?)"
+
+	_, err := repo.exec(m, model.RepositoryMethodInsert, query, threadID, userID)
+	return err
+}
+
+// DeleteThreadModerator delete a record.
+func (repo *threadModeratorRepository) DeleteThreadModerator(m repository.SQLManager, threadID, userID uint32) error {
+	query := "DELETE FROM thread_moderators WHERE thread_id=? AND user_id=?"
+
+	affect, err := repo.exec(m, model.RepositoryMethodDELETE, query, threadID, userID)
+	if err != nil {
+		return err
+	}
+
+	if affect == 0 {
+		err := &model.NoSuchDataError{
+			PropertyNameForDeveloper:    model.UserIDPropertyForDeveloper,
+			PropertyNameForUser:         model.UserIDPropertyForUser,
+			PropertyValue:               userID,
+			DomainModelNameForDeveloper: model.DomainModelNameThreadModeratorForDeveloper,
+			DomainModelNameForUser:      model.DomainModelNameThreadModeratorForUser,
+		}
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+// DeleteThreadModeratorsByUserID deletes all records of the user.
+func (repo *threadModeratorRepository) DeleteThreadModeratorsByUserID(m repository.SQLManager, userID uint32) error {
+	query := "DELETE FROM thread_moderators WHERE user_id=?"
+
+	_, err := repo.exec(m, model.RepositoryMethodDELETE, query, userID)
+	return err
+}
+
+// exec executes the query and returns the number of affected rows.
+func (repo *threadModeratorRepository) exec(m repository.SQLManager, method model.RepositoryMethod, query string, args ...interface{}) (int64, error) {
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return 0, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	result, err := stmt.ExecContext(repo.ctx, args...)
+	if err != nil {
+		return 0, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+
+	affect, err := result.RowsAffected()
+	if err != nil {
+		return 0, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+
+	return affect, nil
+}
@@ -0,0 +1,131 @@
+package db
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+	log "github.com/sirupsen/logrus"
+)
+
+// threadReadRepository is repository of how far each user has read threads.
+type threadReadRepository struct {
+	ctx context.Context
+}
+
+// NewThreadReadRepository generates and returns ThreadReadRepository.
+func NewThreadReadRepository(ctx context.Context) repository.ThreadReadRepository {
+	return &threadReadRepository{
+		ctx: ctx,
+	}
+}
+
+// ErrorMsg generates and returns error message.
+func (repo *threadReadRepository) ErrorMsg(method model.RepositoryMethod, err error) error {
+	return &model.RepositoryError{
+		BaseErr:                     err,
+		RepositoryMethod:            method,
+		DomainModelNameForDeveloper: model.DomainModelNameThreadReadForDeveloper,
+		DomainModelNameForUser:      model.DomainModelNameThreadReadForUser,
+	}
+}
+
+// GetThreadUnreadsByThreadIDs counts comments of the threads posted by others after the last read comment of the user.
+// This scans only unread rows of the index (thread_id, id, user_id) of comments, and returns empty list if threadIDs is empty.
+func (repo *threadReadRepository) GetThreadUnreadsByThreadIDs(m repository.SQLManager, userID uint32, threadIDs []uint32) (unreads []*model.ThreadUnread, err error) {
+	if len(threadIDs) == 0 {
+		return make([]*model.ThreadUnread, 0), nil
+	}
+
+	query := "SELECT c.thread_id, COUNT(*), MIN(c.id) FROM comments c " +
+		"LEFT JOIN thread_reads r ON r.thread_id=c.thread_id AND r.user_id=? " +
+		"WHERE c.thread_id IN (?" + strings.Repeat(", ?", len(threadIDs)-1) + ") " +
+		"AND c.id>COALESCE(r.last_read_comment_id, 0) AND c.user_id<>? GROUP BY c.thread_id"
+
+	args := make([]interface{}, 0, len(threadIDs)+2)
+	args = append(args, userID)
+	for _, id := range threadIDs {
+		args = append(args, id)
+	}
+	args = append(args, userID)
+
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	rows, err := stmt.QueryContext(repo.ctx, args...)
+	if err != nil {
+		return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+	defer func() {
+		err = rows.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	list := make([]*model.ThreadUnread, 0)
+	for rows.Next() {
+		unread := &model.ThreadUnread{}
+
+		err = rows.Scan(
+			&unread.ThreadID,
+			&unread.UnreadCount,
+			&unread.FirstUnreadCommentID,
+		)
+
+		if err != nil {
+			return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+		}
+
+		list = append(list, unread)
+	}
+
+	return list, nil
+}
+
+// UpsertThreadRead inserts a record or updates the existing one.
+// The last read comment is kept if it is newer, so that reading on another device does not make comments unread again.
+func (repo *threadReadRepository) UpsertThreadRead(m repository.SQLManager, read *model.ThreadRead) error {
+	query := "INSERT INTO thread_reads (thread_id, user_id, last_read_comment_id, updated_at) VALUES (?, ?, ?, ?) " +
+		"ON DUPLICATE KEY UPDATE last_read_comment_id=GREATEST(last_read_comment_id, VALUES(last_read_comment_id)), updated_at=VALUES(updated_at)"
+
+	return repo.exec(m, model.RepositoryMethodInsert, query, read.ThreadID, read.UserID, read.LastReadCommentID, read.UpdatedAt)
+}
+
+// DeleteThreadReadsByUserID deletes all records of the user.
+func (repo *threadReadRepository) DeleteThreadReadsByUserID(m repository.SQLManager, userID uint32) error {
+	query := "DELETE FROM thread_reads WHERE user_id=?"
+
+	return repo.exec(m, model.RepositoryMethodDELETE, query, userID)
+}
+
+// exec executes the query.
+func (repo *threadReadRepository) exec(m repository.SQLManager, method model.RepositoryMethod, query string, args ...interface{}) error {
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return repo.ErrorMsg(method, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	if _, err := stmt.ExecContext(repo.ctx, args...); err != nil {
+		return repo.ErrorMsg(method, errors.WithStack(err))
+	}
+
+	return nil
+}
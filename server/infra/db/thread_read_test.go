@@ -0,0 +1,74 @@
+package db
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/testutil"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func Test_threadReadRepository_GetThreadUnreadsByThreadIDs(t *testing.T) {
+	// set sqlmock
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Close()
+
+	tests := []struct {
+		name      string
+		threadIDs []uint32
+		want      []*model.ThreadUnread
+	}{
+		{
+			name:      "When threads are given, counts unread comments of all of them by one query",
+			threadIDs: []uint32{1, 2, 3},
+			want: []*model.ThreadUnread{
+				{ThreadID: 1, UnreadCount: 2, FirstUnreadCommentID: 10},
+				{ThreadID: 3, UnreadCount: 1, FirstUnreadCommentID: 30},
+			},
+		},
+		{
+			name:      "When no thread is given, returns empty list without query",
+			threadIDs: []uint32{},
+			want:      []*model.ThreadUnread{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if len(tt.threadIDs) > 0 {
+				rows := sqlmock.NewRows([]string{"thread_id", "count", "min"})
+				for _, u := range tt.want {
+					rows.AddRow(u.ThreadID, u.UnreadCount, u.FirstUnreadCommentID)
+				}
+
+				query := "SELECT c.thread_id, COUNT\\(\\*\\), MIN\\(c.id\\) FROM comments c " +
+					"LEFT JOIN thread_reads r ON r.thread_id=c.thread_id AND r.user_id=\\? " +
+					"WHERE c.thread_id IN \\(\\?, \\?, \\?\\) " +
+					"AND c.id>COALESCE\\(r.last_read_comment_id, 0\\) AND c.user_id<>\\? GROUP BY c.thread_id"
+				mock.ExpectPrepare(query).ExpectQuery().
+					WithArgs(model.UserValidIDForTest, 1, 2, 3, model.UserValidIDForTest).
+					WillReturnRows(rows)
+			}
+
+			repo := &threadReadRepository{
+				ctx: context.Background(),
+			}
+
+			got, err := repo.GetThreadUnreadsByThreadIDs(db, model.UserValidIDForTest, tt.threadIDs)
+			if err != nil {
+				t.Fatalf("threadReadRepository.GetThreadUnreadsByThreadIDs() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				testutil.Errorf(t, tt.want, got)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
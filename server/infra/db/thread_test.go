@@ -0,0 +1,70 @@
+package db
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/pkg/errors"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+)
+
+func Test_threadRepository_GetPublicThreadByTitle(t *testing.T) {
+	// set sqlmock
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Close()
+
+	// private threads have NULL public_title, so that they are never found by the title.
+	query := "SELECT id, title, visibility, user_id, created_at, updated_at FROM threads WHERE public_title=\\?"
+	mock.ExpectPrepare(query).ExpectQuery().
+		WithArgs(model.ThreadTitleForTest).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "visibility", "user_id", "created_at", "updated_at"}))
+
+	repo := &threadRepository{
+		ctx: context.Background(),
+	}
+
+	_, err = repo.GetPublicThreadByTitle(db, model.ThreadTitleForTest)
+	if _, ok := errors.Cause(err).(*model.NoSuchDataError); !ok {
+		t.Errorf("threadRepository.GetPublicThreadByTitle() error = %v, want NoSuchDataError", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func Test_threadRepository_InsertThread_duplicateTitle(t *testing.T) {
+	// set sqlmock
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Close()
+
+	mock.ExpectPrepare("INSERT INTO threads").ExpectExec().
+		WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'testThreadTitle' for key 'threads.public_title'"})
+
+	repo := &threadRepository{
+		ctx: context.Background(),
+	}
+
+	_, err = repo.InsertThread(db, &model.Thread{Title: model.ThreadTitleForTest, Visibility: model.ThreadVisibilityPublic})
+	want := &model.AlreadyExistError{
+		PropertyNameForDeveloper:    model.TitlePropertyForDeveloper,
+		PropertyNameForUser:         model.TitlePropertyForUser,
+		PropertyValue:               model.ThreadTitleForTest,
+		DomainModelNameForDeveloper: model.DomainModelNameThreadForDeveloper,
+		DomainModelNameForUser:      model.DomainModelNameThreadForUser,
+	}
+	if got := errors.Cause(err); reflect.TypeOf(got) != reflect.TypeOf(want) || got.Error() != want.Error() {
+		t.Errorf("threadRepository.InsertThread() error = %v, want %v", err, want)
+	}
+}
@@ -0,0 +1,231 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+	log "github.com/sirupsen/logrus"
+)
+
+// totpRepository is repository of TOTP and recovery codes of users.
+type totpRepository struct {
+	ctx context.Context
+}
+
+// NewTOTPRepository generates and returns TOTPRepository.
+func NewTOTPRepository(ctx context.Context) repository.TOTPRepository {
+	return &totpRepository{
+		ctx: ctx,
+	}
+}
+
+// ErrorMsg generates and returns error message.
+func (repo *totpRepository) ErrorMsg(method model.RepositoryMethod, err error) error {
+	return &model.RepositoryError{
+		BaseErr:                     err,
+		RepositoryMethod:            method,
+		DomainModelNameForDeveloper: model.DomainModelNameTOTPForDeveloper,
+		DomainModelNameForUser:      model.DomainModelNameTOTPForUser,
+	}
+}
+
+// GetTOTPByUserID gets and returns a record specified by user id.
+func (repo *totpRepository) GetTOTPByUserID(m repository.SQLManager, userID uint32) (*model.TOTP, error) {
+	query := "SELECT user_id, secret, enabled, last_used_step, created_at FROM user_totp WHERE user_id=?"
+
+	list, err := repo.list(m, model.RepositoryMethodREAD, query, userID)
+
+	if len(list) == 0 {
+		err = &model.NoSuchDataError{
+			BaseErr:                     err,
+			PropertyNameForDeveloper:    model.UserIDPropertyForDeveloper,
+			PropertyNameForUser:         model.UserIDPropertyForUser,
+			PropertyValue:               userID,
+			DomainModelNameForDeveloper: model.DomainModelNameTOTPForDeveloper,
+			DomainModelNameForUser:      model.DomainModelNameTOTPForUser,
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	if err != nil {
+		return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+
+	return list[0], nil
+}
+
+// list gets and returns list of records.
+func (repo *totpRepository) list(m repository.SQLManager, method model.RepositoryMethod, query string, args ...interface{}) (totps []*model.TOTP, err error) {
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return nil, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	rows, err := stmt.QueryContext(repo.ctx, args...)
+	if err != nil {
+		return nil, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+	defer func() {
+		err = rows.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	list := make([]*model.TOTP, 0)
+	for rows.Next() {
+		totp := &model.TOTP{}
+
+		err = rows.Scan(
+			&totp.UserID,
+			&totp.Secret,
+			&totp.Enabled,
+			&totp.LastUsedStep,
+			&totp.CreatedAt,
+		)
+
+		if err != nil {
+			return nil, repo.ErrorMsg(method, errors.WithStack(err))
+		}
+
+		list = append(list, totp)
+	}
+
+	return list, nil
+}
+
+// SaveTOTP inserts a record or replaces the record of the user.
+func (repo *totpRepository) SaveTOTP(m repository.SQLManager, totp *model.TOTP) error {
+	query := "INSERT INTO user_totp (user_id, secret, enabled, last_used_step, created_at) VALUES (?, ?, ?, ?, ?) " +
+		"ON DUPLICATE KEY UPDATE secret=VALUES(secret), enabled=VALUES(enabled), last_used_step=VALUES(last_used_step), created_at=VALUES(created_at)"
+
+	if _, err := repo.exec(m, model.RepositoryMethodInsert, query, totp.UserID, totp.Secret, totp.Enabled, totp.LastUsedStep, totp.CreatedAt); err != nil {
+		return err
+	}
+	return nil
+}
+
+// UseTOTPStep updates the last used step if the step is after it.
+func (repo *totpRepository) UseTOTPStep(m repository.SQLManager, userID uint32, step int64) error {
+	query := "UPDATE user_totp SET last_used_step=? WHERE user_id=? AND last_used_step<?"
+
+	affect, err := repo.exec(m, model.RepositoryMethodUPDATE, query, step, userID, step)
+	if err != nil {
+		return err
+	}
+
+	if affect == 0 {
+		err := &model.NoSuchDataError{
+			PropertyNameForDeveloper:    model.UserIDPropertyForDeveloper,
+			PropertyNameForUser:         model.UserIDPropertyForUser,
+			PropertyValue:               userID,
+			DomainModelNameForDeveloper: model.DomainModelNameTOTPForDeveloper,
+			DomainModelNameForUser:      model.DomainModelNameTOTPForUser,
+		}
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+// DeleteTOTP deletes the record of the user.
+func (repo *totpRepository) DeleteTOTP(m repository.SQLManager, userID uint32) error {
+	query := "DELETE FROM user_totp WHERE user_id=?"
+
+	_, err := repo.exec(m, model.RepositoryMethodDELETE, query, userID)
+	return err
+}
+
+// InsertRecoveryCodes inserts records of recovery codes of the user at once.
+func (repo *totpRepository) InsertRecoveryCodes(m repository.SQLManager, userID uint32, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	values := make([]string, 0, len(ids))
+	args := make([]interface{}, 0, len(ids)*2)
+	for _, id := range ids {
+		values = append(values, "(?, ?)")
+		args = append(args, id, userID)
+	}
+	query := "INSERT INTO totp_recovery_codes (id, user_id) VALUES " + strings.Join(values, ", ")
+
+	affect, err := repo.exec(m, model.RepositoryMethodInsert, query, args...)
+	if err != nil {
+		return err
+	}
+
+	if affect != int64(len(ids)) {
+		err = fmt.Errorf("total affected: %d ", affect)
+		return repo.ErrorMsg(model.RepositoryMethodInsert, errors.WithStack(err))
+	}
+
+	return nil
+}
+
+// DeleteRecoveryCode deletes the recovery code of the user.
+func (repo *totpRepository) DeleteRecoveryCode(m repository.SQLManager, userID uint32, id string) error {
+	query := "DELETE FROM totp_recovery_codes WHERE user_id=? AND id=?"
+
+	affect, err := repo.exec(m, model.RepositoryMethodDELETE, query, userID, id)
+	if err != nil {
+		return err
+	}
+
+	if affect == 0 {
+		err := &model.NoSuchDataError{
+			PropertyNameForDeveloper:    model.CodePropertyForDeveloper,
+			PropertyNameForUser:         model.CodePropertyForUser,
+			DomainModelNameForDeveloper: model.DomainModelNameRecoveryCodeForDeveloper,
+			DomainModelNameForUser:      model.DomainModelNameRecoveryCodeForUser,
+		}
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+// DeleteRecoveryCodesByUserID deletes all recovery codes of the user.
+func (repo *totpRepository) DeleteRecoveryCodesByUserID(m repository.SQLManager, userID uint32) error {
+	query := "DELETE FROM totp_recovery_codes WHERE user_id=?"
+
+	_, err := repo.exec(m, model.RepositoryMethodDELETE, query, userID)
+	return err
+}
+
+// exec executes the query and returns the number of affected rows.
+func (repo *totpRepository) exec(m repository.SQLManager, method model.RepositoryMethod, query string, args ...interface{}) (int64, error) {
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return 0, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	result, err := stmt.ExecContext(repo.ctx, args...)
+	if err != nil {
+		return 0, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+
+	affect, err := result.RowsAffected()
+	if err != nil {
+		return 0, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+
+	return affect, nil
+}
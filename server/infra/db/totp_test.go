@@ -0,0 +1,151 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/pkg/errors"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func Test_totpRepository_UseTOTPStep(t *testing.T) {
+	// set sqlmock
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Close()
+
+	tests := []struct {
+		name        string
+		rowAffected int64
+		err         error
+		wantErr     error
+	}{
+		{
+			name:        "When the step is after the last used one, returns nil",
+			rowAffected: 1,
+		},
+		{
+			name:        "When the step has been used, returns NoSuchDataError",
+			rowAffected: 0,
+			wantErr: &model.NoSuchDataError{
+				PropertyNameForDeveloper:    model.UserIDPropertyForDeveloper,
+				PropertyNameForUser:         model.UserIDPropertyForUser,
+				PropertyValue:               model.UserValidIDForTest,
+				DomainModelNameForDeveloper: model.DomainModelNameTOTPForDeveloper,
+				DomainModelNameForUser:      model.DomainModelNameTOTPForUser,
+			},
+		},
+		{
+			name: "when DB error has occurred、returns error",
+			err:  errors.New(model.ErrorMessageForTest),
+			wantErr: &model.RepositoryError{
+				RepositoryMethod:            model.RepositoryMethodUPDATE,
+				DomainModelNameForDeveloper: model.DomainModelNameTOTPForDeveloper,
+				DomainModelNameForUser:      model.DomainModelNameTOTPForUser,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var step int64 = 37037036
+
+			query := "UPDATE user_totp SET last_used_step=\\? WHERE user_id=\\? AND last_used_step<\\?"
+			prep := mock.ExpectPrepare(query)
+
+			if tt.err != nil {
+				prep.ExpectExec().WithArgs(step, model.UserValidIDForTest, step).WillReturnError(tt.err)
+			} else {
+				prep.ExpectExec().WithArgs(step, model.UserValidIDForTest, step).WillReturnResult(sqlmock.NewResult(0, tt.rowAffected))
+			}
+
+			repo := &totpRepository{
+				ctx: context.Background(),
+			}
+
+			err := repo.UseTOTPStep(db, model.UserValidIDForTest, step)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("totpRepository.UseTOTPStep() error = %v, wantErr nil", err)
+				}
+				return
+			}
+			if err == nil || errors.Cause(err).Error() != tt.wantErr.Error() {
+				t.Errorf("totpRepository.UseTOTPStep() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_totpRepository_DeleteRecoveryCode(t *testing.T) {
+	// set sqlmock
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Close()
+
+	id := model.RecoveryCodeIDFromCode(model.RecoveryCodeForTest)
+
+	tests := []struct {
+		name        string
+		rowAffected int64
+		err         error
+		wantErr     error
+	}{
+		{
+			name:        "When the code exists, returns nil",
+			rowAffected: 1,
+		},
+		{
+			name:        "When the code has been used, returns NoSuchDataError",
+			rowAffected: 0,
+			wantErr: &model.NoSuchDataError{
+				PropertyNameForDeveloper:    model.CodePropertyForDeveloper,
+				PropertyNameForUser:         model.CodePropertyForUser,
+				DomainModelNameForDeveloper: model.DomainModelNameRecoveryCodeForDeveloper,
+				DomainModelNameForUser:      model.DomainModelNameRecoveryCodeForUser,
+			},
+		},
+		{
+			name: "when DB error has occurred、returns error",
+			err:  errors.New(model.ErrorMessageForTest),
+			wantErr: &model.RepositoryError{
+				RepositoryMethod:            model.RepositoryMethodDELETE,
+				DomainModelNameForDeveloper: model.DomainModelNameTOTPForDeveloper,
+				DomainModelNameForUser:      model.DomainModelNameTOTPForUser,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := "DELETE FROM totp_recovery_codes WHERE user_id=\\? AND id=\\?"
+			prep := mock.ExpectPrepare(query)
+
+			if tt.err != nil {
+				prep.ExpectExec().WithArgs(model.UserValidIDForTest, id).WillReturnError(tt.err)
+			} else {
+				prep.ExpectExec().WithArgs(model.UserValidIDForTest, id).WillReturnResult(sqlmock.NewResult(0, tt.rowAffected))
+			}
+
+			repo := &totpRepository{
+				ctx: context.Background(),
+			}
+
+			err := repo.DeleteRecoveryCode(db, model.UserValidIDForTest, id)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("totpRepository.DeleteRecoveryCode() error = %v, wantErr nil", err)
+				}
+				return
+			}
+			if err == nil || errors.Cause(err).Error() != tt.wantErr.Error() {
+				t.Errorf("totpRepository.DeleteRecoveryCode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
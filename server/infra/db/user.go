@@ -2,8 +2,11 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"strings"
 
+	"github.com/go-sql-driver/mysql"
 	log "github.com/sirupsen/logrus"
 
 	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
@@ -33,7 +36,7 @@ func (repo *userRepository) ErrorMsg(method model.RepositoryMethod, err error) e
 }
 
 func (repo *userRepository) GetUserByID(m SQLManager, id uint32) (*model.User, error) {
-	query := "SELECT id, name, session_id, password, created_at, updated_at FROM users WHERE id=?"
+	query := "SELECT id, name, password, email, email_verified, disabled, created_at, updated_at FROM users WHERE id=?"
 
 	list, err := repo.list(m, model.RepositoryMethodREAD, query, id)
 
@@ -57,7 +60,7 @@ func (repo *userRepository) GetUserByID(m SQLManager, id uint32) (*model.User, e
 }
 
 func (repo *userRepository) GetUserByName(m SQLManager, name string) (*model.User, error) {
-	query := "SELECT id, name, session_id, password, created_at, updated_at FROM users WHERE name=?"
+	query := "SELECT id, name, password, email, email_verified, disabled, created_at, updated_at FROM users WHERE name=?"
 	list, err := repo.list(m, model.RepositoryMethodREAD, query, name)
 
 	if len(list) == 0 {
@@ -80,6 +83,29 @@ func (repo *userRepository) GetUserByName(m SQLManager, name string) (*model.Use
 
 }
 
+func (repo *userRepository) GetUserByEmail(m SQLManager, email string) (*model.User, error) {
+	query := "SELECT id, name, password, email, email_verified, disabled, created_at, updated_at FROM users WHERE email=?"
+	list, err := repo.list(m, model.RepositoryMethodREAD, query, email)
+
+	if len(list) == 0 {
+		err = &model.NoSuchDataError{
+			BaseErr:                     err,
+			PropertyNameForDeveloper:    model.EmailPropertyForDeveloper,
+			PropertyNameForUser:         model.EmailPropertyForUser,
+			PropertyValue:               email,
+			DomainModelNameForDeveloper: model.DomainModelNameUserForDeveloper,
+			DomainModelNameForUser:      model.DomainModelNameUserForUser,
+		}
+		return nil, err
+	}
+
+	if err != nil {
+		return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+
+	return list[0], nil
+}
+
 func (repo *userRepository) list(m SQLManager, method model.RepositoryMethod, query string, args ...interface{}) (users []*model.User, err error) {
 	stmt, err := m.PrepareContext(repo.ctx, query)
 	if err != nil {
@@ -106,12 +132,15 @@ func (repo *userRepository) list(m SQLManager, method model.RepositoryMethod, qu
 	list := make([]*model.User, 0)
 	for rows.Next() {
 		user := &model.User{}
+		var email sql.NullString
 
 		err = rows.Scan(
 			&user.ID,
 			&user.Name,
-			&user.SessionID,
 			&user.Password,
+			&email,
+			&user.EmailVerified,
+			&user.Disabled,
 			&user.CreatedAt,
 			&user.UpdatedAt,
 		)
@@ -119,6 +148,7 @@ func (repo *userRepository) list(m SQLManager, method model.RepositoryMethod, qu
 		if err != nil {
 			return nil, repo.ErrorMsg(method, errors.WithStack(err))
 		}
+		user.Email = email.String
 
 		list = append(list, user)
 	}
@@ -127,7 +157,7 @@ func (repo *userRepository) list(m SQLManager, method model.RepositoryMethod, qu
 }
 
 func (repo *userRepository) InsertUser(m SQLManager, user *model.User) (uint32, error) {
-	query := "INSERT INFO users (name, session_id, password, created_at, updated_at) VALUES (?, ?, ?, ?, ?)"
+	query := "INSERT INTO users (name, password, email, email_verified, disabled, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?)"
 	stmt, err := m.PrepareContext(repo.ctx, query)
 	if err != nil {
 		return model.InvalidID, repo.ErrorMsg(model.RepositoryMethodInsert, errors.WithStack(err))
@@ -139,8 +169,11 @@ func (repo *userRepository) InsertUser(m SQLManager, user *model.User) (uint32,
 		}
 	}()
 
-	result, err := stmt.ExecContext(repo.ctx, user.Name, user.SessionID, user.Password, user.CreatedAt, user.UpdatedAt)
+	result, err := stmt.ExecContext(repo.ctx, user.Name, user.Password, nullEmail(user.Email), user.EmailVerified, user.Disabled, user.CreatedAt, user.UpdatedAt)
 	if err != nil {
+		if isDuplicateEntry(err, "name") {
+			return model.InvalidID, errors.WithStack(repo.nameAlreadyExistError(user.Name, err))
+		}
 		return model.InvalidID, repo.ErrorMsg(model.RepositoryMethodInsert, errors.WithStack(err))
 	}
 
@@ -158,7 +191,7 @@ func (repo *userRepository) InsertUser(m SQLManager, user *model.User) (uint32,
 	return uint32(id), nil
 }
 func (repo *userRepository) UpdateUser(m SQLManager, id uint32, user *model.User) error {
-	query := "UPDATE users SET session_id=?, password=?, update_at=?, WHERE id=?"
+	query := "UPDATE users SET name=?, password=?, email=?, email_verified=?, disabled=?, updated_at=? WHERE id=?"
 
 	stmt, err := m.PrepareContext(repo.ctx, query)
 	if err != nil {
@@ -172,8 +205,11 @@ func (repo *userRepository) UpdateUser(m SQLManager, id uint32, user *model.User
 		}
 	}()
 
-	result, err := stmt.ExecContext(repo.ctx, user.SessionID, user.Password, user.UpdatedAt, id)
+	result, err := stmt.ExecContext(repo.ctx, user.Name, user.Password, nullEmail(user.Email), user.EmailVerified, user.Disabled, user.UpdatedAt, id)
 	if err != nil {
+		if isDuplicateEntry(err, "name") {
+			return errors.WithStack(repo.nameAlreadyExistError(user.Name, err))
+		}
 		return repo.ErrorMsg(model.RepositoryMethodUPDATE, errors.WithStack(err))
 	}
 
@@ -215,3 +251,34 @@ func (repo *userRepository) DeleteUser(m SQLManager, id uint32) error {
 
 	return nil
 }
+
+// nameAlreadyExistError returns AlreadyExistError of the name, which the unique key of name rejects.
+// The name is checked before inserting and updating, but concurrent requests may pass the check together.
+func (repo *userRepository) nameAlreadyExistError(name string, err error) error {
+	return &model.AlreadyExistError{
+		BaseErr:                     err,
+		PropertyNameForDeveloper:    model.NamePropertyForDeveloper,
+		PropertyNameForUser:         model.NamePropertyForUser,
+		PropertyValue:               name,
+		DomainModelNameForDeveloper: model.DomainModelNameUserForDeveloper,
+		DomainModelNameForUser:      model.DomainModelNameUserForUser,
+	}
+}
+
+// mysqlErrDupEntry is the error number of MySQL for a duplicate entry of a unique key.
+const mysqlErrDupEntry = 1062
+
+// isDuplicateEntry returns whether err is a duplicate entry of the unique key.
+// MySQL 8 qualifies the key by the table in the message, e.g. 'users.name'.
+func isDuplicateEntry(err error, key string) bool {
+	mErr, ok := errors.Cause(err).(*mysql.MySQLError)
+	if !ok || mErr.Number != mysqlErrDupEntry {
+		return false
+	}
+	return strings.HasSuffix(mErr.Message, "'"+key+"'") || strings.HasSuffix(mErr.Message, "."+key+"'")
+}
+
+// nullEmail returns NULL for empty email, so that users without email do not conflict on the unique key.
+func nullEmail(email string) sql.NullString {
+	return sql.NullString{String: email, Valid: email != ""}
+}
@@ -0,0 +1,170 @@
+package db
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+	log "github.com/sirupsen/logrus"
+)
+
+// userBlockRepository is repository of blocks between users.
+type userBlockRepository struct {
+	ctx context.Context
+}
+
+// NewUserBlockRepository generates and returns UserBlockRepository.
+func NewUserBlockRepository(ctx context.Context) repository.UserBlockRepository {
+	return &userBlockRepository{
+		ctx: ctx,
+	}
+}
+
+// ErrorMsg generates and returns error message.
+func (repo *userBlockRepository) ErrorMsg(method model.RepositoryMethod, err error) error {
+	return &model.RepositoryError{
+		BaseErr:                     err,
+		RepositoryMethod:            method,
+		DomainModelNameForDeveloper: model.DomainModelNameUserBlockForDeveloper,
+		DomainModelNameForUser:      model.DomainModelNameUserBlockForUser,
+	}
+}
+
+// GetUserBlocksBetween gets and returns records in either direction between the users.
+// This returns empty list if neither blocks the other.
+func (repo *userBlockRepository) GetUserBlocksBetween(m repository.SQLManager, userID, otherUserID uint32) ([]*model.UserBlock, error) {
+	query := "SELECT user_id, blocked_user_id, created_at FROM user_blocks " +
+		"WHERE (user_id=? AND blocked_user_id=?) OR (user_id=? AND blocked_user_id=?)"
+
+	list, err := repo.list(m, model.RepositoryMethodREAD, query, userID, otherUserID, otherUserID, userID)
+	if err != nil {
+		return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+
+	return list, nil
+}
+
+// GetUserBlocksByUserID gets and returns records by the user in order of blocking.
+// This returns empty list if the user blocks no one.
+func (repo *userBlockRepository) GetUserBlocksByUserID(m repository.SQLManager, userID uint32) ([]*model.UserBlock, error) {
+	query := "SELECT user_id, blocked_user_id, created_at FROM user_blocks WHERE user_id=? ORDER BY created_at, blocked_user_id"
+
+	list, err := repo.list(m, model.RepositoryMethodREAD, query, userID)
+	if err != nil {
+		return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+
+	return list, nil
+}
+
+// list gets and returns list of records.
+func (repo *userBlockRepository) list(m repository.SQLManager, method model.RepositoryMethod, query string, args ...interface{}) (blocks []*model.UserBlock, err error) {
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return nil, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	rows, err := stmt.QueryContext(repo.ctx, args...)
+	if err != nil {
+		return nil, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+	defer func() {
+		err = rows.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	list := make([]*model.UserBlock, 0)
+	for rows.Next() {
+		block := &model.UserBlock{}
+
+		err = rows.Scan(
+			&block.UserID,
+			&block.BlockedUserID,
+			&block.CreatedAt,
+		)
+
+		if err != nil {
+			return nil, repo.ErrorMsg(method, errors.WithStack(err))
+		}
+
+		list = append(list, block)
+	}
+
+	return list, nil
+}
+
+// InsertUserBlock insert a record.
+// This does nothing if the user already blocks the other, so that blocking is idempotent.
+func (repo *userBlockRepository) InsertUserBlock(m repository.SQLManager, block *model.UserBlock) error {
+	query := "INSERT IGNORE INTO user_blocks (user_id, blocked_user_id, created_at) VALUES (?, ?, ?)"
+
+	_, err := repo.exec(m, model.RepositoryMethodInsert, query, block.UserID, block.BlockedUserID, block.CreatedAt)
+	return err
+}
+
+// DeleteUserBlock delete a record.
+func (repo *userBlockRepository) DeleteUserBlock(m repository.SQLManager, userID, blockedUserID uint32) error {
+	query := "DELETE FROM user_blocks WHERE user_id=? AND blocked_user_id=?"
+
+	affect, err := repo.exec(m, model.RepositoryMethodDELETE, query, userID, blockedUserID)
+	if err != nil {
+		return err
+	}
+
+	if affect == 0 {
+		err := &model.NoSuchDataError{
+			PropertyNameForDeveloper:    model.UserIDPropertyForDeveloper,
+			PropertyNameForUser:         model.UserIDPropertyForUser,
+			PropertyValue:               blockedUserID,
+			DomainModelNameForDeveloper: model.DomainModelNameUserBlockForDeveloper,
+			DomainModelNameForUser:      model.DomainModelNameUserBlockForUser,
+		}
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+// DeleteUserBlocksByUserID deletes all records by and against the user.
+func (repo *userBlockRepository) DeleteUserBlocksByUserID(m repository.SQLManager, userID uint32) error {
+	query := "DELETE FROM user_blocks WHERE user_id=? OR blocked_user_id=?"
+
+	_, err := repo.exec(m, model.RepositoryMethodDELETE, query, userID, userID)
+	return err
+}
+
+// exec executes the query and returns the number of affected rows.
+func (repo *userBlockRepository) exec(m repository.SQLManager, method model.RepositoryMethod, query string, args ...interface{}) (int64, error) {
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return 0, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	result, err := stmt.ExecContext(repo.ctx, args...)
+	if err != nil {
+		return 0, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+
+	affect, err := result.RowsAffected()
+	if err != nil {
+		return 0, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+
+	return affect, nil
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/go-sql-driver/mysql"
 	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
 	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
 	"github.com/hideUW/nuxt-go-chat-app/server/testutil"
@@ -126,7 +127,6 @@ func Test_userRepository_GetUserByID(t *testing.T) {
 			want: &model.User{
 				ID:        model.UserValidIDForTest,
 				Name:      model.UserNameForTest,
-				SessionID: model.SessionValidIDForTest,
 				Password:  model.PasswordForTest,
 				CreatedAt: testutil.TimeNow(),
 				UpdatedAt: testutil.TimeNow(),
@@ -154,14 +154,14 @@ func Test_userRepository_GetUserByID(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			q := "SELECT id, name, session_id, password, created_at, updated_at FROM users WHERE id=?"
+			q := "SELECT id, name, password, email, email_verified, disabled, created_at, updated_at FROM users WHERE id=?"
 			prep := mock.ExpectPrepare(q)
 
 			if tt.wantErr != nil {
 				prep.ExpectQuery().WillReturnError(tt.wantErr)
 			} else {
-				rows := sqlmock.NewRows([]string{"id", "name", "session_id", "password", "created_at", "updated_at"}).
-					AddRow(tt.want.ID, tt.want.Name, tt.want.SessionID, tt.want.Password, tt.want.CreatedAt, tt.want.UpdatedAt)
+				rows := sqlmock.NewRows([]string{"id", "name", "password", "email", "email_verified", "disabled", "created_at", "updated_at"}).
+					AddRow(tt.want.ID, tt.want.Name, tt.want.Password, nil, tt.want.EmailVerified, tt.want.Disabled, tt.want.CreatedAt, tt.want.UpdatedAt)
 				prep.ExpectQuery().WithArgs(tt.want.ID).WillReturnRows(rows)
 			}
 
@@ -220,7 +220,6 @@ func Test_userRepository_GetUserByName(t *testing.T) {
 			},
 			want: &model.User{
 				Name:      model.UserNameForTest,
-				SessionID: model.SessionValidIDForTest,
 				Password:  model.PasswordForTest,
 				CreatedAt: testutil.TimeNow(),
 				UpdatedAt: testutil.TimeNow(),
@@ -249,14 +248,14 @@ func Test_userRepository_GetUserByName(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			q := "SELECT id, name, session_id, password, created_at, updated_at FROM users WHERE name=?"
+			q := "SELECT id, name, password, email, email_verified, disabled, created_at, updated_at FROM users WHERE name=?"
 			prep := mock.ExpectPrepare(q)
 
 			if tt.wantErr != nil {
 				prep.ExpectQuery().WillReturnError(tt.wantErr)
 			} else {
-				rows := sqlmock.NewRows([]string{"id", "name", "session_id", "password", "created_at", "updated_at"}).
-					AddRow(tt.want.ID, tt.want.Name, tt.want.SessionID, tt.want.Password, tt.want.CreatedAt, tt.want.UpdatedAt)
+				rows := sqlmock.NewRows([]string{"id", "name", "password", "email", "email_verified", "disabled", "created_at", "updated_at"}).
+					AddRow(tt.want.ID, tt.want.Name, tt.want.Password, nil, tt.want.EmailVerified, tt.want.Disabled, tt.want.CreatedAt, tt.want.UpdatedAt)
 				prep.ExpectQuery().WithArgs(tt.want.Name).WillReturnRows(rows)
 			}
 
@@ -314,7 +313,6 @@ func Test_userRepository_InsertUser(t *testing.T) {
 				user: &model.User{
 					ID:        model.UserValidIDForTest,
 					Name:      model.UserNameForTest,
-					SessionID: model.SessionValidIDForTest,
 					Password:  model.PasswordForTest,
 					CreatedAt: testutil.TimeNow(),
 					UpdatedAt: testutil.TimeNow(),
@@ -333,7 +331,6 @@ func Test_userRepository_InsertUser(t *testing.T) {
 				user: &model.User{
 					ID:        model.UserInValidIDForTest,
 					Name:      model.UserNameForTest,
-					SessionID: model.SessionValidIDForTest,
 					Password:  model.PasswordForTest,
 					CreatedAt: testutil.TimeNow(),
 					UpdatedAt: testutil.TimeNow(),
@@ -356,7 +353,6 @@ func Test_userRepository_InsertUser(t *testing.T) {
 				user: &model.User{
 					ID:        model.UserInValidIDForTest,
 					Name:      model.UserNameForTest,
-					SessionID: model.SessionValidIDForTest,
 					Password:  model.PasswordForTest,
 					CreatedAt: testutil.TimeNow(),
 					UpdatedAt: testutil.TimeNow(),
@@ -379,7 +375,6 @@ func Test_userRepository_InsertUser(t *testing.T) {
 				user: &model.User{
 					ID:        model.UserInValidIDForTest,
 					Name:      model.UserNameForTest,
-					SessionID: model.SessionValidIDForTest,
 					Password:  model.PasswordForTest,
 					CreatedAt: testutil.TimeNow(),
 					UpdatedAt: testutil.TimeNow(),
@@ -400,9 +395,9 @@ func Test_userRepository_InsertUser(t *testing.T) {
 			prep := mock.ExpectPrepare(query)
 
 			if tt.args.err != nil {
-				prep.ExpectExec().WithArgs(tt.args.user.ID, tt.args.user.Name, tt.args.user.SessionID, tt.args.user.Password, tt.args.user.CreatedAt, tt.args.user.UpdatedAt).WillReturnError(tt.args.err)
+				prep.ExpectExec().WithArgs(tt.args.user.ID, tt.args.user.Name, tt.args.user.Password, tt.args.user.CreatedAt, tt.args.user.UpdatedAt).WillReturnError(tt.args.err)
 			} else {
-				prep.ExpectExec().WithArgs(tt.args.user.ID, tt.args.user.Name, tt.args.user.SessionID, tt.args.user.Password, tt.args.user.CreatedAt, tt.args.user.UpdatedAt).WillReturnResult(sqlmock.NewResult(1, tt.rowAffected))
+				prep.ExpectExec().WithArgs(tt.args.user.ID, tt.args.user.Name, tt.args.user.Password, tt.args.user.CreatedAt, tt.args.user.UpdatedAt).WillReturnResult(sqlmock.NewResult(1, tt.rowAffected))
 			}
 
 			repo := &userRepository{
@@ -456,7 +451,6 @@ func Test_userRepository_UpdateUser(t *testing.T) {
 				user: &model.User{
 					ID:        model.UserValidIDForTest,
 					Name:      model.UserNameForTest,
-					SessionID: model.SessionValidIDForTest,
 					Password:  model.PasswordForTest,
 					CreatedAt: testutil.TimeNow(),
 					UpdatedAt: testutil.TimeNow(),
@@ -476,7 +470,6 @@ func Test_userRepository_UpdateUser(t *testing.T) {
 				user: &model.User{
 					ID:        model.UserInValidIDForTest,
 					Name:      model.UserNameForTest,
-					SessionID: model.SessionValidIDForTest,
 					Password:  model.PasswordForTest,
 					CreatedAt: testutil.TimeNow(),
 					UpdatedAt: testutil.TimeNow(),
@@ -500,7 +493,6 @@ func Test_userRepository_UpdateUser(t *testing.T) {
 				user: &model.User{
 					ID:        model.UserInValidIDForTest,
 					Name:      model.UserNameForTest,
-					SessionID: model.SessionValidIDForTest,
 					Password:  model.PasswordForTest,
 					CreatedAt: testutil.TimeNow(),
 					UpdatedAt: testutil.TimeNow(),
@@ -524,7 +516,6 @@ func Test_userRepository_UpdateUser(t *testing.T) {
 				user: &model.User{
 					ID:        model.UserInValidIDForTest,
 					Name:      model.UserNameForTest,
-					SessionID: model.SessionValidIDForTest,
 					Password:  model.PasswordForTest,
 					CreatedAt: testutil.TimeNow(),
 					UpdatedAt: testutil.TimeNow(),
@@ -541,13 +532,13 @@ func Test_userRepository_UpdateUser(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			query := "UPDATE users SET session_id=\\?, password=\\?, created_at=\\?, updated_at=\\? WHERE id=\\?"
+			query := "UPDATE users SET name=\\?, password=\\?, email=\\?, email_verified=\\?, disabled=\\?, updated_at=\\? WHERE id=\\?"
 			prep := mock.ExpectPrepare(query)
 
 			if tt.args.err != nil {
-				prep.ExpectExec().WithArgs(tt.args.user.SessionID, tt.args.user.Password, tt.args.user.CreatedAt, tt.args.user.UpdatedAt, tt.args.id).WillReturnError(tt.args.err)
+				prep.ExpectExec().WithArgs(tt.args.user.Name, tt.args.user.Password, nil, tt.args.user.EmailVerified, tt.args.user.Disabled, tt.args.user.UpdatedAt, tt.args.id).WillReturnError(tt.args.err)
 			} else {
-				prep.ExpectExec().WithArgs(tt.args.user.SessionID, tt.args.user.Password, tt.args.user.CreatedAt, tt.args.user.UpdatedAt, tt.args.id).WillReturnResult(sqlmock.NewResult(1, tt.rowAffected))
+				prep.ExpectExec().WithArgs(tt.args.user.Name, tt.args.user.Password, nil, tt.args.user.EmailVerified, tt.args.user.Disabled, tt.args.user.UpdatedAt, tt.args.id).WillReturnResult(sqlmock.NewResult(1, tt.rowAffected))
 			}
 
 			repo := &userRepository{
@@ -675,3 +666,68 @@ func Test_userRepository_DeleteUser(t *testing.T) {
 		})
 	}
 }
+
+func Test_userRepository_InsertUser_duplicateName(t *testing.T) {
+	// set sqlmock
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Close()
+
+	tests := []struct {
+		name    string
+		err     error
+		wantErr error
+	}{
+		{
+			name: "When the name is duplicate on MySQL 8, returns AlreadyExistError",
+			err:  &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'testUserName' for key 'users.name'"},
+			wantErr: &model.AlreadyExistError{
+				PropertyNameForDeveloper:    model.NamePropertyForDeveloper,
+				PropertyNameForUser:         model.NamePropertyForUser,
+				PropertyValue:               model.UserNameForTest,
+				DomainModelNameForDeveloper: model.DomainModelNameUserForDeveloper,
+				DomainModelNameForUser:      model.DomainModelNameUserForUser,
+			},
+		},
+		{
+			name: "When the name is duplicate on MySQL 5.7, returns AlreadyExistError",
+			err:  &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'testUserName' for key 'name'"},
+			wantErr: &model.AlreadyExistError{
+				PropertyNameForDeveloper:    model.NamePropertyForDeveloper,
+				PropertyNameForUser:         model.NamePropertyForUser,
+				PropertyValue:               model.UserNameForTest,
+				DomainModelNameForDeveloper: model.DomainModelNameUserForDeveloper,
+				DomainModelNameForUser:      model.DomainModelNameUserForUser,
+			},
+		},
+		{
+			name: "When the email is duplicate, returns RepositoryError",
+			err:  &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'test@example.com' for key 'users.email'"},
+			wantErr: &model.RepositoryError{
+				RepositoryMethod:            model.RepositoryMethodInsert,
+				DomainModelNameForDeveloper: model.DomainModelNameUserForDeveloper,
+				DomainModelNameForUser:      model.DomainModelNameUserForUser,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectPrepare("INSERT INTO users").ExpectExec().WillReturnError(tt.err)
+
+			repo := &userRepository{
+				ctx: context.Background(),
+			}
+
+			_, err := repo.InsertUser(db, &model.User{Name: model.UserNameForTest, Password: model.PasswordForTest})
+			if err == nil {
+				t.Fatal("userRepository.InsertUser() should return error")
+			}
+			if reflect.TypeOf(errors.Cause(err)) != reflect.TypeOf(tt.wantErr) || errors.Cause(err).Error() != tt.wantErr.Error() {
+				t.Errorf("userRepository.InsertUser() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
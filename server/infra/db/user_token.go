@@ -0,0 +1,190 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+	log "github.com/sirupsen/logrus"
+)
+
+// userTokenRepository is repository of tokens sent to users by email.
+type userTokenRepository struct {
+	ctx context.Context
+}
+
+// NewUserTokenRepository generates and returns UserTokenRepository.
+func NewUserTokenRepository(ctx context.Context) repository.UserTokenRepository {
+	return &userTokenRepository{
+		ctx: ctx,
+	}
+}
+
+// ErrorMsg generates and returns error message.
+func (repo *userTokenRepository) ErrorMsg(method model.RepositoryMethod, err error) error {
+	return &model.RepositoryError{
+		BaseErr:                     err,
+		RepositoryMethod:            method,
+		DomainModelNameForDeveloper: model.DomainModelNameUserTokenForDeveloper,
+		DomainModelNameForUser:      model.DomainModelNameUserTokenForUser,
+	}
+}
+
+// GetUserToken gets and returns a record specified by id.
+func (repo *userTokenRepository) GetUserToken(m repository.SQLManager, id string) (*model.UserToken, error) {
+	query := "SELECT id, user_id, purpose, email, created_at, expires_at FROM user_tokens WHERE id=?"
+
+	list, err := repo.list(m, model.RepositoryMethodREAD, query, id)
+
+	if len(list) == 0 {
+		err = &model.NoSuchDataError{
+			BaseErr:                     err,
+			PropertyNameForDeveloper:    model.IDPropertyForDeveloper,
+			PropertyNameForUser:         model.IDPropertyForUser,
+			PropertyValue:               id,
+			DomainModelNameForDeveloper: model.DomainModelNameUserTokenForDeveloper,
+			DomainModelNameForUser:      model.DomainModelNameUserTokenForUser,
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	if err != nil {
+		return nil, repo.ErrorMsg(model.RepositoryMethodREAD, errors.WithStack(err))
+	}
+
+	return list[0], nil
+}
+
+// list gets and returns list of records.
+func (repo *userTokenRepository) list(m repository.SQLManager, method model.RepositoryMethod, query string, args ...interface{}) (tokens []*model.UserToken, err error) {
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return nil, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	rows, err := stmt.QueryContext(repo.ctx, args...)
+	if err != nil {
+		return nil, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+	defer func() {
+		err = rows.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	list := make([]*model.UserToken, 0)
+	for rows.Next() {
+		token := &model.UserToken{}
+		var purpose string
+
+		err = rows.Scan(
+			&token.ID,
+			&token.UserID,
+			&purpose,
+			&token.Email,
+			&token.CreatedAt,
+			&token.ExpiresAt,
+		)
+
+		if err != nil {
+			return nil, repo.ErrorMsg(method, errors.WithStack(err))
+		}
+		token.Purpose = model.UserTokenPurpose(purpose)
+
+		list = append(list, token)
+	}
+
+	return list, nil
+}
+
+// InsertUserToken insert a record.
+func (repo *userTokenRepository) InsertUserToken(m repository.SQLManager, token *model.UserToken) error {
+	query := "INSERT INTO user_tokens (id, user_id, purpose, email, created_at, expires_at) VALUES (?, ?, ?, ?, ?, ?)"
+
+	affect, err := repo.exec(m, model.RepositoryMethodInsert, query, token.ID, token.UserID, token.Purpose.String(), token.Email, token.CreatedAt, token.ExpiresAt)
+	if err != nil {
+		return err
+	}
+
+	if affect != 1 {
+		err = fmt.Errorf("total affected: %d ", affect)
+		return repo.ErrorMsg(model.RepositoryMethodInsert, errors.WithStack(err))
+	}
+
+	return nil
+}
+
+// DeleteUserToken deletes a record and returns NoSuchDataError if it does not exist.
+func (repo *userTokenRepository) DeleteUserToken(m repository.SQLManager, id string) error {
+	query := "DELETE FROM user_tokens WHERE id=?"
+
+	affect, err := repo.exec(m, model.RepositoryMethodDELETE, query, id)
+	if err != nil {
+		return err
+	}
+
+	if affect == 0 {
+		err := &model.NoSuchDataError{
+			PropertyNameForDeveloper:    model.IDPropertyForDeveloper,
+			PropertyNameForUser:         model.IDPropertyForUser,
+			PropertyValue:               id,
+			DomainModelNameForDeveloper: model.DomainModelNameUserTokenForDeveloper,
+			DomainModelNameForUser:      model.DomainModelNameUserTokenForUser,
+		}
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+// DeleteUserTokensByPurpose deletes all records of the user for the purpose.
+func (repo *userTokenRepository) DeleteUserTokensByPurpose(m repository.SQLManager, userID uint32, purpose model.UserTokenPurpose) error {
+	query := "DELETE FROM user_tokens WHERE user_id=? AND purpose=?"
+
+	_, err := repo.exec(m, model.RepositoryMethodDELETE, query, userID, purpose.String())
+	return err
+}
+
+// DeleteUserTokensByUserID deletes all records of the user.
+func (repo *userTokenRepository) DeleteUserTokensByUserID(m repository.SQLManager, userID uint32) error {
+	query := "DELETE FROM user_tokens WHERE user_id=?"
+
+	_, err := repo.exec(m, model.RepositoryMethodDELETE, query, userID)
+	return err
+}
+
+// exec executes the query and returns the number of affected rows.
+func (repo *userTokenRepository) exec(m repository.SQLManager, method model.RepositoryMethod, query string, args ...interface{}) (int64, error) {
+	stmt, err := m.PrepareContext(repo.ctx, query)
+	if err != nil {
+		return 0, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+	defer func() {
+		err = stmt.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	result, err := stmt.ExecContext(repo.ctx, args...)
+	if err != nil {
+		return 0, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+
+	affect, err := result.RowsAffected()
+	if err != nil {
+		return 0, repo.ErrorMsg(method, errors.WithStack(err))
+	}
+
+	return affect, nil
+}
@@ -0,0 +1,57 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/service"
+	"github.com/hideUW/nuxt-go-chat-app/server/util"
+)
+
+// fileMailerFrom is the sender of mails written to files.
+const fileMailerFrom = "noreply@localhost"
+
+// fileMailer writes mails to files instead of sending them, for development without SMTP server.
+// Each mail is written as a .eml file, which can be opened by mail clients.
+type fileMailer struct {
+	dir string
+	now func() time.Time
+}
+
+// NewFileMailer generates and returns Mailer which writes mails to files in dir.
+func NewFileMailer(dir string) (service.Mailer, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, errors.Wrap(err, "failed to make directory of mails")
+	}
+	return &fileMailer{
+		dir: dir,
+		now: time.Now,
+	}, nil
+}
+
+// Send writes the mail to a new file.
+func (m *fileMailer) Send(ctx context.Context, mail *model.Mail) error {
+	now := m.now()
+	msg, err := message(fileMailerFrom, mail, now)
+	if err != nil {
+		return err
+	}
+
+	suffix, err := util.RandomToken(6)
+	if err != nil {
+		return errors.Wrap(err, "failed to generate name of file")
+	}
+
+	name := filepath.Join(m.dir, fmt.Sprintf("%s-%s.eml", now.Format("20060102T150405"), suffix))
+	if err := ioutil.WriteFile(name, msg, 0600); err != nil {
+		return errors.Wrap(err, "failed to write mail")
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io/ioutil"
+	"mime"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/testutil"
+)
+
+// mailForTest has non-ASCII subject and a body longer than a line of base64.
+var mailForTest = &model.Mail{
+	To:      model.EmailForTest,
+	Subject: "メールアドレスの確認",
+	Body:    "以下のURLを開いてメールアドレスを確認してください。\nhttp://localhost:8080/verify_email?token=" + model.UserTokenForTest,
+}
+
+// parseMessage parses the message and returns the mail in it.
+func parseMessage(t *testing.T, msg []byte) *model.Mail {
+	t.Helper()
+
+	parsed, err := mail.ReadMessage(bytes.NewReader(msg))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoded, err := ioutil.ReadAll(parsed.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := base64.StdEncoding.DecodeString(strings.Replace(string(encoded), "\r\n", "", -1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &model.Mail{
+		To:      parsed.Header.Get("To"),
+		Subject: subject,
+		Body:    string(body),
+	}
+}
+
+func Test_message(t *testing.T) {
+	tests := []struct {
+		name    string
+		mail    *model.Mail
+		wantErr bool
+	}{
+		{
+			name: "When the mail is valid, returns message which has the same mail",
+			mail: mailForTest,
+		},
+		{
+			name:    "When the subject has line break, returns error",
+			mail:    &model.Mail{To: model.EmailForTest, Subject: "subject\r\nBcc: attacker@example.com"},
+			wantErr: true,
+		},
+		{
+			name:    "When the recipient has line break, returns error",
+			mail:    &model.Mail{To: model.EmailForTest + "\nBcc: attacker@example.com", Subject: "subject"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := message("noreply@example.com", tt.mail, time.Now())
+			if tt.wantErr {
+				if err == nil {
+					t.Error("message() error = nil, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("message() error = %v", err)
+			}
+
+			if got := parseMessage(t, msg); *got != *tt.mail {
+				testutil.Errorf(t, tt.mail, got)
+			}
+		})
+	}
+}
+
+func Test_smtpMailer_Send(t *testing.T) {
+	var gotAddr, gotFrom string
+	var gotTo []string
+	var gotAuth smtp.Auth
+	var gotMsg []byte
+
+	m := NewSMTPMailer(SMTPConfig{
+		Host:     "smtp.example.com",
+		Port:     587,
+		Username: "testUser",
+		Password: "testPassword",
+		From:     "noreply@example.com",
+	}).(*smtpMailer)
+	m.send = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		gotAddr, gotAuth, gotFrom, gotTo, gotMsg = addr, a, from, to, msg
+		return nil
+	}
+
+	if err := m.Send(context.Background(), mailForTest); err != nil {
+		t.Fatal(err)
+	}
+
+	if gotAddr != "smtp.example.com:587" || gotFrom != "noreply@example.com" || len(gotTo) != 1 || gotTo[0] != model.EmailForTest {
+		t.Errorf("unexpected envelope: addr = %s, from = %s, to = %v", gotAddr, gotFrom, gotTo)
+	}
+	if gotAuth == nil {
+		t.Error("auth should be set when username is given")
+	}
+	if got := parseMessage(t, gotMsg); *got != *mailForTest {
+		testutil.Errorf(t, mailForTest, got)
+	}
+}
+
+func Test_fileMailer_Send(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	m, err := NewFileMailer(filepath.Join(dir, "mails"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := m.Send(context.Background(), mailForTest); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "mails", "*.eml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("%d files are written, want 2", len(files))
+	}
+
+	msg, err := ioutil.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := parseMessage(t, msg); *got != *mailForTest {
+		testutil.Errorf(t, mailForTest, got)
+	}
+}
@@ -0,0 +1,39 @@
+package mail
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+)
+
+// MemoryMailer keeps mails in memory instead of sending them, so that tests can read them.
+type MemoryMailer struct {
+	mu    sync.Mutex
+	mails []*model.Mail
+}
+
+// NewMemoryMailer generates and returns MemoryMailer.
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+// Send keeps the mail.
+func (m *MemoryMailer) Send(ctx context.Context, mail *model.Mail) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sent := *mail
+	m.mails = append(m.mails, &sent)
+	return nil
+}
+
+// Mails returns mails sent so far in order.
+func (m *MemoryMailer) Mails() []*model.Mail {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	mails := make([]*model.Mail, len(m.mails))
+	copy(mails, m.mails)
+	return mails
+}
@@ -0,0 +1,46 @@
+// Package mail implements service.Mailer by SMTP, files and memory.
+package mail
+
+import (
+	"bytes"
+	"encoding/base64"
+	"mime"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+)
+
+// lineLength is the max length of a line of base64 body, which is the limit of RFC 2045.
+const lineLength = 76
+
+// message builds the message of the mail in RFC 5322 with UTF-8 body in base64.
+// Headers must not have line breaks, so that headers can not be injected by the recipient or the subject.
+func message(from string, mail *model.Mail, now time.Time) ([]byte, error) {
+	for _, v := range []string{from, mail.To, mail.Subject} {
+		if strings.ContainsAny(v, "\r\n") {
+			return nil, errors.New("header of mail should not have line break")
+		}
+	}
+
+	var b bytes.Buffer
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + mail.To + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("UTF-8", mail.Subject) + "\r\n")
+	b.WriteString("Date: " + now.Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: base64\r\n")
+	b.WriteString("\r\n")
+
+	body := base64.StdEncoding.EncodeToString([]byte(mail.Body))
+	for len(body) > lineLength {
+		b.WriteString(body[:lineLength] + "\r\n")
+		body = body[lineLength:]
+	}
+	b.WriteString(body + "\r\n")
+
+	return b.Bytes(), nil
+}
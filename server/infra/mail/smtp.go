@@ -0,0 +1,60 @@
+package mail
+
+import (
+	"context"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/service"
+)
+
+// SMTPConfig is the configuration of SMTP server.
+// Username is empty if the server does not require authentication.
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// smtpMailer sends mails by SMTP.
+// STARTTLS is used if the server supports it, and authentication is sent only over TLS or to localhost by net/smtp.
+type smtpMailer struct {
+	config SMTPConfig
+	send   func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+	now    func() time.Time
+}
+
+// NewSMTPMailer generates and returns Mailer which sends mails by SMTP.
+func NewSMTPMailer(config SMTPConfig) service.Mailer {
+	return &smtpMailer{
+		config: config,
+		send:   smtp.SendMail,
+		now:    time.Now,
+	}
+}
+
+// Send sends the mail.
+func (m *smtpMailer) Send(ctx context.Context, mail *model.Mail) error {
+	msg, err := message(m.config.From, mail, m.now())
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if m.config.Username != "" {
+		auth = smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+	}
+
+	addr := net.JoinHostPort(m.config.Host, strconv.Itoa(m.config.Port))
+	if err := m.send(addr, auth, m.config.From, []string{mail.To}, msg); err != nil {
+		return errors.Wrap(err, "failed to send mail by SMTP")
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+	"github.com/pkg/errors"
+)
+
+// oidcLoginRepository is the in-memory repository of login in progress.
+// This is only shared in a process, so that the callback must reach the server which started the login.
+type oidcLoginRepository struct {
+	mu     sync.Mutex
+	logins map[string]*model.OIDCLogin
+	writes int
+	now    func() time.Time
+}
+
+// NewOIDCLoginRepository generates and returns OIDCLoginRepository.
+func NewOIDCLoginRepository() repository.OIDCLoginRepository {
+	return &oidcLoginRepository{
+		logins: make(map[string]*model.OIDCLogin),
+		now:    time.Now,
+	}
+}
+
+// InsertOIDCLogin stores the login.
+func (repo *oidcLoginRepository) InsertOIDCLogin(login *model.OIDCLogin) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	stored := *login
+	stored.Token = ""
+	repo.logins[login.ID] = &stored
+
+	repo.afterWrite()
+	return nil
+}
+
+// TakeOIDCLogin gets and deletes the login specified by id.
+// Expired login is returned as it is, and the caller checks its expiry.
+func (repo *oidcLoginRepository) TakeOIDCLogin(id string) (*model.OIDCLogin, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	login, ok := repo.logins[id]
+	if !ok {
+		return nil, errors.WithStack(&model.NoSuchDataError{
+			PropertyNameForDeveloper:    model.IDPropertyForDeveloper,
+			PropertyNameForUser:         model.IDPropertyForUser,
+			PropertyValue:               id,
+			DomainModelNameForDeveloper: model.DomainModelNameOIDCLoginForDeveloper,
+			DomainModelNameForUser:      model.DomainModelNameOIDCLoginForUser,
+		})
+	}
+	delete(repo.logins, id)
+
+	return login, nil
+}
+
+// afterWrite sweeps expired logins once in a while so that abandoned logins do not stay.
+// This must be called with lock held.
+func (repo *oidcLoginRepository) afterWrite() {
+	repo.writes++
+	if repo.writes < sweepInterval {
+		return
+	}
+	repo.writes = 0
+
+	now := repo.now()
+	for id, login := range repo.logins {
+		if login.IsExpired(now) {
+			delete(repo.logins, id)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+	"github.com/pkg/errors"
+)
+
+// pendingLoginRepository is the in-memory repository of login which waits for the second factor.
+// This is only shared in a process, so that the second step must reach the server which handled the first.
+type pendingLoginRepository struct {
+	mu     sync.Mutex
+	logins map[string]*model.PendingLogin
+	writes int
+	now    func() time.Time
+}
+
+// NewPendingLoginRepository generates and returns PendingLoginRepository.
+func NewPendingLoginRepository() repository.PendingLoginRepository {
+	return &pendingLoginRepository{
+		logins: make(map[string]*model.PendingLogin),
+		now:    time.Now,
+	}
+}
+
+// InsertPendingLogin stores the login.
+func (repo *pendingLoginRepository) InsertPendingLogin(login *model.PendingLogin) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	stored := *login
+	stored.Token = ""
+	repo.logins[login.ID] = &stored
+
+	repo.afterWrite()
+	return nil
+}
+
+// TakePendingLogin gets and deletes the login specified by id.
+// Expired login is returned as it is, and the caller checks its expiry.
+func (repo *pendingLoginRepository) TakePendingLogin(id string) (*model.PendingLogin, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	login, ok := repo.logins[id]
+	if !ok {
+		return nil, errors.WithStack(&model.NoSuchDataError{
+			PropertyNameForDeveloper:    model.IDPropertyForDeveloper,
+			PropertyNameForUser:         model.IDPropertyForUser,
+			PropertyValue:               id,
+			DomainModelNameForDeveloper: model.DomainModelNamePendingLoginForDeveloper,
+			DomainModelNameForUser:      model.DomainModelNamePendingLoginForUser,
+		})
+	}
+	delete(repo.logins, id)
+
+	return login, nil
+}
+
+// afterWrite sweeps expired logins once in a while so that abandoned logins do not stay.
+// This must be called with lock held.
+func (repo *pendingLoginRepository) afterWrite() {
+	repo.writes++
+	if repo.writes < sweepInterval {
+		return
+	}
+	repo.writes = 0
+
+	now := repo.now()
+	for id, login := range repo.logins {
+		if login.IsExpired(now) {
+			delete(repo.logins, id)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+	"github.com/pkg/errors"
+)
+
+// typingKey is the key of typing, which is the user in the thread.
+type typingKey struct {
+	threadID uint32
+	userID   uint32
+}
+
+// presenceRepository is the in-memory repository of presence and typing.
+// This is only shared in a process.
+type presenceRepository struct {
+	mu      sync.Mutex
+	records map[uint32]*model.PresenceRecord
+	typings map[typingKey]*model.Typing
+	writes  int
+}
+
+// NewPresenceRepository generates and returns PresenceRepository.
+func NewPresenceRepository() repository.PresenceRepository {
+	return &presenceRepository{
+		records: make(map[uint32]*model.PresenceRecord),
+		typings: make(map[typingKey]*model.Typing),
+	}
+}
+
+// GetPresenceRecord gets and returns a record of the user.
+func (repo *presenceRepository) GetPresenceRecord(userID uint32) (*model.PresenceRecord, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	r, ok := repo.records[userID]
+	if !ok {
+		return nil, errors.WithStack(&model.NoSuchDataError{
+			PropertyNameForDeveloper:    model.UserIDPropertyForDeveloper,
+			PropertyNameForUser:         model.UserIDPropertyForUser,
+			PropertyValue:               userID,
+			DomainModelNameForDeveloper: model.DomainModelNamePresenceForDeveloper,
+			DomainModelNameForUser:      model.DomainModelNamePresenceForUser,
+		})
+	}
+
+	copied := *r
+	return &copied, nil
+}
+
+// GetPresenceRecordsByUserIDs gets and returns records of the users which are not expired.
+func (repo *presenceRepository) GetPresenceRecordsByUserIDs(userIDs []uint32, now time.Time) ([]*model.PresenceRecord, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	list := make([]*model.PresenceRecord, 0, len(userIDs))
+	for _, id := range userIDs {
+		r, ok := repo.records[id]
+		if !ok || r.IsExpired(now) {
+			continue
+		}
+		copied := *r
+		list = append(list, &copied)
+	}
+
+	return list, nil
+}
+
+// PutPresenceRecord stores the record of the user, which replaces the existing one.
+func (repo *presenceRepository) PutPresenceRecord(record *model.PresenceRecord) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	copied := *record
+	repo.records[record.UserID] = &copied
+
+	repo.afterWrite(record.LastHeartbeatAt)
+	return nil
+}
+
+// DeletePresenceRecord deletes a record of the user.
+func (repo *presenceRepository) DeletePresenceRecord(userID uint32) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	delete(repo.records, userID)
+	return nil
+}
+
+// GetTyping gets and returns typing of the user in the thread.
+func (repo *presenceRepository) GetTyping(threadID, userID uint32) (*model.Typing, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	t, ok := repo.typings[typingKey{threadID: threadID, userID: userID}]
+	if !ok {
+		return nil, errors.WithStack(&model.NoSuchDataError{
+			PropertyNameForDeveloper:    model.UserIDPropertyForDeveloper,
+			PropertyNameForUser:         model.UserIDPropertyForUser,
+			PropertyValue:               userID,
+			DomainModelNameForDeveloper: model.DomainModelNameTypingForDeveloper,
+			DomainModelNameForUser:      model.DomainModelNameTypingForUser,
+		})
+	}
+
+	copied := *t
+	return &copied, nil
+}
+
+// GetTypingsByThreadID gets and returns typings in the thread which are not expired in order of starting.
+func (repo *presenceRepository) GetTypingsByThreadID(threadID uint32, now time.Time) ([]*model.Typing, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	list := make([]*model.Typing, 0)
+	for k, t := range repo.typings {
+		if k.threadID != threadID || t.IsExpired(now) {
+			continue
+		}
+		copied := *t
+		list = append(list, &copied)
+	}
+
+	sortTypings(list)
+	return list, nil
+}
+
+// PutTyping stores typing of the user in the thread, which replaces the existing one.
+func (repo *presenceRepository) PutTyping(typing *model.Typing) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	copied := *typing
+	repo.typings[typingKey{threadID: typing.ThreadID, userID: typing.UserID}] = &copied
+
+	repo.afterWrite(typing.StartedAt)
+	return nil
+}
+
+// DeleteTyping deletes typing of the user in the thread.
+func (repo *presenceRepository) DeleteTyping(threadID, userID uint32) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	delete(repo.typings, typingKey{threadID: threadID, userID: userID})
+	return nil
+}
+
+// afterWrite sweeps expired records once in a while so that memory does not grow by users who went offline.
+// This must be called with lock held.
+func (repo *presenceRepository) afterWrite(now time.Time) {
+	repo.writes++
+	if repo.writes < sweepInterval {
+		return
+	}
+	repo.writes = 0
+
+	for k, r := range repo.records {
+		if r.IsExpired(now) {
+			delete(repo.records, k)
+		}
+	}
+	for k, t := range repo.typings {
+		if t.IsExpired(now) {
+			delete(repo.typings, k)
+		}
+	}
+}
+
+// sortTypings sorts typings in order of starting, and by user for the same time.
+func sortTypings(list []*model.Typing) {
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].StartedAt.Equal(list[j].StartedAt) {
+			return list[i].StartedAt.Before(list[j].StartedAt)
+		}
+		return list[i].UserID < list[j].UserID
+	})
+}
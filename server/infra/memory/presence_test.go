@@ -0,0 +1,199 @@
+package memory
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+)
+
+func Test_presenceRepository_afterWrite(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		// writes is the number of writes before the write of the test.
+		writes          int
+		wantRecordIDs   []uint32
+		wantTypingUsers []uint32
+	}{
+		{
+			name:            "When writes do not reach the interval, keeps expired ones",
+			writes:          sweepInterval - 2,
+			wantRecordIDs:   []uint32{1, 2, 3},
+			wantTypingUsers: []uint32{1, 2, 3},
+		},
+		{
+			name:            "When writes reach the interval, sweeps expired ones",
+			writes:          sweepInterval - 1,
+			wantRecordIDs:   []uint32{2, 3},
+			wantTypingUsers: []uint32{2, 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := NewPresenceRepository().(*presenceRepository)
+			repo.records[1] = &model.PresenceRecord{UserID: 1, ExpiresAt: now}
+			repo.records[2] = &model.PresenceRecord{UserID: 2, ExpiresAt: now.Add(time.Second)}
+			repo.typings[typingKey{threadID: 1, userID: 1}] = &model.Typing{ThreadID: 1, UserID: 1, ExpiresAt: now.Add(-time.Second)}
+			repo.typings[typingKey{threadID: 1, userID: 2}] = &model.Typing{ThreadID: 1, UserID: 2, ExpiresAt: now.Add(time.Second)}
+			repo.writes = tt.writes
+
+			if err := repo.PutPresenceRecord(&model.PresenceRecord{UserID: 3, LastHeartbeatAt: now, ExpiresAt: now.Add(time.Minute)}); err != nil {
+				t.Fatal(err)
+			}
+			repo.writes = tt.writes
+			if err := repo.PutTyping(&model.Typing{ThreadID: 1, UserID: 3, StartedAt: now, ExpiresAt: now.Add(time.Minute)}); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, id := range tt.wantRecordIDs {
+				if _, ok := repo.records[id]; !ok {
+					t.Errorf("record of user %d should be kept", id)
+				}
+			}
+			if len(repo.records) != len(tt.wantRecordIDs) {
+				t.Errorf("len(records) = %d, want %d", len(repo.records), len(tt.wantRecordIDs))
+			}
+			for _, id := range tt.wantTypingUsers {
+				if _, ok := repo.typings[typingKey{threadID: 1, userID: id}]; !ok {
+					t.Errorf("typing of user %d should be kept", id)
+				}
+			}
+			if len(repo.typings) != len(tt.wantTypingUsers) {
+				t.Errorf("len(typings) = %d, want %d", len(repo.typings), len(tt.wantTypingUsers))
+			}
+		})
+	}
+}
+
+func Test_presenceRepository_GetTypingsByThreadID(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		typings     []*model.Typing
+		threadID    uint32
+		wantUserIDs []uint32
+	}{
+		{
+			name: "When typings are in the thread, returns them in order of starting",
+			typings: []*model.Typing{
+				{ThreadID: 1, UserID: 1, StartedAt: now.Add(-time.Second), ExpiresAt: now.Add(time.Second)},
+				{ThreadID: 1, UserID: 2, StartedAt: now.Add(-2 * time.Second), ExpiresAt: now.Add(time.Second)},
+			},
+			threadID:    1,
+			wantUserIDs: []uint32{2, 1},
+		},
+		{
+			name: "When typings start at the same time, returns them in order of user",
+			typings: []*model.Typing{
+				{ThreadID: 1, UserID: 3, StartedAt: now, ExpiresAt: now.Add(time.Second)},
+				{ThreadID: 1, UserID: 1, StartedAt: now, ExpiresAt: now.Add(time.Second)},
+				{ThreadID: 1, UserID: 2, StartedAt: now, ExpiresAt: now.Add(time.Second)},
+			},
+			threadID:    1,
+			wantUserIDs: []uint32{1, 2, 3},
+		},
+		{
+			name: "When typings are expired, does not return them",
+			typings: []*model.Typing{
+				{ThreadID: 1, UserID: 1, StartedAt: now.Add(-2 * time.Second), ExpiresAt: now},
+				{ThreadID: 1, UserID: 2, StartedAt: now.Add(-2 * time.Second), ExpiresAt: now.Add(-time.Second)},
+				{ThreadID: 1, UserID: 3, StartedAt: now.Add(-2 * time.Second), ExpiresAt: now.Add(time.Nanosecond)},
+			},
+			threadID:    1,
+			wantUserIDs: []uint32{3},
+		},
+		{
+			name: "When typings are in other threads, does not return them",
+			typings: []*model.Typing{
+				{ThreadID: 1, UserID: 1, StartedAt: now, ExpiresAt: now.Add(time.Second)},
+				{ThreadID: 2, UserID: 2, StartedAt: now, ExpiresAt: now.Add(time.Second)},
+			},
+			threadID:    2,
+			wantUserIDs: []uint32{2},
+		},
+		{
+			name:        "When nobody is typing, returns empty list",
+			threadID:    1,
+			wantUserIDs: []uint32{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := NewPresenceRepository()
+			for _, typing := range tt.typings {
+				if err := repo.PutTyping(typing); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := repo.GetTypingsByThreadID(tt.threadID, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			gotUserIDs := make([]uint32, 0, len(got))
+			for _, typing := range got {
+				gotUserIDs = append(gotUserIDs, typing.UserID)
+			}
+			if !reflect.DeepEqual(gotUserIDs, tt.wantUserIDs) {
+				t.Errorf("presenceRepository.GetTypingsByThreadID() user ids = %v, want %v", gotUserIDs, tt.wantUserIDs)
+			}
+		})
+	}
+}
+
+func Test_presenceRepository_copy(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	repo := NewPresenceRepository()
+
+	record := &model.PresenceRecord{UserID: 1, LastHeartbeatAt: now, ExpiresAt: now.Add(time.Minute)}
+	if err := repo.PutPresenceRecord(record); err != nil {
+		t.Fatal(err)
+	}
+	typing := &model.Typing{ThreadID: 1, UserID: 1, StartedAt: now, ExpiresAt: now.Add(time.Minute)}
+	if err := repo.PutTyping(typing); err != nil {
+		t.Fatal(err)
+	}
+
+	// changes of the stored ones and the read ones do not affect the repository.
+	record.Away = true
+	typing.ExpiresAt = now
+
+	gotRecord, err := repo.GetPresenceRecord(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotRecord.ExpiresAt = now
+
+	gotRecords, err := repo.GetPresenceRecordsByUserIDs([]uint32{1}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotRecords[0].Away = true
+
+	gotTyping, err := repo.GetTyping(1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotTyping.ExpiresAt = now
+
+	gotTypings, err := repo.GetTypingsByThreadID(1, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotTypings[0].ExpiresAt = now
+
+	wantRecord := &model.PresenceRecord{UserID: 1, LastHeartbeatAt: now, ExpiresAt: now.Add(time.Minute)}
+	if got, _ := repo.GetPresenceRecord(1); !reflect.DeepEqual(got, wantRecord) {
+		t.Errorf("presenceRepository.GetPresenceRecord() = %v, want %v", got, wantRecord)
+	}
+	wantTyping := &model.Typing{ThreadID: 1, UserID: 1, StartedAt: now, ExpiresAt: now.Add(time.Minute)}
+	if got, _ := repo.GetTyping(1, 1); !reflect.DeepEqual(got, wantTyping) {
+		t.Errorf("presenceRepository.GetTyping() = %v, want %v", got, wantTyping)
+	}
+}
@@ -0,0 +1,175 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+	"unicode"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+)
+
+// searchRepository is the in-memory inverted index of comments for setups without the FULLTEXT index of MySQL.
+// Text is split into bigrams of characters like the ngram parser, so that text without spaces such as Japanese is found.
+// Bigrams only narrow down candidates, and each candidate is checked with the whole terms.
+// This is only shared in a process, and is built from the database at start.
+type searchRepository struct {
+	mu       sync.RWMutex
+	comments map[uint32]*model.Comment
+	postings map[string]map[uint32]struct{}
+}
+
+// NewSearchRepository generates and returns SearchRepository.
+func NewSearchRepository() repository.SearchRepository {
+	return &searchRepository{
+		comments: make(map[uint32]*model.Comment),
+		postings: make(map[string]map[uint32]struct{}),
+	}
+}
+
+// IndexComment adds the comment to the index, or replaces the one which has the same id.
+// Deleted comments are removed instead. SQLManager is not used.
+func (repo *searchRepository) IndexComment(m repository.SQLManager, comment *model.Comment) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	repo.remove(comment.ID)
+	if comment.IsDeleted() {
+		return nil
+	}
+
+	indexed := &model.Comment{
+		ID:        comment.ID,
+		ThreadID:  comment.ThreadID,
+		UserID:    comment.UserID,
+		Content:   comment.Content,
+		CreatedAt: comment.CreatedAt,
+	}
+	repo.comments[indexed.ID] = indexed
+
+	for _, token := range bigrams(indexed.Content, 1) {
+		ids, ok := repo.postings[token]
+		if !ok {
+			ids = make(map[uint32]struct{})
+			repo.postings[token] = ids
+		}
+		ids[indexed.ID] = struct{}{}
+	}
+	return nil
+}
+
+// RemoveComment removes the comment from the index. SQLManager is not used.
+func (repo *searchRepository) RemoveComment(m repository.SQLManager, commentID uint32) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	repo.remove(commentID)
+	return nil
+}
+
+// SearchCommentIDs returns ids of at most limit comments which match the query
+// whose ids are less than beforeID in order of newest first. SQLManager is not used.
+func (repo *searchRepository) SearchCommentIDs(m repository.SQLManager, query *model.SearchQuery, beforeID uint32, limit int) ([]uint32, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	ids := make([]uint32, 0)
+	for id := range repo.candidates(query.Terms) {
+		if beforeID != 0 && id >= beforeID {
+			continue
+		}
+		if query.Matches(repo.comments[id]) {
+			ids = append(ids, id)
+		}
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] > ids[j]
+	})
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+	return ids, nil
+}
+
+// candidates returns ids of comments which have all bigrams of the terms.
+// All comments are candidates if the terms have no bigram.
+func (repo *searchRepository) candidates(terms []string) map[uint32]struct{} {
+	var tokens []string
+	for _, term := range terms {
+		tokens = append(tokens, bigrams(term, 2)...)
+	}
+
+	if len(tokens) == 0 {
+		all := make(map[uint32]struct{}, len(repo.comments))
+		for id := range repo.comments {
+			all[id] = struct{}{}
+		}
+		return all
+	}
+
+	// intersection is made from the smallest postings.
+	sort.Slice(tokens, func(i, j int) bool {
+		return len(repo.postings[tokens[i]]) < len(repo.postings[tokens[j]])
+	})
+
+	result := make(map[uint32]struct{}, len(repo.postings[tokens[0]]))
+	for id := range repo.postings[tokens[0]] {
+		result[id] = struct{}{}
+	}
+	for _, token := range tokens[1:] {
+		ids := repo.postings[token]
+		for id := range result {
+			if _, ok := ids[id]; !ok {
+				delete(result, id)
+			}
+		}
+	}
+	return result
+}
+
+// remove removes the comment from comments and postings, which is called with the lock.
+func (repo *searchRepository) remove(commentID uint32) {
+	comment, ok := repo.comments[commentID]
+	if !ok {
+		return
+	}
+
+	for _, token := range bigrams(comment.Content, 1) {
+		ids := repo.postings[token]
+		delete(ids, commentID)
+		if len(ids) == 0 {
+			delete(repo.postings, token)
+		}
+	}
+	delete(repo.comments, commentID)
+}
+
+// bigrams returns bigrams of characters in lower case of each word in the text, where words are separated by
+// spaces and punctuation. Words shorter than 2 characters are returned as they are if minWordLength is 1, or skipped.
+// A term in the text has all bigrams of its words of 2 characters or more, which is why they narrow down candidates.
+func bigrams(text string, minWordLength int) []string {
+	var tokens []string
+	var word []rune
+
+	flush := func() {
+		if len(word) >= minWordLength && len(word) == 1 {
+			tokens = append(tokens, string(word))
+		}
+		for i := 0; i+2 <= len(word); i++ {
+			tokens = append(tokens, string(word[i:i+2]))
+		}
+		word = word[:0]
+	}
+
+	for _, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r) || r == '_' {
+			word = append(word, unicode.ToLower(r))
+			continue
+		}
+		flush()
+	}
+	flush()
+
+	return tokens
+}
@@ -0,0 +1,304 @@
+package memory
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+)
+
+// newTestSearchRepository returns the index of the comments whose ids are their keys.
+func newTestSearchRepository(t *testing.T, contents map[uint32]string) *searchRepository {
+	repo := NewSearchRepository().(*searchRepository)
+	for id, content := range contents {
+		if err := repo.IndexComment(nil, &model.Comment{ID: id, Content: content}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return repo
+}
+
+func Test_bigrams(t *testing.T) {
+	tests := []struct {
+		name          string
+		text          string
+		minWordLength int
+		want          []string
+	}{
+		{
+			name:          "When words are separated by spaces and punctuation, returns bigrams of each word in lower case",
+			text:          "Hello, Go!",
+			minWordLength: 1,
+			want:          []string{"he", "el", "ll", "lo", "go"},
+		},
+		{
+			name:          "When text is Japanese without spaces, returns bigrams of the whole text",
+			text:          "東京タワー",
+			minWordLength: 1,
+			want:          []string{"東京", "京タ", "タワ", "ワー"},
+		},
+		{
+			name:          "When letters are mixed with Japanese, returns bigrams across them",
+			text:          "Go言語",
+			minWordLength: 1,
+			want:          []string{"go", "o言", "言語"},
+		},
+		{
+			name:          "When words are single characters and minWordLength is 1, returns them as they are",
+			text:          "a 猫",
+			minWordLength: 1,
+			want:          []string{"a", "猫"},
+		},
+		{
+			name:          "When words are single characters and minWordLength is 2, returns nothing",
+			text:          "a 猫",
+			minWordLength: 2,
+			want:          nil,
+		},
+		{
+			name:          "When text is empty, returns nothing",
+			text:          "",
+			minWordLength: 1,
+			want:          nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := bigrams(tt.text, tt.minWordLength); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("bigrams() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_searchRepository_candidates(t *testing.T) {
+	contents := map[uint32]string{
+		1: "東京タワーに行った",
+		2: "京都タワーに行った",
+		3: "hello world",
+	}
+
+	tests := []struct {
+		name  string
+		terms []string
+		want  map[uint32]struct{}
+	}{
+		{
+			name:  "When a term is given, returns comments which have all its bigrams",
+			terms: []string{"タワー"},
+			want:  map[uint32]struct{}{1: {}, 2: {}},
+		},
+		{
+			name:  "When terms are given, returns the intersection of them",
+			terms: []string{"タワー", "東京"},
+			want:  map[uint32]struct{}{1: {}},
+		},
+		{
+			name:  "When a bigram of the term is not indexed, returns nothing",
+			terms: []string{"大阪"},
+			want:  map[uint32]struct{}{},
+		},
+		{
+			name:  "When terms have no bigram, returns all comments",
+			terms: []string{"東"},
+			want:  map[uint32]struct{}{1: {}, 2: {}, 3: {}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newTestSearchRepository(t, contents)
+
+			if got := repo.candidates(tt.terms); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("searchRepository.candidates() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_searchRepository_remove(t *testing.T) {
+	tests := []struct {
+		name         string
+		removedID    uint32
+		wantPostings map[string]map[uint32]struct{}
+	}{
+		{
+			name:      "When the comment is indexed, removes it and postings which have no other comment",
+			removedID: 1,
+			wantPostings: map[string]map[uint32]struct{}{
+				"京都": {2: {}},
+				"都タ": {2: {}},
+				"タワ": {2: {}},
+				"ワー": {2: {}},
+			},
+		},
+		{
+			name:      "When the comment is not indexed, does nothing",
+			removedID: 3,
+			wantPostings: map[string]map[uint32]struct{}{
+				"東京": {1: {}},
+				"京タ": {1: {}},
+				"京都": {2: {}},
+				"都タ": {2: {}},
+				"タワ": {1: {}, 2: {}},
+				"ワー": {1: {}, 2: {}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newTestSearchRepository(t, map[uint32]string{
+				1: "東京タワー",
+				2: "京都タワー",
+			})
+
+			if err := repo.RemoveComment(nil, tt.removedID); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, ok := repo.comments[tt.removedID]; ok {
+				t.Errorf("comment %d should be removed", tt.removedID)
+			}
+			if !reflect.DeepEqual(repo.postings, tt.wantPostings) {
+				t.Errorf("searchRepository.postings = %v, want %v", repo.postings, tt.wantPostings)
+			}
+		})
+	}
+}
+
+func Test_searchRepository_SearchCommentIDs(t *testing.T) {
+	contents := map[uint32]string{
+		1: "東京タワーに行った",
+		2: "Go言語で書いた",
+		3: "東京の本屋",
+		4: "タワーから東京を見た",
+		5: "東京へ行く",
+		6: "ワー東京タワ",
+	}
+
+	tests := []struct {
+		name     string
+		terms    []string
+		beforeID uint32
+		limit    int
+		want     []uint32
+	}{
+		{
+			name:  "When Japanese term without spaces is given, returns comments which have it in order of newest first",
+			terms: []string{"東京"},
+			limit: 10,
+			want:  []uint32{6, 5, 4, 3, 1},
+		},
+		{
+			name:  "When the term is in another case, returns comments which have it",
+			terms: []string{"go"},
+			limit: 10,
+			want:  []uint32{2},
+		},
+		{
+			name:  "When single character term is given, returns comments which have it",
+			terms: []string{"本"},
+			limit: 10,
+			want:  []uint32{3},
+		},
+		{
+			name:  "When comments have all bigrams of the term but not the whole term, does not return them",
+			terms: []string{"東京タワー"},
+			limit: 10,
+			want:  []uint32{1},
+		},
+		{
+			name:  "When terms are given, returns comments which have all of them",
+			terms: []string{"東京", "タワー"},
+			limit: 10,
+			want:  []uint32{4, 1},
+		},
+		{
+			name:  "When limit is given, returns at most limit comments",
+			terms: []string{"東京"},
+			limit: 2,
+			want:  []uint32{6, 5},
+		},
+		{
+			name:     "When beforeID is given, returns comments older than it",
+			terms:    []string{"東京"},
+			beforeID: 4,
+			limit:    10,
+			want:     []uint32{3, 1},
+		},
+		{
+			name:  "When no comment has the term, returns empty list",
+			terms: []string{"大阪"},
+			limit: 10,
+			want:  []uint32{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newTestSearchRepository(t, contents)
+
+			got, err := repo.SearchCommentIDs(nil, &model.SearchQuery{Terms: tt.terms}, tt.beforeID, tt.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("searchRepository.SearchCommentIDs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_searchRepository_IndexComment(t *testing.T) {
+	tests := []struct {
+		name              string
+		comment           *model.Comment
+		terms             []string
+		want              []uint32
+		wantRemovedTokens []string
+	}{
+		{
+			name:              "When the comment is edited, old bigrams no longer match",
+			comment:           &model.Comment{ID: 1, Content: "大阪城"},
+			terms:             []string{"東京"},
+			want:              []uint32{},
+			wantRemovedTokens: []string{"東京", "京タ", "タワ", "ワー"},
+		},
+		{
+			name:    "When the comment is edited, new content matches",
+			comment: &model.Comment{ID: 1, Content: "大阪城"},
+			terms:   []string{"大阪"},
+			want:    []uint32{1},
+		},
+		{
+			name:              "When the comment is deleted, removes it",
+			comment:           &model.Comment{ID: 1, Content: "東京タワー", DeletedAt: time.Now()},
+			terms:             []string{"東京"},
+			want:              []uint32{},
+			wantRemovedTokens: []string{"東京", "京タ", "タワ", "ワー"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newTestSearchRepository(t, map[uint32]string{
+				1: "東京タワー",
+			})
+
+			if err := repo.IndexComment(nil, tt.comment); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := repo.SearchCommentIDs(nil, &model.SearchQuery{Terms: tt.terms}, 0, 10)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("searchRepository.SearchCommentIDs() = %v, want %v", got, tt.want)
+			}
+			for _, token := range tt.wantRemovedTokens {
+				if _, ok := repo.postings[token]; ok {
+					t.Errorf("posting of %q should be removed", token)
+				}
+			}
+		})
+	}
+}
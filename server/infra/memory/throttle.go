@@ -0,0 +1,158 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/repository"
+	"github.com/pkg/errors"
+)
+
+// sweepInterval is the number of writes between sweeps of expired records.
+const sweepInterval = 1024
+
+// throttleEntry is the stored record and the time it can be forgotten.
+type throttleEntry struct {
+	record    *model.ThrottleRecord
+	expiresAt time.Time
+}
+
+// throttleRepository is the in-memory repository of throttle record.
+// This is only shared in a process.
+type throttleRepository struct {
+	mu      sync.Mutex
+	entries map[model.ThrottleKey]*throttleEntry
+	writes  int
+}
+
+// NewThrottleRepository generates and returns ThrottleRepository.
+func NewThrottleRepository() repository.ThrottleRepository {
+	return &throttleRepository{
+		entries: make(map[model.ThrottleKey]*throttleEntry),
+	}
+}
+
+// GetThrottleRecord gets and returns a record specified by key.
+func (repo *throttleRepository) GetThrottleRecord(key model.ThrottleKey) (*model.ThrottleRecord, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	e, ok := repo.entries[key]
+	if !ok {
+		return nil, errors.WithStack(repo.noSuchDataError(key))
+	}
+
+	return copyThrottleRecord(e.record), nil
+}
+
+// AddThrottleFailure adds a failure at given time and forgets failures older than window.
+func (repo *throttleRepository) AddThrottleFailure(key model.ThrottleKey, at time.Time, window time.Duration) (*model.ThrottleRecord, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	e, ok := repo.entries[key]
+	if !ok {
+		e = &throttleEntry{
+			record: &model.ThrottleRecord{Key: key},
+		}
+		repo.entries[key] = e
+	}
+
+	since := at.Add(-window)
+	failures := make([]time.Time, 0, len(e.record.Failures)+1)
+	for _, f := range e.record.Failures {
+		if f.After(since) {
+			failures = append(failures, f)
+		}
+	}
+	e.record.Failures = append(failures, at)
+
+	if expiresAt := at.Add(window); expiresAt.After(e.expiresAt) {
+		e.expiresAt = expiresAt
+	}
+
+	repo.afterWrite(at)
+
+	return copyThrottleRecord(e.record), nil
+}
+
+// RemoveThrottleFailure removes one failure at given time.
+// This does nothing if the record or the failure does not exist.
+func (repo *throttleRepository) RemoveThrottleFailure(key model.ThrottleKey, at time.Time) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	e, ok := repo.entries[key]
+	if !ok {
+		return nil
+	}
+
+	e.record = e.record.WithoutFailure(at)
+	return nil
+}
+
+// LockThrottleRecord locks a record specified by key until given time.
+func (repo *throttleRepository) LockThrottleRecord(key model.ThrottleKey, until time.Time) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	e, ok := repo.entries[key]
+	if !ok {
+		return errors.WithStack(repo.noSuchDataError(key))
+	}
+
+	e.record.LockedUntil = until
+	if until.After(e.expiresAt) {
+		e.expiresAt = until
+	}
+
+	return nil
+}
+
+// DeleteThrottleRecord deletes a record specified by key.
+func (repo *throttleRepository) DeleteThrottleRecord(key model.ThrottleKey) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	delete(repo.entries, key)
+	return nil
+}
+
+// afterWrite sweeps expired records once in a while so that memory does not grow by unique keys.
+// This must be called with lock held.
+func (repo *throttleRepository) afterWrite(now time.Time) {
+	repo.writes++
+	if repo.writes < sweepInterval {
+		return
+	}
+	repo.writes = 0
+
+	for k, e := range repo.entries {
+		if !now.Before(e.expiresAt) {
+			delete(repo.entries, k)
+		}
+	}
+}
+
+func (repo *throttleRepository) noSuchDataError(key model.ThrottleKey) error {
+	return &model.NoSuchDataError{
+		PropertyNameForDeveloper:    model.KeyPropertyForDeveloper,
+		PropertyNameForUser:         model.KeyPropertyForUser,
+		PropertyValue:               key.String(),
+		DomainModelNameForDeveloper: model.DomainModelNameThrottleRecordForDeveloper,
+		DomainModelNameForUser:      model.DomainModelNameThrottleRecordForUser,
+	}
+}
+
+// copyThrottleRecord copies record so that callers can not modify stored one.
+func copyThrottleRecord(r *model.ThrottleRecord) *model.ThrottleRecord {
+	failures := make([]time.Time, len(r.Failures))
+	copy(failures, r.Failures)
+
+	return &model.ThrottleRecord{
+		Key:         r.Key,
+		Failures:    failures,
+		LockedUntil: r.LockedUntil,
+	}
+}
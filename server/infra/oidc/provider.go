@@ -0,0 +1,381 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/service"
+)
+
+// idTokenAlgorithm is the algorithm of ID token, which every provider must support.
+const idTokenAlgorithm = "RS256"
+
+// clockSkew is the difference of clocks allowed between the provider and the server.
+const clockSkew = time.Minute
+
+// maxResponseSize is the max bytes of response read from the provider.
+const maxResponseSize = 1 << 20
+
+// scopes is the scopes requested to the provider.
+var scopes = []string{"openid", "profile", "email"}
+
+// Config is the configuration of the client registered to the provider.
+type Config struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	// HTTPClient talks to the provider, and http.DefaultClient is used if nil.
+	HTTPClient *http.Client
+}
+
+// discovery is the metadata of the provider.
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type provider struct {
+	config    Config
+	discovery *discovery
+	client    *http.Client
+	now       func() time.Time
+
+	mu   sync.Mutex
+	keys map[string]*rsa.PublicKey
+}
+
+// NewProvider discovers the provider and returns OIDCProvider.
+func NewProvider(ctx context.Context, config Config) (service.OIDCProvider, error) {
+	if config.Issuer == "" || config.ClientID == "" || config.RedirectURL == "" {
+		return nil, errors.New("issuer, client id and redirect url of OpenID Connect are required")
+	}
+
+	p := &provider{
+		config: config,
+		client: config.HTTPClient,
+		now:    time.Now,
+		keys:   make(map[string]*rsa.PublicKey),
+	}
+	if p.client == nil {
+		p.client = http.DefaultClient
+	}
+
+	d := &discovery{}
+	wellKnown := strings.TrimSuffix(config.Issuer, "/") + "/.well-known/openid-configuration"
+	if err := p.getJSON(ctx, wellKnown, d); err != nil {
+		return nil, errors.Wrap(err, "failed to discover OpenID Connect provider")
+	}
+	if d.Issuer != config.Issuer {
+		return nil, errors.Errorf("issuer of OpenID Connect provider should be %q, but %q", config.Issuer, d.Issuer)
+	}
+	if d.AuthorizationEndpoint == "" || d.TokenEndpoint == "" || d.JWKSURI == "" {
+		return nil, errors.New("OpenID Connect provider should have authorization, token and jwks endpoints")
+	}
+	p.discovery = d
+
+	return p, nil
+}
+
+// Issuer returns the issuer which identifies the provider.
+func (p *provider) Issuer() string {
+	return p.config.Issuer
+}
+
+// AuthCodeURL returns the URL of authorization code flow with PKCE.
+func (p *provider) AuthCodeURL(state, nonce, codeChallenge string) string {
+	v := url.Values{}
+	v.Set("response_type", "code")
+	v.Set("client_id", p.config.ClientID)
+	v.Set("redirect_uri", p.config.RedirectURL)
+	v.Set("scope", strings.Join(scopes, " "))
+	v.Set("state", state)
+	v.Set("nonce", nonce)
+	v.Set("code_challenge", codeChallenge)
+	v.Set("code_challenge_method", "S256")
+
+	sep := "?"
+	if strings.Contains(p.discovery.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return p.discovery.AuthorizationEndpoint + sep + v.Encode()
+}
+
+// tokenResponse is the response of token endpoint.
+type tokenResponse struct {
+	IDToken string `json:"id_token"`
+}
+
+// Exchange exchanges the code at token endpoint and verifies the ID token.
+func (p *provider) Exchange(ctx context.Context, code, codeVerifier string) (*model.OIDCClaims, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.config.RedirectURL)
+	form.Set("code_verifier", codeVerifier)
+	form.Set("client_id", p.config.ClientID)
+
+	req, err := http.NewRequest(http.MethodPost, p.discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, p.serverError(err, "failed to create token request")
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if p.config.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	}
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return nil, p.serverError(err, "failed to request token endpoint")
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(io.LimitReader(res.Body, maxResponseSize))
+	if err != nil {
+		return nil, p.serverError(err, "failed to read token response")
+	}
+
+	// the provider rejects invalid or used code and wrong code verifier by 400.
+	if res.StatusCode == http.StatusBadRequest || res.StatusCode == http.StatusUnauthorized {
+		return nil, errors.WithStack(&model.AuthenticationErr{
+			BaseErr: errors.Errorf("token endpoint responded %d: %s", res.StatusCode, body),
+		})
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, p.serverError(errors.Errorf("token endpoint responded %d", res.StatusCode), "failed to exchange code")
+	}
+
+	token := &tokenResponse{}
+	if err := json.Unmarshal(body, token); err != nil || token.IDToken == "" {
+		return nil, p.serverError(err, "token response should have id_token")
+	}
+
+	claims, err := p.verify(ctx, token.IDToken)
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// idTokenHeader is the header of ID token.
+type idTokenHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+}
+
+// idTokenPayload is the payload of ID token.
+type idTokenPayload struct {
+	Issuer            string          `json:"iss"`
+	Subject           string          `json:"sub"`
+	Audience          json.RawMessage `json:"aud"`
+	AuthorizedParty   string          `json:"azp"`
+	ExpiresAt         int64           `json:"exp"`
+	IssuedAt          int64           `json:"iat"`
+	Nonce             string          `json:"nonce"`
+	PreferredUsername string          `json:"preferred_username"`
+	Name              string          `json:"name"`
+	Email             string          `json:"email"`
+}
+
+// verify verifies signature, issuer, audience and expiry of the ID token and returns its claims.
+func (p *provider) verify(ctx context.Context, idToken string) (*model.OIDCClaims, error) {
+	parts := strings.Split(idToken, ".")
+	if len(parts) != 3 {
+		return nil, p.invalidToken("malformed id token")
+	}
+
+	header := &idTokenHeader{}
+	if err := decodeSegment(parts[0], header); err != nil {
+		return nil, p.invalidToken("malformed header of id token")
+	}
+	if header.Algorithm != idTokenAlgorithm {
+		return nil, p.invalidToken("unexpected algorithm of id token: " + header.Algorithm)
+	}
+
+	key, err := p.key(ctx, header.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, p.invalidToken("malformed signature of id token")
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return nil, p.invalidToken("signature of id token does not match")
+	}
+
+	payload := &idTokenPayload{}
+	if err := decodeSegment(parts[1], payload); err != nil {
+		return nil, p.invalidToken("malformed payload of id token")
+	}
+
+	now := p.now()
+	switch {
+	case payload.Issuer != p.config.Issuer:
+		return nil, p.invalidToken("unexpected issuer of id token: " + payload.Issuer)
+	case !p.hasAudience(payload):
+		return nil, p.invalidToken("id token is not issued for this client")
+	case payload.Subject == "":
+		return nil, p.invalidToken("id token should have subject")
+	case !now.Before(time.Unix(payload.ExpiresAt, 0).Add(clockSkew)):
+		return nil, p.invalidToken("id token is expired")
+	case time.Unix(payload.IssuedAt, 0).After(now.Add(clockSkew)):
+		return nil, p.invalidToken("id token is issued in the future")
+	}
+
+	return &model.OIDCClaims{
+		Issuer:            payload.Issuer,
+		Subject:           payload.Subject,
+		Nonce:             payload.Nonce,
+		PreferredUsername: payload.PreferredUsername,
+		Name:              payload.Name,
+		Email:             payload.Email,
+	}, nil
+}
+
+// hasAudience returns whether the ID token is issued for this client.
+// Audience is a string or an array, and authorized party must be this client if there are other audiences.
+func (p *provider) hasAudience(payload *idTokenPayload) bool {
+	var audiences []string
+	var single string
+	if err := json.Unmarshal(payload.Audience, &single); err == nil {
+		audiences = []string{single}
+	} else if err := json.Unmarshal(payload.Audience, &audiences); err != nil {
+		return false
+	}
+
+	found := false
+	for _, aud := range audiences {
+		if aud == p.config.ClientID {
+			found = true
+		}
+	}
+	if len(audiences) > 1 && payload.AuthorizedParty != p.config.ClientID {
+		return false
+	}
+	return found
+}
+
+// key returns the public key specified by id.
+// Keys are fetched again when the id is unknown, so that rotation at the provider is followed.
+func (p *provider) key(ctx context.Context, id string) (*rsa.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.keys[id]; ok {
+		return key, nil
+	}
+
+	keys, err := p.fetchKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p.keys = keys
+
+	key, ok := p.keys[id]
+	if !ok {
+		return nil, p.invalidToken("unknown key of id token: " + id)
+	}
+	return key, nil
+}
+
+// jwkSet is the public keys of the provider in the format of JWK Set.
+type jwkSet struct {
+	Keys []struct {
+		KeyType string `json:"kty"`
+		KeyID   string `json:"kid"`
+		Use     string `json:"use"`
+		N       string `json:"n"`
+		E       string `json:"e"`
+	} `json:"keys"`
+}
+
+// fetchKeys fetches RSA public keys for signature from jwks endpoint.
+func (p *provider) fetchKeys(ctx context.Context) (map[string]*rsa.PublicKey, error) {
+	set := &jwkSet{}
+	if err := p.getJSON(ctx, p.discovery.JWKSURI, set); err != nil {
+		return nil, p.serverError(err, "failed to fetch keys of OpenID Connect provider")
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.KeyType != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		n, nErr := base64.RawURLEncoding.DecodeString(k.N)
+		e, eErr := base64.RawURLEncoding.DecodeString(k.E)
+		if nErr != nil || eErr != nil || len(e) == 0 || len(e) > 4 {
+			continue
+		}
+		keys[k.KeyID] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	return keys, nil
+}
+
+// getJSON gets and decodes JSON.
+func (p *provider) getJSON(ctx context.Context, u string, v interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	res, err := p.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return errors.Errorf("%s responded %d", u, res.StatusCode)
+	}
+
+	if err := json.NewDecoder(io.LimitReader(res.Body, maxResponseSize)).Decode(v); err != nil {
+		return errors.Wrapf(err, "failed to decode response of %s", u)
+	}
+	return nil
+}
+
+// invalidToken returns AuthenticationErr of invalid ID token.
+func (p *provider) invalidToken(reason string) error {
+	return errors.WithStack(&model.AuthenticationErr{BaseErr: errors.New(reason)})
+}
+
+// serverError returns OtherServerError of failure to talk to the provider.
+func (p *provider) serverError(err error, reason string) error {
+	return errors.WithStack(&model.OtherServerError{
+		BaseErr:                   err,
+		InvalidReasonForDeveloper: reason,
+	})
+}
+
+// decodeSegment decodes a segment of JWT.
+func decodeSegment(segment string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
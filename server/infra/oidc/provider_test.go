@@ -0,0 +1,155 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/testutil/fakeoidc"
+)
+
+const redirectURLForTest = "http://localhost:8080/api/oidc/callback"
+
+func newProviderForTest(t *testing.T, fake *fakeoidc.Provider) *provider {
+	t.Helper()
+
+	p, err := NewProvider(context.Background(), Config{
+		Issuer:       fake.Issuer(),
+		ClientID:     fakeoidc.ClientID,
+		ClientSecret: fakeoidc.ClientSecret,
+		RedirectURL:  redirectURLForTest,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p.(*provider)
+}
+
+func Test_provider_Exchange(t *testing.T) {
+	const verifier = "testCodeVerifier1234567890123456789012345678"
+	challenge := (&model.OIDCLogin{CodeVerifier: verifier}).CodeChallenge()
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		verifier string
+		tamper   func(claims map[string]interface{})
+		otherKey bool
+		wantErr  bool
+	}{
+		{
+			name:     "When the code and the verifier are valid, returns claims",
+			verifier: verifier,
+		},
+		{
+			name:     "When the audience is another client but this client is authorized party, returns claims",
+			verifier: verifier,
+			tamper: func(claims map[string]interface{}) {
+				claims["aud"] = []string{"otherClient", fakeoidc.ClientID}
+				claims["azp"] = fakeoidc.ClientID
+			},
+		},
+		{
+			name:     "When the code verifier does not match the challenge, returns AuthenticationErr",
+			verifier: verifier + "x",
+			wantErr:  true,
+		},
+		{
+			name:     "When the ID token is signed by unknown key, returns AuthenticationErr",
+			verifier: verifier,
+			otherKey: true,
+			wantErr:  true,
+		},
+		{
+			name:     "When the ID token is issued for another client, returns AuthenticationErr",
+			verifier: verifier,
+			tamper:   func(claims map[string]interface{}) { claims["aud"] = "otherClient" },
+			wantErr:  true,
+		},
+		{
+			name:     "When the ID token is issued by another issuer, returns AuthenticationErr",
+			verifier: verifier,
+			tamper:   func(claims map[string]interface{}) { claims["iss"] = "https://example.com" },
+			wantErr:  true,
+		},
+		{
+			name:     "When the ID token is expired, returns AuthenticationErr",
+			verifier: verifier,
+			tamper:   func(claims map[string]interface{}) { claims["exp"] = time.Now().Add(-time.Hour).Unix() },
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := fakeoidc.NewProvider(t)
+			defer fake.Close()
+			fake.Tamper = tt.tamper
+			if tt.otherKey {
+				fake.SigningKey = otherKey
+			}
+
+			p := newProviderForTest(t, fake)
+			callback := fake.Authorize(t, p.AuthCodeURL("testState", "testNonce", challenge))
+			if got := callback.Query().Get("state"); got != "testState" {
+				t.Fatalf("state = %v, want testState", got)
+			}
+
+			got, err := p.Exchange(context.Background(), callback.Query().Get("code"), tt.verifier)
+			if tt.wantErr {
+				if _, ok := errors.Cause(err).(*model.AuthenticationErr); !ok {
+					t.Errorf("provider.Exchange() error = %v, want AuthenticationErr", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("provider.Exchange() error = %v", err)
+			}
+
+			want := &model.OIDCClaims{
+				Issuer:            fake.Issuer(),
+				Subject:           fake.User.Subject,
+				Nonce:             "testNonce",
+				PreferredUsername: fake.User.PreferredUsername,
+			}
+			if *got != *want {
+				t.Errorf("provider.Exchange() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func Test_provider_AuthCodeURL(t *testing.T) {
+	fake := fakeoidc.NewProvider(t)
+	defer fake.Close()
+
+	p := newProviderForTest(t, fake)
+	u, err := url.Parse(p.AuthCodeURL("testState", "testNonce", "testChallenge"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"response_type":         "code",
+		"client_id":             fakeoidc.ClientID,
+		"redirect_uri":          redirectURLForTest,
+		"state":                 "testState",
+		"nonce":                 "testNonce",
+		"code_challenge":        "testChallenge",
+		"code_challenge_method": "S256",
+	}
+	for k, v := range want {
+		if got := u.Query().Get(k); got != v {
+			t.Errorf("provider.AuthCodeURL() %s = %v, want %v", k, got, v)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package ratelimit
+
+import (
+	"container/list"
+	"math"
+	"sync"
+	"time"
+)
+
+// Rate is the number of requests allowed in a period.
+type Rate struct {
+	Limit  int
+	Period time.Duration
+}
+
+// perSecond returns the number of tokens refilled per second.
+func (r Rate) perSecond() float64 {
+	return float64(r.Limit) / r.Period.Seconds()
+}
+
+// Result is the result of Allow.
+type Result struct {
+	Allowed bool
+	// Limit is the size of the bucket.
+	Limit int
+	// Remaining is the number of requests allowed right now.
+	Remaining int
+	// Reset is the duration until the bucket becomes full.
+	Reset time.Duration
+	// RetryAfter is the duration until next request is allowed.
+	// This is zero if the request is allowed.
+	RetryAfter time.Duration
+}
+
+// bucket is the token bucket of a key.
+type bucket struct {
+	key    string
+	tokens float64
+	last   time.Time
+}
+
+// Limiter limits requests by token bucket per key.
+// Buckets are kept up to capacity and the least recently used one is evicted.
+// An evicted bucket is the same as a full one if it has been idle long enough.
+// Callers should derive keys which a client can not rotate cheaply, because rotating keys evicts buckets.
+type Limiter struct {
+	mu       sync.Mutex
+	rate     Rate
+	capacity int
+	buckets  map[string]*list.Element
+	lru      *list.List
+	now      func() time.Time
+}
+
+// NewLimiter generates and returns Limiter.
+func NewLimiter(rate Rate, capacity int) *Limiter {
+	return &Limiter{
+		rate:     rate,
+		capacity: capacity,
+		buckets:  make(map[string]*list.Element),
+		lru:      list.New(),
+		now:      time.Now,
+	}
+}
+
+// Allow takes a token from the bucket of key and returns the result.
+func (l *Limiter) Allow(key string) Result {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	b := l.bucket(key, now)
+
+	burst := float64(l.rate.Limit)
+	perSecond := l.rate.perSecond()
+
+	b.tokens = math.Min(burst, b.tokens+now.Sub(b.last).Seconds()*perSecond)
+	b.last = now
+
+	res := Result{
+		Limit: l.rate.Limit,
+	}
+	if b.tokens >= 1 {
+		b.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = secondsToDuration((1 - b.tokens) / perSecond)
+	}
+
+	res.Remaining = int(b.tokens)
+	res.Reset = secondsToDuration((burst - b.tokens) / perSecond)
+
+	return res
+}
+
+// Len returns the number of buckets kept.
+func (l *Limiter) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.lru.Len()
+}
+
+// bucket returns the bucket of key and marks it as recently used.
+// If buckets exceed capacity, the least recently used one is evicted.
+// This must be called with lock held.
+func (l *Limiter) bucket(key string, now time.Time) *bucket {
+	if e, ok := l.buckets[key]; ok {
+		l.lru.MoveToFront(e)
+		return e.Value.(*bucket)
+	}
+
+	b := &bucket{
+		key:    key,
+		tokens: float64(l.rate.Limit),
+		last:   now,
+	}
+	l.buckets[key] = l.lru.PushFront(b)
+
+	for l.capacity > 0 && l.lru.Len() > l.capacity {
+		oldest := l.lru.Back()
+		l.lru.Remove(oldest)
+		delete(l.buckets, oldest.Value.(*bucket).key)
+	}
+
+	return b
+}
+
+func secondsToDuration(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,151 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/testutil"
+)
+
+func TestLimiter_Allow(t *testing.T) {
+	type request struct {
+		key string
+		// at is the elapsed time from the beginning.
+		at time.Duration
+	}
+
+	tests := []struct {
+		name          string
+		rate          Rate
+		capacity      int
+		requests      []request
+		want          Result
+		wantBucketLen int
+	}{
+		{
+			name:     "When the first request is given, returns allowed with remaining",
+			rate:     Rate{Limit: 3, Period: 3 * time.Second},
+			capacity: 10,
+			requests: []request{
+				{key: "a", at: 0},
+			},
+			want: Result{
+				Allowed:   true,
+				Limit:     3,
+				Remaining: 2,
+				Reset:     time.Second,
+			},
+			wantBucketLen: 1,
+		},
+		{
+			name:     "When the bucket is empty, returns not allowed with retry after",
+			rate:     Rate{Limit: 2, Period: 2 * time.Second},
+			capacity: 10,
+			requests: []request{
+				{key: "a", at: 0},
+				{key: "a", at: 0},
+				{key: "a", at: 0},
+			},
+			want: Result{
+				Allowed:    false,
+				Limit:      2,
+				Remaining:  0,
+				Reset:      2 * time.Second,
+				RetryAfter: time.Second,
+			},
+			wantBucketLen: 1,
+		},
+		{
+			name:     "When tokens are refilled, returns allowed",
+			rate:     Rate{Limit: 2, Period: 2 * time.Second},
+			capacity: 10,
+			requests: []request{
+				{key: "a", at: 0},
+				{key: "a", at: 0},
+				{key: "a", at: time.Second},
+			},
+			want: Result{
+				Allowed:   true,
+				Limit:     2,
+				Remaining: 0,
+				Reset:     2 * time.Second,
+			},
+			wantBucketLen: 1,
+		},
+		{
+			name:     "When another key is empty, returns allowed",
+			rate:     Rate{Limit: 1, Period: time.Second},
+			capacity: 10,
+			requests: []request{
+				{key: "a", at: 0},
+				{key: "b", at: 0},
+			},
+			want: Result{
+				Allowed:   true,
+				Limit:     1,
+				Remaining: 0,
+				Reset:     time.Second,
+			},
+			wantBucketLen: 2,
+		},
+		{
+			name:     "When buckets exceed capacity, evicts the least recently used bucket",
+			rate:     Rate{Limit: 1, Period: time.Minute},
+			capacity: 2,
+			requests: []request{
+				{key: "a", at: 0},
+				{key: "b", at: 0},
+				{key: "a", at: 0},
+				{key: "c", at: 0},
+				{key: "b", at: 0},
+			},
+			want: Result{
+				Allowed:   true,
+				Limit:     1,
+				Remaining: 0,
+				Reset:     time.Minute,
+			},
+			wantBucketLen: 2,
+		},
+		{
+			name:     "When buckets are full of depleted ones, still allows a new key",
+			rate:     Rate{Limit: 1, Period: time.Minute},
+			capacity: 2,
+			requests: []request{
+				{key: "a", at: 0},
+				{key: "b", at: 10 * time.Second},
+				{key: "c", at: 20 * time.Second},
+			},
+			want: Result{
+				Allowed:   true,
+				Limit:     1,
+				Remaining: 0,
+				Reset:     time.Minute,
+			},
+			wantBucketLen: 2,
+		},
+	}
+
+	start := time.Date(2019, 5, 1, 12, 0, 0, 0, time.UTC)
+	defer testutil.ResetFakeTime()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewLimiter(tt.rate, tt.capacity)
+			l.now = testutil.TimeNow
+
+			var got Result
+			for _, r := range tt.requests {
+				testutil.SetFakeTime(start.Add(r.at))
+				got = l.Allow(r.key)
+			}
+
+			if got != tt.want {
+				t.Errorf("Limiter.Allow() = %+v, want %+v", got, tt.want)
+			}
+			if l.Len() != tt.wantBucketLen {
+				t.Errorf("Limiter.Len() = %d, want %d", l.Len(), tt.wantBucketLen)
+			}
+		})
+	}
+}
@@ -99,7 +99,7 @@ func (rm *requestManager) GetIntValueOfURLParam(r *http.Request, key model.Prope
 func (rm *requestManager) GetUint32ValueOfURLParam(r *http.Request, key model.PropertyNameForDeveloper) (uint32, error) {
 	v, err := rm.GetIntValueOfURLParam(r, key)
 	if err != nil {
-		return model.InvalidID, nil
+		return model.InvalidID, err
 	}
 
 	return uint32(v), nil
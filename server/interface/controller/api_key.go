@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/application"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/infra/router"
+)
+
+// APIKeyController is the interface of APIKeyController.
+type APIKeyController interface {
+	CreateAPIKey(w http.ResponseWriter, r *http.Request)
+	ListAPIKeys(w http.ResponseWriter, r *http.Request)
+	RevokeAPIKey(w http.ResponseWriter, r *http.Request)
+}
+
+type apiKeyController struct {
+	rm    router.RequestManager
+	akApp application.APIKeyService
+}
+
+// NewAPIKeyController generates and returns APIKeyController.
+func NewAPIKeyController(rm router.RequestManager, akApp application.APIKeyService) APIKeyController {
+	return &apiKeyController{
+		rm:    rm,
+		akApp: akApp,
+	}
+}
+
+// CreateAPIKey creates an API key of the user who sent the request.
+// The key is returned only in this response.
+func (c *apiKeyController) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopeAdmin)
+	if !ok {
+		return
+	}
+
+	b, err := GetValueFromPayLoad(r)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	dto := &APIKeyRequestDTO{}
+	if err := unmarshalRequest(b, dto, "request body should be json of name, scopes and expiresAt"); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	apiKey, err := c.akApp.CreateAPIKey(r.Context(), me.ID, dto.Name, dto.ToScopes(), dto.ExpiresAt)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	w.Header().Set(CacheControl, "no-store")
+	if err := Response(w, http.StatusOK, TranslateFromAPIKeyToCreatedAPIKeyDTO(apiKey)); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
+
+// ListAPIKeys returns API keys of the user who sent the request.
+func (c *apiKeyController) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopeAdmin)
+	if !ok {
+		return
+	}
+
+	apiKeys, err := c.akApp.ListAPIKeys(r.Context(), me.ID)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	dtos := make([]*APIKeyDTO, 0, len(apiKeys))
+	for _, apiKey := range apiKeys {
+		dtos = append(dtos, TranslateFromAPIKeyToAPIKeyDTO(apiKey))
+	}
+
+	if err := Response(w, http.StatusOK, dtos); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
+
+// RevokeAPIKey deletes the API key specified by id.
+func (c *apiKeyController) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopeAdmin)
+	if !ok {
+		return
+	}
+
+	if _, err := c.akApp.RevokeAPIKey(r.Context(), me.ID, mux.Vars(r)["id"]); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if err := Response(w, http.StatusOK); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
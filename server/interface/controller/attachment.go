@@ -0,0 +1,159 @@
+package controller
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/application"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/infra/router"
+)
+
+// maxMultipartOverhead is the bytes allowed in the request body of upload other than the file,
+// which are boundaries, headers of parts and small fields.
+const maxMultipartOverhead = 1 << 20
+
+// attachmentSandbox is Content-Security-Policy of attachments, so that they can not run scripts
+// even if browsers render them as documents of the origin.
+const attachmentSandbox = "default-src 'none'; img-src 'self'; style-src 'unsafe-inline'; sandbox"
+
+// AttachmentController is the interface of AttachmentController.
+type AttachmentController interface {
+	UploadAttachment(w http.ResponseWriter, r *http.Request)
+	DownloadAttachment(w http.ResponseWriter, r *http.Request)
+}
+
+type attachmentController struct {
+	rm   router.RequestManager
+	aApp application.AttachmentService
+}
+
+// NewAttachmentController generates and returns AttachmentController.
+func NewAttachmentController(rm router.RequestManager, aApp application.AttachmentService) AttachmentController {
+	return &attachmentController{
+		rm:   rm,
+		aApp: aApp,
+	}
+}
+
+// UploadAttachment attaches the file sent as the field file of multipart/form-data to the comment specified by id.
+// The file is streamed to the storage without being buffered in memory.
+func (c *attachmentController) UploadAttachment(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopePostComments)
+	if !ok {
+		return
+	}
+
+	id, err := c.rm.GetUint32ValueOfURLParam(r, model.IDPropertyForDeveloper)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, model.MaxAttachmentSize+maxMultipartOverhead)
+	part, err := filePart(r)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+	defer part.Close()
+
+	attachment, err := c.aApp.UploadAttachment(r.Context(), me.ID, id, part.FileName(), part)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if err := Response(w, http.StatusOK, TranslateFromAttachmentToAttachmentDTO(attachment)); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
+
+// DownloadAttachment returns the content of the attachment specified by id, which supports range requests.
+// Images are shown inline and other files are downloaded, both with the type sniffed on upload.
+func (c *attachmentController) DownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopeReadThreads)
+	if !ok {
+		return
+	}
+
+	id, err := c.rm.GetUint32ValueOfURLParam(r, model.IDPropertyForDeveloper)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	attachment, content, err := c.aApp.OpenAttachment(r.Context(), me.ID, id)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+	defer func() {
+		if err := content.Close(); err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	disposition := "attachment"
+	if attachment.IsImage() {
+		disposition = "inline"
+	}
+
+	h := w.Header()
+	h.Set(ContentType, attachment.ContentType)
+	h.Set(ContentDisposition, mime.FormatMediaType(disposition, map[string]string{"filename": attachment.FileName}))
+	h.Set(ContentTypeOptions, "nosniff")
+	h.Set(ContentSecurityPolicy, attachmentSandbox)
+	// the content never changes, but it is private to users who can read the thread.
+	h.Set(CacheControl, "private, max-age=86400")
+	h.Set(ETag, `"`+attachment.SHA256+`"`)
+
+	http.ServeContent(w, r, "", attachment.CreatedAt, content)
+}
+
+// filePart returns the first part of the field file in multipart/form-data, which the caller must close.
+// Parts before it are skipped, and parts after it are not read.
+func filePart(r *http.Request) (*multipart.Part, error) {
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return nil, errors.WithStack(&model.InvalidParamError{
+			BaseErr:                   err,
+			PropertyNameForDeveloper:  model.FilePropertyForDeveloper,
+			PropertyNameForUser:       model.FilePropertyForUser,
+			PropertyValue:             r.Header.Get(ContentType),
+			InvalidReasonForDeveloper: "request body should be multipart/form-data",
+			InvalidReasonForUser:      "ファイルを送信してください",
+		})
+	}
+
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			return nil, errors.WithStack(&model.RequiredError{
+				PropertyNameForDeveloper: model.FilePropertyForDeveloper,
+				PropertyNameForUser:      model.FilePropertyForUser,
+			})
+		}
+		if err != nil {
+			return nil, errors.WithStack(&model.InvalidParamError{
+				BaseErr:                   err,
+				PropertyNameForDeveloper:  model.FilePropertyForDeveloper,
+				PropertyNameForUser:       model.FilePropertyForUser,
+				PropertyValue:             r.Header.Get(ContentType),
+				InvalidReasonForDeveloper: "failed to read multipart/form-data",
+				InvalidReasonForUser:      "ファイルを読み込めませんでした",
+			})
+		}
+
+		if part.FormName() == model.FilePropertyForDeveloper.String() && part.FileName() != "" {
+			return part, nil
+		}
+		part.Close()
+	}
+}
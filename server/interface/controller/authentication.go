@@ -14,18 +14,23 @@ import (
 // AuthenticationController is the interface of AuthenticationController.
 type AuthenticationController interface {
 	SignUp(w http.ResponseWriter, r *http.Request)
+	Login(w http.ResponseWriter, r *http.Request)
+	LoginWithSecondFactor(w http.ResponseWriter, r *http.Request)
+	Logout(w http.ResponseWriter, r *http.Request)
 }
 
 type authenticationController struct {
 	rm   router.RequestManager
 	aApp application.AuthenticationService
+	cp   CookiePolicy
 }
 
 // NewAuthenticationController generates and returns AuthenticationController.
-func NewAuthenticationController(rm router.RequestManager, uAPP application.AuthenticationService) AuthenticationController {
+func NewAuthenticationController(rm router.RequestManager, uAPP application.AuthenticationService, cp CookiePolicy) AuthenticationController {
 	return &authenticationController{
 		rm:   rm,
 		aApp: uAPP,
+		cp:   cp,
 	}
 }
 
@@ -36,7 +41,7 @@ func (c *authenticationController) SignUp(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	user, err := ParseUserFromPayLoad(b)
+	user, err := ParseUserFromPayload(b)
 	if err != nil {
 		ResponseAndLogError(w, err)
 		return
@@ -52,13 +57,17 @@ func (c *authenticationController) SignUp(w http.ResponseWriter, r *http.Request
 	user.UpdatedAt = time.Now()
 
 	ctx := r.Context()
-	user, err = c.aApp.SignUp(ctx, user)
+	user, session, err := c.aApp.SignUp(ctx, user, GetClient(r))
 	if err != nil {
 		ResponseAndLogError(w, err)
 		return
 	}
 
-	cookie := c.newCookieWithSessionID(user.SessionID, 86400)
+	cookie, err := c.cp.SessionCookie(session.Token)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
 	uDTO := TranslateFromUserToUserDTO(user)
 
 	if err := ResponseWithCookie(w, http.StatusOK, cookie, uDTO); err != nil {
@@ -67,26 +76,114 @@ func (c *authenticationController) SignUp(w http.ResponseWriter, r *http.Request
 	}
 }
 
-func ParseUserFromPayload(b []byte) (*model.User, error) {
-	u := &model.User{}
-	if err := json.Unmarshal(b, u); err != nil {
-		if err := json.Unmarshal(b, u); err != nil {
-			err = &model.InvalidDataError{
-				BaseErr:               err,
-				DataNameForDeveloper:  "request body",
-				DataValueForDeveloper: string(b),
-			}
-			return nil, errors.WithStack(err)
+func (c *authenticationController) Login(w http.ResponseWriter, r *http.Request) {
+	b, err := GetValueFromPayLoad(r)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	user, err := ParseUserFromPayload(b)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	user, err = model.NewUser(user.Name, user.Password)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	ctx := r.Context()
+	user, session, pending, err := c.aApp.Login(ctx, user, GetClient(r))
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	// the session is not created until the second factor is sent with the pending token.
+	if pending != nil {
+		w.Header().Set(CacheControl, "no-store")
+		if err := Response(w, http.StatusOK, TranslateFromPendingLoginToPendingLoginDTO(pending, time.Now())); err != nil {
+			ResponseAndLogError(w, err)
+			return
 		}
+		return
+	}
+
+	cookie, err := c.cp.SessionCookie(session.Token)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+	uDTO := TranslateFromUserToUserDTO(user)
+
+	if err := ResponseWithCookie(w, http.StatusOK, cookie, uDTO); err != nil {
+		ResponseAndLogError(w, err)
+		return
 	}
-	return u, nil
 }
 
-// newCookieWithSessionID generates and returns cookie with session id.
-func (c *authenticationController) newCookieWithSessionID(sessionID string, maxAge int) *http.Cookie {
-	return &http.Cookie{
-		Name:   model.SessionIDAtCookie,
-		Value:  sessionID,
-		MaxAge: maxAge,
+// LoginWithSecondFactor checks the code for the pending login and sets the cookie of the new session.
+func (c *authenticationController) LoginWithSecondFactor(w http.ResponseWriter, r *http.Request) {
+	b, err := GetValueFromPayLoad(r)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	dto := &SecondFactorLoginRequestDTO{}
+	if err := unmarshalRequest(b, dto, "request body should be json of pending token and code"); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	user, session, err := c.aApp.LoginWithSecondFactor(r.Context(), dto.PendingToken, dto.Code, GetClient(r))
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	cookie, err := c.cp.SessionCookie(session.Token)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if err := ResponseWithCookie(w, http.StatusOK, cookie, TranslateFromUserToUserDTO(user)); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
+
+// Logout deletes the session and clears the cookie.
+// The cookie is cleared even if the session is invalid.
+func (c *authenticationController) Logout(w http.ResponseWriter, r *http.Request) {
+	if token, err := c.cp.SessionToken(r); err == nil {
+		if err := c.aApp.Logout(r.Context(), token); err != nil {
+			ResponseAndLogError(w, err)
+			return
+		}
+	}
+
+	if err := ResponseWithCookie(w, http.StatusOK, c.cp.ClearSessionCookie()); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
+
+// ParseUserFromPayload parses User from payload.
+// Request body is not kept in error because it has password.
+func ParseUserFromPayload(b []byte) (*model.User, error) {
+	dto := &UserRequestDTO{}
+	if err := json.Unmarshal(b, dto); err != nil {
+		err = &model.InvalidDataError{
+			BaseErr:                   err,
+			DataNameForDeveloper:      "request body",
+			InvalidReasonForDeveloper: "request body should be json of user",
+		}
+		return nil, errors.WithStack(err)
 	}
+	return TranslateFromUserRequestDTOToUser(dto), nil
 }
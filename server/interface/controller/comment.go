@@ -0,0 +1,274 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/application"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/infra/router"
+)
+
+// CommentController is the interface of CommentController.
+type CommentController interface {
+	ListComments(w http.ResponseWriter, r *http.Request)
+	PostComment(w http.ResponseWriter, r *http.Request)
+	ListReplies(w http.ResponseWriter, r *http.Request)
+	EditComment(w http.ResponseWriter, r *http.Request)
+	DeleteComment(w http.ResponseWriter, r *http.Request)
+	ListRevisions(w http.ResponseWriter, r *http.Request)
+	AddReaction(w http.ResponseWriter, r *http.Request)
+	RemoveReaction(w http.ResponseWriter, r *http.Request)
+}
+
+type commentController struct {
+	rm   router.RequestManager
+	cApp application.CommentService
+}
+
+// NewCommentController generates and returns CommentController.
+func NewCommentController(rm router.RequestManager, cApp application.CommentService) CommentController {
+	return &commentController{
+		rm:   rm,
+		cApp: cApp,
+	}
+}
+
+// ListComments returns comments of the thread specified by id.
+func (c *commentController) ListComments(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopeReadThreads)
+	if !ok {
+		return
+	}
+
+	threadID, err := c.rm.GetUint32ValueOfURLParam(r, model.IDPropertyForDeveloper)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	comments, err := c.cApp.ListComments(r.Context(), me.ID, threadID)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	dtos := make([]*CommentDTO, 0, len(comments))
+	for _, comment := range comments {
+		dtos = append(dtos, TranslateFromCommentToCommentDTO(comment))
+	}
+
+	if err := Response(w, http.StatusOK, dtos); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
+
+// PostComment posts the comment to the thread specified by id.
+func (c *commentController) PostComment(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopePostComments)
+	if !ok {
+		return
+	}
+
+	threadID, err := c.rm.GetUint32ValueOfURLParam(r, model.IDPropertyForDeveloper)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	b, err := GetValueFromPayLoad(r)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	dto := &PostCommentRequestDTO{}
+	if err := unmarshalRequest(b, dto, "request body should be json of content and parentCommentId"); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	comment, err := c.cApp.PostComment(r.Context(), me.ID, threadID, dto.ParentCommentID, dto.Content)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if err := Response(w, http.StatusOK, TranslateFromCommentToCommentDTO(comment)); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
+
+// ListReplies returns a page of replies to the comment specified by id.
+// The page starts after the reply specified by the query after, and has at most the query limit replies.
+func (c *commentController) ListReplies(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopeReadThreads)
+	if !ok {
+		return
+	}
+
+	id, err := c.rm.GetUint32ValueOfURLParam(r, model.IDPropertyForDeveloper)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	after, err := GetUint32ValueOfQuery(r, model.AfterPropertyForDeveloper, 0)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	limit, err := GetUint32ValueOfQuery(r, model.LimitPropertyForDeveloper, 0)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	page, err := c.cApp.ListReplies(r.Context(), me.ID, id, after, int(limit))
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if err := Response(w, http.StatusOK, TranslateFromReplyPageToReplyPageDTO(page)); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
+
+// EditComment changes the content of the comment specified by id, which only the author can.
+func (c *commentController) EditComment(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopePostComments)
+	if !ok {
+		return
+	}
+
+	id, err := c.rm.GetUint32ValueOfURLParam(r, model.IDPropertyForDeveloper)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	b, err := GetValueFromPayLoad(r)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	dto := &CommentRequestDTO{}
+	if err := unmarshalRequest(b, dto, "request body should be json of content"); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	comment, err := c.cApp.EditComment(r.Context(), me, id, dto.Content)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if err := Response(w, http.StatusOK, TranslateFromCommentToCommentDTO(comment)); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
+
+// ListRevisions returns the edit history of the comment specified by id.
+func (c *commentController) ListRevisions(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopeReadThreads)
+	if !ok {
+		return
+	}
+
+	id, err := c.rm.GetUint32ValueOfURLParam(r, model.IDPropertyForDeveloper)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	revisions, err := c.cApp.ListRevisions(r.Context(), me.ID, id)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	dtos := make([]*CommentRevisionDTO, 0, len(revisions))
+	for _, revision := range revisions {
+		dtos = append(dtos, TranslateFromCommentRevisionToCommentRevisionDTO(revision))
+	}
+
+	if err := Response(w, http.StatusOK, dtos); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
+
+// DeleteComment deletes the comment specified by id.
+// Moderators can delete comments of others, and others get ForbiddenError.
+func (c *commentController) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopePostComments)
+	if !ok {
+		return
+	}
+
+	id, err := c.rm.GetUint32ValueOfURLParam(r, model.IDPropertyForDeveloper)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	comment, err := c.cApp.DeleteComment(r.Context(), me, id)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if err := Response(w, http.StatusOK, TranslateFromCommentToCommentDTO(comment)); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
+
+// AddReaction adds the reaction with the emoji to the comment specified by id, and returns the comment.
+func (c *commentController) AddReaction(w http.ResponseWriter, r *http.Request) {
+	c.react(w, r, c.cApp.AddReaction)
+}
+
+// RemoveReaction removes the reaction with the emoji from the comment specified by id, and returns the comment.
+func (c *commentController) RemoveReaction(w http.ResponseWriter, r *http.Request) {
+	c.react(w, r, c.cApp.RemoveReaction)
+}
+
+// react calls the method of CommentService with the comment id and emoji in the url.
+func (c *commentController) react(w http.ResponseWriter, r *http.Request, method func(ctx context.Context, userID, id uint32, emoji string) (*model.Comment, error)) {
+	me, ok := requireScope(w, r, model.ScopePostComments)
+	if !ok {
+		return
+	}
+
+	id, err := c.rm.GetUint32ValueOfURLParam(r, model.IDPropertyForDeveloper)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	emoji, err := c.rm.GetValueOfURLParamWithoutAcceptanceEmpty(r, model.EmojiPropertyForDeveloper)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	comment, err := method(r.Context(), me.ID, id, emoji)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if err := Response(w, http.StatusOK, TranslateFromCommentToCommentDTO(comment)); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
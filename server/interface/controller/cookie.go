@@ -0,0 +1,270 @@
+package controller
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/pkg/errors"
+)
+
+// hostPrefix is the prefix of cookie name which browsers accept only if Secure, Path=/ and no Domain.
+const hostPrefix = "__Host-"
+
+// CookieKey is the key which signs or encrypts cookie value.
+// ID is written in cookie value, so that cookies issued by an old key are verified by the same key.
+type CookieKey struct {
+	ID     string
+	Secret []byte
+}
+
+// CookieConfig is the configuration of CookiePolicy.
+type CookieConfig struct {
+	Name       string
+	HostPrefix bool
+	Domain     string
+	Path       string
+	Secure     bool
+	SameSite   http.SameSite
+	MaxAge     int
+	// Keys are the keys of cookie value. The first one is used for new cookies
+	// and the others are only used for verification, so that keys can be rotated.
+	Keys []CookieKey
+	// Encrypt encrypts cookie value in addition to authentication.
+	Encrypt bool
+}
+
+// CookiePolicy is the interface of CookiePolicy.
+// Every path which issues or clears the session cookie must use this.
+type CookiePolicy interface {
+	Name() string
+	SessionCookie(token string) (*http.Cookie, error)
+	ClearSessionCookie() *http.Cookie
+	SessionToken(r *http.Request) (string, error)
+}
+
+type cookiePolicy struct {
+	config CookieConfig
+	name   string
+}
+
+// NewCookiePolicy generates and returns CookiePolicy.
+func NewCookiePolicy(config CookieConfig) (CookiePolicy, error) {
+	if config.Name == "" {
+		config.Name = model.SessionIDAtCookie
+	}
+	if config.Path == "" {
+		config.Path = "/"
+	}
+	if config.SameSite == 0 {
+		config.SameSite = http.SameSiteLaxMode
+	}
+
+	if len(config.Keys) == 0 {
+		return nil, errors.New("at least one cookie key is required")
+	}
+	for _, k := range config.Keys {
+		if k.ID == "" || strings.Contains(k.ID, ".") || len(k.Secret) < 32 {
+			return nil, errors.Errorf("cookie key %q should have id without dot and secret of at least 32 bytes", k.ID)
+		}
+	}
+
+	name := config.Name
+	if config.HostPrefix {
+		if !config.Secure || config.Path != "/" || config.Domain != "" {
+			return nil, errors.New("cookie with __Host- prefix should be Secure, have Path=/ and no Domain")
+		}
+		name = hostPrefix + name
+	}
+
+	return &cookiePolicy{
+		config: config,
+		name:   name,
+	}, nil
+}
+
+// Name returns the name of the session cookie including the prefix.
+func (p *cookiePolicy) Name() string {
+	return p.name
+}
+
+// SessionCookie generates and returns the cookie which has encoded session token.
+func (p *cookiePolicy) SessionCookie(token string) (*http.Cookie, error) {
+	value, err := p.encode(token)
+	if err != nil {
+		return nil, errors.WithStack(&model.OtherServerError{
+			BaseErr:                   err,
+			InvalidReasonForDeveloper: "failed to encode session cookie",
+		})
+	}
+
+	c := p.cookie(value)
+	c.MaxAge = p.config.MaxAge
+	return c, nil
+}
+
+// ClearSessionCookie generates and returns the cookie which removes the session cookie.
+func (p *cookiePolicy) ClearSessionCookie() *http.Cookie {
+	c := p.cookie("")
+	c.MaxAge = -1
+	return c
+}
+
+// SessionToken returns the session token in the cookie of the request.
+// This returns AuthenticationErr if the cookie is absent or tampered.
+func (p *cookiePolicy) SessionToken(r *http.Request) (string, error) {
+	cookie, err := r.Cookie(p.name)
+	if err != nil || cookie.Value == "" {
+		return "", errors.WithStack(&model.AuthenticationErr{BaseErr: err})
+	}
+
+	token, err := p.decode(cookie.Value)
+	if err != nil {
+		return "", errors.WithStack(&model.AuthenticationErr{BaseErr: err})
+	}
+
+	return token, nil
+}
+
+func (p *cookiePolicy) cookie(value string) *http.Cookie {
+	return &http.Cookie{
+		Name:     p.name,
+		Value:    value,
+		Path:     p.config.Path,
+		Domain:   p.config.Domain,
+		Secure:   p.config.Secure,
+		HttpOnly: true,
+		SameSite: p.config.SameSite,
+	}
+}
+
+// encode encodes value as "<key id>.<payload>" by the first key.
+func (p *cookiePolicy) encode(value string) (string, error) {
+	key := p.config.Keys[0]
+
+	if p.config.Encrypt {
+		sealed, err := p.seal(key, []byte(value))
+		if err != nil {
+			return "", err
+		}
+		return key.ID + "." + base64.RawURLEncoding.EncodeToString(sealed), nil
+	}
+
+	payload := base64.RawURLEncoding.EncodeToString([]byte(value))
+	mac := p.mac(key, payload)
+	return key.ID + "." + payload + "." + base64.RawURLEncoding.EncodeToString(mac), nil
+}
+
+// decode verifies and decodes value encoded by encode.
+func (p *cookiePolicy) decode(value string) (string, error) {
+	parts := strings.Split(value, ".")
+
+	key, ok := p.key(parts[0])
+	if !ok {
+		return "", errors.Errorf("unknown cookie key %q", parts[0])
+	}
+
+	if p.config.Encrypt {
+		if len(parts) != 2 {
+			return "", errors.New("malformed cookie value")
+		}
+		sealed, err := base64.RawURLEncoding.DecodeString(parts[1])
+		if err != nil {
+			return "", errors.Wrap(err, "failed to decode cookie value")
+		}
+		plain, err := p.open(key, sealed)
+		if err != nil {
+			return "", err
+		}
+		return string(plain), nil
+	}
+
+	if len(parts) != 3 {
+		return "", errors.New("malformed cookie value")
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", errors.Wrap(err, "failed to decode cookie signature")
+	}
+	if !hmac.Equal(mac, p.mac(key, parts[1])) {
+		return "", errors.New("cookie signature does not match")
+	}
+	plain, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", errors.Wrap(err, "failed to decode cookie value")
+	}
+	return string(plain), nil
+}
+
+func (p *cookiePolicy) key(id string) (CookieKey, bool) {
+	for _, k := range p.config.Keys {
+		if k.ID == id {
+			return k, true
+		}
+	}
+	return CookieKey{}, false
+}
+
+// mac returns HMAC-SHA256 of payload bound to the cookie name and the key id.
+func (p *cookiePolicy) mac(key CookieKey, payload string) []byte {
+	h := hmac.New(sha256.New, deriveKey(key, "sign"))
+	fmt.Fprintf(h, "%s|%s|%s", p.name, key.ID, payload)
+	return h.Sum(nil)
+}
+
+// seal encrypts plain by AES-GCM. The cookie name is authenticated as additional data.
+func (p *cookiePolicy) seal(key CookieKey, plain []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, errors.Wrap(err, "failed to read nonce")
+	}
+
+	return aead.Seal(nonce, nonce, plain, []byte(p.name)), nil
+}
+
+// open decrypts sealed encrypted by seal.
+func (p *cookiePolicy) open(key CookieKey, sealed []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("malformed cookie value")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+
+	plain, err := aead.Open(nil, nonce, ciphertext, []byte(p.name))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt cookie value")
+	}
+	return plain, nil
+}
+
+func newAEAD(key CookieKey) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(deriveKey(key, "encrypt"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create cipher")
+	}
+	return cipher.NewGCM(block)
+}
+
+// deriveKey derives the key for a purpose, so that a secret is not used for both signing and encryption.
+func deriveKey(key CookieKey, purpose string) []byte {
+	h := hmac.New(sha256.New, key.Secret)
+	h.Write([]byte(purpose))
+	return h.Sum(nil)
+}
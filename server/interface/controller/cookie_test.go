@@ -0,0 +1,168 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/pkg/errors"
+)
+
+func Test_cookiePolicy_SessionToken(t *testing.T) {
+	oldKey := CookieKey{ID: "old", Secret: []byte(strings.Repeat("o", 32))}
+	newKey := CookieKey{ID: "new", Secret: []byte(strings.Repeat("n", 32))}
+
+	tests := []struct {
+		name        string
+		issueConfig CookieConfig
+		readConfig  CookieConfig
+		tamper      func(v string) string
+		wantErr     bool
+	}{
+		{
+			name:        "When signed cookie is not tampered, returns session id",
+			issueConfig: CookieConfig{Keys: []CookieKey{newKey}},
+			readConfig:  CookieConfig{Keys: []CookieKey{newKey}},
+			wantErr:     false,
+		},
+		{
+			name:        "When encrypted cookie is not tampered, returns session id",
+			issueConfig: CookieConfig{Keys: []CookieKey{newKey}, Encrypt: true},
+			readConfig:  CookieConfig{Keys: []CookieKey{newKey}, Encrypt: true},
+			wantErr:     false,
+		},
+		{
+			name:        "When cookie is signed by rotated key, returns session id",
+			issueConfig: CookieConfig{Keys: []CookieKey{oldKey}},
+			readConfig:  CookieConfig{Keys: []CookieKey{newKey, oldKey}},
+			wantErr:     false,
+		},
+		{
+			name:        "When cookie is signed by removed key, returns AuthenticationErr",
+			issueConfig: CookieConfig{Keys: []CookieKey{oldKey}},
+			readConfig:  CookieConfig{Keys: []CookieKey{newKey}},
+			wantErr:     true,
+		},
+		{
+			name:        "When signed value is tampered, returns AuthenticationErr",
+			issueConfig: CookieConfig{Keys: []CookieKey{newKey}},
+			readConfig:  CookieConfig{Keys: []CookieKey{newKey}},
+			tamper: func(v string) string {
+				parts := strings.Split(v, ".")
+				parts[1] = "YW5vdGhlclNlc3Npb25JRA"
+				return strings.Join(parts, ".")
+			},
+			wantErr: true,
+		},
+		{
+			name:        "When encrypted value is tampered, returns AuthenticationErr",
+			issueConfig: CookieConfig{Keys: []CookieKey{newKey}, Encrypt: true},
+			readConfig:  CookieConfig{Keys: []CookieKey{newKey}, Encrypt: true},
+			tamper: func(v string) string {
+				return v[:len(v)-2] + "AA"
+			},
+			wantErr: true,
+		},
+		{
+			name:        "When raw session id is given, returns AuthenticationErr",
+			issueConfig: CookieConfig{Keys: []CookieKey{newKey}},
+			readConfig:  CookieConfig{Keys: []CookieKey{newKey}},
+			tamper: func(v string) string {
+				return model.SessionTokenForTest
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issuer, err := NewCookiePolicy(tt.issueConfig)
+			if err != nil {
+				t.Fatal(err)
+			}
+			reader, err := NewCookiePolicy(tt.readConfig)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			cookie, err := issuer.SessionCookie(model.SessionTokenForTest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.tamper != nil {
+				cookie.Value = tt.tamper(cookie.Value)
+			}
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.AddCookie(cookie)
+
+			got, err := reader.SessionToken(r)
+			if tt.wantErr {
+				if _, ok := errors.Cause(err).(*model.AuthenticationErr); !ok {
+					t.Errorf("cookiePolicy.SessionToken() error = %v, want AuthenticationErr", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("cookiePolicy.SessionToken() error = %v", err)
+			}
+			if got != model.SessionTokenForTest {
+				t.Errorf("cookiePolicy.SessionToken() = %v, want %v", got, model.SessionTokenForTest)
+			}
+		})
+	}
+}
+
+func TestNewCookiePolicy(t *testing.T) {
+	key := CookieKey{ID: "key", Secret: []byte(strings.Repeat("k", 32))}
+
+	tests := []struct {
+		name     string
+		config   CookieConfig
+		wantName string
+		wantErr  bool
+	}{
+		{
+			name:     "When __Host- prefix is enabled with Secure, returns prefixed name",
+			config:   CookieConfig{HostPrefix: true, Secure: true, Keys: []CookieKey{key}},
+			wantName: "__Host-" + model.SessionIDAtCookie,
+		},
+		{
+			name:    "When __Host- prefix is enabled without Secure, returns error",
+			config:  CookieConfig{HostPrefix: true, Keys: []CookieKey{key}},
+			wantErr: true,
+		},
+		{
+			name:    "When __Host- prefix is enabled with Domain, returns error",
+			config:  CookieConfig{HostPrefix: true, Secure: true, Domain: "example.com", Keys: []CookieKey{key}},
+			wantErr: true,
+		},
+		{
+			name:    "When no key is given, returns error",
+			config:  CookieConfig{},
+			wantErr: true,
+		},
+		{
+			name:    "When secret is short, returns error",
+			config:  CookieConfig{Keys: []CookieKey{{ID: "short", Secret: []byte("short")}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewCookiePolicy(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewCookiePolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			c := got.ClearSessionCookie()
+			if c.Name != tt.wantName || !c.HttpOnly || c.Path != "/" || c.MaxAge != -1 {
+				t.Errorf("NewCookiePolicy().ClearSessionCookie() = %+v", c)
+			}
+		})
+	}
+}
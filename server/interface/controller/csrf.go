@@ -0,0 +1,174 @@
+package controller
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"net/url"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/util"
+	"github.com/pkg/errors"
+)
+
+// csrfTokenSize is the bytes of entropy of CSRF token.
+const csrfTokenSize = 32
+
+// CSRFTokenDTO is DTO of CSRF token.
+type CSRFTokenDTO struct {
+	Token string `json:"token"`
+}
+
+// CSRFController is the interface of CSRFController.
+type CSRFController interface {
+	GetToken(w http.ResponseWriter, r *http.Request)
+}
+
+type csrfController struct{}
+
+// NewCSRFController generates and returns CSRFController.
+func NewCSRFController() CSRFController {
+	return &csrfController{}
+}
+
+// GetToken returns CSRF token and sets it to cookie.
+// Token in cookie is reused so that other tabs keep working.
+func (c *csrfController) GetToken(w http.ResponseWriter, r *http.Request) {
+	if cookie, err := r.Cookie(model.CSRFTokenAtCookie); err == nil && cookie.Value != "" {
+		if err := Response(w, http.StatusOK, &CSRFTokenDTO{Token: cookie.Value}); err != nil {
+			ResponseAndLogError(w, err)
+		}
+		return
+	}
+
+	token, err := util.RandomToken(csrfTokenSize)
+	if err != nil {
+		ResponseAndLogError(w, errors.WithStack(&model.OtherServerError{
+			BaseErr:                   err,
+			InvalidReasonForDeveloper: "failed to generate csrf token",
+		}))
+		return
+	}
+
+	// This cookie is read by JavaScript of the client, so that it is not HttpOnly.
+	cookie := &http.Cookie{
+		Name:     model.CSRFTokenAtCookie,
+		Value:    token,
+		Path:     "/",
+		SameSite: http.SameSiteStrictMode,
+	}
+
+	if err := ResponseWithCookie(w, http.StatusOK, cookie, &CSRFTokenDTO{Token: token}); err != nil {
+		ResponseAndLogError(w, err)
+	}
+}
+
+// CSRFMiddleware is the interface of CSRFMiddleware.
+type CSRFMiddleware interface {
+	Handler(next http.Handler) http.Handler
+}
+
+type csrfMiddleware struct {
+	allowedOrigins map[string]bool
+}
+
+// NewCSRFMiddleware generates and returns CSRFMiddleware.
+// allowedOrigins is the origins allowed other than the host of the request, e.g. http://localhost:3000.
+func NewCSRFMiddleware(allowedOrigins []string) CSRFMiddleware {
+	m := make(map[string]bool, len(allowedOrigins))
+	for _, o := range allowedOrigins {
+		m[o] = true
+	}
+
+	return &csrfMiddleware{
+		allowedOrigins: m,
+	}
+}
+
+// Handler rejects state changing requests which are not sent from allowed origins
+// or do not have the same token in header as in cookie.
+// Request with Authorization header is exempt because browsers do not attach it to cross site requests,
+// and such request is authenticated only by the header.
+func (m *csrfMiddleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := GetBearerToken(r); ok || isSafeMethod(r.Method) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if err := m.check(r); err != nil {
+			ResponseAndLogError(w, err)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// check checks origin and token of the request.
+func (m *csrfMiddleware) check(r *http.Request) error {
+	if err := m.checkOrigin(r); err != nil {
+		return err
+	}
+
+	cookie, err := r.Cookie(model.CSRFTokenAtCookie)
+	if err != nil || cookie.Value == "" {
+		return errors.WithStack(&model.CSRFError{
+			BaseErr:                   err,
+			InvalidReasonForDeveloper: "csrf token cookie is missing",
+		})
+	}
+
+	header := r.Header.Get(CSRFHeader)
+	if subtle.ConstantTimeCompare([]byte(header), []byte(cookie.Value)) != 1 {
+		return errors.WithStack(&model.CSRFError{
+			InvalidReasonForDeveloper: "csrf token in header does not match cookie",
+		})
+	}
+
+	return nil
+}
+
+// checkOrigin checks Origin header, or Referer header if Origin is absent.
+// Request which has neither is left to the token check.
+func (m *csrfMiddleware) checkOrigin(r *http.Request) error {
+	origin := r.Header.Get(Origin)
+	if origin == "" {
+		referer := r.Header.Get(Referer)
+		if referer == "" {
+			return nil
+		}
+
+		u, err := url.Parse(referer)
+		if err != nil {
+			return errors.WithStack(&model.CSRFError{
+				BaseErr:                   err,
+				InvalidReasonForDeveloper: "referer is invalid",
+			})
+		}
+		origin = u.Scheme + "://" + u.Host
+	}
+
+	if m.allowedOrigins[origin] {
+		return nil
+	}
+
+	u, err := url.Parse(origin)
+	if err == nil && u.Host == r.Host {
+		return nil
+	}
+
+	return errors.WithStack(&model.CSRFError{
+		BaseErr:                   err,
+		InvalidReasonForDeveloper: "origin is not allowed: " + origin,
+	})
+}
+
+// isSafeMethod returns whether the method does not change state.
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	default:
+		return false
+	}
+}
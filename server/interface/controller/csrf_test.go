@@ -0,0 +1,112 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+)
+
+func Test_csrfMiddleware_Handler(t *testing.T) {
+	const token = "testCSRFToken"
+
+	tests := []struct {
+		name       string
+		method     string
+		origin     string
+		referer    string
+		cookie     string
+		header     string
+		wantStatus int
+	}{
+		{
+			name:       "When the method is safe, passes without token",
+			method:     http.MethodGet,
+			origin:     "http://evil.example.com",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "When token in header matches cookie and origin is the same host, passes",
+			method:     http.MethodPost,
+			origin:     "http://example.com",
+			cookie:     token,
+			header:     token,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "When origin is allowed, passes",
+			method:     http.MethodPost,
+			origin:     "http://localhost:3000",
+			cookie:     token,
+			header:     token,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "When origin is another site, returns 403",
+			method:     http.MethodPost,
+			origin:     "http://evil.example.com",
+			cookie:     token,
+			header:     token,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "When origin is absent and referer is another site, returns 403",
+			method:     http.MethodDelete,
+			referer:    "http://evil.example.com/page",
+			cookie:     token,
+			header:     token,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "When header is absent, returns 403",
+			method:     http.MethodPost,
+			origin:     "http://example.com",
+			cookie:     token,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "When header does not match cookie, returns 403",
+			method:     http.MethodPut,
+			origin:     "http://example.com",
+			cookie:     token,
+			header:     "anotherToken",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "When cookie is absent, returns 403",
+			method:     http.MethodPost,
+			header:     token,
+			wantStatus: http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewCSRFMiddleware([]string{"http://localhost:3000"})
+			h := m.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			r := httptest.NewRequest(tt.method, "http://example.com/api/signup", nil)
+			if tt.origin != "" {
+				r.Header.Set(Origin, tt.origin)
+			}
+			if tt.referer != "" {
+				r.Header.Set(Referer, tt.referer)
+			}
+			if tt.cookie != "" {
+				r.AddCookie(&http.Cookie{Name: model.CSRFTokenAtCookie, Value: tt.cookie})
+			}
+			if tt.header != "" {
+				r.Header.Set(CSRFHeader, tt.header)
+			}
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("csrfMiddleware.Handler() status = %d, want %d", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
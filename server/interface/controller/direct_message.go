@@ -0,0 +1,226 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/application"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/infra/router"
+)
+
+// DirectMessageController is the interface of DirectMessageController.
+type DirectMessageController interface {
+	ListConversations(w http.ResponseWriter, r *http.Request)
+	GetOrCreateConversation(w http.ResponseWriter, r *http.Request)
+	ListMessages(w http.ResponseWriter, r *http.Request)
+	PostMessage(w http.ResponseWriter, r *http.Request)
+	ListBlocks(w http.ResponseWriter, r *http.Request)
+	BlockUser(w http.ResponseWriter, r *http.Request)
+	UnblockUser(w http.ResponseWriter, r *http.Request)
+}
+
+type directMessageController struct {
+	rm    router.RequestManager
+	dmApp application.DirectMessageService
+}
+
+// NewDirectMessageController generates and returns DirectMessageController.
+func NewDirectMessageController(rm router.RequestManager, dmApp application.DirectMessageService) DirectMessageController {
+	return &directMessageController{
+		rm:    rm,
+		dmApp: dmApp,
+	}
+}
+
+// ListConversations returns direct conversations of the user who sent the request.
+func (c *directMessageController) ListConversations(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopeReadThreads)
+	if !ok {
+		return
+	}
+
+	conversations, err := c.dmApp.ListConversations(r.Context(), me.ID)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	dtos := make([]*DirectConversationDTO, 0, len(conversations))
+	for _, conversation := range conversations {
+		dtos = append(dtos, TranslateFromDirectConversationToDirectConversationDTO(conversation, me.ID))
+	}
+
+	if err := Response(w, http.StatusOK, dtos); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
+
+// GetOrCreateConversation returns the direct conversation with the user in the request body,
+// which is created if it does not exist yet.
+func (c *directMessageController) GetOrCreateConversation(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopePostComments)
+	if !ok {
+		return
+	}
+
+	b, err := GetValueFromPayLoad(r)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	dto := &DirectConversationRequestDTO{}
+	if err := unmarshalRequest(b, dto, "request body should be json of userId"); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	conversation, err := c.dmApp.GetOrCreateConversation(r.Context(), me.ID, dto.UserID)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if err := Response(w, http.StatusOK, TranslateFromDirectConversationToDirectConversationDTO(conversation, me.ID)); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
+
+// ListMessages returns messages of the direct conversation specified by id.
+func (c *directMessageController) ListMessages(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopeReadThreads)
+	if !ok {
+		return
+	}
+
+	threadID, err := c.rm.GetUint32ValueOfURLParam(r, model.IDPropertyForDeveloper)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	comments, err := c.dmApp.ListMessages(r.Context(), me.ID, threadID)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	dtos := make([]*CommentDTO, 0, len(comments))
+	for _, comment := range comments {
+		dtos = append(dtos, TranslateFromCommentToCommentDTO(comment))
+	}
+
+	if err := Response(w, http.StatusOK, dtos); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
+
+// PostMessage posts the message to the direct conversation specified by id.
+func (c *directMessageController) PostMessage(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopePostComments)
+	if !ok {
+		return
+	}
+
+	threadID, err := c.rm.GetUint32ValueOfURLParam(r, model.IDPropertyForDeveloper)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	b, err := GetValueFromPayLoad(r)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	dto := &CommentRequestDTO{}
+	if err := unmarshalRequest(b, dto, "request body should be json of content"); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	comment, err := c.dmApp.PostMessage(r.Context(), me.ID, threadID, dto.Content)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if err := Response(w, http.StatusOK, TranslateFromCommentToCommentDTO(comment)); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
+
+// ListBlocks returns users blocked by the user who sent the request.
+func (c *directMessageController) ListBlocks(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopeAdmin)
+	if !ok {
+		return
+	}
+
+	blocks, err := c.dmApp.ListBlocks(r.Context(), me.ID)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	dtos := make([]*UserBlockDTO, 0, len(blocks))
+	for _, block := range blocks {
+		dtos = append(dtos, TranslateFromUserBlockToUserBlockDTO(block))
+	}
+
+	if err := Response(w, http.StatusOK, dtos); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
+
+// BlockUser blocks the user specified by userID.
+func (c *directMessageController) BlockUser(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopeAdmin)
+	if !ok {
+		return
+	}
+
+	userID, err := c.rm.GetUint32ValueOfURLParam(r, model.UserIDPropertyForDeveloper)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	block, err := c.dmApp.BlockUser(r.Context(), me.ID, userID)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if err := Response(w, http.StatusOK, TranslateFromUserBlockToUserBlockDTO(block)); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
+
+// UnblockUser removes the block of the user specified by userID.
+func (c *directMessageController) UnblockUser(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopeAdmin)
+	if !ok {
+		return
+	}
+
+	userID, err := c.rm.GetUint32ValueOfURLParam(r, model.UserIDPropertyForDeveloper)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if err := c.dmApp.UnblockUser(r.Context(), me.ID, userID); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
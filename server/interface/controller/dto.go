@@ -1,27 +1,736 @@
 package controller
 
 import (
+	"encoding/base64"
+	"strings"
 	"time"
 
-	"github.com/sekky0905/nuxt-vue-go-chat/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
 )
 
-// UserDTO is DTO of User.
+// UserRequestDTO is DTO of User in request.
+type UserRequestDTO struct {
+	Name     string `json:"name"`
+	Password string `json:"password" secret:"true"`
+}
+
+// TranslateFromUserRequestDTOToUser translate from UserRequestDTO to User.
+func TranslateFromUserRequestDTOToUser(dto *UserRequestDTO) *model.User {
+	return &model.User{
+		Name:     dto.Name,
+		Password: dto.Password,
+	}
+}
+
+// TokenRequestDTO is DTO of request to issue tokens.
+// Code is required only if TOTP of the user is enabled.
+type TokenRequestDTO struct {
+	Name     string `json:"name"`
+	Password string `json:"password" secret:"true"`
+	Code     string `json:"code" secret:"true"`
+}
+
+// SecondFactorLoginRequestDTO is DTO of request of the second step of login.
+type SecondFactorLoginRequestDTO struct {
+	PendingToken string `json:"pendingToken" secret:"true"`
+	Code         string `json:"code" secret:"true"`
+}
+
+// TOTPCodeRequestDTO is DTO of request which has the code of TOTP or a recovery code.
+type TOTPCodeRequestDTO struct {
+	Code string `json:"code" secret:"true"`
+}
+
+// UserNameRequestDTO is DTO of request to change name of user.
+type UserNameRequestDTO struct {
+	Name string `json:"name"`
+}
+
+// PasswordChangeRequestDTO is DTO of request to change password of user.
+type PasswordChangeRequestDTO struct {
+	OldPassword string `json:"oldPassword" secret:"true"`
+	NewPassword string `json:"newPassword" secret:"true"`
+}
+
+// EmailRequestDTO is DTO of request which has the email.
+type EmailRequestDTO struct {
+	Email string `json:"email"`
+}
+
+// EmailTokenRequestDTO is DTO of request which has the token sent by email.
+type EmailTokenRequestDTO struct {
+	Token string `json:"token" secret:"true"`
+}
+
+// PasswordResetRequestDTO is DTO of request to reset password by the token sent by email.
+type PasswordResetRequestDTO struct {
+	Token       string `json:"token" secret:"true"`
+	NewPassword string `json:"newPassword" secret:"true"`
+}
+
+// UserDTO is DTO of User in response.
+// This must not have password or session id, session id is only sent as cookie.
+// Email is only sent to the user itself.
 type UserDTO struct {
-	ID        uint32    `json:"id"`
-	Name      string    `json:"name"`
-	SessionID string    `json:"sessionId"`
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
+	ID            uint32    `json:"id"`
+	Name          string    `json:"name"`
+	Email         string    `json:"email,omitempty"`
+	EmailVerified bool      `json:"emailVerified"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
 }
 
 // TranslateFromUserToUserDTO translate from User to UserDTO.
 func TranslateFromUserToUserDTO(user *model.User) *UserDTO {
 	return &UserDTO{
-		ID:        user.ID,
-		Name:      user.Name,
-		SessionID: user.SessionID,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
+		ID:            user.ID,
+		Name:          user.Name,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerified,
+		CreatedAt:     user.CreatedAt,
+		UpdatedAt:     user.UpdatedAt,
+	}
+}
+
+// SessionDTO is DTO of Session in response.
+// This must not have the token, the token is only sent as cookie.
+type SessionDTO struct {
+	ID         string    `json:"id"`
+	UserAgent  string    `json:"userAgent"`
+	IPAddress  string    `json:"ipAddress"`
+	Current    bool      `json:"current"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastSeenAt time.Time `json:"lastSeenAt"`
+}
+
+// TranslateFromSessionToSessionDTO translate from Session to SessionDTO.
+// currentID is the id of the session of the request.
+func TranslateFromSessionToSessionDTO(session *model.Session, currentID string) *SessionDTO {
+	return &SessionDTO{
+		ID:         session.ID,
+		UserAgent:  session.UserAgent,
+		IPAddress:  session.IPAddress,
+		Current:    session.ID == currentID,
+		CreatedAt:  session.CreatedAt,
+		LastSeenAt: session.LastSeenAt,
+	}
+}
+
+// RefreshTokenRequestDTO is DTO of request which has refresh token.
+type RefreshTokenRequestDTO struct {
+	RefreshToken string `json:"refreshToken" secret:"true"`
+}
+
+// TokenDTO is DTO of issued tokens in response.
+// This is the only response which has credentials, so that it is sent with Cache-Control: no-store.
+type TokenDTO struct {
+	AccessToken  string `json:"accessToken"`
+	TokenType    string `json:"tokenType"`
+	ExpiresIn    int64  `json:"expiresIn"`
+	RefreshToken string `json:"refreshToken"`
+}
+
+// TranslateFromTokenPairToTokenDTO translate from TokenPair to TokenDTO.
+func TranslateFromTokenPairToTokenDTO(pair *model.TokenPair, now time.Time) *TokenDTO {
+	return &TokenDTO{
+		AccessToken:  pair.AccessToken,
+		TokenType:    strings.TrimSpace(bearerScheme),
+		ExpiresIn:    int64(pair.ExpiresAt.Sub(now).Seconds()),
+		RefreshToken: pair.RefreshToken,
+	}
+}
+
+// PendingLoginDTO is DTO of login which waits for the second factor in response.
+// The token is sent back with the code, so that this is sent with Cache-Control: no-store.
+type PendingLoginDTO struct {
+	TwoFactorRequired bool   `json:"twoFactorRequired"`
+	PendingToken      string `json:"pendingToken"`
+	ExpiresIn         int64  `json:"expiresIn"`
+}
+
+// TranslateFromPendingLoginToPendingLoginDTO translate from PendingLogin to PendingLoginDTO.
+func TranslateFromPendingLoginToPendingLoginDTO(pending *model.PendingLogin, now time.Time) *PendingLoginDTO {
+	return &PendingLoginDTO{
+		TwoFactorRequired: true,
+		PendingToken:      pending.Token,
+		ExpiresIn:         int64(pending.ExpiresAt.Sub(now).Seconds()),
+	}
+}
+
+// TOTPEnrollmentDTO is DTO of TOTP which waits for confirmation in response.
+// This has the secret, so that it is sent with Cache-Control: no-store.
+type TOTPEnrollmentDTO struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioningUri"`
+}
+
+// TranslateFromTOTPToTOTPEnrollmentDTO translate from TOTP to TOTPEnrollmentDTO.
+func TranslateFromTOTPToTOTPEnrollmentDTO(totp *model.TOTP, uri string) *TOTPEnrollmentDTO {
+	return &TOTPEnrollmentDTO{
+		Secret:          totp.Secret,
+		ProvisioningURI: uri,
+	}
+}
+
+// RecoveryCodesDTO is DTO of recovery codes in response.
+// This is the only response which has the codes, so that it is sent with Cache-Control: no-store.
+type RecoveryCodesDTO struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+// JWKSetDTO is DTO of public keys in the format of JWK Set.
+type JWKSetDTO struct {
+	Keys []*JWKDTO `json:"keys"`
+}
+
+// JWKDTO is DTO of a public key in the format of JWK.
+type JWKDTO struct {
+	KeyType   string `json:"kty"`
+	Curve     string `json:"crv"`
+	KeyID     string `json:"kid"`
+	X         string `json:"x"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+}
+
+// TranslateFromPublicKeysToJWKSetDTO translate from PublicKeys of Ed25519 to JWKSetDTO.
+func TranslateFromPublicKeysToJWKSetDTO(keys []*model.PublicKey) *JWKSetDTO {
+	dto := &JWKSetDTO{
+		Keys: make([]*JWKDTO, 0, len(keys)),
+	}
+	for _, k := range keys {
+		dto.Keys = append(dto.Keys, &JWKDTO{
+			KeyType:   "OKP",
+			Curve:     "Ed25519",
+			KeyID:     k.ID,
+			X:         base64.RawURLEncoding.EncodeToString(k.Key),
+			Use:       "sig",
+			Algorithm: "EdDSA",
+		})
+	}
+	return dto
+}
+
+// APIKeyRequestDTO is DTO of request to create API key.
+type APIKeyRequestDTO struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+// ToScopes returns scopes of the request.
+func (dto *APIKeyRequestDTO) ToScopes() model.Scopes {
+	scopes := make(model.Scopes, 0, len(dto.Scopes))
+	for _, sc := range dto.Scopes {
+		scopes = append(scopes, model.Scope(sc))
+	}
+	return scopes
+}
+
+// APIKeyDTO is DTO of APIKey in response.
+// This must not have the key, the key is only sent once by CreatedAPIKeyDTO.
+type APIKeyDTO struct {
+	ID         string     `json:"id"`
+	Prefix     string     `json:"prefix"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	ExpiresAt  *time.Time `json:"expiresAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+}
+
+// TranslateFromAPIKeyToAPIKeyDTO translate from APIKey to APIKeyDTO.
+func TranslateFromAPIKeyToAPIKeyDTO(apiKey *model.APIKey) *APIKeyDTO {
+	scopes := make([]string, 0, len(apiKey.Scopes))
+	for _, sc := range apiKey.Scopes {
+		scopes = append(scopes, string(sc))
+	}
+
+	return &APIKeyDTO{
+		ID:         apiKey.ID,
+		Prefix:     apiKey.Prefix,
+		Name:       apiKey.Name,
+		Scopes:     scopes,
+		CreatedAt:  apiKey.CreatedAt,
+		ExpiresAt:  apiKey.ExpiresAt,
+		LastUsedAt: apiKey.LastUsedAt,
+	}
+}
+
+// CreatedAPIKeyDTO is DTO of created API key in response.
+// This is the only response which has the key, so that it is sent with Cache-Control: no-store.
+type CreatedAPIKeyDTO struct {
+	*APIKeyDTO
+	Key string `json:"key"`
+}
+
+// TranslateFromAPIKeyToCreatedAPIKeyDTO translate from APIKey to CreatedAPIKeyDTO.
+func TranslateFromAPIKeyToCreatedAPIKeyDTO(apiKey *model.APIKey) *CreatedAPIKeyDTO {
+	return &CreatedAPIKeyDTO{
+		APIKeyDTO: TranslateFromAPIKeyToAPIKeyDTO(apiKey),
+		Key:       apiKey.Key,
+	}
+}
+
+// ThreadRequestDTO is DTO of request to create thread.
+// Visibility is public or private, and public if it is empty.
+type ThreadRequestDTO struct {
+	Title      string `json:"title"`
+	Visibility string `json:"visibility"`
+}
+
+// ThreadDTO is DTO of Thread in response.
+type ThreadDTO struct {
+	ID         uint32    `json:"id"`
+	Title      string    `json:"title"`
+	Visibility string    `json:"visibility"`
+	UserID     uint32    `json:"userId"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// TranslateFromThreadToThreadDTO translate from Thread to ThreadDTO.
+func TranslateFromThreadToThreadDTO(thread *model.Thread) *ThreadDTO {
+	return &ThreadDTO{
+		ID:         thread.ID,
+		Title:      thread.Title,
+		Visibility: thread.Visibility.String(),
+		UserID:     thread.UserID,
+		CreatedAt:  thread.CreatedAt,
+		UpdatedAt:  thread.UpdatedAt,
+	}
+}
+
+// ThreadSummaryDTO is DTO of ThreadSummary in response, which is ThreadDTO with unread comments.
+// FirstUnreadCommentID is 0 if there is no unread comment.
+type ThreadSummaryDTO struct {
+	*ThreadDTO
+	UnreadCount          uint32 `json:"unreadCount"`
+	FirstUnreadCommentID uint32 `json:"firstUnreadCommentId"`
+}
+
+// TranslateFromThreadSummaryToThreadSummaryDTO translate from ThreadSummary to ThreadSummaryDTO.
+func TranslateFromThreadSummaryToThreadSummaryDTO(summary *model.ThreadSummary) *ThreadSummaryDTO {
+	return &ThreadSummaryDTO{
+		ThreadDTO:            TranslateFromThreadToThreadDTO(summary.Thread),
+		UnreadCount:          summary.UnreadCount,
+		FirstUnreadCommentID: summary.FirstUnreadCommentID,
+	}
+}
+
+// ThreadReadRequestDTO is DTO of request to mark thread read up to the comment.
+type ThreadReadRequestDTO struct {
+	CommentID uint32 `json:"commentId"`
+}
+
+// ThreadMemberDTO is DTO of ThreadMember in response.
+type ThreadMemberDTO struct {
+	ThreadID  uint32    `json:"threadId"`
+	UserID    uint32    `json:"userId"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// TranslateFromThreadMemberToThreadMemberDTO translate from ThreadMember to ThreadMemberDTO.
+func TranslateFromThreadMemberToThreadMemberDTO(member *model.ThreadMember) *ThreadMemberDTO {
+	return &ThreadMemberDTO{
+		ThreadID:  member.ThreadID,
+		UserID:    member.UserID,
+		Role:      member.Role.String(),
+		CreatedAt: member.CreatedAt,
+	}
+}
+
+// ThreadInviteRequestDTO is DTO of request to create invitation to thread.
+// ExpiresIn is the lifetime in seconds, and the default is used if it is 0.
+// MaxUses is the number of users who can join by the invitation, and 0 means no limit.
+type ThreadInviteRequestDTO struct {
+	ExpiresIn uint32 `json:"expiresIn"`
+	MaxUses   uint32 `json:"maxUses"`
+}
+
+// ThreadInviteDTO is DTO of created invitation in response.
+// This is the only response which has the token, so that it is sent with Cache-Control: no-store.
+type ThreadInviteDTO struct {
+	Token     string    `json:"token"`
+	ThreadID  uint32    `json:"threadId"`
+	MaxUses   uint32    `json:"maxUses"`
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// TranslateFromThreadInviteToThreadInviteDTO translate from ThreadInvite to ThreadInviteDTO.
+func TranslateFromThreadInviteToThreadInviteDTO(invite *model.ThreadInvite) *ThreadInviteDTO {
+	return &ThreadInviteDTO{
+		Token:     invite.Token,
+		ThreadID:  invite.ThreadID,
+		MaxUses:   invite.MaxUses,
+		CreatedAt: invite.CreatedAt,
+		ExpiresAt: invite.ExpiresAt,
+	}
+}
+
+// ThreadInviteAcceptRequestDTO is DTO of request to accept invitation to thread.
+type ThreadInviteAcceptRequestDTO struct {
+	Token string `json:"token"`
+}
+
+// CommentRequestDTO is DTO of request to post comment.
+type CommentRequestDTO struct {
+	Content string `json:"content"`
+}
+
+// PostCommentRequestDTO is DTO of request to post comment to thread.
+// ParentCommentID is the comment to reply to, and is omitted to post to the thread.
+type PostCommentRequestDTO struct {
+	Content         string `json:"content"`
+	ParentCommentID uint32 `json:"parentCommentId"`
+}
+
+// CommentDTO is DTO of Comment in response.
+// Content of the deleted comment is empty unless the user can read deleted comments, and the client shows it as deleted.
+type CommentDTO struct {
+	ID       uint32 `json:"id"`
+	ThreadID uint32 `json:"threadId"`
+	UserID   uint32 `json:"userId"`
+	// ParentCommentID is omitted unless the comment is a reply.
+	ParentCommentID uint32              `json:"parentCommentId,omitempty"`
+	Depth           uint8               `json:"depth"`
+	Content         string              `json:"content"`
+	Edited          bool                `json:"edited"`
+	Deleted         bool                `json:"deleted"`
+	Reactions       []*ReactionCountDTO `json:"reactions"`
+	Replies         *ReplySummaryDTO    `json:"replies,omitempty"`
+	Attachments     []*AttachmentDTO    `json:"attachments"`
+	CreatedAt       time.Time           `json:"createdAt"`
+	UpdatedAt       time.Time           `json:"updatedAt"`
+}
+
+// TranslateFromCommentToCommentDTO translate from Comment to CommentDTO.
+func TranslateFromCommentToCommentDTO(comment *model.Comment) *CommentDTO {
+	dto := &CommentDTO{
+		ID:              comment.ID,
+		ThreadID:        comment.ThreadID,
+		UserID:          comment.UserID,
+		ParentCommentID: comment.ParentCommentID,
+		Depth:           comment.Depth,
+		Content:         comment.Content,
+		Edited:          comment.IsEdited(),
+		Deleted:         comment.IsDeleted(),
+		Reactions:       make([]*ReactionCountDTO, 0, len(comment.Reactions)),
+		Attachments:     make([]*AttachmentDTO, 0, len(comment.Attachments)),
+		CreatedAt:       comment.CreatedAt,
+		UpdatedAt:       comment.UpdatedAt,
+	}
+	for _, count := range comment.Reactions {
+		dto.Reactions = append(dto.Reactions, TranslateFromReactionCountToReactionCountDTO(count))
+	}
+	if comment.Replies != nil {
+		dto.Replies = TranslateFromReplySummaryToReplySummaryDTO(comment.Replies)
+	}
+	for _, attachment := range comment.Attachments {
+		dto.Attachments = append(dto.Attachments, TranslateFromAttachmentToAttachmentDTO(attachment))
+	}
+	return dto
+}
+
+// AttachmentDTO is DTO of Attachment in response.
+// The content is downloaded from /api/attachments/{id}.
+type AttachmentDTO struct {
+	ID          uint32    `json:"id"`
+	CommentID   uint32    `json:"commentId"`
+	UserID      uint32    `json:"userId"`
+	FileName    string    `json:"fileName"`
+	ContentType string    `json:"contentType"`
+	Size        int64     `json:"size"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// TranslateFromAttachmentToAttachmentDTO translate from Attachment to AttachmentDTO.
+func TranslateFromAttachmentToAttachmentDTO(attachment *model.Attachment) *AttachmentDTO {
+	return &AttachmentDTO{
+		ID:          attachment.ID,
+		CommentID:   attachment.CommentID,
+		UserID:      attachment.UserID,
+		FileName:    attachment.FileName,
+		ContentType: attachment.ContentType,
+		Size:        attachment.Size,
+		CreatedAt:   attachment.CreatedAt,
+	}
+}
+
+// ReplySummaryDTO is DTO of ReplySummary in response.
+type ReplySummaryDTO struct {
+	ReplyCount        uint32    `json:"replyCount"`
+	LatestReplyID     uint32    `json:"latestReplyId"`
+	LatestReplyUserID uint32    `json:"latestReplyUserId"`
+	LatestReplyAt     time.Time `json:"latestReplyAt"`
+}
+
+// TranslateFromReplySummaryToReplySummaryDTO translate from ReplySummary to ReplySummaryDTO.
+func TranslateFromReplySummaryToReplySummaryDTO(summary *model.ReplySummary) *ReplySummaryDTO {
+	return &ReplySummaryDTO{
+		ReplyCount:        summary.ReplyCount,
+		LatestReplyID:     summary.LatestReplyID,
+		LatestReplyUserID: summary.LatestReplyUserID,
+		LatestReplyAt:     summary.LatestReplyAt,
 	}
 }
+
+// ReplyPageDTO is DTO of ReplyPage in response.
+// The next page is requested with the id of the last reply as after if HasMore is true.
+type ReplyPageDTO struct {
+	ParentCommentID uint32        `json:"parentCommentId"`
+	Replies         []*CommentDTO `json:"replies"`
+	HasMore         bool          `json:"hasMore"`
+}
+
+// TranslateFromReplyPageToReplyPageDTO translate from ReplyPage to ReplyPageDTO.
+func TranslateFromReplyPageToReplyPageDTO(page *model.ReplyPage) *ReplyPageDTO {
+	dto := &ReplyPageDTO{
+		ParentCommentID: page.ParentCommentID,
+		Replies:         make([]*CommentDTO, 0, len(page.Replies)),
+		HasMore:         page.HasMore,
+	}
+	for _, reply := range page.Replies {
+		dto.Replies = append(dto.Replies, TranslateFromCommentToCommentDTO(reply))
+	}
+	return dto
+}
+
+// CommentRevisionDTO is DTO of CommentRevision in response.
+// Content is what the comment was before EditorID edited it at EditedAt.
+type CommentRevisionDTO struct {
+	ID        uint32    `json:"id"`
+	CommentID uint32    `json:"commentId"`
+	EditorID  uint32    `json:"editorId"`
+	Content   string    `json:"content"`
+	EditedAt  time.Time `json:"editedAt"`
+}
+
+// TranslateFromCommentRevisionToCommentRevisionDTO translate from CommentRevision to CommentRevisionDTO.
+func TranslateFromCommentRevisionToCommentRevisionDTO(revision *model.CommentRevision) *CommentRevisionDTO {
+	return &CommentRevisionDTO{
+		ID:        revision.ID,
+		CommentID: revision.CommentID,
+		EditorID:  revision.EditorID,
+		Content:   revision.Content,
+		EditedAt:  revision.CreatedAt,
+	}
+}
+
+// NotificationDTO is DTO of Notification in response.
+// The content of the comment is read from the thread, which checks that the user can still read it.
+type NotificationDTO struct {
+	ID        uint32     `json:"id"`
+	Kind      string     `json:"kind"`
+	ThreadID  uint32     `json:"threadId"`
+	CommentID uint32     `json:"commentId"`
+	ActorID   uint32     `json:"actorId"`
+	Read      bool       `json:"read"`
+	CreatedAt time.Time  `json:"createdAt"`
+	ReadAt    *time.Time `json:"readAt"`
+}
+
+// TranslateFromNotificationToNotificationDTO translate from Notification to NotificationDTO.
+func TranslateFromNotificationToNotificationDTO(notification *model.Notification) *NotificationDTO {
+	dto := &NotificationDTO{
+		ID:        notification.ID,
+		Kind:      string(notification.Kind),
+		ThreadID:  notification.ThreadID,
+		CommentID: notification.CommentID,
+		ActorID:   notification.ActorID,
+		Read:      notification.IsRead(),
+		CreatedAt: notification.CreatedAt,
+	}
+	if notification.IsRead() {
+		readAt := notification.ReadAt
+		dto.ReadAt = &readAt
+	}
+	return dto
+}
+
+// NotificationPageDTO is DTO of NotificationPage in response.
+// The next page is requested with the id of the last notification as before if HasMore is true.
+type NotificationPageDTO struct {
+	Notifications []*NotificationDTO `json:"notifications"`
+	UnreadCount   uint32             `json:"unreadCount"`
+	HasMore       bool               `json:"hasMore"`
+}
+
+// TranslateFromNotificationPageToNotificationPageDTO translate from NotificationPage to NotificationPageDTO.
+func TranslateFromNotificationPageToNotificationPageDTO(page *model.NotificationPage) *NotificationPageDTO {
+	dto := &NotificationPageDTO{
+		Notifications: make([]*NotificationDTO, 0, len(page.Notifications)),
+		UnreadCount:   page.UnreadCount,
+		HasMore:       page.HasMore,
+	}
+	for _, notification := range page.Notifications {
+		dto.Notifications = append(dto.Notifications, TranslateFromNotificationToNotificationDTO(notification))
+	}
+	return dto
+}
+
+// TextRangeDTO is DTO of TextRange in response, which is counted in characters rather than bytes or UTF-16.
+type TextRangeDTO struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// SearchHitDTO is DTO of SearchHit in response.
+type SearchHitDTO struct {
+	Comment    *CommentDTO     `json:"comment"`
+	Highlights []*TextRangeDTO `json:"highlights"`
+}
+
+// SearchResultDTO is DTO of SearchResult in response.
+// The next page is requested with NextBefore as before if HasMore is true.
+type SearchResultDTO struct {
+	Hits       []*SearchHitDTO `json:"hits"`
+	HasMore    bool            `json:"hasMore"`
+	NextBefore uint32          `json:"nextBefore,omitempty"`
+}
+
+// TranslateFromSearchResultToSearchResultDTO translate from SearchResult to SearchResultDTO.
+func TranslateFromSearchResultToSearchResultDTO(result *model.SearchResult) *SearchResultDTO {
+	dto := &SearchResultDTO{
+		Hits:       make([]*SearchHitDTO, 0, len(result.Hits)),
+		HasMore:    result.HasMore,
+		NextBefore: result.NextBeforeID,
+	}
+	for _, hit := range result.Hits {
+		hitDTO := &SearchHitDTO{
+			Comment:    TranslateFromCommentToCommentDTO(hit.Comment),
+			Highlights: make([]*TextRangeDTO, 0, len(hit.Highlights)),
+		}
+		for _, r := range hit.Highlights {
+			hitDTO.Highlights = append(hitDTO.Highlights, &TextRangeDTO{Start: r.Start, End: r.End})
+		}
+		dto.Hits = append(dto.Hits, hitDTO)
+	}
+	return dto
+}
+
+// ReactionCountDTO is DTO of ReactionCount in response.
+type ReactionCountDTO struct {
+	Emoji       string `json:"emoji"`
+	Count       uint32 `json:"count"`
+	ReactedByMe bool   `json:"reactedByMe"`
+}
+
+// TranslateFromReactionCountToReactionCountDTO translate from ReactionCount to ReactionCountDTO.
+func TranslateFromReactionCountToReactionCountDTO(count *model.ReactionCount) *ReactionCountDTO {
+	return &ReactionCountDTO{
+		Emoji:       count.Emoji,
+		Count:       count.Count,
+		ReactedByMe: count.ReactedByMe,
+	}
+}
+
+// DirectConversationRequestDTO is DTO of request to get or create direct conversation with the user.
+type DirectConversationRequestDTO struct {
+	UserID uint32 `json:"userId"`
+}
+
+// DirectConversationDTO is DTO of DirectConversation in response.
+// ID is the id of the thread of the conversation, and UserID is the partner of the user who requests.
+type DirectConversationDTO struct {
+	ID            uint32    `json:"id"`
+	UserID        uint32    `json:"userId"`
+	LastMessageAt time.Time `json:"lastMessageAt"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+// TranslateFromDirectConversationToDirectConversationDTO translate from DirectConversation to DirectConversationDTO for the user.
+func TranslateFromDirectConversationToDirectConversationDTO(conversation *model.DirectConversation, userID uint32) *DirectConversationDTO {
+	return &DirectConversationDTO{
+		ID:            conversation.ThreadID,
+		UserID:        conversation.OtherUserID(userID),
+		LastMessageAt: conversation.LastMessageAt,
+		CreatedAt:     conversation.CreatedAt,
+	}
+}
+
+// UserBlockDTO is DTO of UserBlock in response.
+type UserBlockDTO struct {
+	UserID    uint32    `json:"userId"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// TranslateFromUserBlockToUserBlockDTO translate from UserBlock to UserBlockDTO.
+func TranslateFromUserBlockToUserBlockDTO(block *model.UserBlock) *UserBlockDTO {
+	return &UserBlockDTO{
+		UserID:    block.BlockedUserID,
+		CreatedAt: block.CreatedAt,
+	}
+}
+
+// HeartbeatRequestDTO is DTO of request of heartbeat.
+// Active is whether the user has operated the client since the last heartbeat, and Away is set by the user.
+type HeartbeatRequestDTO struct {
+	Active bool `json:"active"`
+	Away   bool `json:"away"`
+}
+
+// PresenceDTO is DTO of Presence in response.
+// LastActiveAt is omitted if the user is offline.
+type PresenceDTO struct {
+	UserID       uint32     `json:"userId"`
+	Status       string     `json:"status"`
+	LastActiveAt *time.Time `json:"lastActiveAt,omitempty"`
+}
+
+// TranslateFromPresenceToPresenceDTO translate from Presence to PresenceDTO.
+func TranslateFromPresenceToPresenceDTO(presence *model.Presence) *PresenceDTO {
+	dto := &PresenceDTO{
+		UserID: presence.UserID,
+		Status: presence.Status.String(),
+	}
+	if !presence.LastActiveAt.IsZero() {
+		lastActiveAt := presence.LastActiveAt
+		dto.LastActiveAt = &lastActiveAt
+	}
+	return dto
+}
+
+// ThreadPresenceDTO is DTO of ThreadPresence in response.
+type ThreadPresenceDTO struct {
+	ThreadID      uint32         `json:"threadId"`
+	Presences     []*PresenceDTO `json:"presences"`
+	TypingUserIDs []uint32       `json:"typingUserIds"`
+}
+
+// TranslateFromThreadPresenceToThreadPresenceDTO translate from ThreadPresence to ThreadPresenceDTO.
+func TranslateFromThreadPresenceToThreadPresenceDTO(tp *model.ThreadPresence) *ThreadPresenceDTO {
+	dto := &ThreadPresenceDTO{
+		ThreadID:      tp.ThreadID,
+		Presences:     make([]*PresenceDTO, 0, len(tp.Presences)),
+		TypingUserIDs: make([]uint32, 0, len(tp.TypingUserIDs)),
+	}
+	for _, p := range tp.Presences {
+		dto.Presences = append(dto.Presences, TranslateFromPresenceToPresenceDTO(p))
+	}
+	dto.TypingUserIDs = append(dto.TypingUserIDs, tp.TypingUserIDs...)
+	return dto
+}
+
+// RolesDTO is DTO of roles of user in response.
+type RolesDTO struct {
+	Roles []string `json:"roles"`
+}
+
+// TranslateFromRolesToRolesDTO translate from Roles to RolesDTO.
+func TranslateFromRolesToRolesDTO(roles model.Roles) *RolesDTO {
+	dto := &RolesDTO{
+		Roles: make([]string, 0, len(roles)),
+	}
+	for _, r := range roles {
+		dto.Roles = append(dto.Roles, r.String())
+	}
+	return dto
+}
+
+// ModeratorsDTO is DTO of moderators of thread in response.
+type ModeratorsDTO struct {
+	UserIDs []uint32 `json:"userIds"`
+}
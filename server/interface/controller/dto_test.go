@@ -0,0 +1,316 @@
+package controller
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/testutil"
+)
+
+// secretValueForTest is set to every secret field of models.
+const secretValueForTest = "secretValueForTest"
+
+// credentialDTOsForTest is DTOs which are not checked for secret fields,
+// because issuing the credentials to the client is their purpose.
+var credentialDTOsForTest = []interface{}{
+	&TokenDTO{},
+	&CreatedAPIKeyDTO{},
+	&PendingLoginDTO{},
+	&TOTPEnrollmentDTO{},
+	&RecoveryCodesDTO{},
+	&ThreadInviteDTO{},
+	&CSRFTokenDTO{},
+}
+
+// responseDTOsForTest returns every DTO in response, translated from models which have secret fields set.
+// DTO added to response must be added here, which TestResponseDTOs_CoverEveryDTO checks.
+func responseDTOsForTest(t *testing.T) []interface{} {
+	t.Helper()
+
+	testutil.SetFakeTime(time.Now())
+
+	user := &model.User{
+		ID:        model.UserValidIDForTest,
+		Name:      model.UserNameForTest,
+		CreatedAt: testutil.TimeNow(),
+		UpdatedAt: testutil.TimeNow(),
+	}
+	setSecretFields(t, user)
+
+	session := &model.Session{
+		UserID:     model.UserValidIDForTest,
+		UserAgent:  model.UserAgentForTest,
+		IPAddress:  model.ClientIPForTest,
+		CreatedAt:  testutil.TimeNow(),
+		LastSeenAt: testutil.TimeNow(),
+	}
+	setSecretFields(t, session)
+
+	keys := []*model.PublicKey{{ID: "testKeyID", Key: []byte(secretValueForTest)}}
+
+	apiKey := model.NewAPIKey(model.APIKeyForTest, model.UserValidIDForTest, model.APIKeyNameForTest, model.AllScopes, nil, testutil.TimeNow())
+	setSecretFields(t, apiKey)
+
+	thread := &model.Thread{
+		ID:        model.ThreadValidIDForTest,
+		Title:     model.ThreadTitleForTest,
+		UserID:    model.UserValidIDForTest,
+		CreatedAt: testutil.TimeNow(),
+		UpdatedAt: testutil.TimeNow(),
+	}
+	setSecretFields(t, thread)
+
+	comment := &model.Comment{
+		ID:        model.CommentValidIDForTest,
+		ThreadID:  model.ThreadValidIDForTest,
+		UserID:    model.UserValidIDForTest,
+		Content:   model.CommentContentForTest,
+		Reactions: []*model.ReactionCount{{CommentID: model.CommentValidIDForTest, Emoji: model.EmojiForTest, Count: 1, ReactedByMe: true}},
+		Replies: &model.ReplySummary{
+			ParentCommentID:   model.CommentValidIDForTest,
+			ReplyCount:        1,
+			LatestReplyID:     model.CommentValidIDForTest + 1,
+			LatestReplyUserID: model.UserValidIDForTest,
+			LatestReplyAt:     testutil.TimeNow(),
+		},
+		Attachments: []*model.Attachment{{
+			ID:          model.AttachmentValidIDForTest,
+			CommentID:   model.CommentValidIDForTest,
+			UserID:      model.UserValidIDForTest,
+			FileName:    model.AttachmentFileNameForTest,
+			ContentType: "image/png",
+			Size:        1,
+			CreatedAt:   testutil.TimeNow(),
+		}},
+		CreatedAt: testutil.TimeNow(),
+		UpdatedAt: testutil.TimeNow(),
+	}
+	setSecretFields(t, comment)
+
+	member := &model.ThreadMember{
+		ThreadID:  model.ThreadValidIDForTest,
+		UserID:    model.UserValidIDForTest,
+		Role:      model.ThreadMemberRoleOwner,
+		CreatedAt: testutil.TimeNow(),
+	}
+	setSecretFields(t, member)
+
+	conversation := &model.DirectConversation{
+		ThreadID:      model.ThreadValidIDForTest,
+		UserID1:       model.UserValidIDForTest,
+		UserID2:       model.UserInValidIDForTest,
+		LastMessageAt: testutil.TimeNow(),
+		CreatedAt:     testutil.TimeNow(),
+	}
+	setSecretFields(t, conversation)
+
+	block := &model.UserBlock{
+		UserID:        model.UserValidIDForTest,
+		BlockedUserID: model.UserInValidIDForTest,
+		CreatedAt:     testutil.TimeNow(),
+	}
+	setSecretFields(t, block)
+
+	return []interface{}{
+		TranslateFromUserToUserDTO(user),
+		TranslateFromSessionToSessionDTO(session, secretValueForTest),
+		TranslateFromPublicKeysToJWKSetDTO(keys),
+		TranslateFromAPIKeyToAPIKeyDTO(apiKey),
+		TranslateFromThreadToThreadDTO(thread),
+		TranslateFromThreadSummaryToThreadSummaryDTO(&model.ThreadSummary{Thread: thread, UnreadCount: 1, FirstUnreadCommentID: model.CommentValidIDForTest}),
+		TranslateFromThreadMemberToThreadMemberDTO(member),
+		TranslateFromCommentToCommentDTO(comment),
+		TranslateFromAttachmentToAttachmentDTO(comment.Attachments[0]),
+		TranslateFromReplyPageToReplyPageDTO(&model.ReplyPage{
+			ParentCommentID: model.CommentValidIDForTest,
+			Replies:         []*model.Comment{comment},
+			HasMore:         true,
+		}),
+		TranslateFromCommentRevisionToCommentRevisionDTO(&model.CommentRevision{
+			ID:        1,
+			CommentID: model.CommentValidIDForTest,
+			EditorID:  model.UserValidIDForTest,
+			Content:   model.CommentContentForTest,
+			CreatedAt: testutil.TimeNow(),
+		}),
+		TranslateFromNotificationPageToNotificationPageDTO(&model.NotificationPage{
+			Notifications: []*model.Notification{model.NewMentionNotification(comment, model.UserInValidIDForTest)},
+			UnreadCount:   1,
+			HasMore:       true,
+		}),
+		TranslateFromSearchResultToSearchResultDTO(&model.SearchResult{
+			Hits:         []*model.SearchHit{{Comment: comment, Highlights: []model.TextRange{{Start: 0, End: 4}}}},
+			HasMore:      true,
+			NextBeforeID: model.CommentValidIDForTest,
+		}),
+		TranslateFromDirectConversationToDirectConversationDTO(conversation, model.UserValidIDForTest),
+		TranslateFromUserBlockToUserBlockDTO(block),
+		TranslateFromThreadPresenceToThreadPresenceDTO(&model.ThreadPresence{
+			ThreadID:      model.ThreadValidIDForTest,
+			Presences:     []*model.Presence{{UserID: model.UserValidIDForTest, Status: model.PresenceStatusOnline, LastActiveAt: testutil.TimeNow()}},
+			TypingUserIDs: []uint32{model.UserValidIDForTest},
+		}),
+		TranslateFromRolesToRolesDTO(model.Roles{model.RoleAdmin}),
+		&ModeratorsDTO{UserIDs: []uint32{model.UserValidIDForTest}},
+	}
+}
+
+// setSecretFields sets secretValueForTest to string fields tagged secret.
+func setSecretFields(t *testing.T, v interface{}) {
+	t.Helper()
+
+	rv := reflect.ValueOf(v).Elem()
+	for i := 0; i < rv.NumField(); i++ {
+		f := rv.Type().Field(i)
+		if f.Tag.Get("secret") != "true" {
+			continue
+		}
+		if f.Type.Kind() != reflect.String {
+			t.Fatalf("secret field %s.%s should be string", rv.Type().Name(), f.Name)
+		}
+		rv.Field(i).SetString(secretValueForTest)
+	}
+}
+
+// serializedSecretFields returns fields tagged secret which are serialized to JSON.
+func serializedSecretFields(typ reflect.Type, path string) []string {
+	for typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice || typ.Kind() == reflect.Map {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct || typ == reflect.TypeOf(time.Time{}) {
+		return nil
+	}
+
+	var found []string
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.PkgPath != "" || f.Tag.Get("json") == "-" {
+			continue
+		}
+
+		name := path + "." + f.Name
+		if f.Tag.Get("secret") == "true" {
+			found = append(found, name)
+		}
+		found = append(found, serializedSecretFields(f.Type, name)...)
+	}
+	return found
+}
+
+// declaredDTONames returns names of every type named *DTO which is declared in the package except tests.
+func declaredDTONames(t *testing.T) []string {
+	t.Helper()
+
+	notTest := func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}
+	pkgs, err := parser.ParseDir(token.NewFileSet(), ".", notTest, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, pkg := range pkgs {
+		for _, f := range pkg.Files {
+			for _, decl := range f.Decls {
+				gd, ok := decl.(*ast.GenDecl)
+				if !ok || gd.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range gd.Specs {
+					if name := spec.(*ast.TypeSpec).Name.Name; strings.HasSuffix(name, "DTO") {
+						names = append(names, name)
+					}
+				}
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// collectDTONames adds names of typ and types in its fields which are declared in the package.
+func collectDTONames(typ reflect.Type, names map[string]bool) {
+	for typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice || typ.Kind() == reflect.Map {
+		typ = typ.Elem()
+	}
+	if typ.PkgPath() != reflect.TypeOf(UserDTO{}).PkgPath() || names[typ.Name()] {
+		return
+	}
+	names[typ.Name()] = true
+
+	if typ.Kind() != reflect.Struct {
+		return
+	}
+	for i := 0; i < typ.NumField(); i++ {
+		collectDTONames(typ.Field(i).Type, names)
+	}
+}
+
+// TestResponseDTOs_CoverEveryDTO fails when a DTO is not added to responseDTOsForTest,
+// so that a new DTO can not leak secret fields without being checked.
+// Request DTOs are only decoded from requests, and are not checked.
+func TestResponseDTOs_CoverEveryDTO(t *testing.T) {
+	covered := make(map[string]bool)
+	for _, dto := range responseDTOsForTest(t) {
+		collectDTONames(reflect.TypeOf(dto), covered)
+	}
+	for _, dto := range credentialDTOsForTest {
+		collectDTONames(reflect.TypeOf(dto), covered)
+	}
+
+	for _, name := range declaredDTONames(t) {
+		if strings.HasSuffix(name, "RequestDTO") || covered[name] {
+			continue
+		}
+		t.Errorf("%s is not checked for secret fields, add it to responseDTOsForTest", name)
+	}
+}
+
+func TestResponseDTOs_DoNotSerializeSecretField(t *testing.T) {
+	for _, dto := range responseDTOsForTest(t) {
+		typ := reflect.TypeOf(dto)
+		t.Run(typ.String(), func(t *testing.T) {
+			if found := serializedSecretFields(typ, typ.String()); len(found) > 0 {
+				t.Errorf("%s serializes secret fields %v", typ, found)
+			}
+		})
+	}
+}
+
+func TestResponseDTOs_DoNotLeakSecretValue(t *testing.T) {
+	for _, dto := range responseDTOsForTest(t) {
+		typ := reflect.TypeOf(dto)
+		t.Run(typ.String(), func(t *testing.T) {
+			b, err := json.Marshal(dto)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(string(b), secretValueForTest) {
+				t.Errorf("%s leaks secret value: %s", typ, b)
+			}
+		})
+	}
+}
+
+func TestUser_DoesNotSerializeSecretField(t *testing.T) {
+	user := &model.User{Name: model.UserNameForTest}
+	setSecretFields(t, user)
+
+	b, err := json.Marshal(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), secretValueForTest) {
+		t.Errorf("model.User leaks secret value: %s", b)
+	}
+}
@@ -0,0 +1,141 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/application"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/infra/router"
+)
+
+// EmailController is the interface of EmailController.
+type EmailController interface {
+	ChangeEmail(w http.ResponseWriter, r *http.Request)
+	VerifyEmail(w http.ResponseWriter, r *http.Request)
+	RequestPasswordReset(w http.ResponseWriter, r *http.Request)
+	ResetPassword(w http.ResponseWriter, r *http.Request)
+}
+
+type emailController struct {
+	rm   router.RequestManager
+	eApp application.EmailService
+}
+
+// NewEmailController generates and returns EmailController.
+func NewEmailController(rm router.RequestManager, eApp application.EmailService) EmailController {
+	return &emailController{
+		rm:   rm,
+		eApp: eApp,
+	}
+}
+
+// ChangeEmail sets the email of the user who sent the request and sends the mail to verify it.
+func (c *emailController) ChangeEmail(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopeAdmin)
+	if !ok {
+		return
+	}
+
+	dto, err := parseEmailRequest(r)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	user, err := c.eApp.ChangeEmail(r.Context(), me.ID, dto.Email)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if err := Response(w, http.StatusOK, TranslateFromUserToUserDTO(user)); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
+
+// VerifyEmail verifies the email by the token sent to it.
+// This does not require login, so that the link in the mail can be opened on another device.
+func (c *emailController) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	b, err := GetValueFromPayLoad(r)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	dto := &EmailTokenRequestDTO{}
+	if err := unmarshalRequest(b, dto, "request body should be json of token"); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if _, err := c.eApp.VerifyEmail(r.Context(), dto.Token); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if err := Response(w, http.StatusOK); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
+
+// RequestPasswordReset sends the mail to reset password.
+// This responds the same whether the email is registered or not.
+func (c *emailController) RequestPasswordReset(w http.ResponseWriter, r *http.Request) {
+	dto, err := parseEmailRequest(r)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if err := c.eApp.RequestPasswordReset(r.Context(), dto.Email); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if err := Response(w, http.StatusOK); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
+
+// ResetPassword changes password by the token sent by email.
+// The user is logged out of all devices, and logs in again with the new password.
+func (c *emailController) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	b, err := GetValueFromPayLoad(r)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	dto := &PasswordResetRequestDTO{}
+	if err := unmarshalRequest(b, dto, "request body should be json of token and newPassword"); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if err := c.eApp.ResetPassword(r.Context(), dto.Token, dto.NewPassword); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if err := Response(w, http.StatusOK); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
+
+// parseEmailRequest parses the request which has the email.
+func parseEmailRequest(r *http.Request) (*EmailRequestDTO, error) {
+	b, err := GetValueFromPayLoad(r)
+	if err != nil {
+		return nil, err
+	}
+
+	dto := &EmailRequestDTO{}
+	if err := unmarshalRequest(b, dto, "request body should be json of email"); err != nil {
+		return nil, err
+	}
+	return dto, nil
+}
@@ -18,4 +18,8 @@ const (
 	RequiredFailure              ErrCode = "RequiredError"
 	AlreadyExistsFailure         ErrCode = "AlreadyExistsFailure"
 	AuthenticationFailure        ErrCode = "AuthenticationFailure"
+	TooManyRequestsFailure       ErrCode = "TooManyRequestsFailure"
+	CSRFFailure                  ErrCode = "CSRFFailure"
+	InsufficientScopeFailure     ErrCode = "InsufficientScopeFailure"
+	ForbiddenFailure             ErrCode = "ForbiddenFailure"
 )
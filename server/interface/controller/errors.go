@@ -2,6 +2,7 @@ package controller
 
 import (
 	"fmt"
+	"math"
 	"net/http"
 
 	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
@@ -16,6 +17,8 @@ type handledError struct {
 	Message        string  `json:"message"`
 	ErrorUserTitle string  `json:"error_user_title"`
 	ErrorUserMsg   string  `json:"error_user_msg"`
+	// RetryAfter is seconds which is set to Retry-After header.
+	RetryAfter int `json:"-"`
 }
 
 const systemError = "system error has occurred"
@@ -73,6 +76,47 @@ func handleError(err error) *handledError {
 			ErrorUserTitle: "認証エラー",
 			ErrorUserMsg:   "認証に失敗しました、IDもしくはパスワードが不正か既に利用されています",
 		}
+	case *model.TooManyRequestsError:
+		realErr := errors.Cause(err).(*model.TooManyRequestsError)
+		return &handledError{
+			BaseError:      realErr.BaseErr,
+			Status:         http.StatusTooManyRequests,
+			Code:           TooManyRequestsFailure,
+			Message:        errors.Cause(err).Error(),
+			ErrorUserTitle: "試行回数の超過",
+			ErrorUserMsg:   "試行回数が多すぎます、しばらく待ってから再度お試しください",
+			RetryAfter:     int(math.Ceil(realErr.RetryAfter.Seconds())),
+		}
+	case *model.InsufficientScopeError:
+		realErr := errors.Cause(err).(*model.InsufficientScopeError)
+		return &handledError{
+			BaseError:      realErr.BaseErr,
+			Status:         http.StatusForbidden,
+			Code:           InsufficientScopeFailure,
+			Message:        errors.Cause(err).Error(),
+			ErrorUserTitle: "権限の不足",
+			ErrorUserMsg:   fmt.Sprintf("この操作には%sのスコープが必要です", realErr.Scope),
+		}
+	case *model.ForbiddenError:
+		realErr := errors.Cause(err).(*model.ForbiddenError)
+		return &handledError{
+			BaseError:      realErr.BaseErr,
+			Status:         http.StatusForbidden,
+			Code:           ForbiddenFailure,
+			Message:        errors.Cause(err).Error(),
+			ErrorUserTitle: "権限の不足",
+			ErrorUserMsg:   "この操作を行う権限がありません",
+		}
+	case *model.CSRFError:
+		realErr := errors.Cause(err).(*model.CSRFError)
+		return &handledError{
+			BaseError:      realErr.BaseErr,
+			Status:         http.StatusForbidden,
+			Code:           CSRFFailure,
+			Message:        errors.Cause(err).Error(),
+			ErrorUserTitle: "不正なリクエスト",
+			ErrorUserMsg:   "リクエストを検証できませんでした、ページを再読み込みしてください",
+		}
 	case *model.RepositoryError:
 		realErr := errors.Cause(err).(*model.RepositoryError)
 		return &handledError{
@@ -0,0 +1,207 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/application"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/pkg/errors"
+)
+
+// contextKey is the key of value set to context of request.
+type contextKey string
+
+const (
+	userContextKey   contextKey = "user"
+	scopesContextKey contextKey = "scopes"
+)
+
+// UserFromContext returns the user who sent the request.
+func UserFromContext(ctx context.Context) (*model.User, bool) {
+	user, ok := ctx.Value(userContextKey).(*model.User)
+	return user, ok && user != nil
+}
+
+// ScopesFromContext returns the scopes allowed to the credential of the request.
+// Session and access token are allowed all scopes, and API key is allowed only its scopes.
+func ScopesFromContext(ctx context.Context) model.Scopes {
+	scopes, _ := ctx.Value(scopesContextKey).(model.Scopes)
+	return scopes
+}
+
+// withUser returns the request which has the user and the scopes in its context.
+func withUser(r *http.Request, user *model.User, scopes model.Scopes) *http.Request {
+	ctx := context.WithValue(r.Context(), userContextKey, user)
+	return r.WithContext(context.WithValue(ctx, scopesContextKey, scopes))
+}
+
+// AuthenticationMiddleware is the interface of AuthenticationMiddleware.
+type AuthenticationMiddleware interface {
+	Handler(next http.Handler) http.Handler
+}
+
+type authenticationMiddleware struct {
+	aApp  application.AuthenticationService
+	tApp  application.TokenService
+	akApp application.APIKeyService
+	cp    CookiePolicy
+}
+
+// NewAuthenticationMiddleware generates and returns AuthenticationMiddleware.
+func NewAuthenticationMiddleware(aApp application.AuthenticationService, tApp application.TokenService, akApp application.APIKeyService, cp CookiePolicy) AuthenticationMiddleware {
+	return &authenticationMiddleware{
+		aApp:  aApp,
+		tApp:  tApp,
+		akApp: akApp,
+		cp:    cp,
+	}
+}
+
+// Handler sets the user of the API key, the access token or the session to context of request.
+// Request with Authorization header is authenticated only by the API key or the access token, and the cookie is ignored.
+// Request without session cookie or with expired session is passed as anonymous.
+// Tampered cookie is rejected and cleared before the session is looked up.
+func (m *authenticationMiddleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token, ok := GetBearerToken(r); ok {
+			m.serveWithAccessToken(w, r, next, token)
+			return
+		}
+
+		if _, err := r.Cookie(m.cp.Name()); err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		token, err := m.cp.SessionToken(r)
+		if err != nil {
+			http.SetCookie(w, m.cp.ClearSessionCookie())
+			ResponseAndLogError(w, err)
+			return
+		}
+
+		user, err := m.aApp.GetSessionUser(r.Context(), token, GetClient(r))
+		if err != nil {
+			if _, ok := errors.Cause(err).(*model.AuthenticationErr); ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+			ResponseAndLogError(w, err)
+			return
+		}
+
+		next.ServeHTTP(w, withUser(r, user, model.AllScopes))
+	})
+}
+
+// serveWithAccessToken serves the request as the user of the API key or the access token.
+// API key is told from access token by its prefix.
+// Invalid or expired token is rejected, so that the client can tell it should refresh the token.
+func (m *authenticationMiddleware) serveWithAccessToken(w http.ResponseWriter, r *http.Request, next http.Handler, token string) {
+	if token == "" {
+		ResponseAndLogError(w, errors.WithStack(&model.AuthenticationErr{}))
+		return
+	}
+
+	if model.IsAPIKey(token) {
+		user, scopes, err := m.akApp.GetAPIKeyUser(r.Context(), token)
+		if err != nil {
+			ResponseAndLogError(w, err)
+			return
+		}
+		next.ServeHTTP(w, withUser(r, user, scopes))
+		return
+	}
+
+	user, err := m.tApp.GetAccessTokenUser(r.Context(), token)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	next.ServeHTTP(w, withUser(r, user, model.AllScopes))
+}
+
+// requireUser returns the user who sent the request.
+// This responds AuthenticationErr and returns false if the request is anonymous.
+func requireUser(w http.ResponseWriter, r *http.Request) (*model.User, bool) {
+	user, ok := UserFromContext(r.Context())
+	if !ok {
+		ResponseAndLogError(w, errors.WithStack(&model.AuthenticationErr{}))
+		return nil, false
+	}
+	return user, true
+}
+
+// requireScope returns the user who sent the request with the credential allowed the scope.
+// This responds AuthenticationErr if the request is anonymous,
+// and InsufficientScopeError if the credential is not allowed the scope.
+func requireScope(w http.ResponseWriter, r *http.Request, scope model.Scope) (*model.User, bool) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return nil, false
+	}
+
+	if !ScopesFromContext(r.Context()).Has(scope) {
+		ResponseAndLogError(w, errors.WithStack(&model.InsufficientScopeError{Scope: scope}))
+		return nil, false
+	}
+	return user, true
+}
+
+// RoleMiddleware is the interface of RoleMiddleware.
+type RoleMiddleware interface {
+	Require(permission model.Permission) func(next http.Handler) http.Handler
+}
+
+type roleMiddleware struct {
+	rApp application.RoleService
+}
+
+// NewRoleMiddleware generates and returns RoleMiddleware.
+func NewRoleMiddleware(rApp application.RoleService) RoleMiddleware {
+	return &roleMiddleware{
+		rApp: rApp,
+	}
+}
+
+// Require returns the middleware which passes only users whose roles allow the permission.
+// This is for routes which only privileged users use at all, e.g. administration,
+// and application services still check the policy for each operation.
+// This must be used after AuthenticationMiddleware.
+func (m *roleMiddleware) Require(permission model.Permission) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := requireRole(w, r, m.rApp, permission); !ok {
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// requireRole returns the user who sent the request with the roles which allow the permission.
+// This responds AuthenticationErr if the request is anonymous,
+// and ForbiddenError if the roles of the user do not allow the permission.
+func requireRole(w http.ResponseWriter, r *http.Request, rApp application.RoleService, permission model.Permission) (*model.User, bool) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return nil, false
+	}
+
+	roles, err := rApp.GetRoles(r.Context(), user.ID)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return nil, false
+	}
+
+	if !roles.Can(permission) {
+		ResponseAndLogError(w, errors.WithStack(&model.ForbiddenError{
+			InvalidReasonForDeveloper: fmt.Sprintf("%s is required", permission),
+		}))
+		return nil, false
+	}
+	return user, true
+}
@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/application"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/infra/router"
+)
+
+// NotificationController is the interface of NotificationController.
+type NotificationController interface {
+	ListNotifications(w http.ResponseWriter, r *http.Request)
+	MarkRead(w http.ResponseWriter, r *http.Request)
+	MarkAllRead(w http.ResponseWriter, r *http.Request)
+}
+
+type notificationController struct {
+	rm   router.RequestManager
+	nApp application.NotificationService
+}
+
+// NewNotificationController generates and returns NotificationController.
+func NewNotificationController(rm router.RequestManager, nApp application.NotificationService) NotificationController {
+	return &notificationController{
+		rm:   rm,
+		nApp: nApp,
+	}
+}
+
+// ListNotifications returns a page of notifications of the user who sent the request in order of newest first.
+// The page starts before the notification specified by the query before, and has at most the query limit notifications.
+func (c *notificationController) ListNotifications(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopeReadThreads)
+	if !ok {
+		return
+	}
+
+	before, err := GetUint32ValueOfQuery(r, model.BeforePropertyForDeveloper, 0)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	limit, err := GetUint32ValueOfQuery(r, model.LimitPropertyForDeveloper, 0)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	page, err := c.nApp.ListNotifications(r.Context(), me.ID, before, int(limit))
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if err := Response(w, http.StatusOK, TranslateFromNotificationPageToNotificationPageDTO(page)); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
+
+// MarkRead marks the notification specified by id of the user who sent the request as read.
+func (c *notificationController) MarkRead(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopeReadThreads)
+	if !ok {
+		return
+	}
+
+	id, err := c.rm.GetUint32ValueOfURLParam(r, model.IDPropertyForDeveloper)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	notification, err := c.nApp.MarkRead(r.Context(), me.ID, id)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if err := Response(w, http.StatusOK, TranslateFromNotificationToNotificationDTO(notification)); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
+
+// MarkAllRead marks all notifications of the user who sent the request as read.
+func (c *notificationController) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopeReadThreads)
+	if !ok {
+		return
+	}
+
+	if err := c.nApp.MarkAllRead(r.Context(), me.ID); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/application"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/infra/router"
+)
+
+// OIDCController is the interface of OIDCController.
+type OIDCController interface {
+	Login(w http.ResponseWriter, r *http.Request)
+	Callback(w http.ResponseWriter, r *http.Request)
+}
+
+type oidcController struct {
+	rm   router.RequestManager
+	oApp application.OIDCService
+	cp   CookiePolicy
+	// lcp is the policy of the cookie which binds the login in progress to the browser.
+	// This must be SameSite=Lax, so that the cookie is sent on the redirect from the provider.
+	lcp CookiePolicy
+	// redirectURL is the page to which the browser is redirected after login.
+	redirectURL string
+}
+
+// NewOIDCController generates and returns OIDCController.
+func NewOIDCController(rm router.RequestManager, oApp application.OIDCService, cp, lcp CookiePolicy, redirectURL string) OIDCController {
+	return &oidcController{
+		rm:          rm,
+		oApp:        oApp,
+		cp:          cp,
+		lcp:         lcp,
+		redirectURL: redirectURL,
+	}
+}
+
+// Login starts login and redirects the browser to the provider.
+func (c *oidcController) Login(w http.ResponseWriter, r *http.Request) {
+	login, authURL, err := c.oApp.StartLogin(r.Context())
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	cookie, err := c.lcp.SessionCookie(login.Token)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	http.SetCookie(w, cookie)
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// Callback finishes login by the redirect from the provider, and sets the session cookie.
+// The cookie of the login is cleared whether login succeeds or not, because it can be used only once.
+// If TOTP of the user is enabled, the pending token is passed in the fragment of the redirect URL instead of the cookie,
+// and the client sends it with the second factor to /api/login/two_factor.
+// The fragment is not sent to servers nor in Referer.
+func (c *oidcController) Callback(w http.ResponseWriter, r *http.Request) {
+	token, err := c.lcp.SessionToken(r)
+	http.SetCookie(w, c.lcp.ClearSessionCookie())
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	q := r.URL.Query()
+	if e := q.Get("error"); e != "" {
+		ResponseAndLogError(w, errors.WithStack(&model.AuthenticationErr{
+			BaseErr: errors.Errorf("provider responded error: %s", e),
+		}))
+		return
+	}
+
+	_, session, pending, err := c.oApp.FinishLogin(r.Context(), token, q.Get("state"), q.Get("code"), GetClient(r))
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if pending != nil {
+		w.Header().Set(CacheControl, "no-store")
+		http.Redirect(w, r, c.redirectURL+"#"+url.Values{"pendingToken": {pending.Token}}.Encode(), http.StatusFound)
+		return
+	}
+
+	cookie, err := c.cp.SessionCookie(session.Token)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	http.SetCookie(w, cookie)
+	http.Redirect(w, r, c.redirectURL, http.StatusFound)
+}
@@ -0,0 +1,144 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/application"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/infra/router"
+)
+
+// PresenceController is the interface of PresenceController.
+type PresenceController interface {
+	Heartbeat(w http.ResponseWriter, r *http.Request)
+	GoOffline(w http.ResponseWriter, r *http.Request)
+	GetThreadPresence(w http.ResponseWriter, r *http.Request)
+	StartTyping(w http.ResponseWriter, r *http.Request)
+	StopTyping(w http.ResponseWriter, r *http.Request)
+}
+
+type presenceController struct {
+	rm    router.RequestManager
+	prApp application.PresenceService
+}
+
+// NewPresenceController generates and returns PresenceController.
+func NewPresenceController(rm router.RequestManager, prApp application.PresenceService) PresenceController {
+	return &presenceController{
+		rm:    rm,
+		prApp: prApp,
+	}
+}
+
+// Heartbeat records that the client of the user who sent the request is connected, and returns the presence.
+func (c *presenceController) Heartbeat(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopeReadThreads)
+	if !ok {
+		return
+	}
+
+	b, err := GetValueFromPayLoad(r)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	dto := &HeartbeatRequestDTO{}
+	if err := unmarshalRequest(b, dto, "request body should be json of active and away"); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	presence, err := c.prApp.Heartbeat(r.Context(), me.ID, dto.Active, dto.Away)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if err := Response(w, http.StatusOK, TranslateFromPresenceToPresenceDTO(presence)); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
+
+// GoOffline makes the user who sent the request offline.
+func (c *presenceController) GoOffline(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopeReadThreads)
+	if !ok {
+		return
+	}
+
+	if err := c.prApp.GoOffline(r.Context(), me.ID); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetThreadPresence returns presence of members of the thread specified by id and users typing in it.
+func (c *presenceController) GetThreadPresence(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopeReadThreads)
+	if !ok {
+		return
+	}
+
+	threadID, err := c.rm.GetUint32ValueOfURLParam(r, model.IDPropertyForDeveloper)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	tp, err := c.prApp.GetThreadPresence(r.Context(), me.ID, threadID)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if err := Response(w, http.StatusOK, TranslateFromThreadPresenceToThreadPresenceDTO(tp)); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
+
+// StartTyping records that the user who sent the request is typing in the thread specified by id.
+func (c *presenceController) StartTyping(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopePostComments)
+	if !ok {
+		return
+	}
+
+	threadID, err := c.rm.GetUint32ValueOfURLParam(r, model.IDPropertyForDeveloper)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if err := c.prApp.StartTyping(r.Context(), me.ID, threadID); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// StopTyping ends typing of the user who sent the request in the thread specified by id.
+func (c *presenceController) StopTyping(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopePostComments)
+	if !ok {
+		return
+	}
+
+	threadID, err := c.rm.GetUint32ValueOfURLParam(r, model.IDPropertyForDeveloper)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if err := c.prApp.StopTyping(r.Context(), me.ID, threadID); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"expvar"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/infra/ratelimit"
+	"github.com/pkg/errors"
+)
+
+// rateLimitRejections is the number of rejected requests per rule.
+// This is exposed by expvar.
+var rateLimitRejections = expvar.NewMap("rate_limit_rejections")
+
+// RateLimitRule is the rule of rate limit applied to a route.
+// PathPattern is the path template of gorilla mux, e.g. /api/threads/{id}/comments.
+type RateLimitRule struct {
+	Method      string
+	PathPattern string
+	Rate        ratelimit.Rate
+}
+
+// key returns the key of the rule.
+func (r RateLimitRule) key() string {
+	return fmt.Sprintf("%s %s", r.Method, r.PathPattern)
+}
+
+// RateLimitMiddleware is the interface of RateLimitMiddleware.
+type RateLimitMiddleware interface {
+	Handler(next http.Handler) http.Handler
+}
+
+type rateLimitMiddleware struct {
+	limiters map[string]*ratelimit.Limiter
+}
+
+// NewRateLimitMiddleware generates and returns RateLimitMiddleware.
+// capacity is the max number of clients kept per rule.
+func NewRateLimitMiddleware(rules []RateLimitRule, capacity int) RateLimitMiddleware {
+	limiters := make(map[string]*ratelimit.Limiter, len(rules))
+	for _, rule := range rules {
+		limiters[rule.key()] = ratelimit.NewLimiter(rule.Rate, capacity)
+	}
+
+	return &rateLimitMiddleware{
+		limiters: limiters,
+	}
+}
+
+// Handler limits requests to the routes which have rules.
+// This must be used after AuthenticationMiddleware so that requests are limited per user.
+func (m *rateLimitMiddleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := mux.CurrentRoute(r)
+		if route == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		pattern, err := route.GetPathTemplate()
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ruleKey := RateLimitRule{Method: r.Method, PathPattern: pattern}.key()
+		limiter, ok := m.limiters[ruleKey]
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		res := limiter.Allow(rateLimitClientKey(r))
+		setRateLimitHeaders(w, res)
+
+		if !res.Allowed {
+			rateLimitRejections.Add(ruleKey, 1)
+			ResponseError(w, errors.WithStack(&model.TooManyRequestsError{
+				RetryAfter: res.RetryAfter,
+			}))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ipv6PrefixBits is the length of the prefix which identifies the IPv6 client.
+// A client is usually assigned a whole /64, so that its addresses in it are counted together.
+const ipv6PrefixBits = 64
+
+// rateLimitClientKey returns the key of the client.
+// Logged in user is identified by user id, otherwise by IP.
+// IPv6 clients are identified by the /64 prefix, so that they can not reset limits
+// by rotating addresses in it.
+func rateLimitClientKey(r *http.Request) string {
+	if user, ok := UserFromContext(r.Context()); ok {
+		return fmt.Sprintf("user:%d", user.ID)
+	}
+
+	ip := net.ParseIP(GetClientIP(r))
+	if ip == nil || ip.To4() != nil {
+		return fmt.Sprintf("ip:%s", GetClientIP(r))
+	}
+	prefix := ip.Mask(net.CIDRMask(ipv6PrefixBits, 8*net.IPv6len))
+	return fmt.Sprintf("ip:%s/%d", prefix, ipv6PrefixBits)
+}
+
+// setRateLimitHeaders sets RateLimit headers.
+func setRateLimitHeaders(w http.ResponseWriter, res ratelimit.Result) {
+	w.Header().Set(RateLimitLimit, strconv.Itoa(res.Limit))
+	w.Header().Set(RateLimitRemaining, strconv.Itoa(res.Remaining))
+	w.Header().Set(RateLimitReset, strconv.Itoa(int(math.Ceil(res.Reset.Seconds()))))
+}
@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+)
+
+func Test_rateLimitClientKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		remoteAddr string
+		user       *model.User
+		want       string
+	}{
+		{
+			name:       "When the user is logged in, returns the key of the user",
+			remoteAddr: "192.0.2.1:1234",
+			user:       &model.User{ID: model.UserValidIDForTest},
+			want:       "user:1",
+		},
+		{
+			name:       "When the client is IPv4, returns the key of the address",
+			remoteAddr: "192.0.2.1:1234",
+			want:       "ip:192.0.2.1",
+		},
+		{
+			name:       "When the client is IPv6, returns the key of the /64 prefix",
+			remoteAddr: "[2001:db8:1:2:3:4:5:6]:1234",
+			want:       "ip:2001:db8:1:2::/64",
+		},
+		{
+			name:       "When the client rotates IPv6 addresses in the /64 prefix, returns the same key",
+			remoteAddr: "[2001:db8:1:2:ffff:ffff:ffff:ffff]:1234",
+			want:       "ip:2001:db8:1:2::/64",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/api/login", nil)
+			r.RemoteAddr = tt.remoteAddr
+			if tt.user != nil {
+				r = withUser(r, tt.user, model.AllScopes)
+			}
+
+			if got := rateLimitClientKey(r); got != tt.want {
+				t.Errorf("rateLimitClientKey() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,15 +1,39 @@
 package controller
 
 import (
+	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
 	"github.com/pkg/errors"
 )
 
-const ContentLength = "Content-Length"
+// Header name.
+const (
+	ContentLength = "Content-Length"
+	ContentType   = "Content-Type"
+	RetryAfter    = "Retry-After"
+	CacheControl  = "Cache-Control"
+
+	ContentDisposition    = "Content-Disposition"
+	ContentTypeOptions    = "X-Content-Type-Options"
+	ContentSecurityPolicy = "Content-Security-Policy"
+	ETag                  = "ETag"
+
+	Authorization = "Authorization"
+
+	Origin     = "Origin"
+	Referer    = "Referer"
+	CSRFHeader = "X-XSRF-TOKEN"
+
+	RateLimitLimit     = "RateLimit-Limit"
+	RateLimitRemaining = "RateLimit-Remaining"
+	RateLimitReset     = "RateLimit-Reset"
+)
 
 // GetValueFromPayLoad load http payload.
 func GetValueFromPayLoad(r *http.Request) ([]byte, error) {
@@ -37,3 +61,59 @@ func GetValueFromPayLoad(r *http.Request) ([]byte, error) {
 
 	return body, nil
 }
+
+// GetClientIP returns IP address of the client.
+// X-Forwarded-For is not trusted because the server is exposed directly.
+func GetClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// bearerScheme is the scheme of Authorization header which has access token.
+const bearerScheme = "Bearer "
+
+// GetBearerToken returns the token in Authorization header.
+// The second value is false if the request does not have Authorization header,
+// and the token is empty if the header is not Bearer.
+func GetBearerToken(r *http.Request) (string, bool) {
+	v := r.Header.Get(Authorization)
+	if v == "" {
+		return "", false
+	}
+	if len(v) <= len(bearerScheme) || !strings.EqualFold(v[:len(bearerScheme)], bearerScheme) {
+		return "", true
+	}
+	return v[len(bearerScheme):], true
+}
+
+// GetClient returns the client which sends the request.
+func GetClient(r *http.Request) *model.Client {
+	return model.NewClient(GetClientIP(r), r.UserAgent())
+}
+
+// GetUint32ValueOfQuery returns uint32 value of the query parameter specified by key.
+// This returns defaultValue if the parameter is empty, and InvalidParamError if it is not a number.
+func GetUint32ValueOfQuery(r *http.Request, key model.PropertyNameForDeveloper, defaultValue uint32) (uint32, error) {
+	v := r.URL.Query().Get(key.String())
+	if v == "" {
+		return defaultValue, nil
+	}
+
+	i, err := strconv.ParseUint(v, 10, 32)
+	if err != nil {
+		propertyNameForUser := model.PropertyNameKV[key]
+		err := &model.InvalidParamError{
+			PropertyNameForDeveloper:  key,
+			PropertyNameForUser:       propertyNameForUser,
+			PropertyValue:             v,
+			InvalidReasonForDeveloper: fmt.Sprintf("%s should be unsigned integer, but requested value is %s", key, v),
+			InvalidReasonForUser:      fmt.Sprintf("%s は、数字で入力してください", propertyNameForUser),
+		}
+		return 0, errors.WithStack(err)
+	}
+
+	return uint32(i), nil
+}
@@ -3,6 +3,7 @@ package controller
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
 
 	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
 	"github.com/pkg/errors"
@@ -11,7 +12,7 @@ import (
 
 // Response returns response to client.
 func Response(w http.ResponseWriter, statusCode int, obj ...interface{}) error {
-	w.Header().Add(ContentLength, "application/json; charset=UTF-8")
+	w.Header().Add(ContentType, "application/json; charset=UTF-8")
 	w.WriteHeader(statusCode)
 
 	var body interface{} = nil
@@ -44,6 +45,7 @@ func ResponseWithCookie(w http.ResponseWriter, statusCode int, cookie *http.Cook
 // ResponseError returns response error to client.
 func ResponseError(w http.ResponseWriter, err error) {
 	he := handleError(err)
+	setRetryAfter(w, he)
 	if err := Response(w, he.Status, he); err != nil {
 		log.Errorf("failed to response:%s", err.Error())
 	}
@@ -58,7 +60,15 @@ func ResponseAndLogError(w http.ResponseWriter, err error) {
 		log.Errorf("error has occurred:%s", err.Error())
 	}
 
+	setRetryAfter(w, he)
 	if err := Response(w, he.Status, he); err != nil {
 		log.Errorf("failed to response:%s", err.Error())
 	}
 }
+
+// setRetryAfter sets Retry-After header if handledError has it.
+func setRetryAfter(w http.ResponseWriter, he *handledError) {
+	if he.RetryAfter > 0 {
+		w.Header().Set(RetryAfter, strconv.Itoa(he.RetryAfter))
+	}
+}
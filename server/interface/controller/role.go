@@ -0,0 +1,216 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/application"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/infra/router"
+)
+
+// RoleController is the interface of RoleController.
+type RoleController interface {
+	GetMyRoles(w http.ResponseWriter, r *http.Request)
+	GetRoles(w http.ResponseWriter, r *http.Request)
+	GrantRole(w http.ResponseWriter, r *http.Request)
+	RevokeRole(w http.ResponseWriter, r *http.Request)
+	ListThreadModerators(w http.ResponseWriter, r *http.Request)
+	AddThreadModerator(w http.ResponseWriter, r *http.Request)
+	RemoveThreadModerator(w http.ResponseWriter, r *http.Request)
+}
+
+type roleController struct {
+	rm   router.RequestManager
+	rApp application.RoleService
+}
+
+// NewRoleController generates and returns RoleController.
+func NewRoleController(rm router.RequestManager, rApp application.RoleService) RoleController {
+	return &roleController{
+		rm:   rm,
+		rApp: rApp,
+	}
+}
+
+// GetMyRoles returns roles of the user who sent the request.
+func (c *roleController) GetMyRoles(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	c.responseRoles(w, r, me.ID)
+}
+
+// GetRoles returns roles of the user specified by userID.
+func (c *roleController) GetRoles(w http.ResponseWriter, r *http.Request) {
+	if _, ok := requireScope(w, r, model.ScopeAdmin); !ok {
+		return
+	}
+
+	userID, err := c.rm.GetUint32ValueOfURLParam(r, model.UserIDPropertyForDeveloper)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	c.responseRoles(w, r, userID)
+}
+
+// GrantRole grants the role to the user specified by userID, and returns roles of the user.
+func (c *roleController) GrantRole(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopeAdmin)
+	if !ok {
+		return
+	}
+
+	userID, role, err := c.parseUserRole(r)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if err := c.rApp.GrantRole(r.Context(), me, userID, role); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	c.responseRoles(w, r, userID)
+}
+
+// RevokeRole revokes the role from the user specified by userID, and returns roles of the user.
+func (c *roleController) RevokeRole(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopeAdmin)
+	if !ok {
+		return
+	}
+
+	userID, role, err := c.parseUserRole(r)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if err := c.rApp.RevokeRole(r.Context(), me, userID, role); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	c.responseRoles(w, r, userID)
+}
+
+// parseUserRole parses userID and role in URL.
+func (c *roleController) parseUserRole(r *http.Request) (uint32, model.Role, error) {
+	userID, err := c.rm.GetUint32ValueOfURLParam(r, model.UserIDPropertyForDeveloper)
+	if err != nil {
+		return model.InvalidID, "", err
+	}
+
+	role, err := c.rm.GetValueOfURLParamWithoutAcceptanceEmpty(r, model.RolePropertyForDeveloper)
+	if err != nil {
+		return model.InvalidID, "", err
+	}
+
+	return userID, model.Role(role), nil
+}
+
+// responseRoles responds roles of the user.
+func (c *roleController) responseRoles(w http.ResponseWriter, r *http.Request, userID uint32) {
+	roles, err := c.rApp.GetRoles(r.Context(), userID)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if err := Response(w, http.StatusOK, TranslateFromRolesToRolesDTO(roles)); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
+
+// ListThreadModerators returns ids of moderators of the thread specified by id.
+func (c *roleController) ListThreadModerators(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopeReadThreads)
+	if !ok {
+		return
+	}
+
+	threadID, err := c.rm.GetUint32ValueOfURLParam(r, model.IDPropertyForDeveloper)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	c.responseModerators(w, r, me.ID, threadID)
+}
+
+// AddThreadModerator appoints the user specified by userID as a moderator of the thread specified by id.
+func (c *roleController) AddThreadModerator(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopeAdmin)
+	if !ok {
+		return
+	}
+
+	threadID, userID, err := c.parseThreadModerator(r)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if err := c.rApp.AddThreadModerator(r.Context(), me, threadID, userID); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	c.responseModerators(w, r, me.ID, threadID)
+}
+
+// RemoveThreadModerator dismisses the user specified by userID from moderators of the thread specified by id.
+func (c *roleController) RemoveThreadModerator(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopeAdmin)
+	if !ok {
+		return
+	}
+
+	threadID, userID, err := c.parseThreadModerator(r)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if err := c.rApp.RemoveThreadModerator(r.Context(), me, threadID, userID); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	c.responseModerators(w, r, me.ID, threadID)
+}
+
+// parseThreadModerator parses id of thread and userID in URL.
+func (c *roleController) parseThreadModerator(r *http.Request) (uint32, uint32, error) {
+	threadID, err := c.rm.GetUint32ValueOfURLParam(r, model.IDPropertyForDeveloper)
+	if err != nil {
+		return model.InvalidID, model.InvalidID, err
+	}
+
+	userID, err := c.rm.GetUint32ValueOfURLParam(r, model.UserIDPropertyForDeveloper)
+	if err != nil {
+		return model.InvalidID, model.InvalidID, err
+	}
+
+	return threadID, userID, nil
+}
+
+// responseModerators responds ids of moderators of the thread which the user reads.
+func (c *roleController) responseModerators(w http.ResponseWriter, r *http.Request, userID, threadID uint32) {
+	ids, err := c.rApp.ListThreadModerators(r.Context(), userID, threadID)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if err := Response(w, http.StatusOK, &ModeratorsDTO{UserIDs: ids}); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
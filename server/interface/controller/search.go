@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/application"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/infra/router"
+)
+
+// SearchController is the interface of SearchController.
+type SearchController interface {
+	Search(w http.ResponseWriter, r *http.Request)
+}
+
+type searchController struct {
+	rm    router.RequestManager
+	seApp application.SearchService
+}
+
+// NewSearchController generates and returns SearchController.
+func NewSearchController(rm router.RequestManager, seApp application.SearchService) SearchController {
+	return &searchController{
+		rm:    rm,
+		seApp: seApp,
+	}
+}
+
+// Search returns a page of comments which match the query q and the user who sent the request can read.
+// The page starts before the comment specified by the query before, and has at most the query limit comments.
+func (c *searchController) Search(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopeReadThreads)
+	if !ok {
+		return
+	}
+
+	before, err := GetUint32ValueOfQuery(r, model.BeforePropertyForDeveloper, 0)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	limit, err := GetUint32ValueOfQuery(r, model.LimitPropertyForDeveloper, 0)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	q := r.URL.Query().Get(model.QueryPropertyForDeveloper.String())
+	result, err := c.seApp.Search(r.Context(), me.ID, q, before, int(limit))
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if err := Response(w, http.StatusOK, TranslateFromSearchResultToSearchResultDTO(result)); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
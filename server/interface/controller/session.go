@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/application"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/infra/router"
+)
+
+// SessionController is the interface of SessionController.
+type SessionController interface {
+	ListSessions(w http.ResponseWriter, r *http.Request)
+	RevokeSession(w http.ResponseWriter, r *http.Request)
+	RevokeAllSessions(w http.ResponseWriter, r *http.Request)
+}
+
+type sessionController struct {
+	rm   router.RequestManager
+	sApp application.SessionService
+	cp   CookiePolicy
+}
+
+// NewSessionController generates and returns SessionController.
+func NewSessionController(rm router.RequestManager, sApp application.SessionService, cp CookiePolicy) SessionController {
+	return &sessionController{
+		rm:   rm,
+		sApp: sApp,
+		cp:   cp,
+	}
+}
+
+// ListSessions returns active sessions of the user who sent the request.
+func (c *sessionController) ListSessions(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopeAdmin)
+	if !ok {
+		return
+	}
+
+	sessions, err := c.sApp.ListSessions(r.Context(), me.ID)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	// the current session is marked only if the cookie is valid.
+	var currentID string
+	if token, err := c.cp.SessionToken(r); err == nil {
+		currentID = model.SessionIDFromToken(token)
+	}
+	dtos := make([]*SessionDTO, 0, len(sessions))
+	for _, session := range sessions {
+		dtos = append(dtos, TranslateFromSessionToSessionDTO(session, currentID))
+	}
+
+	if err := Response(w, http.StatusOK, dtos); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
+
+// RevokeSession deletes the session specified by id.
+// The cookie is cleared if the session is the current one.
+func (c *sessionController) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopeAdmin)
+	if !ok {
+		return
+	}
+
+	session, err := c.sApp.RevokeSession(r.Context(), me.ID, mux.Vars(r)["id"])
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if token, err := c.cp.SessionToken(r); err == nil && model.SessionIDFromToken(token) == session.ID {
+		err = ResponseWithCookie(w, http.StatusOK, c.cp.ClearSessionCookie())
+		if err != nil {
+			ResponseAndLogError(w, err)
+		}
+		return
+	}
+
+	if err := Response(w, http.StatusOK); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
+
+// RevokeAllSessions deletes all sessions of the user who sent the request and clears the cookie.
+func (c *sessionController) RevokeAllSessions(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopeAdmin)
+	if !ok {
+		return
+	}
+
+	if err := c.sApp.RevokeAllSessions(r.Context(), me.ID); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if err := ResponseWithCookie(w, http.StatusOK, c.cp.ClearSessionCookie()); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
@@ -0,0 +1,143 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/application"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/infra/router"
+)
+
+// ThreadController is the interface of ThreadController.
+type ThreadController interface {
+	ListThreads(w http.ResponseWriter, r *http.Request)
+	GetThread(w http.ResponseWriter, r *http.Request)
+	CreateThread(w http.ResponseWriter, r *http.Request)
+	MarkThreadRead(w http.ResponseWriter, r *http.Request)
+}
+
+type threadController struct {
+	rm   router.RequestManager
+	tApp application.ThreadService
+}
+
+// NewThreadController generates and returns ThreadController.
+func NewThreadController(rm router.RequestManager, tApp application.ThreadService) ThreadController {
+	return &threadController{
+		rm:   rm,
+		tApp: tApp,
+	}
+}
+
+// ListThreads returns threads which the user who sent the request can read with their unread comments.
+func (c *threadController) ListThreads(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopeReadThreads)
+	if !ok {
+		return
+	}
+
+	summaries, err := c.tApp.ListThreads(r.Context(), me.ID)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	dtos := make([]*ThreadSummaryDTO, 0, len(summaries))
+	for _, summary := range summaries {
+		dtos = append(dtos, TranslateFromThreadSummaryToThreadSummaryDTO(summary))
+	}
+
+	if err := Response(w, http.StatusOK, dtos); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
+
+// GetThread returns the thread specified by id.
+func (c *threadController) GetThread(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopeReadThreads)
+	if !ok {
+		return
+	}
+
+	id, err := c.rm.GetUint32ValueOfURLParam(r, model.IDPropertyForDeveloper)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	thread, err := c.tApp.GetThread(r.Context(), me.ID, id)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if err := Response(w, http.StatusOK, TranslateFromThreadToThreadDTO(thread)); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
+
+// CreateThread creates the thread by the user who sent the request.
+func (c *threadController) CreateThread(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopePostComments)
+	if !ok {
+		return
+	}
+
+	b, err := GetValueFromPayLoad(r)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	dto := &ThreadRequestDTO{}
+	if err := unmarshalRequest(b, dto, "request body should be json of title and visibility"); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	thread, err := c.tApp.CreateThread(r.Context(), me.ID, dto.Title, model.ThreadVisibility(dto.Visibility))
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if err := Response(w, http.StatusOK, TranslateFromThreadToThreadDTO(thread)); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
+
+// MarkThreadRead records that the user who sent the request has read the thread specified by id up to the comment.
+func (c *threadController) MarkThreadRead(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopeReadThreads)
+	if !ok {
+		return
+	}
+
+	id, err := c.rm.GetUint32ValueOfURLParam(r, model.IDPropertyForDeveloper)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	b, err := GetValueFromPayLoad(r)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	dto := &ThreadReadRequestDTO{}
+	if err := unmarshalRequest(b, dto, "request body should be json of commentId"); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if err := c.tApp.MarkThreadRead(r.Context(), me.ID, id, dto.CommentID); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
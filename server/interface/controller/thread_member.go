@@ -0,0 +1,178 @@
+package controller
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/application"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/infra/router"
+)
+
+// ThreadMemberController is the interface of ThreadMemberController.
+type ThreadMemberController interface {
+	ListMembers(w http.ResponseWriter, r *http.Request)
+	JoinThread(w http.ResponseWriter, r *http.Request)
+	LeaveThread(w http.ResponseWriter, r *http.Request)
+	CreateInvite(w http.ResponseWriter, r *http.Request)
+	AcceptInvite(w http.ResponseWriter, r *http.Request)
+}
+
+type threadMemberController struct {
+	rm   router.RequestManager
+	mApp application.ThreadMemberService
+}
+
+// NewThreadMemberController generates and returns ThreadMemberController.
+func NewThreadMemberController(rm router.RequestManager, mApp application.ThreadMemberService) ThreadMemberController {
+	return &threadMemberController{
+		rm:   rm,
+		mApp: mApp,
+	}
+}
+
+// ListMembers returns members of the thread specified by id.
+func (c *threadMemberController) ListMembers(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopeReadThreads)
+	if !ok {
+		return
+	}
+
+	threadID, err := c.rm.GetUint32ValueOfURLParam(r, model.IDPropertyForDeveloper)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	members, err := c.mApp.ListMembers(r.Context(), me.ID, threadID)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	dtos := make([]*ThreadMemberDTO, 0, len(members))
+	for _, member := range members {
+		dtos = append(dtos, TranslateFromThreadMemberToThreadMemberDTO(member))
+	}
+
+	if err := Response(w, http.StatusOK, dtos); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
+
+// JoinThread makes the user who sent the request a member of the public thread specified by id.
+func (c *threadMemberController) JoinThread(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopePostComments)
+	if !ok {
+		return
+	}
+
+	threadID, err := c.rm.GetUint32ValueOfURLParam(r, model.IDPropertyForDeveloper)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	member, err := c.mApp.JoinThread(r.Context(), me.ID, threadID)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if err := Response(w, http.StatusOK, TranslateFromThreadMemberToThreadMemberDTO(member)); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
+
+// LeaveThread removes the user who sent the request from members of the thread specified by id.
+func (c *threadMemberController) LeaveThread(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopePostComments)
+	if !ok {
+		return
+	}
+
+	threadID, err := c.rm.GetUint32ValueOfURLParam(r, model.IDPropertyForDeveloper)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if err := c.mApp.LeaveThread(r.Context(), me.ID, threadID); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CreateInvite creates the invitation to the thread specified by id.
+func (c *threadMemberController) CreateInvite(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopeAdmin)
+	if !ok {
+		return
+	}
+
+	threadID, err := c.rm.GetUint32ValueOfURLParam(r, model.IDPropertyForDeveloper)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	b, err := GetValueFromPayLoad(r)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	dto := &ThreadInviteRequestDTO{}
+	if err := unmarshalRequest(b, dto, "request body should be json of expiresIn and maxUses"); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	ttl := time.Duration(dto.ExpiresIn) * time.Second
+	invite, err := c.mApp.CreateInvite(r.Context(), me, threadID, ttl, dto.MaxUses)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	w.Header().Set(CacheControl, "no-store")
+	if err := Response(w, http.StatusOK, TranslateFromThreadInviteToThreadInviteDTO(invite)); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
+
+// AcceptInvite makes the user who sent the request a member of the thread by the invitation.
+func (c *threadMemberController) AcceptInvite(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopePostComments)
+	if !ok {
+		return
+	}
+
+	b, err := GetValueFromPayLoad(r)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	dto := &ThreadInviteAcceptRequestDTO{}
+	if err := unmarshalRequest(b, dto, "request body should be json of token"); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	thread, err := c.mApp.AcceptInvite(r.Context(), me.ID, dto.Token)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if err := Response(w, http.StatusOK, TranslateFromThreadToThreadDTO(thread)); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
@@ -0,0 +1,128 @@
+package controller
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/application"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/infra/router"
+)
+
+// TokenController is the interface of TokenController.
+type TokenController interface {
+	IssueTokens(w http.ResponseWriter, r *http.Request)
+	RefreshTokens(w http.ResponseWriter, r *http.Request)
+	RevokeRefreshToken(w http.ResponseWriter, r *http.Request)
+	GetJWKS(w http.ResponseWriter, r *http.Request)
+}
+
+type tokenController struct {
+	rm   router.RequestManager
+	tApp application.TokenService
+}
+
+// NewTokenController generates and returns TokenController.
+func NewTokenController(rm router.RequestManager, tApp application.TokenService) TokenController {
+	return &tokenController{
+		rm:   rm,
+		tApp: tApp,
+	}
+}
+
+// IssueTokens checks name, password and the code of the second factor and issues tokens.
+func (c *tokenController) IssueTokens(w http.ResponseWriter, r *http.Request) {
+	b, err := GetValueFromPayLoad(r)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	dto := &TokenRequestDTO{}
+	if err := unmarshalRequest(b, dto, "request body should be json of user"); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	user, err := model.NewUser(dto.Name, dto.Password)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	pair, err := c.tApp.IssueTokens(r.Context(), user, dto.Code, GetClient(r))
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	c.responseTokens(w, pair)
+}
+
+// RefreshTokens issues new tokens by the refresh token.
+func (c *tokenController) RefreshTokens(w http.ResponseWriter, r *http.Request) {
+	dto, err := parseRefreshTokenRequest(r)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	pair, err := c.tApp.RefreshTokens(r.Context(), dto.RefreshToken)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	c.responseTokens(w, pair)
+}
+
+// RevokeRefreshToken revokes the refresh token.
+func (c *tokenController) RevokeRefreshToken(w http.ResponseWriter, r *http.Request) {
+	dto, err := parseRefreshTokenRequest(r)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if err := c.tApp.RevokeRefreshToken(r.Context(), dto.RefreshToken); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if err := Response(w, http.StatusOK); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
+
+// GetJWKS returns public keys which verify access tokens.
+func (c *tokenController) GetJWKS(w http.ResponseWriter, r *http.Request) {
+	dto := TranslateFromPublicKeysToJWKSetDTO(c.tApp.PublicKeys(r.Context()))
+	if err := Response(w, http.StatusOK, dto); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
+
+// responseTokens returns tokens which must not be cached.
+func (c *tokenController) responseTokens(w http.ResponseWriter, pair *model.TokenPair) {
+	w.Header().Set(CacheControl, "no-store")
+	if err := Response(w, http.StatusOK, TranslateFromTokenPairToTokenDTO(pair, time.Now())); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
+
+// parseRefreshTokenRequest parses the request which has refresh token.
+func parseRefreshTokenRequest(r *http.Request) (*RefreshTokenRequestDTO, error) {
+	b, err := GetValueFromPayLoad(r)
+	if err != nil {
+		return nil, err
+	}
+
+	dto := &RefreshTokenRequestDTO{}
+	if err := unmarshalRequest(b, dto, "request body should be json of refresh token"); err != nil {
+		return nil, err
+	}
+	return dto, nil
+}
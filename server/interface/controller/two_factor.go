@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/application"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/infra/router"
+)
+
+// TwoFactorController is the interface of TwoFactorController.
+type TwoFactorController interface {
+	StartTOTPEnrollment(w http.ResponseWriter, r *http.Request)
+	ConfirmTOTPEnrollment(w http.ResponseWriter, r *http.Request)
+	DisableTOTP(w http.ResponseWriter, r *http.Request)
+}
+
+type twoFactorController struct {
+	rm    router.RequestManager
+	tfApp application.TwoFactorService
+}
+
+// NewTwoFactorController generates and returns TwoFactorController.
+func NewTwoFactorController(rm router.RequestManager, tfApp application.TwoFactorService) TwoFactorController {
+	return &twoFactorController{
+		rm:    rm,
+		tfApp: tfApp,
+	}
+}
+
+// StartTOTPEnrollment generates a new secret of TOTP of the user who sent the request.
+// The secret is returned only in this response.
+func (c *twoFactorController) StartTOTPEnrollment(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopeAdmin)
+	if !ok {
+		return
+	}
+
+	totp, uri, err := c.tfApp.StartTOTPEnrollment(r.Context(), me.ID)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	w.Header().Set(CacheControl, "no-store")
+	if err := Response(w, http.StatusOK, TranslateFromTOTPToTOTPEnrollmentDTO(totp, uri)); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
+
+// ConfirmTOTPEnrollment enables TOTP of the user who sent the request by the code.
+// Recovery codes are returned only in this response.
+func (c *twoFactorController) ConfirmTOTPEnrollment(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopeAdmin)
+	if !ok {
+		return
+	}
+
+	dto, err := parseTOTPCodeRequest(r)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	codes, err := c.tfApp.ConfirmTOTPEnrollment(r.Context(), me.ID, dto.Code)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	w.Header().Set(CacheControl, "no-store")
+	if err := Response(w, http.StatusOK, &RecoveryCodesDTO{RecoveryCodes: codes}); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
+
+// DisableTOTP disables TOTP of the user who sent the request by the code or a recovery code.
+func (c *twoFactorController) DisableTOTP(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopeAdmin)
+	if !ok {
+		return
+	}
+
+	dto, err := parseTOTPCodeRequest(r)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if err := c.tfApp.DisableTOTP(r.Context(), me.ID, dto.Code); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if err := Response(w, http.StatusOK); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
+
+// parseTOTPCodeRequest parses the request which has the code.
+func parseTOTPCodeRequest(r *http.Request) (*TOTPCodeRequestDTO, error) {
+	b, err := GetValueFromPayLoad(r)
+	if err != nil {
+		return nil, err
+	}
+
+	dto := &TOTPCodeRequestDTO{}
+	if err := unmarshalRequest(b, dto, "request body should be json of code"); err != nil {
+		return nil, err
+	}
+	return dto, nil
+}
@@ -0,0 +1,156 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/application"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/infra/router"
+	"github.com/pkg/errors"
+)
+
+// UserController is the interface of UserController.
+type UserController interface {
+	GetMe(w http.ResponseWriter, r *http.Request)
+	ChangeName(w http.ResponseWriter, r *http.Request)
+	ChangePassword(w http.ResponseWriter, r *http.Request)
+	DeleteAccount(w http.ResponseWriter, r *http.Request)
+}
+
+type userController struct {
+	rm   router.RequestManager
+	uApp application.UserService
+	cp   CookiePolicy
+}
+
+// NewUserController generates and returns UserController.
+func NewUserController(rm router.RequestManager, uApp application.UserService, cp CookiePolicy) UserController {
+	return &userController{
+		rm:   rm,
+		uApp: uApp,
+		cp:   cp,
+	}
+}
+
+// GetMe returns the user who sent the request.
+func (c *userController) GetMe(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	user, err := c.uApp.GetMe(r.Context(), me.ID)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if err := Response(w, http.StatusOK, TranslateFromUserToUserDTO(user)); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
+
+// ChangeName changes the name of the user who sent the request.
+func (c *userController) ChangeName(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopeAdmin)
+	if !ok {
+		return
+	}
+
+	b, err := GetValueFromPayLoad(r)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	dto := &UserNameRequestDTO{}
+	if err := unmarshalRequest(b, dto, "request body should be json of name"); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	user, err := c.uApp.ChangeName(r.Context(), me.ID, dto.Name)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if err := Response(w, http.StatusOK, TranslateFromUserToUserDTO(user)); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
+
+// ChangePassword changes the password of the user who sent the request.
+// Sessions other than the one of the request are revoked,
+// and all sessions are revoked if the request is authenticated by a bearer token.
+func (c *userController) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopeAdmin)
+	if !ok {
+		return
+	}
+
+	// the bearer token is used before the cookie by AuthenticationMiddleware, and then the request has no session.
+	var token string
+	if _, ok := GetBearerToken(r); !ok {
+		if t, err := c.cp.SessionToken(r); err == nil {
+			token = t
+		}
+	}
+
+	b, err := GetValueFromPayLoad(r)
+	if err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	dto := &PasswordChangeRequestDTO{}
+	if err := unmarshalRequest(b, dto, "request body should be json of passwords"); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if err := c.uApp.ChangePassword(r.Context(), me.ID, token, dto.OldPassword, dto.NewPassword); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if err := Response(w, http.StatusOK); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
+
+// DeleteAccount deletes the user who sent the request and clears the cookie.
+func (c *userController) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	me, ok := requireScope(w, r, model.ScopeAdmin)
+	if !ok {
+		return
+	}
+
+	if err := c.uApp.DeleteAccount(r.Context(), me.ID); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+
+	if err := ResponseWithCookie(w, http.StatusOK, c.cp.ClearSessionCookie()); err != nil {
+		ResponseAndLogError(w, err)
+		return
+	}
+}
+
+// unmarshalRequest parses request body to dto.
+// Request body is not kept in error because it may have password.
+func unmarshalRequest(b []byte, dto interface{}, reason string) error {
+	if err := json.Unmarshal(b, dto); err != nil {
+		err = &model.InvalidDataError{
+			BaseErr:                   err,
+			DataNameForDeveloper:      "request body",
+			InvalidReasonForDeveloper: reason,
+		}
+		return errors.WithStack(err)
+	}
+	return nil
+}
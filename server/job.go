@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/hideUW/nuxt-go-chat-app/server/application"
+)
+
+// buildSearchIndex adds all comments to the index which is not persisted, before the server starts.
+func buildSearchIndex(seApp application.SearchService) {
+	n, err := seApp.Reindex(context.Background())
+	if err != nil {
+		panic(err.Error())
+	}
+	log.Infof("indexed %d comments for search", n)
+}
+
+// commentPurgeInterval is the interval of purging the content of deleted comments.
+const commentPurgeInterval = time.Hour
+
+// purgeDeletedComments purges the content and attachments of comments deleted longer ago than retention
+// at start and every commentPurgeInterval.
+// This runs until the process exits, and errors are only logged because the next run retries.
+func purgeDeletedComments(cApp application.CommentService, atApp application.AttachmentService, retention time.Duration) {
+	ticker := time.NewTicker(commentPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		n, err := cApp.PurgeDeletedComments(context.Background(), retention)
+		if err != nil {
+			log.Errorf("failed to purge deleted comments: %+v", err)
+		} else if n > 0 {
+			log.Infof("purged %d deleted comments", n)
+		}
+
+		an, err := atApp.PurgeDeletedAttachments(context.Background(), retention)
+		if err != nil {
+			log.Errorf("failed to purge attachments of deleted comments: %+v", err)
+		} else if an > 0 {
+			log.Infof("purged %d attachments of deleted comments", an)
+		}
+		<-ticker.C
+	}
+}
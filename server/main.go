@@ -1,12 +1,66 @@
 package main
 
 import (
+	"context"
+	"expvar"
 	"net/http"
+	"time"
 
+	"github.com/hideUW/nuxt-go-chat-app/server/application"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/model"
+	"github.com/hideUW/nuxt-go-chat-app/server/domain/service"
+	"github.com/hideUW/nuxt-go-chat-app/server/infra/db"
+	"github.com/hideUW/nuxt-go-chat-app/server/infra/memory"
+	"github.com/hideUW/nuxt-go-chat-app/server/infra/oidc"
+	"github.com/hideUW/nuxt-go-chat-app/server/infra/ratelimit"
 	"github.com/hideUW/nuxt-go-chat-app/server/infra/router"
+	"github.com/hideUW/nuxt-go-chat-app/server/interface/controller"
+	log "github.com/sirupsen/logrus"
 )
 
+// rateLimitRules is the rules of rate limit of API.
+var rateLimitRules = []controller.RateLimitRule{
+	{Method: http.MethodPost, PathPattern: "/api/signup", Rate: ratelimit.Rate{Limit: 5, Period: time.Hour}},
+	{Method: http.MethodPost, PathPattern: "/api/login", Rate: ratelimit.Rate{Limit: 20, Period: time.Minute}},
+	{Method: http.MethodPost, PathPattern: "/api/login/two_factor", Rate: ratelimit.Rate{Limit: 20, Period: time.Minute}},
+	{Method: http.MethodPost, PathPattern: "/api/token", Rate: ratelimit.Rate{Limit: 20, Period: time.Minute}},
+	{Method: http.MethodPost, PathPattern: "/api/token/refresh", Rate: ratelimit.Rate{Limit: 60, Period: time.Minute}},
+	{Method: http.MethodPut, PathPattern: "/api/users/me/password", Rate: ratelimit.Rate{Limit: 10, Period: time.Minute}},
+	{Method: http.MethodPost, PathPattern: "/api/users/me/totp/confirm", Rate: ratelimit.Rate{Limit: 10, Period: time.Minute}},
+	{Method: http.MethodPost, PathPattern: "/api/users/me/totp/disable", Rate: ratelimit.Rate{Limit: 10, Period: time.Minute}},
+	{Method: http.MethodPut, PathPattern: "/api/users/me/email", Rate: ratelimit.Rate{Limit: 5, Period: time.Hour}},
+	{Method: http.MethodPost, PathPattern: "/api/email/verify", Rate: ratelimit.Rate{Limit: 20, Period: time.Minute}},
+	{Method: http.MethodPost, PathPattern: "/api/password/reset_request", Rate: ratelimit.Rate{Limit: 5, Period: time.Hour}},
+	{Method: http.MethodPost, PathPattern: "/api/password/reset", Rate: ratelimit.Rate{Limit: 20, Period: time.Minute}},
+	{Method: http.MethodPost, PathPattern: "/api/threads", Rate: ratelimit.Rate{Limit: 10, Period: time.Minute}},
+	{Method: http.MethodPost, PathPattern: "/api/threads/{id}/comments", Rate: ratelimit.Rate{Limit: 30, Period: time.Minute}},
+	{Method: http.MethodPut, PathPattern: "/api/comments/{id}", Rate: ratelimit.Rate{Limit: 30, Period: time.Minute}},
+	{Method: http.MethodPut, PathPattern: "/api/comments/{id}/reactions/{emoji}", Rate: ratelimit.Rate{Limit: 60, Period: time.Minute}},
+	{Method: http.MethodPost, PathPattern: "/api/comments/{id}/attachments", Rate: ratelimit.Rate{Limit: 20, Period: time.Minute}},
+	{Method: http.MethodPost, PathPattern: "/api/threads/{id}/invites", Rate: ratelimit.Rate{Limit: 10, Period: time.Minute}},
+	{Method: http.MethodPost, PathPattern: "/api/thread_invites/accept", Rate: ratelimit.Rate{Limit: 20, Period: time.Minute}},
+	{Method: http.MethodPost, PathPattern: "/api/conversations", Rate: ratelimit.Rate{Limit: 10, Period: time.Minute}},
+	{Method: http.MethodPost, PathPattern: "/api/conversations/{id}/messages", Rate: ratelimit.Rate{Limit: 30, Period: time.Minute}},
+	{Method: http.MethodPut, PathPattern: "/api/presence", Rate: ratelimit.Rate{Limit: 30, Period: time.Minute}},
+	{Method: http.MethodPut, PathPattern: "/api/threads/{id}/typing", Rate: ratelimit.Rate{Limit: 120, Period: time.Minute}},
+	{Method: http.MethodGet, PathPattern: "/api/search", Rate: ratelimit.Rate{Limit: 30, Period: time.Minute}},
+	{Method: http.MethodPost, PathPattern: "/api/api_keys", Rate: ratelimit.Rate{Limit: 10, Period: time.Minute}},
+	{Method: http.MethodGet, PathPattern: "/api/oidc/login", Rate: ratelimit.Rate{Limit: 20, Period: time.Minute}},
+	{Method: http.MethodGet, PathPattern: "/api/oidc/callback", Rate: ratelimit.Rate{Limit: 20, Period: time.Minute}},
+}
+
+// rateLimitCapacity is the max number of clients kept per rule of rate limit.
+const rateLimitCapacity = 10000
+
+// totpIssuer is the name of the service shown in authenticator apps.
+const totpIssuer = "nuxt-go-chat-app"
+
 func main() {
+	setUpAPI()
+
+	// For metrics, which are not served by the public router because expvar shows the command line and memory stats.
+	go serveMetrics(metricsAddr())
+
 	// For static file
 	entrypoint := "../client/nuxt-go-chat-app/dist/index.html"
 	router.Router.Path("/").HandlerFunc(ServeStaticFile(entrypoint))
@@ -18,6 +72,235 @@ func main() {
 	}
 }
 
+// setUpAPI injects dependencies and sets routes of API.
+func setUpAPI() {
+	ctx := context.Background()
+	m := db.NewDBManager()
+
+	uRepo := db.NewUserRepository(ctx)
+	sRepo := db.NewSessionRepository(ctx)
+	rtRepo := db.NewRefreshTokenRepository(ctx)
+	akRepo := db.NewAPIKeyRepository(ctx)
+	iRepo := db.NewIdentityRepository(ctx)
+	totpRepo := db.NewTOTPRepository(ctx)
+	utRepo := db.NewUserTokenRepository(ctx)
+	thRepo := db.NewThreadRepository(ctx)
+	cRepo := db.NewCommentRepository(ctx)
+	rRepo := db.NewRoleRepository(ctx)
+	tmRepo := db.NewThreadModeratorRepository(ctx)
+	mRepo := db.NewThreadMemberRepository(ctx)
+	tiRepo := db.NewThreadInviteRepository(ctx)
+	dcRepo := db.NewDirectConversationRepository(ctx)
+	ubRepo := db.NewUserBlockRepository(ctx)
+	trRepo := db.NewThreadReadRepository(ctx)
+	crRepo := db.NewCommentReactionRepository(ctx)
+	cvRepo := db.NewCommentRevisionRepository(ctx)
+	cmRepo := db.NewCommentMentionRepository(ctx)
+	nRepo := db.NewNotificationRepository(ctx)
+	atRepo := db.NewAttachmentRepository(ctx)
+	seRepo, inMemorySearch, err := newSearchRepository(ctx)
+	if err != nil {
+		panic(err.Error())
+	}
+	tRepo := memory.NewThrottleRepository()
+	plRepo := memory.NewPendingLoginRepository()
+	prRepo := memory.NewPresenceRepository()
+
+	uService := service.NewUserService(m, uRepo)
+	sService := service.NewSessionService(m, sRepo)
+	tService := service.NewThrottleService(tRepo, service.DefaultThrottlePolicies)
+	totpService := service.NewTOTPService(totpIssuer)
+	pService := service.NewPolicyService(m, rRepo, tmRepo, mRepo)
+	prService := service.NewPresenceService(prRepo, service.DefaultPresencePolicy, time.Now)
+
+	atKeys, err := accessTokenKeys()
+	if err != nil {
+		panic(err.Error())
+	}
+	atService, err := service.NewAccessTokenService(atKeys)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	mailer, err := newMailer()
+	if err != nil {
+		panic(err.Error())
+	}
+
+	blobStore, err := newBlobStore()
+	if err != nil {
+		panic(err.Error())
+	}
+
+	aApp := application.NewAuthenticationService(m, *application.NewAuthenticationServiceDIInput(uRepo, sRepo, totpRepo, plRepo, uService, sService, tService, totpService), db.CloseTransaction)
+	uApp := application.NewUserService(m, *application.NewUserServiceDIInput(uRepo, sRepo, rtRepo, akRepo, iRepo, totpRepo, utRepo, rRepo, thRepo, tmRepo, mRepo, tiRepo, ubRepo, trRepo, crRepo, cmRepo, nRepo, uService, tService), db.CloseTransaction)
+	tApp := application.NewTokenService(m, *application.NewTokenServiceDIInput(aApp, uRepo, rtRepo, atService), db.CloseTransaction)
+	sApp := application.NewSessionService(m, sRepo)
+	akApp := application.NewAPIKeyService(m, uRepo, akRepo)
+	tfApp := application.NewTwoFactorService(m, *application.NewTwoFactorServiceDIInput(uRepo, totpRepo, totpService, tService), db.CloseTransaction)
+	thApp := application.NewThreadService(m, *application.NewThreadServiceDIInput(thRepo, tmRepo, mRepo, trRepo, cRepo, pService), db.CloseTransaction)
+	tmApp := application.NewThreadMemberService(m, *application.NewThreadMemberServiceDIInput(thRepo, mRepo, tiRepo, tmRepo, pService), db.CloseTransaction)
+	cApp := application.NewCommentService(m, *application.NewCommentServiceDIInput(thRepo, cRepo, crRepo, cvRepo, uRepo, cmRepo, nRepo, seRepo, atRepo, pService), db.CloseTransaction)
+	atApp := application.NewAttachmentService(m, *application.NewAttachmentServiceDIInput(thRepo, cRepo, atRepo, pService, blobStore), db.CloseTransaction)
+	prApp := application.NewPresenceService(m, *application.NewPresenceServiceDIInput(thRepo, mRepo, pService, prService))
+	nApp := application.NewNotificationService(m, nRepo)
+	seApp := application.NewSearchService(m, *application.NewSearchServiceDIInput(uRepo, thRepo, cRepo, seRepo, pService))
+	dmApp := application.NewDirectMessageService(m, *application.NewDirectMessageServiceDIInput(uRepo, thRepo, mRepo, cRepo, crRepo, dcRepo, ubRepo, seRepo, atRepo), db.CloseTransaction)
+	rApp := application.NewRoleService(m, *application.NewRoleServiceDIInput(uRepo, thRepo, rRepo, tmRepo, pService))
+	eApp := application.NewEmailService(m, *application.NewEmailServiceDIInput(uRepo, sRepo, rtRepo, utRepo, tService, mailer, appBaseURL()), db.CloseTransaction)
+
+	cConfig, err := cookieConfig()
+	if err != nil {
+		panic(err.Error())
+	}
+	cp, err := controller.NewCookiePolicy(cConfig)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	retention, err := commentRetention()
+	if err != nil {
+		panic(err.Error())
+	}
+	go purgeDeletedComments(cApp, atApp, retention)
+
+	if inMemorySearch {
+		buildSearchIndex(seApp)
+	}
+
+	rm := router.NewRequestManager()
+	aController := controller.NewAuthenticationController(rm, aApp, cp)
+	uController := controller.NewUserController(rm, uApp, cp)
+	sController := controller.NewSessionController(rm, sApp, cp)
+	tController := controller.NewTokenController(rm, tApp)
+	akController := controller.NewAPIKeyController(rm, akApp)
+	tfController := controller.NewTwoFactorController(rm, tfApp)
+	eController := controller.NewEmailController(rm, eApp)
+	thController := controller.NewThreadController(rm, thApp)
+	cController := controller.NewCommentController(rm, cApp)
+	atController := controller.NewAttachmentController(rm, atApp)
+	tmController := controller.NewThreadMemberController(rm, tmApp)
+	dmController := controller.NewDirectMessageController(rm, dmApp)
+	nController := controller.NewNotificationController(rm, nApp)
+	seController := controller.NewSearchController(rm, seApp)
+	prController := controller.NewPresenceController(rm, prApp)
+	rController := controller.NewRoleController(rm, rApp)
+
+	aMiddleware := controller.NewAuthenticationMiddleware(aApp, tApp, akApp, cp)
+	rlMiddleware := controller.NewRateLimitMiddleware(rateLimitRules, rateLimitCapacity)
+	csrfMiddleware := controller.NewCSRFMiddleware(allowedOrigins())
+	csrfController := controller.NewCSRFController()
+	roleMiddleware := controller.NewRoleMiddleware(rApp)
+
+	// For clients which can not keep cookie, tokens are issued without CSRF token.
+	// This is registered before /api so that it is not shadowed.
+	tokenAPI := router.Router.PathPrefix("/api/token").Subrouter()
+	tokenAPI.Use(rlMiddleware.Handler)
+	tokenAPI.HandleFunc("", tController.IssueTokens).Methods(http.MethodPost)
+	tokenAPI.HandleFunc("/refresh", tController.RefreshTokens).Methods(http.MethodPost)
+	tokenAPI.HandleFunc("/revoke", tController.RevokeRefreshToken).Methods(http.MethodPost)
+	router.Router.HandleFunc("/.well-known/jwks.json", tController.GetJWKS).Methods(http.MethodGet)
+
+	api := router.Router.PathPrefix("/api").Subrouter()
+	api.Use(csrfMiddleware.Handler, aMiddleware.Handler, rlMiddleware.Handler)
+	api.HandleFunc("/csrf_token", csrfController.GetToken).Methods(http.MethodGet)
+	api.HandleFunc("/signup", aController.SignUp).Methods(http.MethodPost)
+	api.HandleFunc("/login", aController.Login).Methods(http.MethodPost)
+	api.HandleFunc("/login/two_factor", aController.LoginWithSecondFactor).Methods(http.MethodPost)
+	api.HandleFunc("/logout", aController.Logout).Methods(http.MethodPost)
+	api.HandleFunc("/users/me", uController.GetMe).Methods(http.MethodGet)
+	api.HandleFunc("/users/me", uController.DeleteAccount).Methods(http.MethodDelete)
+	api.HandleFunc("/users/me/name", uController.ChangeName).Methods(http.MethodPut)
+	api.HandleFunc("/users/me/password", uController.ChangePassword).Methods(http.MethodPut)
+	api.HandleFunc("/users/me/totp", tfController.StartTOTPEnrollment).Methods(http.MethodPost)
+	api.HandleFunc("/users/me/totp/confirm", tfController.ConfirmTOTPEnrollment).Methods(http.MethodPost)
+	api.HandleFunc("/users/me/totp/disable", tfController.DisableTOTP).Methods(http.MethodPost)
+	api.HandleFunc("/users/me/email", eController.ChangeEmail).Methods(http.MethodPut)
+	api.HandleFunc("/email/verify", eController.VerifyEmail).Methods(http.MethodPost)
+	api.HandleFunc("/password/reset_request", eController.RequestPasswordReset).Methods(http.MethodPost)
+	api.HandleFunc("/password/reset", eController.ResetPassword).Methods(http.MethodPost)
+	api.HandleFunc("/users/me/roles", rController.GetMyRoles).Methods(http.MethodGet)
+	api.HandleFunc("/threads", thController.ListThreads).Methods(http.MethodGet)
+	api.HandleFunc("/threads", thController.CreateThread).Methods(http.MethodPost)
+	api.HandleFunc("/threads/{id}", thController.GetThread).Methods(http.MethodGet)
+	api.HandleFunc("/threads/{id}/read", thController.MarkThreadRead).Methods(http.MethodPut)
+	api.HandleFunc("/threads/{id}/comments", cController.ListComments).Methods(http.MethodGet)
+	api.HandleFunc("/threads/{id}/comments", cController.PostComment).Methods(http.MethodPost)
+	api.HandleFunc("/threads/{id}/moderators", rController.ListThreadModerators).Methods(http.MethodGet)
+	api.HandleFunc("/threads/{id}/moderators/{userID}", rController.AddThreadModerator).Methods(http.MethodPut)
+	api.HandleFunc("/threads/{id}/moderators/{userID}", rController.RemoveThreadModerator).Methods(http.MethodDelete)
+	api.HandleFunc("/threads/{id}/presence", prController.GetThreadPresence).Methods(http.MethodGet)
+	api.HandleFunc("/threads/{id}/typing", prController.StartTyping).Methods(http.MethodPut)
+	api.HandleFunc("/threads/{id}/typing", prController.StopTyping).Methods(http.MethodDelete)
+	api.HandleFunc("/threads/{id}/members", tmController.ListMembers).Methods(http.MethodGet)
+	api.HandleFunc("/threads/{id}/members/me", tmController.JoinThread).Methods(http.MethodPut)
+	api.HandleFunc("/threads/{id}/members/me", tmController.LeaveThread).Methods(http.MethodDelete)
+	api.HandleFunc("/threads/{id}/invites", tmController.CreateInvite).Methods(http.MethodPost)
+	api.HandleFunc("/thread_invites/accept", tmController.AcceptInvite).Methods(http.MethodPost)
+	api.HandleFunc("/comments/{id}", cController.EditComment).Methods(http.MethodPut)
+	api.HandleFunc("/comments/{id}", cController.DeleteComment).Methods(http.MethodDelete)
+	api.HandleFunc("/comments/{id}/revisions", cController.ListRevisions).Methods(http.MethodGet)
+	api.HandleFunc("/comments/{id}/replies", cController.ListReplies).Methods(http.MethodGet)
+	api.HandleFunc("/comments/{id}/reactions/{emoji}", cController.AddReaction).Methods(http.MethodPut)
+	api.HandleFunc("/comments/{id}/reactions/{emoji}", cController.RemoveReaction).Methods(http.MethodDelete)
+	api.HandleFunc("/comments/{id}/attachments", atController.UploadAttachment).Methods(http.MethodPost)
+	api.HandleFunc("/attachments/{id}", atController.DownloadAttachment).Methods(http.MethodGet)
+	api.HandleFunc("/conversations", dmController.ListConversations).Methods(http.MethodGet)
+	api.HandleFunc("/conversations", dmController.GetOrCreateConversation).Methods(http.MethodPost)
+	api.HandleFunc("/conversations/{id}/messages", dmController.ListMessages).Methods(http.MethodGet)
+	api.HandleFunc("/conversations/{id}/messages", dmController.PostMessage).Methods(http.MethodPost)
+	api.HandleFunc("/users/me/blocks", dmController.ListBlocks).Methods(http.MethodGet)
+	api.HandleFunc("/users/me/blocks/{userID}", dmController.BlockUser).Methods(http.MethodPut)
+	api.HandleFunc("/users/me/blocks/{userID}", dmController.UnblockUser).Methods(http.MethodDelete)
+	api.HandleFunc("/search", seController.Search).Methods(http.MethodGet)
+	api.HandleFunc("/notifications", nController.ListNotifications).Methods(http.MethodGet)
+	api.HandleFunc("/notifications/read", nController.MarkAllRead).Methods(http.MethodPut)
+	api.HandleFunc("/notifications/{id}/read", nController.MarkRead).Methods(http.MethodPut)
+	api.HandleFunc("/presence", prController.Heartbeat).Methods(http.MethodPut)
+	api.HandleFunc("/presence", prController.GoOffline).Methods(http.MethodDelete)
+	api.HandleFunc("/sessions", sController.ListSessions).Methods(http.MethodGet)
+	api.HandleFunc("/sessions", sController.RevokeAllSessions).Methods(http.MethodDelete)
+	api.HandleFunc("/sessions/{id}", sController.RevokeSession).Methods(http.MethodDelete)
+	api.HandleFunc("/api_keys", akController.ListAPIKeys).Methods(http.MethodGet)
+	api.HandleFunc("/api_keys", akController.CreateAPIKey).Methods(http.MethodPost)
+	api.HandleFunc("/api_keys/{id}", akController.RevokeAPIKey).Methods(http.MethodDelete)
+
+	// Administration is only for admins, which is checked before each handler checks the policy.
+	admin := api.PathPrefix("/admin").Subrouter()
+	admin.Use(roleMiddleware.Require(model.PermissionManageRoles))
+	admin.HandleFunc("/users/{userID}/roles", rController.GetRoles).Methods(http.MethodGet)
+	admin.HandleFunc("/users/{userID}/roles/{role}", rController.GrantRole).Methods(http.MethodPut)
+	admin.HandleFunc("/users/{userID}/roles/{role}", rController.RevokeRole).Methods(http.MethodDelete)
+
+	if config, ok := oidcConfig(); ok {
+		provider, err := oidc.NewProvider(ctx, config)
+		if err != nil {
+			panic(err.Error())
+		}
+		lcp, err := controller.NewCookiePolicy(oidcLoginCookieConfig(cConfig))
+		if err != nil {
+			panic(err.Error())
+		}
+
+		oApp := application.NewOIDCService(m, *application.NewOIDCServiceDIInput(uRepo, sRepo, iRepo, memory.NewOIDCLoginRepository(), totpRepo, plRepo, uService, sService, provider), db.CloseTransaction)
+		oController := controller.NewOIDCController(rm, oApp, cp, lcp, "/")
+
+		api.HandleFunc("/oidc/login", oController.Login).Methods(http.MethodGet)
+		api.HandleFunc("/oidc/callback", oController.Callback).Methods(http.MethodGet)
+	}
+}
+
+// serveMetrics serves expvar on the internal address.
+// Errors are only logged, so that the API is served without metrics.
+func serveMetrics(addr string) {
+	mux := http.NewServeMux()
+	mux.Handle("/debug/vars", expvar.Handler())
+
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		log.Errorf("failed to serve metrics on %s: %+v", addr, err)
+	}
+}
+
 // ServeStaticFile delivers static files
 func ServeStaticFile(entrypoint string) func(w http.ResponseWriter, r *http.Request) {
 	fn := func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,17 @@
+package util
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+
+	"github.com/pkg/errors"
+)
+
+// RandomToken generates URL safe random string which has given bytes of entropy.
+func RandomToken(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "failed to read random bytes")
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}